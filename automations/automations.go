@@ -0,0 +1,110 @@
+// Package automations registers remediations compiled into the same binary as the built-in
+// automations, so the configuration can reference them by name as the action of an automation.
+package automations
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// ReadFinding returns the project remediated and the values the automation runs with for the
+// finding, a normalized Security Command Center notification. The values are sent to the
+// automation as JSON. Return findings.ErrUnsupportedFinding for findings it doesn't remediate.
+type ReadFinding func(b []byte) (projectID string, values interface{}, err error)
+
+// Execute runs the automation with the values read from the finding.
+type Execute func(ctx context.Context, values *Values, services *services.Global) error
+
+// Values are the values the router sends a registered automation.
+type Values struct {
+	// Automation is the name the automation is registered with.
+	Automation string
+	// ProjectID is the project remediated, which the target and exclude lists are checked against.
+	ProjectID string
+	// DryRun is set if the automation should only log what it would change.
+	DryRun bool
+	// Data holds the values ReadFinding returned as JSON.
+	Data json.RawMessage
+}
+
+// Automation is a registered automation.
+type Automation struct {
+	Name        string
+	ReadFinding ReadFinding
+	Execute     Execute
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]*Automation{}
+)
+
+// Register makes the automation available under the name, typically from the init function of the
+// package implementing it. Actions of built-in automations take precedence over registered ones.
+// Register panics if the name is registered twice or if a function is nil.
+func Register(name string, read ReadFinding, execute Execute) {
+	if name == "" || read == nil || execute == nil {
+		panic("automations: Register requires a name, ReadFinding and Execute")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("automations: Register called twice for %q", name))
+	}
+	registry[name] = &Automation{Name: name, ReadFinding: read, Execute: execute}
+}
+
+// Lookup returns the automation registered with the name.
+func Lookup(name string) (*Automation, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	a, ok := registry[name]
+	return a, ok
+}
+
+// Names returns the sorted names of the registered automations.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run runs the automation the values are for.
+func Run(ctx context.Context, values *Values, services *services.Global) error {
+	a, ok := Lookup(values.Automation)
+	if !ok {
+		return fmt.Errorf("automation %q is not registered", values.Automation)
+	}
+	return a.Execute(ctx, values, services)
+}
+
+// unregister removes the automation, for tests.
+func unregister(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(registry, name)
+}
@@ -0,0 +1,75 @@
+package automations
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+func TestRegister(t *testing.T) {
+	var ran *Values
+	read := func(b []byte) (string, interface{}, error) {
+		return "test-project", map[string]string{"ticket": "SEC-1"}, nil
+	}
+	execute := func(ctx context.Context, values *Values, services *services.Global) error {
+		ran = values
+		return nil
+	}
+	Register("open_ticket", read, execute)
+	defer unregister("open_ticket")
+
+	a, ok := Lookup("open_ticket")
+	if !ok {
+		t.Fatal("open_ticket not registered")
+	}
+	if project, _, err := a.ReadFinding(nil); err != nil || project != "test-project" {
+		t.Errorf("got project %q and error %v", project, err)
+	}
+	if diff := cmp.Diff(Names(), []string{"open_ticket"}); diff != "" {
+		t.Errorf("names differ:%+v", diff)
+	}
+	values := &Values{Automation: "open_ticket", ProjectID: "test-project", Data: json.RawMessage(`{"ticket":"SEC-1"}`)}
+	if err := Run(context.Background(), values, nil); err != nil {
+		t.Fatalf("failed to run: %q", err)
+	}
+	if ran != values {
+		t.Errorf("got values %+v", ran)
+	}
+	if err := Run(context.Background(), &Values{Automation: "unknown"}, nil); err == nil {
+		t.Error("ran an unregistered automation")
+	}
+	for _, tt := range []struct {
+		name     string
+		register func()
+	}{
+		{name: "twice", register: func() { Register("open_ticket", read, execute) }},
+		{name: "without name", register: func() { Register("", read, execute) }},
+		{name: "without execute", register: func() { Register("close_ticket", read, nil) }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s failed, registered", tt.name)
+				}
+			}()
+			tt.register()
+		})
+	}
+}
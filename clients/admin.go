@@ -0,0 +1,74 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"fmt"
+
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/option"
+)
+
+// Admin client for the Google Workspace Admin SDK Directory API.
+type Admin struct {
+	service *admin.Service
+}
+
+// NewAdmin returns and initializes an Admin SDK client.
+//
+// The automation service account must be assigned a Google Workspace admin role that allows
+// managing users.
+func NewAdmin(ctx context.Context) (*Admin, error) {
+	a, err := admin.NewService(ctx, option.WithScopes(admin.AdminDirectoryUserScope, admin.AdminDirectoryUserSecurityScope))
+	if err != nil {
+		return nil, fmt.Errorf("failed to init admin: %q", err)
+	}
+	return &Admin{service: a}, nil
+}
+
+// GetUser returns the user.
+func (a *Admin) GetUser(ctx context.Context, userKey string) (*admin.User, error) {
+	return a.service.Users.Get(userKey).Context(ctx).Do()
+}
+
+// UpdateUser updates the user.
+func (a *Admin) UpdateUser(ctx context.Context, userKey string, user *admin.User) (*admin.User, error) {
+	return a.service.Users.Update(userKey, user).Context(ctx).Do()
+}
+
+// SignOutUser signs the user out of all web and device sessions and resets their sign-in cookies.
+func (a *Admin) SignOutUser(ctx context.Context, userKey string) error {
+	return a.service.Users.SignOut(userKey).Context(ctx).Do()
+}
+
+// ListTokens returns the OAuth 2.0 tokens issued by the user to third party applications.
+func (a *Admin) ListTokens(ctx context.Context, userKey string) (*admin.Tokens, error) {
+	return a.service.Tokens.List(userKey).Context(ctx).Do()
+}
+
+// DeleteToken revokes the OAuth 2.0 token issued by the user to the application.
+func (a *Admin) DeleteToken(ctx context.Context, userKey, clientID string) error {
+	return a.service.Tokens.Delete(userKey, clientID).Context(ctx).Do()
+}
+
+// ListASPs returns the application-specific passwords of the user.
+func (a *Admin) ListASPs(ctx context.Context, userKey string) (*admin.Asps, error) {
+	return a.service.Asps.List(userKey).Context(ctx).Do()
+}
+
+// DeleteASP deletes the application-specific password of the user.
+func (a *Admin) DeleteASP(ctx context.Context, userKey string, codeID int64) error {
+	return a.service.Asps.Delete(userKey, codeID).Context(ctx).Do()
+}
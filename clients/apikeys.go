@@ -0,0 +1,113 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+)
+
+// apiKeysEndpoint is the base URL of the API Keys API.
+const apiKeysEndpoint = "https://apikeys.googleapis.com/v2/"
+
+// apiKeyRestrictions describes the restrictions of an API key.
+type apiKeyRestrictions struct {
+	BrowserKeyRestrictions *browserKeyRestrictions `json:"browserKeyRestrictions,omitempty"`
+	ServerKeyRestrictions  *serverKeyRestrictions  `json:"serverKeyRestrictions,omitempty"`
+	APITargets             []*apiTarget            `json:"apiTargets,omitempty"`
+}
+
+type browserKeyRestrictions struct {
+	AllowedReferrers []string `json:"allowedReferrers"`
+}
+
+type serverKeyRestrictions struct {
+	AllowedIPs []string `json:"allowedIps"`
+}
+
+type apiTarget struct {
+	Service string `json:"service"`
+}
+
+// APIKeys client.
+type APIKeys struct {
+	client *http.Client
+}
+
+// NewAPIKeys returns and initializes an API Keys client.
+//
+// The generated library doesn't include the API Keys API yet so requests are sent directly.
+func NewAPIKeys(ctx context.Context) (*APIKeys, error) {
+	c, _, err := htransport.NewClient(ctx, append(clientOptions(ctx), option.WithScopes(cloudPlatformScope))...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init api keys: %q", err)
+	}
+	return &APIKeys{client: c}, nil
+}
+
+// DeleteKey deletes the API key.
+func (a *APIKeys) DeleteKey(ctx context.Context, name string) error {
+	return a.do(ctx, http.MethodDelete, name, nil)
+}
+
+// UpdateKeyRestrictions replaces the restrictions of the API key. Referrers and IPs are mutually
+// exclusive application restrictions, if both are given the referrers are used.
+func (a *APIKeys) UpdateKeyRestrictions(ctx context.Context, name string, referrers, ips, apis []string) error {
+	r := &apiKeyRestrictions{}
+	switch {
+	case len(referrers) > 0:
+		r.BrowserKeyRestrictions = &browserKeyRestrictions{AllowedReferrers: referrers}
+	case len(ips) > 0:
+		r.ServerKeyRestrictions = &serverKeyRestrictions{AllowedIPs: ips}
+	}
+	for _, api := range apis {
+		r.APITargets = append(r.APITargets, &apiTarget{Service: api})
+	}
+	body := struct {
+		Restrictions *apiKeyRestrictions `json:"restrictions"`
+	}{r}
+	return a.do(ctx, http.MethodPatch, name+"?updateMask=restrictions", body)
+}
+
+func (a *APIKeys) do(ctx context.Context, method, path string, body interface{}) error {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, apiKeysEndpoint+path, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := a.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("api keys %s %s returned %d: %s", method, path, resp.StatusCode, b)
+	}
+	return nil
+}
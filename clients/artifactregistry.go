@@ -0,0 +1,72 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	artifactregistry "google.golang.org/api/artifactregistry/v1beta1"
+)
+
+// ArtifactRegistry client.
+type ArtifactRegistry struct {
+	service *artifactregistry.Service
+}
+
+// NewArtifactRegistry returns and initializes an Artifact Registry client.
+func NewArtifactRegistry(ctx context.Context) (*ArtifactRegistry, error) {
+	a, err := artifactregistry.NewService(ctx, clientOptions(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init artifact registry: %q", err)
+	}
+	return &ArtifactRegistry{service: a}, nil
+}
+
+// GetRepositoryPolicy returns the IAM policy of the repository.
+func (a *ArtifactRegistry) GetRepositoryPolicy(ctx context.Context, name string) (*artifactregistry.Policy, error) {
+	return a.service.Projects.Locations.Repositories.GetIamPolicy(name).Context(ctx).Do()
+}
+
+// SetRepositoryPolicy sets the IAM policy of the repository.
+func (a *ArtifactRegistry) SetRepositoryPolicy(ctx context.Context, name string, policy *artifactregistry.Policy) (*artifactregistry.Policy, error) {
+	return a.service.Projects.Locations.Repositories.SetIamPolicy(name, &artifactregistry.SetIamPolicyRequest{Policy: policy}).Context(ctx).Do()
+}
+
+// ListTags returns the tags of the package.
+func (a *ArtifactRegistry) ListTags(ctx context.Context, parent string) ([]*artifactregistry.Tag, error) {
+	tags := []*artifactregistry.Tag{}
+	err := a.service.Projects.Locations.Repositories.Packages.Tags.List(parent).Pages(ctx, func(page *artifactregistry.ListTagsResponse) error {
+		tags = append(tags, page.Tags...)
+		return nil
+	})
+	return tags, err
+}
+
+// CreateTag creates a tag within the package.
+func (a *ArtifactRegistry) CreateTag(ctx context.Context, parent, tagID string, tag *artifactregistry.Tag) (*artifactregistry.Tag, error) {
+	return a.service.Projects.Locations.Repositories.Packages.Tags.Create(parent, tag).TagId(tagID).Context(ctx).Do()
+}
+
+// UpdateTagVersion points the tag at a new version.
+func (a *ArtifactRegistry) UpdateTagVersion(ctx context.Context, name, version string) (*artifactregistry.Tag, error) {
+	return a.service.Projects.Locations.Repositories.Packages.Tags.Patch(name, &artifactregistry.Tag{Version: version}).UpdateMask("version").Context(ctx).Do()
+}
+
+// DeleteTag deletes the tag.
+func (a *ArtifactRegistry) DeleteTag(ctx context.Context, name string) error {
+	_, err := a.service.Projects.Locations.Repositories.Packages.Tags.Delete(name).Context(ctx).Do()
+	return err
+}
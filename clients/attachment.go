@@ -0,0 +1,41 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"mime"
+	"net/http"
+	"path/filepath"
+)
+
+// Attachment is a file attached to an email or message, such as the diff of an IAM policy.
+type Attachment struct {
+	Filename string
+	// ContentType is the MIME type of the content. If empty it is guessed from the extension of the
+	// filename, or else from the content.
+	ContentType string
+	Content     []byte
+}
+
+// Type returns the MIME type of the attachment.
+func (a Attachment) Type() string {
+	if a.ContentType != "" {
+		return a.ContentType
+	}
+	if t := mime.TypeByExtension(filepath.Ext(a.Filename)); t != "" {
+		return t
+	}
+	return http.DetectContentType(a.Content)
+}
@@ -0,0 +1,131 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/iam"
+)
+
+// AWSCredentials are the credentials of the AWS IAM user or role automations act as, stored as
+// JSON in Secret Manager.
+type AWSCredentials struct {
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+	// SessionToken is only set for temporary credentials.
+	SessionToken string `json:"session_token"`
+}
+
+// AWS client calls the IAM and EC2 APIs of an AWS account.
+type AWS struct {
+	session *session.Session
+	iam     *iam.IAM
+}
+
+// NewAWS returns and initializes an AWS client acting with the credentials.
+func NewAWS(creds AWSCredentials) (*AWS, error) {
+	s, err := session.NewSession(&aws.Config{
+		Credentials: credentials.NewStaticCredentials(creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken),
+		// IAM is global, EC2 clients are created for the region of the instance.
+		Region: aws.String("us-east-1"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to init aws: %q", err)
+	}
+	return &AWS{session: s, iam: iam.New(s)}, nil
+}
+
+// ec2 returns an EC2 client of the region.
+func (a *AWS) ec2(region string) *ec2.EC2 {
+	return ec2.New(a.session, aws.NewConfig().WithRegion(region))
+}
+
+// UpdateAccessKey sets the status of the access key of the IAM user, "Active" or "Inactive".
+func (a *AWS) UpdateAccessKey(ctx context.Context, userName, accessKeyID, status string) error {
+	_, err := a.iam.UpdateAccessKeyWithContext(ctx, &iam.UpdateAccessKeyInput{
+		UserName:    aws.String(userName),
+		AccessKeyId: aws.String(accessKeyID),
+		Status:      aws.String(status),
+	})
+	return err
+}
+
+// DescribeInstance returns the EC2 instance.
+func (a *AWS) DescribeInstance(ctx context.Context, region, instanceID string) (*ec2.Instance, error) {
+	out, err := a.ec2(region).DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []*string{aws.String(instanceID)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range out.Reservations {
+		if len(r.Instances) > 0 {
+			return r.Instances[0], nil
+		}
+	}
+	return nil, fmt.Errorf("instance %q not found in %q", instanceID, region)
+}
+
+// SecurityGroups returns the security groups of the VPC with the name.
+func (a *AWS) SecurityGroups(ctx context.Context, region, vpcID, name string) ([]*ec2.SecurityGroup, error) {
+	out, err := a.ec2(region).DescribeSecurityGroupsWithContext(ctx, &ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{
+			{Name: aws.String("vpc-id"), Values: []*string{aws.String(vpcID)}},
+			{Name: aws.String("group-name"), Values: []*string{aws.String(name)}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.SecurityGroups, nil
+}
+
+// CreateSecurityGroup creates a security group in the VPC and returns its ID.
+func (a *AWS) CreateSecurityGroup(ctx context.Context, region, vpcID, name, description string) (string, error) {
+	out, err := a.ec2(region).CreateSecurityGroupWithContext(ctx, &ec2.CreateSecurityGroupInput{
+		VpcId:       aws.String(vpcID),
+		GroupName:   aws.String(name),
+		Description: aws.String(description),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(out.GroupId), nil
+}
+
+// RevokeSecurityGroupEgress removes the egress rules from the security group.
+func (a *AWS) RevokeSecurityGroupEgress(ctx context.Context, region, groupID string, permissions []*ec2.IpPermission) error {
+	_, err := a.ec2(region).RevokeSecurityGroupEgressWithContext(ctx, &ec2.RevokeSecurityGroupEgressInput{
+		GroupId:       aws.String(groupID),
+		IpPermissions: permissions,
+	})
+	return err
+}
+
+// ModifyInstanceSecurityGroups replaces the security groups of the primary network interface of
+// the instance.
+func (a *AWS) ModifyInstanceSecurityGroups(ctx context.Context, region, instanceID string, groupIDs []string) error {
+	_, err := a.ec2(region).ModifyInstanceAttributeWithContext(ctx, &ec2.ModifyInstanceAttributeInput{
+		InstanceId: aws.String(instanceID),
+		Groups:     aws.StringSlice(groupIDs),
+	})
+	return err
+}
@@ -0,0 +1,74 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+)
+
+// AzureCredentials are the credentials of the Azure service principal automations act as, stored
+// as JSON in Secret Manager.
+type AzureCredentials struct {
+	TenantID     string `json:"tenant_id"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+}
+
+// Azure client calls the Azure Resource Manager APIs of Azure subscriptions.
+type Azure struct {
+	authorizer autorest.Authorizer
+}
+
+// NewAzure returns and initializes an Azure client acting with the credentials.
+func NewAzure(creds AzureCredentials) (*Azure, error) {
+	a, err := auth.NewClientCredentialsConfig(creds.ClientID, creds.ClientSecret, creds.TenantID).Authorizer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init azure: %q", err)
+	}
+	return &Azure{authorizer: a}, nil
+}
+
+// containers returns a blob containers client of the subscription.
+func (a *Azure) containers(subscriptionID string) storage.BlobContainersClient {
+	c := storage.NewBlobContainersClient(subscriptionID)
+	c.Authorizer = a.authorizer
+	return c
+}
+
+// ContainerPublicAccess returns the public access level of the blob container, "None", "Blob" or
+// "Container".
+func (a *Azure) ContainerPublicAccess(ctx context.Context, subscriptionID, resourceGroup, account, container string) (string, error) {
+	c, err := a.containers(subscriptionID).Get(ctx, resourceGroup, account, container)
+	if err != nil {
+		return "", err
+	}
+	if c.ContainerProperties == nil {
+		return "", nil
+	}
+	return string(c.ContainerProperties.PublicAccess), nil
+}
+
+// SetContainerPublicAccess sets the public access level of the blob container.
+func (a *Azure) SetContainerPublicAccess(ctx context.Context, subscriptionID, resourceGroup, account, container, access string) error {
+	_, err := a.containers(subscriptionID).Update(ctx, resourceGroup, account, container, storage.BlobContainer{
+		ContainerProperties: &storage.ContainerProperties{PublicAccess: storage.PublicAccess(access)},
+	})
+	return err
+}
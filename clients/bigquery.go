@@ -47,3 +47,8 @@ func (bq *BigQuery) OverwriteDatasetMetadata(ctx context.Context, projectID, dat
 	blindWrite := ""
 	return bq.client.DatasetInProject(projectID, datasetID).Update(ctx, dm, blindWrite)
 }
+
+// Insert streams the rows, such as a struct or a slice of structs, into the table.
+func (bq *BigQuery) Insert(ctx context.Context, projectID, datasetID, tableID string, src interface{}) error {
+	return bq.client.DatasetInProject(projectID, datasetID).Table(tableID).Inserter().Put(ctx, src)
+}
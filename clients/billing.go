@@ -0,0 +1,46 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	cloudbilling "google.golang.org/api/cloudbilling/v1"
+)
+
+// Billing client.
+type Billing struct {
+	service *cloudbilling.APIService
+}
+
+// NewBilling returns and initializes a Cloud Billing client.
+func NewBilling(ctx context.Context) (*Billing, error) {
+	b, err := cloudbilling.NewService(ctx, clientOptions(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init billing: %q", err)
+	}
+	return &Billing{service: b}, nil
+}
+
+// GetBillingInfo returns the billing information of the project.
+func (b *Billing) GetBillingInfo(ctx context.Context, name string) (*cloudbilling.ProjectBillingInfo, error) {
+	return b.service.Projects.GetBillingInfo(name).Context(ctx).Do()
+}
+
+// UpdateBillingInfo sets the billing account of the project.
+func (b *Billing) UpdateBillingInfo(ctx context.Context, name string, info *cloudbilling.ProjectBillingInfo) (*cloudbilling.ProjectBillingInfo, error) {
+	return b.service.Projects.UpdateBillingInfo(name, info).Context(ctx).Do()
+}
@@ -0,0 +1,46 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	binaryauthorization "google.golang.org/api/binaryauthorization/v1"
+)
+
+// BinaryAuthorization client.
+type BinaryAuthorization struct {
+	service *binaryauthorization.Service
+}
+
+// NewBinaryAuthorization returns and initializes a Binary Authorization client.
+func NewBinaryAuthorization(ctx context.Context) (*BinaryAuthorization, error) {
+	b, err := binaryauthorization.NewService(ctx, clientOptions(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init binary authorization: %q", err)
+	}
+	return &BinaryAuthorization{service: b}, nil
+}
+
+// GetPolicy returns the Binary Authorization policy of the project.
+func (b *BinaryAuthorization) GetPolicy(ctx context.Context, name string) (*binaryauthorization.Policy, error) {
+	return b.service.Projects.GetPolicy(name).Context(ctx).Do()
+}
+
+// UpdatePolicy replaces the Binary Authorization policy of the project.
+func (b *BinaryAuthorization) UpdatePolicy(ctx context.Context, name string, policy *binaryauthorization.Policy) (*binaryauthorization.Policy, error) {
+	return b.service.Projects.UpdatePolicy(name, policy).Context(ctx).Do()
+}
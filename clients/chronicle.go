@@ -0,0 +1,73 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+)
+
+// chronicleIngestionScope is the OAuth scope of the Chronicle ingestion API.
+const chronicleIngestionScope = "https://www.googleapis.com/auth/malachite-ingestion"
+
+// Chronicle client sends UDM events with the Chronicle ingestion API.
+type Chronicle struct {
+	client   *http.Client
+	endpoint string
+}
+
+// NewChronicle returns and initializes a Chronicle client for the region, such as "europe", using
+// the ingestion credentials of the Chronicle instance. An empty region is the United States.
+func NewChronicle(ctx context.Context, region string, credentials []byte) (*Chronicle, error) {
+	c, _, err := htransport.NewClient(ctx, option.WithCredentialsJSON(credentials), option.WithScopes(chronicleIngestionScope))
+	if err != nil {
+		return nil, fmt.Errorf("failed to init chronicle: %q", err)
+	}
+	host := "malachiteingestion-pa.googleapis.com"
+	if region != "" && region != "us" {
+		host = region + "-" + host
+	}
+	return &Chronicle{client: c, endpoint: "https://" + host + "/v2/udmevents:batchCreate"}, nil
+}
+
+// CreateUDMEvents sends the UDM events, as JSON, to the Chronicle instance of the customer.
+func (c *Chronicle) CreateUDMEvents(ctx context.Context, customerID string, events ...json.RawMessage) error {
+	b, err := json.Marshal(map[string]interface{}{"customer_id": customerID, "events": events})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.endpoint, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("chronicle returned %d: %s", resp.StatusCode, b)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	cloudasset "google.golang.org/api/cloudasset/v1"
+)
+
+// CloudAsset client.
+type CloudAsset struct {
+	service *cloudasset.Service
+}
+
+// NewCloudAsset returns and initializes a Cloud Asset Inventory client.
+func NewCloudAsset(ctx context.Context) (*CloudAsset, error) {
+	c, err := cloudasset.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init cloud asset: %q", err)
+	}
+	return &CloudAsset{service: c}, nil
+}
+
+// SearchAllResources returns the resources within the scope matching the query.
+func (c *CloudAsset) SearchAllResources(ctx context.Context, scope, query string) ([]*cloudasset.ResourceSearchResult, error) {
+	results := []*cloudasset.ResourceSearchResult{}
+	err := c.service.V1.SearchAllResources(scope).Query(query).Pages(ctx, func(r *cloudasset.SearchAllResourcesResponse) error {
+		results = append(results, r.Results...)
+		return nil
+	})
+	return results, err
+}
@@ -0,0 +1,46 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	cloudfunctions "google.golang.org/api/cloudfunctions/v1"
+)
+
+// CloudFunctions client.
+type CloudFunctions struct {
+	service *cloudfunctions.Service
+}
+
+// NewCloudFunctions returns and initializes a Cloud Functions client.
+func NewCloudFunctions(ctx context.Context) (*CloudFunctions, error) {
+	cf, err := cloudfunctions.NewService(ctx, clientOptions(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init cloud functions: %q", err)
+	}
+	return &CloudFunctions{service: cf}, nil
+}
+
+// GetFunctionPolicy returns the IAM policy of a function.
+func (c *CloudFunctions) GetFunctionPolicy(ctx context.Context, name string) (*cloudfunctions.Policy, error) {
+	return c.service.Projects.Locations.Functions.GetIamPolicy(name).Context(ctx).Do()
+}
+
+// SetFunctionPolicy sets the IAM policy of a function.
+func (c *CloudFunctions) SetFunctionPolicy(ctx context.Context, name string, policy *cloudfunctions.Policy) (*cloudfunctions.Policy, error) {
+	return c.service.Projects.Locations.Functions.SetIamPolicy(name, &cloudfunctions.SetIamPolicyRequest{Policy: policy}).Context(ctx).Do()
+}
@@ -0,0 +1,61 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"fmt"
+
+	ci "google.golang.org/api/cloudidentity/v1"
+)
+
+// CloudIdentity client.
+type CloudIdentity struct {
+	service *ci.Service
+}
+
+// NewCloudIdentity returns and initializes a Cloud Identity client.
+//
+// The automation service account must be able to read groups, for example by being assigned the
+// Groups Reader admin role in the Google Admin console.
+func NewCloudIdentity(ctx context.Context) (*CloudIdentity, error) {
+	c, err := ci.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init cloud identity: %q", err)
+	}
+	return &CloudIdentity{service: c}, nil
+}
+
+// LookupGroup returns the resource name of the group with the given email.
+func (c *CloudIdentity) LookupGroup(ctx context.Context, email string) (string, error) {
+	resp, err := c.service.Groups.Lookup().GroupKeyId(email).Context(ctx).Do()
+	if err != nil {
+		return "", err
+	}
+	return resp.Name, nil
+}
+
+// GetGroup returns the group.
+func (c *CloudIdentity) GetGroup(ctx context.Context, name string) (*ci.Group, error) {
+	return c.service.Groups.Get(name).Context(ctx).Do()
+}
+
+// LookupMembership returns the resource name of the membership of the member in the group.
+func (c *CloudIdentity) LookupMembership(ctx context.Context, group, email string) (string, error) {
+	resp, err := c.service.Groups.Memberships.Lookup(group).MemberKeyId(email).Context(ctx).Do()
+	if err != nil {
+		return "", err
+	}
+	return resp.Name, nil
+}
@@ -0,0 +1,56 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	run "google.golang.org/api/run/v1"
+)
+
+// CloudRun client.
+type CloudRun struct {
+	service *run.APIService
+}
+
+// NewCloudRun returns and initializes a Cloud Run client.
+func NewCloudRun(ctx context.Context) (*CloudRun, error) {
+	r, err := run.NewService(ctx, clientOptions(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init cloud run: %q", err)
+	}
+	return &CloudRun{service: r}, nil
+}
+
+// GetServicePolicy returns the IAM policy of a service.
+func (c *CloudRun) GetServicePolicy(ctx context.Context, name string) (*run.Policy, error) {
+	return c.service.Projects.Locations.Services.GetIamPolicy(name).Context(ctx).Do()
+}
+
+// SetServicePolicy sets the IAM policy of a service.
+func (c *CloudRun) SetServicePolicy(ctx context.Context, name string, policy *run.Policy) (*run.Policy, error) {
+	return c.service.Projects.Locations.Services.SetIamPolicy(name, &run.SetIamPolicyRequest{Policy: policy}).Context(ctx).Do()
+}
+
+// GetService returns a service.
+func (c *CloudRun) GetService(ctx context.Context, name string) (*run.Service, error) {
+	return c.service.Projects.Locations.Services.Get(name).Context(ctx).Do()
+}
+
+// ReplaceService replaces a service with the one given.
+func (c *CloudRun) ReplaceService(ctx context.Context, name string, service *run.Service) (*run.Service, error) {
+	return c.service.Projects.Locations.Services.ReplaceService(name, service).Context(ctx).Do()
+}
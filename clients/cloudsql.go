@@ -31,7 +31,7 @@ type CloudSQL struct {
 
 // NewCloudSQL returns and initializes a Cloud SQL client.
 func NewCloudSQL(ctx context.Context) (*CloudSQL, error) {
-	sql, err := sqladmin.NewService(ctx)
+	sql, err := sqladmin.NewService(ctx, clientOptions(ctx)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to init scc: %q", err)
 	}
@@ -43,7 +43,7 @@ func NewCloudSQL(ctx context.Context) (*CloudSQL, error) {
 
 // UpdateUser updates a given user.
 func (s *CloudSQL) UpdateUser(ctx context.Context, projectID, instance, host, name string, user *sqladmin.User) (*sqladmin.Operation, error) {
-	return s.service.Users.Update(projectID, instance, user).Host(host).Context(ctx).Do()
+	return s.service.Users.Update(projectID, instance, user).Host(host).Name(name).Context(ctx).Do()
 }
 
 // PatchInstance updates partialy a Cloud SQL instance.
@@ -0,0 +1,42 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	cloudtrace "google.golang.org/api/cloudtrace/v2"
+)
+
+// CloudTrace client.
+type CloudTrace struct {
+	service *cloudtrace.Service
+}
+
+// NewCloudTrace returns and initializes a Cloud Trace client.
+func NewCloudTrace(ctx context.Context) (*CloudTrace, error) {
+	c, err := cloudtrace.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init cloud trace: %q", err)
+	}
+	return &CloudTrace{service: c}, nil
+}
+
+// BatchWriteSpans writes the spans to the traces of the project.
+func (c *CloudTrace) BatchWriteSpans(ctx context.Context, projectID string, spans []*cloudtrace.Span) error {
+	_, err := c.service.Projects.Traces.BatchWrite("projects/"+projectID, &cloudtrace.BatchWriteSpansRequest{Spans: spans}).Context(ctx).Do()
+	return err
+}
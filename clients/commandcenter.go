@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	commandcenter "cloud.google.com/go/securitycenter/apiv1beta1"
+	"google.golang.org/api/iterator"
 	sccpb "google.golang.org/genproto/googleapis/cloud/securitycenter/v1beta1"
 )
 
@@ -51,3 +52,35 @@ func (s *SecurityCommandCenter) AddSecurityMarks(ctx context.Context, request *s
 func (s *SecurityCommandCenter) SetFindingState(ctx context.Context, request *sccpb.SetFindingStateRequest) (*sccpb.Finding, error) {
 	return s.service.SetFindingState(ctx, request)
 }
+
+// ListFindings returns the findings matching the request.
+func (s *SecurityCommandCenter) ListFindings(ctx context.Context, request *sccpb.ListFindingsRequest) ([]*sccpb.Finding, error) {
+	findings := []*sccpb.Finding{}
+	it := s.service.ListFindings(ctx, request)
+	for {
+		f, err := it.Next()
+		if err == iterator.Done {
+			return findings, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, f)
+	}
+}
+
+// ListAssets returns the assets matching the request.
+func (s *SecurityCommandCenter) ListAssets(ctx context.Context, request *sccpb.ListAssetsRequest) ([]*sccpb.ListAssetsResponse_ListAssetsResult, error) {
+	results := []*sccpb.ListAssetsResponse_ListAssetsResult{}
+	it := s.service.ListAssets(ctx, request)
+	for {
+		r, err := it.Next()
+		if err == iterator.Done {
+			return results, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+}
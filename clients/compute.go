@@ -37,11 +37,12 @@ type Compute struct {
 	snapshots *compute.SnapshotsService
 	opsZone   *compute.ZoneOperationsService
 	opsGlobal *compute.GlobalOperationsService
+	opsRegion *compute.RegionOperationsService
 }
 
 // NewCompute returns and initializes a Compute client.
 func NewCompute(ctx context.Context) (*Compute, error) {
-	cc, err := compute.NewService(ctx)
+	cc, err := compute.NewService(ctx, clientOptions(ctx)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to init cs: %q", err)
 	}
@@ -51,67 +52,248 @@ func NewCompute(ctx context.Context) (*Compute, error) {
 		snapshots: compute.NewSnapshotsService(cc),
 		opsZone:   compute.NewZoneOperationsService(cc),
 		opsGlobal: compute.NewGlobalOperationsService(cc),
+		opsRegion: compute.NewRegionOperationsService(cc),
 	}, nil
 }
 
 // DiskInsert creates a new disk in the project.
 func (c *Compute) DiskInsert(ctx context.Context, projectID, zone string, disk *compute.Disk) (*compute.Operation, error) {
-	return c.disks.Insert(projectID, zone, disk).Context(ctx).Do()
+	var op *compute.Operation
+	err := retry(ctx, computeAPI, func() (err error) {
+		op, err = c.disks.Insert(projectID, zone, disk).Context(ctx).Do()
+		return err
+	})
+	return op, err
 }
 
 // DeleteDiskSnapshot deletes the given snapshot from the project.
 func (c *Compute) DeleteDiskSnapshot(ctx context.Context, project, snapshot string) (*compute.Operation, error) {
-	return c.snapshots.Delete(project, snapshot).Context(ctx).Do()
+	var op *compute.Operation
+	err := retry(ctx, computeAPI, func() (err error) {
+		op, err = c.snapshots.Delete(project, snapshot).Context(ctx).Do()
+		return err
+	})
+	return op, err
 }
 
 // InsertFirewallRule inserts a new firewall rule.
 func (c *Compute) InsertFirewallRule(ctx context.Context, projectID string, fw *compute.Firewall) (*compute.Operation, error) {
-	return c.compute.Firewalls.Insert(projectID, fw).Context(ctx).Do()
+	var op *compute.Operation
+	err := retry(ctx, computeAPI, func() (err error) {
+		op, err = c.compute.Firewalls.Insert(projectID, fw).Context(ctx).Do()
+		return err
+	})
+	return op, err
 }
 
 // PatchFirewallRule updates the firewall rule for the given project.
 func (c *Compute) PatchFirewallRule(ctx context.Context, projectID string, rule string, rb *compute.Firewall) (*compute.Operation, error) {
-	return c.compute.Firewalls.Patch(projectID, rule, rb).Context(ctx).Do()
+	var op *compute.Operation
+	err := retry(ctx, computeAPI, func() (err error) {
+		op, err = c.compute.Firewalls.Patch(projectID, rule, rb).Context(ctx).Do()
+		return err
+	})
+	return op, err
 }
 
 // DeleteFirewallRule deletes the firewall rule for the given project.
 func (c *Compute) DeleteFirewallRule(ctx context.Context, projectID string, rule string) (*compute.Operation, error) {
-	return c.compute.Firewalls.Delete(projectID, rule).Context(ctx).Do()
+	var op *compute.Operation
+	err := retry(ctx, computeAPI, func() (err error) {
+		op, err = c.compute.Firewalls.Delete(projectID, rule).Context(ctx).Do()
+		return err
+	})
+	return op, err
+}
+
+// ListFirewallRules returns a list of firewall rules for the given project.
+func (c *Compute) ListFirewallRules(ctx context.Context, projectID string) (*compute.FirewallList, error) {
+	var r *compute.FirewallList
+	err := retry(ctx, computeAPI, func() (err error) {
+		r, err = c.compute.Firewalls.List(projectID).Context(ctx).Do()
+		return err
+	})
+	return r, err
+}
+
+// ListGlobalForwardingRules returns the global forwarding rules of the project.
+func (c *Compute) ListGlobalForwardingRules(ctx context.Context, projectID string) (*compute.ForwardingRuleList, error) {
+	var r *compute.ForwardingRuleList
+	err := retry(ctx, computeAPI, func() (err error) {
+		r, err = c.compute.GlobalForwardingRules.List(projectID).Context(ctx).Do()
+		return err
+	})
+	return r, err
+}
+
+// DeleteGlobalForwardingRule deletes a global forwarding rule.
+func (c *Compute) DeleteGlobalForwardingRule(ctx context.Context, projectID, rule string) (*compute.Operation, error) {
+	var op *compute.Operation
+	err := retry(ctx, computeAPI, func() (err error) {
+		op, err = c.compute.GlobalForwardingRules.Delete(projectID, rule).Context(ctx).Do()
+		return err
+	})
+	return op, err
+}
+
+// TargetHTTPProxy returns the target HTTP proxy.
+func (c *Compute) TargetHTTPProxy(ctx context.Context, projectID, proxy string) (*compute.TargetHttpProxy, error) {
+	var r *compute.TargetHttpProxy
+	err := retry(ctx, computeAPI, func() (err error) {
+		r, err = c.compute.TargetHttpProxies.Get(projectID, proxy).Context(ctx).Do()
+		return err
+	})
+	return r, err
+}
+
+// TargetHTTPSProxy returns the target HTTPS proxy.
+func (c *Compute) TargetHTTPSProxy(ctx context.Context, projectID, proxy string) (*compute.TargetHttpsProxy, error) {
+	var r *compute.TargetHttpsProxy
+	err := retry(ctx, computeAPI, func() (err error) {
+		r, err = c.compute.TargetHttpsProxies.Get(projectID, proxy).Context(ctx).Do()
+		return err
+	})
+	return r, err
+}
+
+// URLMap returns the URL map.
+func (c *Compute) URLMap(ctx context.Context, projectID, urlMap string) (*compute.UrlMap, error) {
+	var r *compute.UrlMap
+	err := retry(ctx, computeAPI, func() (err error) {
+		r, err = c.compute.UrlMaps.Get(projectID, urlMap).Context(ctx).Do()
+		return err
+	})
+	return r, err
+}
+
+// BackendService returns the global backend service.
+func (c *Compute) BackendService(ctx context.Context, projectID, service string) (*compute.BackendService, error) {
+	var r *compute.BackendService
+	err := retry(ctx, computeAPI, func() (err error) {
+		r, err = c.compute.BackendServices.Get(projectID, service).Context(ctx).Do()
+		return err
+	})
+	return r, err
+}
+
+// PatchBackendService patches the global backend service.
+func (c *Compute) PatchBackendService(ctx context.Context, projectID, service string, bs *compute.BackendService) (*compute.Operation, error) {
+	var op *compute.Operation
+	err := retry(ctx, computeAPI, func() (err error) {
+		op, err = c.compute.BackendServices.Patch(projectID, service, bs).Context(ctx).Do()
+		return err
+	})
+	return op, err
 }
 
 // GetInstance returns the specified compute instance resource.
 func (c *Compute) GetInstance(ctx context.Context, project, zone, instance string) (*compute.Instance, error) {
-	return c.compute.Instances.Get(project, zone, instance).Context(ctx).Do()
+	var r *compute.Instance
+	err := retry(ctx, computeAPI, func() (err error) {
+		r, err = c.compute.Instances.Get(project, zone, instance).Context(ctx).Do()
+		return err
+	})
+	return r, err
+}
+
+// SetInstanceMetadata sets the metadata of the specified compute instance resource.
+func (c *Compute) SetInstanceMetadata(ctx context.Context, project, zone, instance string, metadata *compute.Metadata) (*compute.Operation, error) {
+	var op *compute.Operation
+	err := retry(ctx, computeAPI, func() (err error) {
+		op, err = c.compute.Instances.SetMetadata(project, zone, instance, metadata).Context(ctx).Do()
+		return err
+	})
+	return op, err
+}
+
+// SetInstanceTags sets the network tags of the specified compute instance resource.
+func (c *Compute) SetInstanceTags(ctx context.Context, project, zone, instance string, tags *compute.Tags) (*compute.Operation, error) {
+	var op *compute.Operation
+	err := retry(ctx, computeAPI, func() (err error) {
+		op, err = c.compute.Instances.SetTags(project, zone, instance, tags).Context(ctx).Do()
+		return err
+	})
+	return op, err
+}
+
+// GetProject returns the specified compute project resource.
+func (c *Compute) GetProject(ctx context.Context, project string) (*compute.Project, error) {
+	var r *compute.Project
+	err := retry(ctx, computeAPI, func() (err error) {
+		r, err = c.compute.Projects.Get(project).Context(ctx).Do()
+		return err
+	})
+	return r, err
+}
+
+// SetCommonInstanceMetadata sets the project-wide metadata shared by all instances in the project.
+func (c *Compute) SetCommonInstanceMetadata(ctx context.Context, project string, metadata *compute.Metadata) (*compute.Operation, error) {
+	var op *compute.Operation
+	err := retry(ctx, computeAPI, func() (err error) {
+		op, err = c.compute.Projects.SetCommonInstanceMetadata(project, metadata).Context(ctx).Do()
+		return err
+	})
+	return op, err
 }
 
 // DeleteAccessConfig deletes an access config from an instance's network interface.
 func (c *Compute) DeleteAccessConfig(ctx context.Context, project, zone, instance, accessConfig, networkInterface string) (*compute.Operation, error) {
-	return c.compute.Instances.DeleteAccessConfig(project, zone, instance, accessConfig, networkInterface).Context(ctx).Do()
+	var op *compute.Operation
+	err := retry(ctx, computeAPI, func() (err error) {
+		op, err = c.compute.Instances.DeleteAccessConfig(project, zone, instance, accessConfig, networkInterface).Context(ctx).Do()
+		return err
+	})
+	return op, err
 }
 
 // FirewallRule get the details of a firewall rule
 func (c *Compute) FirewallRule(ctx context.Context, projectID string, ruleID string) (*compute.Firewall, error) {
-	return c.compute.Firewalls.Get(projectID, ruleID).Context(ctx).Do()
+	var r *compute.Firewall
+	err := retry(ctx, computeAPI, func() (err error) {
+		r, err = c.compute.Firewalls.Get(projectID, ruleID).Context(ctx).Do()
+		return err
+	})
+	return r, err
 }
 
 // CreateSnapshot creates a snapshot of a specified persistent disk.
 func (c *Compute) CreateSnapshot(ctx context.Context, projectID, zone, disk string, rb *compute.Snapshot) (*compute.Operation, error) {
-	return c.compute.Disks.CreateSnapshot(projectID, zone, disk, rb).Context(ctx).Do()
+	var op *compute.Operation
+	err := retry(ctx, computeAPI, func() (err error) {
+		op, err = c.compute.Disks.CreateSnapshot(projectID, zone, disk, rb).Context(ctx).Do()
+		return err
+	})
+	return op, err
 }
 
 // ListDisks returns a list of disk for a given project.
 func (c *Compute) ListDisks(ctx context.Context, projectID, zone string) (*compute.DiskList, error) {
-	return c.compute.Disks.List(projectID, zone).Context(ctx).Do()
+	var r *compute.DiskList
+	err := retry(ctx, computeAPI, func() (err error) {
+		r, err = c.compute.Disks.List(projectID, zone).Context(ctx).Do()
+		return err
+	})
+	return r, err
 }
 
 // ListProjectSnapshots returns a list of snapshot reousrces for a given project.
 func (c *Compute) ListProjectSnapshots(ctx context.Context, projectID string) (*compute.SnapshotList, error) {
-	return c.compute.Snapshots.List(projectID).Context(ctx).Do()
+	var r *compute.SnapshotList
+	err := retry(ctx, computeAPI, func() (err error) {
+		r, err = c.compute.Snapshots.List(projectID).Context(ctx).Do()
+		return err
+	})
+	return r, err
 }
 
 // SetLabels sets labels on a snapshot.
 func (c *Compute) SetLabels(ctx context.Context, projectID, resource string, rb *compute.GlobalSetLabelsRequest) (*compute.Operation, error) {
-	return c.compute.Snapshots.SetLabels(projectID, resource, rb).Context(ctx).Do()
+	var op *compute.Operation
+	err := retry(ctx, computeAPI, func() (err error) {
+		op, err = c.compute.Snapshots.SetLabels(projectID, resource, rb).Context(ctx).Do()
+		return err
+	})
+	return op, err
 }
 
 // WaitZone will wait for the zonal operation to complete.
@@ -128,19 +310,85 @@ func (c *Compute) WaitGlobal(project string, op *compute.Operation) []error {
 	})
 }
 
+// WaitRegion will wait for the regional operation to complete.
+func (c *Compute) WaitRegion(project, region string, op *compute.Operation) []error {
+	return wait(op, func() (*compute.Operation, error) {
+		return c.opsRegion.Get(project, region, fmt.Sprintf("%d", op.Id)).Do()
+	})
+}
+
+// SetPrivateIPGoogleAccess sets whether VMs in the subnetwork can reach Google APIs without an external IP.
+func (c *Compute) SetPrivateIPGoogleAccess(ctx context.Context, projectID, region, subnetwork string, enabled bool) (*compute.Operation, error) {
+	req := &compute.SubnetworksSetPrivateIpGoogleAccessRequest{
+		PrivateIpGoogleAccess: enabled,
+		ForceSendFields:       []string{"PrivateIpGoogleAccess"},
+	}
+	var op *compute.Operation
+	err := retry(ctx, computeAPI, func() (err error) {
+		op, err = c.compute.Subnetworks.SetPrivateIpGoogleAccess(projectID, region, subnetwork, req).Context(ctx).Do()
+		return err
+	})
+	return op, err
+}
+
+// Subnetwork returns the subnetwork.
+func (c *Compute) Subnetwork(ctx context.Context, projectID, region, subnetwork string) (*compute.Subnetwork, error) {
+	var r *compute.Subnetwork
+	err := retry(ctx, computeAPI, func() (err error) {
+		r, err = c.compute.Subnetworks.Get(projectID, region, subnetwork).Context(ctx).Do()
+		return err
+	})
+	return r, err
+}
+
+// PatchSubnetwork patches the subnetwork, the patch must include the subnetwork's current fingerprint.
+func (c *Compute) PatchSubnetwork(ctx context.Context, projectID, region, subnetwork string, sn *compute.Subnetwork) (*compute.Operation, error) {
+	var op *compute.Operation
+	err := retry(ctx, computeAPI, func() (err error) {
+		op, err = c.compute.Subnetworks.Patch(projectID, region, subnetwork, sn).Context(ctx).Do()
+		return err
+	})
+	return op, err
+}
+
 // StopInstance instance command to some instance/zone
 func (c *Compute) StopInstance(ctx context.Context, projectID, zone, instance string) (*compute.Operation, error) {
-	return c.compute.Instances.Stop(projectID, zone, instance).Context(ctx).Do()
+	var op *compute.Operation
+	err := retry(ctx, computeAPI, func() (err error) {
+		op, err = c.compute.Instances.Stop(projectID, zone, instance).Context(ctx).Do()
+		return err
+	})
+	return op, err
 }
 
 // StartInstance starts a given instance in given zone.
 func (c *Compute) StartInstance(ctx context.Context, projectID, zone, instance string) (*compute.Operation, error) {
-	return c.compute.Instances.Start(projectID, zone, instance).Context(ctx).Do()
+	var op *compute.Operation
+	err := retry(ctx, computeAPI, func() (err error) {
+		op, err = c.compute.Instances.Start(projectID, zone, instance).Context(ctx).Do()
+		return err
+	})
+	return op, err
+}
+
+// UpdateShieldedInstanceConfig updates the Shielded VM options of an instance.
+func (c *Compute) UpdateShieldedInstanceConfig(ctx context.Context, projectID, zone, instance string, config *compute.ShieldedInstanceConfig) (*compute.Operation, error) {
+	var op *compute.Operation
+	err := retry(ctx, computeAPI, func() (err error) {
+		op, err = c.compute.Instances.UpdateShieldedInstanceConfig(projectID, zone, instance, config).Context(ctx).Do()
+		return err
+	})
+	return op, err
 }
 
 // DeleteInstance deletes a given instance in given zone.
 func (c *Compute) DeleteInstance(ctx context.Context, projectID, zone, instance string) (*compute.Operation, error) {
-	return c.compute.Instances.Delete(projectID, zone, instance).Context(ctx).Do()
+	var op *compute.Operation
+	err := retry(ctx, computeAPI, func() (err error) {
+		op, err = c.compute.Instances.Delete(projectID, zone, instance).Context(ctx).Do()
+		return err
+	})
+	return op, err
 }
 
 func wait(op *compute.Operation, fn func() (*compute.Operation, error)) []error {
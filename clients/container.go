@@ -28,7 +28,7 @@ type Container struct {
 
 // NewContainer returns and initializes a Container client.
 func NewContainer(ctx context.Context) (*Container, error) {
-	cc, err := container.NewService(ctx)
+	cc, err := container.NewService(ctx, clientOptions(ctx)...)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to init container service: %q", err)
 	}
@@ -39,3 +39,13 @@ func NewContainer(ctx context.Context) (*Container, error) {
 func (c *Container) UpdateAddonsConfig(ctx context.Context, projectID, zone, clusterID string, conf *container.SetAddonsConfigRequest) (*container.Operation, error) {
 	return c.container.Projects.Zones.Clusters.Addons(projectID, zone, clusterID, conf).Context(ctx).Do()
 }
+
+// UpdateCluster updates the settings of a given cluster.
+func (c *Container) UpdateCluster(ctx context.Context, projectID, zone, clusterID string, req *container.UpdateClusterRequest) (*container.Operation, error) {
+	return c.container.Projects.Zones.Clusters.Update(projectID, zone, clusterID, req).Context(ctx).Do()
+}
+
+// GetCluster returns the details of a given cluster.
+func (c *Container) GetCluster(ctx context.Context, projectID, zone, clusterID string) (*container.Cluster, error) {
+	return c.container.Projects.Zones.Clusters.Get(projectID, zone, clusterID).Context(ctx).Do()
+}
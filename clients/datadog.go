@@ -0,0 +1,85 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Datadog client posts events and submits metrics with the Datadog API.
+type Datadog struct {
+	client *http.Client
+	site   string
+	apiKey string
+}
+
+// NewDatadog returns and initializes a Datadog client for the site, such as "datadoghq.eu".
+func NewDatadog(site, apiKey string) *Datadog {
+	return &Datadog{client: &http.Client{Timeout: 30 * time.Second}, site: site, apiKey: apiKey}
+}
+
+// PostEvent posts an event to the event stream. Events with the same aggregation key are grouped.
+func (d *Datadog) PostEvent(ctx context.Context, title, text, alertType, aggregationKey string, tags []string, t time.Time) error {
+	return d.post(ctx, "/api/v1/events", map[string]interface{}{
+		"title":            title,
+		"text":             text,
+		"alert_type":       alertType,
+		"aggregation_key":  aggregationKey,
+		"tags":             tags,
+		"date_happened":    t.Unix(),
+		"source_type_name": "security-response-automation",
+	})
+}
+
+// SubmitMetric submits a point of the metric, of type "count" or "gauge".
+func (d *Datadog) SubmitMetric(ctx context.Context, metric, metricType string, value float64, tags []string, t time.Time) error {
+	return d.post(ctx, "/api/v1/series", map[string]interface{}{
+		"series": []map[string]interface{}{{
+			"metric": metric,
+			"type":   metricType,
+			"points": [][]float64{{float64(t.Unix()), value}},
+			"tags":   tags,
+		}},
+	})
+}
+
+func (d *Datadog) post(ctx context.Context, path string, body interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, "https://api."+d.site+path, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("DD-API-KEY", d.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := d.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("datadog returned %d: %s", resp.StatusCode, b)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	dataflow "google.golang.org/api/dataflow/v1b3"
+)
+
+// Dataflow client.
+type Dataflow struct {
+	service *dataflow.Service
+}
+
+// NewDataflow returns and initializes a Dataflow client.
+func NewDataflow(ctx context.Context) (*Dataflow, error) {
+	d, err := dataflow.NewService(ctx, clientOptions(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init dataflow: %q", err)
+	}
+	return &Dataflow{service: d}, nil
+}
+
+// UpdateJob updates the state of the Dataflow job.
+func (d *Dataflow) UpdateJob(ctx context.Context, projectID, location, jobID string, job *dataflow.Job) (*dataflow.Job, error) {
+	return d.service.Projects.Locations.Jobs.Update(projectID, location, jobID, job).Context(ctx).Do()
+}
@@ -0,0 +1,46 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	dataproc "google.golang.org/api/dataproc/v1"
+)
+
+// Dataproc client.
+type Dataproc struct {
+	service *dataproc.Service
+}
+
+// NewDataproc returns and initializes a Dataproc client.
+func NewDataproc(ctx context.Context) (*Dataproc, error) {
+	d, err := dataproc.NewService(ctx, clientOptions(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init dataproc: %q", err)
+	}
+	return &Dataproc{service: d}, nil
+}
+
+// CancelJob starts cancelling the Dataproc job.
+func (d *Dataproc) CancelJob(ctx context.Context, projectID, region, jobID string) (*dataproc.Job, error) {
+	return d.service.Projects.Regions.Jobs.Cancel(projectID, region, jobID, &dataproc.CancelJobRequest{}).Context(ctx).Do()
+}
+
+// DeleteCluster deletes the Dataproc cluster.
+func (d *Dataproc) DeleteCluster(ctx context.Context, projectID, region, cluster string) (*dataproc.Operation, error) {
+	return d.service.Projects.Regions.Clusters.Delete(projectID, region, cluster).Context(ctx).Do()
+}
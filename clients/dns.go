@@ -0,0 +1,45 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"fmt"
+
+	dns "google.golang.org/api/dns/v1"
+)
+
+// DNS client.
+type DNS struct {
+	service *dns.Service
+}
+
+// NewDNS returns and initializes a Cloud DNS client.
+func NewDNS(ctx context.Context) (*DNS, error) {
+	d, err := dns.NewService(ctx, clientOptions(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init dns: %q", err)
+	}
+	return &DNS{service: d}, nil
+}
+
+// GetManagedZone returns the managed zone.
+func (d *DNS) GetManagedZone(ctx context.Context, projectID, zone string) (*dns.ManagedZone, error) {
+	return d.service.ManagedZones.Get(projectID, zone).Context(ctx).Do()
+}
+
+// PatchManagedZone applies a patch to the managed zone.
+func (d *DNS) PatchManagedZone(ctx context.Context, projectID, zone string, mz *dns.ManagedZone) (*dns.Operation, error) {
+	return d.service.ManagedZones.Patch(projectID, zone, mz).Context(ctx).Do()
+}
@@ -0,0 +1,89 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"fmt"
+
+	firestore "google.golang.org/api/firestore/v1"
+)
+
+// Firestore client.
+type Firestore struct {
+	service *firestore.Service
+}
+
+// NewFirestore returns and initializes a Firestore client.
+func NewFirestore(ctx context.Context) (*Firestore, error) {
+	f, err := firestore.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init firestore: %q", err)
+	}
+	return &Firestore{service: f}, nil
+}
+
+// CreateDocument adds a document with the fields to the collection of the project's default database.
+// An ID is generated if documentID is empty.
+func (f *Firestore) CreateDocument(ctx context.Context, projectID, collection, documentID string, fields map[string]firestore.Value) error {
+	call := f.service.Projects.Databases.Documents.CreateDocument(documents(projectID), collection, &firestore.Document{Fields: fields})
+	if documentID != "" {
+		call = call.DocumentId(documentID)
+	}
+	_, err := call.Context(ctx).Do()
+	return err
+}
+
+// GetDocument returns the document of the collection in the project's default database.
+func (f *Firestore) GetDocument(ctx context.Context, projectID, collection, documentID string) (*firestore.Document, error) {
+	return f.service.Projects.Databases.Documents.Get(documents(projectID) + "/" + collection + "/" + documentID).Context(ctx).Do()
+}
+
+// UpdateDocument sets the fields of the document. If updateTime is set the document is only
+// updated if it was not changed since, so concurrent updates can't overwrite each other.
+func (f *Firestore) UpdateDocument(ctx context.Context, projectID, collection, documentID string, fields map[string]firestore.Value, updateTime string) error {
+	paths := make([]string, 0, len(fields))
+	for k := range fields {
+		paths = append(paths, k)
+	}
+	call := f.service.Projects.Databases.Documents.Patch(documents(projectID)+"/"+collection+"/"+documentID, &firestore.Document{Fields: fields}).UpdateMaskFieldPaths(paths...)
+	if updateTime != "" {
+		call = call.CurrentDocumentUpdateTime(updateTime)
+	}
+	_, err := call.Context(ctx).Do()
+	return err
+}
+
+// ListDocuments returns the documents of the collection in the project's default database.
+func (f *Firestore) ListDocuments(ctx context.Context, projectID, collection string) ([]*firestore.Document, error) {
+	docs := []*firestore.Document{}
+	err := f.service.Projects.Databases.Documents.List(documents(projectID), collection).Pages(ctx, func(r *firestore.ListDocumentsResponse) error {
+		docs = append(docs, r.Documents...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// DeleteDocument deletes the document of the collection in the project's default database.
+func (f *Firestore) DeleteDocument(ctx context.Context, projectID, collection, documentID, updateTime string) error {
+	_, err := f.service.Projects.Databases.Documents.Delete(documents(projectID) + "/" + collection + "/" + documentID).Context(ctx).Do()
+	return err
+}
+
+func documents(projectID string) string {
+	return fmt.Sprintf("projects/%s/databases/(default)/documents", projectID)
+}
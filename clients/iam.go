@@ -0,0 +1,90 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	iam "google.golang.org/api/iam/v1"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+)
+
+// iamEndpoint is the base URL of the IAM API.
+const iamEndpoint = "https://iam.googleapis.com/v1/"
+
+// IAM client.
+type IAM struct {
+	service *iam.Service
+	client  *http.Client
+}
+
+// NewIAM returns and initializes an IAM client.
+func NewIAM(ctx context.Context) (*IAM, error) {
+	i, err := iam.NewService(ctx, clientOptions(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init iam: %q", err)
+	}
+	c, _, err := htransport.NewClient(ctx, append(clientOptions(ctx), option.WithScopes(cloudPlatformScope))...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init iam http client: %q", err)
+	}
+	return &IAM{service: i, client: c}, nil
+}
+
+// ListServiceAccountKeys returns the user-managed keys of the given service account.
+func (i *IAM) ListServiceAccountKeys(ctx context.Context, name string) ([]*iam.ServiceAccountKey, error) {
+	resp, err := i.service.Projects.ServiceAccounts.Keys.List(name).KeyTypes("USER_MANAGED").Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return resp.Keys, nil
+}
+
+// GetServiceAccountPolicy returns the IAM policy of the given service account.
+func (i *IAM) GetServiceAccountPolicy(ctx context.Context, name string) (*iam.Policy, error) {
+	return i.service.Projects.ServiceAccounts.GetIamPolicy(name).OptionsRequestedPolicyVersion(policyVersion).Context(ctx).Do()
+}
+
+// SetServiceAccountPolicy sets the IAM policy of the given service account.
+func (i *IAM) SetServiceAccountPolicy(ctx context.Context, name string, policy *iam.Policy) (*iam.Policy, error) {
+	policy.Version = policyVersion
+	return i.service.Projects.ServiceAccounts.SetIamPolicy(name, &iam.SetIamPolicyRequest{Policy: policy}).Context(ctx).Do()
+}
+
+// DisableKey disables the given service account key.
+//
+// The generated IAM library doesn't expose the disable method yet so the request is sent directly.
+func (i *IAM) DisableKey(ctx context.Context, name string) error {
+	req, err := http.NewRequest(http.MethodPost, iamEndpoint+name+":disable", bytes.NewBufferString("{}"))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := i.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to disable key %q: %s: %s", name, resp.Status, b)
+	}
+	return nil
+}
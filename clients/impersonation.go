@@ -0,0 +1,107 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	iamcredentials "google.golang.org/api/iamcredentials/v1"
+	"google.golang.org/api/option"
+)
+
+// impersonationLifetime is how long the access tokens of impersonated service accounts last.
+const impersonationLifetime = "3600s"
+
+type impersonationKey struct{}
+
+var (
+	// mu guards tokenSources, the token sources of the impersonated service accounts, reused
+	// across remediations so tokens are only generated once they expire.
+	mu           sync.Mutex
+	tokenSources = map[string]oauth2.TokenSource{}
+)
+
+// WithImpersonation returns a context creating clients that act as the service account, using
+// access tokens generated with the IAM Credentials API by the service account of the function.
+// The function's service account needs roles/iam.serviceAccountTokenCreator on it.
+func WithImpersonation(ctx context.Context, serviceAccount string) (context.Context, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	ts, ok := tokenSources[serviceAccount]
+	if !ok {
+		// The IAM Credentials client is created as the function's service account.
+		s, err := iamcredentials.NewService(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to init iam credentials: %q", err)
+		}
+		ts = oauth2.ReuseTokenSource(nil, &impersonatedTokenSource{service: s, serviceAccount: serviceAccount})
+		tokenSources[serviceAccount] = ts
+	}
+	return context.WithValue(ctx, impersonationKey{}, ts), nil
+}
+
+// clientOptions returns the options clients are created with in the context, so remediations
+// run as the service account they impersonate, if any.
+func clientOptions(ctx context.Context) []option.ClientOption {
+	ts, ok := ctx.Value(impersonationKey{}).(oauth2.TokenSource)
+	if !ok {
+		return nil
+	}
+	return []option.ClientOption{option.WithTokenSource(ts)}
+}
+
+// CheckCredentials returns an error if the clients created in the context can't get an access
+// token, those of the impersonated service account if any or else of the function's.
+func CheckCredentials(ctx context.Context) error {
+	ts, ok := ctx.Value(impersonationKey{}).(oauth2.TokenSource)
+	if !ok {
+		var err error
+		if ts, err = google.DefaultTokenSource(ctx, cloudPlatformScope); err != nil {
+			return fmt.Errorf("failed to find default credentials: %q", err)
+		}
+	}
+	if _, err := ts.Token(); err != nil {
+		return fmt.Errorf("failed to get access token: %q", err)
+	}
+	return nil
+}
+
+// impersonatedTokenSource generates access tokens of the service account.
+type impersonatedTokenSource struct {
+	service        *iamcredentials.Service
+	serviceAccount string
+}
+
+// Token returns a new access token of the service account.
+func (s *impersonatedTokenSource) Token() (*oauth2.Token, error) {
+	name := "projects/-/serviceAccounts/" + s.serviceAccount
+	resp, err := s.service.Projects.ServiceAccounts.GenerateAccessToken(name, &iamcredentials.GenerateAccessTokenRequest{
+		Scope:    []string{cloudPlatformScope},
+		Lifetime: impersonationLifetime,
+	}).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token of %q: %q", s.serviceAccount, err)
+	}
+	expiry, err := time.Parse(time.RFC3339, resp.ExpireTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse expiry of access token of %q: %q", s.serviceAccount, err)
+	}
+	return &oauth2.Token{AccessToken: resp.AccessToken, TokenType: "Bearer", Expiry: expiry}, nil
+}
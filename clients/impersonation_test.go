@@ -0,0 +1,56 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	iamcredentials "google.golang.org/api/iamcredentials/v1"
+	"google.golang.org/api/option"
+)
+
+func TestImpersonatedTokenSource(t *testing.T) {
+	const serviceAccount = "remediator@folder-project.iam.gserviceaccount.com"
+	var path string
+	var req iamcredentials.GenerateAccessTokenRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		_, _ = w.Write([]byte(`{"accessToken": "token-1", "expireTime": "2020-01-01T01:00:00Z"}`))
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+	s, err := iamcredentials.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("failed to init iam credentials: %q", err)
+	}
+	token, err := (&impersonatedTokenSource{service: s, serviceAccount: serviceAccount}).Token()
+	if err != nil {
+		t.Fatalf("failed to get token: %q", err)
+	}
+	if want := "/v1/projects/-/serviceAccounts/" + serviceAccount + ":generateAccessToken"; path != want {
+		t.Errorf("got path %q want %q", path, want)
+	}
+	if len(req.Scope) != 1 || req.Scope[0] != cloudPlatformScope || req.Lifetime != impersonationLifetime {
+		t.Errorf("unexpected request %+v", req)
+	}
+	if token.AccessToken != "token-1" || token.Expiry.Format("15:04") != "01:00" {
+		t.Errorf("unexpected token %+v", token)
+	}
+}
@@ -0,0 +1,84 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Jira client calls the Jira REST API with basic authentication.
+type Jira struct {
+	client *http.Client
+	url    string
+	user   string
+	token  string
+}
+
+// NewJira returns and initializes a Jira client for the site URL, such as
+// "https://cloudorg.atlassian.net", authenticating as the user with the API token.
+func NewJira(siteURL, user, token string) *Jira {
+	return &Jira{client: &http.Client{Timeout: 30 * time.Second}, url: strings.TrimSuffix(siteURL, "/"), user: user, token: token}
+}
+
+// CreateIssue creates the issue with the JSON body and returns its key.
+func (j *Jira) CreateIssue(ctx context.Context, body []byte) (string, error) {
+	b, err := j.do(ctx, http.MethodPost, "/rest/api/2/issue", body)
+	if err != nil {
+		return "", err
+	}
+	var r struct {
+		Key string `json:"key"`
+	}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return "", err
+	}
+	return r.Key, nil
+}
+
+// UpdateIssue edits the issue with the JSON body.
+func (j *Jira) UpdateIssue(ctx context.Context, key string, body []byte) error {
+	_, err := j.do(ctx, http.MethodPut, "/rest/api/2/issue/"+url.PathEscape(key), body)
+	return err
+}
+
+func (j *Jira) do(ctx context.Context, method, path string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest(method, j.url+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(j.user, j.token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := j.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("jira returned %d: %s", resp.StatusCode, b)
+	}
+	return b, nil
+}
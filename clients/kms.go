@@ -0,0 +1,61 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	kms "google.golang.org/api/cloudkms/v1"
+)
+
+// KMS client.
+type KMS struct {
+	service *kms.Service
+}
+
+// NewKMS returns and initializes a Cloud KMS client.
+func NewKMS(ctx context.Context) (*KMS, error) {
+	k, err := kms.NewService(ctx, clientOptions(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init kms: %q", err)
+	}
+	return &KMS{service: k}, nil
+}
+
+// GetKeyRingPolicy returns the IAM policy of the key ring.
+func (k *KMS) GetKeyRingPolicy(ctx context.Context, name string) (*kms.Policy, error) {
+	return k.service.Projects.Locations.KeyRings.GetIamPolicy(name).Context(ctx).Do()
+}
+
+// SetKeyRingPolicy sets the IAM policy of the key ring.
+func (k *KMS) SetKeyRingPolicy(ctx context.Context, name string, policy *kms.Policy) (*kms.Policy, error) {
+	return k.service.Projects.Locations.KeyRings.SetIamPolicy(name, &kms.SetIamPolicyRequest{Policy: policy}).Context(ctx).Do()
+}
+
+// GetCryptoKeyPolicy returns the IAM policy of the crypto key.
+func (k *KMS) GetCryptoKeyPolicy(ctx context.Context, name string) (*kms.Policy, error) {
+	return k.service.Projects.Locations.KeyRings.CryptoKeys.GetIamPolicy(name).Context(ctx).Do()
+}
+
+// SetCryptoKeyPolicy sets the IAM policy of the crypto key.
+func (k *KMS) SetCryptoKeyPolicy(ctx context.Context, name string, policy *kms.Policy) (*kms.Policy, error) {
+	return k.service.Projects.Locations.KeyRings.CryptoKeys.SetIamPolicy(name, &kms.SetIamPolicyRequest{Policy: policy}).Context(ctx).Do()
+}
+
+// PatchCryptoKey applies a patch to the crypto key limited to the fields in the update mask.
+func (k *KMS) PatchCryptoKey(ctx context.Context, name, updateMask string, key *kms.CryptoKey) (*kms.CryptoKey, error) {
+	return k.service.Projects.Locations.KeyRings.CryptoKeys.Patch(name, key).UpdateMask(updateMask).Context(ctx).Do()
+}
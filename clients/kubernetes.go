@@ -0,0 +1,113 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+)
+
+const (
+	cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+	// strategicMergePatch is the content type used when patching Kubernetes objects.
+	strategicMergePatch = "application/strategic-merge-patch+json"
+)
+
+// Kubernetes client talking to the API server of a single GKE cluster.
+type Kubernetes struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewKubernetes returns and initializes a Kubernetes client for the cluster at the given endpoint.
+// The cluster's base64 encoded CA certificate is used to verify the API server.
+func NewKubernetes(ctx context.Context, endpoint, caCertificate string) (*Kubernetes, error) {
+	ca, err := base64.StdEncoding.DecodeString(caCertificate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode cluster ca certificate: %q", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("failed to parse cluster ca certificate")
+	}
+	base := &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}
+	trans, err := htransport.NewTransport(ctx, base, append(clientOptions(ctx), option.WithScopes(cloudPlatformScope))...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init kubernetes transport: %q", err)
+	}
+	return &Kubernetes{endpoint: "https://" + endpoint, client: &http.Client{Transport: trans}}, nil
+}
+
+// Get reads the object at the given API path into out.
+func (k *Kubernetes) Get(ctx context.Context, path string, out interface{}) error {
+	return k.do(ctx, http.MethodGet, path, "", nil, out)
+}
+
+// Patch applies a strategic merge patch to the object at the given API path.
+func (k *Kubernetes) Patch(ctx context.Context, path string, patch interface{}) error {
+	return k.do(ctx, http.MethodPatch, path, strategicMergePatch, patch, nil)
+}
+
+// Post creates the body at the given API path.
+func (k *Kubernetes) Post(ctx context.Context, path string, body interface{}) error {
+	return k.do(ctx, http.MethodPost, path, "application/json", body, nil)
+}
+
+// Delete deletes the object at the given API path.
+func (k *Kubernetes) Delete(ctx context.Context, path string) error {
+	return k.do(ctx, http.MethodDelete, path, "", nil, nil)
+}
+
+func (k *Kubernetes) do(ctx context.Context, method, path, contentType string, body, out interface{}) error {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, k.endpoint+path, &buf)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := k.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("kubernetes api %s %s returned %d: %s", method, path, resp.StatusCode, b)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(b, out)
+}
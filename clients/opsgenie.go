@@ -0,0 +1,83 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Opsgenie client creates and closes alerts with the Opsgenie Alert API.
+type Opsgenie struct {
+	client *http.Client
+	apiURL string
+	apiKey string
+}
+
+// NewOpsgenie returns and initializes an Opsgenie client for the API, such as
+// "https://api.eu.opsgenie.com".
+func NewOpsgenie(apiURL, apiKey string) *Opsgenie {
+	return &Opsgenie{client: &http.Client{Timeout: 30 * time.Second}, apiURL: apiURL, apiKey: apiKey}
+}
+
+// CreateAlert creates an alert. Opsgenie deduplicates open alerts with the same alias.
+func (o *Opsgenie) CreateAlert(ctx context.Context, alias, message, description, priority string, tags []string, details map[string]string) error {
+	return o.post(ctx, "/v2/alerts", map[string]interface{}{
+		"alias":       alias,
+		"message":     message,
+		"description": description,
+		"priority":    priority,
+		"tags":        tags,
+		"details":     details,
+		"source":      "security-response-automation",
+	})
+}
+
+// CloseAlert closes the open alert with the alias, adding the note.
+func (o *Opsgenie) CloseAlert(ctx context.Context, alias, note string) error {
+	return o.post(ctx, "/v2/alerts/"+url.PathEscape(alias)+"/close?identifierType=alias", map[string]interface{}{
+		"note":   note,
+		"source": "security-response-automation",
+	})
+}
+
+func (o *Opsgenie) post(ctx context.Context, path string, body interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, o.apiURL+path, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "GenieKey "+o.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := o.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("opsgenie returned %d: %s", resp.StatusCode, b)
+	}
+	return nil
+}
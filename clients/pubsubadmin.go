@@ -0,0 +1,56 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	pubsub "google.golang.org/api/pubsub/v1"
+)
+
+// PubSubAdmin client used to manage the IAM policies of topics and subscriptions.
+type PubSubAdmin struct {
+	service *pubsub.Service
+}
+
+// NewPubSubAdmin returns and initializes a Pub/Sub admin client.
+func NewPubSubAdmin(ctx context.Context) (*PubSubAdmin, error) {
+	p, err := pubsub.NewService(ctx, clientOptions(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init pubsub admin: %q", err)
+	}
+	return &PubSubAdmin{service: p}, nil
+}
+
+// GetTopicPolicy returns the IAM policy of the topic.
+func (p *PubSubAdmin) GetTopicPolicy(ctx context.Context, name string) (*pubsub.Policy, error) {
+	return p.service.Projects.Topics.GetIamPolicy(name).Context(ctx).Do()
+}
+
+// SetTopicPolicy sets the IAM policy of the topic.
+func (p *PubSubAdmin) SetTopicPolicy(ctx context.Context, name string, policy *pubsub.Policy) (*pubsub.Policy, error) {
+	return p.service.Projects.Topics.SetIamPolicy(name, &pubsub.SetIamPolicyRequest{Policy: policy}).Context(ctx).Do()
+}
+
+// GetSubscriptionPolicy returns the IAM policy of the subscription.
+func (p *PubSubAdmin) GetSubscriptionPolicy(ctx context.Context, name string) (*pubsub.Policy, error) {
+	return p.service.Projects.Subscriptions.GetIamPolicy(name).Context(ctx).Do()
+}
+
+// SetSubscriptionPolicy sets the IAM policy of the subscription.
+func (p *PubSubAdmin) SetSubscriptionPolicy(ctx context.Context, name string, policy *pubsub.Policy) (*pubsub.Policy, error) {
+	return p.service.Projects.Subscriptions.SetIamPolicy(name, &pubsub.SetIamPolicyRequest{Policy: policy}).Context(ctx).Do()
+}
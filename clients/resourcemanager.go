@@ -16,61 +16,179 @@ package clients
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	crm "google.golang.org/api/cloudresourcemanager/v1"
+	crmv2 "google.golang.org/api/cloudresourcemanager/v2"
 )
 
+// policyVersion is the IAM policy version requested and set, which returns conditional role
+// bindings intact so setting the policy keeps their conditions.
+const policyVersion = 3
+
 // CloudResourceManager client.
 type CloudResourceManager struct {
 	service *crm.Service
+	// folders manages folders, which are only available in the v2 API.
+	folders *crmv2.Service
 }
 
 // NewCloudResourceManager returns and initalizes the Cloud Resource Manager client.
 func NewCloudResourceManager(ctx context.Context) (*CloudResourceManager, error) {
-	s, err := crm.NewService(ctx)
+	s, err := crm.NewService(ctx, clientOptions(ctx)...)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to init crm: %q", err)
 	}
-	return &CloudResourceManager{service: s}, nil
+	f, err := crmv2.NewService(ctx, clientOptions(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init crm v2: %q", err)
+	}
+	return &CloudResourceManager{service: s, folders: f}, nil
 }
 
 // GetPolicyProject returns the IAM policy for the given project resource.
 func (c *CloudResourceManager) GetPolicyProject(ctx context.Context, projectID string) (*crm.Policy, error) {
-	return c.service.Projects.GetIamPolicy(projectID, &crm.GetIamPolicyRequest{}).Context(ctx).Do()
+	req := &crm.GetIamPolicyRequest{Options: &crm.GetPolicyOptions{RequestedPolicyVersion: policyVersion}}
+	var r *crm.Policy
+	err := retry(ctx, resourceManagerAPI, func() (err error) {
+		r, err = c.service.Projects.GetIamPolicy(projectID, req).Context(ctx).Do()
+		return err
+	})
+	return r, err
 }
 
 // SetPolicyProject sets an IAM policy for the given project resource.
 func (c *CloudResourceManager) SetPolicyProject(ctx context.Context, projectID string, p *crm.Policy) (*crm.Policy, error) {
-	return c.service.Projects.SetIamPolicy(projectID, &crm.SetIamPolicyRequest{Policy: p}).Context(ctx).Do()
+	p.Version = policyVersion
+	var r *crm.Policy
+	err := retry(ctx, resourceManagerAPI, func() (err error) {
+		r, err = c.service.Projects.SetIamPolicy(projectID, &crm.SetIamPolicyRequest{Policy: p}).Context(ctx).Do()
+		return err
+	})
+	return r, err
 }
 
 // SetPolicyProjectWithMask sets an IAM policy for the given project resource.
 func (c *CloudResourceManager) SetPolicyProjectWithMask(ctx context.Context, projectID string, p *crm.Policy, updateField ...string) (*crm.Policy, error) {
 	req := &crm.SetIamPolicyRequest{Policy: p, UpdateMask: createMask(updateField)}
-	return c.service.Projects.SetIamPolicy(projectID, req).Context(ctx).Do()
+	var r *crm.Policy
+	err := retry(ctx, resourceManagerAPI, func() (err error) {
+		r, err = c.service.Projects.SetIamPolicy(projectID, req).Context(ctx).Do()
+		return err
+	})
+	return r, err
 }
 
 // GetAncestry returns the ancestry for the given project.
 func (c *CloudResourceManager) GetAncestry(ctx context.Context, projectID string) (*crm.GetAncestryResponse, error) {
-	return c.service.Projects.GetAncestry(projectID, &crm.GetAncestryRequest{}).Context(ctx).Do()
+	var r *crm.GetAncestryResponse
+	err := retry(ctx, resourceManagerAPI, func() (err error) {
+		r, err = c.service.Projects.GetAncestry(projectID, &crm.GetAncestryRequest{}).Context(ctx).Do()
+		return err
+	})
+	return r, err
 }
 
 // GetPolicyOrganization returns the IAM policy for the given organization resource.
 func (c *CloudResourceManager) GetPolicyOrganization(ctx context.Context, name string) (*crm.Policy, error) {
-	return c.service.Organizations.GetIamPolicy(name, &crm.GetIamPolicyRequest{}).Context(ctx).Do()
+	req := &crm.GetIamPolicyRequest{Options: &crm.GetPolicyOptions{RequestedPolicyVersion: policyVersion}}
+	var r *crm.Policy
+	err := retry(ctx, resourceManagerAPI, func() (err error) {
+		r, err = c.service.Organizations.GetIamPolicy(name, req).Context(ctx).Do()
+		return err
+	})
+	return r, err
 }
 
 // SetPolicyOrganization sets an IAM policy for the given organization resource.
 func (c *CloudResourceManager) SetPolicyOrganization(ctx context.Context, name string, p *crm.Policy) (*crm.Policy, error) {
-	return c.service.Organizations.SetIamPolicy(name, &crm.SetIamPolicyRequest{Policy: p}).Context(ctx).Do()
+	p.Version = policyVersion
+	var r *crm.Policy
+	err := retry(ctx, resourceManagerAPI, func() (err error) {
+		r, err = c.service.Organizations.SetIamPolicy(name, &crm.SetIamPolicyRequest{Policy: p}).Context(ctx).Do()
+		return err
+	})
+	return r, err
+}
+
+// GetPolicyFolder returns the IAM policy for the given folder resource.
+func (c *CloudResourceManager) GetPolicyFolder(ctx context.Context, name string) (*crm.Policy, error) {
+	req := &crmv2.GetIamPolicyRequest{Options: &crmv2.GetPolicyOptions{RequestedPolicyVersion: policyVersion}}
+	var p *crmv2.Policy
+	err := retry(ctx, resourceManagerAPI, func() (err error) {
+		p, err = c.folders.Folders.GetIamPolicy(name, req).Context(ctx).Do()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	var policy crm.Policy
+	if err := convertPolicy(p, &policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// SetPolicyFolder sets an IAM policy for the given folder resource.
+func (c *CloudResourceManager) SetPolicyFolder(ctx context.Context, name string, p *crm.Policy) (*crm.Policy, error) {
+	p.Version = policyVersion
+	var policy crmv2.Policy
+	if err := convertPolicy(p, &policy); err != nil {
+		return nil, err
+	}
+	err := retry(ctx, resourceManagerAPI, func() error {
+		_, err := c.folders.Folders.SetIamPolicy(name, &crmv2.SetIamPolicyRequest{Policy: &policy}).Context(ctx).Do()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// GetFolder returns the folder by resource name.
+func (c *CloudResourceManager) GetFolder(ctx context.Context, name string) (*crmv2.Folder, error) {
+	var r *crmv2.Folder
+	err := retry(ctx, resourceManagerAPI, func() (err error) {
+		r, err = c.folders.Folders.Get(name).Context(ctx).Do()
+		return err
+	})
+	return r, err
+}
+
+// SetOrgPolicyProject sets an organization policy constraint on a project.
+func (c *CloudResourceManager) SetOrgPolicyProject(ctx context.Context, projectID string, p *crm.OrgPolicy) (*crm.OrgPolicy, error) {
+	var r *crm.OrgPolicy
+	err := retry(ctx, resourceManagerAPI, func() (err error) {
+		r, err = c.service.Projects.SetOrgPolicy("projects/"+projectID, &crm.SetOrgPolicyRequest{Policy: p}).Context(ctx).Do()
+		return err
+	})
+	return r, err
 }
 
 // GetOrganization returns the organization info by resource name.
 func (c *CloudResourceManager) GetOrganization(ctx context.Context, name string) (*crm.Organization, error) {
-	return c.service.Organizations.Get(name).Context(ctx).Do()
+	var r *crm.Organization
+	err := retry(ctx, resourceManagerAPI, func() (err error) {
+		r, err = c.service.Organizations.Get(name).Context(ctx).Do()
+		return err
+	})
+	return r, err
+}
+
+// convertPolicy converts between the v1 and v2 IAM policies, which share the same representation.
+func convertPolicy(from, to interface{}) error {
+	b, err := json.Marshal(from)
+	if err != nil {
+		return fmt.Errorf("failed to marshal policy: %q", err)
+	}
+	if err := json.Unmarshal(b, to); err != nil {
+		return fmt.Errorf("failed to unmarshal policy: %q", err)
+	}
+	return nil
 }
 
 // createMask creates a string of comma separated field names to mark which fields to change.
@@ -0,0 +1,277 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/api/googleapi"
+)
+
+// APIs whose calls are retried, which circuit breakers open for.
+const (
+	computeAPI         = "compute"
+	resourceManagerAPI = "cloudresourcemanager"
+	storageAPI         = "storage"
+	sendGridAPI        = "sendgrid"
+)
+
+// tracerName names the tracer of the client calls.
+const tracerName = "github.com/googlecloudplatform/security-response-automation/clients"
+
+const (
+	defaultRetryAttempts   = 5
+	defaultRetryBackoff    = 500 * time.Millisecond
+	defaultRetryMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy configures how calls failing with transient errors are retried.
+type RetryPolicy struct {
+	// MaxAttempts is how many times a call is attempted, defaults to 5. Set to 1 to disable retries.
+	MaxAttempts int `yaml:"max_attempts"`
+	// Backoff is how long the first retry waits, doubled after each retry. Defaults to 500ms.
+	Backoff time.Duration
+	// MaxBackoff caps how long a retry waits, defaults to 30 seconds.
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+}
+
+// ErrRetriesExhausted is returned when a call still fails with a transient error after it was
+// retried, or once retrying it would pass the deadline of its context.
+type ErrRetriesExhausted struct {
+	// Attempts is how many times the call was attempted.
+	Attempts int
+	// Err is the error of the last attempt.
+	Err error
+}
+
+func (e *ErrRetriesExhausted) Error() string {
+	return fmt.Sprintf("retries exhausted after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns the error of the last attempt.
+func (e *ErrRetriesExhausted) Unwrap() error {
+	return e.Err
+}
+
+// ErrCircuitOpen is returned instead of calling an API whose circuit breaker is open.
+type ErrCircuitOpen struct {
+	API string
+}
+
+func (e *ErrCircuitOpen) Error() string {
+	return fmt.Sprintf("circuit breaker of %s is open", e.API)
+}
+
+// CircuitBreaker keeps APIs that fail persistently from being called until they recover.
+type CircuitBreaker interface {
+	// Allow returns whether the API may be called.
+	Allow(ctx context.Context, api string) bool
+	// Record records the outcome of a call, failed if it still failed once retries were exhausted.
+	Record(ctx context.Context, api string, failed bool)
+}
+
+var (
+	retryMu     sync.RWMutex
+	retryPolicy RetryPolicy
+	breaker     CircuitBreaker
+	// jitter returns a random duration in [0, n), replaced in tests.
+	jitter = func(n int64) int64 { return rand.Int63n(n) }
+)
+
+// SetRetryPolicy sets the policy the calls of all clients are retried with.
+func SetRetryPolicy(p RetryPolicy) {
+	retryMu.Lock()
+	defer retryMu.Unlock()
+	retryPolicy = p
+}
+
+func currentRetryPolicy() RetryPolicy {
+	retryMu.RLock()
+	defer retryMu.RUnlock()
+	return retryPolicy
+}
+
+// SetCircuitBreaker sets the circuit breaker consulted before the calls of all clients, nil to
+// disable it.
+func SetCircuitBreaker(b CircuitBreaker) {
+	retryMu.Lock()
+	defer retryMu.Unlock()
+	breaker = b
+}
+
+func currentCircuitBreaker() CircuitBreaker {
+	retryMu.RLock()
+	defer retryMu.RUnlock()
+	return breaker
+}
+
+func (p RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return defaultRetryAttempts
+	}
+	return p.MaxAttempts
+}
+
+// delay returns how long to wait after the given failed attempt: the exponential backoff of the
+// attempt, of which the second half is jittered so concurrent callers don't retry together.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	backoff, max := p.Backoff, p.MaxBackoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+	if max <= 0 {
+		max = defaultRetryMaxBackoff
+	}
+	d := backoff
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	half := int64(d / 2)
+	return time.Duration(half + jitter(half+1))
+}
+
+// statusError is returned by calls that responded with an unexpected HTTP status code.
+type statusError struct {
+	code int
+	err  error
+}
+
+func (e *statusError) Error() string {
+	return e.err.Error()
+}
+
+// transient returns whether the call failed because it was throttled, hit a server error or
+// didn't reach the API, so it may succeed when retried. Conflicts aren't transient: resending a
+// policy with a stale etag or inserting a resource that already exists fails the same way every
+// time. Errors wrapping these are transient as well.
+func transient(err error) bool {
+	var apiErr *googleapi.Error
+	var statusErr *statusError
+	switch {
+	case errors.As(err, &apiErr):
+		return transientCode(apiErr.Code)
+	case errors.As(err, &statusErr):
+		return transientCode(statusErr.code)
+	}
+	return unreachable(err)
+}
+
+func transientCode(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// unreachable returns whether the call failed to reach the API, such as when the connection was
+// reset or timed out. Calls canceled by their own context aren't retried.
+func unreachable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// retry attempts the call to the API until it succeeds or fails with an error that isn't
+// transient. Retries wait for an exponential backoff with jitter and stop once the attempts of the
+// retry policy are exhausted, or waiting would pass the deadline of the context. The API isn't
+// called while its circuit breaker is open. Each call is traced as a span named after the method of
+// the client, with an event for each retry.
+func retry(ctx context.Context, api string, call func() error) (err error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, caller(), trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("api", api)))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+	b := currentCircuitBreaker()
+	if b == nil {
+		return retryCall(ctx, call)
+	}
+	if !b.Allow(ctx, api) {
+		return &ErrCircuitOpen{API: api}
+	}
+	err = retryCall(ctx, call)
+	_, exhausted := err.(*ErrRetriesExhausted)
+	b.Record(ctx, api, exhausted)
+	return err
+}
+
+// caller returns the client method calling retry, such as "Compute.InsertFirewallRule".
+func caller() string {
+	pc, _, _, ok := runtime.Caller(2)
+	if !ok {
+		return "call"
+	}
+	name := runtime.FuncForPC(pc).Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	return strings.NewReplacer("clients.", "", "(*", "", ")", "").Replace(name)
+}
+
+func retryCall(ctx context.Context, call func() error) error {
+	p := currentRetryPolicy()
+	for attempt := 1; ; attempt++ {
+		err := call()
+		if err == nil || !transient(err) {
+			return err
+		}
+		if attempt >= p.maxAttempts() {
+			return &ErrRetriesExhausted{Attempts: attempt, Err: err}
+		}
+		d := p.delay(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(d).After(deadline) {
+			return &ErrRetriesExhausted{Attempts: attempt, Err: err}
+		}
+		trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
+			attribute.Int("attempt", attempt),
+			attribute.String("error", err.Error()),
+			attribute.String("backoff", d.String()),
+		))
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return &ErrRetriesExhausted{Attempts: attempt, Err: err}
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/sendgrid/rest"
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/api/googleapi"
+)
+
+func TestRetry(t *testing.T) {
+	SetRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond})
+	defer SetRetryPolicy(RetryPolicy{})
+	errThrottled := &googleapi.Error{Code: http.StatusTooManyRequests}
+	for _, tt := range []struct {
+		name      string
+		errs      []error
+		attempts  int
+		exhausted bool
+		wantErr   bool
+	}{
+		{name: "succeeds", errs: []error{nil}, attempts: 1},
+		{name: "succeeds after retries", errs: []error{errThrottled, &googleapi.Error{Code: http.StatusServiceUnavailable}, nil}, attempts: 3},
+		{name: "not transient", errs: []error{&googleapi.Error{Code: http.StatusNotFound}}, attempts: 1, wantErr: true},
+		{name: "already exists", errs: []error{&googleapi.Error{Code: http.StatusConflict}, nil}, attempts: 1, wantErr: true},
+		{name: "exhausted", errs: []error{errThrottled, errThrottled, errThrottled, nil}, attempts: 3, exhausted: true, wantErr: true},
+		{name: "wrapped", errs: []error{fmt.Errorf("failed to get policy: %w", errThrottled), nil}, attempts: 2},
+		{name: "connection reset", errs: []error{&url.Error{Op: "Get", URL: "https://compute.googleapis.com", Err: syscall.ECONNRESET}, nil}, attempts: 2},
+		{name: "timeout", errs: []error{timeoutError{}, nil}, attempts: 2},
+		{name: "canceled", errs: []error{&url.Error{Op: "Get", URL: "https://compute.googleapis.com", Err: context.Canceled}, nil}, attempts: 1, wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := retry(context.Background(), "test", func() error {
+				attempts++
+				return tt.errs[attempts-1]
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%s failed: got error %v", tt.name, err)
+			}
+			if attempts != tt.attempts {
+				t.Errorf("%s failed: got %d attempts want %d", tt.name, attempts, tt.attempts)
+			}
+			if e, ok := err.(*ErrRetriesExhausted); ok != tt.exhausted || (ok && e.Attempts != tt.attempts) {
+				t.Errorf("%s failed: got error %v", tt.name, err)
+			}
+		})
+	}
+}
+
+// timeoutError is a network error that timed out.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestRetryDeadline(t *testing.T) {
+	SetRetryPolicy(RetryPolicy{Backoff: time.Minute})
+	defer SetRetryPolicy(RetryPolicy{})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	attempts := 0
+	err := retry(ctx, "test", func() error {
+		attempts++
+		return &googleapi.Error{Code: http.StatusInternalServerError}
+	})
+	if _, ok := err.(*ErrRetriesExhausted); !ok || attempts != 1 {
+		t.Errorf("got error %v after %d attempts, want to stop before the deadline", err, attempts)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	defer func(j func(int64) int64) { jitter = j }(jitter)
+	jitter = func(n int64) int64 { return n - 1 }
+	p := RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	for _, tt := range []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{attempt: 1, min: 500 * time.Millisecond, max: time.Second},
+		{attempt: 2, min: time.Second, max: 2 * time.Second},
+		{attempt: 10, min: 2500 * time.Millisecond, max: 5 * time.Second},
+	} {
+		if got := p.delay(tt.attempt); got < tt.min || got > tt.max {
+			t.Errorf("attempt %d failed: got %s want within [%s, %s]", tt.attempt, got, tt.min, tt.max)
+		}
+	}
+}
+
+// throttledSendGrid throttles the first sends.
+type throttledSendGrid struct {
+	throttled, sends int
+}
+
+func (s *throttledSendGrid) Send(*mail.SGMailV3) (*rest.Response, error) {
+	s.sends++
+	if s.sends <= s.throttled {
+		return &rest.Response{StatusCode: http.StatusTooManyRequests}, nil
+	}
+	return &rest.Response{StatusCode: http.StatusAccepted}, nil
+}
+
+func TestSendGridRetry(t *testing.T) {
+	SetRetryPolicy(RetryPolicy{Backoff: time.Millisecond})
+	defer SetRetryPolicy(RetryPolicy{})
+	s := &throttledSendGrid{throttled: 2}
+	sendGrid := &SendGrid{Service: s}
+	if _, err := sendGrid.Send(context.Background(), "subject", "from", "body", []string{"tt"}); err != nil {
+		t.Fatalf("failed to send: %q", err)
+	}
+	if s.sends != 3 {
+		t.Errorf("got %d sends want 3", s.sends)
+	}
+	s = &throttledSendGrid{throttled: 10}
+	sendGrid.Service = s
+	_, err := sendGrid.Send(context.Background(), "subject", "from", "body", []string{"tt"})
+	if _, ok := err.(*ErrRetriesExhausted); !ok || s.sends != defaultRetryAttempts {
+		t.Errorf("got error %v after %d sends", err, s.sends)
+	}
+	// Sends stop retrying at the deadline of the invocation.
+	SetRetryPolicy(RetryPolicy{Backoff: time.Minute})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	s = &throttledSendGrid{throttled: 10}
+	sendGrid.Service = s
+	if _, err := sendGrid.Send(ctx, "subject", "from", "body", []string{"tt"}); err == nil || s.sends != 1 {
+		t.Errorf("got error %v after %d sends, want to stop before the deadline", err, s.sends)
+	}
+}
+
+// stubBreaker opens once a call exhausted its retries.
+type stubBreaker struct {
+	open     bool
+	recorded []bool
+}
+
+func (b *stubBreaker) Allow(ctx context.Context, api string) bool {
+	return !b.open
+}
+
+func (b *stubBreaker) Record(ctx context.Context, api string, failed bool) {
+	b.recorded = append(b.recorded, failed)
+	b.open = b.open || failed
+}
+
+func TestRetryCircuitBreaker(t *testing.T) {
+	SetRetryPolicy(RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond})
+	b := &stubBreaker{}
+	SetCircuitBreaker(b)
+	defer func() {
+		SetRetryPolicy(RetryPolicy{})
+		SetCircuitBreaker(nil)
+	}()
+	calls := 0
+	call := func() error {
+		calls++
+		return &googleapi.Error{Code: http.StatusServiceUnavailable}
+	}
+	if err := retry(context.Background(), computeAPI, call); err == nil {
+		t.Fatalf("expected the call to fail")
+	}
+	err := retry(context.Background(), computeAPI, call)
+	if e, ok := err.(*ErrCircuitOpen); !ok || e.API != computeAPI {
+		t.Errorf("got error %v want the circuit open", err)
+	}
+	if calls != 2 || len(b.recorded) != 1 || !b.recorded[0] {
+		t.Errorf("got %d calls and recorded %v", calls, b.recorded)
+	}
+}
+
+func TestRetrySpan(t *testing.T) {
+	SetRetryPolicy(RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond})
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	defer func() {
+		SetRetryPolicy(RetryPolicy{})
+		otel.SetTracerProvider(trace.NewNoopTracerProvider())
+	}()
+	err := retry(context.Background(), storageAPI, func() error {
+		return &googleapi.Error{Code: http.StatusServiceUnavailable}
+	})
+	if err == nil {
+		t.Fatalf("expected the call to fail")
+	}
+	spans := sr.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans want 1", len(spans))
+	}
+	s := spans[0]
+	if s.Name() != "TestRetrySpan" || s.SpanKind() != trace.SpanKindClient {
+		t.Errorf("got span %q of kind %v want the caller as a client span", s.Name(), s.SpanKind())
+	}
+	if s.Status().Code != codes.Error {
+		t.Errorf("got status %v want an error", s.Status())
+	}
+	if events := s.Events(); len(events) == 0 || events[0].Name != "retry" {
+		t.Errorf("got events %v want the retry", events)
+	}
+}
@@ -0,0 +1,63 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"google.golang.org/api/idtoken"
+)
+
+// RotationHook client calls an HTTP endpoint, such as a Cloud Function, that generates new secret values.
+type RotationHook struct {
+	client *http.Client
+	url    string
+}
+
+// NewRotationHook returns and initializes a rotation hook client. Requests are authenticated with
+// an identity token for the hook's URL.
+func NewRotationHook(ctx context.Context, url string) (*RotationHook, error) {
+	c, err := idtoken.NewClient(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init rotation hook: %q", err)
+	}
+	return &RotationHook{client: c, url: url}, nil
+}
+
+// Call posts the JSON body to the hook and returns the response body.
+func (r *RotationHook) Call(ctx context.Context, body []byte) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rotation hook returned %d: %s", resp.StatusCode, b)
+	}
+	return b, nil
+}
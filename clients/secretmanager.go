@@ -0,0 +1,76 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	secretmanager "google.golang.org/api/secretmanager/v1"
+)
+
+// SecretManager client.
+type SecretManager struct {
+	service *secretmanager.Service
+}
+
+// NewSecretManager returns and initializes a Secret Manager client.
+func NewSecretManager(ctx context.Context) (*SecretManager, error) {
+	sm, err := secretmanager.NewService(ctx, clientOptions(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init secret manager: %q", err)
+	}
+	return &SecretManager{service: sm}, nil
+}
+
+// CreateSecret creates a new secret, without any versions, in the given parent.
+func (s *SecretManager) CreateSecret(ctx context.Context, parent, secretID string, secret *secretmanager.Secret) (*secretmanager.Secret, error) {
+	return s.service.Projects.Secrets.Create(parent, secret).SecretId(secretID).Context(ctx).Do()
+}
+
+// AddSecretVersion adds a new version to an existing secret.
+func (s *SecretManager) AddSecretVersion(ctx context.Context, secret string, req *secretmanager.AddSecretVersionRequest) (*secretmanager.SecretVersion, error) {
+	return s.service.Projects.Secrets.AddVersion(secret, req).Context(ctx).Do()
+}
+
+// ListSecrets returns the secrets in the given parent.
+func (s *SecretManager) ListSecrets(ctx context.Context, parent string) ([]*secretmanager.Secret, error) {
+	secrets := []*secretmanager.Secret{}
+	err := s.service.Projects.Secrets.List(parent).Pages(ctx, func(page *secretmanager.ListSecretsResponse) error {
+		secrets = append(secrets, page.Secrets...)
+		return nil
+	})
+	return secrets, err
+}
+
+// ListSecretVersions returns the versions of a secret.
+func (s *SecretManager) ListSecretVersions(ctx context.Context, secret string) ([]*secretmanager.SecretVersion, error) {
+	versions := []*secretmanager.SecretVersion{}
+	err := s.service.Projects.Secrets.Versions.List(secret).Pages(ctx, func(page *secretmanager.ListSecretVersionsResponse) error {
+		versions = append(versions, page.Versions...)
+		return nil
+	})
+	return versions, err
+}
+
+// AccessSecretVersion returns the payload of a version of a secret.
+func (s *SecretManager) AccessSecretVersion(ctx context.Context, name string) (*secretmanager.AccessSecretVersionResponse, error) {
+	return s.service.Projects.Secrets.Versions.Access(name).Context(ctx).Do()
+}
+
+// DisableSecretVersion disables a version of a secret.
+func (s *SecretManager) DisableSecretVersion(ctx context.Context, name string) (*secretmanager.SecretVersion, error) {
+	return s.service.Projects.Secrets.Versions.Disable(name, &secretmanager.DisableSecretVersionRequest{}).Context(ctx).Do()
+}
@@ -15,6 +15,8 @@ package clients
 // limitations under the License.
 
 import (
+	"context"
+	"encoding/base64"
 	"fmt"
 
 	"github.com/sendgrid/rest"
@@ -42,23 +44,28 @@ func NewSendGridClient(apiKey string) *SendGrid {
 	return &SendGrid{Service: sendgrid.NewSendClient(apiKey)}
 }
 
-// Send email SendGrid.
-func (s *SendGrid) Send(subject, from, body string, to []string) (*rest.Response, error) {
-	e := createEmail(subject, from, body, emailSender, to)
-	r, err := s.Service.Send(e)
-
+// Send email SendGrid. Retries stop at the deadline of the context.
+func (s *SendGrid) Send(ctx context.Context, subject, from, body string, to []string, attachments ...Attachment) (*rest.Response, error) {
+	e := createEmail(subject, from, body, emailSender, to, attachments...)
+	var r *rest.Response
+	// Sends are retried when SendGrid throttles them or fails.
+	err := retry(ctx, sendGridAPI, func() (err error) {
+		r, err = s.Service.Send(e)
+		if err != nil {
+			return err
+		}
+		if r.StatusCode < 200 || r.StatusCode > 202 {
+			return &statusError{code: r.StatusCode, err: fmt.Errorf("Error to send email. StatusCode:(%d)", r.StatusCode)}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	if r.StatusCode < 200 || r.StatusCode > 202 {
-		return nil, fmt.Errorf("Error to send email. StatusCode:(%d)", r.StatusCode)
-	}
-
-	return r, err
+	return r, nil
 }
 
-func createEmail(subject, from, body, sender string, to []string) *mail.SGMailV3 {
+func createEmail(subject, from, body, sender string, to []string, attachments ...Attachment) *mail.SGMailV3 {
 	email := mail.NewV3Mail()
 	email.SetFrom(mail.NewEmail(sender, from))
 	email.Subject = subject
@@ -69,5 +76,12 @@ func createEmail(subject, from, body, sender string, to []string) *mail.SGMailV3
 	}
 	email.AddContent(mail.NewContent("text/plain", body))
 	email.AddPersonalizations(p)
+	for _, a := range attachments {
+		email.AddAttachment(mail.NewAttachment().
+			SetFilename(a.Filename).
+			SetType(a.Type()).
+			SetDisposition("attachment").
+			SetContent(base64.StdEncoding.EncodeToString(a.Content)))
+	}
 	return email
 }
@@ -15,6 +15,7 @@ package clients
 // limitations under the License.
 
 import (
+	"context"
 	"testing"
 
 	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
@@ -54,7 +55,7 @@ func TestClientSendGridSendEmail(t *testing.T) {
 			sendGrid := NewSendGridClient("api-key")
 			sendGrid.Service = tt.mockService
 
-			res, err := sendGrid.Send("subject", "from", "body", []string{"tt"})
+			res, err := sendGrid.Send(context.Background(), "subject", "from", "body", []string{"tt"})
 
 			if err != nil && err.Error() != tt.expectedError {
 				t.Errorf("%v failed exp:%v got:%v", tt.name, tt.expectedError, err)
@@ -66,3 +67,13 @@ func TestClientSendGridSendEmail(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateEmailAttachments(t *testing.T) {
+	e := createEmail("subject", "from", "body", emailSender, []string{"tt"}, Attachment{Filename: "firewall-rule.json", Content: []byte("{}")})
+	if len(e.Attachments) != 1 {
+		t.Fatalf("got %d attachments want 1", len(e.Attachments))
+	}
+	if a := e.Attachments[0]; a.Filename != "firewall-rule.json" || a.Type != "application/json" || a.Content != "e30=" || a.Disposition != "attachment" {
+		t.Errorf("unexpected attachment %+v", a)
+	}
+}
@@ -0,0 +1,116 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// incidentTable is the path of the table API for incidents. Reference fields such as the
+// assignment group can be set by their display value.
+const incidentTable = "/api/now/table/incident"
+
+// ServiceNow client calls the ServiceNow table API with basic authentication.
+type ServiceNow struct {
+	client   *http.Client
+	url      string
+	user     string
+	password string
+}
+
+// serviceNowResult holds the fields of table API responses used by the client.
+type serviceNowResult struct {
+	SysID string `json:"sys_id"`
+}
+
+// NewServiceNow returns and initializes a ServiceNow client for the instance URL, such as
+// "https://cloudorg.service-now.com", authenticating as the user.
+func NewServiceNow(instanceURL, user, password string) *ServiceNow {
+	return &ServiceNow{client: &http.Client{Timeout: 30 * time.Second}, url: strings.TrimSuffix(instanceURL, "/"), user: user, password: password}
+}
+
+// FindIncident returns the system ID of the incident with the correlation ID, or an empty string
+// if there is none.
+func (s *ServiceNow) FindIncident(ctx context.Context, correlationID string) (string, error) {
+	q := url.Values{}
+	q.Set("sysparm_query", "correlation_id="+correlationID)
+	q.Set("sysparm_fields", "sys_id")
+	q.Set("sysparm_limit", "1")
+	var r struct {
+		Result []serviceNowResult `json:"result"`
+	}
+	if err := s.do(ctx, http.MethodGet, incidentTable+"?"+q.Encode(), nil, &r); err != nil {
+		return "", err
+	}
+	if len(r.Result) == 0 {
+		return "", nil
+	}
+	return r.Result[0].SysID, nil
+}
+
+// CreateIncident creates the incident with the JSON body and returns its system ID.
+func (s *ServiceNow) CreateIncident(ctx context.Context, body []byte) (string, error) {
+	var r struct {
+		Result serviceNowResult `json:"result"`
+	}
+	if err := s.do(ctx, http.MethodPost, incidentTable+"?sysparm_input_display_value=true", body, &r); err != nil {
+		return "", err
+	}
+	return r.Result.SysID, nil
+}
+
+// UpdateIncident updates the incident with the JSON body.
+func (s *ServiceNow) UpdateIncident(ctx context.Context, sysID string, body []byte) error {
+	return s.do(ctx, http.MethodPatch, incidentTable+"/"+url.PathEscape(sysID)+"?sysparm_input_display_value=true", body, nil)
+}
+
+func (s *ServiceNow) do(ctx context.Context, method, path string, body []byte, v interface{}) error {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, s.url+path, r)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.user, s.password)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("servicenow returned %d: %s", resp.StatusCode, b)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.Unmarshal(b, v)
+}
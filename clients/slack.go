@@ -0,0 +1,139 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"time"
+)
+
+// slackAPI is the base URL of the Slack Web API.
+const slackAPI = "https://slack.com/api/"
+
+// Slack client calls the Slack Web API with a bot token.
+type Slack struct {
+	client *http.Client
+	token  string
+}
+
+// slackResponse holds the fields of Slack Web API responses used by the client.
+type slackResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+	TS    string `json:"ts"`
+	User  struct {
+		Profile struct {
+			Email string `json:"email"`
+		} `json:"profile"`
+	} `json:"user"`
+}
+
+// NewSlack returns and initializes a Slack client authenticating with the bot token.
+func NewSlack(token string) *Slack {
+	return &Slack{client: &http.Client{Timeout: 30 * time.Second}, token: token}
+}
+
+// PostMessage posts the JSON message with chat.postMessage and returns its timestamp, which
+// identifies the message to reply in its thread.
+func (s *Slack) PostMessage(ctx context.Context, body []byte) (string, error) {
+	req, err := http.NewRequest(http.MethodPost, slackAPI+"chat.postMessage", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	r, err := s.do(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return r.TS, nil
+}
+
+// UploadFile uploads the file with files.upload to the channel, in the thread of the message with
+// the timestamp if set.
+func (s *Slack) UploadFile(ctx context.Context, channel, threadTS, filename, contentType string, content []byte) error {
+	a := Attachment{Filename: filename, ContentType: contentType, Content: content}
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	fields := map[string]string{"channels": channel, "filename": a.Filename}
+	if threadTS != "" {
+		fields["thread_ts"] = threadTS
+	}
+	for k, v := range fields {
+		if err := w.WriteField(k, v); err != nil {
+			return err
+		}
+	}
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Disposition": {mime.FormatMediaType("form-data", map[string]string{"name": "file", "filename": a.Filename})},
+		"Content-Type":        {a.Type()},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(a.Content); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, slackAPI+"files.upload", &b)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	_, err = s.do(ctx, req)
+	return err
+}
+
+// UserEmail returns the email address of the Slack user with users.info.
+func (s *Slack) UserEmail(ctx context.Context, userID string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, slackAPI+"users.info?"+url.Values{"user": {userID}}.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	r, err := s.do(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return r.User.Profile.Email, nil
+}
+
+func (s *Slack) do(ctx context.Context, req *http.Request) (*slackResponse, error) {
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("slack returned %d", resp.StatusCode)
+	}
+	var r slackResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, err
+	}
+	if !r.OK {
+		return nil, fmt.Errorf("slack returned error %q", r.Error)
+	}
+	return &r, nil
+}
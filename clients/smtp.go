@@ -0,0 +1,98 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sendgrid/rest"
+)
+
+// smtpOK is the status of the response to emails the SMTP server accepted.
+const smtpOK = 250
+
+// SMTP client sends emails through an SMTP server. STARTTLS is used if the server supports it,
+// credentials are only sent over TLS.
+type SMTP struct {
+	addr string
+	auth smtp.Auth
+	// send sends the message, it is smtp.SendMail unless tests replace it.
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTP returns and initializes an SMTP client for the server, authenticating with the username
+// and password if set.
+func NewSMTP(host string, port int, username, password string) *SMTP {
+	s := &SMTP{addr: net.JoinHostPort(host, strconv.Itoa(port)), send: smtp.SendMail}
+	if username != "" {
+		s.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return s
+}
+
+// Send sends the plain text email with the attachments.
+func (s *SMTP) Send(_ context.Context, subject, from, body string, to []string, attachments ...Attachment) (*rest.Response, error) {
+	if err := s.send(s.addr, s.auth, from, to, smtpMessage(subject, from, body, to, time.Now(), attachments...)); err != nil {
+		return nil, fmt.Errorf("failed to send email: %q", err)
+	}
+	return &rest.Response{StatusCode: smtpOK}, nil
+}
+
+func smtpMessage(subject, from, body string, to []string, now time.Time, attachments ...Attachment) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s <%s>\r\n", mime.QEncoding.Encode("utf-8", emailSender), from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	text := strings.Replace(strings.Replace(body, "\r\n", "\n", -1), "\n", "\r\n", -1)
+	if len(attachments) == 0 {
+		b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+		b.WriteString(text)
+		return b.Bytes()
+	}
+	w := multipart.NewWriter(&b)
+	fmt.Fprintf(&b, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", w.Boundary())
+	// Writing to the buffer can't fail.
+	part, _ := w.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/plain; charset=utf-8"}})
+	part.Write([]byte(text))
+	for _, a := range attachments {
+		part, _ := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {a.Type()},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		encoded := base64.StdEncoding.EncodeToString(a.Content)
+		// Lines of base64 content are at most 76 characters.
+		for len(encoded) > 76 {
+			part.Write([]byte(encoded[:76] + "\r\n"))
+			encoded = encoded[76:]
+		}
+		part.Write([]byte(encoded + "\r\n"))
+	}
+	w.Close()
+	return b.Bytes()
+}
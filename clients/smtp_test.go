@@ -0,0 +1,82 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientSMTPSend(t *testing.T) {
+	var gotAddr, gotFrom string
+	var gotTo []string
+	var gotMsg []byte
+	s := NewSMTP("smtp.cloudorg.com", 587, "automation", "secret")
+	s.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotFrom, gotTo, gotMsg = addr, from, to, msg
+		return nil
+	}
+	r, err := s.Send(context.Background(), "CloseBucket: success", "automation@cloudorg.com", "line one\nline two", []string{"security@cloudorg.com", "oncall@cloudorg.com"})
+	if err != nil {
+		t.Fatalf("failed to send: %q", err)
+	}
+	if r.StatusCode != smtpOK {
+		t.Errorf("got status %d want %d", r.StatusCode, smtpOK)
+	}
+	if gotAddr != "smtp.cloudorg.com:587" || gotFrom != "automation@cloudorg.com" || len(gotTo) != 2 {
+		t.Errorf("unexpected envelope %q %q %v", gotAddr, gotFrom, gotTo)
+	}
+	msg := string(gotMsg)
+	for _, want := range []string{
+		"To: security@cloudorg.com, oncall@cloudorg.com\r\n",
+		"Subject: CloseBucket: success\r\n",
+		"\r\n\r\nline one\r\nline two",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message %q does not contain %q", msg, want)
+		}
+	}
+}
+
+func TestSMTPMessageEncodesHeaders(t *testing.T) {
+	msg := string(smtpMessage("Lösung", "a@cloudorg.com", "body", []string{"b@cloudorg.com"}, time.Date(2020, 6, 2, 0, 0, 0, 0, time.UTC)))
+	if !strings.Contains(msg, "Subject: =?utf-8?q?L=C3=B6sung?=\r\n") {
+		t.Errorf("subject not encoded in %q", msg)
+	}
+	if !strings.Contains(msg, "Date: Tue, 02 Jun 2020 00:00:00 +0000\r\n") {
+		t.Errorf("unexpected date in %q", msg)
+	}
+}
+
+func TestSMTPMessageAttachments(t *testing.T) {
+	msg := string(smtpMessage("subject", "a@cloudorg.com", "body", []string{"b@cloudorg.com"}, time.Now(),
+		Attachment{Filename: "policy-diff.json", Content: []byte(`{"Role":"roles/editor"}`)},
+		Attachment{Filename: "removed", ContentType: "text/plain", Content: []byte("user:bob@gmail.com")}))
+	for _, want := range []string{
+		"Content-Type: multipart/mixed; boundary=",
+		"Content-Type: text/plain; charset=utf-8\r\n\r\nbody",
+		"Content-Disposition: attachment; filename=policy-diff.json\r\n",
+		"Content-Type: application/json\r\n",
+		"eyJSb2xlIjoicm9sZXMvZWRpdG9yIn0=",
+		"Content-Type: text/plain\r\n",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message %q does not contain %q", msg, want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Splunk client sends events to a Splunk HTTP Event Collector.
+type Splunk struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+// NewSplunk returns and initializes a Splunk client for the collector, such as
+// "https://http-inputs-cloudorg.splunkcloud.com".
+func NewSplunk(url, token string) *Splunk {
+	return &Splunk{client: &http.Client{Timeout: 30 * time.Second}, url: strings.TrimSuffix(url, "/"), token: token}
+}
+
+// SendEvent sends the JSON event to the index with the source type. An empty index uses the
+// default index of the token.
+func (s *Splunk) SendEvent(ctx context.Context, index, sourceType, source string, t time.Time, event []byte) error {
+	b, err := json.Marshal(map[string]interface{}{
+		"time":       float64(t.UnixNano()) / float64(time.Second),
+		"index":      index,
+		"sourcetype": sourceType,
+		"source":     source,
+		"event":      json.RawMessage(event),
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.url+"/services/collector/event", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Splunk "+s.token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("splunk returned %d: %s", resp.StatusCode, b)
+	}
+	return nil
+}
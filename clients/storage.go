@@ -17,9 +17,11 @@ package clients
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 
 	"cloud.google.com/go/iam"
 	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
 )
 
 // Storage client.
@@ -29,7 +31,7 @@ type Storage struct {
 
 // NewStorage returns and initializes the Storage client.
 func NewStorage(ctx context.Context) (*Storage, error) {
-	c, err := storage.NewClient(ctx)
+	c, err := storage.NewClient(ctx, clientOptions(ctx)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to init storage: %q", err)
 	}
@@ -38,23 +40,97 @@ func NewStorage(ctx context.Context) (*Storage, error) {
 
 // SetBucketPolicy sets the policy for the given bucket.
 func (s *Storage) SetBucketPolicy(ctx context.Context, bucketName string, policy *iam.Policy) error {
-	return s.service.Bucket(bucketName).IAM().SetPolicy(ctx, policy)
+	return retry(ctx, storageAPI, func() error {
+		return s.service.Bucket(bucketName).IAM().SetPolicy(ctx, policy)
+	})
 }
 
 // BucketPolicy gets the IAM policy for the given bucket.
 func (s *Storage) BucketPolicy(ctx context.Context, bucketName string) (*iam.Policy, error) {
-	return s.service.Bucket(bucketName).IAM().Policy(ctx)
+	var policy *iam.Policy
+	err := retry(ctx, storageAPI, func() (err error) {
+		policy, err = s.service.Bucket(bucketName).IAM().Policy(ctx)
+		return err
+	})
+	return policy, err
 }
 
-// EnableBucketOnlyPolicy enables the bucket only policy for the given bucket.
-func (s *Storage) EnableBucketOnlyPolicy(ctx context.Context, bucketName string) error {
-	enableBucketPolicyOnly := storage.BucketAttrsToUpdate{
-		BucketPolicyOnly: &storage.BucketPolicyOnly{
-			Enabled: true,
+// SetBucketPolicyOnly enables or disables uniform bucket-level access for the given bucket.
+func (s *Storage) SetBucketPolicyOnly(ctx context.Context, bucketName string, enabled bool) error {
+	attrs := storage.BucketAttrsToUpdate{
+		UniformBucketLevelAccess: &storage.UniformBucketLevelAccess{
+			Enabled: enabled,
 		},
 	}
-	if _, err := s.service.Bucket(bucketName).Update(ctx, enableBucketPolicyOnly); err != nil {
+	return retry(ctx, storageAPI, func() error {
+		_, err := s.service.Bucket(bucketName).Update(ctx, attrs)
+		return err
+	})
+}
+
+// BucketAttrs returns the attributes of the given bucket.
+func (s *Storage) BucketAttrs(ctx context.Context, bucketName string) (*storage.BucketAttrs, error) {
+	var attrs *storage.BucketAttrs
+	err := retry(ctx, storageAPI, func() (err error) {
+		attrs, err = s.service.Bucket(bucketName).Attrs(ctx)
+		return err
+	})
+	return attrs, err
+}
+
+// DeleteBucketACL removes the ACL entry for the given entity from the bucket.
+func (s *Storage) DeleteBucketACL(ctx context.Context, bucketName string, entity storage.ACLEntity) error {
+	return retry(ctx, storageAPI, func() error {
+		return s.service.Bucket(bucketName).ACL().Delete(ctx, entity)
+	})
+}
+
+// WriteObject writes the data to the named object in the bucket, replacing any existing object.
+func (s *Storage) WriteObject(ctx context.Context, bucketName, name string, data []byte) error {
+	return retry(ctx, storageAPI, func() error {
+		w := s.service.Bucket(bucketName).Object(name).NewWriter(ctx)
+		if _, err := w.Write(data); err != nil {
+			w.Close()
+			return err
+		}
+		return w.Close()
+	})
+}
+
+// ReadObject returns the contents of the named object in the bucket.
+func (s *Storage) ReadObject(ctx context.Context, bucketName, name string) ([]byte, error) {
+	var b []byte
+	err := retry(ctx, storageAPI, func() error {
+		r, err := s.service.Bucket(bucketName).Object(name).NewReader(ctx)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		b, err = ioutil.ReadAll(r)
 		return err
+	})
+	return b, err
+}
+
+// ListObjects returns the names of the objects in the bucket starting with prefix.
+func (s *Storage) ListObjects(ctx context.Context, bucketName, prefix string) ([]string, error) {
+	var names []string
+	err := retry(ctx, storageAPI, func() error {
+		names = []string{}
+		it := s.service.Bucket(bucketName).Objects(ctx, &storage.Query{Prefix: prefix})
+		for {
+			attrs, err := it.Next()
+			if err == iterator.Done {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			names = append(names, attrs.Name)
+		}
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return names, nil
 }
@@ -0,0 +1,70 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+// AdminStub provides a stub for the Admin SDK client.
+type AdminStub struct {
+	StubbedUser   *admin.User
+	SavedUpdate   *admin.User
+	SignedOut     []string
+	StubbedTokens []*admin.Token
+	StubbedASPs   []*admin.Asp
+	DeletedTokens []string
+	DeletedASPs   []int64
+}
+
+// GetUser returns the stubbed user.
+func (a *AdminStub) GetUser(ctx context.Context, userKey string) (*admin.User, error) {
+	return a.StubbedUser, nil
+}
+
+// UpdateUser records the update applied to the user.
+func (a *AdminStub) UpdateUser(ctx context.Context, userKey string, user *admin.User) (*admin.User, error) {
+	a.SavedUpdate = user
+	return user, nil
+}
+
+// SignOutUser records the user signed out.
+func (a *AdminStub) SignOutUser(ctx context.Context, userKey string) error {
+	a.SignedOut = append(a.SignedOut, userKey)
+	return nil
+}
+
+// ListTokens returns the stubbed tokens.
+func (a *AdminStub) ListTokens(ctx context.Context, userKey string) (*admin.Tokens, error) {
+	return &admin.Tokens{Items: a.StubbedTokens}, nil
+}
+
+// DeleteToken records the token deleted.
+func (a *AdminStub) DeleteToken(ctx context.Context, userKey, clientID string) error {
+	a.DeletedTokens = append(a.DeletedTokens, clientID)
+	return nil
+}
+
+// ListASPs returns the stubbed application-specific passwords.
+func (a *AdminStub) ListASPs(ctx context.Context, userKey string) (*admin.Asps, error) {
+	return &admin.Asps{Items: a.StubbedASPs}, nil
+}
+
+// DeleteASP records the application-specific password deleted.
+func (a *AdminStub) DeleteASP(ctx context.Context, userKey string, codeID int64) error {
+	a.DeletedASPs = append(a.DeletedASPs, codeID)
+	return nil
+}
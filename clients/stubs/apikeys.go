@@ -0,0 +1,41 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+)
+
+// APIKeysStub provides a stub for the API Keys client.
+type APIKeysStub struct {
+	DeletedKeys    []string
+	SavedReferrers []string
+	SavedIPs       []string
+	SavedAPIs      []string
+}
+
+// DeleteKey records the deleted key.
+func (a *APIKeysStub) DeleteKey(ctx context.Context, name string) error {
+	a.DeletedKeys = append(a.DeletedKeys, name)
+	return nil
+}
+
+// UpdateKeyRestrictions records the restrictions applied to the key.
+func (a *APIKeysStub) UpdateKeyRestrictions(ctx context.Context, name string, referrers, ips, apis []string) error {
+	a.SavedReferrers = referrers
+	a.SavedIPs = ips
+	a.SavedAPIs = apis
+	return nil
+}
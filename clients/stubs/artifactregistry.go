@@ -0,0 +1,70 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	artifactregistry "google.golang.org/api/artifactregistry/v1beta1"
+)
+
+// ArtifactRegistryStub provides a stub for the Artifact Registry client.
+type ArtifactRegistryStub struct {
+	StubbedPolicy *artifactregistry.Policy
+	SavedPolicy   *artifactregistry.Policy
+	// SavedResource is the name of the repository whose policy was set.
+	SavedResource string
+	StubbedTags   []*artifactregistry.Tag
+	CreatedTags   []*artifactregistry.Tag
+	UpdatedTags   []*artifactregistry.Tag
+	DeletedTags   []string
+}
+
+// GetRepositoryPolicy returns the stubbed policy.
+func (a *ArtifactRegistryStub) GetRepositoryPolicy(ctx context.Context, name string) (*artifactregistry.Policy, error) {
+	return a.StubbedPolicy, nil
+}
+
+// SetRepositoryPolicy records the policy set on the repository.
+func (a *ArtifactRegistryStub) SetRepositoryPolicy(ctx context.Context, name string, policy *artifactregistry.Policy) (*artifactregistry.Policy, error) {
+	a.SavedPolicy = policy
+	a.SavedResource = name
+	return policy, nil
+}
+
+// ListTags returns the stubbed tags.
+func (a *ArtifactRegistryStub) ListTags(ctx context.Context, parent string) ([]*artifactregistry.Tag, error) {
+	return a.StubbedTags, nil
+}
+
+// CreateTag records the tag created.
+func (a *ArtifactRegistryStub) CreateTag(ctx context.Context, parent, tagID string, tag *artifactregistry.Tag) (*artifactregistry.Tag, error) {
+	t := &artifactregistry.Tag{Name: parent + "/tags/" + tagID, Version: tag.Version}
+	a.CreatedTags = append(a.CreatedTags, t)
+	return t, nil
+}
+
+// UpdateTagVersion records the tag updated.
+func (a *ArtifactRegistryStub) UpdateTagVersion(ctx context.Context, name, version string) (*artifactregistry.Tag, error) {
+	t := &artifactregistry.Tag{Name: name, Version: version}
+	a.UpdatedTags = append(a.UpdatedTags, t)
+	return t, nil
+}
+
+// DeleteTag records the tag deleted.
+func (a *ArtifactRegistryStub) DeleteTag(ctx context.Context, name string) error {
+	a.DeletedTags = append(a.DeletedTags, name)
+	return nil
+}
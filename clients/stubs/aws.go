@@ -0,0 +1,76 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// AWSStub provides a stub for the AWS client.
+type AWSStub struct {
+	// SavedAccessKey is the ID of the access key whose status was updated, to SavedAccessKeyStatus.
+	SavedAccessKey       string
+	SavedAccessKeyStatus string
+	StubbedInstance      *ec2.Instance
+	// StubbedSecurityGroups are the security groups found by name.
+	StubbedSecurityGroups []*ec2.SecurityGroup
+	// CreatedSecurityGroup is the name of the security group created, whose ID is "sg-created".
+	CreatedSecurityGroup string
+	// RevokedGroup is the security group whose egress rules were revoked.
+	RevokedGroup       string
+	RevokedPermissions []*ec2.IpPermission
+	// SavedInstanceGroups are the security groups the instance was moved to.
+	SavedInstanceGroups []string
+}
+
+// UpdateAccessKey records the status set on the access key.
+func (a *AWSStub) UpdateAccessKey(ctx context.Context, userName, accessKeyID, status string) error {
+	a.SavedAccessKey = accessKeyID
+	a.SavedAccessKeyStatus = status
+	return nil
+}
+
+// DescribeInstance returns the stubbed instance.
+func (a *AWSStub) DescribeInstance(ctx context.Context, region, instanceID string) (*ec2.Instance, error) {
+	return a.StubbedInstance, nil
+}
+
+// SecurityGroups returns the stubbed security groups.
+func (a *AWSStub) SecurityGroups(ctx context.Context, region, vpcID, name string) ([]*ec2.SecurityGroup, error) {
+	return a.StubbedSecurityGroups, nil
+}
+
+// CreateSecurityGroup records the security group created.
+func (a *AWSStub) CreateSecurityGroup(ctx context.Context, region, vpcID, name, description string) (string, error) {
+	a.CreatedSecurityGroup = name
+	a.StubbedSecurityGroups = append(a.StubbedSecurityGroups, &ec2.SecurityGroup{GroupId: aws.String("sg-created"), GroupName: aws.String(name), VpcId: aws.String(vpcID)})
+	return "sg-created", nil
+}
+
+// RevokeSecurityGroupEgress records the egress rules revoked.
+func (a *AWSStub) RevokeSecurityGroupEgress(ctx context.Context, region, groupID string, permissions []*ec2.IpPermission) error {
+	a.RevokedGroup = groupID
+	a.RevokedPermissions = permissions
+	return nil
+}
+
+// ModifyInstanceSecurityGroups records the security groups of the instance.
+func (a *AWSStub) ModifyInstanceSecurityGroups(ctx context.Context, region, instanceID string, groupIDs []string) error {
+	a.SavedInstanceGroups = groupIDs
+	return nil
+}
@@ -0,0 +1,38 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import "context"
+
+// AzureStub provides a stub for the Azure client.
+type AzureStub struct {
+	// StubbedPublicAccess is the public access level of blob containers.
+	StubbedPublicAccess string
+	// SavedContainer is the blob container whose public access was set, to SavedPublicAccess.
+	SavedContainer    string
+	SavedPublicAccess string
+}
+
+// ContainerPublicAccess returns the stubbed public access level.
+func (a *AzureStub) ContainerPublicAccess(ctx context.Context, subscriptionID, resourceGroup, account, container string) (string, error) {
+	return a.StubbedPublicAccess, nil
+}
+
+// SetContainerPublicAccess records the public access level set on the container.
+func (a *AzureStub) SetContainerPublicAccess(ctx context.Context, subscriptionID, resourceGroup, account, container, access string) error {
+	a.SavedContainer = container
+	a.SavedPublicAccess = access
+	return nil
+}
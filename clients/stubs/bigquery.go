@@ -24,6 +24,9 @@ import (
 type BigQueryStub struct {
 	StubbedMetadata      *bigquery.DatasetMetadata
 	SavedDatasetMetadata *bigquery.DatasetMetadataToUpdate
+	SavedRows            []interface{}
+	// StubbedInsertErr is returned by Insert if set.
+	StubbedInsertErr error
 }
 
 // DatasetMetadata fetches the metadata for the dataset.
@@ -36,3 +39,12 @@ func (s *BigQueryStub) OverwriteDatasetMetadata(ctx context.Context, projectID,
 	s.SavedDatasetMetadata = &dm
 	return nil, nil
 }
+
+// Insert records the rows inserted.
+func (s *BigQueryStub) Insert(ctx context.Context, projectID, datasetID, tableID string, src interface{}) error {
+	if s.StubbedInsertErr != nil {
+		return s.StubbedInsertErr
+	}
+	s.SavedRows = append(s.SavedRows, src)
+	return nil
+}
@@ -0,0 +1,40 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	cloudbilling "google.golang.org/api/cloudbilling/v1"
+)
+
+// BillingStub provides a stub for the Cloud Billing client.
+type BillingStub struct {
+	StubbedBillingInfo *cloudbilling.ProjectBillingInfo
+	SavedBillingInfo   *cloudbilling.ProjectBillingInfo
+	SavedProject       string
+}
+
+// GetBillingInfo returns the stubbed billing information.
+func (s *BillingStub) GetBillingInfo(ctx context.Context, name string) (*cloudbilling.ProjectBillingInfo, error) {
+	return s.StubbedBillingInfo, nil
+}
+
+// UpdateBillingInfo records the billing information set on the project.
+func (s *BillingStub) UpdateBillingInfo(ctx context.Context, name string, info *cloudbilling.ProjectBillingInfo) (*cloudbilling.ProjectBillingInfo, error) {
+	s.SavedProject = name
+	s.SavedBillingInfo = info
+	return info, nil
+}
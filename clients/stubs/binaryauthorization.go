@@ -0,0 +1,38 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	binaryauthorization "google.golang.org/api/binaryauthorization/v1"
+)
+
+// BinaryAuthorizationStub provides a stub for the Binary Authorization client.
+type BinaryAuthorizationStub struct {
+	StubbedPolicy *binaryauthorization.Policy
+	SavedPolicy   *binaryauthorization.Policy
+}
+
+// GetPolicy returns the stubbed policy.
+func (b *BinaryAuthorizationStub) GetPolicy(ctx context.Context, name string) (*binaryauthorization.Policy, error) {
+	return b.StubbedPolicy, nil
+}
+
+// UpdatePolicy records the policy set.
+func (b *BinaryAuthorizationStub) UpdatePolicy(ctx context.Context, name string, policy *binaryauthorization.Policy) (*binaryauthorization.Policy, error) {
+	b.SavedPolicy = policy
+	return policy, nil
+}
@@ -0,0 +1,41 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ChronicleStub provides a stub for the Chronicle client.
+type ChronicleStub struct {
+	// SavedEvents are the UDM events sent, keyed by customer ID.
+	SavedEvents map[string][]string
+	StubbedErr  error
+}
+
+// CreateUDMEvents records the events.
+func (s *ChronicleStub) CreateUDMEvents(ctx context.Context, customerID string, events ...json.RawMessage) error {
+	if s.StubbedErr != nil {
+		return s.StubbedErr
+	}
+	if s.SavedEvents == nil {
+		s.SavedEvents = map[string][]string{}
+	}
+	for _, e := range events {
+		s.SavedEvents[customerID] = append(s.SavedEvents[customerID], string(e))
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	cloudasset "google.golang.org/api/cloudasset/v1"
+)
+
+// CloudAssetStub provides a stub for the Cloud Asset Inventory client.
+type CloudAssetStub struct {
+	StubbedResults []*cloudasset.ResourceSearchResult
+	StubbedErr     error
+	SavedScope     string
+	SavedQuery     string
+}
+
+// SearchAllResources returns the stubbed results.
+func (c *CloudAssetStub) SearchAllResources(ctx context.Context, scope, query string) ([]*cloudasset.ResourceSearchResult, error) {
+	c.SavedScope = scope
+	c.SavedQuery = query
+	return c.StubbedResults, c.StubbedErr
+}
@@ -0,0 +1,70 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"net/http"
+
+	ci "google.golang.org/api/cloudidentity/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// CloudIdentityStub provides a stub for the Cloud Identity client.
+type CloudIdentityStub struct {
+	// StubbedGroups are the groups found, keyed by email.
+	StubbedGroups map[string]*ci.Group
+	// StubbedMembers are the member emails, keyed by group name.
+	StubbedMembers map[string][]string
+	// StubbedErr is returned by every call if set.
+	StubbedErr error
+}
+
+// LookupGroup returns the name of the stubbed group.
+func (c *CloudIdentityStub) LookupGroup(ctx context.Context, email string) (string, error) {
+	if c.StubbedErr != nil {
+		return "", c.StubbedErr
+	}
+	g, ok := c.StubbedGroups[email]
+	if !ok {
+		return "", &googleapi.Error{Code: http.StatusForbidden}
+	}
+	return g.Name, nil
+}
+
+// GetGroup returns the stubbed group.
+func (c *CloudIdentityStub) GetGroup(ctx context.Context, name string) (*ci.Group, error) {
+	if c.StubbedErr != nil {
+		return nil, c.StubbedErr
+	}
+	for _, g := range c.StubbedGroups {
+		if g.Name == name {
+			return g, nil
+		}
+	}
+	return nil, &googleapi.Error{Code: http.StatusNotFound}
+}
+
+// LookupMembership returns the name of the stubbed membership.
+func (c *CloudIdentityStub) LookupMembership(ctx context.Context, group, email string) (string, error) {
+	if c.StubbedErr != nil {
+		return "", c.StubbedErr
+	}
+	for _, m := range c.StubbedMembers[group] {
+		if m == email {
+			return group + "/memberships/" + email, nil
+		}
+	}
+	return "", &googleapi.Error{Code: http.StatusNotFound}
+}
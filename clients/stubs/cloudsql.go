@@ -25,6 +25,8 @@ type CloudSQL struct {
 	SavedInstanceUpdated    *sql.DatabaseInstance
 	InstanceDetailsResponse *sql.DatabaseInstance
 	UpdatedUser             *sql.User
+	UpdatedUserName         string
+	UpdatedUserHost         string
 }
 
 // WaitSQL waits globally.
@@ -41,6 +43,8 @@ func (s *CloudSQL) PatchInstance(ctx context.Context, projectID, instance string
 // UpdateUser updates a given user.
 func (s *CloudSQL) UpdateUser(ctx context.Context, projectID, instance, host, name string, user *sql.User) (*sql.Operation, error) {
 	s.UpdatedUser = user
+	s.UpdatedUserName = name
+	s.UpdatedUserHost = host
 	return &sql.Operation{}, nil
 }
 
@@ -0,0 +1,37 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	cloudtrace "google.golang.org/api/cloudtrace/v2"
+)
+
+// CloudTraceStub provides a stub for the Cloud Trace client.
+type CloudTraceStub struct {
+	WrittenSpans []*cloudtrace.Span
+	// StubbedErr is returned by BatchWriteSpans if set.
+	StubbedErr error
+}
+
+// BatchWriteSpans records the spans.
+func (c *CloudTraceStub) BatchWriteSpans(ctx context.Context, projectID string, spans []*cloudtrace.Span) error {
+	if c.StubbedErr != nil {
+		return c.StubbedErr
+	}
+	c.WrittenSpans = append(c.WrittenSpans, spans...)
+	return nil
+}
@@ -17,6 +17,7 @@ package stubs
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	sccpb "google.golang.org/genproto/googleapis/cloud/securitycenter/v1beta1"
 )
@@ -27,6 +28,13 @@ var ErrEntityNonExistent = fmt.Errorf("rpc error: code = NotFound desc = Request
 // SecurityCommandCenterStub provides a stub for the Security Command center client.
 type SecurityCommandCenterStub struct {
 	GetUpdateSecurityMarksRequest *sccpb.UpdateSecurityMarksRequest
+	GetSetFindingStateRequest     *sccpb.SetFindingStateRequest
+	StubbedSetFindingStateErr     error
+	GetListAssetsRequest          *sccpb.ListAssetsRequest
+	StubbedAssets                 []*sccpb.ListAssetsResponse_ListAssetsResult
+	StubbedListAssetsErr          error
+	// StubbedFindings are returned by ListFindings if their name is in the request's filter.
+	StubbedFindings []*sccpb.Finding
 }
 
 // AddSecurityMarks adds Security Marks to a finding or asset.
@@ -40,5 +48,29 @@ func (s *SecurityCommandCenterStub) AddSecurityMarks(ctx context.Context, reques
 
 // SetFindingState sets finding state
 func (s *SecurityCommandCenterStub) SetFindingState(ctx context.Context, request *sccpb.SetFindingStateRequest) (*sccpb.Finding, error) {
-	return &sccpb.Finding{}, nil
+	s.GetSetFindingStateRequest = request
+	if s.StubbedSetFindingStateErr != nil {
+		return nil, s.StubbedSetFindingStateErr
+	}
+	return &sccpb.Finding{Name: request.GetName(), State: request.GetState()}, nil
+}
+
+// ListAssets returns the stubbed assets.
+func (s *SecurityCommandCenterStub) ListAssets(ctx context.Context, request *sccpb.ListAssetsRequest) ([]*sccpb.ListAssetsResponse_ListAssetsResult, error) {
+	s.GetListAssetsRequest = request
+	if s.StubbedListAssetsErr != nil {
+		return nil, s.StubbedListAssetsErr
+	}
+	return s.StubbedAssets, nil
+}
+
+// ListFindings returns the stubbed findings named in the request's filter.
+func (s *SecurityCommandCenterStub) ListFindings(ctx context.Context, request *sccpb.ListFindingsRequest) ([]*sccpb.Finding, error) {
+	findings := []*sccpb.Finding{}
+	for _, f := range s.StubbedFindings {
+		if strings.Contains(request.GetFilter(), f.GetName()) {
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
 }
@@ -20,6 +20,7 @@ import (
 
 	"github.com/pkg/errors"
 	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
 )
 
 // ErrNonexistentVM is a stub error returned simulating an error in case of VM not found.
@@ -35,11 +36,34 @@ type ComputeStub struct {
 	StubbedListProjectSnapshots  []*compute.SnapshotList
 	StubbedListDisks             *compute.DiskList
 	StubbedFirewall              *compute.Firewall
+	StubbedFirewallRules         map[string]*compute.Firewall
+	StubbedFirewalls             *compute.FirewallList
+	FirewallRuleNotFound         bool
+	DeletedFirewallRules         []string
 	StubbedStopInstance          *compute.Operation
 	StubbedStartInstance         *compute.Operation
 	StubbedInstance              *compute.Instance
 	SavedDiskInsertDst           string
 	DiskInsertCalled             bool
+	SavedSnapshotLabels          map[string]string
+	SavedStoppedInstance         string
+	SavedPrivateGoogleAccess     map[string]bool
+	StubbedProject               *compute.Project
+	SavedInstanceMetadata        *compute.Metadata
+	SavedInstanceTags            *compute.Tags
+	InsertedFirewallRules        []*compute.Firewall
+	SavedProjectMetadata         *compute.Metadata
+	StubbedSubnetwork            *compute.Subnetwork
+	SavedSubnetworkPatch         *compute.Subnetwork
+	StubbedForwardingRules       *compute.ForwardingRuleList
+	DeletedForwardingRules       []string
+	StubbedTargetHTTPProxies     map[string]*compute.TargetHttpProxy
+	StubbedTargetHTTPSProxies    map[string]*compute.TargetHttpsProxy
+	StubbedURLMaps               map[string]*compute.UrlMap
+	StubbedBackendService        *compute.BackendService
+	SavedBackendServicePatch     *compute.BackendService
+	SavedStartedInstance         string
+	SavedShieldedInstanceConfig  *compute.ShieldedInstanceConfig
 }
 
 // DiskInsert creates a new disk in the project.
@@ -58,6 +82,7 @@ type NetworkAccessConfigStub struct {
 // InsertFirewallRule inserts a new firewall rule.
 func (c *ComputeStub) InsertFirewallRule(ctx context.Context, projectID string, fw *compute.Firewall) (*compute.Operation, error) {
 	c.SavedFirewallRule = fw
+	c.InsertedFirewallRules = append(c.InsertedFirewallRules, fw)
 	return nil, nil
 }
 
@@ -69,14 +94,82 @@ func (c *ComputeStub) PatchFirewallRule(ctx context.Context, projectID string, r
 
 // DeleteFirewallRule deletes the firewall rule for the given project.
 func (c *ComputeStub) DeleteFirewallRule(ctx context.Context, projectID string, rule string) (*compute.Operation, error) {
+	c.DeletedFirewallRules = append(c.DeletedFirewallRules, rule)
 	return nil, nil
 }
 
 // FirewallRule get the details of a firewall rule
 func (c *ComputeStub) FirewallRule(ctx context.Context, projectID string, ruleID string) (*compute.Firewall, error) {
+	if c.FirewallRuleNotFound {
+		return nil, &googleapi.Error{Code: 404}
+	}
+	if c.StubbedFirewallRules != nil {
+		r, ok := c.StubbedFirewallRules[ruleID]
+		if !ok {
+			return nil, &googleapi.Error{Code: 404}
+		}
+		return r, nil
+	}
 	return c.StubbedFirewall, nil
 }
 
+// ListFirewallRules returns the stubbed firewall rules.
+func (c *ComputeStub) ListFirewallRules(ctx context.Context, projectID string) (*compute.FirewallList, error) {
+	if c.StubbedFirewalls == nil {
+		return &compute.FirewallList{}, nil
+	}
+	return c.StubbedFirewalls, nil
+}
+
+// ListGlobalForwardingRules returns the stubbed forwarding rules.
+func (c *ComputeStub) ListGlobalForwardingRules(ctx context.Context, projectID string) (*compute.ForwardingRuleList, error) {
+	if c.StubbedForwardingRules == nil {
+		return &compute.ForwardingRuleList{}, nil
+	}
+	return c.StubbedForwardingRules, nil
+}
+
+// DeleteGlobalForwardingRule records the forwarding rule deleted.
+func (c *ComputeStub) DeleteGlobalForwardingRule(ctx context.Context, projectID, rule string) (*compute.Operation, error) {
+	c.DeletedForwardingRules = append(c.DeletedForwardingRules, rule)
+	return &compute.Operation{}, nil
+}
+
+// TargetHTTPProxy returns the stubbed target HTTP proxy.
+func (c *ComputeStub) TargetHTTPProxy(ctx context.Context, projectID, proxy string) (*compute.TargetHttpProxy, error) {
+	if p, ok := c.StubbedTargetHTTPProxies[proxy]; ok {
+		return p, nil
+	}
+	return nil, &googleapi.Error{Code: 404}
+}
+
+// TargetHTTPSProxy returns the stubbed target HTTPS proxy.
+func (c *ComputeStub) TargetHTTPSProxy(ctx context.Context, projectID, proxy string) (*compute.TargetHttpsProxy, error) {
+	if p, ok := c.StubbedTargetHTTPSProxies[proxy]; ok {
+		return p, nil
+	}
+	return nil, &googleapi.Error{Code: 404}
+}
+
+// URLMap returns the stubbed URL map.
+func (c *ComputeStub) URLMap(ctx context.Context, projectID, urlMap string) (*compute.UrlMap, error) {
+	if m, ok := c.StubbedURLMaps[urlMap]; ok {
+		return m, nil
+	}
+	return nil, &googleapi.Error{Code: 404}
+}
+
+// BackendService returns the stubbed backend service.
+func (c *ComputeStub) BackendService(ctx context.Context, projectID, service string) (*compute.BackendService, error) {
+	return c.StubbedBackendService, nil
+}
+
+// PatchBackendService records the backend service patch.
+func (c *ComputeStub) PatchBackendService(ctx context.Context, projectID, service string, bs *compute.BackendService) (*compute.Operation, error) {
+	c.SavedBackendServicePatch = bs
+	return &compute.Operation{}, nil
+}
+
 // GetInstance returns the specified compute instance resource.
 func (c *ComputeStub) GetInstance(ctx context.Context, project, zone, instance string) (*compute.Instance, error) {
 	if c.GetInstanceShouldFail {
@@ -85,6 +178,29 @@ func (c *ComputeStub) GetInstance(ctx context.Context, project, zone, instance s
 	return c.StubbedInstance, nil
 }
 
+// SetInstanceMetadata records the metadata set on the instance.
+func (c *ComputeStub) SetInstanceMetadata(ctx context.Context, project, zone, instance string, metadata *compute.Metadata) (*compute.Operation, error) {
+	c.SavedInstanceMetadata = metadata
+	return &compute.Operation{}, nil
+}
+
+// SetInstanceTags records the network tags set on the instance.
+func (c *ComputeStub) SetInstanceTags(ctx context.Context, project, zone, instance string, tags *compute.Tags) (*compute.Operation, error) {
+	c.SavedInstanceTags = tags
+	return &compute.Operation{}, nil
+}
+
+// GetProject returns the stubbed compute project resource.
+func (c *ComputeStub) GetProject(ctx context.Context, project string) (*compute.Project, error) {
+	return c.StubbedProject, nil
+}
+
+// SetCommonInstanceMetadata records the project-wide metadata.
+func (c *ComputeStub) SetCommonInstanceMetadata(ctx context.Context, project string, metadata *compute.Metadata) (*compute.Operation, error) {
+	c.SavedProjectMetadata = metadata
+	return &compute.Operation{}, nil
+}
+
 // DeleteAccessConfig deletes an access config from an instance's network interface.
 func (c *ComputeStub) DeleteAccessConfig(ctx context.Context, project, zone, instance, accessConfig, networkInterface string) (*compute.Operation, error) {
 	if c.DeleteAccessConfigShouldFail {
@@ -127,10 +243,25 @@ func (c *ComputeStub) ListDisks(ctx context.Context, _, _ string) (*compute.Disk
 }
 
 // SetLabels sets the labels on a snapshot.
-func (c *ComputeStub) SetLabels(context.Context, string, string, *compute.GlobalSetLabelsRequest) (*compute.Operation, error) {
+func (c *ComputeStub) SetLabels(_ context.Context, _, _ string, req *compute.GlobalSetLabelsRequest) (*compute.Operation, error) {
+	c.SavedSnapshotLabels = req.Labels
 	return nil, nil
 }
 
+// WaitRegion waits at the region level.
+func (c *ComputeStub) WaitRegion(_, _ string, _ *compute.Operation) []error {
+	return []error{}
+}
+
+// SetPrivateIPGoogleAccess records the Private Google Access setting of the subnetwork.
+func (c *ComputeStub) SetPrivateIPGoogleAccess(ctx context.Context, projectID, region, subnetwork string, enabled bool) (*compute.Operation, error) {
+	if c.SavedPrivateGoogleAccess == nil {
+		c.SavedPrivateGoogleAccess = make(map[string]bool)
+	}
+	c.SavedPrivateGoogleAccess[subnetwork] = enabled
+	return &compute.Operation{}, nil
+}
+
 // WaitGlobal waits globally.
 func (c *ComputeStub) WaitGlobal(_ string, _ *compute.Operation) []error {
 	return []error{}
@@ -143,15 +274,34 @@ func (c *ComputeStub) WaitZone(_, _ string, _ *compute.Operation) []error {
 
 // StopInstance stops an instance.
 func (c *ComputeStub) StopInstance(ctx context.Context, projectID, zone, instance string) (*compute.Operation, error) {
+	c.SavedStoppedInstance = instance
 	return c.StubbedStopInstance, nil
 }
 
 // StartInstance starts a given instance in given zone.
 func (c *ComputeStub) StartInstance(ctx context.Context, projectID, zone, instance string) (*compute.Operation, error) {
+	c.SavedStartedInstance = instance
 	return c.StubbedStartInstance, nil
 }
 
+// UpdateShieldedInstanceConfig records the Shielded VM options set on the instance.
+func (c *ComputeStub) UpdateShieldedInstanceConfig(ctx context.Context, projectID, zone, instance string, config *compute.ShieldedInstanceConfig) (*compute.Operation, error) {
+	c.SavedShieldedInstanceConfig = config
+	return &compute.Operation{}, nil
+}
+
 // DeleteInstance starts a given instance in given zone.
 func (c *ComputeStub) DeleteInstance(ctx context.Context, projectID, zone, instance string) (*compute.Operation, error) {
 	return nil, nil
 }
+
+// Subnetwork returns the stubbed subnetwork.
+func (c *ComputeStub) Subnetwork(ctx context.Context, projectID, region, subnetwork string) (*compute.Subnetwork, error) {
+	return c.StubbedSubnetwork, nil
+}
+
+// PatchSubnetwork records the patch applied to the subnetwork.
+func (c *ComputeStub) PatchSubnetwork(ctx context.Context, projectID, region, subnetwork string, sn *compute.Subnetwork) (*compute.Operation, error) {
+	c.SavedSubnetworkPatch = sn
+	return &compute.Operation{}, nil
+}
@@ -23,6 +23,8 @@ import (
 // ContainerStub provides a stub for the Container client.
 type ContainerStub struct {
 	UpdatedAddonsConfig *container.SetAddonsConfigRequest
+	UpdatedCluster      *container.UpdateClusterRequest
+	StubbedCluster      *container.Cluster
 }
 
 // UpdateAddonsConfig updates the addons configuration of a given cluster.
@@ -30,3 +32,14 @@ func (c *ContainerStub) UpdateAddonsConfig(ctx context.Context, projectID, zone,
 	c.UpdatedAddonsConfig = conf
 	return &container.Operation{}, nil
 }
+
+// UpdateCluster updates the settings of a given cluster.
+func (c *ContainerStub) UpdateCluster(ctx context.Context, projectID, zone, clusterID string, req *container.UpdateClusterRequest) (*container.Operation, error) {
+	c.UpdatedCluster = req
+	return &container.Operation{}, nil
+}
+
+// GetCluster returns the details of a given cluster.
+func (c *ContainerStub) GetCluster(ctx context.Context, projectID, zone, clusterID string) (*container.Cluster, error) {
+	return c.StubbedCluster, nil
+}
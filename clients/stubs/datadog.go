@@ -0,0 +1,57 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"time"
+)
+
+// DatadogStub provides a stub for the Datadog client.
+type DatadogStub struct {
+	// SavedEvents are the titles of the events posted, keyed by alert type.
+	SavedEvents map[string][]string
+	// SavedMetrics are the values submitted and SavedTags the tags of the last point, keyed by
+	// metric.
+	SavedMetrics map[string][]float64
+	SavedTags    map[string][]string
+	StubbedErr   error
+}
+
+// PostEvent records the event.
+func (s *DatadogStub) PostEvent(ctx context.Context, title, text, alertType, aggregationKey string, tags []string, t time.Time) error {
+	if s.StubbedErr != nil {
+		return s.StubbedErr
+	}
+	if s.SavedEvents == nil {
+		s.SavedEvents = map[string][]string{}
+	}
+	s.SavedEvents[alertType] = append(s.SavedEvents[alertType], title)
+	return nil
+}
+
+// SubmitMetric records the point of the metric.
+func (s *DatadogStub) SubmitMetric(ctx context.Context, metric, metricType string, value float64, tags []string, t time.Time) error {
+	if s.StubbedErr != nil {
+		return s.StubbedErr
+	}
+	if s.SavedMetrics == nil {
+		s.SavedMetrics = map[string][]float64{}
+		s.SavedTags = map[string][]string{}
+	}
+	s.SavedMetrics[metric] = append(s.SavedMetrics[metric], value)
+	s.SavedTags[metric] = tags
+	return nil
+}
@@ -0,0 +1,32 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	dataflow "google.golang.org/api/dataflow/v1b3"
+)
+
+// DataflowStub provides a stub for the Dataflow client.
+type DataflowStub struct {
+	UpdatedJobs []*dataflow.Job
+}
+
+// UpdateJob records the job updated.
+func (d *DataflowStub) UpdateJob(ctx context.Context, projectID, location, jobID string, job *dataflow.Job) (*dataflow.Job, error) {
+	d.UpdatedJobs = append(d.UpdatedJobs, job)
+	return job, nil
+}
@@ -0,0 +1,39 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	dataproc "google.golang.org/api/dataproc/v1"
+)
+
+// DataprocStub provides a stub for the Dataproc client.
+type DataprocStub struct {
+	CancelledJobs   []string
+	DeletedClusters []string
+}
+
+// CancelJob records the job cancelled.
+func (d *DataprocStub) CancelJob(ctx context.Context, projectID, region, jobID string) (*dataproc.Job, error) {
+	d.CancelledJobs = append(d.CancelledJobs, jobID)
+	return &dataproc.Job{}, nil
+}
+
+// DeleteCluster records the cluster deleted.
+func (d *DataprocStub) DeleteCluster(ctx context.Context, projectID, region, cluster string) (*dataproc.Operation, error) {
+	d.DeletedClusters = append(d.DeletedClusters, cluster)
+	return &dataproc.Operation{}, nil
+}
@@ -0,0 +1,37 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+
+	dns "google.golang.org/api/dns/v1"
+)
+
+// DNSStub provides a stub for the Cloud DNS client.
+type DNSStub struct {
+	StubbedManagedZone *dns.ManagedZone
+	SavedPatch         *dns.ManagedZone
+}
+
+// GetManagedZone returns the stubbed managed zone.
+func (d *DNSStub) GetManagedZone(ctx context.Context, projectID, zone string) (*dns.ManagedZone, error) {
+	return d.StubbedManagedZone, nil
+}
+
+// PatchManagedZone records the patch applied to the managed zone.
+func (d *DNSStub) PatchManagedZone(ctx context.Context, projectID, zone string, mz *dns.ManagedZone) (*dns.Operation, error) {
+	d.SavedPatch = mz
+	return &dns.Operation{}, nil
+}
@@ -0,0 +1,104 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	firestore "google.golang.org/api/firestore/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// FirestoreStub provides a stub for the Firestore client.
+type FirestoreStub struct {
+	SavedDocuments []map[string]firestore.Value
+	// StubbedDocuments are keyed by collection and document ID, such as "automation-undo/123".
+	// Documents created with an ID are added.
+	StubbedDocuments map[string]*firestore.Document
+	// StubbedErr is returned by CreateDocument if set.
+	StubbedErr error
+}
+
+// CreateDocument records the document created, or returns a conflict error if a document with
+// the ID exists.
+func (f *FirestoreStub) CreateDocument(ctx context.Context, projectID, collection, documentID string, fields map[string]firestore.Value) error {
+	if f.StubbedErr != nil {
+		return f.StubbedErr
+	}
+	if _, ok := f.StubbedDocuments[collection+"/"+documentID]; ok && documentID != "" {
+		return &googleapi.Error{Code: http.StatusConflict}
+	}
+	f.SavedDocuments = append(f.SavedDocuments, fields)
+	if documentID != "" {
+		if f.StubbedDocuments == nil {
+			f.StubbedDocuments = map[string]*firestore.Document{}
+		}
+		f.StubbedDocuments[collection+"/"+documentID] = &firestore.Document{Name: collection + "/" + documentID, Fields: fields}
+	}
+	return nil
+}
+
+// GetDocument returns the stubbed document or a not found error.
+func (f *FirestoreStub) GetDocument(ctx context.Context, projectID, collection, documentID string) (*firestore.Document, error) {
+	d, ok := f.StubbedDocuments[collection+"/"+documentID]
+	if !ok {
+		return nil, &googleapi.Error{Code: http.StatusNotFound}
+	}
+	return d, nil
+}
+
+// UpdateDocument sets the fields of the stubbed document or returns a not found error.
+func (f *FirestoreStub) UpdateDocument(ctx context.Context, projectID, collection, documentID string, fields map[string]firestore.Value, updateTime string) error {
+	d, ok := f.StubbedDocuments[collection+"/"+documentID]
+	if !ok {
+		return &googleapi.Error{Code: http.StatusNotFound}
+	}
+	for k, v := range fields {
+		d.Fields[k] = v
+	}
+	return nil
+}
+
+// ListDocuments returns the stubbed documents of the collection, ordered by ID.
+func (f *FirestoreStub) ListDocuments(ctx context.Context, projectID, collection string) ([]*firestore.Document, error) {
+	keys := []string{}
+	for k := range f.StubbedDocuments {
+		if strings.HasPrefix(k, collection+"/") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	docs := []*firestore.Document{}
+	for _, k := range keys {
+		docs = append(docs, f.StubbedDocuments[k])
+	}
+	return docs, nil
+}
+
+// DeleteDocument removes the stubbed document, or returns a not found error or a failed
+// precondition error if updateTime is set and differs from the document's.
+func (f *FirestoreStub) DeleteDocument(ctx context.Context, projectID, collection, documentID, updateTime string) error {
+	d, ok := f.StubbedDocuments[collection+"/"+documentID]
+	if !ok {
+		return &googleapi.Error{Code: http.StatusNotFound}
+	}
+	if updateTime != "" && d.UpdateTime != updateTime {
+		return &googleapi.Error{Code: http.StatusBadRequest, Body: `{"error": {"code": 400, "status": "FAILED_PRECONDITION"}}`}
+	}
+	delete(f.StubbedDocuments, collection+"/"+documentID)
+	return nil
+}
@@ -0,0 +1,57 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	iam "google.golang.org/api/iam/v1"
+)
+
+// IAMStub provides a stub for the IAM client.
+type IAMStub struct {
+	StubbedKeys                 []*iam.ServiceAccountKey
+	DisabledKeys                []string
+	StubbedPolicies             map[string]*iam.Policy
+	SavedServiceAccountPolicies map[string]*iam.Policy
+}
+
+// GetServiceAccountPolicy returns the stubbed policy of the service account.
+func (i *IAMStub) GetServiceAccountPolicy(ctx context.Context, name string) (*iam.Policy, error) {
+	if p, ok := i.StubbedPolicies[name]; ok {
+		return p, nil
+	}
+	return &iam.Policy{}, nil
+}
+
+// SetServiceAccountPolicy records the policy set on the service account.
+func (i *IAMStub) SetServiceAccountPolicy(ctx context.Context, name string, policy *iam.Policy) (*iam.Policy, error) {
+	if i.SavedServiceAccountPolicies == nil {
+		i.SavedServiceAccountPolicies = map[string]*iam.Policy{}
+	}
+	i.SavedServiceAccountPolicies[name] = policy
+	return policy, nil
+}
+
+// ListServiceAccountKeys returns the stubbed keys.
+func (i *IAMStub) ListServiceAccountKeys(ctx context.Context, name string) ([]*iam.ServiceAccountKey, error) {
+	return i.StubbedKeys, nil
+}
+
+// DisableKey records the disabled key.
+func (i *IAMStub) DisableKey(ctx context.Context, name string) error {
+	i.DisabledKeys = append(i.DisabledKeys, name)
+	return nil
+}
@@ -0,0 +1,49 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+)
+
+// JiraStub provides a stub for the Jira client.
+type JiraStub struct {
+	// SavedIssues are the bodies of the created issues, SavedUpdates those of the edits keyed by issue.
+	SavedIssues  []string
+	SavedUpdates map[string][]string
+	StubbedErr   error
+}
+
+// CreateIssue records the issue and returns its position as key.
+func (s *JiraStub) CreateIssue(ctx context.Context, body []byte) (string, error) {
+	if s.StubbedErr != nil {
+		return "", s.StubbedErr
+	}
+	s.SavedIssues = append(s.SavedIssues, string(body))
+	return fmt.Sprintf("SEC-%d", len(s.SavedIssues)), nil
+}
+
+// UpdateIssue records the edit of the issue.
+func (s *JiraStub) UpdateIssue(ctx context.Context, key string, body []byte) error {
+	if s.StubbedErr != nil {
+		return s.StubbedErr
+	}
+	if s.SavedUpdates == nil {
+		s.SavedUpdates = map[string][]string{}
+	}
+	s.SavedUpdates[key] = append(s.SavedUpdates[key], string(body))
+	return nil
+}
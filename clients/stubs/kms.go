@@ -0,0 +1,62 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	kms "google.golang.org/api/cloudkms/v1"
+)
+
+// KMSStub provides a stub for the Cloud KMS client.
+type KMSStub struct {
+	StubbedPolicy *kms.Policy
+	SavedPolicy   *kms.Policy
+	// SavedResource is the name of the key ring or crypto key whose policy was set.
+	SavedResource  string
+	SavedKeyPatch  *kms.CryptoKey
+	SavedPatchMask string
+}
+
+// GetKeyRingPolicy returns the stubbed policy.
+func (k *KMSStub) GetKeyRingPolicy(ctx context.Context, name string) (*kms.Policy, error) {
+	return k.StubbedPolicy, nil
+}
+
+// SetKeyRingPolicy records the policy set on the key ring.
+func (k *KMSStub) SetKeyRingPolicy(ctx context.Context, name string, policy *kms.Policy) (*kms.Policy, error) {
+	k.SavedPolicy = policy
+	k.SavedResource = name
+	return policy, nil
+}
+
+// GetCryptoKeyPolicy returns the stubbed policy.
+func (k *KMSStub) GetCryptoKeyPolicy(ctx context.Context, name string) (*kms.Policy, error) {
+	return k.StubbedPolicy, nil
+}
+
+// SetCryptoKeyPolicy records the policy set on the crypto key.
+func (k *KMSStub) SetCryptoKeyPolicy(ctx context.Context, name string, policy *kms.Policy) (*kms.Policy, error) {
+	k.SavedPolicy = policy
+	k.SavedResource = name
+	return policy, nil
+}
+
+// PatchCryptoKey records the patch applied to the crypto key.
+func (k *KMSStub) PatchCryptoKey(ctx context.Context, name, updateMask string, key *kms.CryptoKey) (*kms.CryptoKey, error) {
+	k.SavedKeyPatch = key
+	k.SavedPatchMask = updateMask
+	return key, nil
+}
@@ -0,0 +1,74 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// KubernetesStub provides a stub for the Kubernetes client.
+type KubernetesStub struct {
+	// StubbedGet maps API paths to the objects returned for them.
+	StubbedGet map[string]interface{}
+	// StubbedPostErr is returned for posts to the matching API paths.
+	StubbedPostErr map[string]error
+	SavedPatches   map[string]interface{}
+	SavedPosts     map[string]interface{}
+	PostedPaths    []string
+	DeletedPaths   []string
+}
+
+// Get reads the stubbed object for the API path into out.
+func (k *KubernetesStub) Get(ctx context.Context, path string, out interface{}) error {
+	obj, ok := k.StubbedGet[path]
+	if !ok {
+		return fmt.Errorf("no stubbed object for %q", path)
+	}
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+// Patch records the patch applied to the API path.
+func (k *KubernetesStub) Patch(ctx context.Context, path string, patch interface{}) error {
+	if k.SavedPatches == nil {
+		k.SavedPatches = make(map[string]interface{})
+	}
+	k.SavedPatches[path] = patch
+	return nil
+}
+
+// Post records the body posted to the API path.
+func (k *KubernetesStub) Post(ctx context.Context, path string, body interface{}) error {
+	if err := k.StubbedPostErr[path]; err != nil {
+		return err
+	}
+	if k.SavedPosts == nil {
+		k.SavedPosts = make(map[string]interface{})
+	}
+	k.SavedPosts[path] = body
+	k.PostedPaths = append(k.PostedPaths, path)
+	return nil
+}
+
+// Delete records the deleted API path.
+func (k *KubernetesStub) Delete(ctx context.Context, path string) error {
+	k.DeletedPaths = append(k.DeletedPaths, path)
+	return nil
+}
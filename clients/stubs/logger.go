@@ -14,23 +14,29 @@ package stubs
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-import "log"
+import (
+	"encoding/json"
+)
 
-// LoggerStub provides a stub for the Logger client.
+// LoggerStub provides a stub for the output of the logger, pass it to logging.NewContext.
 type LoggerStub struct {
+	LastInfo string
+	// Entries holds the messages logged by severity.
+	Entries map[string][]string
 }
 
-// Info push info log to buffer.
-func (l *LoggerStub) Info(message string, a ...interface{}) { log.Printf(message, a...) }
-
-// Warning push warning log to buffer.
-func (l *LoggerStub) Warning(message string, a ...interface{}) { log.Printf(message, a...) }
-
-// Error push error log to buffer.
-func (l *LoggerStub) Error(message string, a ...interface{}) { log.Printf(message, a...) }
-
-// Debug push debug log to buffer.
-func (l *LoggerStub) Debug(message string, a ...interface{}) { log.Printf(message, a...) }
-
-// Close buffer and send messages to stackdriver.
-func (l *LoggerStub) Close() {}
+// Write records the message of the structured entry.
+func (l *LoggerStub) Write(p []byte) (int, error) {
+	var e struct{ Severity, Message string }
+	if err := json.Unmarshal(p, &e); err != nil {
+		return 0, err
+	}
+	if e.Severity == "INFO" {
+		l.LastInfo = e.Message
+	}
+	if l.Entries == nil {
+		l.Entries = map[string][]string{}
+	}
+	l.Entries[e.Severity] = append(l.Entries[e.Severity], e.Message)
+	return len(p), nil
+}
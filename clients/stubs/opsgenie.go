@@ -0,0 +1,52 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+)
+
+// OpsgenieStub provides a stub for the Opsgenie client.
+type OpsgenieStub struct {
+	// SavedAlerts are the priorities of the alerts created and ClosedAlerts the notes they were
+	// closed with, keyed by alias.
+	SavedAlerts  map[string]string
+	ClosedAlerts map[string]string
+	StubbedErr   error
+}
+
+// CreateAlert records the alert.
+func (s *OpsgenieStub) CreateAlert(ctx context.Context, alias, message, description, priority string, tags []string, details map[string]string) error {
+	if s.StubbedErr != nil {
+		return s.StubbedErr
+	}
+	if s.SavedAlerts == nil {
+		s.SavedAlerts = map[string]string{}
+	}
+	s.SavedAlerts[alias] = priority
+	return nil
+}
+
+// CloseAlert records the alert closed.
+func (s *OpsgenieStub) CloseAlert(ctx context.Context, alias, note string) error {
+	if s.StubbedErr != nil {
+		return s.StubbedErr
+	}
+	if s.ClosedAlerts == nil {
+		s.ClosedAlerts = map[string]string{}
+	}
+	s.ClosedAlerts[alias] = note
+	return nil
+}
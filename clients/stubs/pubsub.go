@@ -16,14 +16,18 @@ package stubs
 
 import (
 	"context"
+	"sync"
 
 	"cloud.google.com/go/pubsub"
 )
 
 // PubSubStub provides a stub for the PubSub client.
 type PubSubStub struct {
+	mu               sync.Mutex
 	StubbedTopic     *pubsub.Topic
 	PublishedMessage *pubsub.Message
+	// PublishedMessages are all messages published, in order.
+	PublishedMessages []*pubsub.Message
 }
 
 // Topic returns a reference to a topic.
@@ -33,6 +37,9 @@ func (p *PubSubStub) Topic(id string) *pubsub.Topic {
 
 // Publish will publish a message to a PubSub topic.
 func (p *PubSubStub) Publish(ctx context.Context, topic *pubsub.Topic, message *pubsub.Message) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	p.PublishedMessage = message
+	p.PublishedMessages = append(p.PublishedMessages, message)
 	return "", nil
 }
@@ -0,0 +1,53 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	pubsub "google.golang.org/api/pubsub/v1"
+)
+
+// PubSubAdminStub provides a stub for the Pub/Sub admin client.
+type PubSubAdminStub struct {
+	StubbedPolicy *pubsub.Policy
+	SavedPolicy   *pubsub.Policy
+	// SavedResource is the name of the topic or subscription whose policy was set.
+	SavedResource string
+}
+
+// GetTopicPolicy returns the stubbed policy.
+func (p *PubSubAdminStub) GetTopicPolicy(ctx context.Context, name string) (*pubsub.Policy, error) {
+	return p.StubbedPolicy, nil
+}
+
+// SetTopicPolicy records the policy set on the topic.
+func (p *PubSubAdminStub) SetTopicPolicy(ctx context.Context, name string, policy *pubsub.Policy) (*pubsub.Policy, error) {
+	p.SavedPolicy = policy
+	p.SavedResource = name
+	return policy, nil
+}
+
+// GetSubscriptionPolicy returns the stubbed policy.
+func (p *PubSubAdminStub) GetSubscriptionPolicy(ctx context.Context, name string) (*pubsub.Policy, error) {
+	return p.StubbedPolicy, nil
+}
+
+// SetSubscriptionPolicy records the policy set on the subscription.
+func (p *PubSubAdminStub) SetSubscriptionPolicy(ctx context.Context, name string, policy *pubsub.Policy) (*pubsub.Policy, error) {
+	p.SavedPolicy = policy
+	p.SavedResource = name
+	return policy, nil
+}
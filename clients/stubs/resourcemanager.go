@@ -17,8 +17,10 @@ package stubs
 
 import (
 	"context"
+	"fmt"
 
 	crm "google.golang.org/api/cloudresourcemanager/v1"
+	crmv2 "google.golang.org/api/cloudresourcemanager/v2"
 )
 
 // ResourceManagerStub provides a stub for the CRM client.
@@ -27,21 +29,39 @@ type ResourceManagerStub struct {
 	GetAncestryResponse     *crm.GetAncestryResponse
 	SavedSetPolicy          *crm.Policy
 	GetOrganizationResponse *crm.Organization
+	SavedOrgPolicy          *crm.OrgPolicy
+	// StubbedFolders are the folders found, keyed by resource name.
+	StubbedFolders map[string]*crmv2.Folder
+	// SetPolicyErrs are returned by the next calls setting a project's policy, one per call.
+	SetPolicyErrs []error
+	// GetPolicyCalls counts the calls getting a policy.
+	GetPolicyCalls int
 }
 
 // GetPolicyProject is a stub of Cloud Resource Manager's GetIamPolicy.
 func (s *ResourceManagerStub) GetPolicyProject(ctx context.Context, projectID string) (*crm.Policy, error) {
+	s.GetPolicyCalls++
 	return s.GetPolicyResponse, nil
 }
 
 // SetPolicyProject is a stub of Cloud Resource Manager's SetIamPolicy.
 func (s *ResourceManagerStub) SetPolicyProject(ctx context.Context, projectID string, p *crm.Policy) (*crm.Policy, error) {
+	if len(s.SetPolicyErrs) > 0 {
+		err := s.SetPolicyErrs[0]
+		s.SetPolicyErrs = s.SetPolicyErrs[1:]
+		return nil, err
+	}
 	s.SavedSetPolicy = p
 	return s.SavedSetPolicy, nil
 }
 
 // SetPolicyProjectWithMask is a stub of Cloud Resource Manager's SetIamPolicy.
 func (s *ResourceManagerStub) SetPolicyProjectWithMask(ctx context.Context, projectID string, p *crm.Policy, fields ...string) (*crm.Policy, error) {
+	if len(s.SetPolicyErrs) > 0 {
+		err := s.SetPolicyErrs[0]
+		s.SetPolicyErrs = s.SetPolicyErrs[1:]
+		return nil, err
+	}
 	s.SavedSetPolicy = p
 	return s.SavedSetPolicy, nil
 }
@@ -66,3 +86,29 @@ func (s *ResourceManagerStub) SetPolicyOrganization(ctx context.Context, organiz
 func (s *ResourceManagerStub) GetOrganization(ctx context.Context, organizationID string) (*crm.Organization, error) {
 	return s.GetOrganizationResponse, nil
 }
+
+// SetOrgPolicyProject is a stub of Cloud Resource Manager's SetOrgPolicy.
+func (s *ResourceManagerStub) SetOrgPolicyProject(ctx context.Context, projectID string, p *crm.OrgPolicy) (*crm.OrgPolicy, error) {
+	s.SavedOrgPolicy = p
+	return p, nil
+}
+
+// GetPolicyFolder is a stub of Cloud Resource Manager's GetIamPolicy.
+func (s *ResourceManagerStub) GetPolicyFolder(ctx context.Context, name string) (*crm.Policy, error) {
+	return s.GetPolicyResponse, nil
+}
+
+// SetPolicyFolder is a stub of Cloud Resource Manager's SetIamPolicy.
+func (s *ResourceManagerStub) SetPolicyFolder(ctx context.Context, name string, p *crm.Policy) (*crm.Policy, error) {
+	s.SavedSetPolicy = p
+	return s.SavedSetPolicy, nil
+}
+
+// GetFolder is a stub of Cloud Resource Manager's GetFolder.
+func (s *ResourceManagerStub) GetFolder(ctx context.Context, name string) (*crmv2.Folder, error) {
+	f, ok := s.StubbedFolders[name]
+	if !ok {
+		return nil, fmt.Errorf("folder %q not found", name)
+	}
+	return f, nil
+}
@@ -0,0 +1,31 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+)
+
+// RotationHookStub provides a stub for the rotation hook client.
+type RotationHookStub struct {
+	StubbedResponse []byte
+	SavedRequests   []string
+}
+
+// Call records the request and returns the stubbed response.
+func (r *RotationHookStub) Call(ctx context.Context, body []byte) ([]byte, error) {
+	r.SavedRequests = append(r.SavedRequests, string(body))
+	return r.StubbedResponse, nil
+}
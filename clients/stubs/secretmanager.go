@@ -0,0 +1,81 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/api/googleapi"
+	secretmanager "google.golang.org/api/secretmanager/v1"
+)
+
+// SecretManagerStub provides a stub for the Secret Manager client.
+type SecretManagerStub struct {
+	// SecretExists causes CreateSecret to return an already exists error.
+	SecretExists        bool
+	CreatedSecrets      []string
+	SavedSecretVersions map[string]string
+	StubbedSecrets      []*secretmanager.Secret
+	// StubbedVersions maps secret names to their versions.
+	StubbedVersions  map[string][]*secretmanager.SecretVersion
+	DisabledVersions []string
+	// StubbedPayloads maps secret version names to their base64 encoded payloads.
+	StubbedPayloads map[string]string
+}
+
+// CreateSecret creates a new secret.
+func (s *SecretManagerStub) CreateSecret(ctx context.Context, parent, secretID string, secret *secretmanager.Secret) (*secretmanager.Secret, error) {
+	if s.SecretExists {
+		return nil, &googleapi.Error{Code: http.StatusConflict}
+	}
+	name := parent + "/secrets/" + secretID
+	s.CreatedSecrets = append(s.CreatedSecrets, name)
+	return &secretmanager.Secret{Name: name}, nil
+}
+
+// AddSecretVersion adds a new version to an existing secret.
+func (s *SecretManagerStub) AddSecretVersion(ctx context.Context, secret string, req *secretmanager.AddSecretVersionRequest) (*secretmanager.SecretVersion, error) {
+	if s.SavedSecretVersions == nil {
+		s.SavedSecretVersions = make(map[string]string)
+	}
+	s.SavedSecretVersions[secret] = req.Payload.Data
+	return &secretmanager.SecretVersion{Name: secret + "/versions/1"}, nil
+}
+
+// ListSecrets returns the stubbed secrets.
+func (s *SecretManagerStub) ListSecrets(ctx context.Context, parent string) ([]*secretmanager.Secret, error) {
+	return s.StubbedSecrets, nil
+}
+
+// ListSecretVersions returns the stubbed versions of the secret.
+func (s *SecretManagerStub) ListSecretVersions(ctx context.Context, secret string) ([]*secretmanager.SecretVersion, error) {
+	return s.StubbedVersions[secret], nil
+}
+
+// AccessSecretVersion returns the stubbed payload of the version or a not found error.
+func (s *SecretManagerStub) AccessSecretVersion(ctx context.Context, name string) (*secretmanager.AccessSecretVersionResponse, error) {
+	data, ok := s.StubbedPayloads[name]
+	if !ok {
+		return nil, &googleapi.Error{Code: http.StatusNotFound}
+	}
+	return &secretmanager.AccessSecretVersionResponse{Name: name, Payload: &secretmanager.SecretPayload{Data: data}}, nil
+}
+
+// DisableSecretVersion records the version disabled.
+func (s *SecretManagerStub) DisableSecretVersion(ctx context.Context, name string) (*secretmanager.SecretVersion, error) {
+	s.DisabledVersions = append(s.DisabledVersions, name)
+	return &secretmanager.SecretVersion{Name: name, State: "DISABLED"}, nil
+}
@@ -0,0 +1,69 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	cloudfunctions "google.golang.org/api/cloudfunctions/v1"
+	run "google.golang.org/api/run/v1"
+)
+
+// CloudFunctionsStub provides a stub for the Cloud Functions client.
+type CloudFunctionsStub struct {
+	GetPolicyResponse *cloudfunctions.Policy
+	SavedSetPolicy    *cloudfunctions.Policy
+}
+
+// GetFunctionPolicy returns the IAM policy of a function.
+func (c *CloudFunctionsStub) GetFunctionPolicy(ctx context.Context, name string) (*cloudfunctions.Policy, error) {
+	return c.GetPolicyResponse, nil
+}
+
+// SetFunctionPolicy sets the IAM policy of a function.
+func (c *CloudFunctionsStub) SetFunctionPolicy(ctx context.Context, name string, policy *cloudfunctions.Policy) (*cloudfunctions.Policy, error) {
+	c.SavedSetPolicy = policy
+	return policy, nil
+}
+
+// CloudRunStub provides a stub for the Cloud Run client.
+type CloudRunStub struct {
+	GetPolicyResponse  *run.Policy
+	SavedSetPolicy     *run.Policy
+	GetServiceResponse *run.Service
+	SavedService       *run.Service
+}
+
+// GetServicePolicy returns the IAM policy of a service.
+func (c *CloudRunStub) GetServicePolicy(ctx context.Context, name string) (*run.Policy, error) {
+	return c.GetPolicyResponse, nil
+}
+
+// SetServicePolicy sets the IAM policy of a service.
+func (c *CloudRunStub) SetServicePolicy(ctx context.Context, name string, policy *run.Policy) (*run.Policy, error) {
+	c.SavedSetPolicy = policy
+	return policy, nil
+}
+
+// GetService returns a service.
+func (c *CloudRunStub) GetService(ctx context.Context, name string) (*run.Service, error) {
+	return c.GetServiceResponse, nil
+}
+
+// ReplaceService replaces a service.
+func (c *CloudRunStub) ReplaceService(ctx context.Context, name string, service *run.Service) (*run.Service, error) {
+	c.SavedService = service
+	return service, nil
+}
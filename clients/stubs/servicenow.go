@@ -0,0 +1,60 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+)
+
+// ServiceNowStub provides a stub for the ServiceNow client.
+type ServiceNowStub struct {
+	// StubbedIncidents are the system IDs of incidents keyed by correlation ID.
+	StubbedIncidents map[string]string
+	// SavedIncidents are the bodies of the created incidents, SavedUpdates those of the updates
+	// keyed by system ID.
+	SavedIncidents []string
+	SavedUpdates   map[string][]string
+	StubbedErr     error
+}
+
+// FindIncident returns the stubbed incident with the correlation ID.
+func (s *ServiceNowStub) FindIncident(ctx context.Context, correlationID string) (string, error) {
+	if s.StubbedErr != nil {
+		return "", s.StubbedErr
+	}
+	return s.StubbedIncidents[correlationID], nil
+}
+
+// CreateIncident records the incident and returns its position as system ID.
+func (s *ServiceNowStub) CreateIncident(ctx context.Context, body []byte) (string, error) {
+	if s.StubbedErr != nil {
+		return "", s.StubbedErr
+	}
+	s.SavedIncidents = append(s.SavedIncidents, string(body))
+	return fmt.Sprintf("sys-%d", len(s.SavedIncidents)), nil
+}
+
+// UpdateIncident records the update of the incident.
+func (s *ServiceNowStub) UpdateIncident(ctx context.Context, sysID string, body []byte) error {
+	if s.StubbedErr != nil {
+		return s.StubbedErr
+	}
+	if s.SavedUpdates == nil {
+		s.SavedUpdates = map[string][]string{}
+	}
+	s.SavedUpdates[sysID] = append(s.SavedUpdates[sysID], string(body))
+	return nil
+}
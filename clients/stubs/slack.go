@@ -0,0 +1,53 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+)
+
+// SlackStub provides a stub for the Slack client.
+type SlackStub struct {
+	SavedMessages []string
+	// SavedFiles are the names of the uploaded files keyed by the thread they were uploaded in.
+	SavedFiles map[string][]string
+	// StubbedEmails are the email addresses of Slack users, keyed by user ID.
+	StubbedEmails map[string]string
+}
+
+// PostMessage records the message and returns its position as timestamp.
+func (s *SlackStub) PostMessage(ctx context.Context, body []byte) (string, error) {
+	s.SavedMessages = append(s.SavedMessages, string(body))
+	return fmt.Sprintf("1591056000.%06d", len(s.SavedMessages)), nil
+}
+
+// UploadFile records the name of the file.
+func (s *SlackStub) UploadFile(ctx context.Context, channel, threadTS, filename, contentType string, content []byte) error {
+	if s.SavedFiles == nil {
+		s.SavedFiles = map[string][]string{}
+	}
+	s.SavedFiles[threadTS] = append(s.SavedFiles[threadTS], filename)
+	return nil
+}
+
+// UserEmail returns the stubbed email address of the user.
+func (s *SlackStub) UserEmail(ctx context.Context, userID string) (string, error) {
+	email, ok := s.StubbedEmails[userID]
+	if !ok {
+		return "", fmt.Errorf("user %q not found", userID)
+	}
+	return email, nil
+}
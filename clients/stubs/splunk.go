@@ -0,0 +1,39 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"time"
+)
+
+// SplunkStub provides a stub for the Splunk client.
+type SplunkStub struct {
+	// SavedEvents are the events sent, keyed by index and source type joined by a slash.
+	SavedEvents map[string][]string
+	StubbedErr  error
+}
+
+// SendEvent records the event.
+func (s *SplunkStub) SendEvent(ctx context.Context, index, sourceType, source string, t time.Time, event []byte) error {
+	if s.StubbedErr != nil {
+		return s.StubbedErr
+	}
+	if s.SavedEvents == nil {
+		s.SavedEvents = map[string][]string{}
+	}
+	s.SavedEvents[index+"/"+sourceType] = append(s.SavedEvents[index+"/"+sourceType], string(event))
+	return nil
+}
@@ -16,8 +16,12 @@ package stubs
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 
 	"cloud.google.com/go/iam"
+	"cloud.google.com/go/storage"
 )
 
 // StorageStub provides a stub for the Storage client.
@@ -25,6 +29,9 @@ type StorageStub struct {
 	BucketPolicyResponse  *iam.Policy
 	RemoveBucketPolicy    *iam.Policy
 	EnabledPolicyOnBucket string
+	BucketAttrsResponse   *storage.BucketAttrs
+	DeletedBucketACLs     []storage.ACLEntity
+	Objects               map[string][]byte
 }
 
 // SetBucketPolicy set a policy for the given bucket.
@@ -38,8 +45,54 @@ func (s *StorageStub) BucketPolicy(ctx context.Context, bucketName string) (*iam
 	return s.BucketPolicyResponse, nil
 }
 
-// EnableBucketOnlyPolicy saves the bucket that receives the request for enabling bucket only policy.
-func (s *StorageStub) EnableBucketOnlyPolicy(ctx context.Context, bucketName string) error {
-	s.EnabledPolicyOnBucket = bucketName
+// SetBucketPolicyOnly saves the bucket that receives the request for enabling bucket only policy.
+func (s *StorageStub) SetBucketPolicyOnly(ctx context.Context, bucketName string, enabled bool) error {
+	if enabled {
+		s.EnabledPolicyOnBucket = bucketName
+	}
 	return nil
 }
+
+// BucketAttrs returns the stubbed bucket attributes.
+func (s *StorageStub) BucketAttrs(ctx context.Context, bucketName string) (*storage.BucketAttrs, error) {
+	if s.BucketAttrsResponse == nil {
+		return &storage.BucketAttrs{Name: bucketName}, nil
+	}
+	return s.BucketAttrsResponse, nil
+}
+
+// DeleteBucketACL saves the entities removed from the bucket's ACL.
+func (s *StorageStub) DeleteBucketACL(ctx context.Context, bucketName string, entity storage.ACLEntity) error {
+	s.DeletedBucketACLs = append(s.DeletedBucketACLs, entity)
+	return nil
+}
+
+// WriteObject saves the object keyed by bucket and name.
+func (s *StorageStub) WriteObject(ctx context.Context, bucketName, name string, data []byte) error {
+	if s.Objects == nil {
+		s.Objects = make(map[string][]byte)
+	}
+	s.Objects[bucketName+"/"+name] = data
+	return nil
+}
+
+// ReadObject returns a saved object.
+func (s *StorageStub) ReadObject(ctx context.Context, bucketName, name string) ([]byte, error) {
+	b, ok := s.Objects[bucketName+"/"+name]
+	if !ok {
+		return nil, fmt.Errorf("object %q not found in bucket %q", name, bucketName)
+	}
+	return b, nil
+}
+
+// ListObjects returns the sorted names of saved objects starting with prefix.
+func (s *StorageStub) ListObjects(ctx context.Context, bucketName, prefix string) ([]string, error) {
+	names := []string{}
+	for k := range s.Objects {
+		if strings.HasPrefix(k, bucketName+"/"+prefix) {
+			names = append(names, strings.TrimPrefix(k, bucketName+"/"))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,55 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"sync"
+
+	pubsub "google.golang.org/api/pubsub/v1"
+)
+
+// SubscriberStub provides a stub for the Pub/Sub subscriber client.
+type SubscriberStub struct {
+	mu sync.Mutex
+	// StubbedMessages are pulled in order, at most the maximum at a time.
+	StubbedMessages []*pubsub.ReceivedMessage
+	// Acknowledged are the IDs of the messages acknowledged, in order.
+	Acknowledged []string
+}
+
+// Pull returns up to max of the stubbed messages not pulled yet.
+func (s *SubscriberStub) Pull(ctx context.Context, subscription string, max int64) ([]*pubsub.ReceivedMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.StubbedMessages)
+	if int64(n) > max {
+		n = int(max)
+	}
+	pulled := s.StubbedMessages[:n]
+	s.StubbedMessages = s.StubbedMessages[n:]
+	return pulled, nil
+}
+
+// Acknowledge records the acknowledged messages, or fails if the context is done.
+func (s *SubscriberStub) Acknowledge(ctx context.Context, subscription string, ackIDs []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Acknowledged = append(s.Acknowledged, ackIDs...)
+	return nil
+}
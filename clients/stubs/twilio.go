@@ -0,0 +1,52 @@
+package stubs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+)
+
+// TwilioStub provides a stub for the Twilio client.
+type TwilioStub struct {
+	// SavedMessages and SavedCalls are the text messages and the TwiML of the calls, keyed by
+	// phone number.
+	SavedMessages map[string][]string
+	SavedCalls    map[string][]string
+	StubbedErr    error
+}
+
+// SendSMS records the text message.
+func (s *TwilioStub) SendSMS(ctx context.Context, from, to, body string) error {
+	if s.StubbedErr != nil {
+		return s.StubbedErr
+	}
+	if s.SavedMessages == nil {
+		s.SavedMessages = map[string][]string{}
+	}
+	s.SavedMessages[to] = append(s.SavedMessages[to], body)
+	return nil
+}
+
+// Call records the call.
+func (s *TwilioStub) Call(ctx context.Context, from, to, twiml string) error {
+	if s.StubbedErr != nil {
+		return s.StubbedErr
+	}
+	if s.SavedCalls == nil {
+		s.SavedCalls = map[string][]string{}
+	}
+	s.SavedCalls[to] = append(s.SavedCalls[to], twiml)
+	return nil
+}
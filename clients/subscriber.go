@@ -0,0 +1,51 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	pubsub "google.golang.org/api/pubsub/v1"
+)
+
+// Subscriber client pulls the messages of subscriptions.
+type Subscriber struct {
+	service *pubsub.Service
+}
+
+// NewSubscriber returns and initializes a Pub/Sub subscriber client.
+func NewSubscriber(ctx context.Context) (*Subscriber, error) {
+	p, err := pubsub.NewService(ctx, clientOptions(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init pubsub subscriber: %q", err)
+	}
+	return &Subscriber{service: p}, nil
+}
+
+// Pull returns up to max messages of the subscription.
+func (s *Subscriber) Pull(ctx context.Context, subscription string, max int64) ([]*pubsub.ReceivedMessage, error) {
+	r, err := s.service.Projects.Subscriptions.Pull(subscription, &pubsub.PullRequest{MaxMessages: max}).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return r.ReceivedMessages, nil
+}
+
+// Acknowledge acknowledges the messages of the subscription.
+func (s *Subscriber) Acknowledge(ctx context.Context, subscription string, ackIDs []string) error {
+	_, err := s.service.Projects.Subscriptions.Acknowledge(subscription, &pubsub.AcknowledgeRequest{AckIds: ackIDs}).Context(ctx).Do()
+	return err
+}
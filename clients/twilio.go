@@ -0,0 +1,69 @@
+package clients
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// twilioAPI is the base URL of the Twilio REST API.
+const twilioAPI = "https://api.twilio.com/2010-04-01/Accounts/"
+
+// Twilio client sends text messages and places calls with the Twilio REST API.
+type Twilio struct {
+	client     *http.Client
+	accountSID string
+	authToken  string
+}
+
+// NewTwilio returns and initializes a Twilio client for the account.
+func NewTwilio(accountSID, authToken string) *Twilio {
+	return &Twilio{client: &http.Client{Timeout: 30 * time.Second}, accountSID: accountSID, authToken: authToken}
+}
+
+// SendSMS sends the text message from the Twilio number to the phone number.
+func (t *Twilio) SendSMS(ctx context.Context, from, to, body string) error {
+	return t.post(ctx, "Messages.json", url.Values{"From": {from}, "To": {to}, "Body": {body}})
+}
+
+// Call calls the phone number from the Twilio number, playing the TwiML instructions.
+func (t *Twilio) Call(ctx context.Context, from, to, twiml string) error {
+	return t.post(ctx, "Calls.json", url.Values{"From": {from}, "To": {to}, "Twiml": {twiml}})
+}
+
+func (t *Twilio) post(ctx context.Context, resource string, form url.Values) error {
+	req, err := http.NewRequest(http.MethodPost, twilioAPI+url.PathEscape(t.accountSID)+"/"+resource, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(t.accountSID, t.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := t.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("twilio returned %d: %s", resp.StatusCode, b)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package restrictapikey
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// Values contains the required values needed for this function.
+type Values struct {
+	Action           string
+	ProjectID        string
+	KeyName          string
+	AllowedReferrers []string
+	AllowedIPs       []string
+	AllowedAPIs      []string
+	DryRun           bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	APIKeys *services.APIKeys
+}
+
+// Execute deletes or restricts an exposed API key.
+//
+// The "delete" action removes the key. The "restrict" action replaces the key's restrictions with
+// the configured referrers or IP addresses and APIs.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	switch values.Action {
+	case "delete":
+		if values.DryRun {
+			logging.FromContext(ctx).Info("dry_run on, would have deleted api key %q", values.KeyName)
+			return nil
+		}
+		if err := services.APIKeys.DeleteKey(ctx, values.KeyName); err != nil {
+			return err
+		}
+		logging.FromContext(ctx).Info("deleted api key %q in project %q", values.KeyName, values.ProjectID)
+	case "restrict":
+		if len(values.AllowedReferrers) == 0 && len(values.AllowedIPs) == 0 && len(values.AllowedAPIs) == 0 {
+			return fmt.Errorf("no restrictions configured for api key %q", values.KeyName)
+		}
+		if values.DryRun {
+			logging.FromContext(ctx).Info("dry_run on, would have restricted api key %q to referrers %q, ips %q and apis %q", values.KeyName, values.AllowedReferrers, values.AllowedIPs, values.AllowedAPIs)
+			return nil
+		}
+		if err := services.APIKeys.RestrictKey(ctx, values.KeyName, values.AllowedReferrers, values.AllowedIPs, values.AllowedAPIs); err != nil {
+			return err
+		}
+		logging.FromContext(ctx).Info("restricted api key %q in project %q", values.KeyName, values.ProjectID)
+	default:
+		return fmt.Errorf("unknown api key action: %q", values.Action)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package restrictapikey
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+func TestRestrictAPIKey(t *testing.T) {
+	ctx := context.Background()
+	const keyName = "projects/123/locations/global/keys/abc"
+	for _, tt := range []struct {
+		name              string
+		values            *Values
+		expectedDeleted   []string
+		expectedReferrers []string
+		expectedIPs       []string
+		expectedAPIs      []string
+		expectError       bool
+	}{
+		{
+			name:            "delete",
+			values:          &Values{Action: "delete", KeyName: keyName},
+			expectedDeleted: []string{keyName},
+		},
+		{
+			name:              "restrict to referrers and apis",
+			values:            &Values{Action: "restrict", KeyName: keyName, AllowedReferrers: []string{"*.example.com"}, AllowedAPIs: []string{"maps.googleapis.com"}},
+			expectedReferrers: []string{"*.example.com"},
+			expectedAPIs:      []string{"maps.googleapis.com"},
+		},
+		{
+			name:        "restrict to ips",
+			values:      &Values{Action: "restrict", KeyName: keyName, AllowedIPs: []string{"10.0.0.0/8"}},
+			expectedIPs: []string{"10.0.0.0/8"},
+		},
+		{
+			name:        "restrict without restrictions",
+			values:      &Values{Action: "restrict", KeyName: keyName},
+			expectError: true,
+		},
+		{
+			name:   "dry run",
+			values: &Values{Action: "delete", KeyName: keyName, DryRun: true},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			apiKeysStub := &stubs.APIKeysStub{}
+			err := Execute(ctx, tt.values, &Services{
+				APIKeys: services.NewAPIKeys(apiKeysStub),
+			})
+			if tt.expectError != (err != nil) {
+				t.Fatalf("%s failed, expected error:%v got:%v", tt.name, tt.expectError, err)
+			}
+			if diff := cmp.Diff(tt.expectedDeleted, apiKeysStub.DeletedKeys); diff != "" {
+				t.Errorf("%s failed, difference in deleted keys: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(tt.expectedReferrers, apiKeysStub.SavedReferrers); diff != "" {
+				t.Errorf("%s failed, difference in referrers: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(tt.expectedIPs, apiKeysStub.SavedIPs); diff != "" {
+				t.Errorf("%s failed, difference in ips: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(tt.expectedAPIs, apiKeysStub.SavedAPIs); diff != "" {
+				t.Errorf("%s failed, difference in apis: %+v", tt.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package approve
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/pkg/errors"
+)
+
+// Values contains the required values needed for this function, taken from the approval link.
+type Values struct {
+	ID        string
+	Decision  string
+	Approver  string
+	Expires   string
+	Signature string
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Approvals *services.Approvals
+	PubSub    *services.PubSub
+	// Slack is only required for decisions made from Slack.
+	Slack *services.Slack
+}
+
+// Execute records the approver's decision on the pending action and publishes the action to its
+// topic once approved.
+func Execute(ctx context.Context, values *Values, services *Services) (*services.PendingAction, error) {
+	now := time.Now()
+	if err := services.Approvals.Verify(values.ID, values.Decision, values.Approver, values.Expires, values.Signature, now); err != nil {
+		return nil, errors.Wrap(err, "failed to verify approval link")
+	}
+	return decide(ctx, values.ID, values.Decision, values.Approver, now, services)
+}
+
+// ExecuteSlack records the decision made with the buttons of a Slack approval request, if the
+// Slack user is one of the approvers, and replies with the outcome in the finding's thread.
+func ExecuteSlack(ctx context.Context, d *services.SlackDecision, approvers []string, services *Services) (*services.PendingAction, error) {
+	allowed := false
+	for _, a := range approvers {
+		if strings.EqualFold(a, d.Approver) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, fmt.Errorf("%q is not an approver", d.Approver)
+	}
+	p, err := decide(ctx, d.ID, d.Decision, d.Approver, time.Now(), services)
+	if err != nil {
+		return nil, err
+	}
+	text := fmt.Sprintf("%s decided to %s %q, %d of %d approvals. The action is %s.", d.Approver, d.Decision, p.Action, len(p.Approvers), p.Required, p.Status)
+	if err := services.Slack.Reply(ctx, p.FindingID, text); err != nil {
+		logging.FromContext(ctx).Error("failed to reply to slack thread of %q: %q", p.FindingID, err)
+	}
+	return p, nil
+}
+
+// decide records the approver's decision and publishes the action once approved.
+func decide(ctx context.Context, id, decision, approver string, now time.Time, services *Services) (*services.PendingAction, error) {
+	p, err := services.Approvals.Pending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := services.Approvals.Decide(ctx, p, decision, approver, now); err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("%s decided to %s pending action %q of %q, %d of %d approvals", approver, decision, p.ID, p.Action, len(p.Approvers), p.Required)
+	if !p.Approved() {
+		return p, nil
+	}
+	attributes := map[string]string{"finding_id": p.FindingID, "topic": p.Topic, "approval_id": p.ID, "approvers": strings.Join(p.Approvers, ",")}
+	if len(p.Notify) > 0 {
+		attributes["notify"] = strings.Join(p.Notify, ",")
+	}
+	if p.Severity != "" {
+		attributes["severity"] = p.Severity
+	}
+	if p.Category != "" {
+		attributes["category"] = p.Category
+	}
+	if p.ServiceAccount != "" {
+		attributes["service_account"] = p.ServiceAccount
+	}
+	if _, err := services.PubSub.Publish(ctx, p.Topic, &pubsub.Message{
+		Data:       p.Data,
+		Attributes: attributes,
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to publish approved action %q", p.Action)
+	}
+	return p, nil
+}
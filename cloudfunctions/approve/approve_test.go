@@ -0,0 +1,127 @@
+package approve
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+func TestApprove(t *testing.T) {
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name          string
+		decision      string
+		tamper        bool
+		wantStatus    string
+		wantPublished bool
+		wantErr       bool
+	}{
+		{name: "approve", decision: services.DecisionApprove, wantStatus: services.ApprovalApproved, wantPublished: true},
+		{name: "deny", decision: services.DecisionDeny, wantStatus: services.ApprovalDenied},
+		{name: "invalid signature", decision: services.DecisionApprove, tamper: true, wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			psStub := &stubs.PubSubStub{}
+			approvals := services.NewApprovals(&stubs.FirestoreStub{}, "automation-project", []byte("secret"))
+			p := &services.PendingAction{
+				Action:         "disable_billing",
+				Topic:          "threat-findings-disable-billing",
+				FindingID:      "finding-1",
+				ServiceAccount: "remediator@folder-project.iam.gserviceaccount.com",
+				Data:           []byte(`{"ProjectID":"test-project"}`),
+			}
+			if err := approvals.Request(ctx, p, time.Hour, time.Now()); err != nil {
+				t.Fatalf("failed to request approval: %q", err)
+			}
+			link, _ := url.Parse(approvals.Link("https://approve.example.com", p, tt.decision, "alice@cloudorg.com"))
+			q := link.Query()
+			values := &Values{ID: q.Get("id"), Decision: q.Get("decision"), Approver: q.Get("approver"), Expires: q.Get("expires"), Signature: q.Get("signature")}
+			if tt.tamper {
+				values.Approver = "mallory@cloudorg.com"
+			}
+			got, err := Execute(ctx, values, &Services{
+				Approvals: approvals,
+				PubSub:    services.NewPubSub(psStub),
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%s failed: got err %v want err %v", tt.name, err, tt.wantErr)
+			}
+			if err == nil && got.Status != tt.wantStatus {
+				t.Errorf("%s failed: got status %q want %q", tt.name, got.Status, tt.wantStatus)
+			}
+			if published := psStub.PublishedMessage != nil; published != tt.wantPublished {
+				t.Fatalf("%s failed: got published %v want %v", tt.name, published, tt.wantPublished)
+			}
+			if tt.wantPublished {
+				m := psStub.PublishedMessage
+				if string(m.Data) != `{"ProjectID":"test-project"}` || m.Attributes["finding_id"] != "finding-1" || m.Attributes["approvers"] != "alice@cloudorg.com" {
+					t.Errorf("%s failed: unexpected message %+v", tt.name, psStub.PublishedMessage)
+				}
+				if got := m.Attributes["service_account"]; got != p.ServiceAccount {
+					t.Errorf("%s failed: got service account %q want %q", tt.name, got, p.ServiceAccount)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteSlack(t *testing.T) {
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name          string
+		approver      string
+		wantPublished bool
+		wantErr       bool
+	}{
+		{name: "approver", approver: "Alice@cloudorg.com", wantPublished: true},
+		{name: "not an approver", approver: "mallory@cloudorg.com", wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			psStub := &stubs.PubSubStub{}
+			slackStub := &stubs.SlackStub{}
+			fsStub := &stubs.FirestoreStub{}
+			approvals := services.NewApprovals(fsStub, "automation-project", []byte("secret"))
+			p := &services.PendingAction{
+				Action:    "disable_billing",
+				Topic:     "threat-findings-disable-billing",
+				FindingID: "finding-1",
+				Data:      []byte(`{"ProjectID":"test-project"}`),
+			}
+			if err := approvals.Request(ctx, p, time.Hour, time.Now()); err != nil {
+				t.Fatalf("failed to request approval: %q", err)
+			}
+			d := &services.SlackDecision{ID: p.ID, Decision: services.DecisionApprove, Approver: tt.approver}
+			_, err := ExecuteSlack(ctx, d, []string{"alice@cloudorg.com"}, &Services{
+				Approvals: approvals,
+				PubSub:    services.NewPubSub(psStub),
+				Slack:     services.NewSlack(slackStub, fsStub, "automation-project", "C0123", "secret"),
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%s failed: got err %v want err %v", tt.name, err, tt.wantErr)
+			}
+			if published := psStub.PublishedMessage != nil; published != tt.wantPublished {
+				t.Fatalf("%s failed: got published %v want %v", tt.name, published, tt.wantPublished)
+			}
+			if replied := len(slackStub.SavedMessages) == 1; replied != tt.wantPublished {
+				t.Errorf("%s failed: got replied %v want %v", tt.name, replied, tt.wantPublished)
+			}
+		})
+	}
+}
@@ -0,0 +1,7 @@
+output "url" {
+  value = google_cloudfunctions_function.approve_function.https_trigger_url
+}
+
+output "slack_url" {
+  value = google_cloudfunctions_function.slack_interaction_function.https_trigger_url
+}
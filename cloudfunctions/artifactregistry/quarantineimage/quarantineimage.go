@@ -0,0 +1,74 @@
+package quarantineimage
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// defaultTag is the tag applied to quarantined images if one is not configured.
+const defaultTag = "quarantined"
+
+// Values contains the required values needed for this function.
+type Values struct {
+	// Image is the Artifact Registry image with its digest, such as
+	// "us-docker.pkg.dev/<project>/<repository>/<image>@sha256:<digest>".
+	Image     string
+	ProjectID string
+	// Tag is applied to the image's digest, defaults to "quarantined".
+	Tag string
+	// BlockDeployment removes Binary Authorization allowlist patterns matching the image.
+	BlockDeployment bool
+	DryRun          bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	ArtifactRegistry    *services.ArtifactRegistry
+	BinaryAuthorization *services.BinaryAuthorization
+}
+
+// Execute tags the image as quarantined, removes its other tags and optionally stops the image from
+// bypassing the project's Binary Authorization policy.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	tag := values.Tag
+	if tag == "" {
+		tag = defaultTag
+	}
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have quarantined image %q with tag %q in project %q", values.Image, tag, values.ProjectID)
+		return nil
+	}
+	removed, err := services.ArtifactRegistry.QuarantineImage(ctx, values.Image, tag)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("quarantined image %q with tag %q and removed tags %q in project %q", values.Image, tag, removed, values.ProjectID)
+	if !values.BlockDeployment {
+		return nil
+	}
+	patterns, enforced, err := services.BinaryAuthorization.BlockImage(ctx, values.ProjectID, values.Image)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("removed binary authorization allowlist patterns %q matching image %q in project %q", patterns, values.Image, values.ProjectID)
+	if !enforced {
+		logging.FromContext(ctx).Warning("binary authorization default rule allows all images in project %q, image %q can still be deployed", values.ProjectID, values.Image)
+	}
+	return nil
+}
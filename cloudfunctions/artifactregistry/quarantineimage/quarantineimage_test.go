@@ -0,0 +1,108 @@
+package quarantineimage
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	artifactregistry "google.golang.org/api/artifactregistry/v1beta1"
+	binaryauthorization "google.golang.org/api/binaryauthorization/v1"
+)
+
+func TestQuarantineImage(t *testing.T) {
+	const (
+		image   = "us-docker.pkg.dev/project-id/images/app@sha256:abc123"
+		pkg     = "projects/project-id/locations/us/repositories/images/packages/app"
+		version = pkg + "/versions/sha256:abc123"
+	)
+	ctx := context.Background()
+	allowlist := func(patterns ...string) *binaryauthorization.Policy {
+		p := &binaryauthorization.Policy{DefaultAdmissionRule: &binaryauthorization.AdmissionRule{EvaluationMode: "REQUIRE_ATTESTATION"}}
+		for _, n := range patterns {
+			p.AdmissionWhitelistPatterns = append(p.AdmissionWhitelistPatterns, &binaryauthorization.AdmissionWhitelistPattern{NamePattern: n})
+		}
+		return p
+	}
+	for _, tt := range []struct {
+		name           string
+		values         *Values
+		tags           []*artifactregistry.Tag
+		policy         *binaryauthorization.Policy
+		expectedCreate []*artifactregistry.Tag
+		expectedUpdate []*artifactregistry.Tag
+		expectedDelete []string
+		expectedPolicy *binaryauthorization.Policy
+	}{
+		{
+			name:   "quarantine image",
+			values: &Values{Image: image},
+			tags: []*artifactregistry.Tag{
+				{Name: pkg + "/tags/latest", Version: version},
+				{Name: pkg + "/tags/v1", Version: version},
+				{Name: pkg + "/tags/v2", Version: pkg + "/versions/sha256:def456"},
+			},
+			expectedCreate: []*artifactregistry.Tag{{Name: pkg + "/tags/quarantined", Version: version}},
+			expectedDelete: []string{pkg + "/tags/latest", pkg + "/tags/v1"},
+		},
+		{
+			name:           "move existing quarantine tag",
+			values:         &Values{Image: image, Tag: "blocked"},
+			tags:           []*artifactregistry.Tag{{Name: pkg + "/tags/blocked", Version: pkg + "/versions/sha256:def456"}},
+			expectedUpdate: []*artifactregistry.Tag{{Name: pkg + "/tags/blocked", Version: version}},
+		},
+		{
+			name:           "block deployment",
+			values:         &Values{Image: image, BlockDeployment: true},
+			policy:         allowlist("us-docker.pkg.dev/project-id/images/*", "gcr.io/project-id/app", "us-docker.pkg.dev/project-id/images/app"),
+			expectedCreate: []*artifactregistry.Tag{{Name: pkg + "/tags/quarantined", Version: version}},
+			expectedPolicy: allowlist("gcr.io/project-id/app"),
+		},
+		{
+			name:   "dry run",
+			values: &Values{Image: image, BlockDeployment: true, DryRun: true},
+			tags:   []*artifactregistry.Tag{{Name: pkg + "/tags/latest", Version: version}},
+			policy: allowlist("us-docker.pkg.dev/project-id/images/*"),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			arStub := &stubs.ArtifactRegistryStub{StubbedTags: tt.tags}
+			baStub := &stubs.BinaryAuthorizationStub{StubbedPolicy: tt.policy}
+			values := tt.values
+			values.ProjectID = "project-id"
+			if err := Execute(ctx, values, &Services{
+				ArtifactRegistry:    services.NewArtifactRegistry(arStub),
+				BinaryAuthorization: services.NewBinaryAuthorization(baStub),
+			}); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(tt.expectedCreate, arStub.CreatedTags); diff != "" {
+				t.Errorf("%s failed, created tags difference: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(tt.expectedUpdate, arStub.UpdatedTags); diff != "" {
+				t.Errorf("%s failed, updated tags difference: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(tt.expectedDelete, arStub.DeletedTags); diff != "" {
+				t.Errorf("%s failed, deleted tags difference: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(tt.expectedPolicy, baStub.SavedPolicy); diff != "" {
+				t.Errorf("%s failed, policy difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package removepublicrepository
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/pkg/errors"
+)
+
+// publicUsers contains a slice of public users we want to remove.
+var publicUsers = []string{"allUsers", "allAuthenticatedUsers"}
+
+// Values contains the required values needed for this function.
+//
+// Either Repository or Bucket is set depending on whether the finding is for an Artifact Registry
+// repository or the bucket backing a Container Registry host.
+type Values struct {
+	// Repository is the Artifact Registry repository, such as
+	// "projects/<project>/locations/<location>/repositories/<repository>".
+	Repository string
+	// Bucket is the Container Registry bucket, such as "artifacts.<project>.appspot.com".
+	Bucket    string
+	ProjectID string
+	DryRun    bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	ArtifactRegistry *services.ArtifactRegistry
+	Resource         *services.Resource
+}
+
+// Execute will remove any public users from the repository's IAM policy or the Container Registry
+// bucket's IAM policy and ACLs.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	if values.Repository != "" {
+		if values.DryRun {
+			logging.FromContext(ctx).Info("dry_run on, would have removed public members from repository %q in project %q", values.Repository, values.ProjectID)
+		} else {
+			removed, err := services.ArtifactRegistry.RemoveMembers(ctx, values.Repository, publicUsers)
+			if err != nil {
+				return err
+			}
+			logging.FromContext(ctx).Info("removed %q from repository %q in project %q", removed, values.Repository, values.ProjectID)
+		}
+	}
+	if values.Bucket == "" {
+		return nil
+	}
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have removed public members from registry bucket %q in project %q", values.Bucket, values.ProjectID)
+		return nil
+	}
+	if err := services.Resource.RemoveMembersFromBucket(ctx, values.Bucket, publicUsers); err != nil {
+		return errors.Wrapf(err, "failed to remove public members from %q", values.Bucket)
+	}
+	if err := services.Resource.RemoveEntitiesFromBucketACL(ctx, values.Bucket, publicUsers); err != nil {
+		return errors.Wrapf(err, "failed to remove public acls from %q", values.Bucket)
+	}
+	logging.FromContext(ctx).Info("removed public members from registry bucket %q in project %q", values.Bucket, values.ProjectID)
+	return nil
+}
@@ -0,0 +1,105 @@
+package removepublicrepository
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/iam"
+	"cloud.google.com/go/storage"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	artifactregistry "google.golang.org/api/artifactregistry/v1beta1"
+)
+
+func TestRemovePublicRepository(t *testing.T) {
+	const repository = "projects/project-id/locations/us-central1/repositories/images"
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name             string
+		values           *Values
+		policy           *artifactregistry.Policy
+		expectedPolicy   *artifactregistry.Policy
+		expectedResource string
+		expectedMembers  []string
+		expectedACLs     []storage.ACLEntity
+	}{
+		{
+			name:   "repository",
+			values: &Values{Repository: repository},
+			policy: &artifactregistry.Policy{Bindings: []*artifactregistry.Binding{
+				{Role: "roles/artifactregistry.reader", Members: []string{"allUsers", "group:devs@example.com"}},
+				{Role: "roles/artifactregistry.writer", Members: []string{"allAuthenticatedUsers"}},
+			}},
+			expectedPolicy: &artifactregistry.Policy{Bindings: []*artifactregistry.Binding{
+				{Role: "roles/artifactregistry.reader", Members: []string{"group:devs@example.com"}},
+				{Role: "roles/artifactregistry.writer", Members: []string{}},
+			}},
+			expectedResource: repository,
+		},
+		{
+			name:   "private repository",
+			values: &Values{Repository: repository},
+			policy: &artifactregistry.Policy{Bindings: []*artifactregistry.Binding{
+				{Role: "roles/artifactregistry.reader", Members: []string{"group:devs@example.com"}},
+			}},
+		},
+		{
+			name:            "container registry bucket",
+			values:          &Values{Bucket: "artifacts.project-id.appspot.com"},
+			expectedMembers: []string{"group:devs@example.com"},
+			expectedACLs:    []storage.ACLEntity{storage.AllUsers},
+		},
+		{
+			name:   "dry run",
+			values: &Values{Repository: repository, Bucket: "artifacts.project-id.appspot.com", DryRun: true},
+			policy: &artifactregistry.Policy{Bindings: []*artifactregistry.Binding{
+				{Role: "roles/artifactregistry.reader", Members: []string{"allUsers"}},
+			}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			arStub := &stubs.ArtifactRegistryStub{StubbedPolicy: tt.policy}
+			storageStub := &stubs.StorageStub{BucketPolicyResponse: &iam.Policy{}}
+			storageStub.BucketPolicyResponse.Add("allUsers", "roles/storage.objectViewer")
+			storageStub.BucketPolicyResponse.Add("group:devs@example.com", "roles/storage.objectViewer")
+			storageStub.BucketAttrsResponse = &storage.BucketAttrs{ACL: []storage.ACLRule{{Entity: storage.AllUsers, Role: storage.RoleReader}}}
+			values := tt.values
+			values.ProjectID = "project-id"
+			if err := Execute(ctx, values, &Services{
+				ArtifactRegistry: services.NewArtifactRegistry(arStub),
+				Resource:         services.NewResource(&stubs.ResourceManagerStub{}, storageStub),
+			}); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(tt.expectedPolicy, arStub.SavedPolicy); diff != "" {
+				t.Errorf("%s failed, policy difference: %+v", tt.name, diff)
+			}
+			if arStub.SavedResource != tt.expectedResource {
+				t.Errorf("%s failed, got resource:%q want:%q", tt.name, arStub.SavedResource, tt.expectedResource)
+			}
+			if tt.expectedMembers != nil {
+				if diff := cmp.Diff(tt.expectedMembers, storageStub.RemoveBucketPolicy.Members("roles/storage.objectViewer")); diff != "" {
+					t.Errorf("%s failed, bucket members difference: %+v", tt.name, diff)
+				}
+			}
+			if diff := cmp.Diff(tt.expectedACLs, storageStub.DeletedBucketACLs); diff != "" {
+				t.Errorf("%s failed, acl difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
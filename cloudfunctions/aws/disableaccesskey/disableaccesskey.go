@@ -0,0 +1,62 @@
+package disableaccesskey
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// iamUser is the user type of IAM users, whose access keys are long lived and can be disabled.
+const iamUser = "IAMUser"
+
+// Values contains the required values needed for this function.
+type Values struct {
+	// AccountID is the ID of the AWS account of the access key.
+	AccountID   string
+	UserName    string
+	AccessKeyID string
+	// UserType is the type of principal the access key belongs to, such as IAMUser, Root or
+	// AssumedRole. Only access keys of IAM users are disabled.
+	UserType string
+	// CredentialsSecret is the Secret Manager secret version holding the AWS credentials the
+	// automation acts with, such as "projects/p/secrets/aws/versions/latest".
+	CredentialsSecret string
+	DryRun            bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	AWS *services.AWS
+}
+
+// Execute will deactivate the AWS access key of the IAM user.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	if values.UserType != iamUser {
+		logging.FromContext(ctx).Info("access key %q belongs to a principal of type %q, only access keys of IAM users are disabled", values.AccessKeyID, values.UserType)
+		return nil
+	}
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have disabled access key %q of %q in account %q", values.AccessKeyID, values.UserName, values.AccountID)
+		return nil
+	}
+	if err := services.AWS.DisableAccessKey(ctx, values.UserName, values.AccessKeyID); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("disabled access key %q of %q in account %q", values.AccessKeyID, values.UserName, values.AccountID)
+	return nil
+}
@@ -0,0 +1,68 @@
+package isolateinstance
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// defaultSecurityGroup is the name of the isolation security group if none is configured.
+const defaultSecurityGroup = "sra-isolation"
+
+// Values contains the required values needed for this function.
+type Values struct {
+	// AccountID is the ID of the AWS account of the instance.
+	AccountID  string
+	Region     string
+	InstanceID string
+	// SecurityGroup is the name of the security group isolating instances of the VPC, created
+	// without rules if missing. Defaults to sra-isolation.
+	SecurityGroup string
+	// CredentialsSecret is the Secret Manager secret version holding the AWS credentials the
+	// automation acts with, such as "projects/p/secrets/aws/versions/latest".
+	CredentialsSecret string
+	DryRun            bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	AWS *services.AWS
+}
+
+// Execute will isolate the EC2 instance by replacing its security groups with the isolation
+// security group of its VPC.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	group := values.SecurityGroup
+	if group == "" {
+		group = defaultSecurityGroup
+	}
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have isolated instance %q in %q of account %q with security group %q", values.InstanceID, values.Region, values.AccountID, group)
+		return nil
+	}
+	replaced, err := services.AWS.IsolateInstance(ctx, values.Region, values.InstanceID, group)
+	if err != nil {
+		return err
+	}
+	if len(replaced) == 0 {
+		logging.FromContext(ctx).Info("instance %q in %q of account %q is already isolated", values.InstanceID, values.Region, values.AccountID)
+		return nil
+	}
+	logging.FromContext(ctx).Info("isolated instance %q in %q of account %q with security group %q, replacing %q", values.InstanceID, values.Region, values.AccountID, group, replaced)
+	return nil
+}
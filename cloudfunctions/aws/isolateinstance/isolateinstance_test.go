@@ -0,0 +1,65 @@
+package isolateinstance
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+func TestIsolateInstance(t *testing.T) {
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name           string
+		securityGroup  string
+		dryRun         bool
+		expectedGroup  string
+		instanceGroups []string
+	}{
+		{name: "isolate instance", expectedGroup: "sra-isolation", instanceGroups: []string{"sg-created"}},
+		{name: "configured security group", securityGroup: "quarantine", expectedGroup: "quarantine", instanceGroups: []string{"sg-created"}},
+		{name: "dry run", dryRun: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			awsStub := &stubs.AWSStub{StubbedInstance: &ec2.Instance{
+				InstanceId:     aws.String("i-0123456789abcdef0"),
+				VpcId:          aws.String("vpc-1"),
+				SecurityGroups: []*ec2.GroupIdentifier{{GroupId: aws.String("sg-web")}},
+			}}
+			values := &Values{
+				AccountID:     "123456789012",
+				Region:        "us-east-1",
+				InstanceID:    "i-0123456789abcdef0",
+				SecurityGroup: tt.securityGroup,
+				DryRun:        tt.dryRun,
+			}
+			if err := Execute(ctx, values, &Services{AWS: services.NewAWS(awsStub)}); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if awsStub.CreatedSecurityGroup != tt.expectedGroup {
+				t.Errorf("%s failed, got security group:%q want:%q", tt.name, awsStub.CreatedSecurityGroup, tt.expectedGroup)
+			}
+			if diff := cmp.Diff(tt.instanceGroups, awsStub.SavedInstanceGroups); diff != "" {
+				t.Errorf("%s failed, difference in security groups: %+v", tt.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package removepubliccontainer
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// Values contains the required values needed for this function.
+type Values struct {
+	// SubscriptionID is the ID of the Azure subscription of the storage account.
+	SubscriptionID string
+	ResourceGroup  string
+	StorageAccount string
+	Container      string
+	// CredentialsSecret is the Secret Manager secret version holding the credentials of the Azure
+	// service principal the automation acts as, such as "projects/p/secrets/azure/versions/latest".
+	CredentialsSecret string
+	DryRun            bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Azure *services.Azure
+}
+
+// Execute will remove anonymous read access to the blob container.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	access, err := services.Azure.ContainerPublicAccess(ctx, values.SubscriptionID, values.ResourceGroup, values.StorageAccount, values.Container)
+	if err != nil {
+		return err
+	}
+	if access == "None" {
+		logging.FromContext(ctx).Info("container %q of storage account %q is not public", values.Container, values.StorageAccount)
+		return nil
+	}
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have removed %q public access of container %q of storage account %q in subscription %q", access, values.Container, values.StorageAccount, values.SubscriptionID)
+		return nil
+	}
+	if err := services.Azure.RemoveContainerPublicAccess(ctx, values.SubscriptionID, values.ResourceGroup, values.StorageAccount, values.Container); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("removed %q public access of container %q of storage account %q in subscription %q", access, values.Container, values.StorageAccount, values.SubscriptionID)
+	return nil
+}
@@ -0,0 +1,57 @@
+package removepubliccontainer
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+func TestRemovePublicContainer(t *testing.T) {
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name              string
+		access            string
+		dryRun            bool
+		expectedContainer string
+		expectedAccess    string
+	}{
+		{name: "remove container access", access: "Container", expectedContainer: "simulated-container", expectedAccess: "None"},
+		{name: "remove blob access", access: "Blob", expectedContainer: "simulated-container", expectedAccess: "None"},
+		{name: "dry run", access: "Container", dryRun: true},
+		{name: "not public", access: "None"},
+		{name: "no access level", access: ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			azureStub := &stubs.AzureStub{StubbedPublicAccess: tt.access}
+			values := &Values{
+				SubscriptionID: "0b1f6471-1bf0-4dda-aec3-111122223333",
+				ResourceGroup:  "simulated-rg",
+				StorageAccount: "simulatedaccount",
+				Container:      "simulated-container",
+				DryRun:         tt.dryRun,
+			}
+			if err := Execute(ctx, values, &Services{Azure: services.NewAzure(azureStub)}); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if azureStub.SavedContainer != tt.expectedContainer || azureStub.SavedPublicAccess != tt.expectedAccess {
+				t.Errorf("%s failed, got container:%q access:%q want container:%q access:%q", tt.name, azureStub.SavedContainer, azureStub.SavedPublicAccess, tt.expectedContainer, tt.expectedAccess)
+			}
+		})
+	}
+}
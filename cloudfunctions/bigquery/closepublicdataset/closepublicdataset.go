@@ -17,6 +17,7 @@ package closepublicdataset
 import (
 	"context"
 
+	"github.com/googlecloudplatform/security-response-automation/logging"
 	"github.com/googlecloudplatform/security-response-automation/services"
 	"github.com/pkg/errors"
 )
@@ -31,18 +32,17 @@ type Values struct {
 // Services contains the services needed for this function.
 type Services struct {
 	BigQuery *services.BigQuery
-	Logger   *services.Logger
 }
 
 // Execute removes public access of a BigQuery dataset.
 func Execute(ctx context.Context, values *Values, services *Services) error {
 	if values.DryRun {
-		services.Logger.Info("dry_run on, would have removed public access on bigquery dataset %q in project %q", values.DatasetID, values.ProjectID)
+		logging.FromContext(ctx).Info("dry_run on, would have removed public access on bigquery dataset %q in project %q", values.DatasetID, values.ProjectID)
 		return nil
 	}
 	if err := services.BigQuery.RemoveDatasetPublicAccess(ctx, values.ProjectID, values.DatasetID); err != nil {
 		return errors.Wrapf(err, "error removing bigquery dataset %q public access in project %q", values.DatasetID, values.ProjectID)
 	}
-	services.Logger.Info("removed public access on bigquery dataset %q in project %q", values.DatasetID, values.ProjectID)
+	logging.FromContext(ctx).Info("removed public access on bigquery dataset %q in project %q", values.DatasetID, values.ProjectID)
 	return nil
 }
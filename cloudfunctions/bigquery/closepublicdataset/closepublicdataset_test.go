@@ -54,7 +54,7 @@ func TestClosePublicDataset(t *testing.T) {
 	}
 	for _, tt := range test {
 		t.Run(tt.name, func(t *testing.T) {
-			svcs, bigqueryStub := setup()
+			bigqueryStub := setup()
 			bigqueryStub.StubbedMetadata = tt.metadata
 			bigqueryStub.SavedDatasetMetadata = tt.expectedMetadata
 			values := &Values{
@@ -64,7 +64,6 @@ func TestClosePublicDataset(t *testing.T) {
 			bq := services.NewBigQuery(bigqueryStub)
 			if err := Execute(ctx, values, &Services{
 				BigQuery: bq,
-				Logger:   svcs.Logger,
 			}); err != nil {
 				t.Errorf("%s failed to remove public access in bigquery dataset:%q", tt.name, err)
 			}
@@ -76,9 +75,6 @@ func TestClosePublicDataset(t *testing.T) {
 	}
 }
 
-func setup() (*services.Global, *stubs.BigQueryStub) {
-	loggerStub := &stubs.LoggerStub{}
-	log := services.NewLogger(loggerStub)
-	bigqueryStub := &stubs.BigQueryStub{}
-	return &services.Global{Logger: log}, bigqueryStub
+func setup() *stubs.BigQueryStub {
+	return &stubs.BigQueryStub{}
 }
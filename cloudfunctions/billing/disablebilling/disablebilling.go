@@ -0,0 +1,108 @@
+package disablebilling
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/pkg/errors"
+)
+
+const (
+	// Action is the action approvals to disable billing are requested for.
+	Action = "disable_billing"
+	// StateMark is the security mark tracking the state of the automation on a finding.
+	StateMark = "sra-disable-billing"
+	// stateDisabled marks findings whose project had billing disabled.
+	stateDisabled = "disabled"
+)
+
+// Values contains the required values needed for this function.
+type Values struct {
+	ProjectID string
+	// FindingName is the Security Command Center finding marked once billing is disabled, if any.
+	FindingName string
+	DryRun      bool
+	// ApprovalID is the pending action approved to disable billing. It's set from the attributes
+	// of the message the approval published, never from the message itself.
+	ApprovalID string `json:"-"`
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Approvals             *services.Approvals
+	Billing               *services.Billing
+	SecurityCommandCenter *services.CommandCenter
+}
+
+// Execute detaches the billing account of a project with sustained cryptomining findings.
+//
+// Billing is never disabled without approval. The router holds every request until the approvers
+// it requires followed their signed links, and the values are only trusted if they are those of
+// the pending action they approved.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	if err := approved(ctx, values, services.Approvals); err != nil {
+		return err
+	}
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have disabled billing for project %q", values.ProjectID)
+		return nil
+	}
+	account, err := services.Billing.DisableBilling(ctx, values.ProjectID)
+	if err != nil {
+		return err
+	}
+	if values.FindingName != "" {
+		if _, err := services.SecurityCommandCenter.AddSecurityMarks(ctx, values.FindingName, map[string]string{StateMark: stateDisabled}); err != nil {
+			return err
+		}
+	}
+	if account == "" {
+		logging.FromContext(ctx).Info("billing already disabled for project %q", values.ProjectID)
+		return nil
+	}
+	logging.FromContext(ctx).Info("disabled billing for project %q, detached billing account %q", values.ProjectID, account)
+	return nil
+}
+
+// approved returns an error unless the values are those of a pending action approved to disable
+// billing, and records its approvers in the audit trail.
+func approved(ctx context.Context, values *Values, approvals *services.Approvals) error {
+	if values.ApprovalID == "" {
+		return errors.New("disabling billing requires approval")
+	}
+	p, err := approvals.Pending(ctx, values.ApprovalID)
+	if err != nil {
+		return err
+	}
+	if p.Action != Action || !p.Approved() {
+		return errors.Errorf("pending action %q is not an approved %s", p.ID, Action)
+	}
+	var want Values
+	if err := json.Unmarshal(p.Data, &want); err != nil {
+		return errors.Wrapf(err, "failed to read values of pending action %q", p.ID)
+	}
+	want.ApprovalID = values.ApprovalID
+	if want != *values {
+		return errors.Errorf("values differ from those of pending action %q", p.ID)
+	}
+	services.AuditApprovers(ctx, p.ID, p.Approvers)
+	logging.FromContext(ctx).Info("disabling billing for project %q approved by %s", values.ProjectID, strings.Join(p.Approvers, ", "))
+	return nil
+}
@@ -0,0 +1,143 @@
+package disablebilling
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	cloudbilling "google.golang.org/api/cloudbilling/v1"
+)
+
+func TestDisableBilling(t *testing.T) {
+	const findingName = "organizations/123/sources/456/findings/789"
+	detached := &cloudbilling.ProjectBillingInfo{ForceSendFields: []string{"BillingAccountName"}}
+	values := Values{ProjectID: "project-id", FindingName: findingName}
+	tests := []struct {
+		name         string
+		action       string
+		approvers    []string
+		approved     Values
+		values       Values
+		noApproval   bool
+		expectedErr  bool
+		expectedMark map[string]string
+		expectedInfo *cloudbilling.ProjectBillingInfo
+	}{
+		{
+			name:         "approved",
+			action:       Action,
+			approvers:    []string{"alice@example.com", "bob@example.com"},
+			approved:     values,
+			values:       values,
+			expectedMark: map[string]string{StateMark: "disabled"},
+			expectedInfo: detached,
+		},
+		{
+			name:      "approved dry run",
+			action:    Action,
+			approvers: []string{"alice@example.com"},
+			approved:  Values{ProjectID: "project-id", FindingName: findingName, DryRun: true},
+			values:    Values{ProjectID: "project-id", FindingName: findingName, DryRun: true},
+		},
+		{
+			name:        "without approval",
+			values:      values,
+			noApproval:  true,
+			expectedErr: true,
+		},
+		{
+			name:        "pending approval",
+			action:      Action,
+			approved:    values,
+			values:      values,
+			expectedErr: true,
+		},
+		{
+			name:        "approval of another action",
+			action:      "quarantine_instance",
+			approvers:   []string{"alice@example.com"},
+			approved:    values,
+			values:      values,
+			expectedErr: true,
+		},
+		{
+			name:        "approval of another project",
+			action:      Action,
+			approvers:   []string{"alice@example.com"},
+			approved:    Values{ProjectID: "other-project", FindingName: findingName},
+			values:      values,
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			billingStub := &stubs.BillingStub{
+				StubbedBillingInfo: &cloudbilling.ProjectBillingInfo{BillingEnabled: true, BillingAccountName: "billingAccounts/0X0X0X-0X0X0X-0X0X0X"},
+			}
+			sccStub := &stubs.SecurityCommandCenterStub{}
+			approvals := services.NewApprovals(&stubs.FirestoreStub{}, "automation-project", []byte("key"))
+			values := tt.values
+			if !tt.noApproval {
+				data, _ := json.Marshal(&tt.approved)
+				p := &services.PendingAction{Action: tt.action, Data: data, Required: len(tt.approvers)}
+				now := time.Now()
+				if err := approvals.Request(ctx, p, time.Hour, now); err != nil {
+					t.Fatalf("%s failed to request approval: %q", tt.name, err)
+				}
+				for _, approver := range tt.approvers {
+					if err := approvals.Decide(ctx, p, services.DecisionApprove, approver, now); err != nil {
+						t.Fatalf("%s failed to approve: %q", tt.name, err)
+					}
+				}
+				values.ApprovalID = p.ID
+			}
+			err := Execute(ctx, &values, &Services{
+				Approvals:             approvals,
+				Billing:               services.NewBilling(billingStub),
+				SecurityCommandCenter: services.NewCommandCenter(sccStub),
+			})
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("%s failed: got error %v", tt.name, err)
+			}
+			var marks map[string]string
+			if r := sccStub.GetUpdateSecurityMarksRequest; r != nil {
+				marks = r.GetSecurityMarks().GetMarks()
+			}
+			if diff := cmp.Diff(tt.expectedMark, marks); diff != "" {
+				t.Errorf("%s failed, marks difference: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(tt.expectedInfo, billingStub.SavedBillingInfo); diff != "" {
+				t.Errorf("%s failed, billing difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestApprovalIDFromMessage(t *testing.T) {
+	var values Values
+	if err := json.Unmarshal([]byte(`{"ProjectID": "project-id", "ApprovalID": "forged"}`), &values); err != nil {
+		t.Fatalf("failed to read values: %q", err)
+	}
+	if values.ApprovalID != "" {
+		t.Errorf("approval ID read from the message: %q", values.ApprovalID)
+	}
+}
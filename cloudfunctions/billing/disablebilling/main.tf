@@ -0,0 +1,94 @@
+# Copyright 2019 Google LLC
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+# 	https://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+resource "google_cloudfunctions_function" "disable-billing" {
+  name                  = "DisableBilling"
+  description           = "Detaches the billing account of a project with approved, sustained cryptomining findings."
+  runtime               = "go116"
+  available_memory_mb   = 128
+  source_archive_bucket = var.setup.gcf-bucket-name
+  source_archive_object = var.setup.gcf-object-name
+  timeout               = 60
+  project               = var.setup.automation-project
+  region                = var.setup.region
+  entry_point           = "DisableBilling"
+  service_account_email = var.setup.automation-service-account
+
+  event_trigger {
+    event_type = "google.pubsub.topic.publish"
+    resource   = "threat-findings-disable-billing"
+  }
+  environment_variables = {
+    GCP_PROJECT    = var.setup.automation-project
+    SRA_CONFIG_URI = var.setup.config-uri
+  }
+  timeouts {
+    create = "10m"
+    update = "10m"
+  }
+}
+
+# PubSub topic to trigger this automation.
+resource "google_pubsub_topic" "topic" {
+  name    = "threat-findings-disable-billing"
+  project = var.setup.automation-project
+}
+
+# Required to retrieve ancestry for projects within this folder.
+resource "google_folder_iam_member" "roles-viewer" {
+  count = length(var.folder-ids)
+
+  folder = "folders/${var.folder-ids[count.index]}"
+  role   = "roles/viewer"
+  member = "serviceAccount:${var.setup.automation-service-account}"
+}
+
+# Required to detach billing accounts from projects within this folder.
+resource "google_folder_iam_member" "roles-billing-project-manager" {
+  count = length(var.folder-ids)
+
+  folder = "folders/${var.folder-ids[count.index]}"
+  role   = "roles/billing.projectManager"
+  member = "serviceAccount:${var.setup.automation-service-account}"
+}
+
+# Bucket where cryptomining findings are counted. Counts older than the lifecycle age are removed.
+resource "google_storage_bucket" "counter_bucket" {
+  name               = "${var.setup.automation-project}-cryptomining-counters"
+  project            = var.setup.automation-project
+  location           = "US"
+  bucket_policy_only = true
+
+  lifecycle_rule {
+    condition {
+      age = 30
+    }
+    action {
+      type = "Delete"
+    }
+  }
+}
+
+# Required by the router to count findings before requesting approval.
+resource "google_storage_bucket_iam_member" "counter_bucket_admin" {
+  bucket = google_storage_bucket.counter_bucket.name
+  role   = "roles/storage.objectAdmin"
+  member = "serviceAccount:${var.setup.automation-service-account}"
+}
+
+resource "google_project_service" "cloudbilling_api" {
+  project                    = var.setup.automation-project
+  service                    = "cloudbilling.googleapis.com"
+  disable_dependent_services = false
+  disable_on_destroy         = false
+}
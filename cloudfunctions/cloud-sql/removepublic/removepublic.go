@@ -16,44 +16,69 @@ package removepublic
 
 import (
 	"context"
-	"log"
+	"fmt"
 
+	"github.com/googlecloudplatform/security-response-automation/logging"
 	"github.com/googlecloudplatform/security-response-automation/services"
+	sqladmin "google.golang.org/api/sqladmin/v1beta4"
 )
 
 // Values contains the required values needed for this function.
 type Values struct {
 	ProjectID, InstanceName string
-	DryRun                  bool
+	// DisablePublicIP will disable the instance's public IP rather than only removing 0.0.0.0/0.
+	DisablePublicIP bool
+	DryRun          bool
 }
 
 // Services contains the services needed for this function.
 type Services struct {
 	CloudSQL *services.CloudSQL
 	Resource *services.Resource
-	Logger   *services.Logger
 }
 
 // Execute will remove any public IPs in SQL instance found within the provided resources.
 func Execute(ctx context.Context, values *Values, services *Services) error {
-	log.Printf("getting details from Cloud SQL instance %q in project %q.", values.InstanceName, values.ProjectID)
+	logging.FromContext(ctx).Info("getting details from Cloud SQL instance %q in project %q.", values.InstanceName, values.ProjectID)
 	instance, err := services.CloudSQL.InstanceDetails(ctx, values.ProjectID, values.InstanceName)
 	if err != nil {
 		return err
 	}
 
+	if values.DisablePublicIP {
+		return disablePublicIP(ctx, values, services, instance.Settings.IpConfiguration)
+	}
 	acls := instance.Settings.IpConfiguration.AuthorizedNetworks
 	if !services.CloudSQL.IsPublic(acls) {
-		services.Logger.Info("instance %q does not have public access enabled", values.InstanceName)
+		logging.FromContext(ctx).Info("instance %q does not have public access enabled", values.InstanceName)
 		return nil
 	}
 	if values.DryRun {
-		services.Logger.Info("dry_run on, would have removed public access from Cloud SQL instance %q in project %q.", values.InstanceName, values.ProjectID)
+		logging.FromContext(ctx).Info("dry_run on, would have removed public access from Cloud SQL instance %q in project %q.", values.InstanceName, values.ProjectID)
 		return nil
 	}
 	if err := services.CloudSQL.ClosePublicAccess(ctx, values.ProjectID, values.InstanceName, acls); err != nil {
 		return err
 	}
-	services.Logger.Info("removed public access from Cloud SQL instance %q in project %q.", values.InstanceName, values.ProjectID)
+	logging.FromContext(ctx).Info("removed public access from Cloud SQL instance %q in project %q.", values.InstanceName, values.ProjectID)
+	return nil
+}
+
+func disablePublicIP(ctx context.Context, values *Values, services *Services, ipConfig *sqladmin.IpConfiguration) error {
+	if !ipConfig.Ipv4Enabled {
+		logging.FromContext(ctx).Info("instance %q does not have a public IP", values.InstanceName)
+		return nil
+	}
+	if ipConfig.PrivateNetwork == "" {
+		return fmt.Errorf("instance %q in project %q has no private IP, will not disable public IP", values.InstanceName, values.ProjectID)
+	}
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have disabled public IP of Cloud SQL instance %q in project %q.", values.InstanceName, values.ProjectID)
+		return nil
+	}
+	if err := services.CloudSQL.DisablePublicIP(ctx, values.ProjectID, values.InstanceName); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("disabled public IP of Cloud SQL instance %q in project %q.", values.InstanceName, values.ProjectID)
 	return nil
 }
@@ -93,7 +93,6 @@ func TestCloseCloudSQL(t *testing.T) {
 			if err := Execute(ctx, values, &Services{
 				CloudSQL: svcs.CloudSQL,
 				Resource: svcs.Resource,
-				Logger:   svcs.Logger,
 			}); err != nil {
 				t.Errorf("%s failed to remove public ip from instance :%q", tt.name, err)
 			}
@@ -105,13 +104,86 @@ func TestCloseCloudSQL(t *testing.T) {
 	}
 }
 
+func TestDisablePublicIP(t *testing.T) {
+	ctx := context.Background()
+	test := []struct {
+		name            string
+		ipConfiguration *sqladmin.IpConfiguration
+		dryRun          bool
+		expectedRequest *sqladmin.DatabaseInstance
+		expectedError   bool
+	}{
+		{
+			name: "disable public ip on sql instance",
+			ipConfiguration: &sqladmin.IpConfiguration{
+				Ipv4Enabled:    true,
+				PrivateNetwork: "projects/sha-resources-20191002/global/networks/default",
+			},
+			expectedRequest: &sqladmin.DatabaseInstance{
+				Name:    "public-sql-instance",
+				Project: "sha-resources-20191002",
+				Settings: &sqladmin.Settings{
+					IpConfiguration: &sqladmin.IpConfiguration{
+						Ipv4Enabled:     false,
+						ForceSendFields: []string{"Ipv4Enabled"},
+					},
+				},
+			},
+		},
+		{
+			name: "dry run does not disable public ip",
+			ipConfiguration: &sqladmin.IpConfiguration{
+				Ipv4Enabled:    true,
+				PrivateNetwork: "projects/sha-resources-20191002/global/networks/default",
+			},
+			dryRun:          true,
+			expectedRequest: nil,
+		},
+		{
+			name:            "public ip already disabled",
+			ipConfiguration: &sqladmin.IpConfiguration{Ipv4Enabled: false},
+			expectedRequest: nil,
+		},
+		{
+			name:            "instance without private ip",
+			ipConfiguration: &sqladmin.IpConfiguration{Ipv4Enabled: true},
+			expectedRequest: nil,
+			expectedError:   true,
+		},
+	}
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			svcs, sqlStub := closeSQLSetup()
+			sqlStub.InstanceDetailsResponse = &sqladmin.DatabaseInstance{
+				Name:     "public-sql-instance",
+				Project:  "sha-resources-20191002",
+				Settings: &sqladmin.Settings{IpConfiguration: tt.ipConfiguration},
+			}
+			values := &Values{
+				ProjectID:       "sha-resources-20191002",
+				InstanceName:    "public-sql-instance",
+				DisablePublicIP: true,
+				DryRun:          tt.dryRun,
+			}
+			err := Execute(ctx, values, &Services{
+				CloudSQL: svcs.CloudSQL,
+				Resource: svcs.Resource,
+			})
+			if (err != nil) != tt.expectedError {
+				t.Errorf("%s unexpected error result: %v", tt.name, err)
+			}
+			if diff := cmp.Diff(sqlStub.SavedInstanceUpdated, tt.expectedRequest); diff != "" {
+				t.Errorf("%v failed\n exp:%v\n got:%v", tt.name, tt.expectedRequest, sqlStub.SavedInstanceUpdated)
+			}
+		})
+	}
+}
+
 func closeSQLSetup() (*services.Global, *stubs.CloudSQL) {
-	loggerStub := &stubs.LoggerStub{}
-	log := services.NewLogger(loggerStub)
 	sqlStub := &stubs.CloudSQL{}
 	sql := services.NewCloudSQL(sqlStub)
 	storageStub := &stubs.StorageStub{}
 	crmStub := &stubs.ResourceManagerStub{}
 	res := services.NewResource(crmStub, storageStub)
-	return &services.Global{Logger: log, CloudSQL: sql, Resource: res}, sqlStub
+	return &services.Global{CloudSQL: sql, Resource: res}, sqlStub
 }
@@ -29,7 +29,8 @@ resource "google_cloudfunctions_function" "enforce-ssl-cloud-sql" {
     resource   = "threat-findings-require-ssl"
   }
   environment_variables = {
-    GCP_PROJECT = var.setup.automation-project
+    GCP_PROJECT    = var.setup.automation-project
+    SRA_CONFIG_URI = var.setup.config-uri
   }
   timeouts {
     create = "10m"
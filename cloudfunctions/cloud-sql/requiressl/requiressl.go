@@ -17,6 +17,7 @@ package requiressl
 import (
 	"context"
 
+	"github.com/googlecloudplatform/security-response-automation/logging"
 	"github.com/googlecloudplatform/security-response-automation/services"
 )
 
@@ -30,18 +31,17 @@ type Values struct {
 type Services struct {
 	CloudSQL *services.CloudSQL
 	Resource *services.Resource
-	Logger   *services.Logger
 }
 
 // Execute will remove any public ips in sql instance found within the provided folders.
 func Execute(ctx context.Context, values *Values, services *Services) error {
 	if values.DryRun {
-		services.Logger.Info("dry_run on, enforced ssl on sql instance %q in project %q.", values.InstanceName, values.ProjectID)
+		logging.FromContext(ctx).Info("dry_run on, enforced ssl on sql instance %q in project %q.", values.InstanceName, values.ProjectID)
 		return nil
 	}
 	if err := services.CloudSQL.RequireSSL(ctx, values.ProjectID, values.InstanceName); err != nil {
 		return err
 	}
-	services.Logger.Info("enforced ssl on sql instance %q in project %q.", values.InstanceName, values.ProjectID)
+	logging.FromContext(ctx).Info("enforced ssl on sql instance %q in project %q.", values.InstanceName, values.ProjectID)
 	return nil
 }
@@ -54,7 +54,6 @@ func TestCloudSQLRequireSSL(t *testing.T) {
 			if err := Execute(ctx, values, &Services{
 				CloudSQL: svcs.CloudSQL,
 				Resource: svcs.Resource,
-				Logger:   svcs.Logger,
 			}); err != nil {
 				t.Errorf("%s failed to enforce ssl in the instance :%q", tt.name, err)
 			}
@@ -67,12 +66,10 @@ func TestCloudSQLRequireSSL(t *testing.T) {
 }
 
 func cloudSQLRequireSSL() (*services.Global, *stubs.CloudSQL) {
-	loggerStub := &stubs.LoggerStub{}
-	log := services.NewLogger(loggerStub)
 	sqlStub := &stubs.CloudSQL{}
 	sql := services.NewCloudSQL(sqlStub)
 	storageStub := &stubs.StorageStub{}
 	crmStub := &stubs.ResourceManagerStub{}
 	res := services.NewResource(crmStub, storageStub)
-	return &services.Global{Logger: log, CloudSQL: sql, Resource: res}, sqlStub
+	return &services.Global{CloudSQL: sql, Resource: res}, sqlStub
 }
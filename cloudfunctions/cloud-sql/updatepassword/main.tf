@@ -13,7 +13,7 @@
 # limitations under the License.
 resource "google_cloudfunctions_function" "update-password" {
   name                  = "UpdatePassword"
-  description           = "Updates the root user password of a Cloud SQL instance."
+  description           = "Rotates the root user password of a Cloud SQL instance and stores it in Secret Manager."
   runtime               = "go116"
   available_memory_mb   = 128
   source_archive_bucket = var.setup.gcf-bucket-name
@@ -29,7 +29,8 @@ resource "google_cloudfunctions_function" "update-password" {
     resource   = "threat-findings-update-password"
   }
   environment_variables = {
-    GCP_PROJECT = var.setup.automation-project
+    GCP_PROJECT    = var.setup.automation-project
+    SRA_CONFIG_URI = var.setup.config-uri
   }
   timeouts {
     create = "10m"
@@ -62,6 +63,20 @@ resource "google_folder_iam_member" "roles-cloud-sql-admin" {
   member = "serviceAccount:${var.setup.automation-service-account}"
 }
 
+# Required to store the new password in Secret Manager.
+resource "google_project_iam_member" "roles-secret-manager-admin" {
+  project = var.setup.automation-project
+  role    = "roles/secretmanager.admin"
+  member  = "serviceAccount:${var.setup.automation-service-account}"
+}
+
+resource "google_project_service" "secretmanager_api" {
+  project                    = var.setup.automation-project
+  service                    = "secretmanager.googleapis.com"
+  disable_dependent_services = false
+  disable_on_destroy         = false
+}
+
 resource "google_project_service" "sqladmin_api" {
   project                    = var.setup.automation-project
   service                    = "sqladmin.googleapis.com"
@@ -16,34 +16,93 @@ package updatepassword
 
 import (
 	"context"
-	"log"
+	"strings"
 
+	"github.com/googlecloudplatform/security-response-automation/logging"
 	"github.com/googlecloudplatform/security-response-automation/services"
 )
 
+const (
+	// postgresUserName is the default user of PostgreSQL instances.
+	postgresUserName = "postgres"
+	// defaultSecretPrefix is prepended to the instance name to form the secret ID.
+	defaultSecretPrefix = "sra-sql-root-password-"
+)
+
 // Values contains the values values needed for this function.
 type Values struct {
-	ProjectID, InstanceName, Host, UserName, Password string
-	DryRun                                            bool
+	ProjectID, InstanceName, Host, UserName string
+	// SecretProjectID is the project the new password is stored in. Defaults to ProjectID.
+	SecretProjectID string
+	// SecretPrefix is prepended to the instance name to form the Secret Manager secret ID.
+	SecretPrefix string
+	DryRun       bool
+	Output       []string
+	SendGrid     struct {
+		APIKey string
+		From   string
+		To     []string
+	}
 }
 
+// generatePassword generates the new password, replaced in tests.
+var generatePassword = services.GeneratePassword
+
 // Services contains the services needed for this function.
 type Services struct {
-	CloudSQL *services.CloudSQL
-	Resource *services.Resource
-	Logger   *services.Logger
+	CloudSQL      *services.CloudSQL
+	SecretManager *services.SecretManager
+	Resource      *services.Resource
 }
 
-// Execute will update the root password for the MySQL instance found within the provided resources.
-func Execute(ctx context.Context, values *Values, services *Services) error {
-	log.Printf("updating root password for MySQL instance %q in project %q.", values.InstanceName, values.ProjectID)
+// Output contains the output of this function.
+type Output struct {
+	// SecretVersion is the resource name of the secret version holding the new password.
+	SecretVersion string
+}
+
+// Execute will rotate the root password for the Cloud SQL instance found within the provided resources.
+//
+// The new password is generated here and stored in Secret Manager before it is set on the
+// instance, so it is never lost and Secret Manager holds its only copy.
+func Execute(ctx context.Context, values *Values, services *Services) (*Output, error) {
+	instance, err := services.CloudSQL.InstanceDetails(ctx, values.ProjectID, values.InstanceName)
+	if err != nil {
+		return nil, err
+	}
+	host, userName := values.Host, values.UserName
+	if strings.HasPrefix(instance.DatabaseVersion, "POSTGRES") {
+		host, userName = "", postgresUserName
+	}
+	secretProjectID, secretID := secretLocation(values)
+	logging.FromContext(ctx).Info("updating %q password for Cloud SQL instance %q in project %q.", userName, values.InstanceName, values.ProjectID)
 	if values.DryRun {
-		services.Logger.Info("dry_run on, would have updated root password for MySQL instance %q in project %q.", values.InstanceName, values.ProjectID)
-		return nil
+		logging.FromContext(ctx).Info("dry_run on, would have updated %q password for Cloud SQL instance %q in project %q and stored it in secret %q in project %q.", userName, values.InstanceName, values.ProjectID, secretID, secretProjectID)
+		return nil, nil
+	}
+	password, err := generatePassword()
+	if err != nil {
+		return nil, err
+	}
+	version, err := services.SecretManager.StoreSecret(ctx, secretProjectID, secretID, []byte(password))
+	if err != nil {
+		return nil, err
+	}
+	if err := services.CloudSQL.UpdateUserPassword(ctx, values.ProjectID, values.InstanceName, host, userName, password); err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("updated %q password for Cloud SQL instance %q in project %q, stored in %q.", userName, values.InstanceName, values.ProjectID, version)
+	return &Output{SecretVersion: version}, nil
+}
+
+func secretLocation(values *Values) (string, string) {
+	projectID := values.SecretProjectID
+	if projectID == "" {
+		projectID = values.ProjectID
 	}
-	if err := services.CloudSQL.UpdateUserPassword(ctx, values.ProjectID, values.InstanceName, values.Host, values.UserName, values.Password); err != nil {
-		return err
+	prefix := values.SecretPrefix
+	if prefix == "" {
+		prefix = defaultSecretPrefix
 	}
-	services.Logger.Info("updated root password for MySQL instance %q in project %q.", values.InstanceName, values.ProjectID)
-	return nil
+	return projectID, prefix + values.InstanceName
 }
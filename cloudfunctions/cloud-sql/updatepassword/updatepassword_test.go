@@ -16,6 +16,7 @@ package updatepassword
 
 import (
 	"context"
+	"encoding/base64"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -26,49 +27,96 @@ import (
 
 func TestUpdatePassword(t *testing.T) {
 	ctx := context.Background()
+	const password = "4a542dd833d9f8a7600b13cd281d00cf2b0a5610e825ff931260b2911bef95b5"
+	generatePassword = func() (string, error) { return password, nil }
+	defer func() { generatePassword = services.GeneratePassword }()
 	test := []struct {
-		name            string
-		expectedRequest *sqladmin.User
+		name                  string
+		databaseVersion       string
+		secretProjectID       string
+		secretExists          bool
+		dryRun                bool
+		expectedRequest       *sqladmin.User
+		expectedUserName      string
+		expectedUserHost      string
+		expectedCreatedSecret []string
+		expectedSecrets       map[string]string
+		expectedOutput        *Output
 	}{
 		{
-			name: "update root password",
-			expectedRequest: &sqladmin.User{
-				Password: "4a542dd833d9f8a7600b13cd281d00cf2b0a5610e825ff931260b2911bef95b5",
+			name:                  "update root password",
+			databaseVersion:       "MYSQL_5_7",
+			expectedRequest:       &sqladmin.User{Password: password},
+			expectedUserName:      "root",
+			expectedUserHost:      "%",
+			expectedCreatedSecret: []string{"projects/threat-auto-tests-07102019/secrets/sra-sql-root-password-test-no-password"},
+			expectedSecrets: map[string]string{
+				"projects/threat-auto-tests-07102019/secrets/sra-sql-root-password-test-no-password": base64.StdEncoding.EncodeToString([]byte(password)),
 			},
+			expectedOutput: &Output{SecretVersion: "projects/threat-auto-tests-07102019/secrets/sra-sql-root-password-test-no-password/versions/1"},
+		},
+		{
+			name:             "update postgres password in existing secret",
+			databaseVersion:  "POSTGRES_11",
+			secretProjectID:  "secrets-project",
+			secretExists:     true,
+			expectedRequest:  &sqladmin.User{Password: password},
+			expectedUserName: "postgres",
+			expectedUserHost: "",
+			expectedSecrets: map[string]string{
+				"projects/secrets-project/secrets/sra-sql-root-password-test-no-password": base64.StdEncoding.EncodeToString([]byte(password)),
+			},
+			expectedOutput: &Output{SecretVersion: "projects/secrets-project/secrets/sra-sql-root-password-test-no-password/versions/1"},
+		},
+		{
+			name:            "dry run",
+			databaseVersion: "MYSQL_5_7",
+			dryRun:          true,
 		},
 	}
 	for _, tt := range test {
 		t.Run(tt.name, func(t *testing.T) {
-			svcs, sqlStub := updatePasswordSetup()
+			svcs, sqlStub, smStub := updatePasswordSetup()
+			sqlStub.InstanceDetailsResponse = &sqladmin.DatabaseInstance{DatabaseVersion: tt.databaseVersion}
+			smStub.SecretExists = tt.secretExists
 			values := &Values{
-				ProjectID:    "threat-auto-tests-07102019",
-				InstanceName: "test-no-password",
-				Host:         "%",
-				UserName:     "root",
-				Password:     "4a542dd833d9f8a7600b13cd281d00cf2b0a5610e825ff931260b2911bef95b5",
+				ProjectID:       "threat-auto-tests-07102019",
+				InstanceName:    "test-no-password",
+				Host:            "%",
+				UserName:        "root",
+				SecretProjectID: tt.secretProjectID,
+				DryRun:          tt.dryRun,
 			}
-			if err := Execute(ctx, values, &Services{
-				CloudSQL: svcs.CloudSQL,
-				Resource: svcs.Resource,
-				Logger:   svcs.Logger,
-			}); err != nil {
+			output, err := Execute(ctx, values, svcs)
+			if err != nil {
 				t.Errorf("%s failed to update root password for instance :%q", tt.name, err)
 			}
-
 			if diff := cmp.Diff(sqlStub.UpdatedUser, tt.expectedRequest); diff != "" {
-				t.Errorf("%v failed\n exp:%v\n got:%v", tt.name, tt.expectedRequest, sqlStub.SavedInstanceUpdated)
+				t.Errorf("%v failed\n exp:%v\n got:%v", tt.name, tt.expectedRequest, sqlStub.UpdatedUser)
+			}
+			if sqlStub.UpdatedUserName != tt.expectedUserName || sqlStub.UpdatedUserHost != tt.expectedUserHost {
+				t.Errorf("%v failed exp user:%q@%q got:%q@%q", tt.name, tt.expectedUserName, tt.expectedUserHost, sqlStub.UpdatedUserName, sqlStub.UpdatedUserHost)
+			}
+			if diff := cmp.Diff(smStub.CreatedSecrets, tt.expectedCreatedSecret); diff != "" {
+				t.Errorf("%v failed, difference in created secrets: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(smStub.SavedSecretVersions, tt.expectedSecrets); diff != "" {
+				t.Errorf("%v failed, difference in secret versions: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(output, tt.expectedOutput); diff != "" {
+				t.Errorf("%v failed, difference in output: %+v", tt.name, diff)
 			}
 		})
 	}
 }
 
-func updatePasswordSetup() (*services.Global, *stubs.CloudSQL) {
-	loggerStub := &stubs.LoggerStub{}
-	log := services.NewLogger(loggerStub)
+func updatePasswordSetup() (*Services, *stubs.CloudSQL, *stubs.SecretManagerStub) {
 	sqlStub := &stubs.CloudSQL{}
 	sql := services.NewCloudSQL(sqlStub)
+	smStub := &stubs.SecretManagerStub{}
+	sm := services.NewSecretManager(smStub)
 	storageStub := &stubs.StorageStub{}
 	crmStub := &stubs.ResourceManagerStub{}
 	res := services.NewResource(crmStub, storageStub)
-	return &services.Global{Logger: log, CloudSQL: sql, Resource: res}, sqlStub
+	return &Services{CloudSQL: sql, Resource: res, SecretManager: sm}, sqlStub, smStub
 }
@@ -0,0 +1,94 @@
+package stoproguejob
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// Values contains the required values needed for this function.
+//
+// One of Cluster, DataprocJob or DataflowJob is set depending on what the finding names.
+type Values struct {
+	ProjectID string
+	// Region is the Dataproc region or Dataflow location of the resource.
+	Region        string
+	Cluster       string
+	DataprocJob   string
+	DataflowJob   string
+	AllowProjects []string
+	DryRun        bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Dataproc *services.Dataproc
+	Dataflow *services.Dataflow
+}
+
+// Execute will delete the Dataproc cluster, cancel the Dataproc job or cancel the Dataflow job named
+// in the finding.
+//
+// Projects within the allow list are expected to run these workloads and are left untouched.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	if allowed(values.ProjectID, values.AllowProjects) {
+		logging.FromContext(ctx).Info("project %q is allowed, skipping", values.ProjectID)
+		return nil
+	}
+	switch {
+	case values.Cluster != "":
+		if values.DryRun {
+			logging.FromContext(ctx).Info("dry_run on, would have deleted dataproc cluster %q in project %q", values.Cluster, values.ProjectID)
+			return nil
+		}
+		if err := services.Dataproc.DeleteCluster(ctx, values.ProjectID, values.Region, values.Cluster); err != nil {
+			return err
+		}
+		logging.FromContext(ctx).Info("deleted dataproc cluster %q in project %q", values.Cluster, values.ProjectID)
+	case values.DataprocJob != "":
+		if values.DryRun {
+			logging.FromContext(ctx).Info("dry_run on, would have cancelled dataproc job %q in project %q", values.DataprocJob, values.ProjectID)
+			return nil
+		}
+		if err := services.Dataproc.CancelJob(ctx, values.ProjectID, values.Region, values.DataprocJob); err != nil {
+			return err
+		}
+		logging.FromContext(ctx).Info("cancelled dataproc job %q in project %q", values.DataprocJob, values.ProjectID)
+	case values.DataflowJob != "":
+		if values.DryRun {
+			logging.FromContext(ctx).Info("dry_run on, would have cancelled dataflow job %q in project %q", values.DataflowJob, values.ProjectID)
+			return nil
+		}
+		if err := services.Dataflow.CancelJob(ctx, values.ProjectID, values.Region, values.DataflowJob); err != nil {
+			return err
+		}
+		logging.FromContext(ctx).Info("cancelled dataflow job %q in project %q", values.DataflowJob, values.ProjectID)
+	default:
+		logging.FromContext(ctx).Info("no dataproc or dataflow resource found in project %q", values.ProjectID)
+	}
+	return nil
+}
+
+func allowed(projectID string, allowProjects []string) bool {
+	for _, p := range allowProjects {
+		if p == projectID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package stoproguejob
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	dataflow "google.golang.org/api/dataflow/v1b3"
+)
+
+func TestStopRogueJob(t *testing.T) {
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name                    string
+		values                  *Values
+		expectedDeletedClusters []string
+		expectedCancelledJobs   []string
+		expectedDataflowJobs    []*dataflow.Job
+	}{
+		{
+			name:                    "delete dataproc cluster",
+			values:                  &Values{Cluster: "miner-cluster"},
+			expectedDeletedClusters: []string{"miner-cluster"},
+		},
+		{
+			name:                  "cancel dataproc job",
+			values:                &Values{DataprocJob: "job-1234"},
+			expectedCancelledJobs: []string{"job-1234"},
+		},
+		{
+			name:                 "cancel dataflow job",
+			values:               &Values{DataflowJob: "2020-06-10_10_00_00-1234"},
+			expectedDataflowJobs: []*dataflow.Job{{RequestedState: "JOB_STATE_CANCELLED"}},
+		},
+		{
+			name:   "allowed project",
+			values: &Values{Cluster: "etl-cluster", AllowProjects: []string{"other-project", "project-id"}},
+		},
+		{
+			name:   "dry run",
+			values: &Values{DataflowJob: "2020-06-10_10_00_00-1234", DryRun: true},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dataprocStub := &stubs.DataprocStub{}
+			dataflowStub := &stubs.DataflowStub{}
+			values := tt.values
+			values.ProjectID = "project-id"
+			values.Region = "us-central1"
+			if err := Execute(ctx, values, &Services{
+				Dataproc: services.NewDataproc(dataprocStub),
+				Dataflow: services.NewDataflow(dataflowStub),
+			}); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(tt.expectedDeletedClusters, dataprocStub.DeletedClusters); diff != "" {
+				t.Errorf("%s failed, deleted clusters difference: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(tt.expectedCancelledJobs, dataprocStub.CancelledJobs); diff != "" {
+				t.Errorf("%s failed, cancelled jobs difference: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(tt.expectedDataflowJobs, dataflowStub.UpdatedJobs); diff != "" {
+				t.Errorf("%s failed, dataflow jobs difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
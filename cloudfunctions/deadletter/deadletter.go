@@ -0,0 +1,47 @@
+package deadletter
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// Values contains the required values needed for this function, the failed remediation.
+type Values struct {
+	services.DeadLetter
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	DeadLetters *services.DeadLetters
+}
+
+// Execute keeps the failed remediation until it is replayed.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	l := &values.DeadLetter
+	if err := services.DeadLetters.Store(ctx, l, time.Now()); err != nil {
+		return err
+	}
+	if l.NextAttempt.IsZero() {
+		logging.FromContext(ctx).Error("remediation %q of %q failed after %d replays, replay it on demand: %s", l.ID, l.Action, l.Attempts, l.Error)
+		return nil
+	}
+	logging.FromContext(ctx).Warning("remediation %q of %q failed, replaying it at %s: %s", l.ID, l.Action, l.NextAttempt.Format(time.RFC3339), l.Error)
+	return nil
+}
@@ -0,0 +1,52 @@
+package deadletter
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+func TestDeadLetter(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		attempts   int
+		wantReplay bool
+	}{
+		{name: "first failure", wantReplay: true},
+		{name: "attempts exhausted", attempts: 5},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			fsStub := &stubs.FirestoreStub{}
+			deadLetters := services.NewDeadLetters(fsStub, "automation-project", services.DeadLetterConfig{})
+			values := &Values{services.DeadLetter{ID: "r1", Action: "OpenFirewall", Topic: "threat-findings-open-firewall", Attempts: tt.attempts, Failed: time.Now()}}
+			if err := Execute(ctx, values, &Services{DeadLetters: deadLetters}); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			letters, err := deadLetters.List(ctx)
+			if err != nil || len(letters) != 1 {
+				t.Fatalf("%s failed: got dead letters %v, %v", tt.name, letters, err)
+			}
+			if replay := !letters[0].NextAttempt.IsZero(); replay != tt.wantReplay {
+				t.Errorf("%s failed: got replay %t want %t", tt.name, replay, tt.wantReplay)
+			}
+		})
+	}
+}
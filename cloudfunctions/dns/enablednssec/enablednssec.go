@@ -0,0 +1,90 @@
+package enablednssec
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// skippedMark is the security mark written to the finding when a zone is not modified.
+const skippedMark = "sra-enable-dnssec-skipped"
+
+// Values contains the required values needed for this function.
+type Values struct {
+	ProjectID   string
+	ManagedZone string
+	FindingName string
+	AllowZones  []string
+	DryRun      bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	DNS                   *services.DNS
+	SecurityCommandCenter *services.CommandCenter
+}
+
+// Execute will turn DNSSEC on for the managed zone.
+//
+// Zones within the allow list are left untouched and the finding is marked as skipped.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	if allowed(values.ManagedZone, values.AllowZones) {
+		logging.FromContext(ctx).Info("managed zone %q in project %q is allowed, skipping", values.ManagedZone, values.ProjectID)
+		return markSkipped(ctx, values.FindingName, services)
+	}
+	zone, err := services.DNS.ManagedZone(ctx, values.ProjectID, values.ManagedZone)
+	if err != nil {
+		return err
+	}
+	if zone.Visibility == "private" {
+		logging.FromContext(ctx).Info("managed zone %q in project %q is private and does not support dnssec", values.ManagedZone, values.ProjectID)
+		return nil
+	}
+	if zone.DnssecConfig != nil && zone.DnssecConfig.State == "on" {
+		logging.FromContext(ctx).Info("dnssec already enabled on managed zone %q in project %q", values.ManagedZone, values.ProjectID)
+		return nil
+	}
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have enabled dnssec on managed zone %q in project %q", values.ManagedZone, values.ProjectID)
+		return nil
+	}
+	if err := services.DNS.EnableDNSSEC(ctx, values.ProjectID, values.ManagedZone); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("enabled dnssec on managed zone %q in project %q", values.ManagedZone, values.ProjectID)
+	return nil
+}
+
+func allowed(zone string, allowZones []string) bool {
+	for _, z := range allowZones {
+		if z == zone {
+			return true
+		}
+	}
+	return false
+}
+
+func markSkipped(ctx context.Context, findingName string, services *Services) error {
+	if findingName == "" {
+		return nil
+	}
+	m := map[string]string{skippedMark: "allow_zones"}
+	if _, err := services.SecurityCommandCenter.AddSecurityMarks(ctx, findingName, m); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package enablednssec
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	dns "google.golang.org/api/dns/v1"
+)
+
+func TestEnableDNSSEC(t *testing.T) {
+	ctx := context.Background()
+	test := []struct {
+		name          string
+		zone          *dns.ManagedZone
+		allowZones    []string
+		dryRun        bool
+		expectedPatch *dns.ManagedZone
+		expectedMarks map[string]string
+	}{
+		{
+			name:          "enable dnssec",
+			zone:          &dns.ManagedZone{Name: "public-zone", Visibility: "public"},
+			expectedPatch: &dns.ManagedZone{DnssecConfig: &dns.ManagedZoneDnsSecConfig{State: "on"}},
+		},
+		{
+			name:          "enable dnssec on transferring zone",
+			zone:          &dns.ManagedZone{Name: "public-zone", Visibility: "public", DnssecConfig: &dns.ManagedZoneDnsSecConfig{State: "transfer"}},
+			expectedPatch: &dns.ManagedZone{DnssecConfig: &dns.ManagedZoneDnsSecConfig{State: "on"}},
+		},
+		{
+			name: "already enabled",
+			zone: &dns.ManagedZone{Name: "public-zone", Visibility: "public", DnssecConfig: &dns.ManagedZoneDnsSecConfig{State: "on"}},
+		},
+		{
+			name: "private zone",
+			zone: &dns.ManagedZone{Name: "public-zone", Visibility: "private"},
+		},
+		{
+			name:          "allowed zone",
+			zone:          &dns.ManagedZone{Name: "public-zone", Visibility: "public"},
+			allowZones:    []string{"public-zone"},
+			expectedMarks: map[string]string{"sra-enable-dnssec-skipped": "allow_zones"},
+		},
+		{
+			name:   "dry run",
+			zone:   &dns.ManagedZone{Name: "public-zone", Visibility: "public"},
+			dryRun: true,
+		},
+	}
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			svcs, dnsStub, sccStub := enableDNSSECSetup()
+			dnsStub.StubbedManagedZone = tt.zone
+			values := &Values{
+				ProjectID:   "test-project",
+				ManagedZone: "public-zone",
+				FindingName: "organizations/123/sources/456/findings/789",
+				AllowZones:  tt.allowZones,
+				DryRun:      tt.dryRun,
+			}
+			if err := Execute(ctx, values, svcs); err != nil {
+				t.Errorf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(dnsStub.SavedPatch, tt.expectedPatch); diff != "" {
+				t.Errorf("%v failed, difference: %+v", tt.name, diff)
+			}
+			var marks map[string]string
+			if r := sccStub.GetUpdateSecurityMarksRequest; r != nil {
+				marks = r.GetSecurityMarks().GetMarks()
+			}
+			if diff := cmp.Diff(marks, tt.expectedMarks); diff != "" {
+				t.Errorf("%v failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func enableDNSSECSetup() (*Services, *stubs.DNSStub, *stubs.SecurityCommandCenterStub) {
+	dnsStub := &stubs.DNSStub{}
+	sccStub := &stubs.SecurityCommandCenterStub{}
+	return &Services{DNS: services.NewDNS(dnsStub), SecurityCommandCenter: services.NewCommandCenter(sccStub)}, dnsStub, sccStub
+}
@@ -0,0 +1,85 @@
+package expireexemptions
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/pkg/errors"
+)
+
+// Values contains the required values needed for this function.
+type Values struct {
+	// RouterTopic is the topic suppressed findings that are still active are sent back to.
+	RouterTopic string
+	DryRun      bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Exemptions            *services.Exemptions
+	SecurityCommandCenter *services.CommandCenter
+	PubSub                *services.PubSub
+}
+
+// Execute clears the exemption marks that expired and sends the findings they suppressed that are
+// still active back to the router.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	if values.RouterTopic == "" {
+		return errors.New("no router topic to re-queue findings to")
+	}
+	suppressed, err := services.Exemptions.Suppressed(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, s := range suppressed {
+		if now.Before(s.Expires) {
+			continue
+		}
+		if values.DryRun {
+			logging.FromContext(ctx).Info("dry_run on, would have released finding %q suppressed until %s", s.FindingName, s.Expires.Format(time.RFC3339))
+			continue
+		}
+		if err := release(ctx, values, services, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func release(ctx context.Context, values *Values, svcs *Services, s *services.Suppressed) error {
+	if s.Mark != "" {
+		if err := svcs.SecurityCommandCenter.ClearExemption(ctx, &services.AssetExemption{Asset: s.Asset, Mark: s.Mark}); err != nil {
+			return err
+		}
+		logging.FromContext(ctx).Info("cleared expired exemption %q of %q", s.Mark, s.Asset)
+	}
+	active, err := svcs.SecurityCommandCenter.FindingActive(ctx, s.FindingName)
+	if err != nil {
+		return err
+	}
+	if active {
+		if _, err := svcs.PubSub.Publish(ctx, values.RouterTopic, &pubsub.Message{Data: s.Finding}); err != nil {
+			return errors.Wrapf(err, "failed to re-queue finding %q", s.FindingName)
+		}
+		logging.FromContext(ctx).Info("re-queued finding %q suppressed until %s", s.FindingName, s.Expires.Format(time.RFC3339))
+	}
+	return svcs.Exemptions.Release(ctx, s)
+}
@@ -0,0 +1,87 @@
+package expireexemptions
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	sccpb "google.golang.org/genproto/googleapis/cloud/securitycenter/v1beta1"
+)
+
+func TestExpireExemptions(t *testing.T) {
+	const (
+		name    = "organizations/456/sources/1/findings/2"
+		finding = `{"finding": {"name": "organizations/456/sources/1/findings/2"}}`
+	)
+	tests := []struct {
+		name         string
+		expires      time.Time
+		state        sccpb.Finding_State
+		dryRun       bool
+		wantRequeued bool
+		wantCleared  bool
+		wantReleased bool
+	}{
+		{name: "not expired", expires: time.Now().Add(time.Hour), state: sccpb.Finding_ACTIVE},
+		{name: "expired and active", expires: time.Now().Add(-time.Hour), state: sccpb.Finding_ACTIVE, wantRequeued: true, wantCleared: true, wantReleased: true},
+		{name: "expired and inactive", expires: time.Now().Add(-time.Hour), state: sccpb.Finding_INACTIVE, wantCleared: true, wantReleased: true},
+		{name: "dry run", expires: time.Now().Add(-time.Hour), state: sccpb.Finding_ACTIVE, dryRun: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			fsStub := &stubs.FirestoreStub{}
+			psStub := &stubs.PubSubStub{}
+			sccStub := &stubs.SecurityCommandCenterStub{StubbedFindings: []*sccpb.Finding{{Name: name, State: tt.state}}}
+			exemptions := services.NewExemptions(fsStub, "automation-project")
+			if err := exemptions.Suppress(ctx, &services.Suppressed{
+				FindingName: name,
+				Finding:     []byte(finding),
+				Asset:       "organizations/456/assets/789",
+				Mark:        "allow_public_bucket_acl",
+				Expires:     tt.expires,
+			}); err != nil {
+				t.Fatalf("%s failed to suppress: %q", tt.name, err)
+			}
+			if err := Execute(ctx, &Values{RouterTopic: "threat-findings-router", DryRun: tt.dryRun}, &Services{
+				Exemptions:            exemptions,
+				SecurityCommandCenter: services.NewCommandCenter(sccStub),
+				PubSub:                services.NewPubSub(psStub),
+			}); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if requeued := psStub.PublishedMessage != nil; requeued != tt.wantRequeued {
+				t.Errorf("%s failed: re-queued %t want %t", tt.name, requeued, tt.wantRequeued)
+			}
+			if tt.wantRequeued && string(psStub.PublishedMessage.Data) != finding {
+				t.Errorf("%s failed: re-queued %q", tt.name, psStub.PublishedMessage.Data)
+			}
+			if cleared := sccStub.GetUpdateSecurityMarksRequest != nil; cleared != tt.wantCleared {
+				t.Errorf("%s failed: cleared mark %t want %t", tt.name, cleared, tt.wantCleared)
+			}
+			remaining, err := exemptions.Suppressed(ctx)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if released := len(remaining) == 0; released != tt.wantReleased {
+				t.Errorf("%s failed: released %t want %t", tt.name, released, tt.wantReleased)
+			}
+		})
+	}
+}
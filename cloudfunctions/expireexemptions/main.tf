@@ -0,0 +1,60 @@
+# Package automation contains the Cloud Function code to automate actions.
+
+# Copyright 2019 Google LLC
+
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+
+# 	https://www.apache.org/licenses/LICENSE-2.0
+
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+resource "google_cloudfunctions_function" "expire_exemptions_function" {
+  name                  = "ExpireExemptions"
+  description           = "Clears expired exemptions and re-queues the findings they suppressed."
+  runtime               = "go116"
+  available_memory_mb   = 128
+  source_archive_bucket = var.setup.gcf-bucket-name
+  source_archive_object = var.setup.gcf-object-name
+  timeout               = 60
+  project               = var.setup.automation-project
+  region                = var.setup.region
+  entry_point           = "ExpireExemptions"
+  service_account_email = var.setup.automation-service-account
+
+  event_trigger {
+    event_type = "google.pubsub.topic.publish"
+    resource   = "threat-findings-expire-exemptions"
+  }
+  environment_variables = {
+    GCP_PROJECT    = var.setup.automation-project
+    SRA_CONFIG_URI = var.setup.config-uri
+  }
+  timeouts {
+    create = "10m"
+    update = "10m"
+  }
+}
+
+# PubSub topic to trigger this automation.
+resource "google_pubsub_topic" "topic" {
+  name    = "threat-findings-expire-exemptions"
+  project = var.setup.automation-project
+}
+
+# Periodically clears expired exemptions.
+resource "google_cloud_scheduler_job" "expire_exemptions_job" {
+  name     = "expire-exemptions"
+  project  = var.setup.automation-project
+  region   = var.setup.region
+  schedule = var.schedule
+
+  pubsub_target {
+    topic_name = google_pubsub_topic.topic.id
+    data       = base64encode(jsonencode({ RouterTopic = var.setup.router-topic-name, DryRun = false }))
+  }
+}
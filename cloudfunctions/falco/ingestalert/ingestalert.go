@@ -0,0 +1,66 @@
+package ingestalert
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"crypto/subtle"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/providers/falco/falcoalert"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/pkg/errors"
+)
+
+// ErrUnauthorized is returned when the webhook request doesn't carry the configured token.
+var ErrUnauthorized = errors.New("invalid falco webhook token")
+
+// Values contains the required values needed for this function, taken from the webhook request.
+type Values struct {
+	// Alert is the Falco alert as JSON.
+	Alert []byte
+	// Cluster is the GKE cluster Falco runs in, such as "projects/p/locations/l/clusters/c".
+	Cluster string
+	// Token is the bearer token of the request.
+	Token string
+	// Topic is the router topic the normalized finding is published to.
+	Topic string
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	PubSub *services.PubSub
+}
+
+// Execute normalizes the Falco alert into a finding and publishes it to the router, if the
+// request carries the token configured for the webhook.
+func Execute(ctx context.Context, values *Values, token string, services *Services) error {
+	if token == "" || subtle.ConstantTimeCompare([]byte(values.Token), []byte(token)) != 1 {
+		return ErrUnauthorized
+	}
+	if values.Topic == "" {
+		return errors.New("no router topic configured")
+	}
+	finding, err := falcoalert.Normalize(values.Alert, values.Cluster)
+	if err != nil {
+		return err
+	}
+	if _, err := services.PubSub.Publish(ctx, values.Topic, &pubsub.Message{Data: finding}); err != nil {
+		return errors.Wrapf(err, "failed to publish falco alert to %q", values.Topic)
+	}
+	logging.FromContext(ctx).Info("forwarded falco alert from cluster %q to topic %q", values.Cluster, values.Topic)
+	return nil
+}
@@ -0,0 +1,63 @@
+package ingestalert
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/findings/fixtures"
+	"github.com/googlecloudplatform/security-response-automation/providers/falco/falcoalert"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+func TestIngestAlert(t *testing.T) {
+	const cluster = "projects/aerial-jigsaw-235219/zones/us-central1-a/clusters/ctd-cluster"
+	alert := fixtures.ReadFormat(t, "falco_terminal_shell_in_container", fixtures.Falco)
+	for _, tt := range []struct {
+		name, token, configured, cluster string
+		wantErr                          bool
+	}{
+		{name: "forward alert", token: "s3cret", configured: "s3cret", cluster: cluster},
+		{name: "wrong token", token: "guess", configured: "s3cret", cluster: cluster, wantErr: true},
+		{name: "no token configured", cluster: cluster, wantErr: true},
+		{name: "invalid cluster", token: "s3cret", configured: "s3cret", cluster: "ctd-cluster", wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			psStub := &stubs.PubSubStub{}
+			values := &Values{Alert: alert, Cluster: tt.cluster, Token: tt.token, Topic: "threat-findings-router"}
+			err := Execute(context.Background(), values, tt.configured, &Services{PubSub: services.NewPubSub(psStub)})
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("%s failed: got no error", tt.name)
+				}
+				if psStub.PublishedMessage != nil {
+					t.Errorf("%s failed, not supposed to publish", tt.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if psStub.PublishedMessage == nil {
+				t.Fatalf("%s failed, alert not published", tt.name)
+			}
+			if name := (&falcoalert.Finding{}).Name(psStub.PublishedMessage.Data); name != "falco_terminal_shell_in_container" {
+				t.Errorf("%s failed: got:%q want:%q", tt.name, name, "falco_terminal_shell_in_container")
+			}
+		})
+	}
+}
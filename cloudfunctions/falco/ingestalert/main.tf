@@ -0,0 +1,48 @@
+# Package automation contains the Cloud Function code to automate actions.
+
+# Copyright 2019 Google LLC
+
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+
+# 	https://www.apache.org/licenses/LICENSE-2.0
+
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+resource "google_cloudfunctions_function" "falco_webhook_function" {
+  name                  = "FalcoWebhook"
+  description           = "Normalizes the alerts Falco sends from GKE clusters into findings and sends them to the router."
+  runtime               = "go116"
+  available_memory_mb   = 128
+  source_archive_bucket = var.setup.gcf-bucket-name
+  source_archive_object = var.setup.gcf-object-name
+  timeout               = 60
+  project               = var.setup.automation-project
+  region                = var.setup.region
+  entry_point           = "FalcoWebhook"
+  service_account_email = var.setup.automation-service-account
+  trigger_http          = true
+
+  environment_variables = {
+    GCP_PROJECT    = var.setup.automation-project
+    SRA_CONFIG_URI = var.setup.config-uri
+    OUTPUT_TOPIC   = var.setup.router-topic-name
+  }
+  timeouts {
+    create = "10m"
+    update = "10m"
+  }
+}
+
+# Falco calls the function without Google credentials, requests carry the configured token instead.
+resource "google_cloudfunctions_function_iam_member" "falco_webhook_invoker" {
+  project        = var.setup.automation-project
+  region         = var.setup.region
+  cloud_function = google_cloudfunctions_function.falco_webhook_function.name
+  role           = "roles/cloudfunctions.invoker"
+  member         = "allUsers"
+}
@@ -0,0 +1,3 @@
+output "url" {
+  value = google_cloudfunctions_function.falco_webhook_function.https_trigger_url
+}
@@ -20,6 +20,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/filter/internal/storage"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+	"github.com/googlecloudplatform/security-response-automation/logging"
 	"github.com/googlecloudplatform/security-response-automation/services"
 	"github.com/open-policy-agent/opa/ast"
 	"github.com/open-policy-agent/opa/rego"
@@ -33,7 +35,6 @@ const queryStringPrefix = "data.sra.filter"
 // Services contains the services needed for this function.
 type Services struct {
 	PubSub                *services.PubSub
-	Logger                *services.Logger
 	SecurityCommandCenter *services.CommandCenter
 }
 
@@ -49,12 +50,13 @@ type notification struct {
 
 // Execute will first check the raw finding against the user-supplied Rego policies
 // then if it should be filtered, update the finding, otherwise pass it along to the
-// router cloud function.
+// router cloud function. Findings in the v1 notification formats are normalized first
+// so policies and the router see the same fields.
 func Execute(ctx context.Context, m pubsub.Message, svcs *Services) (err error) {
-	raw := m.Data
+	raw := findings.Normalize(m.Data)
 	var msg notification
 	if err = json.Unmarshal(raw, &msg); err != nil {
-		svcs.Logger.Info("Only SCC Notification format is supported. This message will not be filtered.")
+		logging.FromContext(ctx).Info("Only SCC Notification format is supported. This message will not be filtered.")
 	} else {
 		// Iterate through rego filenames and content that are generated into code
 		// in the internal/storage package. This happens as part of the terraform
@@ -68,20 +70,20 @@ func Execute(ctx context.Context, m pubsub.Message, svcs *Services) (err error)
 			if exception {
 				err = updateFinding(ctx, svcs, filterName, msg.Finding.Name)
 				if err != nil {
-					svcs.Logger.Error("Failed to update finding %s", msg.Finding.Name)
+					logging.FromContext(ctx).Error("Failed to update finding %s", msg.Finding.Name)
 					return err
 				}
-				svcs.Logger.Info("Marked finding %s with filter %s", msg.Finding.Name, filterName)
+				logging.FromContext(ctx).Info("Marked finding %s with filter %s", msg.Finding.Name, filterName)
 				return nil
 			}
 		}
 	}
 	topic, err := publish(ctx, svcs, raw)
 	if err != nil {
-		svcs.Logger.Error("Failed to publish to %s", topic)
+		logging.FromContext(ctx).Error("Failed to publish to %s", topic)
 		return err
 	}
-	svcs.Logger.Info("Forwarded finding to topic %s", topic)
+	logging.FromContext(ctx).Info("Forwarded finding to topic %s", topic)
 
 	return nil
 }
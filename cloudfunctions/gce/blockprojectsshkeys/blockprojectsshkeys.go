@@ -0,0 +1,75 @@
+package blockprojectsshkeys
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"regexp"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/pkg/errors"
+)
+
+// Values contains the required values needed for this function.
+//
+// When DenyPattern is set, project-wide SSH keys matching the regular expression are also removed.
+type Values struct {
+	ProjectID, InstanceZone, InstanceID string
+	DenyPattern                         string
+	DryRun                              bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Host     *services.Host
+	Resource *services.Resource
+}
+
+// Execute blocks project-wide SSH keys on a GCE instance and optionally purges matching project keys.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	var pattern *regexp.Regexp
+	if values.DenyPattern != "" {
+		p, err := regexp.Compile(values.DenyPattern)
+		if err != nil {
+			return errors.Wrapf(err, "invalid deny pattern %q", values.DenyPattern)
+		}
+		pattern = p
+	}
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have blocked project ssh keys for instance %q, in zone %q in project %q.", values.InstanceID, values.InstanceZone, values.ProjectID)
+		if pattern != nil {
+			logging.FromContext(ctx).Info("dry_run on, would have removed project ssh keys matching %q in project %q.", values.DenyPattern, values.ProjectID)
+		}
+		return nil
+	}
+	blocked, err := services.Host.BlockProjectSSHKeys(ctx, values.ProjectID, values.InstanceZone, values.InstanceID)
+	if err != nil {
+		return errors.Wrap(err, "failed to block project ssh keys")
+	}
+	if blocked {
+		logging.FromContext(ctx).Info("blocked project ssh keys for instance %q, in zone %q in project %q.", values.InstanceID, values.InstanceZone, values.ProjectID)
+	} else {
+		logging.FromContext(ctx).Info("project ssh keys already blocked for instance %q, in zone %q in project %q.", values.InstanceID, values.InstanceZone, values.ProjectID)
+	}
+	if pattern == nil {
+		return nil
+	}
+	removed, err := services.Host.RemoveProjectSSHKeys(ctx, values.ProjectID, pattern)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove project ssh keys")
+	}
+	logging.FromContext(ctx).Info("removed %d project ssh keys matching %q in project %q.", len(removed), values.DenyPattern, values.ProjectID)
+	return nil
+}
@@ -0,0 +1,143 @@
+package blockprojectsshkeys
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	compute "google.golang.org/api/compute/v1"
+)
+
+func TestBlockProjectSSHKeys(t *testing.T) {
+	ctx := context.Background()
+	str := func(s string) *string { return &s }
+	projectKeys := "alice:ssh-rsa AAAA alice@example.com\nmallory:ssh-rsa BBBB mallory@evil.com\nbob:ssh-rsa CCCC bob@example.com"
+	test := []struct {
+		name                     string
+		instance                 *compute.Instance
+		denyPattern              string
+		dryRun                   bool
+		expectedInstanceMetadata *compute.Metadata
+		expectedProjectMetadata  *compute.Metadata
+	}{
+		{
+			name:     "block project ssh keys",
+			instance: &compute.Instance{Metadata: &compute.Metadata{Fingerprint: "abc"}},
+			expectedInstanceMetadata: &compute.Metadata{
+				Fingerprint: "abc",
+				Items:       []*compute.MetadataItems{{Key: "block-project-ssh-keys", Value: str("true")}},
+			},
+		},
+		{
+			name: "update existing metadata item",
+			instance: &compute.Instance{Metadata: &compute.Metadata{
+				Items: []*compute.MetadataItems{{Key: "block-project-ssh-keys", Value: str("false")}},
+			}},
+			expectedInstanceMetadata: &compute.Metadata{
+				Items: []*compute.MetadataItems{{Key: "block-project-ssh-keys", Value: str("true")}},
+			},
+		},
+		{
+			name: "already blocked",
+			instance: &compute.Instance{Metadata: &compute.Metadata{
+				Items: []*compute.MetadataItems{{Key: "block-project-ssh-keys", Value: str("true")}},
+			}},
+		},
+		{
+			name:        "block and purge matching project keys",
+			instance:    &compute.Instance{},
+			denyPattern: "@evil\\.com$",
+			expectedInstanceMetadata: &compute.Metadata{
+				Items: []*compute.MetadataItems{{Key: "block-project-ssh-keys", Value: str("true")}},
+			},
+			expectedProjectMetadata: &compute.Metadata{
+				Fingerprint: "def",
+				Items: []*compute.MetadataItems{
+					{Key: "enable-oslogin", Value: str("false")},
+					{Key: "ssh-keys", Value: str("alice:ssh-rsa AAAA alice@example.com\nbob:ssh-rsa CCCC bob@example.com")},
+				},
+			},
+		},
+		{
+			name:        "no matching project keys",
+			instance:    &compute.Instance{},
+			denyPattern: "@nowhere\\.com$",
+			expectedInstanceMetadata: &compute.Metadata{
+				Items: []*compute.MetadataItems{{Key: "block-project-ssh-keys", Value: str("true")}},
+			},
+		},
+		{
+			name:        "dry run",
+			instance:    &compute.Instance{},
+			denyPattern: "@evil\\.com$",
+			dryRun:      true,
+		},
+	}
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			svcs, computeStub := blockProjectSSHKeysSetup()
+			computeStub.StubbedInstance = tt.instance
+			computeStub.StubbedProject = &compute.Project{
+				CommonInstanceMetadata: &compute.Metadata{
+					Fingerprint: "def",
+					Items: []*compute.MetadataItems{
+						{Key: "enable-oslogin", Value: str("false")},
+						{Key: "ssh-keys", Value: str(projectKeys)},
+					},
+				},
+			}
+			values := &Values{
+				ProjectID:    "test-project",
+				InstanceZone: "us-central1-a",
+				InstanceID:   "test-instance",
+				DenyPattern:  tt.denyPattern,
+				DryRun:       tt.dryRun,
+			}
+			if err := Execute(ctx, values, svcs); err != nil {
+				t.Errorf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(computeStub.SavedInstanceMetadata, tt.expectedInstanceMetadata); diff != "" {
+				t.Errorf("%v failed, difference in instance metadata: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(computeStub.SavedProjectMetadata, tt.expectedProjectMetadata); diff != "" {
+				t.Errorf("%v failed, difference in project metadata: %+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestBlockProjectSSHKeysInvalidPattern(t *testing.T) {
+	svcs, computeStub := blockProjectSSHKeysSetup()
+	computeStub.StubbedInstance = &compute.Instance{}
+	values := &Values{ProjectID: "test-project", InstanceZone: "us-central1-a", InstanceID: "test-instance", DenyPattern: "("}
+	if err := Execute(context.Background(), values, svcs); err == nil {
+		t.Errorf("expected error for invalid deny pattern")
+	}
+	if computeStub.SavedInstanceMetadata != nil {
+		t.Errorf("instance metadata should not be modified with an invalid deny pattern")
+	}
+}
+
+func blockProjectSSHKeysSetup() (*Services, *stubs.ComputeStub) {
+	computeStub := &stubs.ComputeStub{}
+	host := services.NewHost(computeStub)
+	crmStub := &stubs.ResourceManagerStub{}
+	storageStub := &stubs.StorageStub{}
+	res := services.NewResource(crmStub, storageStub)
+	return &Services{Host: host, Resource: res}, computeStub
+}
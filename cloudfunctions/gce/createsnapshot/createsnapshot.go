@@ -16,10 +16,11 @@ package createsnapshot
 
 import (
 	"context"
-	"log"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/googlecloudplatform/security-response-automation/logging"
 	"github.com/googlecloudplatform/security-response-automation/services"
 	"github.com/pkg/errors"
 	compute "google.golang.org/api/compute/v1"
@@ -36,6 +37,12 @@ var labels = map[string]string{
 	"info": "created-by-security-response-automation",
 }
 
+// findingIDLabel is the snapshot label holding the ID of the finding that triggered it.
+const findingIDLabel = "finding-id"
+
+// invalidLabelChars matches characters not allowed in label values.
+var invalidLabelChars = regexp.MustCompile(`[^a-z0-9_-]`)
+
 // Values contains the required values needed for this function.
 type Values struct {
 	DryRun    bool
@@ -44,6 +51,10 @@ type Values struct {
 	Instance  string
 	Zone      string
 	Output    []string
+	// FindingID is the ID of the finding, saved as a label on each snapshot.
+	FindingID string
+	// StopInstance will optionally stop the instance once its disks have been snapshotted.
+	StopInstance bool
 
 	Turbinia struct {
 		ProjectID string
@@ -60,7 +71,6 @@ type Values struct {
 // Services contains the services needed for this function.
 type Services struct {
 	Host     *services.Host
-	Logger   *services.Logger
 	Resource *services.Resource
 }
 
@@ -73,15 +83,16 @@ type Output struct {
 // Execute creates a snapshot of an instance's disk.
 //
 // For a given supported finding pull each disk associated with the affected instance.
-// 	- Check to make sure we haven't created a snapshot for this finding recently.
-// 	- Create a new snapshot for each disk labeled with the finding and current time.
+//   - Check to make sure we haven't created a snapshot for this finding recently.
+//   - Create a new snapshot for each disk labeled with the finding and current time.
+//   - Optionally stop the instance.
 //
 // In order for the snapshot to be create the service account must be granted the correct
 // role on the affected project. At this time this grant is defined per project but should
 // be changed to support folder and organization level grants.
 func Execute(ctx context.Context, values *Values, services *Services) (*Output, error) {
 	var output Output
-	log.Printf("listing disk names within instance %q, in zone %q and project %q", values.Instance, values.Zone, values.ProjectID)
+	logging.FromContext(ctx).Info("listing disk names within instance %q, in zone %q and project %q", values.Instance, values.Zone, values.ProjectID)
 	disksCopied := []string{}
 	rule := strings.Replace(values.RuleName, "_", "-", -1)
 	disks, err := services.Host.ListInstanceDisks(ctx, values.ProjectID, values.Zone, values.Instance)
@@ -93,7 +104,7 @@ func Execute(ctx context.Context, values *Values, services *Services) (*Output,
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to list snapshots")
 	}
-	log.Printf("got %d existing snapshots for project %q", len(snapshots.Items), values.ProjectID)
+	logging.FromContext(ctx).Info("got %d existing snapshots for project %q", len(snapshots.Items), values.ProjectID)
 
 	for _, disk := range disks {
 		snapshotName := createSnapshotName(rule, disk.Name)
@@ -103,12 +114,12 @@ func Execute(ctx context.Context, values *Values, services *Services) (*Output,
 		}
 
 		if !create {
-			log.Printf("snapshot %q for disk %q will be skipped (not old enough or from another finding)", snapshotName, disk.Name)
+			logging.FromContext(ctx).Info("snapshot %q for disk %q will be skipped (not old enough or from another finding)", snapshotName, disk.Name)
 			continue
 		}
 
 		if values.DryRun {
-			services.Logger.Info("dry_run on, would created a snapshot of %q from %q", disk.Name, values.ProjectID)
+			logging.FromContext(ctx).Info("dry_run on, would created a snapshot of %q from %q", disk.Name, values.ProjectID)
 			continue
 		}
 
@@ -116,34 +127,65 @@ func Execute(ctx context.Context, values *Values, services *Services) (*Output,
 			if err := services.Host.DeleteDiskSnapshot(ctx, values.ProjectID, k); err != nil {
 				return nil, errors.Wrapf(err, "failed deleting snapshot: %q", k)
 			}
-			services.Logger.Info("removed existing snapshot %q from disk %q", k, disk.Name)
+			logging.FromContext(ctx).Info("removed existing snapshot %q from disk %q", k, disk.Name)
 		}
 
-		log.Printf("creating a snapshot %q for %q", snapshotName, disk.Name)
+		logging.FromContext(ctx).Info("creating a snapshot %q for %q", snapshotName, disk.Name)
 		if err := services.Host.CreateDiskSnapshot(ctx, values.ProjectID, values.Zone, disk.Name, snapshotName); err != nil {
 			return nil, errors.Wrapf(err, "failed creating snapshot: %q", snapshotName)
 		}
-		services.Logger.Info("created snapshot for disk %q", disk.Name)
+		logging.FromContext(ctx).Info("created snapshot for disk %q", disk.Name)
 
-		if err := services.Host.SetSnapshotLabels(ctx, values.ProjectID, snapshotName, disk, labels); err != nil {
+		if err := services.Host.SetSnapshotLabels(ctx, values.ProjectID, snapshotName, disk, snapshotLabels(values.FindingID)); err != nil {
 			return nil, errors.Wrapf(err, "failed setting labels: %q", snapshotName)
 		}
-		log.Printf("set labels for snapshot %q for disk %q", snapshotName, disk.Name)
+		logging.FromContext(ctx).Info("set labels for snapshot %q for disk %q", snapshotName, disk.Name)
 
 		if values.DestProjectID != "" {
-			log.Printf("copying snapshot %q for %q to %q in %q", snapshotName, disk.Name, values.DestProjectID, values.DestZone)
+			logging.FromContext(ctx).Info("copying snapshot %q for %q to %q in %q", snapshotName, disk.Name, values.DestProjectID, values.DestZone)
 			if err := services.Host.CopyDiskSnapshot(ctx, values.ProjectID, values.DestProjectID, values.DestZone, snapshotName); err != nil {
 				return nil, errors.Wrapf(err, "failed to copy disk to %q", values.DestProjectID)
 			}
 			disksCopied = append(disksCopied, snapshotName)
-			services.Logger.Info("copied snapshot %q to %q in %q", snapshotName, values.DestProjectID, values.DestZone)
+			logging.FromContext(ctx).Info("copied snapshot %q to %q in %q", snapshotName, values.DestProjectID, values.DestZone)
 		}
 	}
-	log.Printf("completed")
+	if values.StopInstance {
+		if values.DryRun {
+			logging.FromContext(ctx).Info("dry_run on, would have stopped instance %q in zone %q in project %q", values.Instance, values.Zone, values.ProjectID)
+		} else {
+			if err := services.Host.StopInstance(ctx, values.ProjectID, values.Zone, values.Instance); err != nil {
+				return nil, errors.Wrapf(err, "failed to stop instance %q", values.Instance)
+			}
+			logging.FromContext(ctx).Info("stopped instance %q in zone %q in project %q", values.Instance, values.Zone, values.ProjectID)
+		}
+	}
+	logging.FromContext(ctx).Info("completed")
 	output.DiskNames = disksCopied
 	return &output, nil
 }
 
+// snapshotLabels returns the labels for a new snapshot including the finding ID if known.
+func snapshotLabels(findingID string) map[string]string {
+	l := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		l[k] = v
+	}
+	if id := labelValue(findingID); id != "" {
+		l[findingIDLabel] = id
+	}
+	return l
+}
+
+// labelValue converts the value to a valid label value.
+func labelValue(v string) string {
+	v = invalidLabelChars.ReplaceAllString(strings.ToLower(v), "-")
+	if len(v) > 63 {
+		v = v[:63]
+	}
+	return v
+}
+
 // canCreateSnapshot checks if we should create a snapshot along with a map of existing snapshots to be removed.
 func canCreateSnapshot(snapshots *compute.SnapshotList, disk *compute.Disk, rule string) (bool, map[string]bool, error) {
 	create := true
@@ -185,8 +185,7 @@ func TestCreateSnapshot(t *testing.T) {
 				Zone:          "test-zone",
 			}
 			if _, err := Execute(ctx, values, &Services{
-				Host:   svcs.Host,
-				Logger: svcs.Logger,
+				Host: svcs.Host,
 			}); err != nil {
 				t.Errorf("%s failed to create snapshot: %q", tt.name, err)
 			}
@@ -203,6 +202,71 @@ func TestCreateSnapshot(t *testing.T) {
 	}
 }
 
+func TestCreateSnapshotLabelsAndStop(t *testing.T) {
+	ctx := context.Background()
+	fiveMinAgo := time.Now().Add(-time.Minute * 5).Format(time.RFC3339)
+	snapshotName := "forensic-snapshots-bad-ip-sample-disk-name"
+	for _, tt := range []struct {
+		name            string
+		findingID       string
+		stopInstance    bool
+		dryRun          bool
+		expectedLabels  map[string]string
+		expectedStopped string
+	}{
+		{
+			name:            "label with finding and stop instance",
+			findingID:       "6a30ce604c11417995b1fa260753f3b5",
+			stopInstance:    true,
+			expectedLabels:  map[string]string{"info": "created-by-security-response-automation", "finding-id": "6a30ce604c11417995b1fa260753f3b5"},
+			expectedStopped: "instance1",
+		},
+		{
+			name:           "invalid label characters are replaced",
+			findingID:      "AbC/123",
+			expectedLabels: map[string]string{"info": "created-by-security-response-automation", "finding-id": "abc-123"},
+		},
+		{
+			name:           "no finding id",
+			expectedLabels: map[string]string{"info": "created-by-security-response-automation"},
+		},
+		{
+			name:         "dry run does not stop",
+			stopInstance: true,
+			dryRun:       true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			svcs, computeStub := createSnapshotSetup()
+			computeStub.StubbedListDisks = &compute.DiskList{Items: []*compute.Disk{createDisk("sample-disk-name", "instance1")}}
+			computeStub.StubbedListProjectSnapshots = []*compute.SnapshotList{
+				{Items: []*compute.Snapshot{createSs(snapshotName, time.Now().Format(time.RFC3339), "sample-disk-name")}},
+				{Items: []*compute.Snapshot{createSs(snapshotName, fiveMinAgo, "sample-disk-name")}},
+			}
+			values := &Values{
+				ProjectID:    "project-id-123",
+				RuleName:     "bad_ip",
+				Instance:     "instance1",
+				Zone:         "test-zone",
+				FindingID:    tt.findingID,
+				StopInstance: tt.stopInstance,
+				DryRun:       tt.dryRun,
+			}
+			if _, err := Execute(ctx, values, &Services{
+				Host: svcs.Host,
+			}); err != nil {
+				t.Errorf("%s failed to create snapshot: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(computeStub.SavedSnapshotLabels, tt.expectedLabels); diff != "" {
+				t.Errorf("%v failed, difference: %+v", tt.name, diff)
+			}
+			if computeStub.SavedStoppedInstance != tt.expectedStopped {
+				t.Errorf("%s failed: got:%q want:%q", tt.name, computeStub.SavedStoppedInstance, tt.expectedStopped)
+			}
+		})
+	}
+}
+
 func createDisk(name, instance string) *compute.Disk {
 	return &compute.Disk{
 		Name:     name,
@@ -222,13 +286,11 @@ func createSs(name, time, disk string) *compute.Snapshot {
 }
 
 func createSnapshotSetup() (*services.Global, *stubs.ComputeStub) {
-	loggerStub := &stubs.LoggerStub{}
-	log := services.NewLogger(loggerStub)
 	computeStub := &stubs.ComputeStub{}
 	computeStub.SavedCreateSnapshots = make(map[string]compute.Snapshot)
 	resourceManagerStub := &stubs.ResourceManagerStub{}
 	storageStub := &stubs.StorageStub{}
 	h := services.NewHost(computeStub)
 	r := services.NewResource(resourceManagerStub, storageStub)
-	return &services.Global{Host: h, Resource: r, Logger: log}, computeStub
+	return &services.Global{Host: h, Resource: r}, computeStub
 }
@@ -29,7 +29,8 @@ resource "google_cloudfunctions_function" "create-disk-snapshot" {
     resource   = "threat-findings-create-disk-snapshot"
   }
   environment_variables = {
-    GCP_PROJECT = var.setup.automation-project
+    GCP_PROJECT    = var.setup.automation-project
+    SRA_CONFIG_URI = var.setup.config-uri
   }
   timeouts {
     create = "10m"
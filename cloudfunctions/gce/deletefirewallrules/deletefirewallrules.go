@@ -0,0 +1,98 @@
+package deletefirewallrules
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/pkg/errors"
+	compute "google.golang.org/api/compute/v1"
+)
+
+// Values contains the required values needed for this function.
+//
+// Only rules created at or after IncidentStart are remediated so rules that existed before the
+// incident are left untouched.
+type Values struct {
+	ProjectID         string
+	FirewallRules     []string
+	IncidentStart     time.Time
+	RemediationAction string
+	DryRun            bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Firewall *services.Firewall
+	Resource *services.Resource
+}
+
+// Execute deletes or disables the firewall rules created during the incident.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	action := values.RemediationAction
+	if action == "" {
+		action = "delete"
+	}
+	if action != "delete" && action != "disable" {
+		return fmt.Errorf("unknown firewall rule remediation action: %q", action)
+	}
+	if values.IncidentStart.IsZero() {
+		return fmt.Errorf("no incident start set for firewall rules %q in project %q", values.FirewallRules, values.ProjectID)
+	}
+	for _, name := range values.FirewallRules {
+		r, err := services.Firewall.FirewallRule(ctx, values.ProjectID, name)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get firewall %q", name)
+		}
+		created, err := time.Parse(time.RFC3339, r.CreationTimestamp)
+		if err != nil {
+			logging.FromContext(ctx).Warning("unable to verify creation time %q of firewall %q in project %q, skipping", r.CreationTimestamp, name, values.ProjectID)
+			continue
+		}
+		if created.Before(values.IncidentStart) {
+			logging.FromContext(ctx).Info("firewall %q in project %q was created at %s before the incident started at %s, skipping", name, values.ProjectID, created.Format(time.RFC3339), values.IncidentStart.Format(time.RFC3339))
+			continue
+		}
+		if values.DryRun {
+			logging.FromContext(ctx).Info("dry_run on, would have run %q on firewall %q in project %q", action, name, values.ProjectID)
+			continue
+		}
+		if err := remediate(ctx, services.Firewall, action, values.ProjectID, r.Name); err != nil {
+			return err
+		}
+		logging.FromContext(ctx).Info("ran %q on firewall %q in project %q created at %s", action, name, values.ProjectID, created.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func remediate(ctx context.Context, fw *services.Firewall, action, projectID, name string) error {
+	var op *compute.Operation
+	var err error
+	if action == "disable" {
+		op, err = fw.DisableFirewallRule(ctx, projectID, name, name)
+	} else {
+		op, err = fw.DeleteFirewallRule(ctx, projectID, name)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to %s firewall %q", action, name)
+	}
+	if errs := fw.WaitGlobal(projectID, op); len(errs) > 0 {
+		return errors.Wrapf(errs[0], "failed to %s firewall %q", action, name)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package deletefirewallrules
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	compute "google.golang.org/api/compute/v1"
+)
+
+func TestDeleteFirewallRules(t *testing.T) {
+	ctx := context.Background()
+	incidentStart := time.Date(2020, 6, 10, 17, 0, 0, 0, time.UTC)
+	rules := map[string]*compute.Firewall{
+		"attacker-rule":    {Name: "attacker-rule", CreationTimestamp: "2020-06-10T10:30:00.000-07:00"},
+		"legitimate-rule":  {Name: "legitimate-rule", CreationTimestamp: "2019-01-01T00:00:00.000-07:00"},
+		"unparseable-rule": {Name: "unparseable-rule", CreationTimestamp: "yesterday"},
+	}
+	test := []struct {
+		name            string
+		rules           []string
+		action          string
+		dryRun          bool
+		expectedDeleted []string
+		expectedPatched *compute.Firewall
+	}{
+		{
+			name:            "delete rule created after incident start",
+			rules:           []string{"attacker-rule"},
+			expectedDeleted: []string{"attacker-rule"},
+		},
+		{
+			name:            "disable rule created after incident start",
+			rules:           []string{"attacker-rule"},
+			action:          "disable",
+			expectedPatched: &compute.Firewall{Name: "attacker-rule", Disabled: true},
+		},
+		{
+			name:            "skip rules created before incident start",
+			rules:           []string{"legitimate-rule", "attacker-rule", "unparseable-rule"},
+			expectedDeleted: []string{"attacker-rule"},
+		},
+		{
+			name:   "dry run",
+			rules:  []string{"attacker-rule"},
+			dryRun: true,
+		},
+	}
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			svcs, computeStub := deleteFirewallRulesSetup()
+			computeStub.StubbedFirewallRules = rules
+			values := &Values{
+				ProjectID:         "test-project",
+				FirewallRules:     tt.rules,
+				IncidentStart:     incidentStart,
+				RemediationAction: tt.action,
+				DryRun:            tt.dryRun,
+			}
+			if err := Execute(ctx, values, svcs); err != nil {
+				t.Errorf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(computeStub.DeletedFirewallRules, tt.expectedDeleted); diff != "" {
+				t.Errorf("%v failed, difference in deleted rules: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(computeStub.SavedFirewallRule, tt.expectedPatched); diff != "" {
+				t.Errorf("%v failed, difference in patched rule: %+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestDeleteFirewallRulesErrors(t *testing.T) {
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name   string
+		values *Values
+	}{
+		{name: "unknown action", values: &Values{FirewallRules: []string{"attacker-rule"}, IncidentStart: time.Now(), RemediationAction: "update_source_range"}},
+		{name: "missing incident start", values: &Values{FirewallRules: []string{"attacker-rule"}}},
+		{name: "missing rule", values: &Values{FirewallRules: []string{"nonexistent"}, IncidentStart: time.Now()}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			svcs, computeStub := deleteFirewallRulesSetup()
+			computeStub.StubbedFirewallRules = map[string]*compute.Firewall{}
+			if err := Execute(ctx, tt.values, svcs); err == nil {
+				t.Errorf("%s expected error", tt.name)
+			}
+		})
+	}
+}
+
+func deleteFirewallRulesSetup() (*Services, *stubs.ComputeStub) {
+	computeStub := &stubs.ComputeStub{}
+	fw := services.NewFirewall(computeStub)
+	crmStub := &stubs.ResourceManagerStub{}
+	storageStub := &stubs.StorageStub{}
+	res := services.NewResource(crmStub, storageStub)
+	return &Services{Firewall: fw, Resource: res}, computeStub
+}
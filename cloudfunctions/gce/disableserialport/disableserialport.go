@@ -0,0 +1,58 @@
+package disableserialport
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/pkg/errors"
+)
+
+// Values contains the required values needed for this function.
+type Values struct {
+	ProjectID, InstanceZone, InstanceID string
+	DryRun                              bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Host     *services.Host
+	Resource *services.Resource
+}
+
+// Execute disables interactive serial port access on a GCE instance.
+//
+// The prior serial-port-enable value is logged so the change can be audited and reverted.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	if values.DryRun {
+		prior, err := services.Host.SerialPortEnabled(ctx, values.ProjectID, values.InstanceZone, values.InstanceID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get serial port access")
+		}
+		logging.FromContext(ctx).Info("dry_run on, would have disabled serial port access for instance %q, in zone %q in project %q, serial-port-enable was %q.", values.InstanceID, values.InstanceZone, values.ProjectID, prior)
+		return nil
+	}
+	prior, changed, err := services.Host.DisableSerialPort(ctx, values.ProjectID, values.InstanceZone, values.InstanceID)
+	if err != nil {
+		return errors.Wrap(err, "failed to disable serial port access")
+	}
+	if !changed {
+		logging.FromContext(ctx).Info("serial port access already disabled for instance %q, in zone %q in project %q.", values.InstanceID, values.InstanceZone, values.ProjectID)
+		return nil
+	}
+	logging.FromContext(ctx).Info("disabled serial port access for instance %q, in zone %q in project %q, serial-port-enable was %q.", values.InstanceID, values.InstanceZone, values.ProjectID, prior)
+	return nil
+}
@@ -0,0 +1,96 @@
+package disableserialport
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	compute "google.golang.org/api/compute/v1"
+)
+
+func TestDisableSerialPort(t *testing.T) {
+	ctx := context.Background()
+	str := func(s string) *string { return &s }
+	test := []struct {
+		name             string
+		metadata         *compute.Metadata
+		dryRun           bool
+		expectedMetadata *compute.Metadata
+		expectedLog      string
+	}{
+		{
+			name: "disable serial port",
+			metadata: &compute.Metadata{
+				Fingerprint: "abc",
+				Items:       []*compute.MetadataItems{{Key: "serial-port-enable", Value: str("true")}},
+			},
+			expectedMetadata: &compute.Metadata{
+				Fingerprint: "abc",
+				Items:       []*compute.MetadataItems{{Key: "serial-port-enable", Value: str("false")}},
+			},
+			expectedLog: `disabled serial port access for instance "test-instance", in zone "us-central1-a" in project "test-project", serial-port-enable was "true".`,
+		},
+		{
+			name: "already disabled",
+			metadata: &compute.Metadata{
+				Items: []*compute.MetadataItems{{Key: "serial-port-enable", Value: str("false")}},
+			},
+			expectedLog: `serial port access already disabled for instance "test-instance", in zone "us-central1-a" in project "test-project".`,
+		},
+		{
+			name: "dry run",
+			metadata: &compute.Metadata{
+				Items: []*compute.MetadataItems{{Key: "serial-port-enable", Value: str("1")}},
+			},
+			dryRun:      true,
+			expectedLog: `dry_run on, would have disabled serial port access for instance "test-instance", in zone "us-central1-a" in project "test-project", serial-port-enable was "1".`,
+		},
+	}
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			svcs, computeStub, loggerStub := disableSerialPortSetup()
+			computeStub.StubbedInstance = &compute.Instance{Metadata: tt.metadata}
+			values := &Values{
+				ProjectID:    "test-project",
+				InstanceZone: "us-central1-a",
+				InstanceID:   "test-instance",
+				DryRun:       tt.dryRun,
+			}
+			if err := Execute(logging.NewContext(ctx, loggerStub), values, svcs); err != nil {
+				t.Errorf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(computeStub.SavedInstanceMetadata, tt.expectedMetadata); diff != "" {
+				t.Errorf("%v failed, difference: %+v", tt.name, diff)
+			}
+			if loggerStub.LastInfo != tt.expectedLog {
+				t.Errorf("%v failed, got log %q want %q", tt.name, loggerStub.LastInfo, tt.expectedLog)
+			}
+		})
+	}
+}
+
+func disableSerialPortSetup() (*Services, *stubs.ComputeStub, *stubs.LoggerStub) {
+	loggerStub := &stubs.LoggerStub{}
+	computeStub := &stubs.ComputeStub{}
+	host := services.NewHost(computeStub)
+	crmStub := &stubs.ResourceManagerStub{}
+	storageStub := &stubs.StorageStub{}
+	res := services.NewResource(crmStub, storageStub)
+	return &Services{Host: host, Resource: res}, computeStub, loggerStub
+}
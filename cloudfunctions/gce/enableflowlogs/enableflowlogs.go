@@ -0,0 +1,57 @@
+package enableflowlogs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// Values contains the required values needed for this function.
+type Values struct {
+	ProjectID, Region, Subnetwork string
+	// FlowSampling is the fraction of flows logged, between 0 and 1.
+	FlowSampling float64
+	// AggregationInterval is how long flows are aggregated for, such as INTERVAL_5_SEC.
+	AggregationInterval string
+	DryRun              bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Firewall *services.Firewall
+}
+
+// Execute enables VPC Flow Logs on the subnetwork.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	sn, err := services.Firewall.Subnetwork(ctx, values.ProjectID, values.Region, values.Subnetwork)
+	if err != nil {
+		return err
+	}
+	if sn.LogConfig != nil && sn.LogConfig.Enable {
+		logging.FromContext(ctx).Info("flow logs already enabled on subnetwork %q in region %q in project %q", values.Subnetwork, values.Region, values.ProjectID)
+		return nil
+	}
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have enabled flow logs on subnetwork %q in region %q in project %q", values.Subnetwork, values.Region, values.ProjectID)
+		return nil
+	}
+	if err := services.Firewall.EnableFlowLogs(ctx, values.ProjectID, values.Region, values.Subnetwork, values.FlowSampling, values.AggregationInterval); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("enabled flow logs on subnetwork %q in region %q in project %q", values.Subnetwork, values.Region, values.ProjectID)
+	return nil
+}
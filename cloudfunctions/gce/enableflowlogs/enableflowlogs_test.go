@@ -0,0 +1,77 @@
+package enableflowlogs
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	compute "google.golang.org/api/compute/v1"
+)
+
+func TestEnableFlowLogs(t *testing.T) {
+	ctx := context.Background()
+	test := []struct {
+		name          string
+		subnetwork    *compute.Subnetwork
+		dryRun        bool
+		expectedPatch *compute.Subnetwork
+	}{
+		{
+			name:       "enable flow logs",
+			subnetwork: &compute.Subnetwork{Name: "default", Fingerprint: "abc"},
+			expectedPatch: &compute.Subnetwork{
+				Fingerprint: "abc",
+				LogConfig: &compute.SubnetworkLogConfig{
+					Enable:              true,
+					FlowSampling:        0.25,
+					AggregationInterval: "INTERVAL_1_MIN",
+				},
+			},
+		},
+		{
+			name:       "already enabled",
+			subnetwork: &compute.Subnetwork{Name: "default", LogConfig: &compute.SubnetworkLogConfig{Enable: true}},
+		},
+		{
+			name:       "dry run",
+			subnetwork: &compute.Subnetwork{Name: "default"},
+			dryRun:     true,
+		},
+	}
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			computeStub := &stubs.ComputeStub{StubbedSubnetwork: tt.subnetwork}
+			values := &Values{
+				ProjectID:           "test-project",
+				Region:              "us-central1",
+				Subnetwork:          "default",
+				FlowSampling:        0.25,
+				AggregationInterval: "INTERVAL_1_MIN",
+				DryRun:              tt.dryRun,
+			}
+			if err := Execute(ctx, values, &Services{
+				Firewall: services.NewFirewall(computeStub),
+			}); err != nil {
+				t.Errorf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(computeStub.SavedSubnetworkPatch, tt.expectedPatch); diff != "" {
+				t.Errorf("%v failed, difference in subnetwork patch: %+v", tt.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package enableoslogin
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/pkg/errors"
+)
+
+// Values contains the required values needed for this function.
+//
+// When Folders is set only projects within one of these folders are modified.
+type Values struct {
+	ProjectID string
+	Folders   []string
+	DryRun    bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Host     *services.Host
+	Resource *services.Resource
+}
+
+// Execute enables OS Login in the project-wide metadata.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	if len(values.Folders) > 0 {
+		ok, err := services.Resource.InFolders(ctx, values.ProjectID, values.Folders)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			logging.FromContext(ctx).Info("project %q not in configured folders, skipping", values.ProjectID)
+			return nil
+		}
+	}
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have enabled os login in project %q.", values.ProjectID)
+		return nil
+	}
+	enabled, err := services.Host.EnableProjectOSLogin(ctx, values.ProjectID)
+	if err != nil {
+		return errors.Wrap(err, "failed to enable os login")
+	}
+	if !enabled {
+		logging.FromContext(ctx).Info("os login already enabled in project %q.", values.ProjectID)
+		return nil
+	}
+	logging.FromContext(ctx).Info("enabled os login in project %q.", values.ProjectID)
+	return nil
+}
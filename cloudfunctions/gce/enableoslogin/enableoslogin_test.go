@@ -0,0 +1,101 @@
+package enableoslogin
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	compute "google.golang.org/api/compute/v1"
+)
+
+func TestEnableOSLogin(t *testing.T) {
+	ctx := context.Background()
+	str := func(s string) *string { return &s }
+	test := []struct {
+		name             string
+		metadata         *compute.Metadata
+		folders          []string
+		dryRun           bool
+		expectedMetadata *compute.Metadata
+	}{
+		{
+			name:     "enable os login",
+			metadata: &compute.Metadata{Fingerprint: "abc"},
+			expectedMetadata: &compute.Metadata{
+				Fingerprint: "abc",
+				Items:       []*compute.MetadataItems{{Key: "enable-oslogin", Value: str("true")}},
+			},
+		},
+		{
+			name:     "update disabled os login",
+			metadata: &compute.Metadata{Items: []*compute.MetadataItems{{Key: "enable-oslogin", Value: str("FALSE")}}},
+			expectedMetadata: &compute.Metadata{
+				Items: []*compute.MetadataItems{{Key: "enable-oslogin", Value: str("true")}},
+			},
+		},
+		{
+			name:     "already enabled",
+			metadata: &compute.Metadata{Items: []*compute.MetadataItems{{Key: "enable-oslogin", Value: str("TRUE")}}},
+		},
+		{
+			name:     "project within configured folder",
+			metadata: &compute.Metadata{},
+			folders:  []string{"123"},
+			expectedMetadata: &compute.Metadata{
+				Items: []*compute.MetadataItems{{Key: "enable-oslogin", Value: str("true")}},
+			},
+		},
+		{
+			name:     "project outside configured folders",
+			metadata: &compute.Metadata{},
+			folders:  []string{"999"},
+		},
+		{
+			name:     "dry run",
+			metadata: &compute.Metadata{},
+			dryRun:   true,
+		},
+	}
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			svcs, computeStub := enableOSLoginSetup()
+			computeStub.StubbedProject = &compute.Project{CommonInstanceMetadata: tt.metadata}
+			values := &Values{
+				ProjectID: "test-project",
+				Folders:   tt.folders,
+				DryRun:    tt.dryRun,
+			}
+			if err := Execute(ctx, values, svcs); err != nil {
+				t.Errorf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(computeStub.SavedProjectMetadata, tt.expectedMetadata); diff != "" {
+				t.Errorf("%v failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func enableOSLoginSetup() (*Services, *stubs.ComputeStub) {
+	computeStub := &stubs.ComputeStub{}
+	host := services.NewHost(computeStub)
+	crmStub := &stubs.ResourceManagerStub{}
+	crmStub.GetAncestryResponse = services.CreateAncestors([]string{"project/test-project", "folder/123", "organization/456"})
+	storageStub := &stubs.StorageStub{}
+	res := services.NewResource(crmStub, storageStub)
+	return &Services{Host: host, Resource: res}, computeStub
+}
@@ -0,0 +1,59 @@
+package enableprivateaccess
+
+//  Copyright 2019 Google LLC
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//  	https://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+import (
+	"context"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// Values contains the required values needed for this function.
+type Values struct {
+	ProjectID, Region, Subnetwork string
+	// DenyExternalIP applies the compute.vmExternalIpAccess constraint to the project.
+	DenyExternalIP bool
+	DryRun         bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Firewall *services.Firewall
+	Resource *services.Resource
+}
+
+// Execute enables Private Google Access on the subnetwork and optionally denies external IPs for the project.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have enabled private google access on subnetwork %q in region %q in project %q", values.Subnetwork, values.Region, values.ProjectID)
+		if values.DenyExternalIP {
+			logging.FromContext(ctx).Info("dry_run on, would have denied vm external ip access in project %q", values.ProjectID)
+		}
+		return nil
+	}
+	if err := services.Firewall.EnablePrivateGoogleAccess(ctx, values.ProjectID, values.Region, values.Subnetwork); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("enabled private google access on subnetwork %q in region %q in project %q", values.Subnetwork, values.Region, values.ProjectID)
+	if !values.DenyExternalIP {
+		return nil
+	}
+	if err := services.Resource.DenyVMExternalIPAccess(ctx, values.ProjectID); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("denied vm external ip access in project %q", values.ProjectID)
+	return nil
+}
@@ -0,0 +1,85 @@
+package enableprivateaccess
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	crm "google.golang.org/api/cloudresourcemanager/v1"
+)
+
+func TestEnablePrivateAccess(t *testing.T) {
+	ctx := context.Background()
+	test := []struct {
+		name                  string
+		denyExternalIP        bool
+		dryRun                bool
+		expectedPrivateAccess map[string]bool
+		expectedOrgPolicy     *crm.OrgPolicy
+	}{
+		{
+			name:                  "enable private google access",
+			expectedPrivateAccess: map[string]bool{"default": true},
+		},
+		{
+			name:                  "enable private google access and deny external ips",
+			denyExternalIP:        true,
+			expectedPrivateAccess: map[string]bool{"default": true},
+			expectedOrgPolicy: &crm.OrgPolicy{
+				Constraint: "constraints/compute.vmExternalIpAccess",
+				ListPolicy: &crm.ListPolicy{AllValues: "DENY"},
+			},
+		},
+		{
+			name:           "dry run",
+			denyExternalIP: true,
+			dryRun:         true,
+		},
+	}
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			svcs, computeStub, crmStub := enablePrivateAccessSetup()
+			values := &Values{
+				ProjectID:      "test-project",
+				Region:         "us-central1",
+				Subnetwork:     "default",
+				DenyExternalIP: tt.denyExternalIP,
+				DryRun:         tt.dryRun,
+			}
+			if err := Execute(ctx, values, svcs); err != nil {
+				t.Errorf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(computeStub.SavedPrivateGoogleAccess, tt.expectedPrivateAccess); diff != "" {
+				t.Errorf("%v failed, difference in private google access: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(crmStub.SavedOrgPolicy, tt.expectedOrgPolicy); diff != "" {
+				t.Errorf("%v failed, difference in org policy: %+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func enablePrivateAccessSetup() (*Services, *stubs.ComputeStub, *stubs.ResourceManagerStub) {
+	computeStub := &stubs.ComputeStub{}
+	fw := services.NewFirewall(computeStub)
+	crmStub := &stubs.ResourceManagerStub{}
+	storageStub := &stubs.StorageStub{}
+	res := services.NewResource(crmStub, storageStub)
+	return &Services{Firewall: fw, Resource: res}, computeStub, crmStub
+}
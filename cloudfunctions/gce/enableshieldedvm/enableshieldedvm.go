@@ -0,0 +1,126 @@
+package enableshieldedvm
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/pkg/errors"
+	compute "google.golang.org/api/compute/v1"
+)
+
+// uefiCompatible is the guest OS feature of boot disks whose image supports Shielded VM.
+const uefiCompatible = "UEFI_COMPATIBLE"
+
+// Values contains the required values needed for this function.
+//
+// Only the options set to true are enabled, options already enabled on the instance are never
+// disabled.
+type Values struct {
+	ProjectID, InstanceZone, InstanceID string
+	SecureBoot                          bool
+	VTPM                                bool
+	IntegrityMonitoring                 bool
+	DryRun                              bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Host     *services.Host
+	Resource *services.Resource
+}
+
+// Execute enables the configured Shielded VM options on a GCE instance.
+//
+// Secure Boot and vTPM can only be changed while the instance is stopped, so a running instance is
+// stopped before the update and started again afterwards.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	instance, err := services.Host.Instance(ctx, values.ProjectID, values.InstanceZone, values.InstanceID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get instance")
+	}
+	current := instance.ShieldedInstanceConfig
+	if current == nil {
+		current = &compute.ShieldedInstanceConfig{}
+	}
+	update := &compute.ShieldedInstanceConfig{
+		EnableSecureBoot:          values.SecureBoot && !current.EnableSecureBoot,
+		EnableVtpm:                values.VTPM && !current.EnableVtpm,
+		EnableIntegrityMonitoring: values.IntegrityMonitoring && !current.EnableIntegrityMonitoring,
+	}
+	if !update.EnableSecureBoot && !update.EnableVtpm && !update.EnableIntegrityMonitoring {
+		logging.FromContext(ctx).Info("shielded vm options already enabled for instance %q, in zone %q in project %q.", values.InstanceID, values.InstanceZone, values.ProjectID)
+		return nil
+	}
+	if !supportsShieldedVM(instance) {
+		return fmt.Errorf("boot disk of instance %q does not support shielded vm", values.InstanceID)
+	}
+	restart := (update.EnableSecureBoot || update.EnableVtpm) && instance.Status == "RUNNING"
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have enabled shielded vm options %q for instance %q, in zone %q in project %q, restart: %t.", options(update), values.InstanceID, values.InstanceZone, values.ProjectID, restart)
+		return nil
+	}
+	if restart {
+		if err := services.Host.StopInstance(ctx, values.ProjectID, values.InstanceZone, values.InstanceID); err != nil {
+			return err
+		}
+		logging.FromContext(ctx).Info("stopped instance %q, in zone %q in project %q.", values.InstanceID, values.InstanceZone, values.ProjectID)
+	}
+	if err := services.Host.UpdateShieldedInstanceConfig(ctx, values.ProjectID, values.InstanceZone, values.InstanceID, update); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("enabled shielded vm options %q for instance %q, in zone %q in project %q.", options(update), values.InstanceID, values.InstanceZone, values.ProjectID)
+	if !restart {
+		return nil
+	}
+	if err := services.Host.StartInstance(ctx, values.ProjectID, values.InstanceZone, values.InstanceID); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("started instance %q, in zone %q in project %q.", values.InstanceID, values.InstanceZone, values.ProjectID)
+	return nil
+}
+
+// supportsShieldedVM returns true if the boot disk of the instance is UEFI compatible.
+func supportsShieldedVM(instance *compute.Instance) bool {
+	for _, disk := range instance.Disks {
+		if !disk.Boot {
+			continue
+		}
+		for _, feature := range disk.GuestOsFeatures {
+			if feature.Type == uefiCompatible {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// options returns the names of the options enabled by the update.
+func options(config *compute.ShieldedInstanceConfig) []string {
+	enabled := []string{}
+	if config.EnableSecureBoot {
+		enabled = append(enabled, "secure_boot")
+	}
+	if config.EnableVtpm {
+		enabled = append(enabled, "vtpm")
+	}
+	if config.EnableIntegrityMonitoring {
+		enabled = append(enabled, "integrity_monitoring")
+	}
+	return enabled
+}
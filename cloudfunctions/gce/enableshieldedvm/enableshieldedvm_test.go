@@ -0,0 +1,126 @@
+package enableshieldedvm
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	compute "google.golang.org/api/compute/v1"
+)
+
+func TestEnableShieldedVM(t *testing.T) {
+	ctx := context.Background()
+	uefiDisks := []*compute.AttachedDisk{
+		{Boot: true, GuestOsFeatures: []*compute.GuestOsFeature{{Type: "VIRTIO_SCSI_MULTIQUEUE"}, {Type: "UEFI_COMPATIBLE"}}},
+	}
+	test := []struct {
+		name            string
+		instance        *compute.Instance
+		secureBoot      bool
+		vtpm            bool
+		integrity       bool
+		dryRun          bool
+		expectedConfig  *compute.ShieldedInstanceConfig
+		expectedStopped string
+		expectedStarted string
+		expectedError   bool
+	}{
+		{
+			name:            "enable all options on running instance",
+			instance:        &compute.Instance{Status: "RUNNING", Disks: uefiDisks},
+			secureBoot:      true,
+			vtpm:            true,
+			integrity:       true,
+			expectedConfig:  &compute.ShieldedInstanceConfig{EnableSecureBoot: true, EnableVtpm: true, EnableIntegrityMonitoring: true},
+			expectedStopped: "test-instance",
+			expectedStarted: "test-instance",
+		},
+		{
+			name:           "stopped instance is not started",
+			instance:       &compute.Instance{Status: "TERMINATED", Disks: uefiDisks},
+			secureBoot:     true,
+			expectedConfig: &compute.ShieldedInstanceConfig{EnableSecureBoot: true},
+		},
+		{
+			name: "integrity monitoring without restart",
+			instance: &compute.Instance{
+				Status:                 "RUNNING",
+				Disks:                  uefiDisks,
+				ShieldedInstanceConfig: &compute.ShieldedInstanceConfig{EnableVtpm: true},
+			},
+			vtpm:           true,
+			integrity:      true,
+			expectedConfig: &compute.ShieldedInstanceConfig{EnableIntegrityMonitoring: true},
+		},
+		{
+			name: "already enabled",
+			instance: &compute.Instance{
+				Status:                 "RUNNING",
+				Disks:                  uefiDisks,
+				ShieldedInstanceConfig: &compute.ShieldedInstanceConfig{EnableSecureBoot: true, EnableVtpm: true, EnableIntegrityMonitoring: true},
+			},
+			secureBoot: true,
+			vtpm:       true,
+			integrity:  true,
+		},
+		{
+			name:          "unsupported image",
+			instance:      &compute.Instance{Status: "RUNNING", Disks: []*compute.AttachedDisk{{Boot: true}}},
+			secureBoot:    true,
+			expectedError: true,
+		},
+		{
+			name:       "dry run",
+			instance:   &compute.Instance{Status: "RUNNING", Disks: uefiDisks},
+			secureBoot: true,
+			dryRun:     true,
+		},
+	}
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			computeStub := &stubs.ComputeStub{StubbedInstance: tt.instance}
+			svcs := &Services{
+				Host:     services.NewHost(computeStub),
+				Resource: services.NewResource(&stubs.ResourceManagerStub{}, &stubs.StorageStub{}),
+			}
+			values := &Values{
+				ProjectID:           "test-project",
+				InstanceZone:        "us-central1-a",
+				InstanceID:          "test-instance",
+				SecureBoot:          tt.secureBoot,
+				VTPM:                tt.vtpm,
+				IntegrityMonitoring: tt.integrity,
+				DryRun:              tt.dryRun,
+			}
+			err := Execute(ctx, values, svcs)
+			if (err != nil) != tt.expectedError {
+				t.Errorf("%s test failed, unexpected error result: %v", tt.name, err)
+			}
+			if diff := cmp.Diff(computeStub.SavedShieldedInstanceConfig, tt.expectedConfig); diff != "" {
+				t.Errorf("%v failed, difference in shielded instance config: %+v", tt.name, diff)
+			}
+			if computeStub.SavedStoppedInstance != tt.expectedStopped {
+				t.Errorf("%v failed, got stopped instance %q want %q", tt.name, computeStub.SavedStoppedInstance, tt.expectedStopped)
+			}
+			if computeStub.SavedStartedInstance != tt.expectedStarted {
+				t.Errorf("%v failed, got started instance %q want %q", tt.name, computeStub.SavedStartedInstance, tt.expectedStarted)
+			}
+		})
+	}
+}
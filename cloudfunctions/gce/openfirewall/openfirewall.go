@@ -17,7 +17,9 @@ package openfirewall
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/googlecloudplatform/security-response-automation/logging"
 	"github.com/googlecloudplatform/security-response-automation/services"
 	"github.com/pkg/errors"
 )
@@ -29,44 +31,93 @@ type Values struct {
 	FirewallID   string
 	SourceRanges []string
 	DryRun       bool
+
+	// Instance and Zone optionally scope the SSH block to the networks of the attacked instance.
+	Instance string
+	Zone     string
+	// Priority is the optional priority of the SSH block rule.
+	Priority int64
+	// Expiry is the optional duration after which the SSH block rule is removed.
+	Expiry time.Duration
 }
 
 // Services contains the services needed for this function.
 type Services struct {
 	Firewall *services.Firewall
+	Host     *services.Host
 	Resource *services.Resource
-	Logger   *services.Logger
 }
 
 // Execute remediates an open firewall.
 func Execute(ctx context.Context, values *Values, services *Services) error {
 	if values.DryRun {
-		services.Logger.Info("dry_run on, would have remediated firewall %q in project %q with action %q", values.FirewallID, values.ProjectID, values.Action)
+		logging.FromContext(ctx).Info("dry_run on, would have remediated firewall %q in project %q with action %q", values.FirewallID, values.ProjectID, values.Action)
 		return nil
 	}
 	switch action := values.Action; action {
 	case "block_ssh":
-		return blockSSH(ctx, services.Logger, services.Firewall, values)
+		if values.Instance != "" {
+			return blockSSHNetwork(ctx, services, values)
+		}
+		return blockSSH(ctx, services.Firewall, values)
 	case "disable":
-		return disable(ctx, services.Logger, services.Firewall, values)
+		return disable(ctx, services.Firewall, values)
 	case "delete":
-		return delete(ctx, services.Logger, services.Firewall, values)
+		return delete(ctx, services.Firewall, values)
 	case "update_source_range":
-		return updateRange(ctx, services.Logger, services.Firewall, values)
+		return updateRange(ctx, services.Firewall, values)
+	case "remove_expired_blocks":
+		return removeExpired(ctx, services.Firewall, values)
 	default:
 		return fmt.Errorf("unknown open firewall remediation action: %q", action)
 	}
 }
 
-func blockSSH(ctx context.Context, logr *services.Logger, fw *services.Firewall, values *Values) error {
+func blockSSH(ctx context.Context, fw *services.Firewall, values *Values) error {
 	if err := fw.BlockSSH(ctx, values.ProjectID, values.SourceRanges); err != nil {
 		return errors.Wrapf(err, "failed to block ssh on %q from %q", values.ProjectID, values.SourceRanges)
 	}
-	logr.Info("blocked ssh on %q from %q", values.ProjectID, values.SourceRanges)
+	logging.FromContext(ctx).Info("blocked ssh on %q from %q", values.ProjectID, values.SourceRanges)
 	return nil
 }
 
-func disable(ctx context.Context, logr *services.Logger, fw *services.Firewall, values *Values) error {
+// blockSSHNetwork blocks SSH to each network the attacked instance is attached to.
+func blockSSHNetwork(ctx context.Context, services *Services, values *Values) error {
+	instance, err := services.Host.Instance(ctx, values.ProjectID, values.Zone, values.Instance)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get instance %q", values.Instance)
+	}
+	var expiry time.Time
+	if values.Expiry > 0 {
+		expiry = time.Now().Add(values.Expiry)
+	}
+	blocked := map[string]bool{}
+	for _, ni := range instance.NetworkInterfaces {
+		if blocked[ni.Network] {
+			continue
+		}
+		if err := services.Firewall.BlockSSHNetwork(ctx, values.ProjectID, ni.Network, values.SourceRanges, values.Priority, expiry); err != nil {
+			return errors.Wrapf(err, "failed to block ssh on %q from %q", ni.Network, values.SourceRanges)
+		}
+		blocked[ni.Network] = true
+		logging.FromContext(ctx).Info("blocked ssh on network %q in %q from %q", ni.Network, values.ProjectID, values.SourceRanges)
+	}
+	return removeExpired(ctx, services.Firewall, values)
+}
+
+// removeExpired removes any SSH block rules within the project that have expired.
+func removeExpired(ctx context.Context, fw *services.Firewall, values *Values) error {
+	removed, err := fw.RemoveExpiredSSHBlocks(ctx, values.ProjectID, time.Now())
+	if err != nil {
+		return err
+	}
+	if len(removed) > 0 {
+		logging.FromContext(ctx).Info("removed expired ssh blocks %q in project %q", removed, values.ProjectID)
+	}
+	return nil
+}
+
+func disable(ctx context.Context, fw *services.Firewall, values *Values) error {
 	r, err := fw.FirewallRule(ctx, values.ProjectID, values.FirewallID)
 	if err != nil {
 		return err
@@ -78,11 +129,11 @@ func disable(ctx context.Context, logr *services.Logger, fw *services.Firewall,
 	if errs := fw.WaitGlobal(values.ProjectID, op); len(errs) > 0 {
 		return errs[0]
 	}
-	logr.Info("disabled firewall %q in project %q.", r.Name, values.ProjectID)
+	logging.FromContext(ctx).Info("disabled firewall %q in project %q.", r.Name, values.ProjectID)
 	return nil
 }
 
-func delete(ctx context.Context, logr *services.Logger, fw *services.Firewall, values *Values) error {
+func delete(ctx context.Context, fw *services.Firewall, values *Values) error {
 	r, err := fw.FirewallRule(ctx, values.ProjectID, values.FirewallID)
 	if err != nil {
 		return err
@@ -94,11 +145,14 @@ func delete(ctx context.Context, logr *services.Logger, fw *services.Firewall, v
 	if errs := fw.WaitGlobal(values.ProjectID, op); len(errs) > 0 {
 		return errs[0]
 	}
-	logr.Info("deleted firewall %q in project %q.", r.Name, values.ProjectID)
+	logging.FromContext(ctx).Info("deleted firewall %q in project %q.", r.Name, values.ProjectID)
 	return nil
 }
 
-func updateRange(ctx context.Context, logr *services.Logger, fw *services.Firewall, values *Values) error {
+func updateRange(ctx context.Context, fw *services.Firewall, values *Values) error {
+	if len(values.SourceRanges) == 0 {
+		return fmt.Errorf("no source ranges configured to restrict firewall %q in project %q", values.FirewallID, values.ProjectID)
+	}
 	r, err := fw.FirewallRule(ctx, values.ProjectID, values.FirewallID)
 	if err != nil {
 		return err
@@ -106,6 +160,6 @@ func updateRange(ctx context.Context, logr *services.Logger, fw *services.Firewa
 	if err := fw.UpdateFirewallRuleSourceRange(ctx, values.ProjectID, values.FirewallID, r.Name, values.SourceRanges); err != nil {
 		return err
 	}
-	logr.Info("updated source range firewall %q in project %q.", r.Name, values.ProjectID)
+	logging.FromContext(ctx).Info("updated source range firewall %q in project %q.", r.Name, values.ProjectID)
 	return nil
 }
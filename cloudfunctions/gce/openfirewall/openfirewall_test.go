@@ -17,6 +17,7 @@ package openfirewall
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	compute "google.golang.org/api/compute/v1"
@@ -62,7 +63,6 @@ func TestBlockSSH(t *testing.T) {
 			if err := Execute(ctx, values, &Services{
 				Firewall: svcs.Firewall,
 				Resource: svcs.Resource,
-				Logger:   svcs.Logger,
 			}); err != nil {
 				t.Errorf("%s failed to disable firewall :%q", tt.name, err)
 			}
@@ -72,6 +72,91 @@ func TestBlockSSH(t *testing.T) {
 		})
 	}
 }
+func TestBlockSSHNetwork(t *testing.T) {
+	const network = "https://www.googleapis.com/compute/v1/projects/test-project/global/networks/default"
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name     string
+		existing *compute.Firewall
+		priority int64
+		expected *compute.Firewall
+	}{
+		{
+			name:     "new rule",
+			priority: 100,
+			expected: &compute.Firewall{
+				Name:         "automatic-ssh-block-default",
+				Description:  "Block SSH TCP port 22 by Security Response Automation",
+				Direction:    "INGRESS",
+				Network:      network,
+				Priority:     100,
+				SourceRanges: []string{"10.0.0.1/32"},
+				Denied:       []*compute.FirewallDenied{{IPProtocol: "tcp", Ports: []string{"22"}}},
+			},
+		},
+		{
+			name:     "existing rule",
+			existing: &compute.Firewall{Id: 123, Name: "automatic-ssh-block-default", SourceRanges: []string{"10.0.0.2/32"}},
+			expected: &compute.Firewall{
+				Name:         "automatic-ssh-block-default",
+				Description:  "Block SSH TCP port 22 by Security Response Automation",
+				SourceRanges: []string{"10.0.0.1/32", "10.0.0.2/32"},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			svcs, computeStub := openFirewallSetup()
+			computeStub.StubbedInstance = &compute.Instance{
+				NetworkInterfaces: []*compute.NetworkInterface{{Name: "nic0", Network: network}, {Name: "nic1", Network: network}},
+			}
+			computeStub.StubbedFirewall = tt.existing
+			computeStub.FirewallRuleNotFound = tt.existing == nil
+			values := &Values{
+				ProjectID:    "test-project",
+				SourceRanges: []string{"10.0.0.1/32"},
+				Action:       "block_ssh",
+				Instance:     "attacked-instance",
+				Zone:         "us-central1-a",
+				Priority:     tt.priority,
+			}
+			if err := Execute(ctx, values, &Services{
+				Firewall: svcs.Firewall,
+				Host:     svcs.Host,
+				Resource: svcs.Resource,
+			}); err != nil {
+				t.Errorf("%s failed to block ssh: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(computeStub.SavedFirewallRule, tt.expected); diff != "" {
+				t.Errorf("%s failed diff:%s", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestRemoveExpiredBlocks(t *testing.T) {
+	ctx := context.Background()
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	svcs, computeStub := openFirewallSetup()
+	computeStub.StubbedFirewalls = &compute.FirewallList{Items: []*compute.Firewall{
+		{Name: "automatic-ssh-block-default", Description: "Block SSH TCP port 22 by Security Response Automation, expires-at=" + past},
+		{Name: "automatic-ssh-block-prod", Description: "Block SSH TCP port 22 by Security Response Automation, expires-at=" + future},
+		{Name: "automatic-ssh-block", Description: "Block SSH TCP port 22 by Security Response Automation"},
+		{Name: "allow-web", Description: "expires-at=" + past},
+	}}
+	values := &Values{ProjectID: "test-project", Action: "remove_expired_blocks"}
+	if err := Execute(ctx, values, &Services{
+		Firewall: svcs.Firewall,
+		Host:     svcs.Host,
+		Resource: svcs.Resource,
+	}); err != nil {
+		t.Errorf("failed to remove expired blocks: %q", err)
+	}
+	if diff := cmp.Diff(computeStub.DeletedFirewallRules, []string{"automatic-ssh-block-default"}); diff != "" {
+		t.Errorf("failed diff:%s", diff)
+	}
+}
+
 func TestOpenFirewall(t *testing.T) {
 	ctx := context.Background()
 	test := []struct {
@@ -117,7 +202,6 @@ func TestOpenFirewall(t *testing.T) {
 			if err := Execute(ctx, values, &Services{
 				Firewall: svcs.Firewall,
 				Resource: svcs.Resource,
-				Logger:   svcs.Logger,
 			}); err != nil {
 				t.Errorf("%s failed to disable firewall :%q", tt.name, err)
 			}
@@ -128,13 +212,33 @@ func TestOpenFirewall(t *testing.T) {
 	}
 }
 
+func TestRestrictWithoutSourceRanges(t *testing.T) {
+	ctx := context.Background()
+	svcs, computeStub := openFirewallSetup()
+	computeStub.StubbedFirewall = &compute.Firewall{Name: "default_allow_all", SourceRanges: []string{"0.0.0.0/0"}}
+	values := &Values{
+		ProjectID:  "test-project",
+		FirewallID: "open-firewall-id",
+		Action:     "update_source_range",
+	}
+	if err := Execute(ctx, values, &Services{
+		Firewall: svcs.Firewall,
+		Host:     svcs.Host,
+		Resource: svcs.Resource,
+	}); err == nil {
+		t.Errorf("expected error restricting without source ranges")
+	}
+	if computeStub.SavedFirewallRule != nil {
+		t.Errorf("firewall should not be modified, got: %+v", computeStub.SavedFirewallRule)
+	}
+}
+
 func openFirewallSetup() (*services.Global, *stubs.ComputeStub) {
-	loggerStub := &stubs.LoggerStub{}
-	log := services.NewLogger(loggerStub)
 	computeStub := &stubs.ComputeStub{}
 	storageStub := &stubs.StorageStub{}
 	crmStub := &stubs.ResourceManagerStub{}
 	res := services.NewResource(crmStub, storageStub)
 	f := services.NewFirewall(computeStub)
-	return &services.Global{Logger: log, Firewall: f, Resource: res}, computeStub
+	h := services.NewHost(computeStub)
+	return &services.Global{Firewall: f, Host: h, Resource: res}, computeStub
 }
@@ -0,0 +1,103 @@
+package quarantineinstance
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/pkg/errors"
+)
+
+// defaultQuarantineTag is the network tag applied when no tag is configured.
+const defaultQuarantineTag = "quarantine"
+
+// Values contains the required values needed for this function.
+//
+// Each step is applied independently, allowing an instance to only be stopped or only be tagged.
+type Values struct {
+	ProjectID string
+	Zone      string
+	Instance  string
+	// RemoveTags removes all existing network tags, dropping any allow rules targeting them.
+	RemoveTags bool
+	// ApplyQuarantineTag applies QuarantineTag and ensures deny all firewall rules target it.
+	ApplyQuarantineTag bool
+	// QuarantineTag is the network tag used to quarantine the instance, defaults to "quarantine".
+	QuarantineTag string
+	// StopInstance will optionally stop the instance once isolated.
+	StopInstance bool
+	DryRun       bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Host     *services.Host
+	Firewall *services.Firewall
+}
+
+// Execute isolates a compromised instance by replacing its network tags with a quarantine tag
+// matched by deny all firewall rules and optionally stopping it.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	tag := values.QuarantineTag
+	if tag == "" {
+		tag = defaultQuarantineTag
+	}
+	if values.DryRun {
+		if values.RemoveTags {
+			logging.FromContext(ctx).Info("dry_run on, would have removed network tags from instance %q in zone %q in project %q", values.Instance, values.Zone, values.ProjectID)
+		}
+		if values.ApplyQuarantineTag {
+			logging.FromContext(ctx).Info("dry_run on, would have applied tag %q to instance %q in zone %q in project %q", tag, values.Instance, values.Zone, values.ProjectID)
+		}
+		if values.StopInstance {
+			logging.FromContext(ctx).Info("dry_run on, would have stopped instance %q in zone %q in project %q", values.Instance, values.Zone, values.ProjectID)
+		}
+		return nil
+	}
+	add := []string{}
+	if values.ApplyQuarantineTag {
+		instance, err := services.Host.Instance(ctx, values.ProjectID, values.Zone, values.Instance)
+		if err != nil {
+			return errors.Wrap(err, "failed to get instance")
+		}
+		// Deny rules are created before tagging so the instance is never tagged without them.
+		for _, ni := range instance.NetworkInterfaces {
+			created, err := services.Firewall.QuarantineNetwork(ctx, values.ProjectID, ni.Network, tag)
+			if err != nil {
+				return errors.Wrapf(err, "failed to quarantine network %q", ni.Network)
+			}
+			if len(created) > 0 {
+				logging.FromContext(ctx).Info("created firewall rules %q in project %q", created, values.ProjectID)
+			}
+		}
+		add = append(add, tag)
+	}
+	if values.RemoveTags || values.ApplyQuarantineTag {
+		removed, err := services.Host.ReplaceNetworkTags(ctx, values.ProjectID, values.Zone, values.Instance, values.RemoveTags, add)
+		if err != nil {
+			return errors.Wrap(err, "failed to replace network tags")
+		}
+		logging.FromContext(ctx).Info("removed tags %q and applied %q to instance %q in zone %q in project %q", removed, add, values.Instance, values.Zone, values.ProjectID)
+	}
+	if values.StopInstance {
+		if err := services.Host.StopInstance(ctx, values.ProjectID, values.Zone, values.Instance); err != nil {
+			return errors.Wrap(err, "failed to stop instance")
+		}
+		logging.FromContext(ctx).Info("stopped instance %q in zone %q in project %q", values.Instance, values.Zone, values.ProjectID)
+	}
+	return nil
+}
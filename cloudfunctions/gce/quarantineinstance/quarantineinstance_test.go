@@ -0,0 +1,133 @@
+package quarantineinstance
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	compute "google.golang.org/api/compute/v1"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+func TestQuarantineInstance(t *testing.T) {
+	ctx := context.Background()
+	const network = "https://www.googleapis.com/compute/v1/projects/project-id/global/networks/default"
+	existingRules := map[string]*compute.Firewall{
+		"sra-quarantine-default-ingress": {Name: "sra-quarantine-default-ingress"},
+		"sra-quarantine-default-egress":  {Name: "sra-quarantine-default-egress"},
+	}
+	test := []struct {
+		name          string
+		values        *Values
+		rules         map[string]*compute.Firewall
+		expectedRules []string
+		expectedTags  *compute.Tags
+		expectedStop  string
+	}{
+		{
+			name: "all steps",
+			values: &Values{
+				RemoveTags:         true,
+				ApplyQuarantineTag: true,
+				StopInstance:       true,
+			},
+			expectedRules: []string{"sra-quarantine-default-ingress", "sra-quarantine-default-egress"},
+			expectedTags:  &compute.Tags{Items: []string{"quarantine"}, Fingerprint: "abc"},
+			expectedStop:  "instance-id",
+		},
+		{
+			name: "custom tag with existing rules",
+			values: &Values{
+				ApplyQuarantineTag: true,
+				QuarantineTag:      "isolated",
+			},
+			rules:        existingRules,
+			expectedTags: &compute.Tags{Items: []string{"http-server", "isolated"}, Fingerprint: "abc"},
+		},
+		{
+			name:         "remove tags only",
+			values:       &Values{RemoveTags: true},
+			expectedTags: &compute.Tags{Items: []string{}, Fingerprint: "abc"},
+		},
+		{
+			name:         "stop only",
+			values:       &Values{StopInstance: true},
+			expectedStop: "instance-id",
+		},
+		{
+			name: "dry run",
+			values: &Values{
+				RemoveTags:         true,
+				ApplyQuarantineTag: true,
+				StopInstance:       true,
+				DryRun:             true,
+			},
+		},
+	}
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			svcs, computeStub := setupQuarantineInstance()
+			computeStub.StubbedFirewallRules = map[string]*compute.Firewall{}
+			if tt.rules != nil {
+				computeStub.StubbedFirewallRules = tt.rules
+			}
+			computeStub.StubbedInstance = &compute.Instance{
+				NetworkInterfaces: []*compute.NetworkInterface{{Name: "nic0", Network: network}},
+				Tags:              &compute.Tags{Items: []string{"http-server"}, Fingerprint: "abc"},
+			}
+			computeStub.StubbedStopInstance = &compute.Operation{}
+			values := tt.values
+			values.ProjectID = "project-id"
+			values.Zone = "instance-zone"
+			values.Instance = "instance-id"
+
+			if err := Execute(ctx, values, &Services{
+				Host:     svcs.Host,
+				Firewall: svcs.Firewall,
+			}); err != nil {
+				t.Errorf("%s failed to quarantine instance :%q", tt.name, err)
+			}
+
+			rules := []string{}
+			for _, fw := range computeStub.InsertedFirewallRules {
+				rules = append(rules, fw.Name)
+				if diff := cmp.Diff([]string{"quarantine"}, fw.TargetTags); diff != "" {
+					t.Errorf("%v failed, target tags difference: %+v", tt.name, diff)
+				}
+			}
+			if diff := cmp.Diff(tt.expectedRules, rules, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("%v failed, rules difference: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(tt.expectedTags, computeStub.SavedInstanceTags); diff != "" {
+				t.Errorf("%v failed, tags difference: %+v", tt.name, diff)
+			}
+			if computeStub.SavedStoppedInstance != tt.expectedStop {
+				t.Errorf("%v failed, stopped instance got:%q want:%q", tt.name, computeStub.SavedStoppedInstance, tt.expectedStop)
+			}
+		})
+	}
+}
+
+func setupQuarantineInstance() (*services.Global, *stubs.ComputeStub) {
+	computeStub := &stubs.ComputeStub{}
+	h := services.NewHost(computeStub)
+	fw := services.NewFirewall(computeStub)
+	return &services.Global{Host: h, Firewall: fw}, computeStub
+}
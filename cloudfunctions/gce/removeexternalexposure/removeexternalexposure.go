@@ -0,0 +1,95 @@
+package removeexternalexposure
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+const (
+	// actionDelete deletes the forwarding rules fronting the backend service.
+	actionDelete = "delete"
+	// actionDisable drains the backend service, forwarding rules have no disabled state.
+	actionDisable = "disable"
+)
+
+// Values contains the required values needed for this function.
+type Values struct {
+	ProjectID      string
+	BackendService string
+	// RemediationAction is either "delete" or "disable", defaults to "delete".
+	RemediationAction string
+	// AllowServices lists backend services that are intentionally public.
+	AllowServices []string
+	DryRun        bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	LoadBalancer *services.LoadBalancer
+}
+
+// Execute removes the external exposure of a backend service by deleting the global forwarding rules
+// routing to it, or by draining the backend service when the action is "disable".
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	action := values.RemediationAction
+	if action == "" {
+		action = actionDelete
+	}
+	if action != actionDelete && action != actionDisable {
+		return fmt.Errorf("unsupported remediation action %q", action)
+	}
+	for _, s := range values.AllowServices {
+		if s == values.BackendService {
+			logging.FromContext(ctx).Info("backend service %q in project %q is allowed to be public", values.BackendService, values.ProjectID)
+			return nil
+		}
+	}
+	rules, err := services.LoadBalancer.ExternalForwardingRules(ctx, values.ProjectID, values.BackendService)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		logging.FromContext(ctx).Info("no external forwarding rules route to backend service %q in project %q", values.BackendService, values.ProjectID)
+		return nil
+	}
+	if action == actionDisable {
+		if values.DryRun {
+			logging.FromContext(ctx).Info("dry_run on, would have drained backend service %q fronted by %q in project %q", values.BackendService, rules, values.ProjectID)
+			return nil
+		}
+		previous, err := services.LoadBalancer.DrainBackendService(ctx, values.ProjectID, values.BackendService)
+		if err != nil {
+			return err
+		}
+		logging.FromContext(ctx).Info("drained backend service %q fronted by %q in project %q, previous capacity scalers: %v", values.BackendService, rules, values.ProjectID, previous)
+		return nil
+	}
+	for _, rule := range rules {
+		if values.DryRun {
+			logging.FromContext(ctx).Info("dry_run on, would have deleted forwarding rule %q fronting backend service %q in project %q", rule, values.BackendService, values.ProjectID)
+			continue
+		}
+		if err := services.LoadBalancer.DeleteForwardingRule(ctx, values.ProjectID, rule); err != nil {
+			return err
+		}
+		logging.FromContext(ctx).Info("deleted forwarding rule %q fronting backend service %q in project %q", rule, values.BackendService, values.ProjectID)
+	}
+	return nil
+}
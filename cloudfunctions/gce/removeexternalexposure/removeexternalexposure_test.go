@@ -0,0 +1,137 @@
+package removeexternalexposure
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	compute "google.golang.org/api/compute/v1"
+)
+
+const computeURL = "https://www.googleapis.com/compute/v1/projects/test-project/global/"
+
+func TestRemoveExternalExposure(t *testing.T) {
+	ctx := context.Background()
+	test := []struct {
+		name                  string
+		backendService        string
+		action                string
+		allowServices         []string
+		dryRun                bool
+		expectedDeletedRules  []string
+		expectedBackendsPatch *compute.BackendService
+		expectedError         bool
+	}{
+		{
+			name:                 "delete forwarding rules",
+			backendService:       "web-backend",
+			expectedDeletedRules: []string{"web-http", "web-https"},
+		},
+		{
+			name:           "disable backend service",
+			backendService: "web-backend",
+			action:         "disable",
+			expectedBackendsPatch: &compute.BackendService{
+				Backends: []*compute.Backend{
+					{Group: computeURL + "instanceGroups/web", CapacityScaler: 0, ForceSendFields: []string{"CapacityScaler"}},
+				},
+				Fingerprint: "abc=",
+			},
+		},
+		{
+			name:                 "path rule",
+			backendService:       "api-backend",
+			expectedDeletedRules: []string{"web-https"},
+		},
+		{
+			name:           "allowed service",
+			backendService: "web-backend",
+			allowServices:  []string{"web-backend"},
+		},
+		{
+			name:           "internal only",
+			backendService: "internal-backend",
+		},
+		{
+			name:           "dry run",
+			backendService: "web-backend",
+			dryRun:         true,
+		},
+		{
+			name:           "unsupported action",
+			backendService: "web-backend",
+			action:         "disconnect",
+			expectedError:  true,
+		},
+	}
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			computeStub := &stubs.ComputeStub{
+				StubbedForwardingRules: &compute.ForwardingRuleList{Items: []*compute.ForwardingRule{
+					{Name: "web-http", LoadBalancingScheme: "EXTERNAL", Target: computeURL + "targetHttpProxies/web-http-proxy"},
+					{Name: "web-https", LoadBalancingScheme: "EXTERNAL_MANAGED", Target: computeURL + "targetHttpsProxies/web-https-proxy"},
+					{Name: "internal", LoadBalancingScheme: "INTERNAL_MANAGED", Target: computeURL + "targetHttpProxies/internal-proxy"},
+				}},
+				StubbedTargetHTTPProxies: map[string]*compute.TargetHttpProxy{
+					"web-http-proxy": {UrlMap: computeURL + "urlMaps/web"},
+					"internal-proxy": {UrlMap: computeURL + "urlMaps/internal"},
+				},
+				StubbedTargetHTTPSProxies: map[string]*compute.TargetHttpsProxy{
+					"web-https-proxy": {UrlMap: computeURL + "urlMaps/web-https"},
+				},
+				StubbedURLMaps: map[string]*compute.UrlMap{
+					"web": {DefaultService: computeURL + "backendServices/web-backend"},
+					"web-https": {
+						DefaultService: computeURL + "backendServices/web-backend",
+						PathMatchers: []*compute.PathMatcher{{
+							DefaultService: computeURL + "backendServices/web-backend",
+							PathRules:      []*compute.PathRule{{Paths: []string{"/api/*"}, Service: computeURL + "backendServices/api-backend"}},
+						}},
+					},
+					"internal": {DefaultService: computeURL + "backendServices/internal-backend"},
+				},
+				StubbedBackendService: &compute.BackendService{
+					Name:        "web-backend",
+					Backends:    []*compute.Backend{{Group: computeURL + "instanceGroups/web", CapacityScaler: 1}},
+					Fingerprint: "abc=",
+				},
+			}
+			svcs := &Services{
+				LoadBalancer: services.NewLoadBalancer(computeStub),
+			}
+			values := &Values{
+				ProjectID:         "test-project",
+				BackendService:    tt.backendService,
+				RemediationAction: tt.action,
+				AllowServices:     tt.allowServices,
+				DryRun:            tt.dryRun,
+			}
+			err := Execute(ctx, values, svcs)
+			if (err != nil) != tt.expectedError {
+				t.Errorf("%s test failed, unexpected error result: %v", tt.name, err)
+			}
+			if diff := cmp.Diff(computeStub.DeletedForwardingRules, tt.expectedDeletedRules); diff != "" {
+				t.Errorf("%v failed, difference in deleted forwarding rules: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(computeStub.SavedBackendServicePatch, tt.expectedBackendsPatch); diff != "" {
+				t.Errorf("%v failed, difference in backend service patch: %+v", tt.name, diff)
+			}
+		})
+	}
+}
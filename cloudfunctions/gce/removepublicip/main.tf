@@ -29,7 +29,8 @@ resource "google_cloudfunctions_function" "remove-public-ip" {
     resource   = "threat-findings-remove-public-ip"
   }
   environment_variables = {
-    GCP_PROJECT = var.setup.automation-project
+    GCP_PROJECT    = var.setup.automation-project
+    SRA_CONFIG_URI = var.setup.config-uri
   }
   timeouts {
     create = "10m"
@@ -17,13 +17,19 @@ package removepublicip
 import (
 	"context"
 
+	"github.com/googlecloudplatform/security-response-automation/logging"
 	"github.com/googlecloudplatform/security-response-automation/services"
 	"github.com/pkg/errors"
 )
 
 // Values contains the required values needed for this function.
+//
+// When Projects or Labels are set only instances within one of the projects and carrying
+// all of the labels are modified.
 type Values struct {
 	ProjectID, InstanceZone, InstanceID string
+	Projects                            []string
+	Labels                              map[string]string
 	DryRun                              bool
 }
 
@@ -31,18 +37,50 @@ type Values struct {
 type Services struct {
 	Host     *services.Host
 	Resource *services.Resource
-	Logger   *services.Logger
 }
 
 // Execute removes the public IP of a GCE instance.
 func Execute(ctx context.Context, values *Values, services *Services) error {
+	if len(values.Projects) > 0 && !inProjects(values.ProjectID, values.Projects) {
+		logging.FromContext(ctx).Info("project %q not in configured projects, skipping instance %q", values.ProjectID, values.InstanceID)
+		return nil
+	}
+	if len(values.Labels) > 0 {
+		instance, err := services.Host.Instance(ctx, values.ProjectID, values.InstanceZone, values.InstanceID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get instance")
+		}
+		if !hasLabels(instance.Labels, values.Labels) {
+			logging.FromContext(ctx).Info("instance %q in project %q does not match configured labels, skipping", values.InstanceID, values.ProjectID)
+			return nil
+		}
+	}
 	if values.DryRun {
-		services.Logger.Info("dry_run on, would have removed public IP address for instance %q, in zone %q in project %q.", values.InstanceID, values.ProjectID)
+		logging.FromContext(ctx).Info("dry_run on, would have removed public IP address for instance %q, in zone %q in project %q.", values.InstanceID, values.InstanceZone, values.ProjectID)
 		return nil
 	}
 	if err := services.Host.RemoveExternalIPs(ctx, values.ProjectID, values.InstanceZone, values.InstanceID); err != nil {
 		return errors.Wrap(err, "failed to remove public ip")
 	}
-	services.Logger.Info("removed public IP address for instance %q, in zone %q in project %q.", values.InstanceID, values.InstanceZone, values.ProjectID)
+	logging.FromContext(ctx).Info("removed public IP address for instance %q, in zone %q in project %q.", values.InstanceID, values.InstanceZone, values.ProjectID)
 	return nil
 }
+
+func inProjects(projectID string, projects []string) bool {
+	for _, p := range projects {
+		if p == projectID {
+			return true
+		}
+	}
+	return false
+}
+
+// hasLabels returns true if every wanted label is present with the same value.
+func hasLabels(labels, want map[string]string) bool {
+	for k, v := range want {
+		if got, ok := labels[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
@@ -42,6 +42,8 @@ func TestRemovePublicIP(t *testing.T) {
 	test := []struct {
 		name                         string
 		instance                     *compute.Instance
+		projects                     []string
+		labels                       map[string]string
 		expectedDeletedAccessConfigs []stubs.NetworkAccessConfigStub
 	}{
 		{
@@ -58,6 +60,38 @@ func TestRemovePublicIP(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "matching project and labels",
+			instance: &compute.Instance{
+				Labels:            map[string]string{"env": "prod", "team": "web"},
+				NetworkInterfaces: []*compute.NetworkInterface{&externalNic0},
+			},
+			projects: []string{"other-project", "project-id"},
+			labels:   map[string]string{"env": "prod"},
+			expectedDeletedAccessConfigs: []stubs.NetworkAccessConfigStub{
+				{
+					NetworkInterfaceName: "nic0",
+					AccessConfigName:     "External NAT",
+				},
+			},
+		},
+		{
+			name: "project not configured",
+			instance: &compute.Instance{
+				NetworkInterfaces: []*compute.NetworkInterface{&externalNic0},
+			},
+			projects:                     []string{"other-project"},
+			expectedDeletedAccessConfigs: nil,
+		},
+		{
+			name: "labels do not match",
+			instance: &compute.Instance{
+				Labels:            map[string]string{"env": "dev"},
+				NetworkInterfaces: []*compute.NetworkInterface{&externalNic0},
+			},
+			labels:                       map[string]string{"env": "prod"},
+			expectedDeletedAccessConfigs: nil,
+		},
 	}
 	for _, tt := range test {
 		t.Run(tt.name, func(t *testing.T) {
@@ -67,12 +101,13 @@ func TestRemovePublicIP(t *testing.T) {
 				ProjectID:    "project-id",
 				InstanceZone: "instance-zone",
 				InstanceID:   "instance-id",
+				Projects:     tt.projects,
+				Labels:       tt.labels,
 			}
 
 			if err := Execute(ctx, values, &Services{
 				Host:     svcs.Host,
 				Resource: svcs.Resource,
-				Logger:   svcs.Logger,
 			}); err != nil {
 				t.Errorf("%s failed to remove public ip :%q", tt.name, err)
 			}
@@ -85,12 +120,10 @@ func TestRemovePublicIP(t *testing.T) {
 }
 
 func setupRemovePublicIP() (*services.Global, *stubs.ComputeStub) {
-	loggerStub := &stubs.LoggerStub{}
-	log := services.NewLogger(loggerStub)
 	computeStub := &stubs.ComputeStub{}
 	storageStub := &stubs.StorageStub{}
 	crmStub := &stubs.ResourceManagerStub{}
 	res := services.NewResource(crmStub, storageStub)
 	h := services.NewHost(computeStub)
-	return &services.Global{Logger: log, Host: h, Resource: res}, computeStub
+	return &services.Global{Host: h, Resource: res}, computeStub
 }
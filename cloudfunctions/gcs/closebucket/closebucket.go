@@ -17,34 +17,69 @@ package closebucket
 import (
 	"context"
 
+	"github.com/googlecloudplatform/security-response-automation/logging"
 	"github.com/googlecloudplatform/security-response-automation/services"
 )
 
 // publicUsers contains a slice of public users we want to remove.
 var publicUsers = []string{"allUsers", "allAuthenticatedUsers"}
 
+// skippedMark is the security mark written to the finding when a bucket is not closed.
+const skippedMark = "sra-close-bucket-skipped"
+
 // Values contains the required values needed for this function.
 type Values struct {
-	BucketName string
-	ProjectID  string
-	DryRun     bool
+	BucketName   string
+	ProjectID    string
+	FindingName  string
+	AllowBuckets []string
+	DryRun       bool
 }
 
 // Services contains the services needed for this function.
 type Services struct {
-	Resource *services.Resource
-	Logger   *services.Logger
+	Resource              *services.Resource
+	SecurityCommandCenter *services.CommandCenter
 }
 
-// Execute will remove any public users from buckets found within the provided folders.
+// Execute will remove any public users from the bucket's IAM policy and ACLs.
+//
+// Buckets within the allow list are left untouched and the finding is marked as skipped.
 func Execute(ctx context.Context, values *Values, services *Services) error {
+	if allowed(values.BucketName, values.AllowBuckets) {
+		logging.FromContext(ctx).Info("bucket %q in project %q is allowed, skipping", values.BucketName, values.ProjectID)
+		return markSkipped(ctx, values.FindingName, services)
+	}
 	if values.DryRun {
-		services.Logger.Info("dry_run on, would have removed public members from bucket %q in project %q", values.BucketName, values.ProjectID)
+		logging.FromContext(ctx).Info("dry_run on, would have removed public members from bucket %q in project %q", values.BucketName, values.ProjectID)
 		return nil
 	}
 	if err := services.Resource.RemoveMembersFromBucket(ctx, values.BucketName, publicUsers); err != nil {
 		return err
 	}
-	services.Logger.Info("removed public members from bucket %q in project %q", values.BucketName, values.ProjectID)
+	if err := services.Resource.RemoveEntitiesFromBucketACL(ctx, values.BucketName, publicUsers); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("removed public members from bucket %q in project %q", values.BucketName, values.ProjectID)
+	return nil
+}
+
+func allowed(bucketName string, allowBuckets []string) bool {
+	for _, b := range allowBuckets {
+		if b == bucketName {
+			return true
+		}
+	}
+	return false
+}
+
+func markSkipped(ctx context.Context, findingName string, services *Services) error {
+	if findingName == "" {
+		return nil
+	}
+	m := map[string]string{skippedMark: "allow_buckets"}
+	if _, err := services.SecurityCommandCenter.AddSecurityMarks(ctx, findingName, m); err != nil {
+		return err
+	}
 	return nil
 }
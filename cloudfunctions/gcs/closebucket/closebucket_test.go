@@ -19,40 +19,77 @@ import (
 	"testing"
 
 	"cloud.google.com/go/iam"
+	"cloud.google.com/go/storage"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
 	"github.com/googlecloudplatform/security-response-automation/services"
 )
 
+const findingName = "organizations/123/sources/456/findings/789"
+
 func TestCloseBucket(t *testing.T) {
 	ctx := context.Background()
 
 	test := []struct {
 		name           string
 		initialMembers []string
+		attrs          *storage.BucketAttrs
+		allowBuckets   []string
 		expected       []string
+		expectedACLs   []storage.ACLEntity
+		expectedMarks  map[string]string
 	}{
 		{
 			name:           "remove allUsers",
 			initialMembers: []string{"allUsers", "member:tom@tom.com"},
 			expected:       []string{"member:tom@tom.com"},
 		},
+		{
+			name:           "remove public acls",
+			initialMembers: []string{"member:tom@tom.com"},
+			attrs: &storage.BucketAttrs{ACL: []storage.ACLRule{
+				{Entity: storage.AllUsers, Role: storage.RoleReader},
+				{Entity: "user-tom@tom.com", Role: storage.RoleOwner},
+				{Entity: storage.AllAuthenticatedUsers, Role: storage.RoleReader},
+			}},
+			expected:     []string{"member:tom@tom.com"},
+			expectedACLs: []storage.ACLEntity{storage.AllUsers, storage.AllAuthenticatedUsers},
+		},
+		{
+			name:           "uniform bucket-level access has no acls",
+			initialMembers: []string{"allAuthenticatedUsers", "member:tom@tom.com"},
+			attrs: &storage.BucketAttrs{
+				UniformBucketLevelAccess: storage.UniformBucketLevelAccess{Enabled: true},
+				ACL:                      []storage.ACLRule{{Entity: storage.AllUsers, Role: storage.RoleReader}},
+			},
+			expected: []string{"member:tom@tom.com"},
+		},
+		{
+			name:           "allowed bucket is skipped",
+			initialMembers: []string{"allUsers", "member:tom@tom.com"},
+			attrs:          &storage.BucketAttrs{ACL: []storage.ACLRule{{Entity: storage.AllUsers, Role: storage.RoleReader}}},
+			allowBuckets:   []string{"open-bucket-name"},
+			expectedMarks:  map[string]string{"sra-close-bucket-skipped": "allow_buckets"},
+		},
 	}
 	for _, tt := range test {
 		t.Run(tt.name, func(t *testing.T) {
-			svcs, storageStub := closeBucketSetup()
+			svcs, storageStub, sccStub := closeBucketSetup()
 			for _, v := range tt.initialMembers {
 				storageStub.BucketPolicyResponse.Add(v, "project/viewer")
 			}
+			storageStub.BucketAttrsResponse = tt.attrs
 
 			required := &Values{
-				ProjectID:  "project-name",
-				BucketName: "open-bucket-name",
+				ProjectID:    "project-name",
+				BucketName:   "open-bucket-name",
+				FindingName:  findingName,
+				AllowBuckets: tt.allowBuckets,
 			}
 
 			if err := Execute(ctx, required, &Services{
-				Resource: svcs.Resource,
-				Logger:   svcs.Logger,
+				Resource:              svcs.Resource,
+				SecurityCommandCenter: svcs.SecurityCommandCenter,
 			}); err != nil {
 				t.Errorf("%s test failed want:%q", tt.name, err)
 			}
@@ -63,16 +100,25 @@ func TestCloseBucket(t *testing.T) {
 					t.Errorf("%v failed exp:%v got:%v", tt.name, tt.expected, s)
 				}
 			}
+			if diff := cmp.Diff(storageStub.DeletedBucketACLs, tt.expectedACLs); diff != "" {
+				t.Errorf("%v failed, difference: %+v", tt.name, diff)
+			}
+			var marks map[string]string
+			if r := sccStub.GetUpdateSecurityMarksRequest; r != nil {
+				marks = r.GetSecurityMarks().GetMarks()
+			}
+			if diff := cmp.Diff(marks, tt.expectedMarks); diff != "" {
+				t.Errorf("%v failed, difference: %+v", tt.name, diff)
+			}
 		})
 	}
 }
 
-func closeBucketSetup() (*services.Global, *stubs.StorageStub) {
-	loggerStub := &stubs.LoggerStub{}
-	log := services.NewLogger(loggerStub)
+func closeBucketSetup() (*services.Global, *stubs.StorageStub, *stubs.SecurityCommandCenterStub) {
 	crmStub := &stubs.ResourceManagerStub{}
 	storageStub := &stubs.StorageStub{}
+	sccStub := &stubs.SecurityCommandCenterStub{}
 	res := services.NewResource(crmStub, storageStub)
 	storageStub.BucketPolicyResponse = &iam.Policy{}
-	return &services.Global{Logger: log, Resource: res}, storageStub
+	return &services.Global{Resource: res, SecurityCommandCenter: services.NewCommandCenter(sccStub)}, storageStub, sccStub
 }
@@ -13,7 +13,7 @@
 # limitations under the License.
 resource "google_cloudfunctions_function" "close-bucket" {
   name                  = "CloseBucket"
-  description           = "Removes users that enable public viewing of GCS buckets."
+  description           = "Removes users and ACLs that enable public viewing of GCS buckets."
   runtime               = "go116"
   available_memory_mb   = 128
   source_archive_bucket = var.setup.gcf-bucket-name
@@ -29,7 +29,8 @@ resource "google_cloudfunctions_function" "close-bucket" {
     resource   = "threat-findings-close-bucket"
   }
   environment_variables = {
-    GCP_PROJECT = var.setup.automation-project
+    GCP_PROJECT    = var.setup.automation-project
+    SRA_CONFIG_URI = var.setup.config-uri
   }
   timeouts {
     create = "10m"
@@ -17,6 +17,7 @@ package enablebucketonlypolicy
 import (
 	"context"
 
+	"github.com/googlecloudplatform/security-response-automation/logging"
 	"github.com/googlecloudplatform/security-response-automation/services"
 )
 
@@ -30,18 +31,17 @@ type Values struct {
 // Services contains the services needed for this function.
 type Services struct {
 	Resource *services.Resource
-	Logger   *services.Logger
 }
 
 // Execute will enable bucket only policy on buckets found within the provided folders.
 func Execute(ctx context.Context, values *Values, services *Services) error {
 	if values.DryRun {
-		services.Logger.Info("dry_run on, would have enabled Bucket only policy on bucket %q in project %q.", values.BucketName, values.ProjectID)
+		logging.FromContext(ctx).Info("dry_run on, would have enabled Bucket only policy on bucket %q in project %q.", values.BucketName, values.ProjectID)
 		return nil
 	}
 	if err := services.Resource.EnableBucketOnlyPolicy(ctx, values.BucketName); err != nil {
 		return err
 	}
-	services.Logger.Info("Bucket only policy enabled on bucket %q in project %q.", values.BucketName, values.ProjectID)
+	logging.FromContext(ctx).Info("Bucket only policy enabled on bucket %q in project %q.", values.BucketName, values.ProjectID)
 	return nil
 }
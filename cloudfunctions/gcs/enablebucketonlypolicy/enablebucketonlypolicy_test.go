@@ -28,12 +28,18 @@ func TestEnableBucketOnlyPolicy(t *testing.T) {
 
 	test := []struct {
 		name     string
+		dryRun   bool
 		expected string
 	}{
 		{
 			name:     "enable bucket only policy",
 			expected: "bucket-to-enable-policy",
 		},
+		{
+			name:     "dry run",
+			dryRun:   true,
+			expected: "",
+		},
 	}
 	for _, tt := range test {
 		t.Run(tt.name, func(t *testing.T) {
@@ -41,30 +47,26 @@ func TestEnableBucketOnlyPolicy(t *testing.T) {
 			values := &Values{
 				ProjectID:  "project-name",
 				BucketName: "bucket-to-enable-policy",
+				DryRun:     tt.dryRun,
 			}
 
 			if err := Execute(ctx, values, &Services{
 				Resource: svcs.Resource,
-				Logger:   svcs.Logger,
 			}); err != nil {
 				t.Errorf("%s test failed want:%q", tt.name, err)
 			}
 
-			if tt.expected != "" {
-				if s := storageStub.EnabledPolicyOnBucket; s != tt.expected {
-					t.Errorf("%v failed exp:%v got:%v", tt.name, tt.expected, s)
-				}
+			if s := storageStub.EnabledPolicyOnBucket; s != tt.expected {
+				t.Errorf("%v failed exp:%v got:%v", tt.name, tt.expected, s)
 			}
 		})
 	}
 }
 
 func enableBucketOnlyPolicySetup() (*services.Global, *stubs.StorageStub) {
-	loggerStub := &stubs.LoggerStub{}
-	log := services.NewLogger(loggerStub)
 	crmStub := &stubs.ResourceManagerStub{}
 	storageStub := &stubs.StorageStub{}
 	res := services.NewResource(crmStub, storageStub)
 	storageStub.BucketPolicyResponse = &iam.Policy{}
-	return &services.Global{Logger: log, Resource: res}, storageStub
+	return &services.Global{Resource: res}, storageStub
 }
@@ -29,7 +29,8 @@ resource "google_cloudfunctions_function" "enable-bucket-only-policy" {
     resource   = "threat-findings-enable-bucket-only-policy"
   }
   environment_variables = {
-    GCP_PROJECT = var.setup.automation-project
+    GCP_PROJECT    = var.setup.automation-project
+    SRA_CONFIG_URI = var.setup.config-uri
   }
   timeouts {
     create = "10m"
@@ -0,0 +1,58 @@
+package deletepod
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// flaggedAnnotation is added to the pod instead of deleting it when dry run is on.
+const flaggedAnnotation = "sra-flagged-finding"
+
+// Values contains the required values needed for this function.
+type Values struct {
+	ProjectID, Zone, ClusterID string
+	Namespace, Pod             string
+	FindingName                string
+	DryRun                     bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Kubernetes *services.Kubernetes
+}
+
+// Execute deletes the pod, or annotates it with the finding name when dry run is on.
+func Execute(ctx context.Context, values *Values, service *Services) error {
+	if values.Pod == "" {
+		return fmt.Errorf("no pod found for cluster %q in project %q", values.ClusterID, values.ProjectID)
+	}
+	if values.DryRun {
+		if err := service.Kubernetes.AnnotatePod(ctx, values.Namespace, values.Pod, map[string]string{flaggedAnnotation: values.FindingName}); err != nil {
+			return err
+		}
+		logging.FromContext(ctx).Info("dry_run on, annotated instead of deleting pod %q in namespace %q of cluster %q in project %q", values.Pod, values.Namespace, values.ClusterID, values.ProjectID)
+		return nil
+	}
+	if err := service.Kubernetes.DeletePod(ctx, values.Namespace, values.Pod); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("deleted pod %q in namespace %q of cluster %q in project %q", values.Pod, values.Namespace, values.ClusterID, values.ProjectID)
+	return nil
+}
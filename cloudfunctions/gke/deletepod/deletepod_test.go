@@ -0,0 +1,87 @@
+package deletepod
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+func TestDeletePod(t *testing.T) {
+	ctx := context.Background()
+	const findingName = "organizations/154584661726/sources/8072959356540502587/findings/b8a0f6d3e2c14c6c9d1f2e3a4b5c6d7e"
+	test := []struct {
+		name            string
+		pod             string
+		dryRun          bool
+		expectedDeleted []string
+		expectedPatches map[string]interface{}
+		expectedError   bool
+	}{
+		{
+			name:            "delete pod",
+			pod:             "miner",
+			expectedDeleted: []string{services.PodPath("default", "miner")},
+		},
+		{
+			name:   "dry run annotates pod",
+			pod:    "miner",
+			dryRun: true,
+			expectedPatches: map[string]interface{}{
+				services.PodPath("default", "miner"): map[string]interface{}{
+					"metadata": map[string]interface{}{"annotations": map[string]string{"sra-flagged-finding": findingName}},
+				},
+			},
+		},
+		{
+			name:          "finding without pod",
+			expectedError: true,
+		},
+	}
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			svcs, k8sStub := deletePodSetup()
+			values := &Values{
+				ProjectID:   "project-test",
+				Zone:        "us-central1-a",
+				ClusterID:   "test-cluster",
+				Namespace:   "default",
+				Pod:         tt.pod,
+				FindingName: findingName,
+				DryRun:      tt.dryRun,
+			}
+			err := Execute(ctx, values, svcs)
+			if (err != nil) != tt.expectedError {
+				t.Errorf("%s test failed, unexpected error result: %v", tt.name, err)
+			}
+			if diff := cmp.Diff(k8sStub.DeletedPaths, tt.expectedDeleted); diff != "" {
+				t.Errorf("%v failed, difference in deleted pods: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(k8sStub.SavedPatches, tt.expectedPatches); diff != "" {
+				t.Errorf("%v failed, difference in pod patches: %+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func deletePodSetup() (*Services, *stubs.KubernetesStub) {
+	k8sStub := &stubs.KubernetesStub{}
+	k8s := services.NewKubernetes(k8sStub)
+	return &Services{Kubernetes: k8s}, k8sStub
+}
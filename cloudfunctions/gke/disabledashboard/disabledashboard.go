@@ -17,6 +17,7 @@ package disabledashboard
 import (
 	"context"
 
+	"github.com/googlecloudplatform/security-response-automation/logging"
 	"github.com/googlecloudplatform/security-response-automation/services"
 )
 
@@ -30,18 +31,17 @@ type Values struct {
 type Services struct {
 	Container *services.Container
 	Resource  *services.Resource
-	Logger    *services.Logger
 }
 
 // Execute disables the Kubernetes dashboard.
 func Execute(ctx context.Context, values *Values, service *Services) error {
 	if values.DryRun {
-		service.Logger.Info("dry_run on, would have disabled dashboard from custer %q in zone %q in project %q", values.ClusterID, values.Zone, values.ProjectID)
+		logging.FromContext(ctx).Info("dry_run on, would have disabled dashboard from custer %q in zone %q in project %q", values.ClusterID, values.Zone, values.ProjectID)
 		return nil
 	}
 	if _, err := service.Container.DisableDashboard(ctx, values.ProjectID, values.Zone, values.ClusterID); err != nil {
 		return err
 	}
-	service.Logger.Info("successfully disabled dashboard from cluster %q in project %q", values.ClusterID, values.ProjectID)
+	logging.FromContext(ctx).Info("successfully disabled dashboard from cluster %q in project %q", values.ClusterID, values.ProjectID)
 	return nil
 }
@@ -52,7 +52,6 @@ func TestDisableDashboard(t *testing.T) {
 		if err := Execute(ctx, values, &Services{
 			Container: svcs.Container,
 			Resource:  svcs.Resource,
-			Logger:    svcs.Logger,
 		}); err != nil {
 			t.Errorf("%s test failed want:%q", tt.name, err)
 		}
@@ -63,12 +62,10 @@ func TestDisableDashboard(t *testing.T) {
 }
 
 func disableDashboardSetup() (*services.Global, *stubs.ContainerStub) {
-	loggerStub := &stubs.LoggerStub{}
-	log := services.NewLogger(loggerStub)
 	contStub := &stubs.ContainerStub{}
 	cont := services.NewContainer(contStub)
 	crmStub := &stubs.ResourceManagerStub{}
 	storageStub := &stubs.StorageStub{}
 	resource := services.NewResource(crmStub, storageStub)
-	return &services.Global{Logger: log, Resource: resource, Container: cont}, contStub
+	return &services.Global{Resource: resource, Container: cont}, contStub
 }
@@ -30,7 +30,8 @@ resource "google_cloudfunctions_function" "disable-dashboard" {
     resource   = "threat-findings-disable-dashboard"
   }
   environment_variables = {
-    GCP_PROJECT = var.setup.automation-project
+    GCP_PROJECT    = var.setup.automation-project
+    SRA_CONFIG_URI = var.setup.config-uri
   }
   timeouts {
     create = "10m"
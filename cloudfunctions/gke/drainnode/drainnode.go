@@ -0,0 +1,58 @@
+package drainnode
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// quarantineLabels are added to the node so forensics tooling can find and attach to it.
+var quarantineLabels = map[string]string{"quarantine": "true"}
+
+// Values contains the required values needed for this function.
+type Values struct {
+	ProjectID, Zone, ClusterID, Node string
+	DryRun                           bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Kubernetes *services.Kubernetes
+}
+
+// Execute cordons the node, labels it as quarantined and drains its workloads.
+func Execute(ctx context.Context, values *Values, service *Services) error {
+	if values.Node == "" {
+		return fmt.Errorf("no node found for cluster %q in project %q", values.ClusterID, values.ProjectID)
+	}
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have cordoned and drained node %q of cluster %q in zone %q in project %q", values.Node, values.ClusterID, values.Zone, values.ProjectID)
+		return nil
+	}
+	if err := service.Kubernetes.CordonNode(ctx, values.Node, quarantineLabels); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("cordoned and quarantined node %q of cluster %q in project %q", values.Node, values.ClusterID, values.ProjectID)
+	evicted, err := service.Kubernetes.DrainNode(ctx, values.Node)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("drained node %q of cluster %q in project %q, evicted pods: %q", values.Node, values.ClusterID, values.ProjectID, evicted)
+	return nil
+}
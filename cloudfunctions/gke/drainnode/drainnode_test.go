@@ -0,0 +1,116 @@
+package drainnode
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+func TestDrainNode(t *testing.T) {
+	ctx := context.Background()
+	const node = "gke-test-cluster-default-pool-3b1f2a7c-x9k2"
+	pods := services.KubernetesPodList{Items: []services.KubernetesPod{
+		{Metadata: services.KubernetesObjectMeta{Name: "miner", Namespace: "default"}},
+		{Metadata: services.KubernetesObjectMeta{Name: "web-6d4f", Namespace: "prod", OwnerReferences: []services.KubernetesOwnerReference{{Kind: "ReplicaSet", Name: "web"}}}},
+		{Metadata: services.KubernetesObjectMeta{Name: "fluentd-x2k", Namespace: "kube-system", OwnerReferences: []services.KubernetesOwnerReference{{Kind: "DaemonSet", Name: "fluentd"}}}},
+		{Metadata: services.KubernetesObjectMeta{Name: "kube-proxy", Namespace: "kube-system", Annotations: map[string]string{"kubernetes.io/config.mirror": "1a2b"}}},
+	}}
+	cordon := map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": map[string]string{"quarantine": "true"}},
+		"spec":     map[string]interface{}{"unschedulable": true},
+	}
+	test := []struct {
+		name              string
+		node              string
+		dryRun            bool
+		evictErr          map[string]error
+		expectedPatches   map[string]interface{}
+		expectedEvictions []string
+		expectedError     bool
+	}{
+		{
+			name:            "cordon and drain node",
+			node:            node,
+			expectedPatches: map[string]interface{}{services.NodePath(node): cordon},
+			expectedEvictions: []string{
+				services.PodPath("default", "miner") + "/eviction",
+				services.PodPath("prod", "web-6d4f") + "/eviction",
+			},
+		},
+		{
+			name:              "eviction blocked by disruption budget",
+			node:              node,
+			evictErr:          map[string]error{services.PodPath("prod", "web-6d4f") + "/eviction": fmt.Errorf("429")},
+			expectedPatches:   map[string]interface{}{services.NodePath(node): cordon},
+			expectedEvictions: []string{services.PodPath("default", "miner") + "/eviction"},
+			expectedError:     true,
+		},
+		{
+			name:   "dry run",
+			node:   node,
+			dryRun: true,
+		},
+		{
+			name:          "finding without node",
+			expectedError: true,
+		},
+	}
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			svcs, k8sStub := drainNodeSetup()
+			k8sStub.StubbedGet = map[string]interface{}{services.NodePodsPath(node): pods}
+			k8sStub.StubbedPostErr = tt.evictErr
+			values := &Values{
+				ProjectID: "project-test",
+				Zone:      "us-central1-a",
+				ClusterID: "test-cluster",
+				Node:      tt.node,
+				DryRun:    tt.dryRun,
+			}
+			err := Execute(ctx, values, svcs)
+			if (err != nil) != tt.expectedError {
+				t.Errorf("%s test failed, unexpected error result: %v", tt.name, err)
+			}
+			// The error of each eviction is returned.
+			if err != nil && tt.evictErr != nil && !strings.Contains(err.Error(), "prod/web-6d4f") {
+				t.Errorf("%s test failed, eviction error missing from %q", tt.name, err)
+			}
+			for path, eviction := range k8sStub.SavedPosts {
+				if v := eviction.(map[string]interface{})["apiVersion"]; v != "policy/v1" {
+					t.Errorf("%s test failed, got eviction %q of API version %q", tt.name, path, v)
+				}
+			}
+			if diff := cmp.Diff(k8sStub.SavedPatches, tt.expectedPatches); diff != "" {
+				t.Errorf("%v failed, difference in node patches: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(k8sStub.PostedPaths, tt.expectedEvictions); diff != "" {
+				t.Errorf("%v failed, difference in evicted pods: %+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func drainNodeSetup() (*Services, *stubs.KubernetesStub) {
+	k8sStub := &stubs.KubernetesStub{}
+	k8s := services.NewKubernetes(k8sStub)
+	return &Services{Kubernetes: k8s}, k8sStub
+}
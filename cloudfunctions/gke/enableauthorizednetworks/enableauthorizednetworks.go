@@ -0,0 +1,53 @@
+package enableauthorizednetworks
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// Values contains the required values needed for this function.
+type Values struct {
+	ProjectID, Zone, ClusterID string
+	// CIDRBlocks are the only networks allowed to reach the Kubernetes master.
+	CIDRBlocks []string
+	DryRun     bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Container *services.Container
+	Resource  *services.Resource
+}
+
+// Execute enables master authorized networks on the cluster.
+func Execute(ctx context.Context, values *Values, service *Services) error {
+	if len(values.CIDRBlocks) == 0 {
+		return fmt.Errorf("no cidr blocks configured for cluster %q in project %q", values.ClusterID, values.ProjectID)
+	}
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have enabled master authorized networks %q on cluster %q in zone %q in project %q", values.CIDRBlocks, values.ClusterID, values.Zone, values.ProjectID)
+		return nil
+	}
+	if _, err := service.Container.EnableMasterAuthorizedNetworks(ctx, values.ProjectID, values.Zone, values.ClusterID, values.CIDRBlocks); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("successfully enabled master authorized networks %q on cluster %q in project %q", values.CIDRBlocks, values.ClusterID, values.ProjectID)
+	return nil
+}
@@ -0,0 +1,95 @@
+package enableauthorizednetworks
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	"google.golang.org/api/container/v1"
+)
+
+func TestEnableAuthorizedNetworks(t *testing.T) {
+	ctx := context.Background()
+
+	test := []struct {
+		name            string
+		cidrBlocks      []string
+		dryRun          bool
+		expectedRequest *container.UpdateClusterRequest
+		expectedError   bool
+	}{
+		{
+			name:       "enable master authorized networks",
+			cidrBlocks: []string{"10.0.0.0/8", "199.27.199.0/24"},
+			expectedRequest: &container.UpdateClusterRequest{
+				Update: &container.ClusterUpdate{
+					DesiredMasterAuthorizedNetworksConfig: &container.MasterAuthorizedNetworksConfig{
+						Enabled: true,
+						CidrBlocks: []*container.CidrBlock{
+							{CidrBlock: "10.0.0.0/8"},
+							{CidrBlock: "199.27.199.0/24"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:            "dry run",
+			cidrBlocks:      []string{"10.0.0.0/8"},
+			dryRun:          true,
+			expectedRequest: nil,
+		},
+		{
+			name:            "no cidr blocks configured",
+			expectedRequest: nil,
+			expectedError:   true,
+		},
+	}
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			svcs, contStub := enableAuthorizedNetworksSetup()
+			values := &Values{
+				ProjectID:  "project-test",
+				Zone:       "us-central1-a",
+				ClusterID:  "test-cluster",
+				CIDRBlocks: tt.cidrBlocks,
+				DryRun:     tt.dryRun,
+			}
+			err := Execute(ctx, values, &Services{
+				Container: svcs.Container,
+				Resource:  svcs.Resource,
+			})
+			if (err != nil) != tt.expectedError {
+				t.Errorf("%s test failed, unexpected error result: %v", tt.name, err)
+			}
+			if diff := cmp.Diff(contStub.UpdatedCluster, tt.expectedRequest); diff != "" {
+				t.Errorf("%v failed\n exp:%v\n got:%v", tt.name, tt.expectedRequest, contStub.UpdatedCluster)
+			}
+		})
+	}
+}
+
+func enableAuthorizedNetworksSetup() (*services.Global, *stubs.ContainerStub) {
+	contStub := &stubs.ContainerStub{}
+	cont := services.NewContainer(contStub)
+	crmStub := &stubs.ResourceManagerStub{}
+	storageStub := &stubs.StorageStub{}
+	resource := services.NewResource(crmStub, storageStub)
+	return &services.Global{Resource: resource, Container: cont}, contStub
+}
@@ -0,0 +1,49 @@
+package backuppolicies
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/pkg/errors"
+)
+
+// Values contains the required values needed for this function.
+type Values struct {
+	// Bucket is the GCS bucket snapshots are written to.
+	Bucket string
+	// Resources are the policies to snapshot, either projects/<project-id> or organizations/<organization-id>.
+	Resources []string
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	PolicyBackup *services.PolicyBackup
+}
+
+// Execute snapshots the IAM policy of each resource into the bucket.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	now := time.Now()
+	for _, resource := range values.Resources {
+		snapshot, err := services.PolicyBackup.Store(ctx, values.Bucket, resource, now)
+		if err != nil {
+			return errors.Wrapf(err, "failed to back up policy of %q", resource)
+		}
+		logging.FromContext(ctx).Info("backed up policy of %q to %q in bucket %q.", resource, snapshot.Object, values.Bucket)
+	}
+	return nil
+}
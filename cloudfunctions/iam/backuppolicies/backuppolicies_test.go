@@ -0,0 +1,46 @@
+package backuppolicies
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"testing"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	crm "google.golang.org/api/cloudresourcemanager/v1"
+)
+
+func TestBackupPolicies(t *testing.T) {
+	ctx := context.Background()
+	crmStub := &stubs.ResourceManagerStub{
+		GetPolicyResponse: &crm.Policy{Bindings: []*crm.Binding{{Role: "roles/editor", Members: []string{"user:bob@gmail.com"}}}},
+	}
+	storageStub := &stubs.StorageStub{}
+	values := &Values{Bucket: "bucket", Resources: []string{"projects/test-project", "organizations/1234"}}
+	if err := Execute(ctx, values, &Services{
+		PolicyBackup: services.NewPolicyBackup(crmStub, storageStub),
+	}); err != nil {
+		t.Fatalf("failed to back up policies: %q", err)
+	}
+	for _, resource := range values.Resources {
+		names, err := storageStub.ListObjects(ctx, "bucket", "iam-policies/"+resource+"/")
+		if err != nil {
+			t.Fatalf("failed to list objects: %q", err)
+		}
+		if len(names) != 1 {
+			t.Errorf("expected one snapshot of %q, got %v", resource, names)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+# Package automation contains the Cloud Function code to automate actions.
+
+# Copyright 2019 Google LLC
+
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+
+# 	https://www.apache.org/licenses/LICENSE-2.0
+
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+resource "google_cloudfunctions_function" "backup_policies_function" {
+  name                  = "BackupIAMPolicies"
+  description           = "Stores snapshots of project and organization IAM policies in GCS."
+  runtime               = "go116"
+  available_memory_mb   = 128
+  source_archive_bucket = var.setup.gcf-bucket-name
+  source_archive_object = var.setup.gcf-object-name
+  timeout               = 60
+  project               = var.setup.automation-project
+  region                = var.setup.region
+  entry_point           = "BackupIAMPolicies"
+  service_account_email = var.setup.automation-service-account
+
+  event_trigger {
+    event_type = "google.pubsub.topic.publish"
+    resource   = "threat-findings-backup-iam-policies"
+  }
+  environment_variables = {
+    GCP_PROJECT    = var.setup.automation-project
+    SRA_CONFIG_URI = var.setup.config-uri
+  }
+  timeouts {
+    create = "10m"
+    update = "10m"
+  }
+}
+
+# Bucket holding IAM policy snapshots.
+resource "google_storage_bucket" "backup_bucket" {
+  name               = "${var.setup.automation-project}-iam-policy-backups"
+  project            = var.setup.automation-project
+  location           = "US"
+  bucket_policy_only = true
+}
+
+# Required to store IAM policy snapshots.
+resource "google_storage_bucket_iam_member" "backup_bucket_admin" {
+  bucket = google_storage_bucket.backup_bucket.name
+  role   = "roles/storage.objectAdmin"
+  member = "serviceAccount:${var.setup.automation-service-account}"
+}
+
+# Required to read IAM policies of projects within this folder.
+resource "google_folder_iam_member" "backup_policies_cloudfunction-folder-bind" {
+  count = length(var.folder-ids)
+
+  folder = "folders/${var.folder-ids[count.index]}"
+  role   = "roles/iam.securityReviewer"
+  member = "serviceAccount:${var.setup.automation-service-account}"
+}
+
+# PubSub topic to trigger this automation.
+resource "google_pubsub_topic" "topic" {
+  name    = "threat-findings-backup-iam-policies"
+  project = var.setup.automation-project
+}
+
+# Periodically triggers a backup of the configured resources.
+resource "google_cloud_scheduler_job" "backup_policies_job" {
+  name     = "backup-iam-policies"
+  project  = var.setup.automation-project
+  region   = var.setup.region
+  schedule = var.schedule
+
+  pubsub_target {
+    topic_name = google_pubsub_topic.topic.id
+    data       = base64encode(jsonencode({ Bucket = google_storage_bucket.backup_bucket.name, Resources = var.resources }))
+  }
+}
@@ -0,0 +1,17 @@
+variable "setup" {}
+
+variable "folder-ids" {
+  type        = list(string)
+  description = "Back up IAM policies of projects within the given folder IDs."
+}
+
+variable "resources" {
+  type        = list(string)
+  description = "Resources to back up, either projects/<project-id> or organizations/<organization-id>."
+}
+
+variable "schedule" {
+  type        = string
+  default     = "0 * * * *"
+  description = "Cron schedule on which IAM policies are backed up."
+}
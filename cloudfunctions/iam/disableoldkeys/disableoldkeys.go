@@ -0,0 +1,83 @@
+package disableoldkeys
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// defaultMaxAge is the age after which keys are disabled if no maximum age is configured.
+const defaultMaxAge = 90 * 24 * time.Hour
+
+// Values contains the required values needed for this function.
+type Values struct {
+	ProjectID      string
+	ServiceAccount string
+	MaxAge         time.Duration
+	DryRun         bool
+	Output         []string
+	SendGrid       struct {
+		APIKey string
+		From   string
+		To     []string
+	}
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	IAM      *services.IAM
+	Resource *services.Resource
+}
+
+// Output contains the output of this function.
+type Output struct {
+	// DisabledKeys are the resource names of the keys that were disabled.
+	DisabledKeys []string
+	// Owners are the email addresses of the project's owners to be notified.
+	Owners []string
+}
+
+// Execute disables the user-managed keys of the service account older than the maximum age.
+func Execute(ctx context.Context, values *Values, services *Services) (*Output, error) {
+	maxAge := values.MaxAge
+	if maxAge == 0 {
+		maxAge = defaultMaxAge
+	}
+	keys, err := services.IAM.OldServiceAccountKeys(ctx, values.ProjectID, values.ServiceAccount, maxAge, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		logging.FromContext(ctx).Info("no keys of %q older than %s", values.ServiceAccount, maxAge)
+		return nil, nil
+	}
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have disabled keys %q of %q", keys, values.ServiceAccount)
+		return nil, nil
+	}
+	if err := services.IAM.DisableKeys(ctx, keys); err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("disabled keys %q of %q", keys, values.ServiceAccount)
+	owners, err := services.Resource.ProjectOwners(ctx, values.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	return &Output{DisabledKeys: keys, Owners: owners}, nil
+}
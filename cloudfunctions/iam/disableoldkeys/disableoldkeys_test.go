@@ -0,0 +1,85 @@
+package disableoldkeys
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	crm "google.golang.org/api/cloudresourcemanager/v1"
+	iam "google.golang.org/api/iam/v1"
+)
+
+func TestDisableOldKeys(t *testing.T) {
+	ctx := context.Background()
+	const prefix = "projects/test-project/serviceAccounts/sa@test-project.iam.gserviceaccount.com/keys/"
+	keys := []*iam.ServiceAccountKey{
+		{Name: prefix + "old", ValidAfterTime: "2019-01-01T00:00:00Z"},
+		{Name: prefix + "new", ValidAfterTime: time.Now().Add(-time.Hour).Format(time.RFC3339)},
+	}
+	for _, tt := range []struct {
+		name             string
+		dryRun           bool
+		maxAge           time.Duration
+		expectedDisabled []string
+		expectedOutput   *Output
+	}{
+		{
+			name:             "disable keys older than default",
+			expectedDisabled: []string{prefix + "old"},
+			expectedOutput:   &Output{DisabledKeys: []string{prefix + "old"}, Owners: []string{"owner@test.com"}},
+		},
+		{
+			name:             "disable keys older than max age",
+			maxAge:           time.Minute,
+			expectedDisabled: []string{prefix + "old", prefix + "new"},
+			expectedOutput:   &Output{DisabledKeys: []string{prefix + "old", prefix + "new"}, Owners: []string{"owner@test.com"}},
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			iamStub := &stubs.IAMStub{StubbedKeys: keys}
+			crmStub := &stubs.ResourceManagerStub{GetPolicyResponse: &crm.Policy{Bindings: []*crm.Binding{
+				{Role: "roles/owner", Members: []string{"user:owner@test.com", "serviceAccount:sa@test-project.iam.gserviceaccount.com"}},
+			}}}
+			values := &Values{
+				ProjectID:      "test-project",
+				ServiceAccount: "sa@test-project.iam.gserviceaccount.com",
+				MaxAge:         tt.maxAge,
+				DryRun:         tt.dryRun,
+			}
+			output, err := Execute(ctx, values, &Services{
+				IAM:      services.NewIAM(iamStub),
+				Resource: services.NewResource(crmStub, &stubs.StorageStub{}),
+			})
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(tt.expectedDisabled, iamStub.DisabledKeys); diff != "" {
+				t.Errorf("%s failed, difference in disabled keys: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(tt.expectedOutput, output); diff != "" {
+				t.Errorf("%s failed, difference in output: %+v", tt.name, diff)
+			}
+		})
+	}
+}
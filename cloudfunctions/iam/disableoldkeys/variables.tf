@@ -0,0 +1,6 @@
+variable "setup" {}
+
+variable "folder-ids" {
+  type        = list(string)
+  description = "Disable old service account keys if they are within the given folder IDs."
+}
@@ -17,6 +17,7 @@ package enableauditlogs
 import (
 	"context"
 
+	"github.com/googlecloudplatform/security-response-automation/logging"
 	"github.com/googlecloudplatform/security-response-automation/services"
 )
 
@@ -28,24 +29,46 @@ type Required struct {
 // Services contains the services needed for this function.
 type Services struct {
 	Resource *services.Resource
-	Logger   *services.Logger
 }
 
 // Values contains the required values needed for this function.
 type Values struct {
 	ProjectID string
-	DryRun    bool
+	// Services to enable data access logs for, merging with their existing audit configs. Every
+	// log type is enabled for allServices if empty.
+	Services []string
+	DryRun   bool
 }
 
 // Execute is the entry point for the Cloud Function to enable audit logs for a specific project.
 func Execute(ctx context.Context, values *Values, services *Services) error {
+	if len(values.Services) > 0 {
+		return enableServiceAuditLogs(ctx, values, services)
+	}
 	if values.DryRun {
-		services.Logger.Info("dry_run on, would have enabled data access audit logs in project %q", values.ProjectID)
+		logging.FromContext(ctx).Info("dry_run on, would have enabled data access audit logs in project %q", values.ProjectID)
 		return nil
 	}
 	if _, err := services.Resource.EnableAuditLogs(ctx, values.ProjectID); err != nil {
 		return err
 	}
-	services.Logger.Info("audit logs was enabled on %q", values.ProjectID)
+	logging.FromContext(ctx).Info("audit logs was enabled on %q", values.ProjectID)
+	return nil
+}
+
+func enableServiceAuditLogs(ctx context.Context, values *Values, services *Services) error {
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have enabled data access audit logs for %q in project %q", values.Services, values.ProjectID)
+		return nil
+	}
+	changed, err := services.Resource.EnableServiceAuditLogs(ctx, values.ProjectID, values.Services)
+	if err != nil {
+		return err
+	}
+	if len(changed) == 0 {
+		logging.FromContext(ctx).Info("data access audit logs already enabled for %q in project %q", values.Services, values.ProjectID)
+		return nil
+	}
+	logging.FromContext(ctx).Info("data access audit logs enabled for %q in project %q", changed, values.ProjectID)
 	return nil
 }
@@ -50,7 +50,6 @@ func TestExecuteEnableDataAccessAuditLogs(t *testing.T) {
 			entity := setupAuditLogs(policy)
 			if err := Execute(ctx, required, &Services{
 				Resource: entity.Resource,
-				Logger:   entity.Logger,
 			}); err != nil {
 				t.Errorf("%s failed to enable audi logs :%q", tt.name, err)
 			}
@@ -62,15 +61,35 @@ func TestExecuteEnableDataAccessAuditLogs(t *testing.T) {
 	}
 }
 
+func TestExecuteEnableServiceAuditLogs(t *testing.T) {
+	ctx := context.Background()
+	policy := &crm.Policy{AuditConfigs: []*crm.AuditConfig{
+		{AuditLogConfigs: []*crm.AuditLogConfig{{LogType: "ADMIN_READ"}}, Service: "allServices"},
+		{AuditLogConfigs: []*crm.AuditLogConfig{{LogType: "DATA_READ"}}, Service: "bigquery.googleapis.com"},
+	}}
+	entity := setupAuditLogs(policy)
+	values := &Values{ProjectID: "fake-project", Services: []string{"bigquery.googleapis.com", "storage.googleapis.com"}}
+	if err := Execute(ctx, values, &Services{
+		Resource: entity.Resource,
+	}); err != nil {
+		t.Fatalf("failed to enable service audit logs: %q", err)
+	}
+	expected := []*crm.AuditConfig{
+		{AuditLogConfigs: []*crm.AuditLogConfig{{LogType: "ADMIN_READ"}}, Service: "allServices"},
+		{AuditLogConfigs: []*crm.AuditLogConfig{{LogType: "DATA_READ"}, {LogType: "DATA_WRITE"}}, Service: "bigquery.googleapis.com"},
+		{AuditLogConfigs: []*crm.AuditLogConfig{{LogType: "DATA_READ"}, {LogType: "DATA_WRITE"}}, Service: "storage.googleapis.com"},
+	}
+	if diff := cmp.Diff(expected, policy.AuditConfigs); diff != "" {
+		t.Errorf("failed to merge audit configs, difference: %+v", diff)
+	}
+}
+
 func setupAuditLogs(mock *crm.Policy) *services.Global {
-	loggerStub := &stubs.LoggerStub{}
-	log := services.NewLogger(loggerStub)
 	return &services.Global{
 		Resource: services.NewResource(
 			&stubs.ResourceManagerStub{
 				GetPolicyResponse: mock,
 			},
 			&stubs.StorageStub{}),
-		Logger: log,
 	}
 }
@@ -0,0 +1,60 @@
+package removedefaulteditor
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// Values contains the required values needed for this function.
+type Values struct {
+	ProjectID string
+	Roles     []string
+	DryRun    bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Resource *services.Resource
+}
+
+// Execute is the entry point for the remove default service account Editor role Cloud Function.
+//
+// The Compute Engine and App Engine default service accounts are removed from the Editor role
+// of the project and granted the configured roles instead. If no roles are configured the
+// default service accounts are only removed from the Editor role.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	if values.DryRun {
+		change, err := services.Resource.PlanReplaceDefaultServiceAccountEditor(ctx, values.ProjectID, values.Roles)
+		if err != nil {
+			return err
+		}
+		logging.FromContext(ctx).Planned(change)
+		return nil
+	}
+	replaced, err := services.Resource.ReplaceDefaultServiceAccountEditor(ctx, values.ProjectID, values.Roles)
+	if err != nil {
+		return err
+	}
+	if len(replaced) == 0 {
+		logging.FromContext(ctx).Info("no default service accounts with the Editor role in %q", values.ProjectID)
+		return nil
+	}
+	logging.FromContext(ctx).Info("successfully replaced the Editor role of %q in %q with %q", replaced, values.ProjectID, values.Roles)
+	return nil
+}
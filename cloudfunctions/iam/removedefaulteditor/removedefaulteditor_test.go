@@ -0,0 +1,126 @@
+package removedefaulteditor
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	crm "google.golang.org/api/cloudresourcemanager/v1"
+)
+
+func TestRemoveDefaultEditor(t *testing.T) {
+	ctx := context.Background()
+	const (
+		compute = "serviceAccount:123456789-compute@developer.gserviceaccount.com"
+		owner   = "user:test@test.com"
+	)
+	for _, tt := range []struct {
+		name             string
+		roles            []string
+		dryRun           bool
+		initialBindings  []*crm.Binding
+		expectedBindings []*crm.Binding
+	}{
+		{
+			name:  "replace editor with configured roles",
+			roles: []string{"roles/logging.logWriter"},
+			initialBindings: []*crm.Binding{
+				{Role: "roles/editor", Members: []string{owner, compute}},
+			},
+			expectedBindings: []*crm.Binding{
+				{Role: "roles/editor", Members: []string{owner}},
+				{Role: "roles/logging.logWriter", Members: []string{compute}},
+			},
+		},
+		{
+			name: "remove editor without replacement roles",
+			initialBindings: []*crm.Binding{
+				{Role: "roles/editor", Members: []string{compute}},
+			},
+			expectedBindings: []*crm.Binding{
+				{Role: "roles/editor", Members: []string{}},
+			},
+		},
+		{
+			name:   "dry run",
+			roles:  []string{"roles/logging.logWriter"},
+			dryRun: true,
+			initialBindings: []*crm.Binding{
+				{Role: "roles/editor", Members: []string{compute}},
+			},
+			expectedBindings: nil,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			svcs, crmStub := removeDefaultEditorSetup()
+			crmStub.GetPolicyResponse = &crm.Policy{Bindings: tt.initialBindings}
+			values := &Values{
+				ProjectID: "test-project-id",
+				Roles:     tt.roles,
+				DryRun:    tt.dryRun,
+			}
+			if err := Execute(ctx, values, &Services{
+				Resource: svcs.Resource,
+			}); err != nil {
+				t.Errorf("%s failed: %q", tt.name, err)
+			}
+			var got []*crm.Binding
+			if crmStub.SavedSetPolicy != nil {
+				got = crmStub.SavedSetPolicy.Bindings
+			}
+			if diff := cmp.Diff(got, tt.expectedBindings); diff != "" {
+				t.Errorf("%v failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestRemoveDefaultEditorPlan(t *testing.T) {
+	loggerStub := &stubs.LoggerStub{}
+	crmStub := &stubs.ResourceManagerStub{GetPolicyResponse: &crm.Policy{Bindings: []*crm.Binding{
+		{Role: "roles/editor", Members: []string{"user:test@test.com", "serviceAccount:123456789-compute@developer.gserviceaccount.com"}},
+	}}}
+	values := &Values{ProjectID: "test-project-id", Roles: []string{"roles/logging.logWriter"}, DryRun: true}
+	ctx := logging.NewContext(context.Background(), loggerStub)
+	if err := Execute(ctx, values, &Services{
+		Resource: services.NewResource(crmStub, &stubs.StorageStub{}),
+	}); err != nil {
+		t.Fatalf("failed: %q", err)
+	}
+	want := "dry_run on, would have changed projects/test-project-id: " +
+		"roles/editor -serviceAccount:123456789-compute@developer.gserviceaccount.com, " +
+		"roles/logging.logWriter +serviceAccount:123456789-compute@developer.gserviceaccount.com"
+	if loggerStub.LastInfo != want {
+		t.Errorf("got %q want %q", loggerStub.LastInfo, want)
+	}
+	if crmStub.SavedSetPolicy != nil {
+		t.Errorf("policy set in dry run: %+v", crmStub.SavedSetPolicy)
+	}
+	if got := len(crmStub.GetPolicyResponse.Bindings[0].Members); got != 2 {
+		t.Errorf("policy changed in dry run, got %d editors want 2", got)
+	}
+}
+
+func removeDefaultEditorSetup() (*services.Global, *stubs.ResourceManagerStub) {
+	crmStub := &stubs.ResourceManagerStub{}
+	storageStub := &stubs.StorageStub{}
+	res := services.NewResource(crmStub, storageStub)
+	return &services.Global{Resource: res}, crmStub
+}
@@ -0,0 +1,6 @@
+variable "setup" {}
+
+variable "folder-ids" {
+  type        = list(string)
+  description = "Replace the Editor role of default service accounts if they are within the given folder IDs."
+}
@@ -0,0 +1,81 @@
+package removeimpersonation
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"strings"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// impersonationRoles are the roles allowing a principal to act as or mint tokens for a service account.
+var impersonationRoles = []string{"roles/iam.serviceAccountTokenCreator", "roles/iam.serviceAccountUser"}
+
+// Values contains the required values needed for this function.
+type Values struct {
+	ProjectID string
+	// Principal is the email of the principal that impersonated the service accounts.
+	Principal string
+	// ServiceAccounts are the emails of the service accounts impersonated by the principal.
+	ServiceAccounts []string
+	DryRun          bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Resource *services.Resource
+	IAM      *services.IAM
+}
+
+// Execute removes the token creator and service account user bindings granted to the principal on
+// the project and on each of the impersonated service accounts.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	if values.Principal == "" {
+		logging.FromContext(ctx).Info("no impersonating principal found for project %q", values.ProjectID)
+		return nil
+	}
+	m := member(values.Principal)
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have removed %q from roles %q in project %q and service accounts %q", m, impersonationRoles, values.ProjectID, values.ServiceAccounts)
+		return nil
+	}
+	removed, err := services.Resource.RemoveMemberRolesProject(ctx, values.ProjectID, m, impersonationRoles)
+	if err != nil {
+		return err
+	}
+	if len(removed) > 0 {
+		logging.FromContext(ctx).Info("removed %q from roles %q in project %q", m, removed, values.ProjectID)
+	}
+	for _, sa := range values.ServiceAccounts {
+		removed, err := services.IAM.RemoveServiceAccountMember(ctx, sa, m, impersonationRoles)
+		if err != nil {
+			return err
+		}
+		if len(removed) > 0 {
+			logging.FromContext(ctx).Info("removed %q from roles %q on service account %q", m, removed, sa)
+		}
+	}
+	return nil
+}
+
+// member returns the IAM member of the principal email.
+func member(principal string) string {
+	if strings.HasSuffix(principal, ".gserviceaccount.com") {
+		return "serviceAccount:" + principal
+	}
+	return "user:" + principal
+}
@@ -0,0 +1,106 @@
+package removeimpersonation
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	crm "google.golang.org/api/cloudresourcemanager/v1"
+	iam "google.golang.org/api/iam/v1"
+)
+
+func TestRemoveImpersonation(t *testing.T) {
+	const (
+		saName    = "projects/-/serviceAccounts/admin@test-project.iam.gserviceaccount.com"
+		attacker  = "user:attacker@external.com"
+		developer = "user:developer@example.com"
+	)
+	ctx := context.Background()
+	test := []struct {
+		name                  string
+		principal             string
+		dryRun                bool
+		projectPolicy         *crm.Policy
+		serviceAccountPolicy  *iam.Policy
+		expectedProjectPolicy *crm.Policy
+		expectedSAPolicies    map[string]*iam.Policy
+		expectedError         bool
+	}{
+		{
+			name:      "remove project and service account bindings",
+			principal: "attacker@external.com",
+			projectPolicy: &crm.Policy{Bindings: []*crm.Binding{
+				{Role: "roles/iam.serviceAccountTokenCreator", Members: []string{attacker, developer}},
+				{Role: "roles/viewer", Members: []string{attacker}},
+			}},
+			serviceAccountPolicy: &iam.Policy{Bindings: []*iam.Binding{
+				{Role: "roles/iam.serviceAccountUser", Members: []string{attacker}},
+			}},
+			expectedProjectPolicy: &crm.Policy{Bindings: []*crm.Binding{
+				{Role: "roles/iam.serviceAccountTokenCreator", Members: []string{developer}},
+				{Role: "roles/viewer", Members: []string{attacker}},
+			}},
+			expectedSAPolicies: map[string]*iam.Policy{
+				saName: {Bindings: []*iam.Binding{{Role: "roles/iam.serviceAccountUser", Members: []string{}}}},
+			},
+		},
+		{
+			name:      "no bindings to remove",
+			principal: "attacker@external.com",
+			projectPolicy: &crm.Policy{Bindings: []*crm.Binding{
+				{Role: "roles/iam.serviceAccountTokenCreator", Members: []string{developer}},
+			}},
+			serviceAccountPolicy: &iam.Policy{},
+		},
+		{
+			name:      "dry run",
+			principal: "attacker@external.com",
+			dryRun:    true,
+		},
+		{
+			name: "no principal",
+		},
+	}
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			crmStub := &stubs.ResourceManagerStub{GetPolicyResponse: tt.projectPolicy}
+			iamStub := &stubs.IAMStub{StubbedPolicies: map[string]*iam.Policy{saName: tt.serviceAccountPolicy}}
+			svcs := &Services{
+				Resource: services.NewResource(crmStub, &stubs.StorageStub{}),
+				IAM:      services.NewIAM(iamStub),
+			}
+			values := &Values{
+				ProjectID:       "test-project",
+				Principal:       tt.principal,
+				ServiceAccounts: []string{"admin@test-project.iam.gserviceaccount.com"},
+				DryRun:          tt.dryRun,
+			}
+			err := Execute(ctx, values, svcs)
+			if (err != nil) != tt.expectedError {
+				t.Errorf("%s test failed, unexpected error result: %v", tt.name, err)
+			}
+			if diff := cmp.Diff(crmStub.SavedSetPolicy, tt.expectedProjectPolicy); diff != "" {
+				t.Errorf("%v failed, difference in project policy: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(iamStub.SavedServiceAccountPolicies, tt.expectedSAPolicies); diff != "" {
+				t.Errorf("%v failed, difference in service account policies: %+v", tt.name, diff)
+			}
+		})
+	}
+}
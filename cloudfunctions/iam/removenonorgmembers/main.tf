@@ -30,7 +30,7 @@ resource "google_cloudfunctions_function" "remove-non-org-members" {
   }
 }
 
-#  Required to get and set organization policies.
+#  Required to get folders and set folder and organization policies.
 resource "google_folder_iam_member" "roles-org-policy" {
   count = length(var.folder-ids)
 
@@ -44,3 +44,44 @@ resource "google_pubsub_topic" "topic" {
   name    = "threat-findings-remove-non-org-members"
   project = var.setup.automation-project
 }
+
+# Required to apply the iam.allowedPolicyMemberDomains constraint on escalation.
+resource "google_folder_iam_member" "roles-org-policy-admin" {
+  count = length(var.folder-ids)
+
+  folder = "folders/${var.folder-ids[count.index]}"
+  role   = "roles/orgpolicy.policyAdmin"
+  member = "serviceAccount:${var.setup.automation-service-account}"
+}
+
+# Bucket where findings are counted for escalation. Counts older than the lifecycle age are removed.
+resource "google_storage_bucket" "counter_bucket" {
+  name               = "${var.setup.automation-project}-finding-counters"
+  project            = var.setup.automation-project
+  location           = "US"
+  bucket_policy_only = true
+
+  lifecycle_rule {
+    condition {
+      age = 30
+    }
+    action {
+      type = "Delete"
+    }
+  }
+}
+
+# Required to count findings.
+resource "google_storage_bucket_iam_member" "counter_bucket_admin" {
+  bucket = google_storage_bucket.counter_bucket.name
+  role   = "roles/storage.objectAdmin"
+  member = "serviceAccount:${var.setup.automation-service-account}"
+}
+
+# Required to look up groups and their members.
+resource "google_project_service" "cloudidentity_api" {
+  project                    = var.setup.automation-project
+  service                    = "cloudidentity.googleapis.com"
+  disable_dependent_services = false
+  disable_on_destroy         = false
+}
@@ -16,33 +16,244 @@ package removenonorgmembers
 
 import (
 	"context"
+	"strings"
+	"time"
 
+	"github.com/googlecloudplatform/security-response-automation/logging"
 	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/pkg/errors"
 )
 
 // Values contains the required values needed for this function.
 type Values struct {
-	ProjectID    string
+	ProjectID string
+	// Resource is the folder or organization whose policy is changed, such as "folders/123".
+	// The project's policy is changed if empty.
+	Resource     string
 	AllowDomains []string
-	DryRun       bool
+	// UnconditionalOnly only removes members from bindings without an IAM condition.
+	UnconditionalOnly bool
+	DryRun            bool
+	Escalation        Escalation
+	// Groups configures how group members are handled.
+	Groups Groups
+	// ServiceAccounts configures how service account members are handled.
+	ServiceAccounts ServiceAccounts
+	// Directory configures looking up users in Cloud Identity.
+	Directory Directory
+}
+
+// Directory looks up users whose email is not from an allowed domain in Cloud Identity, which
+// finds users of organizations with several domains. Only domains are matched if unset or if the
+// lookup fails.
+type Directory struct {
+	// Groups are the groups, such as all-employees@example.com, whose members belong to the
+	// organization and are kept.
+	Groups []string
+}
+
+// Groups configures the removal of groups not from an allowed domain. Groups are kept if unset.
+type Groups struct {
+	// Remove removes groups whose email is not from an allowed domain.
+	Remove bool
+	// Lookup looks up the owner of the group in Cloud Identity before removing it. Groups owned
+	// by one of the customer IDs are kept regardless of their domain.
+	Lookup      bool
+	CustomerIDs []string
+}
+
+// ServiceAccounts configures the removal of service accounts from foreign projects. Service
+// accounts are kept if unset.
+type ServiceAccounts struct {
+	// Remove removes user-managed service accounts not created in the project itself or in one of
+	// the allowed projects. Google-managed service accounts are always kept.
+	Remove        bool
+	AllowProjects []string
+}
+
+// Escalation restricts the domains of a project's members once it has too many findings.
+type Escalation struct {
+	// Threshold is the number of findings within the window after which the project is restricted.
+	// Escalation is off if zero.
+	Threshold int
+	Window    time.Duration
+	// Bucket is where findings are counted.
+	Bucket string
+	// CustomerIDs are the Google Workspace customer IDs whose members remain allowed.
+	CustomerIDs []string
 }
 
 // Services contains the services needed for this function.
 type Services struct {
-	Logger   *services.Logger
-	Resource *services.Resource
+	Resource      *services.Resource
+	Counter       *services.Counter
+	CloudIdentity *services.CloudIdentity
 }
 
-// Execute removes all users from a specific project not in allowed domain list.
+// Execute removes all users and domains from a specific project, folder or organization not in
+// allowed domain list.
+//
+// If escalation is configured and the project has more findings than the threshold within the
+// window, the iam.allowedPolicyMemberDomains constraint is also applied to the project. Folders and
+// organizations are never escalated.
 func Execute(ctx context.Context, values *Values, services *Services) error {
+	target := values.Resource
+	if target == "" {
+		target = "projects/" + values.ProjectID
+	}
 	if values.DryRun {
-		services.Logger.Info("dry run, would have removed users not from %q in %q", values.AllowDomains, values.ProjectID)
+		change, err := services.Resource.PlanKeepUsersFromDomains(ctx, target, values.AllowDomains, keepOptions(values, services))
+		if err != nil {
+			return err
+		}
+		logging.FromContext(ctx).Planned(change)
+	} else {
+		removed, err := keepMembers(ctx, values, services)
+		if err != nil {
+			return err
+		}
+		logging.FromContext(ctx).Info("successfully removed %q from %s", removed, target)
+	}
+	if values.Escalation.Threshold == 0 || values.Resource != "" {
 		return nil
 	}
-	removed, err := services.Resource.ProjectOnlyKeepUsersFromDomains(ctx, values.ProjectID, values.AllowDomains)
+	return escalate(ctx, values, services)
+}
+
+// keepOptions returns the options of the members kept in addition to those from the allowed domains.
+func keepOptions(values *Values, svcs *Services) services.KeepOptions {
+	opts := services.KeepOptions{
+		UnconditionalOnly: values.UnconditionalOnly,
+		Filters:           filters(values, svcs),
+	}
+	if len(values.Directory.Groups) > 0 {
+		opts.InOrganization = func(ctx context.Context, email string) (bool, error) {
+			return inOrganization(ctx, email, values, svcs), nil
+		}
+	}
+	return opts
+}
+
+// keepMembers removes the members not allowed from the policy of the project, folder or organization.
+func keepMembers(ctx context.Context, values *Values, svcs *Services) ([]string, error) {
+	opts := keepOptions(values, svcs)
+	switch {
+	case values.Resource == "":
+		return svcs.Resource.ProjectOnlyKeepUsersFromDomains(ctx, values.ProjectID, values.AllowDomains, opts)
+	case strings.HasPrefix(values.Resource, "folders/"):
+		return svcs.Resource.FolderOnlyKeepUsersFromDomains(ctx, strings.TrimPrefix(values.Resource, "folders/"), values.AllowDomains, opts)
+	case strings.HasPrefix(values.Resource, "organizations/"):
+		return svcs.Resource.OrganizationOnlyKeepUsersFromDomains(ctx, values.Resource, values.AllowDomains, opts)
+	default:
+		return nil, errors.Errorf("unsupported resource %q", values.Resource)
+	}
+}
+
+// inOrganization returns whether the user is a member of one of the directory groups. Users are
+// only matched by domain, and so removed, if Cloud Identity can't be reached.
+func inOrganization(ctx context.Context, email string, values *Values, svcs *Services) bool {
+	for _, group := range values.Directory.Groups {
+		ok, err := svcs.CloudIdentity.IsMember(ctx, group, email)
+		if err != nil {
+			logging.FromContext(ctx).Warning("failed to look up %q in %q, falling back to domain matching: %q", email, group, err)
+			return false
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// filters returns the filters removing the group and service account members configured.
+func filters(values *Values, svcs *Services) []services.MemberFilter {
+	var f []services.MemberFilter
+	if values.Groups.Remove {
+		f = append(f, func(ctx context.Context, member string) (bool, error) {
+			return removeGroup(ctx, member, values, svcs)
+		})
+	}
+	if values.ServiceAccounts.Remove {
+		f = append(f, func(ctx context.Context, member string) (bool, error) {
+			return removeServiceAccount(member, values), nil
+		})
+	}
+	return f
+}
+
+// removeGroup returns whether the member is a group that is neither from an allowed domain nor,
+// if looked up, owned by an allowed customer.
+func removeGroup(ctx context.Context, member string, values *Values, svcs *Services) (bool, error) {
+	if !strings.HasPrefix(member, "group:") {
+		return false, nil
+	}
+	email := strings.TrimPrefix(member, "group:")
+	if contains(values.AllowDomains, domain(email)) {
+		return false, nil
+	}
+	if !values.Groups.Lookup {
+		return true, nil
+	}
+	customer, err := svcs.CloudIdentity.GroupCustomer(ctx, email)
 	if err != nil {
-		return err
+		return false, err
+	}
+	return customer == "" || !contains(values.Groups.CustomerIDs, customer), nil
+}
+
+// removeServiceAccount returns whether the member is a user-managed service account of a project
+// that is neither the project itself nor allowed.
+func removeServiceAccount(member string, values *Values) bool {
+	if !strings.HasPrefix(member, "serviceAccount:") {
+		return false
+	}
+	d := domain(strings.TrimPrefix(member, "serviceAccount:"))
+	if !strings.HasSuffix(d, ".iam.gserviceaccount.com") {
+		return false
+	}
+	project := strings.TrimSuffix(d, ".iam.gserviceaccount.com")
+	// Service agents are created by Google in projects such as "gcp-sa-pubsub".
+	if strings.HasPrefix(project, "gcp-sa-") {
+		return false
+	}
+	return project != values.ProjectID && !contains(values.ServiceAccounts.AllowProjects, project)
+}
+
+// domain returns the domain of the email.
+func domain(email string) string {
+	return email[strings.LastIndex(email, "@")+1:]
+}
+
+// contains returns whether the value is in the list, ignoring case.
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func escalate(ctx context.Context, values *Values, services *Services) error {
+	key := "non-org-members/" + values.ProjectID
+	now := time.Now()
+	if err := services.Counter.Increment(ctx, values.Escalation.Bucket, key, now); err != nil {
+		return errors.Wrap(err, "failed to count finding")
+	}
+	count, err := services.Counter.Count(ctx, values.Escalation.Bucket, key, now.Add(-values.Escalation.Window))
+	if err != nil {
+		return errors.Wrap(err, "failed to count findings")
+	}
+	if count <= values.Escalation.Threshold {
+		return nil
+	}
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry run, %s had %d findings in %s, would have restricted member domains to %q", values.ProjectID, count, values.Escalation.Window, values.Escalation.CustomerIDs)
+		return nil
+	}
+	if err := services.Resource.RestrictMemberDomains(ctx, values.ProjectID, values.Escalation.CustomerIDs); err != nil {
+		return errors.Wrap(err, "failed to restrict member domains")
 	}
-	services.Logger.Info("successfully removed %q from %s", removed, values.ProjectID)
+	logging.FromContext(ctx).Info("%s had %d findings in %s, restricted member domains to %q", values.ProjectID, count, values.Escalation.Window, values.Escalation.CustomerIDs)
 	return nil
 }
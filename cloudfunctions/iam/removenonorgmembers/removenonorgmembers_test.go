@@ -16,11 +16,14 @@ package removenonorgmembers
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
 	"github.com/googlecloudplatform/security-response-automation/services"
+	ci "google.golang.org/api/cloudidentity/v1"
 	crm "google.golang.org/api/cloudresourcemanager/v1"
 )
 
@@ -51,7 +54,6 @@ func TestErrors(t *testing.T) {
 			values := &Values{ProjectID: "project-id"}
 			err := Execute(context.Background(), values, &Services{
 				Resource: entity.Resource,
-				Logger:   entity.Logger,
 			})
 			if tt.expectedFail && err == nil {
 				t.Errorf("%s failed: %q", tt.name, err)
@@ -69,7 +71,7 @@ func TestRemoveNonOrgMembers(t *testing.T) {
 		allowDomains    []string
 	}{
 		{
-			name: "only remove users not in the allowed domain",
+			name: "only remove users and domains not in the allowed domain",
 			policyInput: createBindings([]string{
 				"user:anyone@google.com",
 				"user:bob@gmail.com",
@@ -83,8 +85,7 @@ func TestRemoveNonOrgMembers(t *testing.T) {
 				"user:ddgo@cloudorg.com",
 				"user:mans@cloudorg.com",
 				"serviceAccount:473000000749@cloudbuild.gserviceaccount.com",
-				"group:admins@example.com",
-				"domain:google.com"}),
+				"group:admins@example.com"}),
 			allowDomains: []string{
 				"cloudorg.com",
 			},
@@ -100,6 +101,8 @@ func TestRemoveNonOrgMembers(t *testing.T) {
 				"user:anyone@google.com",
 				"group:admins@example.com",
 				"domain:aol.com",
+				"domain:google.com",
+				"domain:evilgoogle.com",
 				"user:guy@evilgoogle.com",
 				"user:guy@google.evil.com",
 				"user:mls@cloudorgevil.com",
@@ -112,7 +115,7 @@ func TestRemoveNonOrgMembers(t *testing.T) {
 				"serviceAccount:473000000749@cloudbuild.gserviceaccount.com",
 				"user:anyone@google.com",
 				"group:admins@example.com",
-				"domain:aol.com",
+				"domain:google.com",
 				"user:buddy@prod.google.com",
 			}),
 			allowDomains: []string{
@@ -129,7 +132,6 @@ func TestRemoveNonOrgMembers(t *testing.T) {
 			values := &Values{ProjectID: "project-id", AllowDomains: tt.allowDomains}
 			err := Execute(context.Background(), values, &Services{
 				Resource: entity.Resource,
-				Logger:   entity.Logger,
 			})
 			if err != nil {
 				t.Fatalf("%s failed: %q", tt.name, err)
@@ -142,14 +144,228 @@ func TestRemoveNonOrgMembers(t *testing.T) {
 	}
 }
 
+func TestRemoveGroupsAndServiceAccounts(t *testing.T) {
+	members := []string{
+		"user:ddgo@cloudorg.com",
+		"group:admins@cloudorg.com",
+		"group:admins@partner.com",
+		"group:devs@subsidiary.com",
+		"serviceAccount:app@project-id.iam.gserviceaccount.com",
+		"serviceAccount:app@shared-project.iam.gserviceaccount.com",
+		"serviceAccount:app@foreign-project.iam.gserviceaccount.com",
+		"serviceAccount:service-473000000749@gcp-sa-pubsub.iam.gserviceaccount.com",
+		"serviceAccount:473000000749@cloudbuild.gserviceaccount.com",
+	}
+	tests := []struct {
+		name            string
+		groups          Groups
+		serviceAccounts ServiceAccounts
+		expected        []string
+	}{
+		{
+			name:     "kept by default",
+			expected: members,
+		},
+		{
+			name:   "remove groups",
+			groups: Groups{Remove: true},
+			expected: []string{
+				"user:ddgo@cloudorg.com",
+				"group:admins@cloudorg.com",
+				"serviceAccount:app@project-id.iam.gserviceaccount.com",
+				"serviceAccount:app@shared-project.iam.gserviceaccount.com",
+				"serviceAccount:app@foreign-project.iam.gserviceaccount.com",
+				"serviceAccount:service-473000000749@gcp-sa-pubsub.iam.gserviceaccount.com",
+				"serviceAccount:473000000749@cloudbuild.gserviceaccount.com",
+			},
+		},
+		{
+			name:   "keep groups owned by an allowed customer",
+			groups: Groups{Remove: true, Lookup: true, CustomerIDs: []string{"C0123abcd"}},
+			expected: []string{
+				"user:ddgo@cloudorg.com",
+				"group:admins@cloudorg.com",
+				"group:devs@subsidiary.com",
+				"serviceAccount:app@project-id.iam.gserviceaccount.com",
+				"serviceAccount:app@shared-project.iam.gserviceaccount.com",
+				"serviceAccount:app@foreign-project.iam.gserviceaccount.com",
+				"serviceAccount:service-473000000749@gcp-sa-pubsub.iam.gserviceaccount.com",
+				"serviceAccount:473000000749@cloudbuild.gserviceaccount.com",
+			},
+		},
+		{
+			name:            "remove service accounts from foreign projects",
+			serviceAccounts: ServiceAccounts{Remove: true, AllowProjects: []string{"shared-project"}},
+			expected: []string{
+				"user:ddgo@cloudorg.com",
+				"group:admins@cloudorg.com",
+				"group:admins@partner.com",
+				"group:devs@subsidiary.com",
+				"serviceAccount:app@project-id.iam.gserviceaccount.com",
+				"serviceAccount:app@shared-project.iam.gserviceaccount.com",
+				"serviceAccount:service-473000000749@gcp-sa-pubsub.iam.gserviceaccount.com",
+				"serviceAccount:473000000749@cloudbuild.gserviceaccount.com",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := make([]string, len(members))
+			copy(input, members)
+			entity, crmStub := setupNonOrgTest(&crm.Policy{Bindings: createBindings(input)})
+			ciStub := &stubs.CloudIdentityStub{StubbedGroups: map[string]*ci.Group{
+				"admins@partner.com":  {Name: "groups/partner", Parent: "customers/C0999zzzz"},
+				"devs@subsidiary.com": {Name: "groups/subsidiary", Parent: "customers/C0123abcd"},
+			}}
+			values := &Values{
+				ProjectID:       "project-id",
+				AllowDomains:    []string{"cloudorg.com"},
+				Groups:          tt.groups,
+				ServiceAccounts: tt.serviceAccounts,
+			}
+			if err := Execute(context.Background(), values, &Services{
+				Resource:      entity.Resource,
+				CloudIdentity: services.NewCloudIdentity(ciStub),
+			}); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(createBindings(tt.expected), crmStub.SavedSetPolicy.Bindings); diff != "" {
+				t.Errorf("%v failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestRemoveNonOrgMembersResource(t *testing.T) {
+	for _, resource := range []string{"folders/123", "organizations/456"} {
+		t.Run(resource, func(t *testing.T) {
+			policy := &crm.Policy{Bindings: createBindings([]string{"user:ddgo@cloudorg.com", "user:bob@gmail.com"})}
+			entity, crmStub := setupNonOrgTest(policy)
+			values := &Values{
+				Resource:     resource,
+				AllowDomains: []string{"cloudorg.com"},
+				// Escalation only applies to projects.
+				Escalation: Escalation{Threshold: 1, Window: time.Hour, Bucket: "counters"},
+			}
+			if err := Execute(context.Background(), values, &Services{
+				Resource: entity.Resource,
+			}); err != nil {
+				t.Fatalf("%s failed: %q", resource, err)
+			}
+			if diff := cmp.Diff(createBindings([]string{"user:ddgo@cloudorg.com"}), crmStub.SavedSetPolicy.Bindings); diff != "" {
+				t.Errorf("%v failed, difference: %+v", resource, diff)
+			}
+			if crmStub.SavedOrgPolicy != nil {
+				t.Errorf("%v failed, escalated: %+v", resource, crmStub.SavedOrgPolicy)
+			}
+		})
+	}
+}
+
+func TestDirectory(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected []string
+	}{
+		{
+			name:     "keep users in the directory",
+			expected: []string{"user:ddgo@cloudorg.com", "user:mans@cloudorg.co.uk"},
+		},
+		{
+			name:     "fall back to domain matching",
+			err:      errors.New("service unavailable"),
+			expected: []string{"user:ddgo@cloudorg.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &crm.Policy{Bindings: createBindings([]string{"user:ddgo@cloudorg.com", "user:mans@cloudorg.co.uk", "user:bob@gmail.com"})}
+			entity, crmStub := setupNonOrgTest(policy)
+			ciStub := &stubs.CloudIdentityStub{
+				StubbedGroups:  map[string]*ci.Group{"all@cloudorg.com": {Name: "groups/all"}},
+				StubbedMembers: map[string][]string{"groups/all": {"ddgo@cloudorg.com", "mans@cloudorg.co.uk"}},
+				StubbedErr:     tt.err,
+			}
+			values := &Values{
+				ProjectID:    "project-id",
+				AllowDomains: []string{"cloudorg.com"},
+				Directory:    Directory{Groups: []string{"all@cloudorg.com"}},
+			}
+			if err := Execute(context.Background(), values, &Services{
+				Resource:      entity.Resource,
+				CloudIdentity: services.NewCloudIdentity(ciStub),
+			}); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(createBindings(tt.expected), crmStub.SavedSetPolicy.Bindings); diff != "" {
+				t.Errorf("%v failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestEscalation(t *testing.T) {
+	tests := []struct {
+		name     string
+		findings int
+		dryRun   bool
+		expected *crm.OrgPolicy
+	}{
+		{
+			name:     "below threshold",
+			findings: 2,
+		},
+		{
+			name:     "above threshold",
+			findings: 3,
+			expected: &crm.OrgPolicy{
+				Constraint: "constraints/iam.allowedPolicyMemberDomains",
+				ListPolicy: &crm.ListPolicy{AllowedValues: []string{"C0123abcd"}},
+			},
+		},
+		{
+			name:     "above threshold dry run",
+			findings: 3,
+			dryRun:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &crm.Policy{Bindings: createBindings([]string{"user:bob@gmail.com"})}
+			entity, crmStub := setupNonOrgTest(policy)
+			counter := services.NewCounter(&stubs.StorageStub{})
+			values := &Values{
+				ProjectID:    "project-id",
+				AllowDomains: []string{"cloudorg.com"},
+				DryRun:       tt.dryRun,
+				Escalation: Escalation{
+					Threshold:   2,
+					Window:      time.Hour,
+					Bucket:      "counters",
+					CustomerIDs: []string{"C0123abcd"},
+				},
+			}
+			for i := 0; i < tt.findings; i++ {
+				if err := Execute(context.Background(), values, &Services{
+					Resource: entity.Resource,
+					Counter:  counter,
+				}); err != nil {
+					t.Fatalf("%s failed: %q", tt.name, err)
+				}
+			}
+			if diff := cmp.Diff(tt.expected, crmStub.SavedOrgPolicy); diff != "" {
+				t.Errorf("%v failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
+
 func setupNonOrgTest(policy *crm.Policy) (*services.Global, *stubs.ResourceManagerStub) {
 	crmStub := &stubs.ResourceManagerStub{}
 	crmStub.GetPolicyResponse = policy
-	loggerStub := &stubs.LoggerStub{}
-	log := services.NewLogger(loggerStub)
 	return &services.Global{
 		Resource: services.NewResource(crmStub, &stubs.StorageStub{}),
-		Logger:   log,
 	}, crmStub
 }
 
@@ -0,0 +1,72 @@
+# Package automation contains the Cloud Function code to automate actions.
+
+# Copyright 2019 Google LLC
+
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+
+# 	https://www.apache.org/licenses/LICENSE-2.0
+
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+resource "google_cloudfunctions_function" "restore_policy_function" {
+  name                  = "RestoreIAMPolicy"
+  description           = "Restores the last known-good IAM policy of projects with Event Threat Detection anomalous IAM grants."
+  runtime               = "go116"
+  available_memory_mb   = 128
+  source_archive_bucket = var.setup.gcf-bucket-name
+  source_archive_object = var.setup.gcf-object-name
+  timeout               = 60
+  project               = var.setup.automation-project
+  region                = var.setup.region
+  entry_point           = "RestoreIAMPolicy"
+  service_account_email = var.setup.automation-service-account
+
+  event_trigger {
+    event_type = "google.pubsub.topic.publish"
+    resource   = "threat-findings-restore-iam-policy"
+  }
+  environment_variables = {
+    GCP_PROJECT    = var.setup.automation-project
+    SRA_CONFIG_URI = var.setup.config-uri
+  }
+  timeouts {
+    create = "10m"
+    update = "10m"
+  }
+}
+
+# Required by RestoreIAMPolicy to set IAM policies of projects within this folder.
+resource "google_folder_iam_member" "restore_policy_cloudfunction-folder-bind" {
+  count = length(var.folder-ids)
+
+  folder = "folders/${var.folder-ids[count.index]}"
+  role   = "roles/resourcemanager.folderAdmin"
+  member = "serviceAccount:${var.setup.automation-service-account}"
+}
+
+# Required to retrieve ancestry for projects within this folder.
+resource "google_folder_iam_member" "restore_policy_viewer_cloudfunction-folder-bind" {
+  count = length(var.folder-ids)
+
+  folder = "folders/${var.folder-ids[count.index]}"
+  role   = "roles/viewer"
+  member = "serviceAccount:${var.setup.automation-service-account}"
+}
+
+# PubSub topic to trigger this automation.
+resource "google_pubsub_topic" "topic" {
+  name    = "threat-findings-restore-iam-policy"
+  project = var.setup.automation-project
+}
+
+# Required to read IAM policy snapshots from the backup bucket.
+resource "google_storage_bucket_iam_member" "restore_policy_bucket_viewer" {
+  bucket = var.bucket
+  role   = "roles/storage.objectViewer"
+  member = "serviceAccount:${var.setup.automation-service-account}"
+}
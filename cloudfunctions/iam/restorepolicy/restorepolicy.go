@@ -0,0 +1,55 @@
+package restorepolicy
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/pkg/errors"
+)
+
+// Values contains the required values needed for this function.
+type Values struct {
+	ProjectID string
+	// EventTime is when the anomalous grant happened, the latest snapshot taken before it is restored.
+	EventTime time.Time
+	Bucket    string
+	DryRun    bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	PolicyBackup *services.PolicyBackup
+}
+
+// Execute restores the project's IAM policy from the last snapshot taken before the finding's event time.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	resource := "projects/" + values.ProjectID
+	snapshot, err := services.PolicyBackup.Latest(ctx, values.Bucket, resource, values.EventTime)
+	if err != nil {
+		return errors.Wrap(err, "failed to find policy snapshot")
+	}
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have restored policy of %q from %q taken at %s.", resource, snapshot.Object, snapshot.Time.Format(time.RFC3339))
+		return nil
+	}
+	if err := services.PolicyBackup.Restore(ctx, values.Bucket, snapshot); err != nil {
+		return errors.Wrap(err, "failed to restore policy")
+	}
+	logging.FromContext(ctx).Info("restored policy of %q from %q taken at %s.", resource, snapshot.Object, snapshot.Time.Format(time.RFC3339))
+	return nil
+}
@@ -0,0 +1,72 @@
+package restorepolicy
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	crm "google.golang.org/api/cloudresourcemanager/v1"
+)
+
+func TestRestorePolicy(t *testing.T) {
+	ctx := context.Background()
+	eventTime := time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		dryRun   bool
+		expected *crm.Policy
+	}{
+		{
+			name: "restore snapshot before event",
+			expected: &crm.Policy{
+				Bindings: []*crm.Binding{{Role: "roles/editor", Members: []string{"user:bob@gmail.com"}}},
+				Etag:     "current",
+			},
+		},
+		{
+			name:     "dry run",
+			dryRun:   true,
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crmStub := &stubs.ResourceManagerStub{
+				GetPolicyResponse: &crm.Policy{
+					Bindings: []*crm.Binding{{Role: "roles/editor", Members: []string{"user:bob@gmail.com", "user:attacker@gmail.com"}}},
+					Etag:     "current",
+				},
+			}
+			storageStub := &stubs.StorageStub{Objects: map[string][]byte{
+				"bucket/iam-policies/projects/test-project/20200101T000000Z.json": []byte(`{"bindings":[{"members":["user:alice@gmail.com"],"role":"roles/editor"}]}`),
+				"bucket/iam-policies/projects/test-project/20200102T000000Z.json": []byte(`{"bindings":[{"members":["user:bob@gmail.com"],"role":"roles/editor"}]}`),
+				"bucket/iam-policies/projects/test-project/20200103T000000Z.json": []byte(`{"bindings":[{"members":["user:bob@gmail.com","user:attacker@gmail.com"],"role":"roles/editor"}]}`),
+			}}
+			values := &Values{ProjectID: "test-project", EventTime: eventTime, Bucket: "bucket", DryRun: tt.dryRun}
+			if err := Execute(ctx, values, &Services{
+				PolicyBackup: services.NewPolicyBackup(crmStub, storageStub),
+			}); err != nil {
+				t.Fatalf("%s failed exp:%v got:%v", tt.name, nil, err)
+			}
+			if diff := cmp.Diff(tt.expected, crmStub.SavedSetPolicy); diff != "" {
+				t.Errorf("%v failed (-want +got):\n%s", tt.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+variable "setup" {}
+
+variable "folder-ids" {
+  type        = list(string)
+  description = "Restore IAM policies of projects within the given folder IDs."
+}
+
+variable "bucket" {
+  type        = string
+  description = "GCS bucket holding IAM policy snapshots."
+}
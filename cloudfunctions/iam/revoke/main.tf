@@ -31,7 +31,8 @@ resource "google_cloudfunctions_function" "revoke_member_function" {
     resource   = "threat-findings-iam-revoke"
   }
   environment_variables = {
-    GCP_PROJECT = var.setup.automation-project
+    GCP_PROJECT    = var.setup.automation-project
+    SRA_CONFIG_URI = var.setup.config-uri
   }
   timeouts {
     create = "10m"
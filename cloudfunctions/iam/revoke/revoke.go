@@ -21,6 +21,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/googlecloudplatform/security-response-automation/logging"
 	"github.com/googlecloudplatform/security-response-automation/services"
 )
 
@@ -35,7 +36,6 @@ type Values struct {
 // Services contains the services needed for this function.
 type Services struct {
 	Resource *services.Resource
-	Logger   *services.Logger
 }
 
 // Execute is the entry point for the IAM revoker Cloud Function.
@@ -44,20 +44,19 @@ type Services struct {
 // - The users are believed to be external as reported from the finding provider.
 // - The project where the external users were found are within the set configured resources.
 // - The users do not match the list of allowed domains.
-//
 func Execute(ctx context.Context, values *Values, services *Services) error {
 	members, err := toRemove(values.ExternalMembers, values.AllowDomains)
 	if err != nil {
 		return err
 	}
 	if values.DryRun {
-		services.Logger.Info("dry_run on, would have removed %q from %q", members, values.ProjectID)
+		logging.FromContext(ctx).Info("dry_run on, would have removed %q from %q", members, values.ProjectID)
 		return nil
 	}
 	if err := services.Resource.RemoveUsersProject(ctx, values.ProjectID, members); err != nil {
 		return err
 	}
-	services.Logger.Info("successfully removed %q from %s", members, values.ProjectID)
+	logging.FromContext(ctx).Info("successfully removed %q from %s", members, values.ProjectID)
 	return nil
 }
 
@@ -141,7 +141,6 @@ func TestIAMRevoke(t *testing.T) {
 			}
 			if err := Execute(ctx, values, &Services{
 				Resource: svcs.Resource,
-				Logger:   svcs.Logger,
 			}); err != nil {
 				if !xerrors.Is(errors.Cause(err), tt.expectedError) {
 					t.Errorf("%q failed\nwant:%qngot:%q", tt.name, tt.expectedError, errors.Cause(err))
@@ -168,10 +167,8 @@ func createPolicy(members []string) []*crm.Binding {
 }
 
 func revokeGrantsSetup(folderIDs, projectIDs, allowed []string) (*services.Global, *stubs.ResourceManagerStub) {
-	loggerStub := &stubs.LoggerStub{}
-	l := services.NewLogger(loggerStub)
 	crmStub := &stubs.ResourceManagerStub{}
 	storageStub := &stubs.StorageStub{}
 	r := services.NewResource(crmStub, storageStub)
-	return &services.Global{Logger: l, Resource: r}, crmStub
+	return &services.Global{Resource: r}, crmStub
 }
@@ -0,0 +1,86 @@
+// Package revokegrants provides the implementation of automated actions.
+package revokegrants
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"strings"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// Binding is a single member granted a role.
+type Binding struct {
+	Role   string
+	Member string
+}
+
+// Values contains the required values needed for this function.
+type Values struct {
+	ProjectID    string
+	Bindings     []Binding
+	AllowDomains []string
+	DryRun       bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Resource *services.Resource
+}
+
+// Execute is the entry point for the IAM grant revoker Cloud Function.
+//
+// Unlike the IAM revoker which removes external members from every role, this automation
+// only removes the exact member and role pairs added as reported by the finding. Members
+// matching the list of allowed domains are kept and the rest of the policy is untouched.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	remove := toRemove(values.Bindings, values.AllowDomains)
+	if len(remove) == 0 {
+		logging.FromContext(ctx).Info("no grants to revoke from %q", values.ProjectID)
+		return nil
+	}
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have revoked %q from %q", remove, values.ProjectID)
+		return nil
+	}
+	if err := services.Resource.RemoveRoleMembersProject(ctx, values.ProjectID, remove); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("successfully revoked %q from %s", remove, values.ProjectID)
+	return nil
+}
+
+// toRemove returns the disallowed members to be removed keyed by role.
+func toRemove(bindings []Binding, allowed []string) map[string][]string {
+	remove := make(map[string][]string)
+	for _, b := range bindings {
+		if b.Role == "" || b.Member == "" || allowedDomain(b.Member, allowed) {
+			continue
+		}
+		remove[b.Role] = append(remove[b.Role], b.Member)
+	}
+	return remove
+}
+
+func allowedDomain(member string, allowed []string) bool {
+	for _, domain := range allowed {
+		if strings.HasSuffix(strings.ToLower(member), "@"+strings.ToLower(domain)) {
+			return true
+		}
+	}
+	return false
+}
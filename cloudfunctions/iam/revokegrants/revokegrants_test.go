@@ -0,0 +1,103 @@
+package revokegrants
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	crm "google.golang.org/api/cloudresourcemanager/v1"
+)
+
+func TestIAMRevokeGrants(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		name             string
+		bindings         []Binding
+		allowed          []string
+		initialBindings  []*crm.Binding
+		expectedBindings []*crm.Binding
+	}{
+		{
+			name:     "remove only the granted role",
+			bindings: []Binding{{Role: "roles/owner", Member: "user:tom@gmail.com"}},
+			initialBindings: []*crm.Binding{
+				{Role: "roles/owner", Members: []string{"user:test@test.com", "user:tom@gmail.com"}},
+				{Role: "roles/viewer", Members: []string{"user:tom@gmail.com"}},
+			},
+			expectedBindings: []*crm.Binding{
+				{Role: "roles/owner", Members: []string{"user:test@test.com"}},
+				{Role: "roles/viewer", Members: []string{"user:tom@gmail.com"}},
+			},
+		},
+		{
+			name: "remove multiple grants",
+			bindings: []Binding{
+				{Role: "roles/owner", Member: "user:tom@gmail.com"},
+				{Role: "roles/editor", Member: "serviceAccount:sa@evil.iam.gserviceaccount.com"},
+			},
+			initialBindings: []*crm.Binding{
+				{Role: "roles/owner", Members: []string{"user:tom@gmail.com"}},
+				{Role: "roles/editor", Members: []string{"user:test@test.com", "serviceAccount:sa@evil.iam.gserviceaccount.com"}},
+			},
+			expectedBindings: []*crm.Binding{
+				{Role: "roles/owner", Members: []string{}},
+				{Role: "roles/editor", Members: []string{"user:test@test.com"}},
+			},
+		},
+		{
+			name:     "allowed domain is kept",
+			bindings: []Binding{{Role: "roles/owner", Member: "user:tom@test.com"}},
+			allowed:  []string{"test.com"},
+			initialBindings: []*crm.Binding{
+				{Role: "roles/owner", Members: []string{"user:tom@test.com"}},
+			},
+			expectedBindings: nil,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			svcs, crmStub := revokeGrantsSetup()
+			crmStub.GetPolicyResponse = &crm.Policy{Bindings: tt.initialBindings}
+			values := &Values{
+				ProjectID:    "test-project-id",
+				Bindings:     tt.bindings,
+				AllowDomains: tt.allowed,
+			}
+			if err := Execute(ctx, values, &Services{
+				Resource: svcs.Resource,
+			}); err != nil {
+				t.Errorf("%s failed: %q", tt.name, err)
+			}
+			var got []*crm.Binding
+			if crmStub.SavedSetPolicy != nil {
+				got = crmStub.SavedSetPolicy.Bindings
+			}
+			if diff := cmp.Diff(got, tt.expectedBindings); diff != "" {
+				t.Errorf("%v failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func revokeGrantsSetup() (*services.Global, *stubs.ResourceManagerStub) {
+	crmStub := &stubs.ResourceManagerStub{}
+	storageStub := &stubs.StorageStub{}
+	res := services.NewResource(crmStub, storageStub)
+	return &services.Global{Resource: res}, crmStub
+}
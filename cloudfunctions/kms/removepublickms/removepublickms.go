@@ -0,0 +1,70 @@
+package removepublickms
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// publicUsers contains a slice of public users we want to remove.
+var publicUsers = []string{"allUsers", "allAuthenticatedUsers"}
+
+// Values contains the required values needed for this function.
+type Values struct {
+	// Resource is the key ring or crypto key, such as
+	// "projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>".
+	Resource  string
+	ProjectID string
+	// RotationPeriod optionally schedules rotation of a crypto key. Rotation is off if zero.
+	RotationPeriod time.Duration
+	DryRun         bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	KMS *services.KMS
+}
+
+// Execute will remove any public users from the key ring or crypto key's IAM policy.
+//
+// If a rotation period is configured and the resource is a crypto key, its rotation is also scheduled.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	rotate := values.RotationPeriod > 0 && strings.Contains(values.Resource, "/cryptoKeys/")
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have removed public members from %q in project %q", values.Resource, values.ProjectID)
+		if rotate {
+			logging.FromContext(ctx).Info("dry_run on, would have scheduled rotation of %q every %s", values.Resource, values.RotationPeriod)
+		}
+		return nil
+	}
+	removed, err := services.KMS.RemoveMembers(ctx, values.Resource, publicUsers)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("removed %q from %q in project %q", removed, values.Resource, values.ProjectID)
+	if !rotate {
+		return nil
+	}
+	if err := services.KMS.ScheduleRotation(ctx, values.Resource, values.RotationPeriod, time.Now()); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("scheduled rotation of %q every %s", values.Resource, values.RotationPeriod)
+	return nil
+}
@@ -0,0 +1,102 @@
+package removepublickms
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	kms "google.golang.org/api/cloudkms/v1"
+)
+
+func TestRemovePublicKMS(t *testing.T) {
+	ctx := context.Background()
+	const (
+		keyRing   = "projects/test-project/locations/global/keyRings/ring"
+		cryptoKey = keyRing + "/cryptoKeys/key"
+	)
+	for _, tt := range []struct {
+		name             string
+		resource         string
+		rotationPeriod   time.Duration
+		dryRun           bool
+		expectedResource string
+		expectedBindings []*kms.Binding
+		expectRotation   bool
+	}{
+		{
+			name:             "remove public members from key ring",
+			resource:         keyRing,
+			rotationPeriod:   24 * time.Hour,
+			expectedResource: keyRing,
+			expectedBindings: []*kms.Binding{
+				{Role: "roles/cloudkms.cryptoKeyEncrypterDecrypter", Members: []string{"user:test@test.com"}},
+				{Role: "roles/cloudkms.viewer", Members: []string{}},
+			},
+		},
+		{
+			name:             "remove public members from crypto key and rotate",
+			resource:         cryptoKey,
+			rotationPeriod:   24 * time.Hour,
+			expectedResource: cryptoKey,
+			expectedBindings: []*kms.Binding{
+				{Role: "roles/cloudkms.cryptoKeyEncrypterDecrypter", Members: []string{"user:test@test.com"}},
+				{Role: "roles/cloudkms.viewer", Members: []string{}},
+			},
+			expectRotation: true,
+		},
+		{
+			name:           "dry run",
+			resource:       cryptoKey,
+			rotationPeriod: 24 * time.Hour,
+			dryRun:         true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			kmsStub := &stubs.KMSStub{StubbedPolicy: &kms.Policy{Bindings: []*kms.Binding{
+				{Role: "roles/cloudkms.cryptoKeyEncrypterDecrypter", Members: []string{"user:test@test.com", "allUsers"}},
+				{Role: "roles/cloudkms.viewer", Members: []string{"allAuthenticatedUsers"}},
+			}}}
+			values := &Values{
+				Resource:       tt.resource,
+				ProjectID:      "test-project",
+				RotationPeriod: tt.rotationPeriod,
+				DryRun:         tt.dryRun,
+			}
+			if err := Execute(ctx, values, &Services{
+				KMS: services.NewKMS(kmsStub),
+			}); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if kmsStub.SavedResource != tt.expectedResource {
+				t.Errorf("%s failed, got resource:%q want:%q", tt.name, kmsStub.SavedResource, tt.expectedResource)
+			}
+			var got []*kms.Binding
+			if kmsStub.SavedPolicy != nil {
+				got = kmsStub.SavedPolicy.Bindings
+			}
+			if diff := cmp.Diff(tt.expectedBindings, got); diff != "" {
+				t.Errorf("%s failed, difference in bindings: %+v", tt.name, diff)
+			}
+			if rotated := kmsStub.SavedKeyPatch != nil; rotated != tt.expectRotation {
+				t.Errorf("%s failed, got rotation:%v want:%v", tt.name, rotated, tt.expectRotation)
+			}
+		})
+	}
+}
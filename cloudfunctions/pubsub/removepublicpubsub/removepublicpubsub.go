@@ -0,0 +1,84 @@
+package removepublicpubsub
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// publicUsers contains a slice of public users we want to remove.
+var publicUsers = []string{"allUsers", "allAuthenticatedUsers"}
+
+// skippedMark is the security mark written to the finding when a resource is not closed.
+const skippedMark = "sra-remove-public-pubsub-skipped"
+
+// Values contains the required values needed for this function.
+type Values struct {
+	// Resource is the topic or subscription, such as "projects/<project>/topics/<topic>".
+	Resource       string
+	ProjectID      string
+	FindingName    string
+	AllowResources []string
+	DryRun         bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	PubSubAdmin           *services.PubSubAdmin
+	SecurityCommandCenter *services.CommandCenter
+}
+
+// Execute will remove any public users from the topic or subscription's IAM policy.
+//
+// Resources within the allow list are left untouched and the finding is marked as skipped.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	if allowed(values.Resource, values.AllowResources) {
+		logging.FromContext(ctx).Info("%q in project %q is allowed, skipping", values.Resource, values.ProjectID)
+		return markSkipped(ctx, values.FindingName, services)
+	}
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have removed public members from %q in project %q", values.Resource, values.ProjectID)
+		return nil
+	}
+	removed, err := services.PubSubAdmin.RemoveMembers(ctx, values.Resource, publicUsers)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("removed %q from %q in project %q", removed, values.Resource, values.ProjectID)
+	return nil
+}
+
+func allowed(resource string, allowResources []string) bool {
+	for _, r := range allowResources {
+		if r == resource {
+			return true
+		}
+	}
+	return false
+}
+
+func markSkipped(ctx context.Context, findingName string, services *Services) error {
+	if findingName == "" {
+		return nil
+	}
+	m := map[string]string{skippedMark: "allow_resources"}
+	if _, err := services.SecurityCommandCenter.AddSecurityMarks(ctx, findingName, m); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package removepublicpubsub
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	pubsub "google.golang.org/api/pubsub/v1"
+)
+
+func TestRemovePublicPubSub(t *testing.T) {
+	ctx := context.Background()
+	const findingName = "organizations/1055058813388/sources/1986930501971458034/findings/1c35bd4b4f6d7145e441f2965c32f074"
+	for _, tt := range []struct {
+		name             string
+		resource         string
+		allowResources   []string
+		dryRun           bool
+		expectedResource string
+		expectedBindings []*pubsub.Binding
+		expectedMarks    map[string]string
+	}{
+		{
+			name:             "remove public members from topic",
+			resource:         "projects/test-project/topics/public-topic",
+			expectedResource: "projects/test-project/topics/public-topic",
+			expectedBindings: []*pubsub.Binding{
+				{Role: "roles/pubsub.publisher", Members: []string{"user:test@test.com"}},
+				{Role: "roles/pubsub.viewer", Members: []string{}},
+			},
+		},
+		{
+			name:             "remove public members from subscription",
+			resource:         "projects/test-project/subscriptions/public-sub",
+			expectedResource: "projects/test-project/subscriptions/public-sub",
+			expectedBindings: []*pubsub.Binding{
+				{Role: "roles/pubsub.publisher", Members: []string{"user:test@test.com"}},
+				{Role: "roles/pubsub.viewer", Members: []string{}},
+			},
+		},
+		{
+			name:           "allowed resource is skipped",
+			resource:       "projects/test-project/topics/public-topic",
+			allowResources: []string{"projects/test-project/topics/public-topic"},
+			expectedMarks:  map[string]string{"sra-remove-public-pubsub-skipped": "allow_resources"},
+		},
+		{
+			name:     "dry run",
+			resource: "projects/test-project/topics/public-topic",
+			dryRun:   true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			psStub := &stubs.PubSubAdminStub{StubbedPolicy: &pubsub.Policy{Bindings: []*pubsub.Binding{
+				{Role: "roles/pubsub.publisher", Members: []string{"user:test@test.com", "allUsers"}},
+				{Role: "roles/pubsub.viewer", Members: []string{"allAuthenticatedUsers"}},
+			}}}
+			sccStub := &stubs.SecurityCommandCenterStub{}
+			values := &Values{
+				Resource:       tt.resource,
+				ProjectID:      "test-project",
+				FindingName:    findingName,
+				AllowResources: tt.allowResources,
+				DryRun:         tt.dryRun,
+			}
+			if err := Execute(ctx, values, &Services{
+				PubSubAdmin:           services.NewPubSubAdmin(psStub),
+				SecurityCommandCenter: services.NewCommandCenter(sccStub),
+			}); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if psStub.SavedResource != tt.expectedResource {
+				t.Errorf("%s failed, got resource:%q want:%q", tt.name, psStub.SavedResource, tt.expectedResource)
+			}
+			var got []*pubsub.Binding
+			if psStub.SavedPolicy != nil {
+				got = psStub.SavedPolicy.Bindings
+			}
+			if diff := cmp.Diff(tt.expectedBindings, got); diff != "" {
+				t.Errorf("%s failed, difference in bindings: %+v", tt.name, diff)
+			}
+			var marks map[string]string
+			if r := sccStub.GetUpdateSecurityMarksRequest; r != nil {
+				marks = r.GetSecurityMarks().GetMarks()
+			}
+			if diff := cmp.Diff(tt.expectedMarks, marks); diff != "" {
+				t.Errorf("%s failed, difference in marks: %+v", tt.name, diff)
+			}
+		})
+	}
+}
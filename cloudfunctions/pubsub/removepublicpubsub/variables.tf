@@ -0,0 +1,6 @@
+variable "setup" {}
+
+variable "folder-ids" {
+  type        = list(string)
+  description = "Remove public users from topics and subscriptions if they are within the given folder IDs."
+}
@@ -0,0 +1,102 @@
+package replay
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// Values contains the required values needed for this function. Without any, the failed
+// remediations whose backoff has passed are replayed.
+type Values struct {
+	// IDs are the remediations replayed right away, regardless of their backoff or attempts.
+	IDs []string `json:"ids"`
+	// All replays every failed remediation right away.
+	All bool `json:"all"`
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	DeadLetters *services.DeadLetters
+	PubSub      *services.PubSub
+}
+
+// Execute publishes the failed remediations to replay back to their topic. Replayed remediations
+// are removed, they are kept again if they fail again.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	letters, err := services.DeadLetters.List(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	failed := []string{}
+	replayed := 0
+	for _, l := range letters {
+		if !replay(values, l, now) {
+			continue
+		}
+		if err := publish(ctx, services, l); err != nil {
+			failed = append(failed, err.Error())
+			continue
+		}
+		if err := services.DeadLetters.Remove(ctx, l); err != nil {
+			failed = append(failed, err.Error())
+			continue
+		}
+		replayed++
+		logging.FromContext(ctx).Info("replayed remediation %q of %q, attempt %d", l.ID, l.Action, l.Attempts+1)
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to replay %d remediations: %s", len(failed), strings.Join(failed, "; "))
+	}
+	logging.FromContext(ctx).Info("replayed %d failed remediations", replayed)
+	return nil
+}
+
+// replay returns whether the failed remediation is replayed now.
+func replay(values *Values, l *services.DeadLetter, now time.Time) bool {
+	if values.All {
+		return true
+	}
+	if len(values.IDs) == 0 {
+		return l.Due(now)
+	}
+	for _, id := range values.IDs {
+		if id == l.ID {
+			return true
+		}
+	}
+	return false
+}
+
+func publish(ctx context.Context, svcs *Services, l *services.DeadLetter) error {
+	attributes := map[string]string{}
+	for k, v := range l.Attributes {
+		attributes[k] = v
+	}
+	attributes[services.AttemptAttribute] = strconv.Itoa(l.Attempts + 1)
+	if _, err := svcs.PubSub.Publish(ctx, l.Topic, &pubsub.Message{Data: l.Data, Attributes: attributes}); err != nil {
+		return fmt.Errorf("failed to publish remediation %q to %q: %q", l.ID, l.Topic, err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package replay
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+func TestReplay(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		values *Values
+		want   []string
+	}{
+		{name: "due", values: &Values{}, want: []string{"due"}},
+		{name: "on demand", values: &Values{IDs: []string{"exhausted"}}, want: []string{"exhausted"}},
+		{name: "all", values: &Values{All: true}, want: []string{"due", "exhausted", "waiting"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			fsStub := &stubs.FirestoreStub{}
+			psStub := &stubs.PubSubStub{}
+			deadLetters := services.NewDeadLetters(fsStub, "automation-project", services.DeadLetterConfig{Backoff: time.Minute, MaxAttempts: 3})
+			for _, l := range []struct {
+				id       string
+				attempts int
+				failed   time.Time
+			}{
+				{id: "due", attempts: 1, failed: time.Now().Add(-time.Hour)},
+				{id: "exhausted", attempts: 3, failed: time.Now().Add(-time.Hour)},
+				{id: "waiting", failed: time.Now()},
+			} {
+				d := &services.DeadLetter{
+					ID:         l.id,
+					Action:     "OpenFirewall",
+					Topic:      "threat-findings-open-firewall",
+					Data:       []byte(`{"ProjectID":"test-project"}`),
+					Attributes: map[string]string{"finding_id": "finding-1"},
+					Attempts:   l.attempts,
+					Failed:     l.failed,
+				}
+				if err := deadLetters.Store(ctx, d, l.failed); err != nil {
+					t.Fatalf("%s failed to store %q: %q", tt.name, l.id, err)
+				}
+			}
+			if err := Execute(ctx, tt.values, &Services{
+				DeadLetters: deadLetters,
+				PubSub:      services.NewPubSub(psStub),
+			}); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if len(psStub.PublishedMessages) != len(tt.want) {
+				t.Fatalf("%s failed: got %d replayed want %d", tt.name, len(psStub.PublishedMessages), len(tt.want))
+			}
+			for _, id := range tt.want {
+				if _, ok := fsStub.StubbedDocuments["automation-dead-letters/"+id]; ok {
+					t.Errorf("%s failed: replayed %q wasn't removed", tt.name, id)
+				}
+			}
+			m := psStub.PublishedMessages[0]
+			if string(m.Data) != `{"ProjectID":"test-project"}` || m.Attributes["finding_id"] != "finding-1" || m.Attributes[services.AttemptAttribute] == "" {
+				t.Errorf("%s failed: unexpected message %+v", tt.name, m)
+			}
+		})
+	}
+}
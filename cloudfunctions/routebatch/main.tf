@@ -0,0 +1,75 @@
+# Copyright 2019 Google LLC
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+# 	https://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+resource "google_cloudfunctions_function" "route_batch" {
+  count                 = var.batch-routing ? 1 : 0
+  name                  = "RouteBatch"
+  description           = "Pulls and routes findings in batches."
+  runtime               = "go116"
+  available_memory_mb   = 512
+  source_archive_bucket = var.setup.gcf-bucket-name
+  source_archive_object = var.setup.gcf-object-name
+  timeout               = 540
+  project               = var.setup.automation-project
+  region                = var.setup.region
+  entry_point           = "RouteBatch"
+  service_account_email = var.setup.automation-service-account
+
+  event_trigger {
+    event_type = "google.pubsub.topic.publish"
+    resource   = "threat-findings-route-batch"
+  }
+  environment_variables = {
+    GCP_PROJECT    = var.setup.automation-project
+    SRA_CONFIG_URI = var.setup.config-uri
+  }
+  timeouts {
+    create = "10m"
+    update = "10m"
+  }
+}
+
+# Holds the findings published to the router topic until a batch pulls them. Findings not routed
+# are redelivered once their ack deadline, which covers the function's timeout, passes.
+resource "google_pubsub_subscription" "route_batch_subscription" {
+  count                = var.batch-routing ? 1 : 0
+  name                 = "threat-findings-route-batch"
+  project              = var.setup.automation-project
+  topic                = var.setup.router-topic-id
+  ack_deadline_seconds = 600
+}
+
+# PubSub topic to trigger this function.
+resource "google_pubsub_topic" "topic" {
+  count   = var.batch-routing ? 1 : 0
+  name    = "threat-findings-route-batch"
+  project = var.setup.automation-project
+}
+
+# Periodically routes a batch of findings.
+resource "google_cloud_scheduler_job" "route_batch_job" {
+  count    = var.batch-routing ? 1 : 0
+  name     = "route-batch"
+  project  = var.setup.automation-project
+  region   = var.setup.region
+  schedule = var.schedule
+
+  pubsub_target {
+    topic_name = google_pubsub_topic.topic[0].id
+    data = base64encode(jsonencode({
+      subscription = google_pubsub_subscription.route_batch_subscription[0].id
+      max_messages = var.max-messages
+      concurrency  = var.concurrency
+    }))
+  }
+}
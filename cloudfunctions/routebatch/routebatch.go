@@ -0,0 +1,146 @@
+package routebatch
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/router"
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// defaultMaxMessages is the number of findings pulled per invocation if not set.
+	defaultMaxMessages = 100
+	// defaultConcurrency is the number of projects routed at once if not set.
+	defaultConcurrency = 10
+	// ackTimeout is how long acknowledging the findings routed may take, even once the
+	// invocation timed out.
+	ackTimeout = 10 * time.Second
+)
+
+// Values contains the required values needed for this function.
+type Values struct {
+	// Subscription is the pull subscription of the findings, such as
+	// "projects/automation-project/subscriptions/threat-findings-route-batch".
+	Subscription string `json:"subscription"`
+	// MaxMessages is the number of findings pulled, defaults to 100.
+	MaxMessages int `json:"max_messages"`
+	// Concurrency is the number of projects whose findings are routed at once, defaults to 10.
+	Concurrency int `json:"concurrency"`
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Subscriber *services.Subscriber
+	// Router routes every finding of the batch, so its clients are set up once per invocation.
+	Router *router.Services
+}
+
+// Execute pulls a batch of findings and routes them, findings of different projects concurrently.
+// Findings of the same project are routed one after the other so their automations are published
+// in order, the automations themselves lock the project before remediating it. Findings routed are
+// acknowledged, the others are redelivered to a later batch.
+func Execute(ctx context.Context, values *Values, svcs *Services) error {
+	if values.Subscription == "" {
+		return fmt.Errorf("missing subscription")
+	}
+	maxMessages, concurrency := values.MaxMessages, values.Concurrency
+	if maxMessages <= 0 {
+		maxMessages = defaultMaxMessages
+	}
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	messages, err := svcs.Subscriber.Pull(ctx, values.Subscription, maxMessages)
+	if err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	var (
+		mu     sync.Mutex
+		routed []*services.Message
+		failed []string
+	)
+	var g errgroup.Group
+	g.SetLimit(concurrency)
+	for _, batch := range byProject(messages) {
+		batch := batch
+		g.Go(func() error {
+			for _, m := range batch {
+				// Findings left once the invocation times out are redelivered.
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				err := router.Execute(ctx, &router.Values{Finding: m.Data}, svcs.Router)
+				mu.Lock()
+				if err != nil {
+					failed = append(failed, err.Error())
+				} else {
+					routed = append(routed, m)
+				}
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	waitErr := g.Wait()
+	// Findings routed before the invocation timed out are still acknowledged, so they aren't
+	// routed again.
+	ackCtx, cancel := context.WithTimeout(context.Background(), ackTimeout)
+	defer cancel()
+	if err := svcs.Subscriber.Acknowledge(ackCtx, values.Subscription, routed); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("routed %d of %d findings pulled from %q", len(routed), len(messages), values.Subscription)
+	if waitErr != nil {
+		return errors.Wrap(waitErr, "failed to route the batch")
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to route %d findings: %s", len(failed), strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// byProject groups the messages by the project of their finding, in the order they were pulled.
+// Findings without a project are routed on their own.
+func byProject(messages []*services.Message) [][]*services.Message {
+	batches := [][]*services.Message{}
+	index := map[string]int{}
+	for _, m := range messages {
+		project := router.FindingProject(m.Data)
+		if project == "" {
+			batches = append(batches, []*services.Message{m})
+			continue
+		}
+		i, ok := index[project]
+		if !ok {
+			i = len(batches)
+			index[project] = i
+			batches = append(batches, nil)
+		}
+		batches[i] = append(batches[i], m)
+	}
+	return batches
+}
@@ -0,0 +1,189 @@
+package routebatch
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	gpubsub "cloud.google.com/go/pubsub"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gcs/closebucket"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/router"
+	"github.com/googlecloudplatform/security-response-automation/findings/fixtures"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	pubsub "google.golang.org/api/pubsub/v1"
+)
+
+func TestRouteBatch(t *testing.T) {
+	findings := map[string][]byte{
+		"first":   fixtures.Read(t, "public_bucket_acl", "finding.name=organizations/1/sources/2/findings/first", "finding.resourceName=//storage.googleapis.com/first"),
+		"second":  fixtures.Read(t, "public_bucket_acl", "finding.name=organizations/1/sources/2/findings/second", "finding.resourceName=//storage.googleapis.com/second"),
+		"other":   fixtures.Read(t, "public_bucket_acl", "finding.name=organizations/1/sources/2/findings/other", "finding.resourceName=//storage.googleapis.com/other", "finding.sourceProperties.ProjectId=other-project"),
+		"invalid": []byte(`{"finding": {}}`),
+	}
+	for _, tt := range []struct {
+		name       string
+		pulled     []string
+		values     *Values
+		wantAcked  []string
+		wantClosed []string
+		canceled   bool
+		timeout    bool
+		wantErr    bool
+	}{
+		{
+			name:       "batch",
+			pulled:     []string{"first", "other", "second"},
+			values:     &Values{Subscription: "projects/p/subscriptions/s"},
+			wantAcked:  []string{"first", "other", "second"},
+			wantClosed: []string{"first", "other", "second"},
+		},
+		{
+			name:       "failed findings redelivered",
+			pulled:     []string{"first", "invalid", "second"},
+			values:     &Values{Subscription: "projects/p/subscriptions/s", Concurrency: 1},
+			wantAcked:  []string{"first", "second"},
+			wantClosed: []string{"first", "second"},
+			wantErr:    true,
+		},
+		{
+			name:       "bounded batch",
+			pulled:     []string{"first", "second", "other"},
+			values:     &Values{Subscription: "projects/p/subscriptions/s", MaxMessages: 2},
+			wantAcked:  []string{"first", "second"},
+			wantClosed: []string{"first", "second"},
+		},
+		{
+			name:       "timed out while routing",
+			pulled:     []string{"first", "second"},
+			values:     &Values{Subscription: "projects/p/subscriptions/s", Concurrency: 1},
+			wantAcked:  []string{"first"},
+			wantClosed: []string{"first"},
+			timeout:    true,
+			wantErr:    true,
+		},
+		{
+			name:       "canceled",
+			pulled:     []string{"first", "other"},
+			values:     &Values{Subscription: "projects/p/subscriptions/s"},
+			wantClosed: []string{},
+			canceled:   true,
+			wantErr:    true,
+		},
+		{name: "missing subscription", values: &Values{}, wantClosed: []string{}, wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			subStub := &stubs.SubscriberStub{}
+			for _, id := range tt.pulled {
+				subStub.StubbedMessages = append(subStub.StubbedMessages, &pubsub.ReceivedMessage{
+					AckId:   id,
+					Message: &pubsub.PubsubMessage{Data: base64.StdEncoding.EncodeToString(findings[id])},
+				})
+			}
+			psStub := &stubs.PubSubStub{}
+			ctx, cancel := context.WithCancel(context.Background())
+			if tt.canceled {
+				cancel()
+			}
+			defer cancel()
+			var publisher services.PubSubClient = psStub
+			if tt.timeout {
+				// The invocation times out once the first finding was routed.
+				publisher = &timingOutPubSub{PubSubStub: psStub, cancel: cancel}
+			}
+			crmStub := &stubs.ResourceManagerStub{}
+			crmStub.GetAncestryResponse = services.CreateAncestors([]string{"project/test-project", "organization/456"})
+			conf := &router.Configuration{}
+			conf.Spec.Parameters.SHA.PublicBucketACL = []router.Automation{{Action: "close_bucket", Target: []string{"organizations/456/*"}}}
+
+			err := Execute(ctx, tt.values, &Services{
+				Subscriber: services.NewSubscriber(subStub),
+				Router: &router.Services{
+					PubSub:                services.NewPubSub(publisher),
+					Configuration:         conf,
+					Resource:              services.NewResource(crmStub, &stubs.StorageStub{}),
+					SecurityCommandCenter: services.NewCommandCenter(&stubs.SecurityCommandCenterStub{}),
+				},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%s failed: got error %v", tt.name, err)
+			}
+			acked := map[string]bool{}
+			for _, id := range subStub.Acknowledged {
+				acked[id] = true
+			}
+			want := map[string]bool{}
+			for _, id := range tt.wantAcked {
+				want[id] = true
+			}
+			if diff := cmp.Diff(acked, want); diff != "" {
+				t.Errorf("%s failed, acknowledged difference:%+v", tt.name, diff)
+			}
+			closed := []string{}
+			for _, m := range psStub.PublishedMessages {
+				var values closebucket.Values
+				if err := json.Unmarshal(m.Data, &values); err != nil {
+					t.Fatalf("%s failed to unmarshal %q: %q", tt.name, m.Data, err)
+				}
+				closed = append(closed, values.BucketName)
+			}
+			sort.Strings(closed)
+			if diff := cmp.Diff(closed, tt.wantClosed); diff != "" {
+				t.Errorf("%s failed, closed difference:%+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+// timingOutPubSub publishes the message and times out the invocation.
+type timingOutPubSub struct {
+	*stubs.PubSubStub
+	cancel func()
+}
+
+func (p *timingOutPubSub) Publish(ctx context.Context, topic *gpubsub.Topic, message *gpubsub.Message) (string, error) {
+	defer p.cancel()
+	return p.PubSubStub.Publish(ctx, topic, message)
+}
+
+func TestByProject(t *testing.T) {
+	read := func(rule, project string) *services.Message {
+		return &services.Message{AckID: project, Data: fixtures.Read(t, rule, "finding.sourceProperties.ProjectId="+project)}
+	}
+	messages := []*services.Message{
+		read("public_bucket_acl", "a"),
+		read("public_bucket_acl", "b"),
+		read("public_bucket_acl", "a"),
+		{AckID: "none", Data: []byte(`{}`)},
+		{AckID: "none", Data: []byte(`{}`)},
+	}
+	got := [][]string{}
+	for _, batch := range byProject(messages) {
+		ids := []string{}
+		for _, m := range batch {
+			ids = append(ids, m.AckID)
+		}
+		got = append(got, ids)
+	}
+	want := [][]string{{"a", "a"}, {"b"}, {"none"}, {"none"}}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("byProject failed, difference:%+v", diff)
+	}
+}
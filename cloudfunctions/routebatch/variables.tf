@@ -0,0 +1,24 @@
+variable "setup" {}
+
+variable "batch-routing" {
+  type        = bool
+  description = "If true, findings are routed in batches by this function instead of one by one by the router."
+}
+
+variable "schedule" {
+  type        = string
+  default     = "* * * * *"
+  description = "Cron schedule on which a batch of findings is routed."
+}
+
+variable "max-messages" {
+  type        = number
+  default     = 100
+  description = "Number of findings pulled per batch."
+}
+
+variable "concurrency" {
+  type        = number
+  default     = 10
+  description = "Number of projects whose findings are routed at once."
+}
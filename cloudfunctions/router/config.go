@@ -0,0 +1,232 @@
+package router
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/clients"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/billing/disablebilling"
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// configURIEnv names the environment variable holding the GCS object the configuration is read
+	// from, such as "gs://cloudorg-sra-config/sra.yaml". The deployed config.yaml is used if unset.
+	configURIEnv = "SRA_CONFIG_URI"
+	// configTTLEnv names the environment variable holding how long the configuration read from GCS
+	// is cached for, such as "30s".
+	configTTLEnv = "SRA_CONFIG_TTL"
+	// configPathEnv names the environment variable holding a local file the configuration is read
+	// from instead of the deployed config.yaml, such as "config/sra.yaml" when running locally.
+	configPathEnv = "SRA_CONFIG_PATH"
+	// defaultConfigTTL is how long the configuration read from GCS is cached for if no TTL is set.
+	defaultConfigTTL = time.Minute
+	// secretTTL is how long the payloads of secret versions referenced by an alias, such as
+	// "latest", are cached for before they are accessed again to pick up rotations.
+	secretTTL = 5 * time.Minute
+)
+
+// ConfigStorage contains minimum interface required to read the configuration from GCS.
+type ConfigStorage interface {
+	ReadObject(ctx context.Context, bucketName, name string) ([]byte, error)
+}
+
+// configCache keeps the configuration read from GCS until its TTL passes, so changes to the object
+// are picked up without redeploying the functions.
+type configCache struct {
+	mu      sync.Mutex
+	storage ConfigStorage
+	uri     string
+	conf    *Configuration
+	expires time.Time
+}
+
+var cache = &configCache{}
+
+// secrets resolves the secret references of configurations. It is kept across invocations so
+// payloads are cached.
+var secrets = services.NewSecretResolver(&lazySecretManager{}, secretTTL)
+
+// lazySecretManager initializes the Secret Manager service when a secret is first accessed, so
+// configurations without secret references don't need it.
+type lazySecretManager struct {
+	mu sync.Mutex
+	sm *services.SecretManager
+}
+
+// Access returns the payload of the secret version.
+func (l *lazySecretManager) Access(ctx context.Context, version string) ([]byte, error) {
+	l.mu.Lock()
+	if l.sm == nil {
+		sm, err := services.InitSecretManager(ctx)
+		if err != nil {
+			l.mu.Unlock()
+			return nil, err
+		}
+		l.sm = sm
+	}
+	l.mu.Unlock()
+	return l.sm.Access(ctx, version)
+}
+
+// Config will return the router's configuration.
+func Config() (*Configuration, error) {
+	uri := os.Getenv(configURIEnv)
+	if uri == "" {
+		path := configPath
+		if p := os.Getenv(configPathEnv); p != "" {
+			path = p
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return parseConfig(context.Background(), b)
+	}
+	ttl := defaultConfigTTL
+	if v := os.Getenv(configTTLEnv); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s", configTTLEnv)
+		}
+		ttl = d
+	}
+	return cache.get(context.Background(), uri, ttl, time.Now())
+}
+
+// get returns the configuration of the object, reading it again once the cached one expired. If
+// reading fails the expired configuration is used until the next TTL passes.
+func (c *configCache) get(ctx context.Context, uri string, ttl time.Duration, now time.Time) (*Configuration, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conf != nil && c.uri == uri && now.Before(c.expires) {
+		return c.conf, nil
+	}
+	conf, err := c.read(ctx, uri)
+	if err != nil {
+		if c.conf == nil || c.uri != uri {
+			return nil, err
+		}
+		logging.FromContext(ctx).Warning("failed to reload config, using the previous one: %q", err)
+		c.expires = now.Add(ttl)
+		return c.conf, nil
+	}
+	c.uri, c.conf, c.expires = uri, conf, now.Add(ttl)
+	return conf, nil
+}
+
+func (c *configCache) read(ctx context.Context, uri string) (*Configuration, error) {
+	path := strings.TrimPrefix(uri, "gs://")
+	i := strings.Index(path, "/")
+	if path == uri || i < 1 || i == len(path)-1 {
+		return nil, fmt.Errorf("config uri %q is not of the form gs://<bucket>/<object>", uri)
+	}
+	if c.storage == nil {
+		s, err := clients.NewStorage(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to initialize storage client")
+		}
+		c.storage = s
+	}
+	b, err := c.storage.ReadObject(ctx, path[:i], path[i+1:])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read config from %q", uri)
+	}
+	return parseConfig(ctx, b)
+}
+
+// parseConfig parses the configuration and resolves its secret references, such as
+// "secret://projects/p/secrets/s/versions/latest", to the payloads of the secret versions.
+func parseConfig(ctx context.Context, b []byte) (*Configuration, error) {
+	var c Configuration
+	if err := yaml.Unmarshal(b, &c); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal config.yaml")
+	}
+	if err := secrets.Resolve(ctx, &c, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	// The clients are shared by all automations of the function, so the policy applies to all.
+	clients.SetRetryPolicy(c.Spec.Retry)
+	return &c, nil
+}
+
+// validate checks the configuration can be used safely.
+func (c *Configuration) validate() error {
+	approval := c.Spec.Approval
+	// Actions outside of their maintenance windows wait for approval as well.
+	approvals := len(approval.Actions) > 0 || len(approval.RequiredApprovers) > 0 || len(c.Spec.MaintenanceWindows) > 0
+	if (approvals || c.DisablesBilling()) && len(approval.Key) < services.MinApprovalKeyLength {
+		return fmt.Errorf("approvals require a spec.approval.key of at least %d characters to sign their links", services.MinApprovalKeyLength)
+	}
+	if c.DisablesBilling() && approval.URL == "" {
+		return fmt.Errorf("%s requires spec.approval.url to link approvers to", disablebilling.Action)
+	}
+	return nil
+}
+
+// DisablesBilling returns whether the configuration runs the disable billing automation, which
+// always waits for approval.
+func (c *Configuration) DisablesBilling() bool {
+	for _, a := range c.Spec.Parameters.ETD.BadIP {
+		if a.Action == disablebilling.Action {
+			return true
+		}
+	}
+	for _, m := range c.Spec.Mappings {
+		for _, a := range m.Automations {
+			if a.Action == disablebilling.Action {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SendGridKey returns the SendGrid API key of the first automation running the action that emails
+// from the sender, or an empty key if none does. Dead letters are kept with their secrets
+// redacted, so replayed remediations read the key from the configuration again.
+func (c *Configuration) SendGridKey(action, from string) string {
+	sha := c.Spec.Parameters.SHA
+	automations := append(append(append([]Automation{}, sha.SQLNoRootPassword...), sha.ServiceAccountKeyNotRotated...), sha.UserManagedServiceAccountKey...)
+	for _, m := range c.Spec.Mappings {
+		automations = append(automations, m.Automations...)
+	}
+	for _, a := range automations {
+		if a.Action != action {
+			continue
+		}
+		sendGrid := a.Properties.UpdatePassword.SendGrid
+		if action == "disable_old_keys" {
+			sendGrid = a.Properties.DisableOldKeys.SendGrid
+		}
+		if sendGrid.From == from && sendGrid.APIKey != "" {
+			return sendGrid.APIKey
+		}
+	}
+	return ""
+}
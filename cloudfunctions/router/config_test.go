@@ -0,0 +1,108 @@
+package router
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+)
+
+func TestConfigCache(t *testing.T) {
+	ctx := context.Background()
+	const uri = "gs://cloudorg-sra-config/config/sra.yaml"
+	now := time.Now()
+	stub := &stubs.StorageStub{Objects: map[string][]byte{"cloudorg-sra-config/config/sra.yaml": []byte("spec:\n  dry_run: true\n")}}
+	c := &configCache{storage: stub}
+	conf, err := c.get(ctx, uri, time.Minute, now)
+	if err != nil {
+		t.Fatalf("failed to read config: %q", err)
+	}
+	if !conf.Spec.DryRun {
+		t.Errorf("got dry run %t want true", conf.Spec.DryRun)
+	}
+	stub.Objects["cloudorg-sra-config/config/sra.yaml"] = []byte("spec:\n  dry_run: false\n")
+	if conf, _ := c.get(ctx, uri, time.Minute, now.Add(30*time.Second)); !conf.Spec.DryRun {
+		t.Error("config reloaded before its TTL passed")
+	}
+	if conf, _ := c.get(ctx, uri, time.Minute, now.Add(2*time.Minute)); conf.Spec.DryRun {
+		t.Error("config not reloaded after its TTL passed")
+	}
+	delete(stub.Objects, "cloudorg-sra-config/config/sra.yaml")
+	if _, err := c.get(ctx, uri, time.Minute, now.Add(4*time.Minute)); err != nil {
+		t.Errorf("previous config should be used if reloading fails, got %q", err)
+	}
+	if _, err := (&configCache{storage: stub}).get(ctx, uri, time.Minute, now); err == nil {
+		t.Error("expected error reading missing config")
+	}
+	if _, err := (&configCache{storage: stub}).get(ctx, "cloudorg-sra-config/sra.yaml", time.Minute, now); err == nil {
+		t.Error("expected error for uri without gs:// scheme")
+	}
+}
+
+func TestParseConfigApprovalKey(t *testing.T) {
+	const key = "0123456789abcdef0123456789abcdef"
+	for _, tt := range []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{name: "no approvals", config: "spec:\n  dry_run: true\n"},
+		{name: "actions", config: "spec:\n  approval:\n    actions: [suspend_user]\n    key: " + key + "\n"},
+		{name: "actions without key", config: "spec:\n  approval:\n    actions: [suspend_user]\n", wantErr: true},
+		{name: "actions with short key", config: "spec:\n  approval:\n    actions: [suspend_user]\n    key: secret\n", wantErr: true},
+		{name: "required approvers without key", config: "spec:\n  approval:\n    required_approvers:\n      suspend_user: 2\n", wantErr: true},
+		{name: "maintenance windows without key", config: "spec:\n  maintenance_windows:\n    - actions: [suspend_user]\n", wantErr: true},
+		{name: "disable billing", config: "spec:\n  approval:\n    url: https://approve.example.com\n    key: " + key + "\n  parameters:\n    etd:\n      bad_ip:\n        - action: disable_billing\n"},
+		{name: "disable billing without key", config: "spec:\n  approval:\n    url: https://approve.example.com\n  parameters:\n    etd:\n      bad_ip:\n        - action: disable_billing\n", wantErr: true},
+		{name: "disable billing without url", config: "spec:\n  approval:\n    key: " + key + "\n  parameters:\n    etd:\n      bad_ip:\n        - action: disable_billing\n", wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseConfig(context.Background(), []byte(tt.config)); (err != nil) != tt.wantErr {
+				t.Errorf("%s failed: got err %v want err %t", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSendGridKey(t *testing.T) {
+	updatePassword := Automation{Action: "cloud_sql_update_password"}
+	updatePassword.Properties.UpdatePassword.SendGrid.APIKey = "SG.password"
+	updatePassword.Properties.UpdatePassword.SendGrid.From = "sra@cloudorg.com"
+	disableOldKeys := Automation{Action: "disable_old_keys"}
+	disableOldKeys.Properties.DisableOldKeys.SendGrid.APIKey = "SG.keys"
+	disableOldKeys.Properties.DisableOldKeys.SendGrid.From = "keys@cloudorg.com"
+	conf := &Configuration{}
+	conf.Spec.Parameters.SHA.SQLNoRootPassword = []Automation{updatePassword}
+	conf.Spec.Mappings = []Mapping{{Automations: []Automation{disableOldKeys}}}
+	for _, tt := range []struct {
+		name   string
+		action string
+		from   string
+		want   string
+	}{
+		{name: "update password", action: "cloud_sql_update_password", from: "sra@cloudorg.com", want: "SG.password"},
+		{name: "mapped disable old keys", action: "disable_old_keys", from: "keys@cloudorg.com", want: "SG.keys"},
+		{name: "other sender", action: "disable_old_keys", from: "sra@cloudorg.com"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conf.SendGridKey(tt.action, tt.from); got != tt.want {
+				t.Errorf("%s failed: got %q want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -29,7 +29,9 @@ resource "google_cloudfunctions_function" "router" {
     resource   = var.setup.router-topic-id
   }
   environment_variables = {
-    GCP_PROJECT = var.setup.automation-project
+    GCP_PROJECT    = var.setup.automation-project
+    SRA_CONFIG_URI = var.setup.config-uri
+    BATCH_ROUTING  = var.batch-routing
   }
   timeouts {
     create = "10m"
@@ -50,3 +52,11 @@ resource "google_folder_iam_member" "roles-browser" {
   role   = "roles/browser"
   member = "serviceAccount:${var.setup.automation-service-account}"
 }
+
+# Required to look up the labels of resources automations select by label.
+resource "google_folder_iam_member" "roles-cloudasset-viewer" {
+  count  = length(var.folder-ids)
+  folder = "folders/${var.folder-ids[count.index]}"
+  role   = "roles/cloudasset.viewer"
+  member = "serviceAccount:${var.setup.automation-service-account}"
+}
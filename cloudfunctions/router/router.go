@@ -18,30 +18,65 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"log"
+	"path"
+	"strings"
+	"time"
 
 	"cloud.google.com/go/pubsub"
+	"github.com/google/uuid"
+	"github.com/googlecloudplatform/security-response-automation/automations"
+	"github.com/googlecloudplatform/security-response-automation/clients"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/billing/disablebilling"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/removenonorgmembers"
+	"github.com/googlecloudplatform/security-response-automation/events"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/metrics"
+	"github.com/googlecloudplatform/security-response-automation/providers/aws/guardduty"
+	"github.com/googlecloudplatform/security-response-automation/providers/azure/defender"
+	"github.com/googlecloudplatform/security-response-automation/providers/ctd/containerthreat"
+	"github.com/googlecloudplatform/security-response-automation/providers/custom/detection"
+	"github.com/googlecloudplatform/security-response-automation/providers/etd/accountcompromised"
 	"github.com/googlecloudplatform/security-response-automation/providers/etd/anomalousiam"
 	"github.com/googlecloudplatform/security-response-automation/providers/etd/badip"
+	"github.com/googlecloudplatform/security-response-automation/providers/etd/firewallrulecreated"
+	"github.com/googlecloudplatform/security-response-automation/providers/etd/impersonation"
+	"github.com/googlecloudplatform/security-response-automation/providers/etd/roguejob"
 	"github.com/googlecloudplatform/security-response-automation/providers/etd/sshbruteforce"
+	"github.com/googlecloudplatform/security-response-automation/providers/falco/falcoalert"
+	"github.com/googlecloudplatform/security-response-automation/providers/forseti/violation"
+	"github.com/googlecloudplatform/security-response-automation/providers/sha/apikeyscanner"
+	"github.com/googlecloudplatform/security-response-automation/providers/sha/artifactscanner"
 	"github.com/googlecloudplatform/security-response-automation/providers/sha/computeinstancescanner"
 	"github.com/googlecloudplatform/security-response-automation/providers/sha/containerscanner"
 	"github.com/googlecloudplatform/security-response-automation/providers/sha/datasetscanner"
+	"github.com/googlecloudplatform/security-response-automation/providers/sha/dnsscanner"
 	"github.com/googlecloudplatform/security-response-automation/providers/sha/firewallscanner"
 	"github.com/googlecloudplatform/security-response-automation/providers/sha/iamscanner"
+	"github.com/googlecloudplatform/security-response-automation/providers/sha/kmsscanner"
+	"github.com/googlecloudplatform/security-response-automation/providers/sha/loadbalancerscanner"
 	"github.com/googlecloudplatform/security-response-automation/providers/sha/loggingscanner"
+	"github.com/googlecloudplatform/security-response-automation/providers/sha/networkscanner"
+	"github.com/googlecloudplatform/security-response-automation/providers/sha/pubsubscanner"
+	"github.com/googlecloudplatform/security-response-automation/providers/sha/serverlessscanner"
 	"github.com/googlecloudplatform/security-response-automation/providers/sha/sqlscanner"
 	"github.com/googlecloudplatform/security-response-automation/providers/sha/storagescanner"
 	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/googlecloudplatform/security-response-automation/simulate"
+	"github.com/googlecloudplatform/security-response-automation/tracing"
 	"github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-var findings = []Namer{
+var providers = []Namer{
 	&anomalousiam.Finding{},
+	&impersonation.Finding{},
+	// Cryptomining findings against Dataproc and Dataflow resources must be named before bad IP findings.
+	&roguejob.Finding{},
 	&badip.Finding{},
 	&sshbruteforce.Finding{},
+	&firewallrulecreated.Finding{},
+	&accountcompromised.Finding{},
 	&storagescanner.Finding{},
 	&sqlscanner.Finding{},
 	&containerscanner.Finding{},
@@ -50,6 +85,20 @@ var findings = []Namer{
 	&datasetscanner.Finding{},
 	&loggingscanner.Finding{},
 	&iamscanner.Finding{},
+	&serverlessscanner.Finding{},
+	&loadbalancerscanner.Finding{},
+	&networkscanner.Finding{},
+	&dnsscanner.Finding{},
+	&apikeyscanner.Finding{},
+	&pubsubscanner.Finding{},
+	&kmsscanner.Finding{},
+	&artifactscanner.Finding{},
+	&containerthreat.Finding{},
+	&guardduty.Finding{},
+	&defender.Finding{},
+	&falcoalert.Finding{},
+	&violation.Finding{},
+	&detection.Finding{},
 }
 
 // originalEventTime is the security mark key name used to hold the finding's event time.
@@ -65,9 +114,29 @@ type Namer interface {
 type Services struct {
 	PubSub                *services.PubSub
 	Configuration         *Configuration
-	Logger                *services.Logger
 	Resource              *services.Resource
 	SecurityCommandCenter *services.CommandCenter
+	// Approvals and Email, Slack or Teams are only required if actions wait for approval.
+	Approvals *services.Approvals
+	Email     *services.Email
+	Slack     *services.Slack
+	Teams     *services.Teams
+	// Audit records the findings skipped because they are exempted, if set.
+	Audit *services.Audit
+	// Exemptions keeps the findings skipped until their exemption expires, if set.
+	Exemptions *services.Exemptions
+	// Tickets files tickets for the findings skipped, if set.
+	Tickets *services.Tickets
+	// Events streams the findings skipped as remediation events, if set.
+	Events *events.Stream
+	// Assets looks up the labels of resources, only required if automations select labels.
+	Assets *services.Assets
+	// Idempotency skips findings redelivered by Pub/Sub, if set.
+	Idempotency *services.Idempotency
+	// Metrics records the findings received and skipped, if set.
+	Metrics metrics.Recorder
+	// Counter counts the findings of projects, only required if disable_billing has a threshold.
+	Counter *services.Counter
 }
 
 // Values contains the required values for this function.
@@ -77,34 +146,137 @@ type Values struct {
 
 // topics maps automation targets to PubSub topics.
 var topics = map[string]struct{ Topic string }{
-	"gce_create_disk_snapshot":  {Topic: "threat-findings-create-disk-snapshot"},
-	"iam_revoke":                {Topic: "threat-findings-iam-revoke"},
-	"close_bucket":              {Topic: "threat-findings-close-bucket"},
-	"enable_bucket_only_policy": {Topic: "threat-findings-enable-bucket-only-policy"},
-	"close_cloud_sql":           {Topic: "threat-findings-remove-public-sql"},
-	"cloud_sql_require_ssl":     {Topic: "threat-findings-require-ssl"},
-	"cloud_sql_update_password": {Topic: "threat-findings-update-password"},
-	"disable_dashboard":         {Topic: "threat-findings-disable-dashboard"},
-	"remove_public_ip":          {Topic: "threat-findings-remove-public-ip"},
-	"remediate_firewall":        {Topic: "threat-findings-open-firewall"},
-	"close_public_dataset":      {Topic: "threat-findings-close-public-dataset"},
-	"enable_audit_logs":         {Topic: "threat-findings-enable-audit-logs"},
-	"remove_non_org_members":    {Topic: "threat-findings-remove-non-org-members"},
+	"gce_create_disk_snapshot":     {Topic: "threat-findings-create-disk-snapshot"},
+	"quarantine_instance":          {Topic: "threat-findings-quarantine-instance"},
+	"disable_billing":              {Topic: "threat-findings-disable-billing"},
+	"stop_rogue_job":               {Topic: "threat-findings-stop-rogue-job"},
+	"remove_impersonation":         {Topic: "threat-findings-remove-impersonation"},
+	"quarantine_image":             {Topic: "threat-findings-quarantine-image"},
+	"rotate_secrets":               {Topic: "threat-findings-rotate-secrets"},
+	"iam_revoke":                   {Topic: "threat-findings-iam-revoke"},
+	"iam_revoke_grants":            {Topic: "threat-findings-iam-revoke-grants"},
+	"restore_iam_policy":           {Topic: "threat-findings-restore-iam-policy"},
+	"suspend_user":                 {Topic: "threat-findings-suspend-user"},
+	"revoke_user_tokens":           {Topic: "threat-findings-revoke-user-tokens"},
+	"enforce_reenrollment":         {Topic: "threat-findings-enforce-reenrollment"},
+	"close_bucket":                 {Topic: "threat-findings-close-bucket"},
+	"enable_bucket_only_policy":    {Topic: "threat-findings-enable-bucket-only-policy"},
+	"close_cloud_sql":              {Topic: "threat-findings-remove-public-sql"},
+	"cloud_sql_require_ssl":        {Topic: "threat-findings-require-ssl"},
+	"cloud_sql_update_password":    {Topic: "threat-findings-update-password"},
+	"disable_dashboard":            {Topic: "threat-findings-disable-dashboard"},
+	"delete_pod":                   {Topic: "threat-findings-delete-pod"},
+	"drain_node":                   {Topic: "threat-findings-drain-node"},
+	"enable_authorized_networks":   {Topic: "threat-findings-enable-authorized-networks"},
+	"remove_public_ip":             {Topic: "threat-findings-remove-public-ip"},
+	"remediate_firewall":           {Topic: "threat-findings-open-firewall"},
+	"close_public_dataset":         {Topic: "threat-findings-close-public-dataset"},
+	"block_project_ssh_keys":       {Topic: "threat-findings-block-project-ssh-keys"},
+	"enable_os_login":              {Topic: "threat-findings-enable-os-login"},
+	"disable_serial_port":          {Topic: "threat-findings-disable-serial-port"},
+	"enable_shielded_vm":           {Topic: "threat-findings-enable-shielded-vm"},
+	"delete_firewall_rules":        {Topic: "threat-findings-delete-firewall-rules"},
+	"enable_dnssec":                {Topic: "threat-findings-enable-dnssec"},
+	"enable_audit_logs":            {Topic: "threat-findings-enable-audit-logs"},
+	"enable_service_audit_logs":    {Topic: "threat-findings-enable-audit-logs"},
+	"enable_private_google_access": {Topic: "threat-findings-enable-private-access"},
+	"enable_flow_logs":             {Topic: "threat-findings-enable-flow-logs"},
+	"remove_public_invoker":        {Topic: "threat-findings-remove-public-invoker"},
+	"enforce_authentication":       {Topic: "threat-findings-enforce-authentication"},
+	"remove_non_org_members":       {Topic: "threat-findings-remove-non-org-members"},
+	"remove_default_editor":        {Topic: "threat-findings-iam-remove-default-editor"},
+	"disable_old_keys":             {Topic: "threat-findings-disable-old-keys"},
+	"restrict_api_key":             {Topic: "threat-findings-restrict-api-key"},
+	"remove_public_pubsub":         {Topic: "threat-findings-remove-public-pubsub"},
+	"remove_public_kms":            {Topic: "threat-findings-remove-public-kms"},
+	"remove_public_repository":     {Topic: "threat-findings-remove-public-repository"},
+	"remove_external_exposure":     {Topic: "threat-findings-remove-external-exposure"},
+	"disable_aws_access_key":       {Topic: "threat-findings-disable-aws-access-key"},
+	"isolate_ec2_instance":         {Topic: "threat-findings-isolate-ec2-instance"},
+	"remove_public_container":      {Topic: "threat-findings-remove-public-container-access"},
+}
+
+// registeredTopic is the PubSub topic of the automations registered with the automations package.
+const registeredTopic = "threat-findings-registered"
+
+// Topic returns the PubSub topic of the automation's action, false if there's no such action.
+func Topic(action string) (string, bool) {
+	t, ok := topics[action]
+	return t.Topic, ok
+}
+
+// Exemption skips remediating the findings matching all of its set fields.
+type Exemption struct {
+	// Project is the ID of the project of the finding.
+	Project string
+	// Resource is the resource name of the finding's asset, such as //storage.googleapis.com/bucket.
+	Resource string
+	// Category is the rule of the finding, such as non_org_iam_member, compared ignoring case.
+	Category string
+	// Expires is when the exemption expires, it doesn't if unset.
+	Expires time.Time
+}
+
+// Mapping routes the findings of a category to its automations instead of the automations
+// configured for the category under parameters.
+type Mapping struct {
+	// Category is the rule of the finding, such as OPEN_FIREWALL, compared ignoring case.
+	Category string
+	// Rule handles the findings as findings of a rule the router supports, such as open_firewall
+	// for a new firewall scanner category. Defaults to the category.
+	Rule string
+	// Filter restricts the findings of the category remediated, besides the filter of the spec.
+	Filter      services.FindingFilter
+	Automations []Automation
+}
+
+// Remediator is a service account remediations of its projects and folders run as, instead of
+// the service account of the automations.
+type Remediator struct {
+	// ServiceAccount is the email of the service account, such as
+	// remediator@folder-project.iam.gserviceaccount.com.
+	ServiceAccount string `yaml:"service_account"`
+	// Projects are the IDs of the projects remediated as the service account.
+	Projects []string
+	// FolderIDs are the folders remediated as the service account, including nested folders.
+	FolderIDs []string `yaml:"folder_ids"`
 }
 
 // Automation represents configuration for an automation.
 type Automation struct {
-	Action     string
-	Target     []string
-	Exclude    []string
+	Action  string
+	Target  []string
+	Exclude []string
+	// FolderIDs targets every project under these folders, including nested folders, besides the
+	// projects matching the target.
+	FolderIDs []string `yaml:"folder_ids"`
+	// Labels restricts the automation to resources with all of these labels, such as env: prod.
+	// A label without a value matches any value.
+	Labels map[string]string
+	// ExcludeLabels skips resources with any of these labels, such as managed-by: terraform.
+	ExcludeLabels map[string]string `yaml:"exclude_labels"`
+	// Notify are the notification channels told of the outcome, such as email or slack.
+	Notify     []string
 	Properties struct {
 		DryRun    bool `yaml:"dry_run"`
 		RevokeIAM struct {
 			AllowDomains []string `yaml:"allow_domains"`
 		} `yaml:"revoke_iam"`
+		RestoreIAMPolicy struct {
+			Bucket string
+		} `yaml:"restore_iam_policy"`
+		SuspendUser struct {
+			Enabled  bool
+			OrgUnits []string `yaml:"org_units"`
+		} `yaml:"suspend_user"`
+		EnforceReenrollment struct {
+			OrgUnits          []string `yaml:"org_units"`
+			EnrollmentOrgUnit string   `yaml:"enrollment_org_unit"`
+		} `yaml:"enforce_reenrollment"`
 		CreateSnapshot struct {
 			TargetSnapshotProjectID string `yaml:"target_snapshot_project_id"`
 			TargetSnapshotZone      string `yaml:"target_snapshot_zone"`
+			StopInstance            bool   `yaml:"stop_instance"`
 			Output                  []string
 			Turbinia                struct {
 				ProjectID string
@@ -112,13 +284,155 @@ type Automation struct {
 				Zone      string
 			}
 		} `yaml:"gce_create_snapshot"`
+		QuarantineInstance struct {
+			RemoveTags         bool   `yaml:"remove_tags"`
+			ApplyQuarantineTag bool   `yaml:"apply_quarantine_tag"`
+			QuarantineTag      string `yaml:"quarantine_tag"`
+			StopInstance       bool   `yaml:"stop_instance"`
+		} `yaml:"quarantine_instance"`
+		DisableBilling struct {
+			Threshold int
+			Window    time.Duration
+			Bucket    string
+		} `yaml:"disable_billing"`
+		QuarantineImage struct {
+			Tag             string
+			BlockDeployment bool `yaml:"block_deployment"`
+		} `yaml:"quarantine_image"`
+		StopRogueJob struct {
+			AllowProjects []string `yaml:"allow_projects"`
+		} `yaml:"stop_rogue_job"`
+		RotateSecrets struct {
+			LabelKey  string `yaml:"label_key"`
+			HookURL   string `yaml:"hook_url"`
+			HookTopic string `yaml:"hook_topic"`
+		} `yaml:"rotate_secrets"`
 		OpenFirewall struct {
 			SourceRanges      []string `yaml:"source_ranges"`
 			RemediationAction string   `yaml:"remediation_action"`
 		} `yaml:"open_firewall"`
+		UpdatePassword struct {
+			SecretProjectID string `yaml:"secret_project_id"`
+			SecretPrefix    string `yaml:"secret_prefix"`
+			Output          []string
+			SendGrid        struct {
+				APIKey string `yaml:"api_key"`
+				From   string
+				To     []string
+			}
+		} `yaml:"cloud_sql_update_password"`
+		CloseCloudSQL struct {
+			DisablePublicIP bool `yaml:"disable_public_ip"`
+		} `yaml:"close_cloud_sql"`
+		BlockSSH struct {
+			Priority int64
+			Expiry   time.Duration
+		} `yaml:"block_ssh"`
 		NonOrgMembers struct {
-			AllowDomains []string `yaml:"allow_domains"`
+			AllowDomains      []string `yaml:"allow_domains"`
+			UnconditionalOnly bool     `yaml:"unconditional_only"`
+			Escalation        struct {
+				Threshold   int
+				Window      time.Duration
+				Bucket      string
+				CustomerIDs []string `yaml:"customer_ids"`
+			}
+			Groups struct {
+				Remove      bool
+				Lookup      bool
+				CustomerIDs []string `yaml:"customer_ids"`
+			}
+			ServiceAccounts struct {
+				Remove        bool
+				AllowProjects []string `yaml:"allow_projects"`
+			} `yaml:"service_accounts"`
+			Directory struct {
+				Groups []string
+			}
 		} `yaml:"non_org_members"`
+		CloseBucket struct {
+			AllowBuckets []string `yaml:"allow_buckets"`
+		} `yaml:"close_bucket"`
+		EnableAuthorizedNetworks struct {
+			CIDRBlocks []string `yaml:"cidr_blocks"`
+		} `yaml:"enable_authorized_networks"`
+		RemovePublicIP struct {
+			Projects []string
+			Labels   map[string]string
+		} `yaml:"remove_public_ip"`
+		DeleteFirewallRules struct {
+			RemediationAction string `yaml:"remediation_action"`
+			Lookback          time.Duration
+		} `yaml:"delete_firewall_rules"`
+		BlockProjectSSHKeys struct {
+			DenyPattern string `yaml:"deny_pattern"`
+		} `yaml:"block_project_ssh_keys"`
+		EnableOSLogin struct {
+			Folders []string
+		} `yaml:"enable_os_login"`
+		EnableShieldedVM struct {
+			SecureBoot          bool `yaml:"secure_boot"`
+			VTPM                bool `yaml:"vtpm"`
+			IntegrityMonitoring bool `yaml:"integrity_monitoring"`
+		} `yaml:"enable_shielded_vm"`
+		EnableDNSSEC struct {
+			AllowZones []string `yaml:"allow_zones"`
+		} `yaml:"enable_dnssec"`
+		EnablePrivateGoogleAccess struct {
+			DenyExternalIP bool `yaml:"deny_external_ip"`
+		} `yaml:"enable_private_google_access"`
+		EnableServiceAuditLogs struct {
+			Services []string
+		} `yaml:"enable_service_audit_logs"`
+		EnableFlowLogs struct {
+			FlowSampling        float64 `yaml:"flow_sampling"`
+			AggregationInterval string  `yaml:"aggregation_interval"`
+		} `yaml:"enable_flow_logs"`
+		RemoveDefaultEditor struct {
+			Roles []string
+		} `yaml:"remove_default_editor"`
+		DisableOldKeys struct {
+			MaxAge   time.Duration `yaml:"max_age"`
+			Output   []string
+			SendGrid struct {
+				APIKey string `yaml:"api_key"`
+				From   string
+				To     []string
+			}
+		} `yaml:"disable_old_keys"`
+		RestrictAPIKey struct {
+			RemediationAction string   `yaml:"remediation_action"`
+			AllowedReferrers  []string `yaml:"allowed_referrers"`
+			AllowedIPs        []string `yaml:"allowed_ips"`
+			AllowedAPIs       []string `yaml:"allowed_apis"`
+		} `yaml:"restrict_api_key"`
+		RemovePublicPubSub struct {
+			AllowResources []string `yaml:"allow_resources"`
+		} `yaml:"remove_public_pubsub"`
+		RemovePublicKMS struct {
+			RotationPeriod time.Duration `yaml:"rotation_period"`
+		} `yaml:"remove_public_kms"`
+		RemoveExternalExposure struct {
+			RemediationAction string   `yaml:"remediation_action"`
+			AllowServices     []string `yaml:"allow_services"`
+		} `yaml:"remove_external_exposure"`
+		// AWS configures the automations remediating GuardDuty findings in AWS accounts.
+		AWS struct {
+			// CredentialsSecret is the Secret Manager secret version holding the AWS credentials
+			// as JSON, such as projects/automation-project/secrets/aws/versions/latest.
+			CredentialsSecret string `yaml:"credentials_secret"`
+		} `yaml:"aws"`
+		IsolateEC2Instance struct {
+			SecurityGroup string `yaml:"security_group"`
+		} `yaml:"isolate_ec2_instance"`
+		// Azure configures the automations remediating Defender for Cloud alerts in Azure
+		// subscriptions.
+		Azure struct {
+			// CredentialsSecret is the Secret Manager secret version holding the credentials of
+			// the Azure service principal as JSON, such as
+			// projects/automation-project/secrets/azure/versions/latest.
+			CredentialsSecret string `yaml:"credentials_secret"`
+		} `yaml:"azure"`
 	}
 }
 
@@ -126,46 +440,149 @@ type Automation struct {
 type Configuration struct {
 	APIVersion string
 	Spec       struct {
-		Name       string
+		Name string
+		// DryRun turns on dry run for every automation, regardless of their own dry_run property.
+		DryRun bool `yaml:"dry_run"`
+		// SCC controls how findings are updated in Security Command Center once remediated.
+		SCC struct {
+			// SetInactive sets remediated findings as inactive, besides marking them as
+			// remediated by automation.
+			SetInactive bool `yaml:"set_inactive"`
+		}
+		// Notifications configures the channels automations notify of their outcome.
+		Notifications services.NotificationConfig
+		// Tickets configures the trackers tickets are filed in for remediations needing follow-up.
+		Tickets services.TicketConfig
+		// Events configures the sinks remediation events are streamed to.
+		Events events.Config
+		// DeadLetters configures how failed remediations are replayed.
+		DeadLetters services.DeadLetterConfig `yaml:"dead_letters"`
+		// Exemptions skip remediating findings until they expire.
+		Exemptions []Exemption
+		// Falco configures the webhook Falco sends the alerts of GKE clusters to.
+		Falco struct {
+			// Token is the bearer token webhook requests must carry.
+			Token string
+		}
+		// Idempotency configures how long redelivered findings are skipped.
+		Idempotency services.IdempotencyConfig
+		// Filter restricts the findings remediated by severity, source or resource.
+		Filter services.FindingFilter
+		// Mappings route finding categories to automations, taking precedence over parameters.
+		Mappings []Mapping
+		// Schemas map the payloads of custom detectors to findings, which are routed by their
+		// category like any other finding.
+		Schemas []findings.Schema
+		// Retry configures how API calls failing with transient errors are retried.
+		Retry clients.RetryPolicy
+		// CircuitBreaker configures when APIs failing persistently stop being called.
+		CircuitBreaker services.BreakerConfig `yaml:"circuit_breaker"`
+		// Remediators are the service accounts impersonated to remediate their projects and
+		// folders. Remediators listing the project take precedence over those of its folders.
+		Remediators []Remediator
+		// MaintenanceWindows restrict actions to the windows they may run in. Outside of them,
+		// actions wait for manual approval instead.
+		MaintenanceWindows []services.MaintenanceWindow `yaml:"maintenance_windows"`
+		// Approval holds the actions that wait for manual approval before they run.
+		Approval struct {
+			Actions []string
+			// RequiredApprovers is the number of distinct approvers an action needs, keyed by
+			// action. Actions listed here wait for approval as well, others need one approver.
+			RequiredApprovers map[string]int `yaml:"required_approvers"`
+			// URL is the trigger URL of the ApproveRemediation Cloud Function.
+			URL string
+			// Key signs the approve and deny links.
+			Key string
+			// TTL is how long the action can be approved, defaults to a day.
+			TTL time.Duration
+			// Slack posts approval requests with approve and deny buttons to the channel of the
+			// Slack notifications, whose bot token must be set. Approvers are matched by the email
+			// of their Slack user.
+			Slack bool
+			// Teams posts approval requests to the Teams channel of the Teams notifications. The
+			// channel is only told about the request, approvers get their links by email.
+			Teams    bool
+			SendGrid struct {
+				APIKey string `yaml:"api_key"`
+				From   string
+				To     []string
+			}
+		}
 		Parameters struct {
 			ETD struct {
-				BadIP         []Automation `yaml:"bad_ip"`
-				AnomalousIAM  []Automation `yaml:"anomalous_iam"`
-				SSHBruteForce []Automation `yaml:"ssh_brute_force"`
+				BadIP                       []Automation `yaml:"bad_ip"`
+				AnomalousIAM                []Automation `yaml:"anomalous_iam"`
+				SSHBruteForce               []Automation `yaml:"ssh_brute_force"`
+				FirewallRuleCreated         []Automation `yaml:"firewall_rule_created"`
+				AccountCompromised          []Automation `yaml:"account_compromised"`
+				RogueJob                    []Automation `yaml:"rogue_job"`
+				ServiceAccountImpersonation []Automation `yaml:"service_account_impersonation"`
+			}
+			CTD struct {
+				AddedBinaryExecuted     []Automation `yaml:"added_binary_executed"`
+				AddedLibraryLoaded      []Automation `yaml:"added_library_loaded"`
+				ReverseShell            []Automation `yaml:"reverse_shell"`
+				MaliciousScriptExecuted []Automation `yaml:"malicious_script_executed"`
 			}
 			SHA struct {
-				PublicBucketACL         []Automation `yaml:"public_bucket_acl"`
-				BucketPolicyOnlyDisable []Automation `yaml:"bucket_policy_only_disabled"`
-				PublicSQLInstance       []Automation `yaml:"public_sql_instance"`
-				SSLNotEnforced          []Automation `yaml:"ssl_not_enforced"`
-				SQLNoRootPassword       []Automation `yaml:"sql_no_root_password"`
-				PublicIPAddress         []Automation `yaml:"public_ip_address"`
-				OpenFirewall            []Automation `yaml:"open_firewall"`
-				PublicDataset           []Automation `yaml:"bigquery_public_dataset"`
-				AuditLoggingDisabled    []Automation `yaml:"audit_logging_disabled"`
-				WebUIEnabled            []Automation `yaml:"web_ui_enabled"`
-				NonOrgMembers           []Automation `yaml:"non_org_members"`
+				PublicBucketACL                  []Automation `yaml:"public_bucket_acl"`
+				BucketPolicyOnlyDisable          []Automation `yaml:"bucket_policy_only_disabled"`
+				PublicSQLInstance                []Automation `yaml:"public_sql_instance"`
+				SSLNotEnforced                   []Automation `yaml:"ssl_not_enforced"`
+				SQLNoRootPassword                []Automation `yaml:"sql_no_root_password"`
+				PublicIPAddress                  []Automation `yaml:"public_ip_address"`
+				OpenFirewall                     []Automation `yaml:"open_firewall"`
+				OpenSSHPort                      []Automation `yaml:"open_ssh_port"`
+				OpenRDPPort                      []Automation `yaml:"open_rdp_port"`
+				PublicDataset                    []Automation `yaml:"bigquery_public_dataset"`
+				AuditLoggingDisabled             []Automation `yaml:"audit_logging_disabled"`
+				WebUIEnabled                     []Automation `yaml:"web_ui_enabled"`
+				MasterAuthorizedNetworksDisabled []Automation `yaml:"master_authorized_networks_disabled"`
+				NonOrgMembers                    []Automation `yaml:"non_org_members"`
+				PublicCloudFunction              []Automation `yaml:"public_cloud_function"`
+				PublicCloudRunService            []Automation `yaml:"public_cloud_run_service"`
+				ProjectWideSSHKeysAllowed        []Automation `yaml:"compute_project_wide_ssh_keys_allowed"`
+				OSLoginDisabled                  []Automation `yaml:"os_login_disabled"`
+				SerialPortsEnabled               []Automation `yaml:"compute_serial_ports_enabled"`
+				ShieldedVMDisabled               []Automation `yaml:"shielded_vm_disabled"`
+				DNSSECDisabled                   []Automation `yaml:"dnssec_disabled"`
+				PrivateGoogleAccessDisabled      []Automation `yaml:"private_google_access_disabled"`
+				FlowLogsDisabled                 []Automation `yaml:"flow_logs_disabled"`
+				NonLeastPrivilege                []Automation `yaml:"non_least_privilege"`
+				DefaultServiceAccountUsed        []Automation `yaml:"default_service_account_used"`
+				ServiceAccountKeyNotRotated      []Automation `yaml:"service_account_key_not_rotated"`
+				UserManagedServiceAccountKey     []Automation `yaml:"user_managed_service_account_key"`
+				APIKeyExists                     []Automation `yaml:"api_key_exists"`
+				APIKeyAPIsUnrestricted           []Automation `yaml:"api_key_apis_unrestricted"`
+				APIKeyAppsUnrestricted           []Automation `yaml:"api_key_apps_unrestricted"`
+				PublicPubSubTopic                []Automation `yaml:"public_pubsub_topic"`
+				PublicPubSubSubscription         []Automation `yaml:"public_pubsub_subscription"`
+				KMSPublicKey                     []Automation `yaml:"kms_public_key"`
+				PublicArtifactRegistryRepository []Automation `yaml:"public_artifact_registry_repository"`
+				PublicContainerRegistry          []Automation `yaml:"public_container_registry"`
+				UnintendedExternalExposure       []Automation `yaml:"unintended_external_exposure"`
 			}
+			GuardDuty struct {
+				AccessKey []Automation `yaml:"guardduty_access_key"`
+				Instance  []Automation `yaml:"guardduty_instance"`
+			} `yaml:"guardduty"`
+			Defender struct {
+				PublicContainer []Automation `yaml:"defender_public_container"`
+			} `yaml:"defender"`
+			Forseti struct {
+				BucketViolation    []Automation `yaml:"forseti_bucket_violation"`
+				IAMPolicyViolation []Automation `yaml:"forseti_iam_policy_violation"`
+			} `yaml:"forseti"`
+			// Falco maps the rule names of Falco alerts, such as
+			// falco_terminal_shell_in_container, to automations.
+			Falco map[string][]Automation `yaml:"falco"`
 		}
 	}
 }
 
-// Config will return the router's configuration.
-func Config() (*Configuration, error) {
-	var c Configuration
-	b, err := ioutil.ReadFile(configPath)
-	if err != nil {
-		return nil, err
-	}
-	if err := yaml.Unmarshal(b, &c); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal config.yaml")
-	}
-	return &c, nil
-}
-
 // ruleName will attempt to deserialize all findings until a name is extracted.
 func ruleName(b []byte) string {
-	for _, finding := range findings {
+	for _, finding := range providers {
 		if n := finding.Name(b); n != "" {
 			return n
 		}
@@ -174,6 +591,16 @@ func ruleName(b []byte) string {
 }
 
 func markAsRemediated(ctx context.Context, name, eventTime string, services *Services) error {
+	// Simulated findings aren't in Security Command Center.
+	if simulated, _ := ctx.Value(simulatedKey{}).(bool); simulated {
+		logging.FromContext(ctx).Info("not marking simulated finding %q as remediated", name)
+		return nil
+	}
+	// Neither are the findings of custom detectors.
+	if strings.HasPrefix(name, findings.SchemaParent) {
+		logging.FromContext(ctx).Info("not marking detection %q as remediated", name)
+		return nil
+	}
 	m := map[string]string{"sra-remediated-event-time": eventTime}
 	if _, err := services.SecurityCommandCenter.AddSecurityMarks(ctx, name, m); err != nil {
 		return err
@@ -181,15 +608,497 @@ func markAsRemediated(ctx context.Context, name, eventTime string, services *Ser
 	return nil
 }
 
-// Execute will route the incoming finding to the appropriate remediations.
-func Execute(ctx context.Context, values *Values, services *Services) error {
-	switch name := ruleName(values.Finding); name {
+type findingIDKey struct{}
+
+// eventTimeKey holds the event time of the finding, which with its ID identifies a delivery.
+type eventTimeKey struct{}
+
+type reporterKey struct{}
+
+type severityKey struct{}
+
+type categoryKey struct{}
+
+type mappingKey struct{}
+
+// serviceAccountKey holds the remediator service account the automation runs as.
+type serviceAccountKey struct{}
+
+// simulatedKey holds whether the finding was fabricated to rehearse the response to it.
+type simulatedKey struct{}
+
+// resourceKey holds the resource of the finding whose labels automations select.
+type resourceKey struct{}
+
+// labelled is a resource whose labels are looked up within the scope.
+type labelled struct {
+	name, scope string
+}
+
+// mapSchema returns the finding the payload of a custom detector maps to with the first schema
+// matching it. Payloads the providers support are returned as is, as are those no schema matches.
+func mapSchema(conf *Configuration, b []byte) ([]byte, error) {
+	if len(conf.Spec.Schemas) == 0 || ruleName(findings.Normalize(b)) != "" {
+		return b, nil
+	}
+	for i := range conf.Spec.Schemas {
+		if s := &conf.Spec.Schemas[i]; s.Matches(b) {
+			b, err := s.Notification(b)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to map payload with schema %q", s.Name)
+			}
+			return b, nil
+		}
+	}
+	return b, nil
+}
+
+// mapping returns the mapping of the finding category, nil if it has none.
+func mapping(conf *Configuration, name string) *Mapping {
+	for i, m := range conf.Spec.Mappings {
+		if strings.EqualFold(m.Category, name) {
+			return &conf.Spec.Mappings[i]
+		}
+	}
+	return nil
+}
+
+// mapped returns the automations the category of the finding is mapped to, or the automations
+// configured for it under parameters if it isn't mapped.
+func mapped(ctx context.Context, automations []Automation) []Automation {
+	if m, ok := ctx.Value(mappingKey{}).(*Mapping); ok {
+		return m.Automations
+	}
+	return automations
+}
+
+// findingID returns the name of a Security Command Center finding, the insert ID of a
+// Stackdriver log finding, the ID of an EventBridge event or the resource ID of a Defender for
+// Cloud alert.
+func findingID(b []byte) string {
+	var f struct {
+		Finding struct {
+			Name string `json:"name"`
+		} `json:"finding"`
+		InsertID string `json:"insertId"`
+		ID       string `json:"id"`
+	}
+	if err := json.Unmarshal(b, &f); err != nil {
+		return ""
+	}
+	switch {
+	case f.Finding.Name != "":
+		return f.Finding.Name
+	case f.InsertID != "":
+		return f.InsertID
+	}
+	return f.ID
+}
+
+// findingEventTime returns the event time of a Security Command Center finding, the timestamp
+// of a Stackdriver log finding, the time of an EventBridge event or the time a Defender for Cloud
+// alert was generated.
+func findingEventTime(b []byte) string {
+	var f struct {
+		Finding struct {
+			EventTime string `json:"eventTime"`
+		} `json:"finding"`
+		Timestamp  string `json:"timestamp"`
+		Time       string `json:"time"`
+		Properties struct {
+			TimeGeneratedUTC string `json:"timeGeneratedUtc"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(b, &f); err != nil {
+		return ""
+	}
+	switch {
+	case f.Finding.EventTime != "":
+		return f.Finding.EventTime
+	case f.Timestamp != "":
+		return f.Timestamp
+	case f.Time != "":
+		return f.Time
+	}
+	return f.Properties.TimeGeneratedUTC
+}
+
+// claim returns the idempotency key of the finding and whether it was claimed, false if the
+// finding was routed before.
+func claim(ctx context.Context, svcs *Services, b []byte) (string, bool, error) {
+	if svcs.Idempotency == nil {
+		return "", true, nil
+	}
+	key := services.IdempotencyKey("Router", findingID(b), findingEventTime(b))
+	ok, err := svcs.Idempotency.Claim(ctx, key, time.Now())
+	if err != nil {
+		return "", false, err
+	}
+	return key, ok, nil
+}
+
+// release releases the idempotency key of a finding that failed to route, so its redelivery is
+// routed again.
+func release(ctx context.Context, svcs *Services, key string) {
+	if svcs.Idempotency == nil {
+		return
+	}
+	if err := svcs.Idempotency.Release(ctx, key); err != nil {
+		logging.FromContext(ctx).Error("failed to release idempotency key %q: %q", key, err)
+	}
+}
+
+// findingSimulated returns whether the finding has the security mark of simulated findings.
+func findingSimulated(b []byte) bool {
+	var f struct {
+		Finding struct {
+			SecurityMarks struct {
+				Marks map[string]string `json:"marks"`
+			} `json:"securityMarks"`
+		} `json:"finding"`
+	}
+	if err := json.Unmarshal(b, &f); err != nil {
+		return false
+	}
+	return f.Finding.SecurityMarks.Marks[simulate.SimulatedMark] == "true"
+}
+
+// findingSeverity returns the severity of a Security Command Center finding, such as "HIGH".
+func findingSeverity(b []byte) string {
+	var f struct {
+		Finding struct {
+			Severity string `json:"severity"`
+		} `json:"finding"`
+	}
+	if err := json.Unmarshal(b, &f); err != nil {
+		return ""
+	}
+	return f.Finding.Severity
+}
+
+// findingResource returns the resource name of the asset of a Security Command Center finding.
+func findingResource(b []byte) string {
+	var f struct {
+		Finding struct {
+			ResourceName string `json:"resourceName"`
+		} `json:"finding"`
+	}
+	if err := json.Unmarshal(b, &f); err != nil {
+		return ""
+	}
+	return f.Finding.ResourceName
+}
+
+// FindingProject returns the project ID of the finding, empty if it has none such as the payloads
+// schemas map.
+func FindingProject(b []byte) string {
+	return findingProject(findings.Normalize(b))
+}
+
+// findingProject returns the project ID of a Security Command Center finding, falling back to the
+// resource block of v1 notifications, or of a Stackdriver log finding.
+func findingProject(b []byte) string {
+	var f struct {
+		Finding struct {
+			SourceProperties struct {
+				ProjectID string `json:"ProjectId"`
+			} `json:"sourceProperties"`
+		} `json:"finding"`
+		Resource struct {
+			Labels struct {
+				ProjectID string `json:"project_id"`
+			} `json:"labels"`
+			ProjectDisplayName string `json:"projectDisplayName"`
+		} `json:"resource"`
+	}
+	if err := json.Unmarshal(b, &f); err != nil {
+		return ""
+	}
+	switch {
+	case f.Finding.SourceProperties.ProjectID != "":
+		return f.Finding.SourceProperties.ProjectID
+	case f.Resource.Labels.ProjectID != "":
+		return f.Resource.Labels.ProjectID
+	}
+	return f.Resource.ProjectDisplayName
+}
+
+// matches returns whether the finding of the rule matches all fields set on the exemption.
+func (e *Exemption) matches(name string, b []byte) bool {
+	if e.Project == "" && e.Resource == "" && e.Category == "" {
+		return false
+	}
+	return (e.Project == "" || e.Project == findingProject(b)) &&
+		(e.Resource == "" || e.Resource == findingResource(b)) &&
+		(e.Category == "" || strings.EqualFold(e.Category, name))
+}
+
+// filtered returns why the finding doesn't match the filter of the spec or of its mapping, or an
+// empty string if it matches both.
+func filtered(conf *Configuration, m *Mapping, b []byte) (string, error) {
+	filters := []services.FindingFilter{conf.Spec.Filter}
+	if m != nil {
+		filters = append(filters, m.Filter)
+	}
+	f := &services.FilteredFinding{Name: findingID(b), Severity: findingSeverity(b), ResourceName: findingResource(b)}
+	for _, filter := range filters {
+		ok, reason, err := filter.Match(f)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return "filtered out: " + reason, nil
+		}
+	}
+	return "", nil
+}
+
+// labelScope returns the project of the finding if known, otherwise its organization, which the
+// labels of its resource are looked up in.
+func labelScope(b []byte) string {
+	if p := findingProject(b); p != "" {
+		return "projects/" + p
+	}
+	id := findingID(b)
+	if strings.HasPrefix(id, "organizations/") {
+		return strings.Join(strings.SplitN(id, "/", 3)[:2], "/")
+	}
+	return ""
+}
+
+// matchesLabels returns whether the resource of the finding has the labels the automation selects.
+func matchesLabels(ctx context.Context, svcs *Services, automation Automation) (bool, error) {
+	if len(automation.Labels) == 0 && len(automation.ExcludeLabels) == 0 {
+		return true, nil
+	}
+	r, _ := ctx.Value(resourceKey{}).(labelled)
+	if r.name == "" || r.scope == "" {
+		return false, fmt.Errorf("action %q selects labels but the finding has no resource", automation.Action)
+	}
+	if svcs.Assets == nil {
+		return false, fmt.Errorf("action %q selects labels but assets are not configured", automation.Action)
+	}
+	labels, err := svcs.Assets.Labels(ctx, r.scope, r.name)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get the labels of %q", r.name)
+	}
+	return services.MatchesLabels(labels, automation.Labels, automation.ExcludeLabels), nil
+}
+
+// exemption is why a finding isn't remediated.
+type exemption struct {
+	reason string
+	// asset is the exemption mark, nil for exemptions in the configuration.
+	asset   *services.AssetExemption
+	expires time.Time
+}
+
+// exempted returns the exemption of the finding that hasn't expired by now, if any. Exemptions are
+// set in the configuration or as security marks on the asset of Security Command Center findings.
+func exempted(ctx context.Context, svcs *Services, name string, b []byte, now time.Time) (*exemption, error) {
+	for _, e := range svcs.Configuration.Spec.Exemptions {
+		if !e.Expires.IsZero() && !now.Before(e.Expires) {
+			continue
+		}
+		if e.matches(name, b) {
+			reason := fmt.Sprintf("configured exemption of project %q, resource %q and category %q", e.Project, e.Resource, e.Category)
+			return &exemption{reason: reason, expires: e.Expires}, nil
+		}
+	}
+	id, resource := findingID(b), findingResource(b)
+	if !strings.Contains(id, "/findings/") || resource == "" {
+		return nil, nil
+	}
+	a, err := svcs.SecurityCommandCenter.Exemption(ctx, id, resource, now)
+	if err != nil || a == nil {
+		return nil, err
+	}
+	reason := fmt.Sprintf("asset %q has exemption mark %q", resource, a.Mark)
+	return &exemption{reason: reason, asset: a, expires: a.Expires}, nil
+}
+
+// skipExempted records that the finding was skipped because it is exempted. Security Command
+// Center findings are kept until their exemption expires, so they can be remediated then.
+func skipExempted(ctx context.Context, svcs *Services, b []byte, e *exemption) error {
+	id := findingID(b)
+	logging.FromContext(ctx).Info("skipping finding %q: %s", id, e.reason)
+	if svcs.Exemptions != nil && !e.expires.IsZero() && strings.Contains(id, "/findings/") {
+		s := &services.Suppressed{FindingName: id, Finding: b, Expires: e.expires}
+		if e.asset != nil {
+			s.Asset, s.Mark = e.asset.Asset, e.asset.Mark
+		}
+		if err := svcs.Exemptions.Suppress(ctx, s); err != nil {
+			return err
+		}
+	}
+	return recordSkipped(ctx, svcs, b, e.reason)
+}
+
+// recordSkipped records the skipped finding in the audit trail and files a ticket for it, if
+// configured.
+func recordSkipped(ctx context.Context, svcs *Services, b []byte, reason string) error {
+	r := &services.AuditRecord{
+		ID:        uuid.New().String(),
+		Time:      time.Now(),
+		FindingID: findingID(b),
+		Severity:  findingSeverity(b),
+		Category:  ruleName(b),
+		Action:    "Router",
+		Values:    string(b),
+		Outcome:   services.AuditSkipped,
+		Reason:    reason,
+	}
+	if svcs.Tickets != nil {
+		if err := svcs.Tickets.File(ctx, r); err != nil {
+			logging.FromContext(ctx).Error("failed to file ticket for %q: %q", r.FindingID, err)
+		}
+	}
+	if svcs.Events != nil {
+		if err := svcs.Events.Send(ctx, events.New(r, r.Time)); err != nil {
+			logging.FromContext(ctx).Error("failed to send event for %q: %q", r.FindingID, err)
+		}
+	}
+	if svcs.Metrics != nil {
+		svcs.Metrics.Outcome(r, metrics.ParseEventTime(findingEventTime(b)))
+	}
+	if svcs.Audit == nil {
+		return nil
+	}
+	return svcs.Audit.Record(ctx, r)
+}
+
+// findingReporter returns the principal identified by the finding, if any.
+func findingReporter(b []byte) string {
+	var f struct {
+		Finding struct {
+			Access struct {
+				PrincipalEmail string `json:"principalEmail"`
+			} `json:"access"`
+			SourceProperties struct {
+				Properties struct {
+					PrincipalEmail string `json:"principalEmail"`
+				} `json:"properties"`
+			} `json:"sourceProperties"`
+		} `json:"finding"`
+		JSONPayload struct {
+			Properties struct {
+				PrincipalEmail string `json:"principalEmail"`
+			} `json:"properties"`
+		} `json:"jsonPayload"`
+	}
+	if err := json.Unmarshal(b, &f); err != nil {
+		return ""
+	}
+	switch {
+	case f.Finding.Access.PrincipalEmail != "":
+		return f.Finding.Access.PrincipalEmail
+	case f.Finding.SourceProperties.Properties.PrincipalEmail != "":
+		return f.Finding.SourceProperties.Properties.PrincipalEmail
+	}
+	return f.JSONPayload.Properties.PrincipalEmail
+}
+
+// Execute will route the incoming finding to the appropriate remediations. Findings redelivered
+// by Pub/Sub are only routed again if routing them failed. Routing is traced as the root span of
+// the remediations, which continue the trace from the attributes of their messages. Notifications
+// of the Security Command Center v1 and v1p1beta1 APIs are normalized before being routed.
+func Execute(ctx context.Context, values *Values, services *Services) (err error) {
+	if values.Finding, err = mapSchema(services.Configuration, values.Finding); err != nil {
+		return err
+	}
+	values.Finding = findings.Normalize(values.Finding)
+	ctx = logging.WithFinding(ctx, findingID(values.Finding))
+	ctx = logging.WithProject(ctx, findingProject(values.Finding))
+	ctx, span := tracing.Start(ctx, "Router",
+		attribute.String("finding_id", findingID(values.Finding)),
+		attribute.String("category", ruleName(values.Finding)),
+	)
+	defer func() { tracing.End(span, err) }()
+	key, ok, err := claim(ctx, services, values.Finding)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		logging.FromContext(ctx).Info("skipping finding %q, it was routed before", findingID(values.Finding))
+		return nil
+	}
+	if services.Metrics != nil {
+		services.Metrics.Received(ruleName(values.Finding), findingSeverity(values.Finding))
+	}
+	if err := execute(ctx, values, services); err != nil {
+		release(ctx, services, key)
+		return err
+	}
+	return nil
+}
+
+func execute(ctx context.Context, values *Values, services *Services) error {
+	ctx = context.WithValue(ctx, findingIDKey{}, findingID(values.Finding))
+	ctx = context.WithValue(ctx, eventTimeKey{}, findingEventTime(values.Finding))
+	ctx = context.WithValue(ctx, reporterKey{}, findingReporter(values.Finding))
+	ctx = context.WithValue(ctx, severityKey{}, findingSeverity(values.Finding))
+	ctx = context.WithValue(ctx, simulatedKey{}, findingSimulated(values.Finding))
+	name := ruleName(values.Finding)
+	ctx = context.WithValue(ctx, categoryKey{}, name)
+	ctx = context.WithValue(ctx, resourceKey{}, labelled{name: findingResource(values.Finding), scope: labelScope(values.Finding)})
+	// Findings whose exemptions can't be read aren't remediated, they are redelivered instead.
+	e, err := exempted(ctx, services, name, values.Finding, time.Now())
+	if err != nil {
+		return errors.Wrap(err, "failed to read exemption marks")
+	}
+	if e != nil {
+		return skipExempted(ctx, services, values.Finding, e)
+	}
+	m := mapping(services.Configuration, name)
+	if m != nil {
+		ctx = context.WithValue(ctx, mappingKey{}, m)
+		if m.Rule != "" {
+			name = strings.ToLower(m.Rule)
+		}
+	}
+	reason, err := filtered(services.Configuration, m, values.Finding)
+	if err != nil {
+		return err
+	}
+	if reason != "" {
+		logging.FromContext(ctx).Info("skipping finding %q: %s", findingID(values.Finding), reason)
+		return recordSkipped(ctx, services, values.Finding, reason)
+	}
+	err = route(ctx, name, values, services)
+	if _, ok := err.(unsupportedError); ok {
+		if skipErr := recordSkipped(ctx, services, values.Finding, err.Error()); skipErr != nil {
+			logging.FromContext(ctx).Error("failed to record unsupported finding: %q", skipErr)
+		}
+	}
+	return err
+}
+
+// unsupportedError is returned for findings no automation supports.
+type unsupportedError struct {
+	error
+}
+
+func unsupported(format string, a ...interface{}) error {
+	return unsupportedError{fmt.Errorf(format, a...)}
+}
+
+// route sends the finding to the automations configured for its rule.
+func route(ctx context.Context, name string, values *Values, services *Services) error {
+	switch name {
 	case "bad_ip":
 		return executeBadIP(ctx, name, values, services)
 	case "iam_anomalous_grant":
 		return executeIamAnomalousGrant(ctx, name, values, services)
 	case "ssh_brute_force":
 		return executeSSHBruteForce(ctx, name, values, services)
+	case "firewall_rule_created":
+		return executeFirewallRuleCreated(ctx, name, values, services)
+	case "account_compromised":
+		return executeAccountCompromised(ctx, name, values, services)
+	case "rogue_job":
+		return executeRogueJob(ctx, name, values, services)
+	case "service_account_impersonation":
+		return executeServiceAccountImpersonation(ctx, name, values, services)
 	case "public_bucket_acl":
 		return executePublicBucketACL(ctx, name, values, services)
 	case "bucket_policy_only_disabled":
@@ -202,6 +1111,14 @@ func Execute(ctx context.Context, values *Values, services *Services) error {
 		return executeSQLNoRootPassword(ctx, name, values, services)
 	case "public_ip_address":
 		return executePublicIPAddress(ctx, name, values, services)
+	case "compute_project_wide_ssh_keys_allowed":
+		return executeProjectWideSSHKeysAllowed(ctx, name, values, services)
+	case "os_login_disabled":
+		return executeOSLoginDisabled(ctx, name, values, services)
+	case "compute_serial_ports_enabled":
+		return executeSerialPortsEnabled(ctx, name, values, services)
+	case "shielded_vm_disabled":
+		return executeShieldedVMDisabled(ctx, name, values, services)
 	case "open_firewall":
 		return executeOpenFirewall(ctx, name, values, services)
 	case "open_ssh_port":
@@ -214,50 +1131,152 @@ func Execute(ctx context.Context, values *Values, services *Services) error {
 		return executeAuditLoggingDisabled(ctx, name, values, services)
 	case "web_ui_enabled":
 		return executeWebUIEnabled(ctx, name, values, services)
+	case "master_authorized_networks_disabled":
+		return executeMasterAuthorizedNetworksDisabled(ctx, name, values, services)
 	case "non_org_iam_member":
 		return executeNonOrgIamMember(ctx, name, values, services)
+	case "non_least_privilege":
+		return executeNonLeastPrivilege(ctx, name, values, services)
+	case "default_service_account_used":
+		return executeDefaultServiceAccountUsed(ctx, name, values, services)
+	case "service_account_key_not_rotated":
+		return executeServiceAccountKey(ctx, name, mapped(ctx, services.Configuration.Spec.Parameters.SHA.ServiceAccountKeyNotRotated), values, services)
+	case "user_managed_service_account_key":
+		return executeServiceAccountKey(ctx, name, mapped(ctx, services.Configuration.Spec.Parameters.SHA.UserManagedServiceAccountKey), values, services)
+	case "public_cloud_function":
+		return executePublicServerless(ctx, name, mapped(ctx, services.Configuration.Spec.Parameters.SHA.PublicCloudFunction), values, services)
+	case "public_cloud_run_service":
+		return executePublicServerless(ctx, name, mapped(ctx, services.Configuration.Spec.Parameters.SHA.PublicCloudRunService), values, services)
+	case "unintended_external_exposure":
+		return executeUnintendedExternalExposure(ctx, name, values, services)
+	case "private_google_access_disabled":
+		return executePrivateGoogleAccessDisabled(ctx, name, values, services)
+	case "flow_logs_disabled":
+		return executeFlowLogsDisabled(ctx, name, values, services)
+	case "dnssec_disabled":
+		return executeDNSSECDisabled(ctx, name, values, services)
+	case "public_pubsub_topic":
+		return executePublicPubSub(ctx, name, mapped(ctx, services.Configuration.Spec.Parameters.SHA.PublicPubSubTopic), values, services)
+	case "public_pubsub_subscription":
+		return executePublicPubSub(ctx, name, mapped(ctx, services.Configuration.Spec.Parameters.SHA.PublicPubSubSubscription), values, services)
+	case "kms_public_key":
+		return executeKMSPublicKey(ctx, name, values, services)
+	case "public_artifact_registry_repository":
+		return executePublicRepository(ctx, name, mapped(ctx, services.Configuration.Spec.Parameters.SHA.PublicArtifactRegistryRepository), values, services)
+	case "public_container_registry":
+		return executePublicRepository(ctx, name, mapped(ctx, services.Configuration.Spec.Parameters.SHA.PublicContainerRegistry), values, services)
+	case "api_key_exists":
+		return executeAPIKey(ctx, name, mapped(ctx, services.Configuration.Spec.Parameters.SHA.APIKeyExists), values, services)
+	case "api_key_apis_unrestricted":
+		return executeAPIKey(ctx, name, mapped(ctx, services.Configuration.Spec.Parameters.SHA.APIKeyAPIsUnrestricted), values, services)
+	case "api_key_apps_unrestricted":
+		return executeAPIKey(ctx, name, mapped(ctx, services.Configuration.Spec.Parameters.SHA.APIKeyAppsUnrestricted), values, services)
+	case "added_binary_executed":
+		return executeContainerThreat(ctx, name, mapped(ctx, services.Configuration.Spec.Parameters.CTD.AddedBinaryExecuted), values, services)
+	case "added_library_loaded":
+		return executeContainerThreat(ctx, name, mapped(ctx, services.Configuration.Spec.Parameters.CTD.AddedLibraryLoaded), values, services)
+	case "reverse_shell":
+		return executeContainerThreat(ctx, name, mapped(ctx, services.Configuration.Spec.Parameters.CTD.ReverseShell), values, services)
+	case "malicious_script_executed":
+		return executeContainerThreat(ctx, name, mapped(ctx, services.Configuration.Spec.Parameters.CTD.MaliciousScriptExecuted), values, services)
+	case "guardduty_access_key":
+		return executeGuardDuty(ctx, name, mapped(ctx, services.Configuration.Spec.Parameters.GuardDuty.AccessKey), values, services)
+	case "guardduty_instance":
+		return executeGuardDuty(ctx, name, mapped(ctx, services.Configuration.Spec.Parameters.GuardDuty.Instance), values, services)
+	case "defender_public_container":
+		return executeDefender(ctx, name, mapped(ctx, services.Configuration.Spec.Parameters.Defender.PublicContainer), values, services)
+	case "forseti_bucket_violation":
+		return executeForseti(ctx, name, mapped(ctx, services.Configuration.Spec.Parameters.Forseti.BucketViolation), values, services)
+	case "forseti_iam_policy_violation":
+		return executeForseti(ctx, name, mapped(ctx, services.Configuration.Spec.Parameters.Forseti.IAMPolicyViolation), values, services)
 	default:
-		return fmt.Errorf("rule %q not found", name)
+		// Falco rules are user defined, so their rule names are only known from configuration.
+		if automations, ok := services.Configuration.Spec.Parameters.Falco[name]; ok {
+			return executeFalco(ctx, name, mapped(ctx, automations), values, services)
+		}
+		// Rules no built-in automation supports may be mapped to registered automations.
+		if m, ok := ctx.Value(mappingKey{}).(*Mapping); ok {
+			return executeRegistered(ctx, name, m.Automations, values, services)
+		}
+		return unsupported("rule %q not found", name)
 	}
 }
 
 func executeBadIP(ctx context.Context, name string, values *Values, services *Services) error {
-	automations := services.Configuration.Spec.Parameters.ETD.BadIP
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.ETD.BadIP)
 	badIP, err := badip.New(values.Finding)
 	if err != nil {
 		return err
 	}
-	if badIP.UseCSCC {
-		securityMarks := badIP.BadIPCSCC.GetFinding().GetSecurityMarks().GetMarks()
-		remediated := securityMarks[originalEventTime] == badIP.BadIPCSCC.GetFinding().GetEventTime()
-		if remediated {
-			log.Printf("finding already remediated")
-			return nil
-		}
+	if badIP.UseCSCC && badIP.SecurityMarks()[originalEventTime] == badIP.EventTime() {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
 	}
-	log.Printf("got rule %q with %d automations", name, len(automations))
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
 	for _, automation := range automations {
 		switch automation.Action {
+		case "disable_billing":
+			values := badIP.DisableBilling()
+			values.DryRun = dryRun(services, automation)
+			ok, err := sustained(ctx, services, automation, values.ProjectID)
+			if err != nil {
+				logging.FromContext(ctx).Error("failed to count findings: %q", err)
+				continue
+			}
+			if !ok {
+				continue
+			}
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
 		case "gce_create_disk_snapshot":
 			values := badIP.CreateSnapshot()
-			values.DryRun = automation.Properties.DryRun
+			values.DryRun = dryRun(services, automation)
 			values.Output = automation.Properties.CreateSnapshot.Output
 			values.DestProjectID = automation.Properties.CreateSnapshot.TargetSnapshotProjectID
 			values.DestZone = automation.Properties.CreateSnapshot.TargetSnapshotZone
+			values.StopInstance = automation.Properties.CreateSnapshot.StopInstance
 			values.Turbinia.ProjectID = automation.Properties.CreateSnapshot.Turbinia.ProjectID
 			values.Turbinia.Topic = automation.Properties.CreateSnapshot.Turbinia.Topic
 			values.Turbinia.Zone = automation.Properties.CreateSnapshot.Turbinia.Zone
 			topic := topics[automation.Action].Topic
-			if err := publish(ctx, services, automation.Action, topic, values.ProjectID, automation.Target, automation.Exclude, values); err != nil {
-				services.Logger.Error("failed to publish: %q", err)
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		case "quarantine_instance":
+			values := badIP.QuarantineInstance()
+			values.DryRun = dryRun(services, automation)
+			values.RemoveTags = automation.Properties.QuarantineInstance.RemoveTags
+			values.ApplyQuarantineTag = automation.Properties.QuarantineInstance.ApplyQuarantineTag
+			values.QuarantineTag = automation.Properties.QuarantineInstance.QuarantineTag
+			values.StopInstance = automation.Properties.QuarantineInstance.StopInstance
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		case "rotate_secrets":
+			values := badIP.RotateSecrets()
+			values.DryRun = dryRun(services, automation)
+			values.LabelKey = automation.Properties.RotateSecrets.LabelKey
+			values.HookURL = automation.Properties.RotateSecrets.HookURL
+			values.HookTopic = automation.Properties.RotateSecrets.HookTopic
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
 				continue
 			}
 		default:
-			return fmt.Errorf("action %q not found", automation.Action)
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
 		}
 	}
 	if badIP.UseCSCC {
-		if err := markAsRemediated(ctx, badIP.BadIPCSCC.GetFinding().GetName(), badIP.BadIPCSCC.GetFinding().GetEventTime(), services); err != nil {
+		if err := markAsRemediated(ctx, badIP.FindingName(), badIP.EventTime(), services); err != nil {
 			return err
 		}
 	}
@@ -265,513 +1284,1929 @@ func executeBadIP(ctx context.Context, name string, values *Values, services *Se
 }
 
 func executeIamAnomalousGrant(ctx context.Context, name string, values *Values, services *Services) error {
-	automations := services.Configuration.Spec.Parameters.ETD.AnomalousIAM
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.ETD.AnomalousIAM)
 	anomalousIAM, err := anomalousiam.New(values.Finding)
 	if err != nil {
 		return err
 	}
-	log.Printf("got rule %q with %d automations", name, len(automations))
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
 	for _, automation := range automations {
 		switch automation.Action {
 		case "iam_revoke":
 			values := anomalousIAM.IAMRevoke()
-			values.DryRun = automation.Properties.DryRun
+			values.DryRun = dryRun(services, automation)
+			values.AllowDomains = automation.Properties.RevokeIAM.AllowDomains
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		case "iam_revoke_grants":
+			values := anomalousIAM.IAMRevokeGrants()
+			values.DryRun = dryRun(services, automation)
 			values.AllowDomains = automation.Properties.RevokeIAM.AllowDomains
 			topic := topics[automation.Action].Topic
-			if err := publish(ctx, services, automation.Action, topic, values.ProjectID, automation.Target, automation.Exclude, values); err != nil {
-				services.Logger.Error("failed to publish: %q", err)
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		case "restore_iam_policy":
+			values := anomalousIAM.RestoreIAMPolicy()
+			values.DryRun = dryRun(services, automation)
+			values.Bucket = automation.Properties.RestoreIAMPolicy.Bucket
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
 				continue
 			}
 		default:
-			return fmt.Errorf("action %q not found", automation.Action)
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
 func executeSSHBruteForce(ctx context.Context, name string, values *Values, services *Services) error {
-	automations := services.Configuration.Spec.Parameters.ETD.SSHBruteForce
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.ETD.SSHBruteForce)
 	sshBruteForce, err := sshbruteforce.New(values.Finding)
 	if err != nil {
 		return err
 	}
-	log.Printf("got rule %q with %d automations", name, len(automations))
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
 	for _, automation := range automations {
 		switch automation.Action {
 		case "remediate_firewall":
 			values := sshBruteForce.OpenFirewall()
-			values.DryRun = automation.Properties.DryRun
+			values.DryRun = dryRun(services, automation)
 			values.Action = "block_ssh"
+			values.Priority = automation.Properties.BlockSSH.Priority
+			values.Expiry = automation.Properties.BlockSSH.Expiry
 			topic := topics[automation.Action].Topic
-			if err := publish(ctx, services, automation.Action, topic, values.ProjectID, automation.Target, automation.Exclude, values); err != nil {
-				services.Logger.Error("failed to publish: %q", err)
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
 				continue
 			}
 		default:
-			return fmt.Errorf("action %q not found", automation.Action)
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
 func executePublicBucketACL(ctx context.Context, name string, values *Values, services *Services) error {
-	automations := services.Configuration.Spec.Parameters.SHA.PublicBucketACL
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.PublicBucketACL)
 	storageScanner, err := storagescanner.New(values.Finding)
 	if err != nil {
 		return err
 	}
-	securityMarks := storageScanner.StorageScanner.GetFinding().GetSecurityMarks().GetMarks()
-	remediated := securityMarks[originalEventTime] == storageScanner.StorageScanner.GetFinding().GetEventTime()
+	securityMarks := storageScanner.SecurityMarks()
+	remediated := securityMarks[originalEventTime] == storageScanner.EventTime()
 	if remediated {
-		log.Printf("finding already remediated")
+		logging.FromContext(ctx).Info("finding already remediated")
 		return nil
 	}
-	log.Printf("got rule %q with %d automations", name, len(automations))
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
 	for _, automation := range automations {
 		switch automation.Action {
 		case "close_bucket":
 			values := storageScanner.CloseBucket()
-			values.DryRun = automation.Properties.DryRun
+			values.DryRun = dryRun(services, automation)
+			values.AllowBuckets = automation.Properties.CloseBucket.AllowBuckets
 			topic := topics[automation.Action].Topic
-			if err := publish(ctx, services, automation.Action, topic, values.ProjectID, automation.Target, automation.Exclude, values); err != nil {
-				services.Logger.Error("failed to publish: %q", err)
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
 				continue
 			}
 		default:
-			return fmt.Errorf("action %q not found", automation.Action)
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
 		}
 	}
-	if err := markAsRemediated(ctx, storageScanner.StorageScanner.GetFinding().GetName(), storageScanner.StorageScanner.GetFinding().GetEventTime(), services); err != nil {
+	if err := markAsRemediated(ctx, storageScanner.FindingName(), storageScanner.EventTime(), services); err != nil {
 		return err
 	}
 	return nil
 }
 
 func executeBucketPolicyOnlyDisabled(ctx context.Context, name string, values *Values, services *Services) error {
-	automations := services.Configuration.Spec.Parameters.SHA.BucketPolicyOnlyDisable
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.BucketPolicyOnlyDisable)
 	storageScanner, err := storagescanner.New(values.Finding)
 	if err != nil {
 		return err
 	}
-	securityMarks := storageScanner.StorageScanner.GetFinding().GetSecurityMarks().GetMarks()
-	remediated := securityMarks[originalEventTime] == storageScanner.StorageScanner.GetFinding().GetEventTime()
+	securityMarks := storageScanner.SecurityMarks()
+	remediated := securityMarks[originalEventTime] == storageScanner.EventTime()
 	if remediated {
-		log.Printf("finding already remediated")
+		logging.FromContext(ctx).Info("finding already remediated")
 		return nil
 	}
-	log.Printf("got rule %q with %d automations", name, len(automations))
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
 	for _, automation := range automations {
 		switch automation.Action {
 		case "enable_bucket_only_policy":
 			values := storageScanner.EnableBucketOnlyPolicy()
-			values.DryRun = automation.Properties.DryRun
+			values.DryRun = dryRun(services, automation)
 			topic := topics[automation.Action].Topic
-			if err := publish(ctx, services, automation.Action, topic, values.ProjectID, automation.Target, automation.Exclude, values); err != nil {
-				services.Logger.Error("failed to publish: %q", err)
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
 				continue
 			}
 		default:
-			return fmt.Errorf("action %q not found", automation.Action)
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
 		}
 	}
-	if err := markAsRemediated(ctx, storageScanner.StorageScanner.GetFinding().GetName(), storageScanner.StorageScanner.GetFinding().GetEventTime(), services); err != nil {
+	if err := markAsRemediated(ctx, storageScanner.FindingName(), storageScanner.EventTime(), services); err != nil {
 		return err
 	}
 	return nil
 }
 
 func executePublicSQLInstance(ctx context.Context, name string, values *Values, services *Services) error {
-	automations := services.Configuration.Spec.Parameters.SHA.PublicSQLInstance
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.PublicSQLInstance)
 	sqlScanner, err := sqlscanner.New(values.Finding)
 	if err != nil {
 		return err
 	}
-	securityMarks := sqlScanner.SQLScanner.GetFinding().GetSecurityMarks().GetMarks()
-	remediated := securityMarks[originalEventTime] == sqlScanner.SQLScanner.GetFinding().GetEventTime()
+	securityMarks := sqlScanner.SecurityMarks()
+	remediated := securityMarks[originalEventTime] == sqlScanner.EventTime()
 	if remediated {
-		log.Printf("finding already remediated")
+		logging.FromContext(ctx).Info("finding already remediated")
 		return nil
 	}
-	log.Printf("got rule %q with %d automations", name, len(automations))
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
 	for _, automation := range automations {
 		switch automation.Action {
 		case "close_cloud_sql":
 			values := sqlScanner.RemovePublic()
-			values.DryRun = automation.Properties.DryRun
+			values.DryRun = dryRun(services, automation)
+			values.DisablePublicIP = automation.Properties.CloseCloudSQL.DisablePublicIP
 			topic := topics[automation.Action].Topic
-			if err := publish(ctx, services, automation.Action, topic, values.ProjectID, automation.Target, automation.Exclude, values); err != nil {
-				services.Logger.Error("failed to publish: %q", err)
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
 				continue
 			}
 		default:
-			return fmt.Errorf("action %q not found", automation.Action)
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
 		}
 	}
-	if err := markAsRemediated(ctx, sqlScanner.SQLScanner.GetFinding().GetName(), sqlScanner.SQLScanner.GetFinding().GetEventTime(), services); err != nil {
+	if err := markAsRemediated(ctx, sqlScanner.FindingName(), sqlScanner.EventTime(), services); err != nil {
 		return err
 	}
 	return nil
 }
 
 func executeSSLNotEnforced(ctx context.Context, name string, values *Values, services *Services) error {
-	automations := services.Configuration.Spec.Parameters.SHA.SSLNotEnforced
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.SSLNotEnforced)
 	sqlScanner, err := sqlscanner.New(values.Finding)
 	if err != nil {
 		return err
 	}
-	securityMarks := sqlScanner.SQLScanner.GetFinding().GetSecurityMarks().GetMarks()
-	remediated := securityMarks[originalEventTime] == sqlScanner.SQLScanner.GetFinding().GetEventTime()
+	securityMarks := sqlScanner.SecurityMarks()
+	remediated := securityMarks[originalEventTime] == sqlScanner.EventTime()
 	if remediated {
-		log.Printf("finding already remediated")
+		logging.FromContext(ctx).Info("finding already remediated")
 		return nil
 	}
-	log.Printf("got rule %q with %d automations", name, len(automations))
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
 	for _, automation := range automations {
 		switch automation.Action {
 		case "cloud_sql_require_ssl":
 			values := sqlScanner.RequireSSL()
-			values.DryRun = automation.Properties.DryRun
+			values.DryRun = dryRun(services, automation)
 			topic := topics[automation.Action].Topic
-			if err := publish(ctx, services, automation.Action, topic, values.ProjectID, automation.Target, automation.Exclude, values); err != nil {
-				services.Logger.Error("failed to publish: %q", err)
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
 				continue
 			}
 		default:
-			return fmt.Errorf("action %q not found", automation.Action)
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
 		}
 	}
-	if err := markAsRemediated(ctx, sqlScanner.SQLScanner.GetFinding().GetName(), sqlScanner.SQLScanner.GetFinding().GetEventTime(), services); err != nil {
+	if err := markAsRemediated(ctx, sqlScanner.FindingName(), sqlScanner.EventTime(), services); err != nil {
 		return err
 	}
 	return nil
 }
 
 func executeSQLNoRootPassword(ctx context.Context, name string, values *Values, services *Services) error {
-	automations := services.Configuration.Spec.Parameters.SHA.SQLNoRootPassword
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.SQLNoRootPassword)
 	sqlScanner, err := sqlscanner.New(values.Finding)
 	if err != nil {
 		return err
 	}
-	securityMarks := sqlScanner.SQLScanner.GetFinding().GetSecurityMarks().GetMarks()
-	remediated := securityMarks[originalEventTime] == sqlScanner.SQLScanner.GetFinding().GetEventTime()
+	securityMarks := sqlScanner.SecurityMarks()
+	remediated := securityMarks[originalEventTime] == sqlScanner.EventTime()
 	if remediated {
-		log.Printf("finding already remediated")
+		logging.FromContext(ctx).Info("finding already remediated")
 		return nil
 	}
-	log.Printf("got rule %q with %d automations", name, len(automations))
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
 	for _, automation := range automations {
 		switch automation.Action {
 		case "cloud_sql_update_password":
-			values, err := sqlScanner.UpdatePassword()
-			if err != nil {
-				services.Logger.Error("failed to get values for %q: %q", automation.Action, err)
-				continue
-			}
-			values.DryRun = automation.Properties.DryRun
+			values := sqlScanner.UpdatePassword()
+			values.DryRun = dryRun(services, automation)
+			values.SecretProjectID = automation.Properties.UpdatePassword.SecretProjectID
+			values.SecretPrefix = automation.Properties.UpdatePassword.SecretPrefix
+			values.Output = automation.Properties.UpdatePassword.Output
+			values.SendGrid.APIKey = automation.Properties.UpdatePassword.SendGrid.APIKey
+			values.SendGrid.From = automation.Properties.UpdatePassword.SendGrid.From
+			values.SendGrid.To = automation.Properties.UpdatePassword.SendGrid.To
 			topic := topics[automation.Action].Topic
-			if err := publish(ctx, services, automation.Action, topic, values.ProjectID, automation.Target, automation.Exclude, values); err != nil {
-				services.Logger.Error("failed to publish: %q", err)
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
 				continue
 			}
 		default:
-			return fmt.Errorf("action %q not found", automation.Action)
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
 		}
 	}
-	if err := markAsRemediated(ctx, sqlScanner.SQLScanner.GetFinding().GetName(), sqlScanner.SQLScanner.GetFinding().GetEventTime(), services); err != nil {
+	if err := markAsRemediated(ctx, sqlScanner.FindingName(), sqlScanner.EventTime(), services); err != nil {
 		return err
 	}
 	return nil
 }
 
 func executePublicIPAddress(ctx context.Context, name string, values *Values, services *Services) error {
-	automations := services.Configuration.Spec.Parameters.SHA.PublicIPAddress
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.PublicIPAddress)
 	computeInstanceScanner, err := computeinstancescanner.New(values.Finding)
 	if err != nil {
 		return err
 	}
-	securityMarks := computeInstanceScanner.ComputeInstanceScanner.GetFinding().GetSecurityMarks().GetMarks()
-	remediated := securityMarks[originalEventTime] == computeInstanceScanner.ComputeInstanceScanner.GetFinding().GetEventTime()
+	securityMarks := computeInstanceScanner.SecurityMarks()
+	remediated := securityMarks[originalEventTime] == computeInstanceScanner.EventTime()
 	if remediated {
-		log.Printf("finding already remediated")
+		logging.FromContext(ctx).Info("finding already remediated")
 		return nil
 	}
-	log.Printf("got rule %q with %d automations", name, len(automations))
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
 	for _, automation := range automations {
 		switch automation.Action {
 		case "remove_public_ip":
 			values := computeInstanceScanner.RemovePublicIP()
-			values.DryRun = automation.Properties.DryRun
+			values.DryRun = dryRun(services, automation)
+			values.Projects = automation.Properties.RemovePublicIP.Projects
+			values.Labels = automation.Properties.RemovePublicIP.Labels
 			topic := topics[automation.Action].Topic
-			if err := publish(ctx, services, automation.Action, topic, values.ProjectID, automation.Target, automation.Exclude, values); err != nil {
-				services.Logger.Error("failed to publish: %q", err)
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
 				continue
 			}
 		default:
-			return fmt.Errorf("action %q not found", automation.Action)
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
 		}
 	}
-	if err := markAsRemediated(ctx, computeInstanceScanner.ComputeInstanceScanner.GetFinding().GetName(), computeInstanceScanner.ComputeInstanceScanner.GetFinding().GetEventTime(), services); err != nil {
+	if err := markAsRemediated(ctx, computeInstanceScanner.FindingName(), computeInstanceScanner.EventTime(), services); err != nil {
 		return err
 	}
 	return nil
 }
 
-func executeOpenFirewall(ctx context.Context, name string, values *Values, services *Services) error {
-	automations := services.Configuration.Spec.Parameters.SHA.OpenFirewall
-	firewallScanner, err := firewallscanner.New(values.Finding)
+func executeProjectWideSSHKeysAllowed(ctx context.Context, name string, values *Values, services *Services) error {
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.ProjectWideSSHKeysAllowed)
+	computeInstanceScanner, err := computeinstancescanner.New(values.Finding)
 	if err != nil {
 		return err
 	}
-	securityMarks := firewallScanner.FirewallScanner.GetFinding().GetSecurityMarks().GetMarks()
-	remediated := securityMarks[originalEventTime] == firewallScanner.FirewallScanner.GetFinding().GetEventTime()
+	securityMarks := computeInstanceScanner.SecurityMarks()
+	remediated := securityMarks[originalEventTime] == computeInstanceScanner.EventTime()
 	if remediated {
-		log.Printf("finding already remediated")
+		logging.FromContext(ctx).Info("finding already remediated")
 		return nil
 	}
-	log.Printf("got rule %q with %d automations", name, len(automations))
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
 	for _, automation := range automations {
 		switch automation.Action {
-		case "remediate_firewall":
-			values := firewallScanner.OpenFirewall()
-			values.DryRun = automation.Properties.DryRun
-			values.SourceRanges = automation.Properties.OpenFirewall.SourceRanges
-			values.Action = automation.Properties.OpenFirewall.RemediationAction
+		case "block_project_ssh_keys":
+			values := computeInstanceScanner.BlockProjectSSHKeys()
+			values.DryRun = dryRun(services, automation)
+			values.DenyPattern = automation.Properties.BlockProjectSSHKeys.DenyPattern
 			topic := topics[automation.Action].Topic
-			if err := publish(ctx, services, automation.Action, topic, values.ProjectID, automation.Target, automation.Exclude, values); err != nil {
-				services.Logger.Error("failed to publish: %q", err)
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
 				continue
 			}
 		default:
-			return fmt.Errorf("action %q not found", automation.Action)
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
 		}
 	}
-	if err := markAsRemediated(ctx, firewallScanner.FirewallScanner.GetFinding().GetName(), firewallScanner.FirewallScanner.GetFinding().GetEventTime(), services); err != nil {
+	if err := markAsRemediated(ctx, computeInstanceScanner.FindingName(), computeInstanceScanner.EventTime(), services); err != nil {
 		return err
 	}
 	return nil
 }
 
-func executeOpenSSHPort(ctx context.Context, name string, values *Values, services *Services) error {
-	automations := services.Configuration.Spec.Parameters.SHA.OpenFirewall
-	firewallScanner, err := firewallscanner.New(values.Finding)
+func executeOSLoginDisabled(ctx context.Context, name string, values *Values, services *Services) error {
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.OSLoginDisabled)
+	computeInstanceScanner, err := computeinstancescanner.New(values.Finding)
 	if err != nil {
 		return err
 	}
-	securityMarks := firewallScanner.FirewallScanner.GetFinding().GetSecurityMarks().GetMarks()
-	remediated := securityMarks[originalEventTime] == firewallScanner.FirewallScanner.GetFinding().GetEventTime()
+	securityMarks := computeInstanceScanner.SecurityMarks()
+	remediated := securityMarks[originalEventTime] == computeInstanceScanner.EventTime()
 	if remediated {
-		log.Printf("finding already remediated")
+		logging.FromContext(ctx).Info("finding already remediated")
 		return nil
 	}
-	log.Printf("got rule %q with %d automations", name, len(automations))
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
 	for _, automation := range automations {
 		switch automation.Action {
-		case "remediate_firewall":
-			values := firewallScanner.OpenFirewall()
-			values.DryRun = automation.Properties.DryRun
-			values.SourceRanges = automation.Properties.OpenFirewall.SourceRanges
-			values.Action = automation.Properties.OpenFirewall.RemediationAction
+		case "enable_os_login":
+			values := computeInstanceScanner.EnableOSLogin()
+			values.DryRun = dryRun(services, automation)
+			values.Folders = automation.Properties.EnableOSLogin.Folders
 			topic := topics[automation.Action].Topic
-			if err := publish(ctx, services, automation.Action, topic, values.ProjectID, automation.Target, automation.Exclude, values); err != nil {
-				services.Logger.Error("failed to publish: %q", err)
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
 				continue
 			}
 		default:
-			return fmt.Errorf("action %q not found", automation.Action)
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
 		}
 	}
-	if err := markAsRemediated(ctx, firewallScanner.FirewallScanner.GetFinding().GetName(), firewallScanner.FirewallScanner.GetFinding().GetEventTime(), services); err != nil {
+	if err := markAsRemediated(ctx, computeInstanceScanner.FindingName(), computeInstanceScanner.EventTime(), services); err != nil {
 		return err
 	}
 	return nil
 }
 
-func executeOpenRDPPort(ctx context.Context, name string, values *Values, services *Services) error {
-	automations := services.Configuration.Spec.Parameters.SHA.OpenFirewall
-	firewallScanner, err := firewallscanner.New(values.Finding)
+func executeSerialPortsEnabled(ctx context.Context, name string, values *Values, services *Services) error {
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.SerialPortsEnabled)
+	computeInstanceScanner, err := computeinstancescanner.New(values.Finding)
 	if err != nil {
 		return err
 	}
-	securityMarks := firewallScanner.FirewallScanner.GetFinding().GetSecurityMarks().GetMarks()
-	remediated := securityMarks[originalEventTime] == firewallScanner.FirewallScanner.GetFinding().GetEventTime()
+	securityMarks := computeInstanceScanner.SecurityMarks()
+	remediated := securityMarks[originalEventTime] == computeInstanceScanner.EventTime()
 	if remediated {
-		log.Printf("finding already remediated")
+		logging.FromContext(ctx).Info("finding already remediated")
 		return nil
 	}
-	log.Printf("got rule %q with %d automations", name, len(automations))
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
 	for _, automation := range automations {
 		switch automation.Action {
-		case "remediate_firewall":
-			values := firewallScanner.OpenFirewall()
-			values.DryRun = automation.Properties.DryRun
-			values.SourceRanges = automation.Properties.OpenFirewall.SourceRanges
-			values.Action = automation.Properties.OpenFirewall.RemediationAction
+		case "disable_serial_port":
+			values := computeInstanceScanner.DisableSerialPort()
+			values.DryRun = dryRun(services, automation)
 			topic := topics[automation.Action].Topic
-			if err := publish(ctx, services, automation.Action, topic, values.ProjectID, automation.Target, automation.Exclude, values); err != nil {
-				services.Logger.Error("failed to publish: %q", err)
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
 				continue
 			}
 		default:
-			return fmt.Errorf("action %q not found", automation.Action)
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
 		}
 	}
-	if err := markAsRemediated(ctx, firewallScanner.FirewallScanner.GetFinding().GetName(), firewallScanner.FirewallScanner.GetFinding().GetEventTime(), services); err != nil {
+	if err := markAsRemediated(ctx, computeInstanceScanner.FindingName(), computeInstanceScanner.EventTime(), services); err != nil {
 		return err
 	}
 	return nil
 }
 
-func executePublicDataset(ctx context.Context, name string, values *Values, services *Services) error {
-	automations := services.Configuration.Spec.Parameters.SHA.PublicDataset
-	publicDataset, err := datasetscanner.New(values.Finding)
+func executeShieldedVMDisabled(ctx context.Context, name string, values *Values, services *Services) error {
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.ShieldedVMDisabled)
+	computeInstanceScanner, err := computeinstancescanner.New(values.Finding)
 	if err != nil {
 		return err
 	}
-	securityMarks := publicDataset.DatasetScanner.GetFinding().GetSecurityMarks().GetMarks()
-	remediated := securityMarks[originalEventTime] == publicDataset.DatasetScanner.GetFinding().GetEventTime()
+	securityMarks := computeInstanceScanner.SecurityMarks()
+	remediated := securityMarks[originalEventTime] == computeInstanceScanner.EventTime()
 	if remediated {
-		log.Printf("finding already remediated")
+		logging.FromContext(ctx).Info("finding already remediated")
 		return nil
 	}
-	log.Printf("got rule %q with %d automations", name, len(automations))
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
 	for _, automation := range automations {
 		switch automation.Action {
-		case "close_public_dataset":
-			values := publicDataset.ClosePublicDataset()
-			values.DryRun = automation.Properties.DryRun
+		case "enable_shielded_vm":
+			values := computeInstanceScanner.EnableShieldedVM()
+			values.SecureBoot = automation.Properties.EnableShieldedVM.SecureBoot
+			values.VTPM = automation.Properties.EnableShieldedVM.VTPM
+			values.IntegrityMonitoring = automation.Properties.EnableShieldedVM.IntegrityMonitoring
+			values.DryRun = dryRun(services, automation)
 			topic := topics[automation.Action].Topic
-			if err := publish(ctx, services, automation.Action, topic, values.ProjectID, automation.Target, automation.Exclude, values); err != nil {
-				services.Logger.Error("failed to publish: %q", err)
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
 				continue
 			}
 		default:
-			return fmt.Errorf("action %q not found", automation.Action)
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
 		}
 	}
-	if err := markAsRemediated(ctx, publicDataset.DatasetScanner.GetFinding().GetName(), publicDataset.DatasetScanner.GetFinding().GetEventTime(), services); err != nil {
+	if err := markAsRemediated(ctx, computeInstanceScanner.FindingName(), computeInstanceScanner.EventTime(), services); err != nil {
 		return err
 	}
 	return nil
 }
 
-func executeAuditLoggingDisabled(ctx context.Context, name string, values *Values, services *Services) error {
-	automations := services.Configuration.Spec.Parameters.SHA.AuditLoggingDisabled
-	loggingScanner, err := loggingscanner.New(values.Finding)
+func executeDefaultServiceAccountUsed(ctx context.Context, name string, values *Values, services *Services) error {
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.DefaultServiceAccountUsed)
+	computeInstanceScanner, err := computeinstancescanner.New(values.Finding)
 	if err != nil {
 		return err
 	}
-	securityMarks := loggingScanner.Loggingscanner.GetFinding().GetSecurityMarks().GetMarks()
-	remediated := securityMarks[originalEventTime] == loggingScanner.Loggingscanner.GetFinding().GetEventTime()
+	securityMarks := computeInstanceScanner.SecurityMarks()
+	remediated := securityMarks[originalEventTime] == computeInstanceScanner.EventTime()
 	if remediated {
-		log.Printf("finding already remediated")
+		logging.FromContext(ctx).Info("finding already remediated")
 		return nil
 	}
-	log.Printf("got rule %q with %d automations", name, len(automations))
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
 	for _, automation := range automations {
 		switch automation.Action {
-		case "enable_audit_logs":
-			values := loggingScanner.EnableAuditLogs()
-			values.DryRun = automation.Properties.DryRun
+		case "remove_default_editor":
+			values := computeInstanceScanner.RemoveDefaultEditor()
+			values.DryRun = dryRun(services, automation)
+			values.Roles = automation.Properties.RemoveDefaultEditor.Roles
 			topic := topics[automation.Action].Topic
-			if err := publish(ctx, services, automation.Action, topic, values.ProjectID, automation.Target, automation.Exclude, values); err != nil {
-				services.Logger.Error("failed to publish: %q", err)
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
 				continue
 			}
 		default:
-			return fmt.Errorf("action %q not found", automation.Action)
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
 		}
 	}
-	if err := markAsRemediated(ctx, loggingScanner.Loggingscanner.GetFinding().GetName(), loggingScanner.Loggingscanner.GetFinding().GetEventTime(), services); err != nil {
+	if err := markAsRemediated(ctx, computeInstanceScanner.FindingName(), computeInstanceScanner.EventTime(), services); err != nil {
 		return err
 	}
 	return nil
 }
 
-func executeWebUIEnabled(ctx context.Context, name string, values *Values, services *Services) error {
-	automations := services.Configuration.Spec.Parameters.SHA.WebUIEnabled
-	containerScanner, err := containerscanner.New(values.Finding)
+func executeFirewallRuleCreated(ctx context.Context, name string, values *Values, services *Services) error {
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.ETD.FirewallRuleCreated)
+	firewallRuleCreated, err := firewallrulecreated.New(values.Finding)
 	if err != nil {
 		return err
 	}
-	securityMarks := containerScanner.Containerscanner.GetFinding().GetSecurityMarks().GetMarks()
-	remediated := securityMarks[originalEventTime] == containerScanner.Containerscanner.GetFinding().GetEventTime()
+	remediated := firewallRuleCreated.SecurityMarks()[originalEventTime] == firewallRuleCreated.EventTime()
 	if remediated {
-		log.Printf("finding already remediated")
+		logging.FromContext(ctx).Info("finding already remediated")
 		return nil
 	}
-	log.Printf("got rule %q with %d automations", name, len(automations))
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
 	for _, automation := range automations {
 		switch automation.Action {
-		case "disable_dashboard":
-			values := containerScanner.DisableDashboard()
-			values.DryRun = automation.Properties.DryRun
+		case "delete_firewall_rules":
+			values := firewallRuleCreated.DeleteFirewallRules()
+			values.DryRun = dryRun(services, automation)
+			values.RemediationAction = automation.Properties.DeleteFirewallRules.RemediationAction
+			values.IncidentStart = values.IncidentStart.Add(-automation.Properties.DeleteFirewallRules.Lookback)
 			topic := topics[automation.Action].Topic
-			if err := publish(ctx, services, automation.Action, topic, values.ProjectID, automation.Target, automation.Exclude, values); err != nil {
-				services.Logger.Error("failed to publish: %q", err)
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
 				continue
 			}
 		default:
-			return fmt.Errorf("action %q not found", automation.Action)
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
 		}
 	}
-	if err := markAsRemediated(ctx, containerScanner.Containerscanner.GetFinding().GetName(), containerScanner.Containerscanner.GetFinding().GetEventTime(), services); err != nil {
+	if err := markAsRemediated(ctx, firewallRuleCreated.FindingName(), firewallRuleCreated.EventTime(), services); err != nil {
 		return err
 	}
 	return nil
 }
 
-func executeNonOrgIamMember(ctx context.Context, name string, values *Values, services *Services) error {
-	automations := services.Configuration.Spec.Parameters.SHA.NonOrgMembers
-	iamScanner, err := iamscanner.New(values.Finding)
+func executeAccountCompromised(ctx context.Context, name string, values *Values, services *Services) error {
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.ETD.AccountCompromised)
+	accountCompromised, err := accountcompromised.New(values.Finding)
 	if err != nil {
 		return err
 	}
-	securityMarks := iamScanner.IAMScanner.GetFinding().GetSecurityMarks().GetMarks()
-	remediated := securityMarks[originalEventTime] == iamScanner.IAMScanner.GetFinding().GetEventTime()
+	remediated := accountCompromised.SecurityMarks()[originalEventTime] == accountCompromised.EventTime()
 	if remediated {
-		log.Printf("finding already remediated")
+		logging.FromContext(ctx).Info("finding already remediated")
 		return nil
 	}
-	log.Printf("got rule %q with %d automations", name, len(automations))
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
 	for _, automation := range automations {
 		switch automation.Action {
-		case "remove_non_org_members":
-			values := iamScanner.RemoveNonOrgMembers()
-			values.DryRun = automation.Properties.DryRun
-			values.AllowDomains = automation.Properties.NonOrgMembers.AllowDomains
+		case "suspend_user":
+			if !automation.Properties.SuspendUser.Enabled {
+				logging.FromContext(ctx).Info("suspend_user is not enabled, skipping.")
+				continue
+			}
+			values := accountCompromised.SuspendUser()
+			values.DryRun = dryRun(services, automation)
+			values.OrgUnits = automation.Properties.SuspendUser.OrgUnits
+			// Workspace users don't belong to a project so organizational units scope this automation
+			// instead of the target and exclude lists.
+			topic := topics[automation.Action].Topic
+			if err := publishToTopic(ctx, services, automation, topic, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		case "enforce_reenrollment":
+			if !accountCompromised.PasswordLeaked() {
+				logging.FromContext(ctx).Info("enforce_reenrollment only applies to leaked passwords, skipping.")
+				continue
+			}
+			values := accountCompromised.EnforceReenrollment()
+			values.DryRun = dryRun(services, automation)
+			values.OrgUnits = automation.Properties.EnforceReenrollment.OrgUnits
+			values.EnrollmentOrgUnit = automation.Properties.EnforceReenrollment.EnrollmentOrgUnit
+			topic := topics[automation.Action].Topic
+			if err := publishToTopic(ctx, services, automation, topic, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		case "revoke_user_tokens":
+			values := accountCompromised.RevokeTokens()
+			values.DryRun = dryRun(services, automation)
 			topic := topics[automation.Action].Topic
-			if err := publish(ctx, services, automation.Action, topic, values.ProjectID, automation.Target, automation.Exclude, values); err != nil {
-				services.Logger.Error("failed to publish: %q", err)
+			if err := publishToTopic(ctx, services, automation, topic, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
 				continue
 			}
 		default:
-			return fmt.Errorf("action %q not found", automation.Action)
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
 		}
 	}
-	if err := markAsRemediated(ctx, iamScanner.IAMScanner.GetFinding().GetName(), iamScanner.IAMScanner.GetFinding().GetEventTime(), services); err != nil {
+	if err := markAsRemediated(ctx, accountCompromised.FindingName(), accountCompromised.EventTime(), services); err != nil {
 		return err
 	}
 	return nil
 }
 
-func publish(ctx context.Context, services *Services, action, topic, projectID string, target, exclude []string, values interface{}) error {
-	ok, err := services.Resource.CheckMatches(ctx, projectID, target, exclude)
+func executeOpenFirewall(ctx context.Context, name string, values *Values, services *Services) error {
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.OpenFirewall)
+	firewallScanner, err := firewallscanner.New(values.Finding)
 	if err != nil {
-		return errors.Wrapf(err, "failed to check if project %q is within the target or is excluded", projectID)
+		return err
+	}
+	securityMarks := firewallScanner.SecurityMarks()
+	remediated := securityMarks[originalEventTime] == firewallScanner.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "remediate_firewall":
+			values := firewallScanner.OpenFirewall()
+			values.DryRun = dryRun(services, automation)
+			values.SourceRanges = automation.Properties.OpenFirewall.SourceRanges
+			values.Action = automation.Properties.OpenFirewall.RemediationAction
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, firewallScanner.FindingName(), firewallScanner.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+func executeOpenSSHPort(ctx context.Context, name string, values *Values, services *Services) error {
+	// Without automations of their own the findings are remediated as open firewalls, unless mapped.
+	automations := services.Configuration.Spec.Parameters.SHA.OpenSSHPort
+	if len(automations) == 0 {
+		automations = services.Configuration.Spec.Parameters.SHA.OpenFirewall
+	}
+	automations = mapped(ctx, automations)
+	firewallScanner, err := firewallscanner.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	securityMarks := firewallScanner.SecurityMarks()
+	remediated := securityMarks[originalEventTime] == firewallScanner.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "remediate_firewall":
+			values := firewallScanner.OpenFirewall()
+			values.DryRun = dryRun(services, automation)
+			values.SourceRanges = automation.Properties.OpenFirewall.SourceRanges
+			values.Action = automation.Properties.OpenFirewall.RemediationAction
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, firewallScanner.FindingName(), firewallScanner.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+func executeOpenRDPPort(ctx context.Context, name string, values *Values, services *Services) error {
+	// Without automations of their own the findings are remediated as open firewalls, unless mapped.
+	automations := services.Configuration.Spec.Parameters.SHA.OpenRDPPort
+	if len(automations) == 0 {
+		automations = services.Configuration.Spec.Parameters.SHA.OpenFirewall
+	}
+	automations = mapped(ctx, automations)
+	firewallScanner, err := firewallscanner.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	securityMarks := firewallScanner.SecurityMarks()
+	remediated := securityMarks[originalEventTime] == firewallScanner.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "remediate_firewall":
+			values := firewallScanner.OpenFirewall()
+			values.DryRun = dryRun(services, automation)
+			values.SourceRanges = automation.Properties.OpenFirewall.SourceRanges
+			values.Action = automation.Properties.OpenFirewall.RemediationAction
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, firewallScanner.FindingName(), firewallScanner.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+func executePublicDataset(ctx context.Context, name string, values *Values, services *Services) error {
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.PublicDataset)
+	publicDataset, err := datasetscanner.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	securityMarks := publicDataset.SecurityMarks()
+	remediated := securityMarks[originalEventTime] == publicDataset.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "close_public_dataset":
+			values := publicDataset.ClosePublicDataset()
+			values.DryRun = dryRun(services, automation)
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, publicDataset.FindingName(), publicDataset.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+func executeAuditLoggingDisabled(ctx context.Context, name string, values *Values, services *Services) error {
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.AuditLoggingDisabled)
+	loggingScanner, err := loggingscanner.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	securityMarks := loggingScanner.SecurityMarks()
+	remediated := securityMarks[originalEventTime] == loggingScanner.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "enable_audit_logs":
+			values := loggingScanner.EnableAuditLogs()
+			values.DryRun = dryRun(services, automation)
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		case "enable_service_audit_logs":
+			values := loggingScanner.EnableServiceAuditLogs()
+			values.DryRun = dryRun(services, automation)
+			if len(values.Services) == 0 {
+				values.Services = automation.Properties.EnableServiceAuditLogs.Services
+			}
+			if len(values.Services) == 0 {
+				logging.FromContext(ctx).Error("no services to enable audit logs for in project %q", values.ProjectID)
+				continue
+			}
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, loggingScanner.FindingName(), loggingScanner.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+func executeWebUIEnabled(ctx context.Context, name string, values *Values, services *Services) error {
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.WebUIEnabled)
+	containerScanner, err := containerscanner.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	securityMarks := containerScanner.SecurityMarks()
+	remediated := securityMarks[originalEventTime] == containerScanner.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "disable_dashboard":
+			values := containerScanner.DisableDashboard()
+			values.DryRun = dryRun(services, automation)
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, containerScanner.FindingName(), containerScanner.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+func executeMasterAuthorizedNetworksDisabled(ctx context.Context, name string, values *Values, services *Services) error {
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.MasterAuthorizedNetworksDisabled)
+	containerScanner, err := containerscanner.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	securityMarks := containerScanner.SecurityMarks()
+	remediated := securityMarks[originalEventTime] == containerScanner.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "enable_authorized_networks":
+			values := containerScanner.EnableAuthorizedNetworks()
+			values.DryRun = dryRun(services, automation)
+			values.CIDRBlocks = automation.Properties.EnableAuthorizedNetworks.CIDRBlocks
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, containerScanner.FindingName(), containerScanner.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+func executePublicServerless(ctx context.Context, name string, automations []Automation, values *Values, services *Services) error {
+	serverlessScanner, err := serverlessscanner.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	remediated := serverlessScanner.SecurityMarks()[originalEventTime] == serverlessScanner.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "remove_public_invoker":
+			values := serverlessScanner.RemovePublicInvoker()
+			values.DryRun = dryRun(services, automation)
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		case "enforce_authentication":
+			values := serverlessScanner.EnforceAuthentication()
+			values.DryRun = dryRun(services, automation)
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, serverlessScanner.FindingName(), serverlessScanner.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+func executeUnintendedExternalExposure(ctx context.Context, name string, values *Values, services *Services) error {
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.UnintendedExternalExposure)
+	loadBalancerScanner, err := loadbalancerscanner.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	remediated := loadBalancerScanner.SecurityMarks()[originalEventTime] == loadBalancerScanner.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "remove_external_exposure":
+			values := loadBalancerScanner.RemoveExternalExposure()
+			values.RemediationAction = automation.Properties.RemoveExternalExposure.RemediationAction
+			values.AllowServices = automation.Properties.RemoveExternalExposure.AllowServices
+			values.DryRun = dryRun(services, automation)
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, loadBalancerScanner.FindingName(), loadBalancerScanner.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+func executePrivateGoogleAccessDisabled(ctx context.Context, name string, values *Values, services *Services) error {
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.PrivateGoogleAccessDisabled)
+	networkScanner, err := networkscanner.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	remediated := networkScanner.SecurityMarks()[originalEventTime] == networkScanner.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "enable_private_google_access":
+			values := networkScanner.EnablePrivateAccess()
+			values.DenyExternalIP = automation.Properties.EnablePrivateGoogleAccess.DenyExternalIP
+			values.DryRun = dryRun(services, automation)
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, networkScanner.FindingName(), networkScanner.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+func executeFlowLogsDisabled(ctx context.Context, name string, values *Values, services *Services) error {
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.FlowLogsDisabled)
+	networkScanner, err := networkscanner.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	remediated := networkScanner.SecurityMarks()[originalEventTime] == networkScanner.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "enable_flow_logs":
+			values := networkScanner.EnableFlowLogs()
+			values.FlowSampling = automation.Properties.EnableFlowLogs.FlowSampling
+			values.AggregationInterval = automation.Properties.EnableFlowLogs.AggregationInterval
+			values.DryRun = dryRun(services, automation)
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, networkScanner.FindingName(), networkScanner.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+func executeDNSSECDisabled(ctx context.Context, name string, values *Values, services *Services) error {
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.DNSSECDisabled)
+	dnsScanner, err := dnsscanner.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	remediated := dnsScanner.SecurityMarks()[originalEventTime] == dnsScanner.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "enable_dnssec":
+			values := dnsScanner.EnableDNSSEC()
+			values.DryRun = dryRun(services, automation)
+			values.AllowZones = automation.Properties.EnableDNSSEC.AllowZones
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, dnsScanner.FindingName(), dnsScanner.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+func executeRogueJob(ctx context.Context, name string, values *Values, services *Services) error {
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.ETD.RogueJob)
+	rogueJob, err := roguejob.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	remediated := rogueJob.SecurityMarks()[originalEventTime] == rogueJob.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "stop_rogue_job":
+			values := rogueJob.StopRogueJob()
+			values.AllowProjects = automation.Properties.StopRogueJob.AllowProjects
+			values.DryRun = dryRun(services, automation)
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, rogueJob.FindingName(), rogueJob.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+func executeServiceAccountImpersonation(ctx context.Context, name string, values *Values, services *Services) error {
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.ETD.ServiceAccountImpersonation)
+	impersonationFinding, err := impersonation.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	remediated := impersonationFinding.SecurityMarks()[originalEventTime] == impersonationFinding.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "remove_impersonation":
+			values := impersonationFinding.RemoveImpersonation()
+			values.DryRun = dryRun(services, automation)
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, impersonationFinding.FindingName(), impersonationFinding.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+func executeContainerThreat(ctx context.Context, name string, automations []Automation, values *Values, services *Services) error {
+	containerThreat, err := containerthreat.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	remediated := containerThreat.SecurityMarks()[originalEventTime] == containerThreat.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "drain_node":
+			values := containerThreat.DrainNode()
+			values.DryRun = dryRun(services, automation)
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		case "delete_pod":
+			values := containerThreat.DeletePod()
+			values.DryRun = dryRun(services, automation)
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		case "rotate_secrets":
+			values := containerThreat.RotateSecrets()
+			values.DryRun = dryRun(services, automation)
+			values.LabelKey = automation.Properties.RotateSecrets.LabelKey
+			values.HookURL = automation.Properties.RotateSecrets.HookURL
+			values.HookTopic = automation.Properties.RotateSecrets.HookTopic
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		case "quarantine_image":
+			values := containerThreat.QuarantineImage()
+			values.Tag = automation.Properties.QuarantineImage.Tag
+			values.BlockDeployment = automation.Properties.QuarantineImage.BlockDeployment
+			values.DryRun = dryRun(services, automation)
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, containerThreat.FindingName(), containerThreat.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+func executeNonOrgIamMember(ctx context.Context, name string, values *Values, services *Services) error {
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.NonOrgMembers)
+	iamScanner, err := iamscanner.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	securityMarks := iamScanner.SecurityMarks()
+	remediated := securityMarks[originalEventTime] == iamScanner.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "remove_non_org_members":
+			if err := publishNonOrgMembers(ctx, services, automation, iamScanner.RemoveNonOrgMembers()); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, iamScanner.FindingName(), iamScanner.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+// publishNonOrgMembers configures the values of the remove non org members automation and sends
+// them to its topic, checking the folder or organization of the policy if it's not a project's.
+func publishNonOrgMembers(ctx context.Context, services *Services, automation Automation, values *removenonorgmembers.Values) error {
+	values.DryRun = dryRun(services, automation)
+	values.AllowDomains = automation.Properties.NonOrgMembers.AllowDomains
+	values.UnconditionalOnly = automation.Properties.NonOrgMembers.UnconditionalOnly
+	escalation := automation.Properties.NonOrgMembers.Escalation
+	values.Escalation.Threshold = escalation.Threshold
+	values.Escalation.Window = escalation.Window
+	values.Escalation.Bucket = escalation.Bucket
+	values.Escalation.CustomerIDs = escalation.CustomerIDs
+	groups := automation.Properties.NonOrgMembers.Groups
+	values.Groups.Remove = groups.Remove
+	values.Groups.Lookup = groups.Lookup
+	values.Groups.CustomerIDs = groups.CustomerIDs
+	serviceAccounts := automation.Properties.NonOrgMembers.ServiceAccounts
+	values.ServiceAccounts.Remove = serviceAccounts.Remove
+	values.ServiceAccounts.AllowProjects = serviceAccounts.AllowProjects
+	values.Directory.Groups = automation.Properties.NonOrgMembers.Directory.Groups
+	topic := topics[automation.Action].Topic
+	if values.Resource != "" {
+		return publishResource(ctx, services, automation, topic, values.Resource, values)
+	}
+	return publish(ctx, services, automation, topic, values.ProjectID, values)
+}
+
+func executeNonLeastPrivilege(ctx context.Context, name string, values *Values, services *Services) error {
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.NonLeastPrivilege)
+	iamScanner, err := iamscanner.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	securityMarks := iamScanner.SecurityMarks()
+	remediated := securityMarks[originalEventTime] == iamScanner.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "remove_default_editor":
+			values := iamScanner.RemoveDefaultEditor()
+			values.DryRun = dryRun(services, automation)
+			values.Roles = automation.Properties.RemoveDefaultEditor.Roles
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, iamScanner.FindingName(), iamScanner.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+func executePublicPubSub(ctx context.Context, name string, automations []Automation, values *Values, services *Services) error {
+	pubsubScanner, err := pubsubscanner.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	remediated := pubsubScanner.SecurityMarks()[originalEventTime] == pubsubScanner.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "remove_public_pubsub":
+			values := pubsubScanner.RemovePublic()
+			values.DryRun = dryRun(services, automation)
+			values.AllowResources = automation.Properties.RemovePublicPubSub.AllowResources
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, pubsubScanner.FindingName(), pubsubScanner.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+func executeKMSPublicKey(ctx context.Context, name string, values *Values, services *Services) error {
+	kmsScanner, err := kmsscanner.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	remediated := kmsScanner.SecurityMarks()[originalEventTime] == kmsScanner.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	automations := mapped(ctx, services.Configuration.Spec.Parameters.SHA.KMSPublicKey)
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "remove_public_kms":
+			values := kmsScanner.RemovePublicKMS()
+			values.DryRun = dryRun(services, automation)
+			values.RotationPeriod = automation.Properties.RemovePublicKMS.RotationPeriod
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, kmsScanner.FindingName(), kmsScanner.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+func executePublicRepository(ctx context.Context, name string, automations []Automation, values *Values, services *Services) error {
+	artifactScanner, err := artifactscanner.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	remediated := artifactScanner.SecurityMarks()[originalEventTime] == artifactScanner.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "remove_public_repository":
+			values := artifactScanner.RemovePublicRepository()
+			values.DryRun = dryRun(services, automation)
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, artifactScanner.FindingName(), artifactScanner.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+func executeAPIKey(ctx context.Context, name string, automations []Automation, values *Values, services *Services) error {
+	apiKeyScanner, err := apikeyscanner.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	remediated := apiKeyScanner.SecurityMarks()[originalEventTime] == apiKeyScanner.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "restrict_api_key":
+			values := apiKeyScanner.RestrictAPIKey()
+			values.DryRun = dryRun(services, automation)
+			values.Action = automation.Properties.RestrictAPIKey.RemediationAction
+			values.AllowedReferrers = automation.Properties.RestrictAPIKey.AllowedReferrers
+			values.AllowedIPs = automation.Properties.RestrictAPIKey.AllowedIPs
+			values.AllowedAPIs = automation.Properties.RestrictAPIKey.AllowedAPIs
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, apiKeyScanner.FindingName(), apiKeyScanner.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+func executeServiceAccountKey(ctx context.Context, name string, automations []Automation, values *Values, services *Services) error {
+	iamScanner, err := iamscanner.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	securityMarks := iamScanner.SecurityMarks()
+	remediated := securityMarks[originalEventTime] == iamScanner.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "disable_old_keys":
+			values := iamScanner.DisableOldKeys()
+			values.DryRun = dryRun(services, automation)
+			values.MaxAge = automation.Properties.DisableOldKeys.MaxAge
+			values.Output = automation.Properties.DisableOldKeys.Output
+			values.SendGrid.APIKey = automation.Properties.DisableOldKeys.SendGrid.APIKey
+			values.SendGrid.From = automation.Properties.DisableOldKeys.SendGrid.From
+			values.SendGrid.To = automation.Properties.DisableOldKeys.SendGrid.To
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, iamScanner.FindingName(), iamScanner.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+// executeGuardDuty remediates GuardDuty findings, mirrored into Security Command Center or
+// delivered by the SQS bridge. Only mirrored findings are marked as remediated.
+func executeGuardDuty(ctx context.Context, name string, automations []Automation, values *Values, services *Services) error {
+	guardDuty, err := guardduty.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	remediated := guardDuty.FindingName() != "" && guardDuty.SecurityMarks()[originalEventTime] == guardDuty.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "disable_aws_access_key":
+			values := guardDuty.DisableAccessKey()
+			values.DryRun = dryRun(services, automation)
+			values.CredentialsSecret = automation.Properties.AWS.CredentialsSecret
+			topic := topics[automation.Action].Topic
+			if err := publishAccount(ctx, services, automation, topic, values.AccountID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		case "isolate_ec2_instance":
+			values := guardDuty.IsolateInstance()
+			values.DryRun = dryRun(services, automation)
+			values.CredentialsSecret = automation.Properties.AWS.CredentialsSecret
+			values.SecurityGroup = automation.Properties.IsolateEC2Instance.SecurityGroup
+			topic := topics[automation.Action].Topic
+			if err := publishAccount(ctx, services, automation, topic, values.AccountID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if guardDuty.FindingName() == "" {
+		return nil
+	}
+	if err := markAsRemediated(ctx, guardDuty.FindingName(), guardDuty.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+func publish(ctx context.Context, services *Services, automation Automation, topic, projectID string, values interface{}) error {
+	ok, err := services.Resource.CheckMatchesFolders(ctx, projectID, automation.Target, automation.FolderIDs, automation.Exclude)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if project %q is within the target or is excluded", projectID)
 	}
 	if !ok {
 		return fmt.Errorf("project %q is not within the target or is excluded", projectID)
 	}
+	sa, err := projectRemediator(ctx, services, projectID)
+	if err != nil {
+		return err
+	}
+	return publishToTopic(context.WithValue(ctx, serviceAccountKey{}, sa), services, automation, topic, values)
+}
+
+// projectRemediator returns the service account of the remediator of the project, or an empty
+// string if it has none.
+func projectRemediator(ctx context.Context, svcs *Services, projectID string) (string, error) {
+	remediators := svcs.Configuration.Spec.Remediators
+	for _, r := range remediators {
+		for _, p := range r.Projects {
+			if p == projectID {
+				return r.ServiceAccount, nil
+			}
+		}
+	}
+	for _, r := range remediators {
+		if len(r.FolderIDs) == 0 {
+			continue
+		}
+		ok, err := svcs.Resource.InFolders(ctx, projectID, r.FolderIDs)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to find the remediator of project %q", projectID)
+		}
+		if ok {
+			return r.ServiceAccount, nil
+		}
+	}
+	return "", nil
+}
+
+// resourceRemediator returns the service account of the remediator of the folder, or an empty
+// string if it has none.
+func resourceRemediator(ctx context.Context, svcs *Services, resource string) (string, error) {
+	for _, r := range svcs.Configuration.Spec.Remediators {
+		if len(r.FolderIDs) == 0 {
+			continue
+		}
+		ok, err := svcs.Resource.CheckMatchesResourceFolders(ctx, resource, nil, r.FolderIDs, nil)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to find the remediator of %q", resource)
+		}
+		if ok {
+			return r.ServiceAccount, nil
+		}
+	}
+	return "", nil
+}
+
+// dryRun returns whether the automation runs in dry run, either configured globally or for the automation.
+func dryRun(services *Services, automation Automation) bool {
+	return services.Configuration.Spec.DryRun || automation.Properties.DryRun
+}
+
+// executeRegistered runs the registered automations of a rule no built-in automation supports.
+// The finding isn't marked as remediated as it may not be in Security Command Center.
+func executeRegistered(ctx context.Context, name string, automations []Automation, values *Values, services *Services) error {
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publishRegistered sends the values the automation registered with the action reads from the
+// finding to the topic of registered automations. It returns an unsupported error if no automation
+// is registered with the action.
+func publishRegistered(ctx context.Context, services *Services, automation Automation, finding []byte) error {
+	registered, ok := automations.Lookup(automation.Action)
+	if !ok {
+		return unsupported("action %q not found", automation.Action)
+	}
+	projectID, v, err := registered.ReadFinding(finding)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to read finding for action %q: %q", automation.Action, err)
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal the values of action %q", automation.Action)
+	}
+	values := &automations.Values{
+		Automation: automation.Action,
+		ProjectID:  projectID,
+		DryRun:     dryRun(services, automation),
+		Data:       data,
+	}
+	if err := publish(ctx, services, automation, registeredTopic, projectID, values); err != nil {
+		logging.FromContext(ctx).Error("failed to publish: %q", err)
+	}
+	return nil
+}
+
+// publishResource sends the values to the automation's topic if the folder or organization is
+// within the target and not excluded.
+func publishResource(ctx context.Context, services *Services, automation Automation, topic, resource string, values interface{}) error {
+	ok, err := services.Resource.CheckMatchesResourceFolders(ctx, resource, automation.Target, automation.FolderIDs, automation.Exclude)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if %q is within the target or is excluded", resource)
+	}
+	if !ok {
+		return fmt.Errorf("%q is not within the target or is excluded", resource)
+	}
+	sa, err := resourceRemediator(ctx, services, resource)
+	if err != nil {
+		return err
+	}
+	return publishToTopic(context.WithValue(ctx, serviceAccountKey{}, sa), services, automation, topic, values)
+}
+
+// executeDefender remediates Defender for Cloud alerts forwarded from Azure. Alerts aren't in
+// Security Command Center so they're never marked as remediated.
+func executeDefender(ctx context.Context, name string, automations []Automation, values *Values, services *Services) error {
+	alert, err := defender.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "remove_public_container":
+			values := alert.RemovePublicContainer()
+			values.DryRun = dryRun(services, automation)
+			values.CredentialsSecret = automation.Properties.Azure.CredentialsSecret
+			topic := topics[automation.Action].Topic
+			if err := publishAccount(ctx, services, automation, topic, values.SubscriptionID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// executeForseti remediates the violations Forseti sends to Security Command Center.
+func executeForseti(ctx context.Context, name string, automations []Automation, values *Values, services *Services) error {
+	forseti, err := violation.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	remediated := forseti.SecurityMarks()[originalEventTime] == forseti.EventTime()
+	if remediated {
+		logging.FromContext(ctx).Info("finding already remediated")
+		return nil
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "close_bucket":
+			values := forseti.CloseBucket()
+			values.DryRun = dryRun(services, automation)
+			values.AllowBuckets = automation.Properties.CloseBucket.AllowBuckets
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		case "remove_non_org_members":
+			if err := publishNonOrgMembers(ctx, services, automation, forseti.RemoveNonOrgMembers()); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	if err := markAsRemediated(ctx, forseti.FindingName(), forseti.EventTime(), services); err != nil {
+		return err
+	}
+	return nil
+}
+
+// executeFalco remediates the pods and nodes of Falco alerts. Alerts aren't in Security Command
+// Center so they're never marked as remediated.
+func executeFalco(ctx context.Context, name string, automations []Automation, values *Values, services *Services) error {
+	falco, err := falcoalert.New(values.Finding)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("got rule %q with %d automations", name, len(automations))
+	for _, automation := range automations {
+		switch automation.Action {
+		case "drain_node":
+			values := falco.DrainNode()
+			values.DryRun = dryRun(services, automation)
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		case "delete_pod":
+			values := falco.DeletePod()
+			values.DryRun = dryRun(services, automation)
+			topic := topics[automation.Action].Topic
+			if err := publish(ctx, services, automation, topic, values.ProjectID, values); err != nil {
+				logging.FromContext(ctx).Error("failed to publish: %q", err)
+				continue
+			}
+		default:
+			if err := publishRegistered(ctx, services, automation, values.Finding); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// publishAccount sends the values to the automation's topic if the AWS account or Azure
+// subscription is within the target and not excluded. Targets and exclusions are account or
+// subscription IDs, "*" matching any.
+func publishAccount(ctx context.Context, services *Services, automation Automation, topic, accountID string, values interface{}) error {
+	if !matchesAccount(automation.Target, accountID) || matchesAccount(automation.Exclude, accountID) {
+		return fmt.Errorf("account %q is not within the target or is excluded", accountID)
+	}
+	return publishToTopic(ctx, services, automation, topic, values)
+}
+
+// matchesAccount returns whether the AWS account or Azure subscription matches any of the patterns.
+func matchesAccount(patterns []string, accountID string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, accountID); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// publishToTopic sends the values to the automation's topic without checking the target and exclude lists.
+func publishToTopic(ctx context.Context, services *Services, automation Automation, topic string, values interface{}) error {
+	action := automation.Action
+	ok, err := matchesLabels(ctx, services, automation)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("resource doesn't match the labels of action %q", action)
+	}
 	b, err := json.Marshal(&values)
 	if err != nil {
 		return errors.Wrapf(err, "failed to marshal when running %q", action)
 	}
+	// The finding ID is passed along so the automation can record it in the audit trail.
+	id, _ := ctx.Value(findingIDKey{}).(string)
+	if requiresApproval(services, action) {
+		return requestApproval(ctx, services, automation, topic, id, b)
+	}
+	within, err := withinWindows(services, action, time.Now())
+	if err != nil {
+		return err
+	}
+	if !within {
+		logging.FromContext(ctx).Info("action %q is outside of its maintenance windows, requesting approval", action)
+		return requestApproval(ctx, services, automation, topic, id, b)
+	}
+	// The topic is passed along so failed remediations can be replayed to it.
+	attributes := map[string]string{"finding_id": id, "topic": topic}
+	// The automation notifies these channels of its outcome.
+	if len(automation.Notify) > 0 {
+		attributes["notify"] = strings.Join(automation.Notify, ",")
+	}
+	if severity, _ := ctx.Value(severityKey{}).(string); severity != "" {
+		attributes["severity"] = severity
+	}
+	if category, _ := ctx.Value(categoryKey{}).(string); category != "" {
+		attributes["category"] = category
+	}
+	// The automation impersonates the remediator service account of the project or folder.
+	if sa, _ := ctx.Value(serviceAccountKey{}).(string); sa != "" {
+		attributes["service_account"] = sa
+	}
+	// The automation skips redeliveries of the finding by its ID and event time.
+	if eventTime, _ := ctx.Value(eventTimeKey{}).(string); eventTime != "" {
+		attributes["event_time"] = eventTime
+	}
+	tracing.Inject(ctx, attributes)
 	if _, err := services.PubSub.Publish(ctx, topic, &pubsub.Message{
-		Data: b,
+		Data:       b,
+		Attributes: attributes,
 	}); err != nil {
-		services.Logger.Error("failed to publish to %q for action %q", topic, action)
+		logging.FromContext(ctx).Error("failed to publish to %q for action %q", topic, action)
+		return err
+	}
+	logging.FromContext(ctx).Info("sent to pubsub topic: %q", topic)
+	return nil
+}
+
+// defaultApprovalTTL is how long actions can be approved if no TTL is configured.
+const defaultApprovalTTL = 24 * time.Hour
+
+// withinWindows returns whether the action may run now according to the maintenance windows.
+func withinWindows(svcs *Services, action string, now time.Time) (bool, error) {
+	ok, err := services.WithinWindows(svcs.Configuration.Spec.MaintenanceWindows, action, now)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to check the maintenance windows of %q", action)
+	}
+	return ok, nil
+}
+
+// mandatoryApproval are the actions that always wait for approval, whether configured or not.
+var mandatoryApproval = map[string]bool{
+	disablebilling.Action: true,
+}
+
+func requiresApproval(services *Services, action string) bool {
+	if mandatoryApproval[action] {
+		return true
+	}
+	approval := services.Configuration.Spec.Approval
+	if _, ok := approval.RequiredApprovers[action]; ok {
+		return true
+	}
+	for _, a := range approval.Actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// sustained counts the finding of the project and returns whether the project had more findings
+// than the threshold of the disable billing automation within its window. Every finding is
+// sustained without a threshold.
+func sustained(ctx context.Context, services *Services, automation Automation, projectID string) (bool, error) {
+	p := automation.Properties.DisableBilling
+	if p.Threshold == 0 {
+		return true, nil
+	}
+	if services.Counter == nil {
+		return false, errors.New("counting findings requires the counter service")
+	}
+	key := "cryptomining/" + projectID
+	now := time.Now()
+	if err := services.Counter.Increment(ctx, p.Bucket, key, now); err != nil {
+		return false, err
+	}
+	count, err := services.Counter.Count(ctx, p.Bucket, key, now.Add(-p.Window))
+	if err != nil {
+		return false, err
+	}
+	if count <= p.Threshold {
+		logging.FromContext(ctx).Info("%s had %d findings in %s, not disabling billing", projectID, count, p.Window)
+		return false, nil
+	}
+	return true, nil
+}
+
+// requestApproval stores the message as a pending action and sends each approver the links to
+// approve or deny it, or posts the approve and deny buttons to Slack, and lets the Teams channel
+// know. The message is only published once approved.
+func requestApproval(ctx context.Context, svcs *Services, automation Automation, topic, findingID string, b []byte) error {
+	action := automation.Action
+	conf := svcs.Configuration.Spec.Approval
+	// Actions always waiting for approval aren't requested without links that can't be forged.
+	if mandatoryApproval[action] && (len(conf.Key) < services.MinApprovalKeyLength || conf.URL == "") {
+		return fmt.Errorf("action %q requires approval but spec.approval.key or spec.approval.url is not configured", action)
+	}
+	// Teams can't tell who follows a link, so approvers decide by email or in Slack.
+	if svcs.Approvals == nil || (svcs.Email == nil && svcs.Slack == nil) {
+		return fmt.Errorf("action %q requires approval but approvals are not configured", action)
+	}
+	ttl := conf.TTL
+	if ttl == 0 {
+		ttl = defaultApprovalTTL
+	}
+	reporter, _ := ctx.Value(reporterKey{}).(string)
+	severity, _ := ctx.Value(severityKey{}).(string)
+	category, _ := ctx.Value(categoryKey{}).(string)
+	serviceAccount, _ := ctx.Value(serviceAccountKey{}).(string)
+	p := &services.PendingAction{
+		Action:    action,
+		Topic:     topic,
+		FindingID: findingID,
+		Data:      b,
+		Reporter:  reporter,
+		Severity:  severity,
+		Category:  category,
+		// The approved action runs as the remediator chosen now.
+		ServiceAccount: serviceAccount,
+		Required:       conf.RequiredApprovers[action],
+		Notify:         automation.Notify,
+	}
+	approvers := []string{}
+	for _, to := range conf.SendGrid.To {
+		// The reporter of the finding can't approve its remediation.
+		if !strings.EqualFold(to, reporter) {
+			approvers = append(approvers, to)
+		}
+	}
+	if len(approvers) < p.Required {
+		return fmt.Errorf("action %q requires %d approvers but only %d can approve it", action, p.Required, len(approvers))
+	}
+	if err := svcs.Approvals.Request(ctx, p, ttl, time.Now()); err != nil {
 		return err
 	}
-	log.Printf("sent to pubsub topic: %q", topic)
+	subject := fmt.Sprintf("Approval required to run %q", action)
+	if svcs.Slack != nil {
+		text := fmt.Sprintf("*%s* for finding %q with:\n```%s```\nIt needs %d of the approvers to approve it. The request expires at %s.", subject, findingID, b, p.Required, p.Expires.Format(time.RFC3339))
+		if err := svcs.Slack.RequestApproval(ctx, p, text); err != nil {
+			return errors.Wrapf(err, "failed to post approval request for %q", action)
+		}
+	}
+	if svcs.Teams != nil {
+		// The card has no links, a channel member could follow every approver's.
+		text := fmt.Sprintf("Security Response Automation wants to run %q for finding %q with:\n\n%s\n\nIt needs %d of the approvers to approve it, they were sent their links. The request expires at %s.", action, findingID, b, p.Required, p.Expires.Format(time.RFC3339))
+		if err := svcs.Teams.RequestApproval(ctx, p, text); err != nil {
+			return errors.Wrapf(err, "failed to post approval request for %q", action)
+		}
+	}
+	for _, to := range approvers {
+		// Approvers only get links by email if email is configured.
+		if svcs.Email == nil {
+			break
+		}
+		approve := svcs.Approvals.Link(conf.URL, p, services.DecisionApprove, to)
+		deny := svcs.Approvals.Link(conf.URL, p, services.DecisionDeny, to)
+		body := fmt.Sprintf("Security Response Automation wants to run %q for finding %q with:\n\n%s\n\nIt needs %d of the approvers to approve it.\n\nApprove: %s\nDeny: %s\n\nThe request expires at %s.\n", action, findingID, b, p.Required, approve, deny, p.Expires.Format(time.RFC3339))
+		if _, err := svcs.Email.Send(ctx, subject, conf.SendGrid.From, body, []string{to}); err != nil {
+			return errors.Wrapf(err, "failed to send approval request for %q", action)
+		}
+	}
+	logging.FromContext(ctx).Info("requested approval %q of %q from %d approvers", p.ID, action, len(approvers))
 	return nil
 }
@@ -4,3 +4,9 @@ variable "folder-ids" {
   type        = list(string)
   description = "Folder IDs to grant the necessary permissions for this Cloud Function execution."
 }
+
+variable "batch-routing" {
+  type        = bool
+  default     = false
+  description = "If true, findings are routed in batches by RouteBatch and this function skips them."
+}
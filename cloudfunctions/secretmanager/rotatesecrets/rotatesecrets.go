@@ -0,0 +1,127 @@
+package rotatesecrets
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// defaultLabelKey is the secret label naming the workload a secret belongs to.
+const defaultLabelKey = "workload"
+
+// RotationRequest is published to the hook topic for each secret.
+type RotationRequest struct {
+	// Secret is the secret's resource name, such as "projects/<project>/secrets/<secret>".
+	Secret string `json:"secret"`
+	// DisabledVersions are the versions disabled once the request is published.
+	DisabledVersions []string `json:"disabledVersions"`
+}
+
+// Values contains the required values needed for this function.
+type Values struct {
+	ProjectID string
+	// Workload is matched against the label of the project's secrets, such as an instance name or
+	// Kubernetes namespace.
+	Workload string
+	LabelKey string
+	// HookURL is called to generate the new value of each secret.
+	HookURL string
+	// HookTopic is sent a rotation request for each secret if HookURL is not set. The hook is
+	// expected to add the new version itself.
+	HookTopic string
+	DryRun    bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	SecretManager *services.SecretManager
+	// RotationHook is only required when a hook URL is configured.
+	RotationHook *services.RotationHook
+	PubSub       *services.PubSub
+}
+
+// Execute rotates the secrets labeled as belonging to the compromised workload. A new version is
+// added from the rotation hook and the versions enabled beforehand are disabled.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	if values.Workload == "" {
+		logging.FromContext(ctx).Info("no workload found in project %q, skipping", values.ProjectID)
+		return nil
+	}
+	if values.HookURL == "" && values.HookTopic == "" {
+		return fmt.Errorf("no rotation hook configured")
+	}
+	labelKey := values.LabelKey
+	if labelKey == "" {
+		labelKey = defaultLabelKey
+	}
+	secrets, err := services.SecretManager.SecretsByLabel(ctx, values.ProjectID, labelKey, values.Workload)
+	if err != nil {
+		return err
+	}
+	if len(secrets) == 0 {
+		logging.FromContext(ctx).Info("no secrets labeled %s=%s in project %q", labelKey, values.Workload, values.ProjectID)
+		return nil
+	}
+	for _, secret := range secrets {
+		if values.DryRun {
+			logging.FromContext(ctx).Info("dry_run on, would have rotated secret %q of workload %q", secret, values.Workload)
+			continue
+		}
+		if err := rotate(ctx, secret, values, services); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rotate adds a new version of the secret and disables its previously enabled versions. The enabled
+// versions are listed first so a version added by a Pub/Sub hook is never disabled.
+func rotate(ctx context.Context, secret string, values *Values, services *Services) error {
+	versions, err := services.SecretManager.EnabledVersions(ctx, secret)
+	if err != nil {
+		return err
+	}
+	if values.HookURL != "" {
+		value, err := services.RotationHook.Generate(ctx, secret)
+		if err != nil {
+			return err
+		}
+		version, err := services.SecretManager.AddVersion(ctx, secret, value)
+		if err != nil {
+			return err
+		}
+		logging.FromContext(ctx).Info("added version %q to secret %q", version, secret)
+	} else {
+		b, err := json.Marshal(&RotationRequest{Secret: secret, DisabledVersions: versions})
+		if err != nil {
+			return err
+		}
+		if _, err := services.PubSub.Publish(ctx, values.HookTopic, &pubsub.Message{Data: b}); err != nil {
+			return fmt.Errorf("failed to publish rotation request for %q: %q", secret, err)
+		}
+		logging.FromContext(ctx).Info("requested rotation of secret %q on topic %q", secret, values.HookTopic)
+	}
+	if err := services.SecretManager.DisableVersions(ctx, versions); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("disabled versions %q of secret %q", versions, secret)
+	return nil
+}
@@ -0,0 +1,110 @@
+package rotatesecrets
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	secretmanager "google.golang.org/api/secretmanager/v1"
+)
+
+func TestRotateSecrets(t *testing.T) {
+	const secret = "projects/test-project/secrets/db-password"
+	ctx := context.Background()
+	secrets := []*secretmanager.Secret{
+		{Name: secret, Labels: map[string]string{"workload": "miner"}},
+		{Name: "projects/test-project/secrets/api-key", Labels: map[string]string{"workload": "frontend"}},
+		{Name: "projects/test-project/secrets/unlabeled"},
+	}
+	versions := map[string][]*secretmanager.SecretVersion{
+		secret: {
+			{Name: secret + "/versions/1", State: "DISABLED"},
+			{Name: secret + "/versions/2", State: "ENABLED"},
+			{Name: secret + "/versions/3", State: "ENABLED"},
+		},
+	}
+	for _, tt := range []struct {
+		name             string
+		values           *Values
+		expectedVersions map[string]string
+		expectedDisabled []string
+		expectedHook     []string
+		expectedMessage  string
+	}{
+		{
+			name:             "rotate with hook url",
+			values:           &Values{Workload: "miner", HookURL: "https://us-central1-test-project.cloudfunctions.net/rotate"},
+			expectedVersions: map[string]string{secret: "bmV3LXZhbHVl"},
+			expectedDisabled: []string{secret + "/versions/2", secret + "/versions/3"},
+			expectedHook:     []string{`{"secret":"projects/test-project/secrets/db-password"}`},
+		},
+		{
+			name:             "rotate with hook topic",
+			values:           &Values{Workload: "miner", HookTopic: "rotate-secrets"},
+			expectedDisabled: []string{secret + "/versions/2", secret + "/versions/3"},
+			expectedMessage:  `{"secret":"projects/test-project/secrets/db-password","disabledVersions":["projects/test-project/secrets/db-password/versions/2","projects/test-project/secrets/db-password/versions/3"]}`,
+		},
+		{
+			name:   "custom label key",
+			values: &Values{Workload: "miner", LabelKey: "app", HookTopic: "rotate-secrets"},
+		},
+		{
+			name:   "dry run",
+			values: &Values{Workload: "miner", HookURL: "https://us-central1-test-project.cloudfunctions.net/rotate", DryRun: true},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			smStub := &stubs.SecretManagerStub{StubbedSecrets: secrets, StubbedVersions: versions}
+			hookStub := &stubs.RotationHookStub{StubbedResponse: []byte("new-value")}
+			psStub := &stubs.PubSubStub{}
+			values := tt.values
+			values.ProjectID = "test-project"
+			if err := Execute(ctx, values, &Services{
+				SecretManager: services.NewSecretManager(smStub),
+				RotationHook:  services.NewRotationHook(hookStub),
+				PubSub:        services.NewPubSub(psStub),
+			}); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(tt.expectedVersions, smStub.SavedSecretVersions); diff != "" {
+				t.Errorf("%s failed, added versions difference: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(tt.expectedDisabled, smStub.DisabledVersions); diff != "" {
+				t.Errorf("%s failed, disabled versions difference: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(tt.expectedHook, hookStub.SavedRequests); diff != "" {
+				t.Errorf("%s failed, hook requests difference: %+v", tt.name, diff)
+			}
+			message := ""
+			if psStub.PublishedMessage != nil {
+				message = string(psStub.PublishedMessage.Data)
+			}
+			if message != tt.expectedMessage {
+				t.Errorf("%s failed, got message:%q want:%q", tt.name, message, tt.expectedMessage)
+			}
+		})
+	}
+}
+
+func TestRotateSecretsWithoutHook(t *testing.T) {
+	err := Execute(context.Background(), &Values{ProjectID: "test-project", Workload: "miner"}, &Services{})
+	if err == nil {
+		t.Errorf("expected an error without a rotation hook")
+	}
+}
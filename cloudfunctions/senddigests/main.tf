@@ -0,0 +1,60 @@
+# Package automation contains the Cloud Function code to automate actions.
+
+# Copyright 2019 Google LLC
+
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+
+# 	https://www.apache.org/licenses/LICENSE-2.0
+
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+resource "google_cloudfunctions_function" "send_digests_function" {
+  name                  = "SendDigests"
+  description           = "Sends the notification digests whose window has passed."
+  runtime               = "go116"
+  available_memory_mb   = 128
+  source_archive_bucket = var.setup.gcf-bucket-name
+  source_archive_object = var.setup.gcf-object-name
+  timeout               = 60
+  project               = var.setup.automation-project
+  region                = var.setup.region
+  entry_point           = "SendDigests"
+  service_account_email = var.setup.automation-service-account
+
+  event_trigger {
+    event_type = "google.pubsub.topic.publish"
+    resource   = "threat-findings-send-digests"
+  }
+  environment_variables = {
+    GCP_PROJECT    = var.setup.automation-project
+    SRA_CONFIG_URI = var.setup.config-uri
+  }
+  timeouts {
+    create = "10m"
+    update = "10m"
+  }
+}
+
+# PubSub topic to trigger this function.
+resource "google_pubsub_topic" "topic" {
+  name    = "threat-findings-send-digests"
+  project = var.setup.automation-project
+}
+
+# Periodically sends the digests that are due.
+resource "google_cloud_scheduler_job" "send_digests_job" {
+  name     = "send-digests"
+  project  = var.setup.automation-project
+  region   = var.setup.region
+  schedule = var.schedule
+
+  pubsub_target {
+    topic_name = google_pubsub_topic.topic.id
+    data       = base64encode(jsonencode({}))
+  }
+}
@@ -0,0 +1,40 @@
+package senddigests
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// Values contains the required values needed for this function.
+type Values struct{}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Notifications *services.Notifications
+}
+
+// Execute sends the digests of the channels whose window has passed.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	if err := services.Notifications.SendDigests(ctx, time.Now()); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("sent due notification digests")
+	return nil
+}
@@ -0,0 +1,54 @@
+package senddigests
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+func TestSendDigests(t *testing.T) {
+	tests := []struct {
+		name     string
+		queued   time.Time
+		wantSent int
+	}{
+		{name: "window passed", queued: time.Now().Add(-time.Hour), wantSent: 1},
+		{name: "window not passed", queued: time.Now()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			webhookStub := &stubs.WebhookStub{}
+			notifications := services.NewDigestedNotifications(map[string]services.Notifier{
+				"webhook": services.NewWebhookNotifier(webhookStub),
+			}, services.NewDigests(&stubs.FirestoreStub{}, "automation-project", map[string]services.DigestConfig{"webhook": {Window: 15 * time.Minute}}))
+			n := &services.Notification{RemediationID: "r1", Time: tt.queued, Severity: "HIGH", Action: "CloseBucket", Outcome: services.AuditSuccess}
+			if err := notifications.Notify(ctx, []string{"webhook"}, n); err != nil {
+				t.Fatalf("%s failed to queue: %q", tt.name, err)
+			}
+			if err := Execute(ctx, &Values{}, &Services{Notifications: notifications}); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if len(webhookStub.SavedRequests) != tt.wantSent {
+				t.Errorf("%s failed: got %d digests want %d", tt.name, len(webhookStub.SavedRequests), tt.wantSent)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package enforceauthentication
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// findingAnnotation is the service annotation holding the ID of the finding that was remediated.
+const findingAnnotation = "security-response-automation.cloud.google.com/finding-id"
+
+// unauthenticatedMembers are removed from the invoker role, matching a service deployed with
+// --no-allow-unauthenticated.
+var unauthenticatedMembers = []string{"allUsers", "allAuthenticatedUsers"}
+
+// Values contains the required values needed for this function.
+type Values struct {
+	ProjectID string
+	// ResourceName is the Cloud Run service "projects/<p>/locations/<l>/services/<s>".
+	ResourceName string
+	FindingID    string
+	DryRun       bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Serverless *services.Serverless
+}
+
+// Execute requires callers of the Cloud Run service to authenticate and annotates the service with
+// the finding ID so the change can be traced back to the finding.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	if !strings.Contains(values.ResourceName, "/services/") {
+		return fmt.Errorf("unsupported resource %q", values.ResourceName)
+	}
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have required authentication on service %q in project %q", values.ResourceName, values.ProjectID)
+		return nil
+	}
+	removed, err := services.Serverless.RemoveServiceInvokers(ctx, values.ResourceName, unauthenticatedMembers)
+	if err != nil {
+		return err
+	}
+	if len(removed) == 0 {
+		logging.FromContext(ctx).Info("service %q already requires authentication", values.ResourceName)
+		return nil
+	}
+	logging.FromContext(ctx).Info("removed %q from invoker role of %q in project %q", removed, values.ResourceName, values.ProjectID)
+	if values.FindingID == "" {
+		return nil
+	}
+	if err := services.Serverless.AnnotateService(ctx, values.ResourceName, map[string]string{findingAnnotation: values.FindingID}); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("annotated service %q with finding %q", values.ResourceName, values.FindingID)
+	return nil
+}
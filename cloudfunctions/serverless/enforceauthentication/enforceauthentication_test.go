@@ -0,0 +1,104 @@
+package enforceauthentication
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	run "google.golang.org/api/run/v1"
+)
+
+func TestEnforceAuthentication(t *testing.T) {
+	const resourceName = "projects/test-project/locations/us-central1/services/public-service"
+	ctx := context.Background()
+	test := []struct {
+		name                  string
+		resourceName          string
+		dryRun                bool
+		servicePolicy         *run.Policy
+		expectedServicePolicy *run.Policy
+		expectedService       *run.Service
+		expectedError         bool
+	}{
+		{
+			name:         "require authentication",
+			resourceName: resourceName,
+			servicePolicy: &run.Policy{Bindings: []*run.Binding{
+				{Role: "roles/run.invoker", Members: []string{"allUsers", "allAuthenticatedUsers", "serviceAccount:caller@test-project.iam.gserviceaccount.com"}},
+			}},
+			expectedServicePolicy: &run.Policy{Bindings: []*run.Binding{
+				{Role: "roles/run.invoker", Members: []string{"serviceAccount:caller@test-project.iam.gserviceaccount.com"}},
+			}},
+			expectedService: &run.Service{Metadata: &run.ObjectMeta{
+				Name: "public-service",
+				Annotations: map[string]string{
+					"run.googleapis.com/ingress":                               "all",
+					"security-response-automation.cloud.google.com/finding-id": "5e1a7b3c9d2f4e6a8b0c1d2e3f4a5b6c",
+				},
+			}},
+		},
+		{
+			name:         "already requires authentication",
+			resourceName: resourceName,
+			servicePolicy: &run.Policy{Bindings: []*run.Binding{
+				{Role: "roles/run.invoker", Members: []string{"serviceAccount:caller@test-project.iam.gserviceaccount.com"}},
+			}},
+		},
+		{
+			name:         "dry run",
+			resourceName: resourceName,
+			dryRun:       true,
+		},
+		{
+			name:          "cloud function",
+			resourceName:  "projects/test-project/locations/us-central1/functions/public-function",
+			expectedError: true,
+		},
+	}
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			runStub := &stubs.CloudRunStub{
+				GetPolicyResponse: tt.servicePolicy,
+				GetServiceResponse: &run.Service{Metadata: &run.ObjectMeta{
+					Name:        "public-service",
+					Annotations: map[string]string{"run.googleapis.com/ingress": "all"},
+				}},
+			}
+			svcs := &Services{
+				Serverless: services.NewServerless(&stubs.CloudFunctionsStub{}, runStub),
+			}
+			values := &Values{
+				ProjectID:    "test-project",
+				ResourceName: tt.resourceName,
+				FindingID:    "5e1a7b3c9d2f4e6a8b0c1d2e3f4a5b6c",
+				DryRun:       tt.dryRun,
+			}
+			err := Execute(ctx, values, svcs)
+			if (err != nil) != tt.expectedError {
+				t.Errorf("%s test failed, unexpected error result: %v", tt.name, err)
+			}
+			if diff := cmp.Diff(runStub.SavedSetPolicy, tt.expectedServicePolicy); diff != "" {
+				t.Errorf("%v failed, difference in service policy: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(runStub.SavedService, tt.expectedService); diff != "" {
+				t.Errorf("%v failed, difference in service: %+v", tt.name, diff)
+			}
+		})
+	}
+}
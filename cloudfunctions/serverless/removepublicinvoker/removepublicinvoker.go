@@ -0,0 +1,69 @@
+package removepublicinvoker
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// publicMembers are removed from the invoker role.
+var publicMembers = []string{"allUsers"}
+
+// Values contains the required values needed for this function.
+type Values struct {
+	ProjectID string
+	// ResourceName is the Cloud Function "projects/<p>/locations/<l>/functions/<f>" or
+	// Cloud Run service "projects/<p>/locations/<l>/services/<s>".
+	ResourceName string
+	DryRun       bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Serverless *services.Serverless
+	Resource   *services.Resource
+}
+
+// Execute removes allUsers from the invoker role of the Cloud Function or Cloud Run service.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have removed %q from invoker role of %q", publicMembers, values.ResourceName)
+		return nil
+	}
+	var removed []string
+	var err error
+	switch {
+	case strings.Contains(values.ResourceName, "/functions/"):
+		removed, err = services.Serverless.RemoveFunctionInvokers(ctx, values.ResourceName, publicMembers)
+	case strings.Contains(values.ResourceName, "/services/"):
+		removed, err = services.Serverless.RemoveServiceInvokers(ctx, values.ResourceName, publicMembers)
+	default:
+		return fmt.Errorf("unsupported resource %q", values.ResourceName)
+	}
+	if err != nil {
+		return err
+	}
+	if len(removed) == 0 {
+		logging.FromContext(ctx).Info("%q is not publicly invokable", values.ResourceName)
+		return nil
+	}
+	logging.FromContext(ctx).Info("removed %q from invoker role of %q in project %q", removed, values.ResourceName, values.ProjectID)
+	return nil
+}
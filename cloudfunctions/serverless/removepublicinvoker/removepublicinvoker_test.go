@@ -0,0 +1,112 @@
+package removepublicinvoker
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	cloudfunctions "google.golang.org/api/cloudfunctions/v1"
+	run "google.golang.org/api/run/v1"
+)
+
+func TestRemovePublicInvoker(t *testing.T) {
+	ctx := context.Background()
+	test := []struct {
+		name                   string
+		resourceName           string
+		dryRun                 bool
+		functionPolicy         *cloudfunctions.Policy
+		servicePolicy          *run.Policy
+		expectedFunctionPolicy *cloudfunctions.Policy
+		expectedServicePolicy  *run.Policy
+		expectedError          bool
+	}{
+		{
+			name:         "remove allUsers from function",
+			resourceName: "projects/test-project/locations/us-central1/functions/public-function",
+			functionPolicy: &cloudfunctions.Policy{Bindings: []*cloudfunctions.Binding{
+				{Role: "roles/cloudfunctions.invoker", Members: []string{"allUsers", "user:tom@example.com"}},
+				{Role: "roles/cloudfunctions.viewer", Members: []string{"allUsers"}},
+			}},
+			expectedFunctionPolicy: &cloudfunctions.Policy{Bindings: []*cloudfunctions.Binding{
+				{Role: "roles/cloudfunctions.invoker", Members: []string{"user:tom@example.com"}},
+				{Role: "roles/cloudfunctions.viewer", Members: []string{"allUsers"}},
+			}},
+		},
+		{
+			name:         "remove allUsers from cloud run service",
+			resourceName: "projects/test-project/locations/us-central1/services/public-service",
+			servicePolicy: &run.Policy{Bindings: []*run.Binding{
+				{Role: "roles/run.invoker", Members: []string{"allUsers"}},
+			}},
+			expectedServicePolicy: &run.Policy{Bindings: []*run.Binding{
+				{Role: "roles/run.invoker", Members: []string{}},
+			}},
+		},
+		{
+			name:         "service not public",
+			resourceName: "projects/test-project/locations/us-central1/services/private-service",
+			servicePolicy: &run.Policy{Bindings: []*run.Binding{
+				{Role: "roles/run.invoker", Members: []string{"serviceAccount:caller@test-project.iam.gserviceaccount.com"}},
+			}},
+		},
+		{
+			name:         "dry run",
+			resourceName: "projects/test-project/locations/us-central1/functions/public-function",
+			dryRun:       true,
+		},
+		{
+			name:          "unsupported resource",
+			resourceName:  "projects/test-project/instances/public-sql-instance",
+			expectedError: true,
+		},
+	}
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			svcs, cfStub, runStub := removePublicInvokerSetup()
+			cfStub.GetPolicyResponse = tt.functionPolicy
+			runStub.GetPolicyResponse = tt.servicePolicy
+			values := &Values{
+				ProjectID:    "test-project",
+				ResourceName: tt.resourceName,
+				DryRun:       tt.dryRun,
+			}
+			err := Execute(ctx, values, svcs)
+			if (err != nil) != tt.expectedError {
+				t.Errorf("%s test failed, unexpected error result: %v", tt.name, err)
+			}
+			if diff := cmp.Diff(cfStub.SavedSetPolicy, tt.expectedFunctionPolicy); diff != "" {
+				t.Errorf("%v failed, difference in function policy: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(runStub.SavedSetPolicy, tt.expectedServicePolicy); diff != "" {
+				t.Errorf("%v failed, difference in service policy: %+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func removePublicInvokerSetup() (*Services, *stubs.CloudFunctionsStub, *stubs.CloudRunStub) {
+	cfStub := &stubs.CloudFunctionsStub{}
+	runStub := &stubs.CloudRunStub{}
+	serverless := services.NewServerless(cfStub, runStub)
+	crmStub := &stubs.ResourceManagerStub{}
+	storageStub := &stubs.StorageStub{}
+	res := services.NewResource(crmStub, storageStub)
+	return &Services{Serverless: serverless, Resource: res}, cfStub, runStub
+}
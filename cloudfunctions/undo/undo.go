@@ -0,0 +1,58 @@
+package undo
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/pkg/errors"
+)
+
+// Values contains the required values needed for this function.
+type Values struct {
+	// RemediationID is the ID of the remediation to undo, as recorded in the audit trail.
+	RemediationID string
+	DryRun        bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Undo *services.Undo
+}
+
+// Execute restores the resources changed by the remediation to their prior state.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	if values.RemediationID == "" {
+		return errors.New("no remediation ID to undo")
+	}
+	states, err := services.Undo.States(ctx, values.RemediationID)
+	if err != nil {
+		return err
+	}
+	// Restore in reverse so a resource changed more than once ends up in its earliest state.
+	for i := len(states) - 1; i >= 0; i-- {
+		state := states[i]
+		if values.DryRun {
+			logging.FromContext(ctx).Info("dry_run on, would have restored %s %q changed by remediation %q", state.Kind, state.Resource, values.RemediationID)
+			continue
+		}
+		if err := services.Undo.Restore(ctx, state); err != nil {
+			return errors.Wrapf(err, "failed to undo remediation %q", values.RemediationID)
+		}
+		logging.FromContext(ctx).Info("restored %s %q changed by remediation %q", state.Kind, state.Resource, values.RemediationID)
+	}
+	return nil
+}
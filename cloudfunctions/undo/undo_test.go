@@ -0,0 +1,151 @@
+package undo
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	crm "google.golang.org/api/cloudresourcemanager/v1"
+	compute "google.golang.org/api/compute/v1"
+)
+
+func TestUndoPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		dryRun   bool
+		expected *crm.Policy
+	}{
+		{
+			name: "restore prior policy",
+			expected: &crm.Policy{
+				Bindings: []*crm.Binding{{Role: "roles/editor", Members: []string{"user:ddgo@cloudorg.com", "user:bob@gmail.com"}}},
+				Etag:     "current",
+			},
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crmStub := &stubs.ResourceManagerStub{
+				GetPolicyResponse: &crm.Policy{
+					Bindings: []*crm.Binding{{Role: "roles/editor", Members: []string{"user:ddgo@cloudorg.com", "user:bob@gmail.com"}}},
+					Etag:     "current",
+				},
+			}
+			fsStub := &stubs.FirestoreStub{}
+			remediate(t, fsStub, func(ctx context.Context) error {
+				r := services.NewResource(crmStub, &stubs.StorageStub{})
+				_, err := r.ProjectOnlyKeepUsersFromDomains(ctx, "test-project", []string{"cloudorg.com"}, services.KeepOptions{})
+				return err
+			})
+			crmStub.SavedSetPolicy = nil
+			if err := Execute(context.Background(), &Values{RemediationID: "remediation-1", DryRun: tt.dryRun}, &Services{
+				Undo: services.NewUndo(fsStub, crmStub, &stubs.ComputeStub{}, "automation-project"),
+			}); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(tt.expected, crmStub.SavedSetPolicy); diff != "" {
+				t.Errorf("%s failed (-want +got):\n%s", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestUndoFirewall(t *testing.T) {
+	rule := func() *compute.Firewall {
+		return &compute.Firewall{Id: 123, Name: "allow-ssh", Network: "default", SourceRanges: []string{"0.0.0.0/0"}}
+	}
+	tests := []struct {
+		name         string
+		remediate    func(context.Context, *services.Firewall) error
+		deleted      bool
+		wantInserted *compute.Firewall
+		wantPatched  *compute.Firewall
+	}{
+		{
+			name: "disabled rule is enabled",
+			remediate: func(ctx context.Context, fw *services.Firewall) error {
+				_, err := fw.DisableFirewallRule(ctx, "test-project", "allow-ssh", "allow-ssh")
+				return err
+			},
+			wantPatched: &compute.Firewall{Name: "allow-ssh", Network: "default", SourceRanges: []string{"0.0.0.0/0"}, ForceSendFields: []string{"Disabled", "SourceRanges"}},
+		},
+		{
+			name: "deleted rule is recreated",
+			remediate: func(ctx context.Context, fw *services.Firewall) error {
+				_, err := fw.DeleteFirewallRule(ctx, "test-project", "allow-ssh")
+				return err
+			},
+			deleted:      true,
+			wantInserted: &compute.Firewall{Name: "allow-ssh", Network: "default", SourceRanges: []string{"0.0.0.0/0"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			computeStub := &stubs.ComputeStub{StubbedFirewall: rule()}
+			fsStub := &stubs.FirestoreStub{}
+			remediate(t, fsStub, func(ctx context.Context) error {
+				return tt.remediate(ctx, services.NewFirewall(computeStub))
+			})
+			computeStub.SavedFirewallRule = nil
+			computeStub.FirewallRuleNotFound = tt.deleted
+			if err := Execute(context.Background(), &Values{RemediationID: "remediation-1"}, &Services{
+				Undo: services.NewUndo(fsStub, &stubs.ResourceManagerStub{}, computeStub, "automation-project"),
+			}); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			var inserted *compute.Firewall
+			if len(computeStub.InsertedFirewallRules) > 0 {
+				inserted = computeStub.InsertedFirewallRules[0]
+			}
+			if diff := cmp.Diff(tt.wantInserted, inserted); diff != "" {
+				t.Errorf("%s failed, inserted (-want +got):\n%s", tt.name, diff)
+			}
+			if tt.wantPatched != nil {
+				if diff := cmp.Diff(tt.wantPatched, computeStub.SavedFirewallRule); diff != "" {
+					t.Errorf("%s failed, patched (-want +got):\n%s", tt.name, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestUndoUnknownRemediation(t *testing.T) {
+	err := Execute(context.Background(), &Values{RemediationID: "unknown"}, &Services{
+		Undo: services.NewUndo(&stubs.FirestoreStub{}, &stubs.ResourceManagerStub{}, &stubs.ComputeStub{}, "automation-project"),
+	})
+	if err == nil {
+		t.Fatalf("expected an error undoing an unknown remediation")
+	}
+}
+
+// remediate runs the remediation as audited by the automation and records it as "remediation-1".
+func remediate(t *testing.T, fsStub *stubs.FirestoreStub, run func(context.Context) error) {
+	record := &services.AuditRecord{ID: "remediation-1", Time: time.Now(), Action: "test"}
+	if err := run(services.WithAudit(context.Background(), record)); err != nil {
+		t.Fatalf("failed to remediate: %q", err)
+	}
+	audit := services.NewAudit(&stubs.BigQueryStub{}, fsStub, "automation-project", "")
+	if err := audit.Record(context.Background(), record); err != nil {
+		t.Fatalf("failed to record remediation: %q", err)
+	}
+}
@@ -0,0 +1,71 @@
+package enforcereenrollment
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/pkg/errors"
+)
+
+// Values contains the required values needed for this function.
+type Values struct {
+	UserEmail string
+	// OrgUnits are the organizational units whose users may be forced to re-enroll.
+	OrgUnits []string
+	// EnrollmentOrgUnit is the organizational unit enforcing 2-Step Verification users not yet
+	// enrolled are moved to.
+	EnrollmentOrgUnit string
+	DryRun            bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Admin *services.Admin
+}
+
+// Execute forces a user whose password leaked to change it at next sign-in and, if they are not
+// already enrolled in or enforced to use 2-Step Verification, moves them to the enrollment
+// organizational unit.
+//
+// Only users within one of the allowed organizational units are changed.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	user, err := services.Admin.User(ctx, values.UserEmail)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get user %q", values.UserEmail)
+	}
+	if !services.Admin.InOrgUnits(user, values.OrgUnits) {
+		logging.FromContext(ctx).Info("user %q in organizational unit %q is not within the allowed organizational units, skipping.", values.UserEmail, user.OrgUnitPath)
+		return nil
+	}
+	orgUnit := ""
+	if !user.IsEnrolledIn2Sv && !user.IsEnforcedIn2Sv {
+		orgUnit = values.EnrollmentOrgUnit
+	}
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have required user %q to change password and moved them to organizational unit %q.", values.UserEmail, orgUnit)
+		return nil
+	}
+	if err := services.Admin.ForceReenrollment(ctx, values.UserEmail, orgUnit); err != nil {
+		return errors.Wrapf(err, "failed to force re-enrollment of user %q", values.UserEmail)
+	}
+	logging.FromContext(ctx).Info("required user %q to change password at next sign-in and signed them out.", values.UserEmail)
+	if orgUnit != "" {
+		logging.FromContext(ctx).Info("moved user %q from organizational unit %q to %q to enforce 2-step verification.", values.UserEmail, user.OrgUnitPath, orgUnit)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package enforcereenrollment
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestEnforceReenrollment(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name              string
+		user              *admin.User
+		orgUnits          []string
+		dryRun            bool
+		expectedUpdate    *admin.User
+		expectedSignedOut []string
+	}{
+		{
+			name:              "not enrolled in 2sv",
+			user:              &admin.User{PrimaryEmail: "bob@example.com", OrgUnitPath: "/Engineering/Contractors"},
+			orgUnits:          []string{"/Engineering"},
+			expectedUpdate:    &admin.User{ChangePasswordAtNextLogin: true, OrgUnitPath: "/2SV Enforced"},
+			expectedSignedOut: []string{"bob@example.com"},
+		},
+		{
+			name:              "already enrolled in 2sv",
+			user:              &admin.User{PrimaryEmail: "bob@example.com", OrgUnitPath: "/Engineering", IsEnrolledIn2Sv: true},
+			orgUnits:          []string{"/Engineering"},
+			expectedUpdate:    &admin.User{ChangePasswordAtNextLogin: true},
+			expectedSignedOut: []string{"bob@example.com"},
+		},
+		{
+			name:     "user outside allowed org units",
+			user:     &admin.User{PrimaryEmail: "bob@example.com", OrgUnitPath: "/Sales"},
+			orgUnits: []string{"/Engineering"},
+		},
+		{
+			name: "no allowed org units",
+			user: &admin.User{PrimaryEmail: "bob@example.com", OrgUnitPath: "/Engineering"},
+		},
+		{
+			name:     "dry run",
+			user:     &admin.User{PrimaryEmail: "bob@example.com", OrgUnitPath: "/Engineering"},
+			orgUnits: []string{"/"},
+			dryRun:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adminStub := &stubs.AdminStub{StubbedUser: tt.user}
+			values := &Values{
+				UserEmail:         "bob@example.com",
+				OrgUnits:          tt.orgUnits,
+				EnrollmentOrgUnit: "/2SV Enforced",
+				DryRun:            tt.dryRun,
+			}
+			if err := Execute(ctx, values, &Services{
+				Admin: services.NewAdmin(adminStub),
+			}); err != nil {
+				t.Fatalf("%s failed exp:%v got:%v", tt.name, nil, err)
+			}
+			if diff := cmp.Diff(tt.expectedUpdate, adminStub.SavedUpdate); diff != "" {
+				t.Errorf("%v failed (-want +got):\n%s", tt.name, diff)
+			}
+			if diff := cmp.Diff(tt.expectedSignedOut, adminStub.SignedOut); diff != "" {
+				t.Errorf("%v failed (-want +got):\n%s", tt.name, diff)
+			}
+		})
+	}
+}
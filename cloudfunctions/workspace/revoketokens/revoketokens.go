@@ -0,0 +1,61 @@
+package revoketokens
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/pkg/errors"
+)
+
+// Values contains the required values needed for this function.
+type Values struct {
+	UserEmail string
+	DryRun    bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Admin *services.Admin
+}
+
+// Execute revokes the OAuth 2.0 tokens and application-specific passwords of a Google Workspace user.
+//
+// Unlike suspending the user this leaves their account usable, only sessions held by applications end.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	if values.DryRun {
+		tokens, err := services.Admin.Tokens(ctx, values.UserEmail)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get tokens of %q", values.UserEmail)
+		}
+		asps, err := services.Admin.ASPs(ctx, values.UserEmail)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get application-specific passwords of %q", values.UserEmail)
+		}
+		logging.FromContext(ctx).Info("dry_run on, would have revoked %d tokens and %d application-specific passwords of user %q.", len(tokens), len(asps), values.UserEmail)
+		return nil
+	}
+	tokens, err := services.Admin.RevokeTokens(ctx, values.UserEmail)
+	if err != nil {
+		return errors.Wrapf(err, "failed to revoke tokens of %q", values.UserEmail)
+	}
+	asps, err := services.Admin.RevokeASPs(ctx, values.UserEmail)
+	if err != nil {
+		return errors.Wrapf(err, "failed to revoke application-specific passwords of %q", values.UserEmail)
+	}
+	logging.FromContext(ctx).Info("revoked tokens issued to %q and application-specific passwords %q of user %q.", tokens, asps, values.UserEmail)
+	return nil
+}
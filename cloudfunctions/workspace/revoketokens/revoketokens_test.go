@@ -0,0 +1,70 @@
+package revoketokens
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestRevokeTokens(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name           string
+		dryRun         bool
+		expectedTokens []string
+		expectedASPs   []int64
+	}{
+		{
+			name:           "revoke tokens and passwords",
+			expectedTokens: []string{"client-1", "client-2"},
+			expectedASPs:   []int64{42},
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adminStub := &stubs.AdminStub{
+				StubbedTokens: []*admin.Token{
+					{ClientId: "client-1", DisplayText: "Mail client"},
+					{ClientId: "client-2", DisplayText: "Calendar sync"},
+				},
+				StubbedASPs: []*admin.Asp{{CodeId: 42, Name: "old phone"}},
+			}
+			values := &Values{UserEmail: "bob@example.com", DryRun: tt.dryRun}
+			if err := Execute(ctx, values, &Services{
+				Admin: services.NewAdmin(adminStub),
+			}); err != nil {
+				t.Fatalf("%s failed exp:%v got:%v", tt.name, nil, err)
+			}
+			if diff := cmp.Diff(tt.expectedTokens, adminStub.DeletedTokens); diff != "" {
+				t.Errorf("%v failed (-want +got):\n%s", tt.name, diff)
+			}
+			if diff := cmp.Diff(tt.expectedASPs, adminStub.DeletedASPs); diff != "" {
+				t.Errorf("%v failed (-want +got):\n%s", tt.name, diff)
+			}
+			if len(adminStub.SignedOut) != 0 || adminStub.SavedUpdate != nil {
+				t.Errorf("%v failed, user should not be signed out or updated", tt.name)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package suspenduser
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/pkg/errors"
+)
+
+// Values contains the required values needed for this function.
+type Values struct {
+	UserEmail string
+	// OrgUnits are the organizational units whose users may be suspended.
+	OrgUnits []string
+	DryRun   bool
+}
+
+// Services contains the services needed for this function.
+type Services struct {
+	Admin *services.Admin
+}
+
+// Execute suspends a compromised Google Workspace user and revokes their sign-in cookies.
+//
+// Only users within one of the allowed organizational units are suspended.
+func Execute(ctx context.Context, values *Values, services *Services) error {
+	user, err := services.Admin.User(ctx, values.UserEmail)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get user %q", values.UserEmail)
+	}
+	if !services.Admin.InOrgUnits(user, values.OrgUnits) {
+		logging.FromContext(ctx).Info("user %q in organizational unit %q is not within the allowed organizational units, skipping.", values.UserEmail, user.OrgUnitPath)
+		return nil
+	}
+	if user.Suspended {
+		logging.FromContext(ctx).Info("user %q is already suspended.", values.UserEmail)
+		return nil
+	}
+	if values.DryRun {
+		logging.FromContext(ctx).Info("dry_run on, would have suspended user %q in organizational unit %q.", values.UserEmail, user.OrgUnitPath)
+		return nil
+	}
+	if err := services.Admin.SuspendUser(ctx, values.UserEmail); err != nil {
+		return errors.Wrapf(err, "failed to suspend user %q", values.UserEmail)
+	}
+	logging.FromContext(ctx).Info("suspended user %q in organizational unit %q and revoked their sign-in cookies.", values.UserEmail, user.OrgUnitPath)
+	return nil
+}
@@ -0,0 +1,139 @@
+// Command simulate fabricates findings of the rules supported by the router, so teams can rehearse
+// their response to them without waiting for a real incident.
+//
+//	simulate list
+//
+// prints the rules that can be simulated.
+//
+//	simulate generate public_bucket_acl --organization 123456789012 --project test-project
+//
+// prints the Security Command Center notification of a finding, which can be piped to the sra
+// command to run the automations locally:
+//
+//	simulate generate ... | sra route --finding -
+//
+// Fields are overridden by their path with --set, such as
+// --set finding.resourceName=//storage.googleapis.com/my-bucket.
+//
+//	simulate publish public_bucket_acl --organization 123456789012 --project test-project
+//
+// publishes the finding to the topic of the Router Cloud Function in the automation project set by
+// GCP_PROJECT, as Security Command Center would. The deployed automations run against the resources
+// of the finding, so rehearse in dry run or against resources made for it.
+package main
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/googlecloudplatform/security-response-automation/simulate"
+)
+
+// findingsTopic is the topic Security Command Center publishes findings to for the router.
+const findingsTopic = "threat-findings"
+
+// overrides are the fields set with --set path=value.
+type overrides map[string]string
+
+func (o overrides) String() string {
+	return fmt.Sprint(map[string]string(o))
+}
+
+func (o overrides) Set(s string) error {
+	i := strings.Index(s, "=")
+	if i < 1 {
+		return fmt.Errorf("%q isn't path=value", s)
+	}
+	o[s[:i]] = s[i+1:]
+	return nil
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	ctx := context.Background()
+	var err error
+	switch os.Args[1] {
+	case "list":
+		for _, rule := range simulate.Categories() {
+			fmt.Println(rule)
+		}
+	case "generate":
+		var b []byte
+		if b, err = generate(os.Args[2:]); err == nil {
+			fmt.Printf("%s\n", b)
+		}
+	case "publish":
+		err = publish(ctx, os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "simulate: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: simulate list")
+	fmt.Fprintln(os.Stderr, "       simulate generate <rule> --organization <id> --project <id> [--set <path>=<value>]...")
+	fmt.Fprintln(os.Stderr, "       simulate publish <rule> --organization <id> --project <id> [--set <path>=<value>]...")
+	os.Exit(2)
+}
+
+// generate returns the notification of the finding of the rule in the arguments.
+func generate(args []string) ([]byte, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		usage()
+	}
+	o := simulate.Options{Overrides: overrides{}}
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	fs.StringVar(&o.Organization, "organization", "", "numeric ID of the organization reporting the finding")
+	fs.StringVar(&o.Project, "project", "", "ID of the project of the finding's resource")
+	fs.StringVar(&o.ID, "id", "", "ID of the finding, random if not set")
+	fs.Var(overrides(o.Overrides), "set", "field of the notification to set, by its dot separated path")
+	fs.Parse(args[1:])
+	return simulate.Finding(args[0], o)
+}
+
+// publish publishes the finding of the rule in the arguments to the router.
+func publish(ctx context.Context, args []string) error {
+	projectID := os.Getenv("GCP_PROJECT")
+	if projectID == "" {
+		return fmt.Errorf("GCP_PROJECT must be set to the automation project")
+	}
+	b, err := generate(args)
+	if err != nil {
+		return err
+	}
+	ps, err := services.InitPubSub(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	id, err := ps.Publish(ctx, findingsTopic, &pubsub.Message{Data: b})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("published simulated %s finding as message %s\n", args[0], id)
+	return nil
+}
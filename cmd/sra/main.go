@@ -0,0 +1,279 @@
+// Command sra runs automations locally against a finding read from a JSON file, so automations can
+// be developed and debugged without deploying them.
+//
+// The finding is routed with the local configuration as the Router Cloud Function would route it,
+// but the messages the router publishes are kept instead of sent to Pub/Sub.
+//
+//	sra route --finding finding.json
+//
+// prints the automations the finding triggers and the values published to each of them. With
+// --finding - the finding is read from stdin, such as a finding made by the simulate command.
+//
+//	sra run remove-non-org-members --finding finding.json --dry-run
+//
+// routes the finding, then runs the automation through the entry point of its Cloud Function with
+// the values the router published for it. The router and the automation use real clients with the
+// application default credentials, GCP_PROJECT must be set to the automation project. With
+// --dry-run every automation runs in dry run, only logging the changes it would have made.
+package main
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"cloud.google.com/go/pubsub"
+	exec "github.com/googlecloudplatform/security-response-automation"
+	"github.com/googlecloudplatform/security-response-automation/clients"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/router"
+	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/pkg/errors"
+	crm "google.golang.org/genproto/googleapis/cloud/securitycenter/v1beta1"
+)
+
+// entryPoints maps the topics the router publishes to the entry points of the Cloud Functions
+// subscribed to them.
+var entryPoints = map[string]func(context.Context, pubsub.Message) error{
+	"threat-findings-block-project-ssh-keys":     exec.BlockProjectSSHKeys,
+	"threat-findings-close-bucket":               exec.CloseBucket,
+	"threat-findings-close-public-dataset":       exec.ClosePublicDataset,
+	"threat-findings-create-disk-snapshot":       exec.SnapshotDisk,
+	"threat-findings-delete-firewall-rules":      exec.DeleteFirewallRules,
+	"threat-findings-delete-pod":                 exec.DeletePod,
+	"threat-findings-disable-billing":            exec.DisableBilling,
+	"threat-findings-disable-dashboard":          exec.DisableDashboard,
+	"threat-findings-disable-old-keys":           exec.DisableOldKeys,
+	"threat-findings-disable-serial-port":        exec.DisableSerialPort,
+	"threat-findings-drain-node":                 exec.DrainNode,
+	"threat-findings-enable-audit-logs":          exec.EnableAuditLogs,
+	"threat-findings-enable-authorized-networks": exec.EnableAuthorizedNetworks,
+	"threat-findings-enable-bucket-only-policy":  exec.EnableBucketOnlyPolicy,
+	"threat-findings-enable-dnssec":              exec.EnableDNSSEC,
+	"threat-findings-enable-flow-logs":           exec.EnableFlowLogs,
+	"threat-findings-enable-os-login":            exec.EnableOSLogin,
+	"threat-findings-enable-private-access":      exec.EnablePrivateAccess,
+	"threat-findings-enable-shielded-vm":         exec.EnableShieldedVM,
+	"threat-findings-enforce-authentication":     exec.EnforceAuthentication,
+	"threat-findings-enforce-reenrollment":       exec.EnforceReenrollment,
+	"threat-findings-iam-remove-default-editor":  exec.IAMRemoveDefaultEditor,
+	"threat-findings-iam-revoke":                 exec.IAMRevoke,
+	"threat-findings-iam-revoke-grants":          exec.IAMRevokeGrants,
+	"threat-findings-open-firewall":              exec.OpenFirewall,
+	"threat-findings-quarantine-image":           exec.QuarantineImage,
+	"threat-findings-quarantine-instance":        exec.QuarantineInstance,
+	"threat-findings-remove-external-exposure":   exec.RemoveExternalExposure,
+	"threat-findings-remove-impersonation":       exec.RemoveImpersonation,
+	"threat-findings-remove-non-org-members":     exec.RemoveNonOrganizationMembers,
+	"threat-findings-remove-public-invoker":      exec.RemovePublicInvoker,
+	"threat-findings-remove-public-ip":           exec.RemovePublicIP,
+	"threat-findings-remove-public-kms":          exec.RemovePublicKMS,
+	"threat-findings-remove-public-pubsub":       exec.RemovePublicPubSub,
+	"threat-findings-remove-public-repository":   exec.RemovePublicRepository,
+	"threat-findings-remove-public-sql":          exec.CloseCloudSQL,
+	"threat-findings-require-ssl":                exec.CloudSQLRequireSSL,
+	"threat-findings-restore-iam-policy":         exec.RestoreIAMPolicy,
+	"threat-findings-restrict-api-key":           exec.RestrictAPIKey,
+	"threat-findings-revoke-user-tokens":         exec.RevokeUserTokens,
+	"threat-findings-rotate-secrets":             exec.RotateSecrets,
+	"threat-findings-stop-rogue-job":             exec.StopRogueJob,
+	"threat-findings-suspend-user":               exec.SuspendUser,
+	"threat-findings-update-password":            exec.UpdatePassword,
+}
+
+// published keeps the messages the router publishes instead of sending them.
+type published struct {
+	messages []*pubsub.Message
+}
+
+// Topic returns no topic, the topic of a message is in its attributes.
+func (p *published) Topic(id string) *pubsub.Topic {
+	return nil
+}
+
+// Publish keeps the message.
+func (p *published) Publish(ctx context.Context, topic *pubsub.Topic, m *pubsub.Message) (string, error) {
+	p.messages = append(p.messages, m)
+	return "", nil
+}
+
+// unmarked reads findings from Security Command Center but doesn't mark them, so findings routed
+// locally are still routed by the Router Cloud Function.
+type unmarked struct {
+	services.CommandCenterClient
+}
+
+// AddSecurityMarks returns the marks without adding them.
+func (u *unmarked) AddSecurityMarks(ctx context.Context, request *crm.UpdateSecurityMarksRequest) (*crm.SecurityMarks, error) {
+	return request.GetSecurityMarks(), nil
+}
+
+// SetFindingState returns the finding without changing its state.
+func (u *unmarked) SetFindingState(ctx context.Context, request *crm.SetFindingStateRequest) (*crm.Finding, error) {
+	return &crm.Finding{Name: request.GetName(), State: request.GetState()}, nil
+}
+
+// options are the flags of the commands.
+type options struct {
+	finding string
+	config  string
+	dryRun  bool
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	ctx := context.Background()
+	var err error
+	switch os.Args[1] {
+	case "route":
+		err = routeCommand(ctx, os.Args[2:])
+	case "run":
+		err = runCommand(ctx, os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sra: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: sra route --finding <file> [--config <file>] [--dry-run]")
+	fmt.Fprintln(os.Stderr, "       sra run <automation> --finding <file> [--config <file>] [--dry-run]")
+	os.Exit(2)
+}
+
+func parseFlags(name string, args []string) *options {
+	o := &options{}
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.StringVar(&o.finding, "finding", "", "JSON file of the finding, as published by Security Command Center or a log sink, - for stdin")
+	fs.StringVar(&o.config, "config", "config/sra.yaml", "configuration of the router and automations")
+	fs.BoolVar(&o.dryRun, "dry-run", false, "run every automation in dry run")
+	fs.Parse(args)
+	if o.finding == "" {
+		usage()
+	}
+	return o
+}
+
+// routeCommand prints the messages the router publishes for the finding.
+func routeCommand(ctx context.Context, args []string) error {
+	messages, err := route(ctx, parseFlags("route", args))
+	if err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		fmt.Println("the finding triggers no automations")
+	}
+	for _, m := range messages {
+		var values interface{}
+		if err := json.Unmarshal(m.Data, &values); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal values published to %q", m.Attributes["topic"])
+		}
+		b, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n%s\n", m.Attributes["topic"], b)
+	}
+	return nil
+}
+
+// runCommand routes the finding and runs the automation with the values published for it.
+func runCommand(ctx context.Context, args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		usage()
+	}
+	// Automations are named after their action, such as remove-non-org-members.
+	action := strings.Replace(args[0], "-", "_", -1)
+	topic, ok := router.Topic(action)
+	if !ok {
+		return fmt.Errorf("unknown automation %q", args[0])
+	}
+	o := parseFlags("run", args[1:])
+	messages, err := route(ctx, o)
+	if err != nil {
+		return err
+	}
+	for _, m := range messages {
+		if m.Attributes["topic"] != topic {
+			continue
+		}
+		attributes := map[string]string{}
+		for k, v := range m.Attributes {
+			attributes[k] = v
+		}
+		// Local runs are not replayed if they fail, don't notify and may run again for the
+		// same finding.
+		delete(attributes, "topic")
+		delete(attributes, "notify")
+		delete(attributes, "event_time")
+		return entryPoints[topic](ctx, pubsub.Message{Data: m.Data, Attributes: attributes})
+	}
+	return fmt.Errorf("the finding doesn't trigger %q, check the automations of its rule in %s", args[0], o.config)
+}
+
+// route routes the finding with the configuration, returning the messages the router published.
+func route(ctx context.Context, o *options) ([]*pubsub.Message, error) {
+	var b []byte
+	var err error
+	if o.finding == "-" {
+		b, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		b, err = ioutil.ReadFile(o.finding)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The automations read the same configuration as the router.
+	if err := os.Setenv("SRA_CONFIG_PATH", o.config); err != nil {
+		return nil, err
+	}
+	conf, err := router.Config()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read config %q", o.config)
+	}
+	conf.Spec.DryRun = conf.Spec.DryRun || o.dryRun
+	// Automations run locally are approved by running them.
+	conf.Spec.Approval.Actions = nil
+	conf.Spec.Approval.RequiredApprovers = nil
+	conf.Spec.MaintenanceWindows = nil
+	svcs, err := services.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	scc, err := clients.NewSecurityCommandCenter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ps := &published{}
+	if err := router.Execute(ctx, &router.Values{Finding: b}, &router.Services{
+		PubSub:                services.NewPubSub(ps),
+		Configuration:         conf,
+		Resource:              svcs.Resource,
+		SecurityCommandCenter: services.NewCommandCenter(&unmarked{scc}),
+	}); err != nil {
+		return nil, err
+	}
+	return ps.messages, nil
+}
@@ -0,0 +1,169 @@
+package events
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ChronicleConfig configures the Chronicle instance events are sent to as UDM events.
+type ChronicleConfig struct {
+	CustomerID string `yaml:"customer_id"`
+	// Region of the instance, such as "europe". Empty is the United States.
+	Region string
+	// CredentialsSecret is the Secret Manager secret version holding the ingestion credentials of
+	// the instance, such as "projects/automation-project/secrets/chronicle-ingestion/versions/latest".
+	CredentialsSecret string `yaml:"credentials_secret"`
+}
+
+// ChronicleClient contains minimum interface required by the Chronicle sink.
+type ChronicleClient interface {
+	CreateUDMEvents(ctx context.Context, customerID string, events ...json.RawMessage) error
+}
+
+// udmEvent is the subset of the Unified Data Model describing remediation events.
+type udmEvent struct {
+	Metadata       udmMetadata         `json:"metadata"`
+	Principal      *udmNoun            `json:"principal,omitempty"`
+	Target         udmNoun             `json:"target"`
+	SecurityResult []udmSecurityResult `json:"securityResult"`
+	Additional     map[string]string   `json:"additional,omitempty"`
+}
+
+type udmMetadata struct {
+	EventTimestamp   string `json:"eventTimestamp"`
+	EventType        string `json:"eventType"`
+	ProductName      string `json:"productName"`
+	VendorName       string `json:"vendorName"`
+	ProductEventType string `json:"productEventType"`
+	ProductLogID     string `json:"productLogId"`
+}
+
+type udmNoun struct {
+	User     *udmUser     `json:"user,omitempty"`
+	Resource *udmResource `json:"resource,omitempty"`
+}
+
+type udmUser struct {
+	UserID string `json:"userid"`
+}
+
+type udmResource struct {
+	Name         string `json:"name"`
+	ResourceType string `json:"resourceType"`
+}
+
+type udmSecurityResult struct {
+	RuleName        string        `json:"ruleName,omitempty"`
+	Severity        string        `json:"severity"`
+	Action          []string      `json:"action,omitempty"`
+	Summary         string        `json:"summary"`
+	Description     string        `json:"description,omitempty"`
+	DetectionFields []udmKeyValue `json:"detectionFields"`
+}
+
+type udmKeyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// udmActions maps the event types to the security result action of the remediation. Findings
+// remediated are blocked, findings left as they are allowed.
+var udmActions = map[string]string{
+	Succeeded: "BLOCK",
+	Failed:    "FAIL",
+	DryRun:    "ALLOW",
+	Skipped:   "ALLOW",
+}
+
+// udmSeverities are the finding severities UDM knows, others are unknown.
+var udmSeverities = map[string]bool{"CRITICAL": true, "HIGH": true, "MEDIUM": true, "LOW": true}
+
+// ChronicleSink sends events to Chronicle as UDM events, so the finding and the remediation of it
+// are correlated by the finding ID.
+type ChronicleSink struct {
+	client     ChronicleClient
+	customerID string
+}
+
+// NewChronicleSink returns a sink sending to the Chronicle instance of the customer.
+func NewChronicleSink(client ChronicleClient, customerID string) *ChronicleSink {
+	return &ChronicleSink{client: client, customerID: customerID}
+}
+
+// Send sends the event as a UDM event.
+func (c *ChronicleSink) Send(ctx context.Context, e *Event) error {
+	b, err := json.Marshal(udm(e))
+	if err != nil {
+		return err
+	}
+	return c.client.CreateUDMEvents(ctx, c.customerID, b)
+}
+
+// udm returns the UDM event of the remediation event.
+func udm(e *Event) *udmEvent {
+	severity := strings.ToUpper(e.Severity)
+	if !udmSeverities[severity] {
+		severity = "UNKNOWN_SEVERITY"
+	}
+	result := udmSecurityResult{
+		RuleName: e.Category,
+		Severity: severity,
+		Summary:  fmt.Sprintf("%s %s", e.Action, e.Type),
+		DetectionFields: []udmKeyValue{
+			{Key: "finding_id", Value: e.FindingID},
+			{Key: "remediation_id", Value: e.RemediationID},
+		},
+	}
+	if action, ok := udmActions[e.Type]; ok {
+		result.Action = []string{action}
+	}
+	switch {
+	case e.Error != "":
+		result.Description = e.Error
+	case e.Reason != "":
+		result.Description = e.Reason
+	}
+	u := &udmEvent{
+		Metadata: udmMetadata{
+			EventTimestamp:   e.Time.UTC().Format(time.RFC3339Nano),
+			EventType:        "GENERIC_EVENT",
+			ProductName:      "Security Response Automation",
+			VendorName:       "Google Cloud",
+			ProductEventType: e.Type,
+			ProductLogID:     e.ID,
+		},
+		SecurityResult: []udmSecurityResult{result},
+		Additional: map[string]string{
+			"schema_version": e.SchemaVersion,
+			"action":         e.Action,
+		},
+	}
+	if e.ProjectID != "" {
+		u.Target.Resource = &udmResource{Name: e.ProjectID, ResourceType: "CLOUD_PROJECT"}
+	}
+	if e.Actor != "" {
+		u.Principal = &udmNoun{User: &udmUser{UserID: e.Actor}}
+	}
+	if e.ApprovalID != "" {
+		u.Additional["approval_id"] = e.ApprovalID
+		u.Additional["approvers"] = strings.Join(e.Approvers, ",")
+	}
+	return u
+}
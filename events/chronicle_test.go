@@ -0,0 +1,81 @@
+package events
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+func TestChronicleSink(t *testing.T) {
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name         string
+		record       *services.AuditRecord
+		wantAction   string
+		wantSeverity string
+	}{
+		{
+			name:         "remediated",
+			record:       &services.AuditRecord{FindingID: "f1", Category: "public_bucket_acl", Severity: "HIGH", ProjectID: "p1", Action: "CloseBucket", Outcome: services.AuditSuccess, Actor: "automation@p1.iam.gserviceaccount.com"},
+			wantAction:   "BLOCK",
+			wantSeverity: "HIGH",
+		},
+		{
+			name:         "failed without severity",
+			record:       &services.AuditRecord{FindingID: "f1", Action: "CloseBucket", Outcome: services.AuditFailure, Error: "permission denied"},
+			wantAction:   "FAIL",
+			wantSeverity: "UNKNOWN_SEVERITY",
+		},
+		{
+			name:         "attempted",
+			record:       &services.AuditRecord{FindingID: "f1", Severity: "critical", Action: "CloseBucket"},
+			wantSeverity: "CRITICAL",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubs.ChronicleStub{}
+			if err := NewChronicleSink(stub, "customer-1").Send(ctx, New(tt.record, time.Now())); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			saved := stub.SavedEvents["customer-1"]
+			if len(saved) != 1 {
+				t.Fatalf("%s failed: got %d events want 1", tt.name, len(saved))
+			}
+			var got udmEvent
+			if err := json.Unmarshal([]byte(saved[0]), &got); err != nil {
+				t.Fatalf("%s failed to parse event: %q", tt.name, err)
+			}
+			r := got.SecurityResult[0]
+			if r.Severity != tt.wantSeverity {
+				t.Errorf("%s failed: got severity %q want %q", tt.name, r.Severity, tt.wantSeverity)
+			}
+			if (tt.wantAction == "" && len(r.Action) > 0) || (tt.wantAction != "" && (len(r.Action) != 1 || r.Action[0] != tt.wantAction)) {
+				t.Errorf("%s failed: got actions %v want %q", tt.name, r.Action, tt.wantAction)
+			}
+			if r.DetectionFields[0] != (udmKeyValue{Key: "finding_id", Value: "f1"}) {
+				t.Errorf("%s failed: finding not correlated, got %v", tt.name, r.DetectionFields)
+			}
+			if tt.record.ProjectID != "" && (got.Target.Resource == nil || got.Target.Resource.Name != tt.record.ProjectID) {
+				t.Errorf("%s failed: got target %+v", tt.name, got.Target)
+			}
+		})
+	}
+}
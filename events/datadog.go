@@ -0,0 +1,113 @@
+package events
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DatadogConfig configures the Datadog account events and metrics are sent to.
+type DatadogConfig struct {
+	// Site defaults to "datadoghq.com".
+	Site string
+	// APIKeySecret is the Secret Manager secret version holding the API key, such as
+	// "projects/automation-project/secrets/datadog-api-key/versions/latest".
+	APIKeySecret string `yaml:"api_key_secret"`
+	// Tags are added to every event and metric, such as "env:prod".
+	Tags []string
+}
+
+// DatadogClient contains minimum interface required by the Datadog sink.
+type DatadogClient interface {
+	PostEvent(ctx context.Context, title, text, alertType, aggregationKey string, tags []string, t time.Time) error
+	SubmitMetric(ctx context.Context, metric, metricType string, value float64, tags []string, t time.Time) error
+}
+
+// Metrics submitted to Datadog, tagged with the category, action, severity and type of the event.
+const (
+	// MetricRemediations counts the remediations finished, including the skipped findings.
+	MetricRemediations = "sra.remediations"
+	MetricFailures     = "sra.remediations.failures"
+	// MetricDuration is how long the automation ran for, in milliseconds.
+	MetricDuration = "sra.remediations.duration"
+)
+
+// datadogAlertTypes maps the event types to the alert types of Datadog events.
+var datadogAlertTypes = map[string]string{
+	Succeeded: "success",
+	Failed:    "error",
+	DryRun:    "info",
+	Skipped:   "warning",
+}
+
+// DatadogSink posts an event and submits metrics to Datadog for every finished remediation.
+type DatadogSink struct {
+	client DatadogClient
+	tags   []string
+}
+
+// NewDatadogSink returns a sink adding the tags to everything it sends.
+func NewDatadogSink(client DatadogClient, tags []string) *DatadogSink {
+	return &DatadogSink{client: client, tags: tags}
+}
+
+// Send posts the event and submits its metrics. Attempts are ignored, they are counted once they
+// finish.
+func (d *DatadogSink) Send(ctx context.Context, e *Event) error {
+	alertType, ok := datadogAlertTypes[e.Type]
+	if !ok {
+		return nil
+	}
+	tags := append([]string{
+		"category:" + tagValue(e.Category),
+		"action:" + tagValue(e.Action),
+		"severity:" + tagValue(strings.ToLower(e.Severity)),
+		"type:" + e.Type,
+	}, d.tags...)
+	text := fmt.Sprintf("Finding: %s\nProject: %s\nRemediation: %s", e.FindingID, e.ProjectID, e.RemediationID)
+	if e.Error != "" {
+		text += "\nError: " + e.Error
+	}
+	if e.Reason != "" {
+		text += "\nReason: " + e.Reason
+	}
+	title := fmt.Sprintf("%s %s", e.Action, e.Type)
+	if err := d.client.PostEvent(ctx, title, text, alertType, e.FindingID, tags, e.Time); err != nil {
+		return err
+	}
+	if err := d.client.SubmitMetric(ctx, MetricRemediations, "count", 1, tags, e.Time); err != nil {
+		return err
+	}
+	if e.Type == Failed {
+		if err := d.client.SubmitMetric(ctx, MetricFailures, "count", 1, tags, e.Time); err != nil {
+			return err
+		}
+	}
+	if e.Type == Skipped {
+		return nil
+	}
+	return d.client.SubmitMetric(ctx, MetricDuration, "gauge", float64(e.DurationMS), tags, e.Time)
+}
+
+// tagValue returns the value, or "none" for empty values so every point has each tag.
+func tagValue(v string) string {
+	if v == "" {
+		return "none"
+	}
+	return v
+}
@@ -0,0 +1,65 @@
+package events
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+func TestDatadogSink(t *testing.T) {
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name        string
+		outcome     string
+		wantAlert   string
+		wantMetrics map[string][]float64
+	}{
+		{name: "attempted"},
+		{name: "succeeded", outcome: services.AuditSuccess, wantAlert: "success", wantMetrics: map[string][]float64{MetricRemediations: {1}, MetricDuration: {2000}}},
+		{name: "failed", outcome: services.AuditFailure, wantAlert: "error", wantMetrics: map[string][]float64{MetricRemediations: {1}, MetricFailures: {1}, MetricDuration: {2000}}},
+		{name: "skipped", outcome: services.AuditSkipped, wantAlert: "warning", wantMetrics: map[string][]float64{MetricRemediations: {1}}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubs.DatadogStub{}
+			now := time.Now()
+			r := &services.AuditRecord{Time: now.Add(-2 * time.Second), Category: "public_bucket_acl", Severity: "HIGH", Action: "CloseBucket", Outcome: tt.outcome}
+			if err := NewDatadogSink(stub, []string{"env:prod"}).Send(ctx, New(r, now)); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if tt.wantAlert == "" {
+				if len(stub.SavedEvents) > 0 || len(stub.SavedMetrics) > 0 {
+					t.Errorf("%s failed: attempts should not be sent", tt.name)
+				}
+				return
+			}
+			if got := stub.SavedEvents[tt.wantAlert]; len(got) != 1 || got[0] != "CloseBucket "+New(r, now).Type {
+				t.Errorf("%s failed: got events %v", tt.name, stub.SavedEvents)
+			}
+			if !reflect.DeepEqual(stub.SavedMetrics, tt.wantMetrics) {
+				t.Errorf("%s failed: got metrics %v want %v", tt.name, stub.SavedMetrics, tt.wantMetrics)
+			}
+			wantTags := []string{"category:public_bucket_acl", "action:CloseBucket", "severity:high", "type:" + New(r, now).Type, "env:prod"}
+			if got := stub.SavedTags[MetricRemediations]; !reflect.DeepEqual(got, wantTags) {
+				t.Errorf("%s failed: got tags %v want %v", tt.name, got, wantTags)
+			}
+		})
+	}
+}
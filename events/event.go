@@ -0,0 +1,132 @@
+// Package events owns the canonical schema of remediation events and streams them to sinks
+// such as SIEMs and dashboards.
+package events
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// SchemaVersion is the version of the event schema. Fields are only added to a version, changing
+// or removing fields requires a new version.
+const SchemaVersion = "1"
+
+// Event types.
+const (
+	// Attempted is sent when an automation starts, before the outcome is known.
+	Attempted = "attempted"
+	Succeeded = "succeeded"
+	Failed    = "failed"
+	// DryRun is sent when an automation ran in dry run mode without changing anything.
+	DryRun  = "dry_run"
+	Skipped = "skipped"
+)
+
+// Event is a remediation event. Fields the event has no value for are empty rather than absent,
+// so the JSON encoding matches AvroSchema.
+type Event struct {
+	SchemaVersion string `json:"schema_version"`
+	// ID identifies the event, RemediationID the remediation in the audit trail.
+	ID            string    `json:"event_id"`
+	RemediationID string    `json:"remediation_id"`
+	Time          time.Time `json:"time"`
+	Type          string    `json:"type"`
+	FindingID     string    `json:"finding_id"`
+	Severity      string    `json:"severity"`
+	Category      string    `json:"category"`
+	ProjectID     string    `json:"project_id"`
+	// Action is the automation's Cloud Function, such as "CloseBucket".
+	Action     string   `json:"action"`
+	Error      string   `json:"error"`
+	Reason     string   `json:"reason"`
+	Actor      string   `json:"actor"`
+	ApprovalID string   `json:"approval_id"`
+	Approvers  []string `json:"approvers"`
+	// DurationMS is how long the automation ran for, in milliseconds.
+	DurationMS int64 `json:"duration_ms"`
+}
+
+// AvroSchema is the Avro schema of the JSON encoding of events, for Pub/Sub topic schemas and
+// consumers validating events.
+const AvroSchema = `{
+  "type": "record",
+  "name": "RemediationEvent",
+  "namespace": "com.google.cloud.securityresponseautomation",
+  "fields": [
+    {"name": "schema_version", "type": "string"},
+    {"name": "event_id", "type": "string"},
+    {"name": "remediation_id", "type": "string"},
+    {"name": "time", "type": "string"},
+    {"name": "type", "type": {"type": "enum", "name": "EventType", "symbols": ["attempted", "succeeded", "failed", "dry_run", "skipped"]}},
+    {"name": "finding_id", "type": "string"},
+    {"name": "severity", "type": "string"},
+    {"name": "category", "type": "string"},
+    {"name": "project_id", "type": "string"},
+    {"name": "action", "type": "string"},
+    {"name": "error", "type": "string"},
+    {"name": "reason", "type": "string"},
+    {"name": "actor", "type": "string"},
+    {"name": "approval_id", "type": "string"},
+    {"name": "approvers", "type": {"type": "array", "items": "string"}},
+    {"name": "duration_ms", "type": "long"}
+  ]
+}`
+
+// outcomeTypes maps the outcomes of audit records to event types.
+var outcomeTypes = map[string]string{
+	services.AuditSuccess: Succeeded,
+	services.AuditFailure: Failed,
+	services.AuditDryRun:  DryRun,
+	services.AuditSkipped: Skipped,
+}
+
+// New returns the event of the audit record. Records without an outcome yet are attempts, the
+// duration of the others is the time since the record was started.
+func New(r *services.AuditRecord, now time.Time) *Event {
+	t, ok := outcomeTypes[r.Outcome]
+	if !ok {
+		t = Attempted
+	}
+	var duration time.Duration
+	if t != Attempted && now.After(r.Time) {
+		duration = now.Sub(r.Time)
+	}
+	approvers := r.Approvers
+	if approvers == nil {
+		approvers = []string{}
+	}
+	return &Event{
+		SchemaVersion: SchemaVersion,
+		ID:            uuid.New().String(),
+		RemediationID: r.ID,
+		Time:          now.UTC(),
+		Type:          t,
+		FindingID:     r.FindingID,
+		Severity:      r.Severity,
+		Category:      r.Category,
+		ProjectID:     r.ProjectID,
+		Action:        r.Action,
+		Error:         r.Error,
+		Reason:        r.Reason,
+		Actor:         r.Actor,
+		ApprovalID:    r.ApprovalID,
+		Approvers:     approvers,
+		DurationMS:    int64(duration / time.Millisecond),
+	}
+}
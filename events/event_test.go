@@ -0,0 +1,136 @@
+package events
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+func TestNew(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, tt := range []struct {
+		name    string
+		outcome string
+		want    string
+	}{
+		{name: "attempted", want: Attempted},
+		{name: "succeeded", outcome: services.AuditSuccess, want: Succeeded},
+		{name: "failed", outcome: services.AuditFailure, want: Failed},
+		{name: "dry run", outcome: services.AuditDryRun, want: DryRun},
+		{name: "skipped", outcome: services.AuditSkipped, want: Skipped},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &services.AuditRecord{ID: "r1", Time: now.Add(-1500 * time.Millisecond), FindingID: "f1", Action: "CloseBucket", Outcome: tt.outcome}
+			e := New(r, now)
+			if e.Type != tt.want {
+				t.Errorf("%s failed: got type %q want %q", tt.name, e.Type, tt.want)
+			}
+			if e.SchemaVersion != SchemaVersion || e.RemediationID != "r1" || e.ID == "" || !e.Time.Equal(now) {
+				t.Errorf("%s failed: unexpected event %+v", tt.name, e)
+			}
+			wantDuration := int64(1500)
+			if tt.want == Attempted {
+				wantDuration = 0
+			}
+			if e.DurationMS != wantDuration {
+				t.Errorf("%s failed: got duration %d want %d", tt.name, e.DurationMS, wantDuration)
+			}
+			if e.Approvers == nil {
+				t.Errorf("%s failed: approvers should be empty rather than null", tt.name)
+			}
+		})
+	}
+}
+
+func TestAvroSchema(t *testing.T) {
+	var schema struct {
+		Fields []struct {
+			Name string
+		}
+	}
+	if err := json.Unmarshal([]byte(AvroSchema), &schema); err != nil {
+		t.Fatalf("failed to parse schema: %q", err)
+	}
+	var fields []string
+	for _, f := range schema.Fields {
+		fields = append(fields, f.Name)
+	}
+	b, err := json.Marshal(New(&services.AuditRecord{}, time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for k, v := range m {
+		if v == nil {
+			t.Errorf("field %q is null", k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(fields)
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, fields) {
+		t.Errorf("event fields %v do not match schema fields %v", keys, fields)
+	}
+}
+
+func TestPubSubSink(t *testing.T) {
+	ctx := context.Background()
+	stub := &stubs.PubSubStub{}
+	sink := NewPubSubSink(services.NewPubSub(stub), "remediation-events")
+	e := New(&services.AuditRecord{ID: "r1", Outcome: services.AuditFailure}, time.Now())
+	if err := sink.Send(ctx, e); err != nil {
+		t.Fatalf("failed to send: %q", err)
+	}
+	if got := stub.PublishedMessage.Attributes; got["type"] != Failed || got["schema_version"] != SchemaVersion {
+		t.Errorf("unexpected attributes %v", got)
+	}
+	var got Event
+	if err := json.Unmarshal(stub.PublishedMessage.Data, &got); err != nil {
+		t.Fatalf("failed to parse published event: %q", err)
+	}
+	if got.ID != e.ID || got.RemediationID != "r1" {
+		t.Errorf("unexpected event %+v", got)
+	}
+}
+
+type sinkFunc func(ctx context.Context, e *Event) error
+
+func (f sinkFunc) Send(ctx context.Context, e *Event) error { return f(ctx, e) }
+
+func TestStreamSendsToAllSinks(t *testing.T) {
+	sent := 0
+	ok := sinkFunc(func(ctx context.Context, e *Event) error { sent++; return nil })
+	failing := sinkFunc(func(ctx context.Context, e *Event) error { return errors.New("unavailable") })
+	err := NewStream(failing, ok).Send(context.Background(), New(&services.AuditRecord{}, time.Now()))
+	if err == nil {
+		t.Error("expected error of failing sink")
+	}
+	if sent != 1 {
+		t.Errorf("got %d events sent want 1", sent)
+	}
+}
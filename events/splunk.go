@@ -0,0 +1,65 @@
+package events
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// splunkSource is the source of the events sent to Splunk.
+const splunkSource = "security-response-automation"
+
+// SplunkConfig configures the Splunk HTTP Event Collector events are sent to.
+type SplunkConfig struct {
+	// URL is the collector, such as "https://http-inputs-cloudorg.splunkcloud.com".
+	URL   string
+	Index string
+	// SourceType defaults to "security-response-automation:event".
+	SourceType string `yaml:"sourcetype"`
+	// TokenSecret is the Secret Manager secret version holding the collector token, such as
+	// "projects/automation-project/secrets/splunk-hec-token/versions/latest".
+	TokenSecret string `yaml:"token_secret"`
+}
+
+// SplunkClient contains minimum interface required by the Splunk sink.
+type SplunkClient interface {
+	SendEvent(ctx context.Context, index, sourceType, source string, t time.Time, event []byte) error
+}
+
+// SplunkSink sends events to a Splunk HTTP Event Collector.
+type SplunkSink struct {
+	client     SplunkClient
+	index      string
+	sourceType string
+}
+
+// NewSplunkSink returns a sink sending to the index with the source type.
+func NewSplunkSink(client SplunkClient, index, sourceType string) *SplunkSink {
+	if sourceType == "" {
+		sourceType = "security-response-automation:event"
+	}
+	return &SplunkSink{client: client, index: index, sourceType: sourceType}
+}
+
+// Send sends the event, timestamped with the time of the event.
+func (s *SplunkSink) Send(ctx context.Context, e *Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return s.client.SendEvent(ctx, s.index, s.sourceType, splunkSource, e.Time, b)
+}
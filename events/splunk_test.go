@@ -0,0 +1,56 @@
+package events
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+func TestSplunkSink(t *testing.T) {
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name       string
+		sourceType string
+		wantKey    string
+	}{
+		{name: "default source type", wantKey: "security/security-response-automation:event"},
+		{name: "configured source type", sourceType: "sra", wantKey: "security/sra"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubs.SplunkStub{}
+			e := New(&services.AuditRecord{ID: "r1", Outcome: services.AuditSuccess}, time.Now())
+			if err := NewSplunkSink(stub, "security", tt.sourceType).Send(ctx, e); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			saved := stub.SavedEvents[tt.wantKey]
+			if len(saved) != 1 {
+				t.Fatalf("%s failed: got %v want one event under %q", tt.name, stub.SavedEvents, tt.wantKey)
+			}
+			var got Event
+			if err := json.Unmarshal([]byte(saved[0]), &got); err != nil {
+				t.Fatalf("%s failed to parse event: %q", tt.name, err)
+			}
+			if got.RemediationID != "r1" || got.Type != Succeeded {
+				t.Errorf("%s failed: unexpected event %+v", tt.name, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package events
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/googlecloudplatform/security-response-automation/clients"
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// Config configures the sinks remediation events are sent to.
+type Config struct {
+	PubSub struct {
+		Topic string
+	} `yaml:"pubsub"`
+	Splunk    SplunkConfig
+	Datadog   DatadogConfig
+	Chronicle ChronicleConfig
+}
+
+// Sink receives remediation events.
+type Sink interface {
+	Send(ctx context.Context, e *Event) error
+}
+
+// Stream sends remediation events to each of its sinks.
+type Stream struct {
+	sinks []Sink
+}
+
+// NewStream returns a stream sending to the sinks.
+func NewStream(sinks ...Sink) *Stream {
+	return &Stream{sinks: sinks}
+}
+
+// InitStream creates and initializes a new instance of Stream sending to the configured sinks.
+func InitStream(ctx context.Context, projectID string, conf Config) (*Stream, error) {
+	sinks := []Sink{}
+	if conf.PubSub.Topic != "" {
+		ps, err := services.InitPubSub(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, NewPubSubSink(ps, conf.PubSub.Topic))
+	}
+	if conf.Splunk.URL != "" {
+		token, err := secret(ctx, conf.Splunk.TokenSecret)
+		if err != nil {
+			return nil, err
+		}
+		splunk := clients.NewSplunk(conf.Splunk.URL, token)
+		sinks = append(sinks, NewSplunkSink(splunk, conf.Splunk.Index, conf.Splunk.SourceType))
+	}
+	if conf.Datadog.APIKeySecret != "" {
+		key, err := secret(ctx, conf.Datadog.APIKeySecret)
+		if err != nil {
+			return nil, err
+		}
+		site := conf.Datadog.Site
+		if site == "" {
+			site = "datadoghq.com"
+		}
+		sinks = append(sinks, NewDatadogSink(clients.NewDatadog(site, key), conf.Datadog.Tags))
+	}
+	if conf.Chronicle.CustomerID != "" {
+		credentials, err := secret(ctx, conf.Chronicle.CredentialsSecret)
+		if err != nil {
+			return nil, err
+		}
+		chronicle, err := clients.NewChronicle(ctx, conf.Chronicle.Region, []byte(credentials))
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, NewChronicleSink(chronicle, conf.Chronicle.CustomerID))
+	}
+	return NewStream(sinks...), nil
+}
+
+// secret returns the payload of the Secret Manager secret version.
+func secret(ctx context.Context, version string) (string, error) {
+	sm, err := services.InitSecretManager(ctx)
+	if err != nil {
+		return "", err
+	}
+	b, err := sm.Access(ctx, version)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Send sends the event to each sink. All sinks are tried even if some fail.
+func (s *Stream) Send(ctx context.Context, e *Event) error {
+	var errs []string
+	for _, sink := range s.sinks {
+		if err := sink.Send(ctx, e); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to send event %q: %s", e.ID, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// PubSubSink publishes events as JSON to a topic of the automation project.
+type PubSubSink struct {
+	pubsub *services.PubSub
+	topic  string
+}
+
+// NewPubSubSink returns a sink publishing to the topic.
+func NewPubSubSink(pubsub *services.PubSub, topic string) *PubSubSink {
+	return &PubSubSink{pubsub: pubsub, topic: topic}
+}
+
+// Send publishes the event, with its schema version and type as attributes so subscriptions can
+// filter on them.
+func (p *PubSubSink) Send(ctx context.Context, e *Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = p.pubsub.Publish(ctx, p.topic, &pubsub.Message{
+		Data:       b,
+		Attributes: map[string]string{"schema_version": e.SchemaVersion, "type": e.Type},
+	})
+	return err
+}
@@ -18,31 +18,194 @@ package exec
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/ioutil"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"cloud.google.com/go/pubsub"
+	"github.com/google/uuid"
+	"github.com/googlecloudplatform/security-response-automation/automations"
+	"github.com/googlecloudplatform/security-response-automation/clients"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/apikeys/restrictapikey"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/approve"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/artifactregistry/quarantineimage"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/artifactregistry/removepublicrepository"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/bigquery/closepublicdataset"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/aws/disableaccesskey"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/aws/isolateinstance"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/azure/removepubliccontainer"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/billing/disablebilling"
+	"github.com/googlecloudplatform/security-response-automation/events"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/cloud-sql/removepublic"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/cloud-sql/requiressl"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/cloud-sql/updatepassword"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/filter"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/dataprocessing/stoproguejob"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/deadletter"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/dns/enablednssec"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/expireexemptions"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/falco/ingestalert"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/senddigests"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/blockprojectsshkeys"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/createsnapshot"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/deletefirewallrules"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/disableserialport"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/enableflowlogs"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/enableoslogin"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/enableprivateaccess"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/enableshieldedvm"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/openfirewall"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/quarantineinstance"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/removeexternalexposure"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/removepublicip"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gcs/closebucket"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gcs/enablebucketonlypolicy"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gke/deletepod"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gke/disabledashboard"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gke/drainnode"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gke/enableauthorizednetworks"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/backuppolicies"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/disableoldkeys"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/enableauditlogs"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/removedefaulteditor"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/removeimpersonation"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/removenonorgmembers"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/restorepolicy"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/revoke"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/revokegrants"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/kms/removepublickms"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/pubsub/removepublicpubsub"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/replay"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/routebatch"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/router"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/secretmanager/rotatesecrets"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/serverless/enforceauthentication"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/serverless/removepublicinvoker"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/undo"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/workspace/enforcereenrollment"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/workspace/revoketokens"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/workspace/suspenduser"
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"github.com/googlecloudplatform/security-response-automation/metrics"
 	"github.com/googlecloudplatform/security-response-automation/services"
+	"github.com/googlecloudplatform/security-response-automation/tracing"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// healthCheckInterval is how often the services kept across invocations are checked.
+const healthCheckInterval = 5 * time.Minute
+
 var (
-	svcs      *services.Global
+	audit     *services.Audit
 	projectID = os.Getenv("GCP_PROJECT")
+	// recorder writes the metrics of the automations to the standard output, which Cloud Logging
+	// picks up.
+	recorder = metrics.NewLog(os.Stdout)
+	// global holds the services of the function's service account and pubSub its PubSub client.
+	// Both are created once per instance and kept across invocations.
+	global = services.NewReused("services", func(ctx context.Context) (interface{}, error) {
+		g, err := services.New(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return g, nil
+	}, checkCredentials, healthCheckInterval)
+	pubSub = services.NewReused("pubsub client", func(context.Context) (interface{}, error) {
+		ps, err := services.InitPubSub(context.Background(), projectID)
+		if err != nil {
+			return nil, err
+		}
+		return ps, nil
+	}, checkCredentials, healthCheckInterval)
+	// emails are the email services of each SendGrid API key, kept across invocations.
+	emails = services.NewEmails(services.InitEmail)
+	// shared holds the services of the router and those audited automations use, kept across
+	// invocations like global. Services set up by the configuration are created again once their
+	// configuration changes.
+	shared = struct {
+		exemptions, assets, counter, locks                            *services.Reused
+		approvals, slack, tickets, stream, idempotency, notifications *services.Configured
+	}{
+		exemptions: services.NewReused("exemptions", func(context.Context) (interface{}, error) {
+			e, err := services.InitExemptions(context.Background(), projectID)
+			if err != nil {
+				return nil, err
+			}
+			return e, nil
+		}, checkCredentials, healthCheckInterval),
+		assets: services.NewReused("assets", func(context.Context) (interface{}, error) {
+			a, err := services.InitAssets(context.Background())
+			if err != nil {
+				return nil, err
+			}
+			return a, nil
+		}, checkCredentials, healthCheckInterval),
+		counter: services.NewReused("counter", func(context.Context) (interface{}, error) {
+			c, err := services.InitCounter(context.Background())
+			if err != nil {
+				return nil, err
+			}
+			return c, nil
+		}, checkCredentials, healthCheckInterval),
+		locks: services.NewReused("project locks", func(context.Context) (interface{}, error) {
+			l, err := services.InitProjectLocks(context.Background(), projectID)
+			if err != nil {
+				return nil, err
+			}
+			return l, nil
+		}, checkCredentials, healthCheckInterval),
+		approvals: services.NewConfigured("approvals", func(_ context.Context, key interface{}) (interface{}, error) {
+			a, err := services.InitApprovals(context.Background(), projectID, key.(string))
+			if err != nil {
+				return nil, err
+			}
+			return a, nil
+		}, checkCredentials, healthCheckInterval),
+		slack: services.NewConfigured("slack", func(_ context.Context, conf interface{}) (interface{}, error) {
+			s, err := services.InitSlack(context.Background(), projectID, conf.(services.SlackConfig))
+			if err != nil {
+				return nil, err
+			}
+			return s, nil
+		}, checkCredentials, healthCheckInterval),
+		tickets: services.NewConfigured("tickets", func(_ context.Context, conf interface{}) (interface{}, error) {
+			t, err := services.InitTickets(context.Background(), projectID, conf.(services.TicketConfig))
+			if err != nil {
+				return nil, err
+			}
+			return t, nil
+		}, checkCredentials, healthCheckInterval),
+		stream: services.NewConfigured("event stream", func(_ context.Context, conf interface{}) (interface{}, error) {
+			s, err := events.InitStream(context.Background(), projectID, conf.(events.Config))
+			if err != nil {
+				return nil, err
+			}
+			return s, nil
+		}, checkCredentials, healthCheckInterval),
+		idempotency: services.NewConfigured("idempotency", func(_ context.Context, conf interface{}) (interface{}, error) {
+			i, err := services.InitIdempotency(context.Background(), projectID, conf.(services.IdempotencyConfig))
+			if err != nil {
+				return nil, err
+			}
+			return i, nil
+		}, checkCredentials, healthCheckInterval),
+		notifications: services.NewConfigured("notifications", func(_ context.Context, conf interface{}) (interface{}, error) {
+			n, err := services.InitNotifications(context.Background(), projectID, conf.(services.NotificationConfig))
+			if err != nil {
+				return nil, err
+			}
+			return n, nil
+		}, checkCredentials, healthCheckInterval),
+	}
 )
 
 func init() {
@@ -52,363 +215,2391 @@ func init() {
 	if projectID == "" {
 		log.Fatalf("GCP_PROJECT environment variable not set")
 	}
-	svcs, err = services.New(ctx)
-	if err != nil {
+	if _, err := global.Get(ctx); err != nil {
 		log.Fatalf("failed to initialize services: %q", err)
 	}
+	audit, err = services.InitAudit(ctx, projectID)
+	if err != nil {
+		log.Fatalf("failed to initialize audit: %q", err)
+	}
+	// The breaker is kept across invocations so the state of each API is cached.
+	breaker, err := services.InitBreaker(ctx, projectID, breakerConfig)
+	if err != nil {
+		log.Fatalf("failed to initialize circuit breaker: %q", err)
+	}
+	clients.SetCircuitBreaker(breaker)
+	// Tracing is best effort, the automations run without it.
+	if err := tracing.Init(ctx, projectID); err != nil {
+		logging.FromContext(ctx).Warning("failed to initialize tracing: %q", err)
+	}
+}
+
+// checkCredentials checks the services kept across invocations can still authenticate.
+func checkCredentials(ctx context.Context, _ interface{}) error {
+	return clients.CheckCredentials(ctx)
+}
+
+// initPubSub returns the PubSub service kept across invocations.
+func initPubSub(ctx context.Context) (*services.PubSub, error) {
+	v, err := pubSub.Get(ctx)
+	if v == nil {
+		return nil, err
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to create pubsub client again, reusing the previous one: %q", err)
+	}
+	return v.(*services.PubSub), nil
+}
+
+// reusedGlobal returns the services kept by r, or the previous ones if they failed their health
+// check and couldn't be created again.
+func reusedGlobal(ctx context.Context, r *services.Reused) (*services.Global, error) {
+	v, err := r.Get(ctx)
+	if v == nil {
+		return nil, err
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to create services again, reusing the previous ones: %q", err)
+	}
+	return v.(*services.Global), nil
+}
+
+// kept returns the service kept by r, or the previous one if it failed its health check and
+// couldn't be created again.
+func kept(ctx context.Context, r *services.Reused) (interface{}, error) {
+	v, err := r.Get(ctx)
+	if v == nil {
+		return nil, err
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to create service again, reusing the previous one: %q", err)
+	}
+	return v, nil
+}
+
+// breakerConfig returns the configuration of the circuit breakers, the defaults if it can't be
+// read.
+func breakerConfig() services.BreakerConfig {
+	conf, err := router.Config()
+	if err != nil {
+		logging.FromContext(context.Background()).Error("failed to read config, using the default circuit breaker: %q", err)
+		return services.BreakerConfig{}
+	}
+	return conf.Spec.CircuitBreaker
+}
+
+// audited runs the automation and records the attempt in the audit trail. A failure to write the
+// record is logged but does not fail the automation. The automation is traced as a child of the
+// span of the router that published the finding.
+func audited(ctx context.Context, m pubsub.Message, action string, run func(ctx context.Context) error) error {
+	ctx, span := tracing.Start(tracing.Extract(ctx, m.Attributes), action,
+		attribute.String("finding_id", m.Attributes["finding_id"]),
+		attribute.String("category", m.Attributes["category"]),
+	)
+	defer tracing.Flush(ctx)
+	var values struct {
+		ProjectID string
+		DryRun    bool
+	}
+	// Not every automation has a project or a dry run mode.
+	_ = json.Unmarshal(m.Data, &values)
+	// Entries logged by the automation and its clients are labeled with the remediation.
+	ctx = logging.WithFinding(ctx, m.Attributes["finding_id"])
+	ctx = logging.WithProject(ctx, values.ProjectID)
+	ctx = logging.WithAutomation(ctx, action)
+	record := &services.AuditRecord{
+		ID:        uuid.New().String(),
+		Time:      time.Now(),
+		FindingID: m.Attributes["finding_id"],
+		Severity:  m.Attributes["severity"],
+		Category:  m.Attributes["category"],
+		ProjectID: values.ProjectID,
+		Action:    action,
+		// Secrets, such as the passwords of Cloud SQL users, are never recorded.
+		Values: string(services.RedactValues(m.Data)),
+		// Set if the action waited for approval.
+		ApprovalID: m.Attributes["approval_id"],
+	}
+	if approvers := m.Attributes["approvers"]; approvers != "" {
+		record.Approvers = strings.Split(approvers, ",")
+	}
+	keys := idempotencyKeys(ctx, action)
+	key := services.IdempotencyKey(action, record.FindingID, m.Attributes["event_time"])
+	if !claim(ctx, keys, key, record) {
+		logging.FromContext(ctx).Info("skipping %q of finding %q, it ran before", action, record.FindingID)
+		span.End()
+		return nil
+	}
+	stream := eventStream(ctx, action)
+	sendEvent(ctx, stream, record)
+	// Automations remediating the same project run one after the other, so they don't race to
+	// write its IAM policy.
+	unlock, err := lockProject(ctx, values.ProjectID, record.ID)
+	if err == nil {
+		var runCtx context.Context
+		if runCtx, err = impersonate(ctx, m.Attributes["service_account"]); err == nil {
+			// The remediator service account is recorded as the actor of the remediation.
+			record.Actor = m.Attributes["service_account"]
+			err = run(services.WithAudit(runCtx, record))
+		}
+		unlock()
+	}
+	record.SetOutcome(err, values.DryRun)
+	recorder.Outcome(record, metrics.ParseEventTime(m.Attributes["event_time"]))
+	if err == nil && !values.DryRun {
+		markRemediated(ctx, record.FindingID)
+	}
+	replaying := false
+	if err != nil {
+		if open, ok := errors.Cause(err).(*clients.ErrCircuitOpen); ok {
+			logging.FromContext(ctx).Warning("%q not attempted, the circuit breaker of %s is open", action, open.API)
+		}
+		// Redeliveries and replays of the failed remediation run again.
+		release(ctx, keys, key)
+		replaying = deadLetter(ctx, m, record)
+	}
+	if auditErr := audit.Record(ctx, record); auditErr != nil {
+		logging.FromContext(ctx).Error("failed to record %q in audit trail: %q", action, auditErr)
+	} else if len(record.States) > 0 {
+		logging.FromContext(ctx).Info("saved prior state of remediation %q, run UndoRemediation to restore it", record.ID)
+	}
+	if channels := m.Attributes["notify"]; channels != "" {
+		notify(ctx, strings.Split(channels, ","), record, replaying)
+	}
+	fileTicket(ctx, record)
+	sendEvent(ctx, stream, record)
+	tracing.End(span, err)
+	return err
+}
+
+// deadLetter publishes the message of the failed remediation to the dead letter topic, so it can
+// be replayed, and returns whether it's replayed on schedule. Messages without the topic they were
+// published to can't be replayed.
+func deadLetter(ctx context.Context, m pubsub.Message, record *services.AuditRecord) bool {
+	topic := m.Attributes["topic"]
+	if topic == "" {
+		logging.FromContext(ctx).Warning("remediation %q of %q can't be replayed, its topic is unknown", record.ID, record.Action)
+		return false
+	}
+	// The attempt is only set on replayed messages.
+	attempts, _ := strconv.Atoi(m.Attributes[services.AttemptAttribute])
+	b, err := json.Marshal(&services.DeadLetter{
+		ID:     record.ID,
+		Action: record.Action,
+		Topic:  topic,
+		// Secrets are redacted like in the audit trail, replays read them from the config again.
+		Data:       services.RedactValues(m.Data),
+		Attributes: m.Attributes,
+		Error:      record.Error,
+		Attempts:   attempts,
+		Failed:     time.Now(),
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to marshal dead letter of %q: %q", record.ID, err)
+		return false
+	}
+	ps, err := initPubSub(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to initialize pubsub, dead letter of %q not sent: %q", record.ID, err)
+		return false
+	}
+	if _, err := ps.Publish(ctx, services.DeadLetterTopic, &pubsub.Message{Data: b}); err != nil {
+		logging.FromContext(ctx).Error("failed to send dead letter of %q: %q", record.ID, err)
+		return false
+	}
+	conf, err := router.Config()
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to read config, replays of %q unknown: %q", record.ID, err)
+		return false
+	}
+	return conf.Spec.DeadLetters.Replays(attempts)
+}
+
+// sendGridKey returns the SendGrid API key the action emails with. The key of a replayed
+// remediation was redacted from its dead letter, so it's read from the config again.
+func sendGridKey(ctx context.Context, action, key, from string) string {
+	if key != services.Redacted {
+		return key
+	}
+	conf, err := router.Config()
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to read config for the SendGrid key of %q: %q", action, err)
+		return ""
+	}
+	return conf.SendGridKey(action, from)
+}
+
+// remediators are the services acting as the impersonated remediator service accounts, keyed by
+// service account.
+var remediators = struct {
+	sync.Mutex
+	services map[string]*services.Reused
+}{services: map[string]*services.Reused{}}
+
+type remediatorKey struct{}
+
+// impersonate returns a context running the remediation as the remediator service account, or
+// the context itself if the router didn't choose one.
+func impersonate(ctx context.Context, serviceAccount string) (context.Context, error) {
+	if serviceAccount == "" {
+		return ctx, nil
+	}
+	ctx, err := clients.WithImpersonation(ctx, serviceAccount)
+	if err != nil {
+		return nil, err
+	}
+	remediators.Lock()
+	r, ok := remediators.services[serviceAccount]
+	if !ok {
+		r = services.NewReused("services as "+serviceAccount, func(ctx context.Context) (interface{}, error) {
+			g, err := services.New(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return g, nil
+		}, checkCredentials, healthCheckInterval)
+		remediators.services[serviceAccount] = r
+	}
+	remediators.Unlock()
+	g, err := reusedGlobal(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize services as %q: %q", serviceAccount, err)
+	}
+	return context.WithValue(ctx, remediatorKey{}, g), nil
+}
+
+// scoped returns the services of the remediator service account the remediation runs as, or the
+// services of the function's service account.
+func scoped(ctx context.Context) (*services.Global, error) {
+	if g, ok := ctx.Value(remediatorKey{}).(*services.Global); ok {
+		return g, nil
+	}
+	return reusedGlobal(ctx, global)
+}
+
+// idempotencyKeys returns the idempotency service skipping redelivered findings, or nil if it
+// could not be initialized.
+func idempotencyKeys(ctx context.Context, action string) *services.Idempotency {
+	conf, err := router.Config()
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to read config, redeliveries of %q aren't skipped: %q", action, err)
+		return nil
+	}
+	i, err := kept(ctx, shared.idempotency.For(conf.Spec.Idempotency))
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to initialize idempotency: %q", err)
+		return nil
+	}
+	return i.(*services.Idempotency)
+}
+
+// lockProject waits until the project is locked for the remediation and returns the function
+// unlocking it. Automations without a project aren't locked, and failing to initialize the locks
+// is logged and the automation runs unlocked.
+func lockProject(ctx context.Context, project, remediation string) (func(), error) {
+	if project == "" {
+		return func() {}, nil
+	}
+	v, err := kept(ctx, shared.locks)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to initialize project locks: %q", err)
+		return func() {}, nil
+	}
+	locks := v.(*services.ProjectLocks)
+	if err := locks.Lock(ctx, project, remediation); err != nil {
+		return nil, err
+	}
+	return func() {
+		if err := locks.Unlock(ctx, project, remediation); err != nil {
+			logging.FromContext(ctx).Error("failed to unlock %q: %q", project, err)
+		}
+	}, nil
+}
+
+// claim returns whether the automation runs for the finding, false if it ran for the same event
+// of the finding before. Failing to claim the finding is logged and the automation runs.
+func claim(ctx context.Context, idempotency *services.Idempotency, key string, record *services.AuditRecord) bool {
+	if idempotency == nil {
+		return true
+	}
+	ok, err := idempotency.Claim(ctx, key, time.Now())
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to claim %q of finding %q: %q", record.Action, record.FindingID, err)
+		return true
+	}
+	return ok
+}
+
+// release lets the automation run again for the finding.
+func release(ctx context.Context, idempotency *services.Idempotency, key string) {
+	if idempotency == nil {
+		return
+	}
+	if err := idempotency.Release(ctx, key); err != nil {
+		logging.FromContext(ctx).Error("failed to release idempotency key %q: %q", key, err)
+	}
+}
+
+// eventStream returns the stream of remediation events configured for the automation, or nil if
+// none could be initialized.
+func eventStream(ctx context.Context, action string) *events.Stream {
+	conf, err := router.Config()
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to read config, no events sent for %q: %q", action, err)
+		return nil
+	}
+	stream, err := kept(ctx, shared.stream.For(conf.Spec.Events))
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to initialize event stream: %q", err)
+		return nil
+	}
+	return stream.(*events.Stream)
+}
+
+// sendEvent sends the event of the record to the stream. Failing to send it is logged but does
+// not fail the automation.
+func sendEvent(ctx context.Context, stream *events.Stream, record *services.AuditRecord) {
+	if stream == nil {
+		return
+	}
+	if err := stream.Send(ctx, events.New(record, time.Now())); err != nil {
+		logging.FromContext(ctx).Error("failed to send event for %q: %q", record.Action, err)
+	}
+}
+
+// fileTicket files a ticket for the automation if its outcome needs follow-up. Failing to file
+// it is logged but does not fail the automation.
+func fileTicket(ctx context.Context, record *services.AuditRecord) {
+	conf, err := router.Config()
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to read config, no ticket filed for %q: %q", record.Action, err)
+		return
+	}
+	tickets, err := kept(ctx, shared.tickets.For(conf.Spec.Tickets))
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to initialize tickets: %q", err)
+		return
+	}
+	if err := tickets.(*services.Tickets).File(ctx, record); err != nil {
+		logging.FromContext(ctx).Error("failed to file ticket for %q: %q", record.Action, err)
+	}
+}
+
+// notify sends the outcome of the automation to the channels configured for it. Failures replayed
+// on schedule are marked so paging channels wait for the last attempt. Failing to notify is logged
+// but does not fail the automation.
+func notify(ctx context.Context, channels []string, record *services.AuditRecord, replaying bool) {
+	conf, err := router.Config()
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to read config, %q won't be notified: %q", record.Action, err)
+		return
+	}
+	n, err := kept(ctx, shared.notifications.For(conf.Spec.Notifications))
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to initialize notifications: %q", err)
+		return
+	}
+	notification := services.NewNotification(record)
+	notification.Replaying = replaying
+	if err := n.(*services.Notifications).Notify(ctx, channels, notification); err != nil {
+		logging.FromContext(ctx).Error("failed to notify outcome of %q: %q", record.Action, err)
+	}
+}
+
+// markRemediated updates the remediated finding in Security Command Center so dashboards reflect
+// the action taken. Findings read from Stackdriver logs aren't in Security Command Center.
+func markRemediated(ctx context.Context, findingID string) {
+	if !strings.Contains(findingID, "/findings/") {
+		return
+	}
+	inactive := false
+	if conf, err := router.Config(); err != nil {
+		logging.FromContext(ctx).Error("failed to read config, %q won't be set inactive: %q", findingID, err)
+	} else {
+		inactive = conf.Spec.SCC.SetInactive
+	}
+	g, err := scoped(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to initialize services, %q not updated: %q", findingID, err)
+		return
+	}
+	if err := g.SecurityCommandCenter.MarkRemediated(ctx, findingID, inactive); err != nil {
+		logging.FromContext(ctx).Error("failed to update finding: %q", err)
+	}
+}
+
+// Filter is the entry point for the Filter Cloud function.
+// This function will receive all findings and filter them against
+// any user-defined Rego policies before forwarding along to the
+// Router function.
+func Filter(ctx context.Context, m pubsub.Message) error {
+	ps, err := initPubSub(ctx)
+	if err != nil {
+		return err
+	}
+	g, err := scoped(ctx)
+	if err != nil {
+		return err
+	}
+	return filter.Execute(ctx, m, &filter.Services{
+		PubSub:                ps,
+		SecurityCommandCenter: g.SecurityCommandCenter,
+	})
+}
+
+// Router is the entry point for the router Cloud Function.
+//
+// This Cloud Function will receive all findings and route them to configured automation.
+func Router(ctx context.Context, m pubsub.Message) error {
+	defer tracing.Flush(ctx)
+	// Findings are pulled by RouteBatch instead.
+	if os.Getenv("BATCH_ROUTING") == "true" {
+		return nil
+	}
+	routerServices, err := initRouter(ctx)
+	if err != nil {
+		return err
+	}
+	return router.Execute(ctx, &router.Values{
+		Finding: m.Data,
+	}, routerServices)
+}
+
+// RouteBatch is the entry point for the Cloud Function routing findings in batches.
+//
+// Scheduled instead of triggered by each finding, it pulls a batch of findings from the router
+// topic's pull subscription and routes them with clients set up once for the batch.
+func RouteBatch(ctx context.Context, m pubsub.Message) error {
+	defer tracing.Flush(ctx)
+	var values routebatch.Values
+	if err := json.Unmarshal(m.Data, &values); err != nil {
+		return err
+	}
+	subscriber, err := services.InitSubscriber(ctx)
+	if err != nil {
+		return err
+	}
+	routerServices, err := initRouter(ctx)
+	if err != nil {
+		return err
+	}
+	return routebatch.Execute(ctx, &values, &routebatch.Services{
+		Subscriber: subscriber,
+		Router:     routerServices,
+	})
+}
+
+// initRouter returns the services the router needs for its configuration.
+func initRouter(ctx context.Context) (*router.Services, error) {
+	ps, err := initPubSub(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conf, err := router.Config()
+	if err != nil {
+		return nil, err
+	}
+	var approvals *services.Approvals
+	var email *services.Email
+	var slack *services.Slack
+	var teams *services.Teams
+	// Actions outside of their maintenance windows wait for approval as well, and disable_billing
+	// always does.
+	if approval := conf.Spec.Approval; len(approval.Actions) > 0 || len(approval.RequiredApprovers) > 0 || len(conf.Spec.MaintenanceWindows) > 0 || conf.DisablesBilling() {
+		v, err := kept(ctx, shared.approvals.For(approval.Key))
+		if err != nil {
+			return nil, err
+		}
+		approvals = v.(*services.Approvals)
+		if approval.SendGrid.APIKey != "" {
+			email = emails.Get(approval.SendGrid.APIKey)
+		}
+		if approval.Slack {
+			v, err := kept(ctx, shared.slack.For(conf.Spec.Notifications.Slack))
+			if err != nil {
+				return nil, err
+			}
+			slack = v.(*services.Slack)
+		}
+		if approval.Teams {
+			teams = services.InitTeams(conf.Spec.Notifications.Teams)
+		}
+	}
+	exemptions, err := kept(ctx, shared.exemptions)
+	if err != nil {
+		return nil, err
+	}
+	tickets, err := kept(ctx, shared.tickets.For(conf.Spec.Tickets))
+	if err != nil {
+		return nil, err
+	}
+	stream, err := kept(ctx, shared.stream.For(conf.Spec.Events))
+	if err != nil {
+		return nil, err
+	}
+	assets, err := kept(ctx, shared.assets)
+	if err != nil {
+		return nil, err
+	}
+	idempotency, err := kept(ctx, shared.idempotency.For(conf.Spec.Idempotency))
+	if err != nil {
+		return nil, err
+	}
+	counter, err := kept(ctx, shared.counter)
+	if err != nil {
+		return nil, err
+	}
+	g, err := scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &router.Services{
+		PubSub:                ps,
+		Configuration:         conf,
+		Resource:              g.Resource,
+		SecurityCommandCenter: g.SecurityCommandCenter,
+		Approvals:             approvals,
+		Email:                 email,
+		Slack:                 slack,
+		Teams:                 teams,
+		Audit:                 audit,
+		Exemptions:            exemptions.(*services.Exemptions),
+		Tickets:               tickets.(*services.Tickets),
+		Events:                stream.(*events.Stream),
+		Assets:                assets.(*services.Assets),
+		Idempotency:           idempotency.(*services.Idempotency),
+		Metrics:               recorder,
+		Counter:               counter.(*services.Counter),
+	}, nil
+}
+
+// ApproveRemediation is the entry point for the HTTP Cloud Function the approve and deny links of
+// pending actions point to.
+//
+// Following a link shows a confirmation page, so link scanners don't make the decision, which
+// submits the decision back to this function. Approved actions are published to their topic.
+func ApproveRemediation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	values := &approve.Values{
+		ID:        r.Form.Get("id"),
+		Decision:  r.Form.Get("decision"),
+		Approver:  r.Form.Get("approver"),
+		Expires:   r.Form.Get("expires"),
+		Signature: r.Form.Get("signature"),
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := approvalPage.Execute(w, values); err != nil {
+			logging.FromContext(ctx).Error("failed to render approval page: %q", err)
+		}
+		return
+	}
+	conf, err := router.Config()
+	if err != nil {
+		http.Error(w, "failed to load configuration", http.StatusInternalServerError)
+		return
+	}
+	approvals, err := services.InitApprovals(ctx, projectID, conf.Spec.Approval.Key)
+	if err != nil {
+		http.Error(w, "failed to initialize approvals", http.StatusInternalServerError)
+		return
+	}
+	ps, err := initPubSub(ctx)
+	if err != nil {
+		http.Error(w, "failed to initialize pubsub", http.StatusInternalServerError)
+		return
+	}
+	p, err := approve.Execute(ctx, values, &approve.Services{
+		Approvals: approvals,
+		PubSub:    ps,
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to decide on pending action %q: %q", values.ID, err)
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	fmt.Fprintf(w, "%q was %s.\n", p.Action, p.Status)
+}
+
+// SlackInteraction is the entry point for the HTTP Cloud Function configured as the interactivity
+// request URL of the Slack app, which Slack calls when approve or deny buttons are clicked.
+//
+// Requests are verified with the signing secret of the Slack app. The Slack user deciding must
+// have the email address of one of the approvers.
+func SlackInteraction(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	conf, err := router.Config()
+	if err != nil {
+		http.Error(w, "failed to load configuration", http.StatusInternalServerError)
+		return
+	}
+	slack, err := services.InitSlack(ctx, projectID, conf.Spec.Notifications.Slack)
+	if err != nil {
+		http.Error(w, "failed to initialize slack", http.StatusInternalServerError)
+		return
+	}
+	if err := slack.VerifyRequest(r.Header.Get("X-Slack-Request-Timestamp"), r.Header.Get("X-Slack-Signature"), body, time.Now()); err != nil {
+		logging.FromContext(ctx).Error("failed to verify slack request: %q", err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	d, err := slack.Decision(ctx, []byte(form.Get("payload")))
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to read slack decision: %q", err)
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	approvals, err := services.InitApprovals(ctx, projectID, conf.Spec.Approval.Key)
+	if err != nil {
+		http.Error(w, "failed to initialize approvals", http.StatusInternalServerError)
+		return
+	}
+	ps, err := initPubSub(ctx)
+	if err != nil {
+		http.Error(w, "failed to initialize pubsub", http.StatusInternalServerError)
+		return
+	}
+	if _, err := approve.ExecuteSlack(ctx, d, conf.Spec.Approval.SendGrid.To, &approve.Services{
+		Approvals: approvals,
+		PubSub:    ps,
+		Slack:     slack,
+	}); err != nil {
+		// Slack shows nothing for errors, the outcome is replied in the thread instead.
+		logging.FromContext(ctx).Error("failed to decide on pending action %q: %q", d.ID, err)
+		if err := slack.Reply(ctx, "", fmt.Sprintf("%s could not %s pending action %q: %s", d.Approver, d.Decision, d.ID, err)); err != nil {
+			logging.FromContext(ctx).Error("failed to reply to slack: %q", err)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// FalcoWebhook is the entry point for the HTTP Cloud Function Falco sends the alerts of a GKE
+// cluster to, such as with the webhook output of falcosidekick.
+//
+// The cluster is the cluster query parameter of the URL, such as
+// ?cluster=projects/p/locations/us-central1/clusters/c, and requests carry the token configured
+// under falco as a bearer token. Alerts are normalized into findings and sent to the router.
+func FalcoWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	conf, err := router.Config()
+	if err != nil {
+		http.Error(w, "failed to load configuration", http.StatusInternalServerError)
+		return
+	}
+	ps, err := initPubSub(ctx)
+	if err != nil {
+		http.Error(w, "failed to initialize pubsub", http.StatusInternalServerError)
+		return
+	}
+	values := &ingestalert.Values{
+		Alert:   body,
+		Cluster: r.URL.Query().Get("cluster"),
+		Token:   strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "),
+		Topic:   os.Getenv("OUTPUT_TOPIC"),
+	}
+	switch err := ingestalert.Execute(ctx, values, conf.Spec.Falco.Token, &ingestalert.Services{PubSub: ps}); {
+	case err == ingestalert.ErrUnauthorized:
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+	case err != nil:
+		logging.FromContext(ctx).Error("failed to ingest falco alert from cluster %q: %q", values.Cluster, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+var approvalPage = template.Must(template.New("approval").Parse(`<!DOCTYPE html>
+<html><body>
+<form method="POST">
+<input type="hidden" name="id" value="{{.ID}}">
+<input type="hidden" name="decision" value="{{.Decision}}">
+<input type="hidden" name="approver" value="{{.Approver}}">
+<input type="hidden" name="expires" value="{{.Expires}}">
+<input type="hidden" name="signature" value="{{.Signature}}">
+<p>Do you want to {{.Decision}} pending action {{.ID}}?</p>
+<button type="submit">{{.Decision}}</button>
+</form>
+</body></html>
+`))
+
+// IAMRevoke is the entry point for the IAM revoker Cloud Function.
+//
+// This function will attempt to revoke the external members added to the policy if they
+// match the provided list of disallowed domains. Additionally this method will only remove
+// members if the project they were added to is within the specified folders. This
+// configuration allows you to take a remediation action only on specific members and folders.
+// For example, you may have a folder "development" where users can experiment without strict
+// policies. However in your "production" folder you may want to revoke any grants that ETD
+// finds as long as they match the domains you specify.
+//
+// Permissions required
+// 	- roles/resourcemanager.folderAdmin to revoke IAM grants.
+//	- roles/viewer to verify the affected project is within the enforced folder.
+//
+func IAMRevoke(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "IAMRevoke", func(ctx context.Context) error {
+		var values revoke.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return revoke.Execute(ctx, &values, &revoke.Services{
+				Resource: g.Resource,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// IAMRevokeGrants is the entry point for the IAM grant revoker Cloud Function.
+//
+// This function will revoke only the member and role pairs the finding reports as added,
+// leaving every other binding in the policy untouched. Members matching the provided list of
+// allowed domains are kept.
+//
+// Permissions required
+//	- roles/resourcemanager.folderAdmin to revoke IAM grants.
+//	- roles/viewer to verify the affected project is within the enforced folder.
+//
+func IAMRevokeGrants(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "IAMRevokeGrants", func(ctx context.Context) error {
+		var values revokegrants.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return revokegrants.Execute(ctx, &values, &revokegrants.Services{
+				Resource: g.Resource,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// RemoveImpersonation removes the impersonation bindings of the principal behind an anomalous
+// service account impersonation.
+//
+// This Cloud Function will respond to Event Threat Detection service account self-investigation
+// and impersonation findings. The roles/iam.serviceAccountTokenCreator and
+// roles/iam.serviceAccountUser bindings granted to the principal are removed from the project and
+// from each service account it impersonated.
+//
+// Permissions required
+//	- roles/viewer to verify the affected project is within the enforced folder.
+//	- roles/resourcemanager.projectIamAdmin to update the project's IAM policy.
+//	- roles/iam.serviceAccountAdmin to update the IAM policy of service accounts.
+//
+func RemoveImpersonation(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "RemoveImpersonation", func(ctx context.Context) error {
+		var values removeimpersonation.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			i, err := services.InitIAM(ctx)
+			if err != nil {
+				return err
+			}
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return removeimpersonation.Execute(ctx, &values, &removeimpersonation.Services{
+				Resource: g.Resource,
+				IAM:      i,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// IAMRemoveDefaultEditor is the entry point for the remove default service account Editor role Cloud Function.
+//
+// This function will remove the Compute Engine and App Engine default service accounts from the
+// Editor role of a project and grant them the configured roles instead.
+//
+// Permissions required
+//	- roles/resourcemanager.folderAdmin to update the project's IAM policy.
+//	- roles/viewer to verify the affected project is within the enforced folder.
+//
+func IAMRemoveDefaultEditor(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "IAMRemoveDefaultEditor", func(ctx context.Context) error {
+		var values removedefaulteditor.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return removedefaulteditor.Execute(ctx, &values, &removedefaulteditor.Services{
+				Resource: g.Resource,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// DisableOldKeys is the entry point for the disable old service account keys Cloud Function.
+//
+// This Cloud Function will respond to Security Health Analytics **SERVICE_ACCOUNT_KEY_NOT_ROTATED**
+// and **USER_MANAGED_SERVICE_ACCOUNT_KEY** findings from **IAM_SCANNER**. User-managed keys of the
+// service account older than the configured age are disabled and, if the `sendgrid` output is
+// enabled, the project owners are notified.
+//
+// Permissions required
+//	- roles/iam.serviceAccountKeyAdmin to list and disable service account keys.
+//	- roles/viewer to verify the affected project is within the enforced folder and find its owners.
+//
+func DisableOldKeys(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "DisableOldKeys", func(ctx context.Context) error {
+		var values disableoldkeys.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			i, err := services.InitIAM(ctx)
+			if err != nil {
+				return err
+			}
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			output, err := disableoldkeys.Execute(ctx, &values, &disableoldkeys.Services{
+				IAM:      i,
+				Resource: g.Resource,
+			})
+			if err != nil {
+				return err
+			}
+			if output == nil {
+				return nil
+			}
+			for _, dest := range values.Output {
+				switch dest {
+				case "sendgrid":
+					to := append(values.SendGrid.To, output.Owners...)
+					subject := fmt.Sprintf("Service account keys of %q disabled", values.ServiceAccount)
+					body := fmt.Sprintf("The following keys of service account %q in project %q were disabled by Security Response Automation because they were not rotated:\n\n%s\n", values.ServiceAccount, values.ProjectID, strings.Join(output.DisabledKeys, "\n"))
+					if _, err := emails.Get(sendGridKey(ctx, "disable_old_keys", values.SendGrid.APIKey, values.SendGrid.From)).Send(ctx, subject, values.SendGrid.From, body, to); err != nil {
+						return err
+					}
+					logging.FromContext(ctx).Info("sent disabled keys notification to %d recipients", len(to))
+				}
+			}
+			return nil
+		default:
+			return err
+		}
+	})
+}
+
+// BackupIAMPolicies is the entry point for the IAM policy backup Cloud Function.
+//
+// This function is triggered on a schedule and stores a snapshot of the IAM policy of each
+// configured project or organization in a GCS bucket. These snapshots are used by RestoreIAMPolicy.
+//
+// Permissions required
+//	- roles/iam.securityReviewer to read IAM policies.
+//	- roles/storage.objectAdmin on the backup bucket to store snapshots.
+//
+func BackupIAMPolicies(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "BackupIAMPolicies", func(ctx context.Context) error {
+		var values backuppolicies.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			pb, err := services.InitPolicyBackup(ctx)
+			if err != nil {
+				return err
+			}
+			return backuppolicies.Execute(ctx, &values, &backuppolicies.Services{
+				PolicyBackup: pb,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// RestoreIAMPolicy is the entry point for the IAM policy restore Cloud Function.
+//
+// This function will replace the affected project's IAM policy with the most recent snapshot
+// taken by BackupIAMPolicies before the anomalous grant happened.
+//
+// Permissions required
+//	- roles/resourcemanager.folderAdmin to set IAM policies.
+//	- roles/storage.objectViewer on the backup bucket to read snapshots.
+//
+func RestoreIAMPolicy(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "RestoreIAMPolicy", func(ctx context.Context) error {
+		var values restorepolicy.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			pb, err := services.InitPolicyBackup(ctx)
+			if err != nil {
+				return err
+			}
+			return restorepolicy.Execute(ctx, &values, &restorepolicy.Services{
+				PolicyBackup: pb,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// SnapshotDisk is the entry point for the auto creation of GCE snapshots Cloud Function.
+//
+// Once a supported finding is received this Cloud Function will look for any existing disk snapshots
+// for the affected instance. If there are recent snapshots then no action is taken. This is so we
+// do not overwrite a recent snapshot. If we have not taken a snapshot recently, take a new snapshot
+// for each disk within the instance.
+//
+// Permissions required
+//	- roles/compute.instanceAdmin.v1 to manage disk snapshots.
+//
+func SnapshotDisk(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "SnapshotDisk", func(ctx context.Context) error {
+		var values createsnapshot.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			output, err := createsnapshot.Execute(ctx, &values, &createsnapshot.Services{
+				Host: g.Host,
+			})
+			if err != nil {
+				return err
+			}
+			for _, dest := range values.Output {
+				switch dest {
+				case "turbinia":
+					logging.FromContext(ctx).Info("turbinia output is enabled, sending each copied disk to turbinia")
+					turbiniaProjectID := values.Turbinia.ProjectID
+					turbiniaTopicName := values.Turbinia.Topic
+					turbiniaZone := values.Turbinia.Zone
+					diskNames := output.DiskNames
+					if err := services.SendTurbinia(ctx, turbiniaProjectID, turbiniaTopicName, turbiniaZone, diskNames); err != nil {
+						return err
+					}
+					logging.FromContext(ctx).Info("sent %d disks to turbinia", len(diskNames))
+				}
+			}
+			return nil
+		default:
+			return err
+		}
+	})
+}
+
+// CloseBucket will remove any public users from buckets found within the provided folders.
+//
+// Permissions required
+//	- roles/viewer to retrieve ancestry.
+//	- roles/storeage.admin to modify buckets.
+//	- roles/securitycenter.findingSecurityMarksWriter to mark skipped findings.
+//
+func CloseBucket(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "CloseBucket", func(ctx context.Context) error {
+		var values closebucket.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return closebucket.Execute(ctx, &values, &closebucket.Services{
+				Resource:              g.Resource,
+				SecurityCommandCenter: g.SecurityCommandCenter,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// RemovePublicPubSub will remove any public users from Pub/Sub topics and subscriptions found within the provided folders.
+//
+// Permissions required
+//	- roles/viewer to retrieve ancestry.
+//	- roles/pubsub.admin to modify topic and subscription IAM policies.
+//	- roles/securitycenter.findingSecurityMarksWriter to mark skipped findings.
+//
+func RemovePublicPubSub(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "RemovePublicPubSub", func(ctx context.Context) error {
+		var values removepublicpubsub.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			ps, err := services.InitPubSubAdmin(ctx)
+			if err != nil {
+				return err
+			}
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return removepublicpubsub.Execute(ctx, &values, &removepublicpubsub.Services{
+				PubSubAdmin:           ps,
+				SecurityCommandCenter: g.SecurityCommandCenter,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// RemovePublicKMS will remove any public users from Cloud KMS keys and key rings found within the provided folders.
+//
+// Permissions required
+//	- roles/viewer to retrieve ancestry.
+//	- roles/cloudkms.admin to modify key IAM policies and rotation schedules.
+//
+func RemovePublicKMS(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "RemovePublicKMS", func(ctx context.Context) error {
+		var values removepublickms.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			kms, err := services.InitKMS(ctx)
+			if err != nil {
+				return err
+			}
+			return removepublickms.Execute(ctx, &values, &removepublickms.Services{
+				KMS: kms,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// DisableAWSAccessKey will deactivate the AWS access key of an IAM user flagged by a GuardDuty finding.
+//
+// Permissions required
+//	- roles/secretmanager.secretAccessor to read the AWS credentials.
+//	- iam:UpdateAccessKey in the AWS account.
+//
+func DisableAWSAccessKey(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "DisableAWSAccessKey", func(ctx context.Context) error {
+		var values disableaccesskey.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			aws, err := services.InitAWS(ctx, values.CredentialsSecret)
+			if err != nil {
+				return err
+			}
+			return disableaccesskey.Execute(ctx, &values, &disableaccesskey.Services{
+				AWS: aws,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// IsolateEC2Instance will isolate an AWS EC2 instance flagged by a GuardDuty finding.
+//
+// The security groups of the instance are replaced with a security group of its VPC without rules.
+//
+// Permissions required
+//	- roles/secretmanager.secretAccessor to read the AWS credentials.
+//	- ec2:DescribeInstances, ec2:DescribeSecurityGroups, ec2:CreateSecurityGroup,
+//	  ec2:RevokeSecurityGroupEgress and ec2:ModifyInstanceAttribute in the AWS account.
+//
+func IsolateEC2Instance(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "IsolateEC2Instance", func(ctx context.Context) error {
+		var values isolateinstance.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			aws, err := services.InitAWS(ctx, values.CredentialsSecret)
+			if err != nil {
+				return err
+			}
+			return isolateinstance.Execute(ctx, &values, &isolateinstance.Services{
+				AWS: aws,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// RemovePublicBlobContainer will remove public access of an Azure blob container flagged by a
+// Defender for Cloud alert.
+//
+// Permissions required
+//	- roles/secretmanager.secretAccessor to read the Azure credentials.
+//	- Microsoft.Storage/storageAccounts/blobServices/containers/read and write on the storage
+//	  account, granted by the Storage Account Contributor role.
+//
+func RemovePublicBlobContainer(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "RemovePublicBlobContainer", func(ctx context.Context) error {
+		var values removepubliccontainer.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			azure, err := services.InitAzure(ctx, values.CredentialsSecret)
+			if err != nil {
+				return err
+			}
+			return removepubliccontainer.Execute(ctx, &values, &removepubliccontainer.Services{
+				Azure: azure,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// RegisteredAutomation will run the automation registered with the automations package that the
+// values were sent for, under its own name in the audit trail.
+//
+// Permissions required
+//	- the roles the registered automations require, granted to the automation service account.
+//
+func RegisteredAutomation(ctx context.Context, m pubsub.Message) error {
+	var values automations.Values
+	if err := json.Unmarshal(m.Data, &values); err != nil {
+		return err
+	}
+	return audited(ctx, m, values.Automation, func(ctx context.Context) error {
+		g, err := scoped(ctx)
+		if err != nil {
+			return err
+		}
+		return automations.Run(ctx, &values, g)
+	})
+}
+
+// QuarantineInstance will isolate a compromised GCE instance.
+//
+// The instance's network tags are replaced with a quarantine tag matched by deny all firewall rules
+// and the instance is optionally stopped, as enabled in the configuration.
+//
+// Permissions required
+//	- roles/viewer to retrieve ancestry.
+//	- roles/compute.instanceAdmin.v1 to set network tags and stop the instance.
+//	- roles/compute.securityAdmin to create the quarantine firewall rules.
+//
+func QuarantineInstance(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "QuarantineInstance", func(ctx context.Context) error {
+		var values quarantineinstance.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return quarantineinstance.Execute(ctx, &values, &quarantineinstance.Services{
+				Host:     g.Host,
+				Firewall: g.Firewall,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// RotateSecrets will rotate the Secret Manager secrets labeled as belonging to a compromised workload.
+//
+// Permissions required
+//	- roles/viewer to retrieve ancestry.
+//	- roles/secretmanager.admin to add and disable secret versions.
+//	- roles/pubsub.publisher to publish rotation requests to a hook topic.
+//	- roles/cloudfunctions.invoker on the rotation hook if it is an authenticated Cloud Function.
+//
+func RotateSecrets(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "RotateSecrets", func(ctx context.Context) error {
+		var values rotatesecrets.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			sm, err := services.InitSecretManager(ctx)
+			if err != nil {
+				return err
+			}
+			var hook *services.RotationHook
+			if values.HookURL != "" {
+				if hook, err = services.InitRotationHook(ctx, values.HookURL); err != nil {
+					return err
+				}
+			}
+			var ps *services.PubSub
+			if values.HookTopic != "" {
+				if ps, err = initPubSub(ctx); err != nil {
+					return err
+				}
+			}
+			return rotatesecrets.Execute(ctx, &values, &rotatesecrets.Services{
+				SecretManager: sm,
+				RotationHook:  hook,
+				PubSub:        ps,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// StopRogueJob will delete a Dataproc cluster or cancel a Dataproc or Dataflow job flagged for
+// cryptomining.
+//
+// Permissions required
+//	- roles/viewer to retrieve ancestry.
+//	- roles/dataproc.editor to delete clusters and cancel jobs.
+//	- roles/dataflow.developer to cancel Dataflow jobs.
+//
+func StopRogueJob(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "StopRogueJob", func(ctx context.Context) error {
+		var values stoproguejob.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			dataproc, err := services.InitDataproc(ctx)
+			if err != nil {
+				return err
+			}
+			dataflow, err := services.InitDataflow(ctx)
+			if err != nil {
+				return err
+			}
+			return stoproguejob.Execute(ctx, &values, &stoproguejob.Services{
+				Dataproc: dataproc,
+				Dataflow: dataflow,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// DisableBilling will detach the billing account of a project with sustained cryptomining findings.
+//
+// Billing is only disabled once the approvers required approved the action with their signed links.
+//
+// Permissions required
+//	- roles/viewer to retrieve ancestry.
+//	- roles/billing.projectManager to detach the project's billing account.
+//	- roles/datastore.user to verify the approval of the action.
+//	- roles/securitycenter.findingSecurityMarksWriter to mark findings once billing is disabled.
+//
+func DisableBilling(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "DisableBilling", func(ctx context.Context) error {
+		var values disablebilling.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			values.ApprovalID = m.Attributes["approval_id"]
+			billing, err := services.InitBilling(ctx)
+			if err != nil {
+				return err
+			}
+			conf, err := router.Config()
+			if err != nil {
+				return err
+			}
+			approvals, err := services.InitApprovals(ctx, projectID, conf.Spec.Approval.Key)
+			if err != nil {
+				return err
+			}
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return disablebilling.Execute(ctx, &values, &disablebilling.Services{
+				Approvals:             approvals,
+				Billing:               billing,
+				SecurityCommandCenter: g.SecurityCommandCenter,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// RemovePublicRepository will remove any public users from Artifact Registry repositories and
+// Container Registry buckets found within the provided folders.
+//
+// Permissions required
+//	- roles/viewer to retrieve ancestry.
+//	- roles/artifactregistry.admin to modify repository IAM policies.
+//	- roles/storage.admin to modify Container Registry bucket IAM policies and ACLs.
+//
+func RemovePublicRepository(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "RemovePublicRepository", func(ctx context.Context) error {
+		var values removepublicrepository.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			ar, err := services.InitArtifactRegistry(ctx)
+			if err != nil {
+				return err
+			}
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return removepublicrepository.Execute(ctx, &values, &removepublicrepository.Services{
+				ArtifactRegistry: ar,
+				Resource:         g.Resource,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// QuarantineImage will tag a malicious Artifact Registry image as quarantined, remove its other
+// tags and optionally remove it from Binary Authorization allowlists.
+//
+// Permissions required
+//	- roles/viewer to retrieve ancestry.
+//	- roles/artifactregistry.repoAdmin to create and delete tags.
+//	- roles/binaryauthorization.policyEditor to update Binary Authorization policies.
+//
+func QuarantineImage(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "QuarantineImage", func(ctx context.Context) error {
+		var values quarantineimage.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			ar, err := services.InitArtifactRegistry(ctx)
+			if err != nil {
+				return err
+			}
+			ba, err := services.InitBinaryAuthorization(ctx)
+			if err != nil {
+				return err
+			}
+			return quarantineimage.Execute(ctx, &values, &quarantineimage.Services{
+				ArtifactRegistry:    ar,
+				BinaryAuthorization: ba,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// OpenFirewall will remediate an open firewall.
+//
+// Permissions required
+//	- roles/viewer to retrieve ancestry.
+//	- roles/compute.securityAdmin to modify firewall rules.
+//
+func OpenFirewall(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "OpenFirewall", func(ctx context.Context) error {
+		var values openfirewall.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			err = openfirewall.Execute(ctx, &values, &openfirewall.Services{
+				Firewall: g.Firewall,
+				Host:     g.Host,
+				Resource: g.Resource,
+			})
+			if err != nil {
+				return err
+			}
+			return nil
+		default:
+			return err
+		}
+	})
+}
+
+// DeleteFirewallRules deletes or disables firewall rules created by a compromised identity.
+//
+// This Cloud Function will respond to Event Threat Detection **Firewall Rule Created** findings.
+// Only rules created after the incident started are remediated.
+//
+// Permissions required
+//	- roles/viewer to retrieve ancestry.
+//	- roles/compute.securityAdmin to get, delete and disable firewall rules.
+//
+func DeleteFirewallRules(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "DeleteFirewallRules", func(ctx context.Context) error {
+		var values deletefirewallrules.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return deletefirewallrules.Execute(ctx, &values, &deletefirewallrules.Services{
+				Firewall: g.Firewall,
+				Resource: g.Resource,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// RemoveExternalExposure removes the external HTTP(S) load balancer exposure of a backend service.
+//
+// This Cloud Function will respond to **Unintended External Exposure** findings on global backend
+// services. The external forwarding rules routing to the backend service are deleted, or the backend
+// service is drained when the remediation action is "disable".
+//
+// Permissions required
+//	- roles/viewer to retrieve ancestry.
+//	- roles/compute.loadBalancerAdmin to delete forwarding rules and update backend services.
+//
+func RemoveExternalExposure(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "RemoveExternalExposure", func(ctx context.Context) error {
+		var values removeexternalexposure.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			lb, err := services.InitLoadBalancer(ctx)
+			if err != nil {
+				return err
+			}
+			return removeexternalexposure.Execute(ctx, &values, &removeexternalexposure.Services{
+				LoadBalancer: lb,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// DisableSerialPort disables interactive serial port access on a GCE instance.
+//
+// This Cloud Function will respond to Security Health Analytics **Compute Serial Ports Enabled**
+// findings from **Compute Instance Scanner**. The serial-port-enable metadata of the affected
+// instance is set to false and its prior value logged.
+//
+// Permissions required
+//	- roles/compute.instanceAdmin.v1 to set instance metadata.
+//
+func DisableSerialPort(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "DisableSerialPort", func(ctx context.Context) error {
+		var values disableserialport.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return disableserialport.Execute(ctx, &values, &disableserialport.Services{
+				Host:     g.Host,
+				Resource: g.Resource,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// EnableShieldedVM enables Secure Boot, vTPM and integrity monitoring on a GCE instance.
+//
+// This Cloud Function will respond to Security Health Analytics **Shielded VM Disabled** findings
+// from **Compute Instance Scanner**. A running instance is stopped before Secure Boot or vTPM are
+// enabled and started again afterwards.
+//
+// Permissions required
+//	- roles/viewer to retrieve ancestry.
+//	- roles/compute.instanceAdmin.v1 to stop, start and update the Shielded VM options of an instance.
+//
+func EnableShieldedVM(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "EnableShieldedVM", func(ctx context.Context) error {
+		var values enableshieldedvm.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return enableshieldedvm.Execute(ctx, &values, &enableshieldedvm.Services{
+				Host:     g.Host,
+				Resource: g.Resource,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// EnableOSLogin enables OS Login for a project.
+//
+// This Cloud Function will respond to Security Health Analytics **OS_LOGIN_DISABLED** findings
+// from **Compute Instance Scanner**. The enable-oslogin project metadata is set to true, optionally
+// only for projects within the configured folders.
+//
+// Permissions required
+//	- roles/viewer to retrieve ancestry.
+//	- roles/compute.instanceAdmin.v1 to set project metadata.
+//
+func EnableOSLogin(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "EnableOSLogin", func(ctx context.Context) error {
+		var values enableoslogin.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return enableoslogin.Execute(ctx, &values, &enableoslogin.Services{
+				Host:     g.Host,
+				Resource: g.Resource,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// EnablePrivateAccess enables Private Google Access on a subnetwork.
+//
+// This Cloud Function will respond to Security Health Analytics **PRIVATE_GOOGLE_ACCESS_DISABLED** findings
+// from **Network Scanner**. If configured, external IPs are also denied for the project's instances.
+//
+// Permissions required
+//	- roles/viewer to retrieve ancestry.
+//	- roles/compute.networkAdmin to update the subnetwork.
+//	- roles/orgpolicy.policyAdmin to set the compute.vmExternalIpAccess policy.
+//
+func EnablePrivateAccess(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "EnablePrivateAccess", func(ctx context.Context) error {
+		var values enableprivateaccess.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return enableprivateaccess.Execute(ctx, &values, &enableprivateaccess.Services{
+				Firewall: g.Firewall,
+				Resource: g.Resource,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// EnableFlowLogs enables VPC Flow Logs on a subnetwork.
+//
+// This Cloud Function will respond to Security Health Analytics **FLOW_LOGS_DISABLED** findings
+// from **Network Scanner**.
+//
+// Permissions required
+//	- roles/viewer to retrieve ancestry.
+//	- roles/compute.networkAdmin to update the subnetwork.
+//
+func EnableFlowLogs(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "EnableFlowLogs", func(ctx context.Context) error {
+		var values enableflowlogs.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return enableflowlogs.Execute(ctx, &values, &enableflowlogs.Services{
+				Firewall: g.Firewall,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// RemoveNonOrganizationMembers removes all members that do not match the organization domain.
+//
+// This Cloud Function will respond to Security Health Analytics **NON_ORG_IAM_MEMBER** findings from **IAM Scanner**.
+// All user and domain member types (user:, domain:) that do not correspond to the organization will be removed
+// from policy binding. Groups and service accounts of foreign projects are removed if configured, optionally
+// looking up the customer owning a group in Cloud Identity. Users not from an allowed domain are kept if they
+// are members of one of the configured Cloud Identity groups.
+// Findings on a folder or organization remove the members from its policy instead of the project's.
+// If escalation is configured, projects with repeated findings also have the iam.allowedPolicyMemberDomains
+// constraint applied.
+//
+// Permissions required
+//	- roles/resourcemanager.organizationAdmin to get org info and policies and set policies.
+//	- roles/resourcemanager.folderAdmin to get folders and set folder policies.
+//	- roles/orgpolicy.policyAdmin to restrict member domains on escalation.
+//	- roles/storage.objectAdmin on the escalation bucket to count findings.
+//	- Groups Reader admin role in the Google Admin console to look up groups and their members.
+//
+func RemoveNonOrganizationMembers(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "RemoveNonOrganizationMembers", func(ctx context.Context) error {
+		var values removenonorgmembers.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			var counter *services.Counter
+			if values.Escalation.Threshold > 0 {
+				if counter, err = services.InitCounter(ctx); err != nil {
+					return err
+				}
+			}
+			var cloudIdentity *services.CloudIdentity
+			if values.Groups.Lookup || len(values.Directory.Groups) > 0 {
+				if cloudIdentity, err = services.InitCloudIdentity(ctx); err != nil {
+					return err
+				}
+			}
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return removenonorgmembers.Execute(ctx, &values, &removenonorgmembers.Services{
+				Resource:      g.Resource,
+				Counter:       counter,
+				CloudIdentity: cloudIdentity,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// RemovePublicIP removes all the external IP addresses of a GCE instance.
+//
+// This Cloud Function will respond to Security Health Analytics **Public IP Address** findings
+// from **Compute Instance Scanner**. All public IP addresses of the affected instance will be
+// deleted when this function is activated.
+//
+// Permissions required
+//	- roles/compute.instanceAdmin.v1 to get instance data and delete access config.
+//
+func RemovePublicIP(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "RemovePublicIP", func(ctx context.Context) error {
+		var values removepublicip.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return removepublicip.Execute(ctx, &values, &removepublicip.Services{
+				Host:     g.Host,
+				Resource: g.Resource,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// BlockProjectSSHKeys blocks project-wide SSH keys on a GCE instance.
+//
+// This Cloud Function will respond to Security Health Analytics **Compute Project Wide SSH Keys Allowed**
+// findings from **Compute Instance Scanner**. The block-project-ssh-keys metadata of the affected
+// instance is set to true and, if configured, matching project-wide SSH keys are removed.
+//
+// Permissions required
+//	- roles/compute.instanceAdmin.v1 to set instance and project metadata.
+//
+func BlockProjectSSHKeys(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "BlockProjectSSHKeys", func(ctx context.Context) error {
+		var values blockprojectsshkeys.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return blockprojectsshkeys.Execute(ctx, &values, &blockprojectsshkeys.Services{
+				Host:     g.Host,
+				Resource: g.Resource,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// ClosePublicDataset removes public access of a BigQuery dataset.
+//
+// This Cloud Function will respond to Security Health Analytics **Public Dataset** findings
+// from **Dataset Scanner**. All public access of the affected dataset will be
+// removed when this function is activated.
+//
+// Permissions required
+//	- roles/bigquery.dataOwner to get and update dataset metadata.
+//
+func ClosePublicDataset(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "ClosePublicDataset", func(ctx context.Context) error {
+		var values closepublicdataset.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			bigquery, err := services.InitBigQuery(ctx, values.ProjectID)
+			if err != nil {
+				return err
+			}
+			return closepublicdataset.Execute(ctx, &values, &closepublicdataset.Services{
+				BigQuery: bigquery,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// EnableBucketOnlyPolicy Enable bucket only policy on a GCS bucket.
+//
+// This Cloud Function will respond to Security Health Analytics **BUCKET_POLICY_ONLY_DISABLED** findings
+// from **STORAGE_SCANNER**. Bucket only IAM policy will be enforced on the bucket.
+//
+// Permissions required
+//	- roles/storage.admin to change the Bucket policy mode.
+//
+func EnableBucketOnlyPolicy(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "EnableBucketOnlyPolicy", func(ctx context.Context) error {
+		var values enablebucketonlypolicy.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return enablebucketonlypolicy.Execute(ctx, &values, &enablebucketonlypolicy.Services{
+				Resource: g.Resource,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// CloseCloudSQL removes public IP for a Cloud SQL instance.
+//
+// This Cloud Function will respond to Security Health Analytics **Public SQL Instance** findings
+// from **SQL Scanner**. All public IP addresses of the affected instance will be
+// deleted when this function is activated.
+//
+// Permissions required
+//	- roles/cloudsql.editor to get instance data and delete access config.
+//
+func CloseCloudSQL(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "CloseCloudSQL", func(ctx context.Context) error {
+		var values removepublic.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return removepublic.Execute(ctx, &values, &removepublic.Services{
+				CloudSQL: g.CloudSQL,
+				Resource: g.Resource,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// CloudSQLRequireSSL enables the SSL requirement for a Cloud SQL instance.
+//
+// This Cloud Function will respond to Security Health Analytics **Public SQL Instance** findings
+// from **SQL Scanner**. All public IP addresses of the affected instance will be
+// deleted when this function is activated.
+//
+// Permissions required
+//	- roles/cloudsql.editor to get instance data and delete access config.
+//
+func CloudSQLRequireSSL(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "CloudSQLRequireSSL", func(ctx context.Context) error {
+		var values requiressl.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return requiressl.Execute(ctx, &values, &requiressl.Services{
+				CloudSQL: g.CloudSQL,
+				Resource: g.Resource,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// DisableDashboard will disable the Kubernetes dashboard addon.
+//
+// This Cloud Function will respond to Security Health Analytics **Web UI Enabled** findings
+// from **Container Scanner**. The Kubernetes dashboard addon will be disabled when this
+// function is activated.
+//
+// Permissions required
+//	- roles/container.clusterAdmin update cluster addon.
+//
+func DisableDashboard(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "DisableDashboard", func(ctx context.Context) error {
+		var values disabledashboard.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return disabledashboard.Execute(ctx, &values, &disabledashboard.Services{
+				Container: g.Container,
+				Resource:  g.Resource,
+			})
+		default:
+			return err
+		}
+	})
 }
 
-// Filter is the entry point for the Filter Cloud function.
-// This function will receive all findings and filter them against
-// any user-defined Rego policies before forwarding along to the
-// Router function.
-func Filter(ctx context.Context, m pubsub.Message) error {
-	ps, err := services.InitPubSub(ctx, projectID)
-	if err != nil {
-		return err
-	}
-	return filter.Execute(ctx, m, &filter.Services{
-		PubSub:                ps,
-		Logger:                svcs.Logger,
-		SecurityCommandCenter: svcs.SecurityCommandCenter,
+// DeletePod will delete a compromised GKE pod.
+//
+// This Cloud Function will respond to Container Threat Detection findings, such as cryptomining
+// binaries being executed. The reported pod is deleted, or annotated with the finding name when
+// dry run is on.
+//
+// Permissions required
+//	- roles/container.developer to delete and annotate pods.
+//
+func DeletePod(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "DeletePod", func(ctx context.Context) error {
+		var values deletepod.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			cluster, err := g.Container.Cluster(ctx, values.ProjectID, values.Zone, values.ClusterID)
+			if err != nil {
+				return err
+			}
+			k8s, err := services.InitKubernetes(ctx, cluster)
+			if err != nil {
+				return err
+			}
+			return deletepod.Execute(ctx, &values, &deletepod.Services{
+				Kubernetes: k8s,
+			})
+		default:
+			return err
+		}
 	})
 }
 
-// Router is the entry point for the router Cloud Function.
+// DrainNode will cordon, quarantine and drain a GKE node.
 //
-// This Cloud Function will receive all findings and route them to configured automation.
-func Router(ctx context.Context, m pubsub.Message) error {
-	ps, err := services.InitPubSub(ctx, projectID)
-	if err != nil {
-		return err
-	}
-	conf, err := router.Config()
-	if err != nil {
-		return err
-	}
-	return router.Execute(ctx, &router.Values{
-		Finding: m.Data,
-	}, &router.Services{
-		PubSub:                ps,
-		Configuration:         conf,
-		Logger:                svcs.Logger,
-		Resource:              svcs.Resource,
-		SecurityCommandCenter: svcs.SecurityCommandCenter,
+// This Cloud Function will respond to Container Threat Detection findings that identify a node.
+// The node is marked unschedulable, labeled with quarantine=true so forensics can attach and
+// all workloads except DaemonSet and mirror pods are evicted.
+//
+// Permissions required
+//	- roles/container.admin to patch nodes and evict pods.
+//
+func DrainNode(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "DrainNode", func(ctx context.Context) error {
+		var values drainnode.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			cluster, err := g.Container.Cluster(ctx, values.ProjectID, values.Zone, values.ClusterID)
+			if err != nil {
+				return err
+			}
+			k8s, err := services.InitKubernetes(ctx, cluster)
+			if err != nil {
+				return err
+			}
+			return drainnode.Execute(ctx, &values, &drainnode.Services{
+				Kubernetes: k8s,
+			})
+		default:
+			return err
+		}
 	})
 }
 
-// IAMRevoke is the entry point for the IAM revoker Cloud Function.
+// EnableAuthorizedNetworks will enable master authorized networks on a GKE cluster.
 //
-// This function will attempt to revoke the external members added to the policy if they
-// match the provided list of disallowed domains. Additionally this method will only remove
-// members if the project they were added to is within the specified folders. This
-// configuration allows you to take a remediation action only on specific members and folders.
-// For example, you may have a folder "development" where users can experiment without strict
-// policies. However in your "production" folder you may want to revoke any grants that ETD
-// finds as long as they match the domains you specify.
+// This Cloud Function will respond to Security Health Analytics **Master Authorized Networks Disabled**
+// findings from **Container Scanner**. Only the configured CIDR blocks will be allowed to reach
+// the Kubernetes master when this function is activated.
 //
 // Permissions required
-// 	- roles/resourcemanager.folderAdmin to revoke IAM grants.
-//	- roles/viewer to verify the affected project is within the enforced folder.
+//	- roles/container.clusterAdmin update cluster master authorized networks.
 //
-func IAMRevoke(ctx context.Context, m pubsub.Message) error {
-	var values revoke.Values
-	switch err := json.Unmarshal(m.Data, &values); err {
-	case nil:
-		return revoke.Execute(ctx, &values, &revoke.Services{
-			Resource: svcs.Resource,
-			Logger:   svcs.Logger,
-		})
-	default:
-		return err
-	}
+func EnableAuthorizedNetworks(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "EnableAuthorizedNetworks", func(ctx context.Context) error {
+		var values enableauthorizednetworks.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return enableauthorizednetworks.Execute(ctx, &values, &enableauthorizednetworks.Services{
+				Container: g.Container,
+				Resource:  g.Resource,
+			})
+		default:
+			return err
+		}
+	})
 }
 
-// SnapshotDisk is the entry point for the auto creation of GCE snapshots Cloud Function.
+// EnableAuditLogs enables the Audit Logs to specific project
 //
-// Once a supported finding is received this Cloud Function will look for any existing disk snapshots
-// for the affected instance. If there are recent snapshots then no action is taken. This is so we
-// do not overwrite a recent snapshot. If we have not taken a snapshot recently, take a new snapshot
-// for each disk within the instance.
+// This Cloud Function will respond to Security Health Analytics **AUDIT_LOGGING_DISABLED** findings
+// from **LOGGING_SCANNER**.
 //
 // Permissions required
-//	- roles/compute.instanceAdmin.v1 to manage disk snapshots.
+//	- roles/resourcemanager.folderAdmin to get/update resource policy from projects in folder.
+//	- roles/editor to get/update resource policy to specific project.
 //
-func SnapshotDisk(ctx context.Context, m pubsub.Message) error {
-	var values createsnapshot.Values
-	switch err := json.Unmarshal(m.Data, &values); err {
-	case nil:
-		output, err := createsnapshot.Execute(ctx, &values, &createsnapshot.Services{
-			Host:   svcs.Host,
-			Logger: svcs.Logger,
-		})
-		if err != nil {
+func EnableAuditLogs(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "EnableAuditLogs", func(ctx context.Context) error {
+		var values enableauditlogs.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return enableauditlogs.Execute(ctx, &values, &enableauditlogs.Services{
+				Resource: g.Resource,
+			})
+		default:
 			return err
 		}
-		for _, dest := range values.Output {
-			switch dest {
-			case "turbinia":
-				log.Println("turbinia output is enabled, sending each copied disk to turbinia")
-				turbiniaProjectID := values.Turbinia.ProjectID
-				turbiniaTopicName := values.Turbinia.Topic
-				turbiniaZone := values.Turbinia.Zone
-				diskNames := output.DiskNames
-				if err := services.SendTurbinia(ctx, turbiniaProjectID, turbiniaTopicName, turbiniaZone, diskNames); err != nil {
-					return err
+	})
+}
+
+// UpdatePassword updates the root password for a Cloud SQL instance.
+//
+// This Cloud Function will respond to Security Health Analytics **SQL No Root Password** findings
+// from **SQL Scanner**. The root user (or postgres user for PostgreSQL) of the affected instance
+// will be updated with a new password when this function is activated. The new password is stored
+// in Secret Manager and, if the `sendgrid` output is enabled, the secret reference is emailed.
+//
+// Permissions required
+//	- roles/cloudsql.admin to update a user password.
+//	- roles/secretmanager.admin to create the secret and add the new password as a version.
+//
+func UpdatePassword(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "UpdatePassword", func(ctx context.Context) error {
+		var values updatepassword.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			sm, err := services.InitSecretManager(ctx)
+			if err != nil {
+				return err
+			}
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			output, err := updatepassword.Execute(ctx, &values, &updatepassword.Services{
+				CloudSQL:      g.CloudSQL,
+				SecretManager: sm,
+				Resource:      g.Resource,
+			})
+			if err != nil {
+				return err
+			}
+			if output == nil {
+				return nil
+			}
+			for _, dest := range values.Output {
+				switch dest {
+				case "sendgrid":
+					subject := fmt.Sprintf("Cloud SQL instance %q password rotated", values.InstanceName)
+					body := fmt.Sprintf("The password of Cloud SQL instance %q in project %q was rotated by Security Response Automation.\n\nThe new password is stored in Secret Manager: %s\n", values.InstanceName, values.ProjectID, output.SecretVersion)
+					if _, err := emails.Get(sendGridKey(ctx, "cloud_sql_update_password", values.SendGrid.APIKey, values.SendGrid.From)).Send(ctx, subject, values.SendGrid.From, body, values.SendGrid.To); err != nil {
+						return err
+					}
+					logging.FromContext(ctx).Info("sent password rotation notification to %d recipients", len(values.SendGrid.To))
 				}
-				svcs.Logger.Info("sent %d disks to turbinia", len(diskNames))
 			}
+			return nil
+		default:
+			return err
 		}
-		return nil
-	default:
-		return err
-	}
+	})
 }
 
-// CloseBucket will remove any public users from buckets found within the provided folders.
+// EnforceAuthentication requires authentication to invoke a public Cloud Run service.
+//
+// This Cloud Function will respond to Security Health Analytics **Public Cloud Run Service**
+// findings. The allUsers and allAuthenticatedUsers members are removed from the invoker role and
+// the service is annotated with the finding ID.
 //
 // Permissions required
-//	- roles/viewer to retrieve ancestry.
-//	- roles/storeage.admin to modify buckets.
+//	- roles/run.admin to update the IAM policy and annotations of a service.
+//	- roles/iam.serviceAccountUser to replace a service running as a service account.
 //
-func CloseBucket(ctx context.Context, m pubsub.Message) error {
-	var values closebucket.Values
-	switch err := json.Unmarshal(m.Data, &values); err {
-	case nil:
-		return closebucket.Execute(ctx, &values, &closebucket.Services{
-			Resource: svcs.Resource,
-			Logger:   svcs.Logger,
-		})
-	default:
-		return err
-	}
+func EnforceAuthentication(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "EnforceAuthentication", func(ctx context.Context) error {
+		var values enforceauthentication.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			serverless, err := services.InitServerless(ctx)
+			if err != nil {
+				return err
+			}
+			return enforceauthentication.Execute(ctx, &values, &enforceauthentication.Services{
+				Serverless: serverless,
+			})
+		default:
+			return err
+		}
+	})
 }
 
-// OpenFirewall will remediate an open firewall.
+// RemovePublicInvoker removes allUsers from the invoker role of a Cloud Function or Cloud Run service.
+//
+// This Cloud Function will respond to Security Health Analytics **Public Cloud Function** and
+// **Public Cloud Run Service** findings. The allUsers member will be removed from the invoker role
+// when this function is activated.
+//
+// Permissions required
+//	- roles/cloudfunctions.admin to update the IAM policy of a function.
+//	- roles/run.admin to update the IAM policy of a service.
+//
+func RemovePublicInvoker(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "RemovePublicInvoker", func(ctx context.Context) error {
+		var values removepublicinvoker.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			serverless, err := services.InitServerless(ctx)
+			if err != nil {
+				return err
+			}
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return removepublicinvoker.Execute(ctx, &values, &removepublicinvoker.Services{
+				Serverless: serverless,
+				Resource:   g.Resource,
+			})
+		default:
+			return err
+		}
+	})
+}
+
+// EnableDNSSEC turns DNSSEC on for a Cloud DNS managed zone.
+//
+// This Cloud Function will respond to Security Health Analytics **DNSSEC_DISABLED** findings
+// from **DNS Scanner**. Zones within the configured allow list are skipped.
 //
 // Permissions required
 //	- roles/viewer to retrieve ancestry.
-//	- roles/compute.securityAdmin to modify firewall rules.
+//	- roles/dns.admin to get and patch managed zones.
 //
-func OpenFirewall(ctx context.Context, m pubsub.Message) error {
-	var values openfirewall.Values
-	switch err := json.Unmarshal(m.Data, &values); err {
-	case nil:
-		err := openfirewall.Execute(ctx, &values, &openfirewall.Services{
-			Firewall: svcs.Firewall,
-			Resource: svcs.Resource,
-			Logger:   svcs.Logger,
-		})
-		if err != nil {
+func EnableDNSSEC(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "EnableDNSSEC", func(ctx context.Context) error {
+		var values enablednssec.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			dns, err := services.InitDNS(ctx)
+			if err != nil {
+				return err
+			}
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return enablednssec.Execute(ctx, &values, &enablednssec.Services{
+				DNS:                   dns,
+				SecurityCommandCenter: g.SecurityCommandCenter,
+			})
+		default:
 			return err
 		}
-		return nil
-	default:
-		return err
-	}
+	})
 }
 
-// RemoveNonOrganizationMembers removes all members that do not match the organization domain.
+// RestrictAPIKey deletes or restricts an exposed API key.
 //
-// This Cloud Function will respond to Security Health Analytics **NON_ORG_IAM_MEMBER** findings from **IAM Scanner**.
-// All user member types (user:) that do not correspond to the organization will be removed from policy binding.
+// This Cloud Function will respond to Security Health Analytics **API_KEY_EXISTS**,
+// **API_KEY_APIS_UNRESTRICTED** and **API_KEY_APPS_UNRESTRICTED** findings. The key is either
+// deleted or has the configured application and API restrictions applied.
 //
 // Permissions required
-//	- roles/resourcemanager.organizationAdmin to get org info and policies and set policies.
+//	- roles/viewer to retrieve ancestry.
+//	- roles/serviceusage.apiKeysAdmin to delete and update API keys.
 //
-func RemoveNonOrganizationMembers(ctx context.Context, m pubsub.Message) error {
-	var values removenonorgmembers.Values
-	switch err := json.Unmarshal(m.Data, &values); err {
-	case nil:
-		return removenonorgmembers.Execute(ctx, &values, &removenonorgmembers.Services{
-			Logger:   svcs.Logger,
-			Resource: svcs.Resource,
-		})
-	default:
-		return err
-	}
+func RestrictAPIKey(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "RestrictAPIKey", func(ctx context.Context) error {
+		var values restrictapikey.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			apiKeys, err := services.InitAPIKeys(ctx)
+			if err != nil {
+				return err
+			}
+			return restrictapikey.Execute(ctx, &values, &restrictapikey.Services{
+				APIKeys: apiKeys,
+			})
+		default:
+			return err
+		}
+	})
 }
 
-// RemovePublicIP removes all the external IP addresses of a GCE instance.
+// SuspendUser is the entry point for the Google Workspace user suspension Cloud Function.
 //
-// This Cloud Function will respond to Security Health Analytics **Public IP Address** findings
-// from **Compute Instance Scanner**. All public IP addresses of the affected instance will be
-// deleted when this function is activated.
+// This function will suspend a compromised user and revoke their sign-in cookies, ending any
+// existing sessions. Only users within the configured organizational units are suspended.
 //
 // Permissions required
-//	- roles/compute.instanceAdmin.v1 to get instance data and delete access config.
+//	- Google Workspace User Management Admin role assigned to the automation service account.
 //
-func RemovePublicIP(ctx context.Context, m pubsub.Message) error {
-	var values removepublicip.Values
-	switch err := json.Unmarshal(m.Data, &values); err {
-	case nil:
-		return removepublicip.Execute(ctx, &values, &removepublicip.Services{
-			Host:     svcs.Host,
-			Resource: svcs.Resource,
-			Logger:   svcs.Logger,
-		})
-	default:
-		return err
-	}
+func SuspendUser(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "SuspendUser", func(ctx context.Context) error {
+		var values suspenduser.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			a, err := services.InitAdmin(ctx)
+			if err != nil {
+				return err
+			}
+			return suspenduser.Execute(ctx, &values, &suspenduser.Services{
+				Admin: a,
+			})
+		default:
+			return err
+		}
+	})
 }
 
-// ClosePublicDataset removes public access of a BigQuery dataset.
+// EnforceReenrollment is the entry point for the Google Workspace re-enrollment Cloud Function.
 //
-// This Cloud Function will respond to Security Health Analytics **Public Dataset** findings
-// from **Dataset Scanner**. All public access of the affected dataset will be
-// removed when this function is activated.
+// This function will require a user whose password leaked to change it at next sign-in and sign
+// them out. Users not enrolled in 2-Step Verification are moved to the configured enrollment
+// organizational unit. Only users within the configured organizational units are changed.
 //
 // Permissions required
-//	- roles/bigquery.dataOwner to get and update dataset metadata.
+//	- Google Workspace User Management Admin role assigned to the automation service account.
 //
-func ClosePublicDataset(ctx context.Context, m pubsub.Message) error {
-	var values closepublicdataset.Values
-	switch err := json.Unmarshal(m.Data, &values); err {
-	case nil:
-		bigquery, err := services.InitBigQuery(ctx, values.ProjectID)
-		if err != nil {
+func EnforceReenrollment(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "EnforceReenrollment", func(ctx context.Context) error {
+		var values enforcereenrollment.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			a, err := services.InitAdmin(ctx)
+			if err != nil {
+				return err
+			}
+			return enforcereenrollment.Execute(ctx, &values, &enforcereenrollment.Services{
+				Admin: a,
+			})
+		default:
 			return err
 		}
-		return closepublicdataset.Execute(ctx, &values, &closepublicdataset.Services{
-			BigQuery: bigquery,
-			Logger:   svcs.Logger,
-		})
-	default:
-		return err
-	}
+	})
 }
 
-// EnableBucketOnlyPolicy Enable bucket only policy on a GCS bucket.
+// RevokeUserTokens is the entry point for the Google Workspace token revocation Cloud Function.
 //
-// This Cloud Function will respond to Security Health Analytics **BUCKET_POLICY_ONLY_DISABLED** findings
-// from **STORAGE_SCANNER**. Bucket only IAM policy will be enforced on the bucket.
+// This function will revoke every OAuth 2.0 token and application-specific password of a flagged
+// user. The user keeps access to their account but sessions held by applications end.
 //
 // Permissions required
-//	- roles/storage.admin to change the Bucket policy mode.
+//	- Google Workspace User Management Admin role assigned to the automation service account.
 //
-func EnableBucketOnlyPolicy(ctx context.Context, m pubsub.Message) error {
-	var values enablebucketonlypolicy.Values
-	switch err := json.Unmarshal(m.Data, &values); err {
-	case nil:
-		return enablebucketonlypolicy.Execute(ctx, &values, &enablebucketonlypolicy.Services{
-			Resource: svcs.Resource,
-			Logger:   svcs.Logger,
-		})
-	default:
-		return err
-	}
+func RevokeUserTokens(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "RevokeUserTokens", func(ctx context.Context) error {
+		var values revoketokens.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			a, err := services.InitAdmin(ctx)
+			if err != nil {
+				return err
+			}
+			return revoketokens.Execute(ctx, &values, &revoketokens.Services{
+				Admin: a,
+			})
+		default:
+			return err
+		}
+	})
 }
 
-// CloseCloudSQL removes public IP for a Cloud SQL instance.
+// UndoRemediation is the entry point for the undo Cloud Function.
 //
-// This Cloud Function will respond to Security Health Analytics **Public SQL Instance** findings
-// from **SQL Scanner**. All public IP addresses of the affected instance will be
-// deleted when this function is activated.
+// This Cloud Function restores the resources changed by a remediation to the state they were in
+// before it. The remediation ID is logged by the automation and recorded in the audit trail. IAM
+// policies are overwritten with the prior policy and firewall rules are reverted or recreated.
 //
 // Permissions required
-//	- roles/cloudsql.editor to get instance data and delete access config.
+//	- roles/resourcemanager.folderAdmin to set IAM policies.
+//	- roles/compute.securityAdmin to update and create firewall rules.
+//	- roles/datastore.user to read the prior states.
 //
-func CloseCloudSQL(ctx context.Context, m pubsub.Message) error {
-	var values removepublic.Values
-	switch err := json.Unmarshal(m.Data, &values); err {
-	case nil:
-		return removepublic.Execute(ctx, &values, &removepublic.Services{
-			CloudSQL: svcs.CloudSQL,
-			Resource: svcs.Resource,
-			Logger:   svcs.Logger,
-		})
-	default:
-		return err
-	}
+func UndoRemediation(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "UndoRemediation", func(ctx context.Context) error {
+		var values undo.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			u, err := services.InitUndo(ctx, projectID)
+			if err != nil {
+				return err
+			}
+			return undo.Execute(ctx, &values, &undo.Services{
+				Undo: u,
+			})
+		default:
+			return err
+		}
+	})
 }
 
-// CloudSQLRequireSSL enables the SSL requirement for a Cloud SQL instance.
+// ExpireExemptions is the entry point for the Cloud Function clearing expired exemptions.
 //
-// This Cloud Function will respond to Security Health Analytics **Public SQL Instance** findings
-// from **SQL Scanner**. All public IP addresses of the affected instance will be
-// deleted when this function is activated.
+// This function is triggered on a schedule. It clears the exemption marks that expired and sends
+// the findings they suppressed that are still active back to the router to be remediated.
 //
 // Permissions required
-//	- roles/cloudsql.editor to get instance data and delete access config.
+//	- roles/securitycenter.assetSecurityMarksWriter to clear exemption marks.
+//	- roles/securitycenter.findingsViewer to check whether findings are still active.
+//	- roles/datastore.user to read the suppressed findings.
 //
-func CloudSQLRequireSSL(ctx context.Context, m pubsub.Message) error {
-	var values requiressl.Values
-	switch err := json.Unmarshal(m.Data, &values); err {
-	case nil:
-		return requiressl.Execute(ctx, &values, &requiressl.Services{
-			CloudSQL: svcs.CloudSQL,
-			Resource: svcs.Resource,
-			Logger:   svcs.Logger,
-		})
-	default:
-		return err
-	}
+func ExpireExemptions(ctx context.Context, m pubsub.Message) error {
+	return audited(ctx, m, "ExpireExemptions", func(ctx context.Context) error {
+		var values expireexemptions.Values
+		switch err := json.Unmarshal(m.Data, &values); err {
+		case nil:
+			exemptions, err := services.InitExemptions(ctx, projectID)
+			if err != nil {
+				return err
+			}
+			ps, err := initPubSub(ctx)
+			if err != nil {
+				return err
+			}
+			g, err := scoped(ctx)
+			if err != nil {
+				return err
+			}
+			return expireexemptions.Execute(ctx, &values, &expireexemptions.Services{
+				Exemptions:            exemptions,
+				SecurityCommandCenter: g.SecurityCommandCenter,
+				PubSub:                ps,
+			})
+		default:
+			return err
+		}
+	})
 }
 
-// DisableDashboard will disable the Kubernetes dashboard addon.
+// SendDigests is the entry point for the Cloud Function sending notification digests.
 //
-// This Cloud Function will respond to Security Health Analytics **Web UI Enabled** findings
-// from **Container Scanner**. The Kubernetes dashboard addon will be disabled when this
-// function is activated.
+// This function is triggered on a schedule. It sends a digest to each digested channel whose
+// window has passed, summarizing the notifications batched for it.
 //
 // Permissions required
-//	- roles/container.clusterAdmin update cluster addon.
+//	- roles/datastore.user to read and remove the batched notifications.
 //
-func DisableDashboard(ctx context.Context, m pubsub.Message) error {
-	var values disabledashboard.Values
-	switch err := json.Unmarshal(m.Data, &values); err {
-	case nil:
-		return disabledashboard.Execute(ctx, &values, &disabledashboard.Services{
-			Container: svcs.Container,
-			Resource:  svcs.Resource,
-			Logger:    svcs.Logger,
-		})
-	default:
+func SendDigests(ctx context.Context, m pubsub.Message) error {
+	var values senddigests.Values
+	if err := json.Unmarshal(m.Data, &values); err != nil {
+		return err
+	}
+	conf, err := router.Config()
+	if err != nil {
+		return err
+	}
+	notifications, err := services.InitNotifications(ctx, projectID, conf.Spec.Notifications)
+	if err != nil {
 		return err
 	}
+	return senddigests.Execute(ctx, &values, &senddigests.Services{
+		Notifications: notifications,
+	})
 }
 
-// EnableAuditLogs enables the Audit Logs to specific project
+// DeadLetter is the entry point for the Cloud Function keeping failed remediations.
 //
-// This Cloud Function will respond to Security Health Analytics **AUDIT_LOGGING_DISABLED** findings
-// from **LOGGING_SCANNER**.
+// This function is triggered by the remediations published to the dead letter topic when they
+// fail. They are kept until they are replayed by ReplayDeadLetters.
 //
 // Permissions required
-//	- roles/resourcemanager.folderAdmin to get/update resource policy from projects in folder.
-//	- roles/editor to get/update resource policy to specific project.
+//	- roles/datastore.user to keep the failed remediations.
 //
-func EnableAuditLogs(ctx context.Context, m pubsub.Message) error {
-	var values enableauditlogs.Values
-	switch err := json.Unmarshal(m.Data, &values); err {
-	case nil:
-		return enableauditlogs.Execute(ctx, &values, &enableauditlogs.Services{
-			Resource: svcs.Resource,
-			Logger:   svcs.Logger,
-		})
-	default:
+func DeadLetter(ctx context.Context, m pubsub.Message) error {
+	var values deadletter.Values
+	if err := json.Unmarshal(m.Data, &values); err != nil {
 		return err
 	}
+	conf, err := router.Config()
+	if err != nil {
+		return err
+	}
+	deadLetters, err := services.InitDeadLetters(ctx, projectID, conf.Spec.DeadLetters)
+	if err != nil {
+		return err
+	}
+	return deadletter.Execute(ctx, &values, &deadletter.Services{
+		DeadLetters: deadLetters,
+	})
 }
 
-// UpdatePassword updates the root password for a Cloud SQL instance.
+// ReplayDeadLetters is the entry point for the Cloud Function replaying failed remediations.
 //
-// This Cloud Function will respond to Security Health Analytics **SQL No Root Password** findings
-// from **SQL Scanner**. The root user of the affected instance will be updated with
-// a new password when this function is activated.
+// This function is triggered on a schedule, replaying the failed remediations whose backoff has
+// passed. Publishing {"ids": ["<remediation-id>"]} or {"all": true} to its topic replays failed
+// remediations on demand.
 //
 // Permissions required
-//	- roles/cloudsql.admin to update a user password.
+//	- roles/datastore.user to read and remove the failed remediations.
+//	- roles/pubsub.publisher to publish them back to their topic.
 //
-func UpdatePassword(ctx context.Context, m pubsub.Message) error {
-	var values updatepassword.Values
-	switch err := json.Unmarshal(m.Data, &values); err {
-	case nil:
-		return updatepassword.Execute(ctx, &values, &updatepassword.Services{
-			CloudSQL: svcs.CloudSQL,
-			Resource: svcs.Resource,
-			Logger:   svcs.Logger,
-		})
-	default:
+func ReplayDeadLetters(ctx context.Context, m pubsub.Message) error {
+	var values replay.Values
+	if err := json.Unmarshal(m.Data, &values); err != nil {
+		return err
+	}
+	conf, err := router.Config()
+	if err != nil {
+		return err
+	}
+	deadLetters, err := services.InitDeadLetters(ctx, projectID, conf.Spec.DeadLetters)
+	if err != nil {
 		return err
 	}
+	ps, err := initPubSub(ctx)
+	if err != nil {
+		return err
+	}
+	return replay.Execute(ctx, &values, &replay.Services{
+		DeadLetters: deadLetters,
+		PubSub:      ps,
+	})
 }
@@ -0,0 +1,197 @@
+// Package findings reads the fields automations require of the Security Command Center notifications of findings.
+package findings
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrUnsupportedFinding is returned when the payload isn't a finding or its resource is not one the reader supports.
+	ErrUnsupportedFinding = errors.New("unsupported finding")
+	// ErrValueNotFound is returned when a finding is missing a field the automation requires.
+	ErrValueNotFound = errors.New("value not found")
+)
+
+// Reader reads the fields an automation declares out of the findings it supports.
+//
+// The categories and properties decide which findings are named after their rule. Findings of other
+// categories mapped to the rule by the configuration are read all the same, as long as their
+// resource matches.
+//
+// Fields are declared with a `finding:"path"` tag on the struct passed to Read, where the path is
+// relative to the finding, such as "sourceProperties.ProjectId". The named groups of Resource are
+// read as "resource.<group>". Fields are required unless the tag ends with ",optional". Like JSON
+// field names, the keys of the path are matched case insensitively if no key matches exactly.
+type Reader struct {
+	// Categories are the categories named, any category is named if empty.
+	Categories []string
+	// Resource must match the resource name of the finding if set.
+	Resource *regexp.Regexp
+	// Properties are the source properties of the findings named, such as the scanner of Security
+	// Health Analytics findings.
+	Properties map[string]string
+}
+
+// Finding holds the fields common to every finding, embed it in the values read.
+type Finding struct {
+	Name          string            `finding:"name"`
+	Category      string            `finding:"category"`
+	ResourceName  string            `finding:"resourceName"`
+	State         string            `finding:"state,optional"`
+	EventTime     string            `finding:"eventTime,optional"`
+	SecurityMarks map[string]string `finding:"securityMarks.marks,optional"`
+}
+
+// Name returns the rule name of the finding or an empty string if it's not supported.
+func (r *Reader) Name(b []byte) string {
+	fields, err := r.fields(b)
+	if err != nil || !r.names(fields) {
+		return ""
+	}
+	category, _ := fields["category"].(string)
+	return strings.ToLower(category)
+}
+
+// Read fills the struct pointed to by v with the fields of the finding.
+func (r *Reader) Read(b []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.Errorf("read requires a pointer to a struct, got %T", v)
+	}
+	fields, err := r.fields(b)
+	if err != nil {
+		return err
+	}
+	return decode(fields, rv.Elem())
+}
+
+// fields returns the fields of the finding if it's supported.
+func (r *Reader) fields(b []byte) (map[string]interface{}, error) {
+	var notification struct {
+		Finding map[string]interface{} `json:"finding"`
+	}
+	if err := json.Unmarshal(Normalize(b), &notification); err != nil {
+		return nil, err
+	}
+	fields := notification.Finding
+	if fields == nil {
+		return nil, errors.Wrap(ErrUnsupportedFinding, "not a finding")
+	}
+	if r.Resource == nil {
+		return fields, nil
+	}
+	name, _ := fields["resourceName"].(string)
+	m := r.Resource.FindStringSubmatch(name)
+	if m == nil {
+		return nil, errors.Wrapf(ErrUnsupportedFinding, "resource %q", name)
+	}
+	resource := map[string]interface{}{}
+	for i, group := range r.Resource.SubexpNames() {
+		if group != "" {
+			resource[group] = m[i]
+		}
+	}
+	fields["resource"] = resource
+	return fields, nil
+}
+
+// names returns whether the finding is named after the rule of the reader, by its category and
+// properties.
+func (r *Reader) names(fields map[string]interface{}) bool {
+	for key, want := range r.Properties {
+		if value, _ := lookup(fields, "sourceProperties."+key); value != want {
+			return false
+		}
+	}
+	if len(r.Categories) == 0 {
+		return true
+	}
+	category, _ := fields["category"].(string)
+	for _, c := range r.Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// decode sets the tagged fields of v, and of the structs it embeds, from the fields of the finding.
+func decode(fields map[string]interface{}, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := decode(fields, v.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+		tag, ok := field.Tag.Lookup("finding")
+		if !ok || field.PkgPath != "" {
+			continue
+		}
+		path := strings.TrimSuffix(tag, ",optional")
+		value, ok := lookup(fields, path)
+		if !ok {
+			if path != tag {
+				continue
+			}
+			return errors.Wrapf(ErrValueNotFound, "%q", path)
+		}
+		b, err := json.Marshal(value)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %q", path)
+		}
+		if err := json.Unmarshal(b, v.Field(i).Addr().Interface()); err != nil {
+			return errors.Wrapf(err, "failed to read %q", path)
+		}
+	}
+	return nil
+}
+
+// lookup returns the value found at the dot separated path.
+func lookup(fields map[string]interface{}, path string) (interface{}, bool) {
+	var value interface{} = fields
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = get(m, key); !ok || value == nil {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// get returns the value of the key, or of a key equal to it under case folding.
+func get(m map[string]interface{}, key string) (interface{}, bool) {
+	if value, ok := m[key]; ok {
+		return value, true
+	}
+	for k, value := range m {
+		if strings.EqualFold(k, key) {
+			return value, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,256 @@
+package findings
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+)
+
+type values struct {
+	Finding
+	ProjectID string   `finding:"resource.project"`
+	Zone      string   `finding:"resource.zone"`
+	Severity  int      `finding:"sourceProperties.SeverityLevel,optional"`
+	Networks  []string `finding:"sourceProperties.Networks"`
+	ignored   string   `finding:"name"`
+}
+
+func TestRead(t *testing.T) {
+	const (
+		dnssecDisabled = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/d1",
+				"resourceName": "//dns.googleapis.com/projects/test-project/managedZones/public-zone",
+				"state": "ACTIVE",
+				"category": "DNSSEC_DISABLED",
+				"eventTime": "2019-11-22T18:34:36.153Z",
+				"sourceProperties": {
+					"SeverityLevel": 3,
+					"Networks": ["default"]
+				},
+				"securityMarks": {
+					"marks": {
+						"sra-remediated-event-time": "2019-11-22T18:34:36.153Z"
+					}
+				}
+			}
+		}`
+		missingOptional = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/d2",
+				"resourceName": "//dns.googleapis.com/projects/test-project/managedZones/public-zone",
+				"category": "DNSSEC_DISABLED",
+				"sourceProperties": {
+					"Networks": ["default"]
+				}
+			}
+		}`
+		missingRequired = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/d3",
+				"resourceName": "//dns.googleapis.com/projects/test-project/managedZones/public-zone",
+				"category": "DNSSEC_DISABLED",
+				"sourceProperties": {
+					"Networks": null
+				}
+			}
+		}`
+		otherCategory = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/d5",
+				"resourceName": "//dns.googleapis.com/projects/test-project/managedZones/public-zone",
+				"category": "ACME_DNSSEC",
+				"sourceProperties": {
+					"Networks": ["default"]
+				}
+			}
+		}`
+		mismatchedResource = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/d6",
+				"resourceName": "//dns.googleapis.com/projects/test-project/policies/default",
+				"category": "DNSSEC_DISABLED"
+			}
+		}`
+		notFinding = `{"resource": {"name": "public-zone"}}`
+	)
+	reader := &Reader{
+		Categories: []string{"DNSSEC_DISABLED"},
+		Resource:   regexp.MustCompile(`^//dns\.googleapis\.com/projects/(?P<project>[^/]+)/managedZones/(?P<zone>[^/]+)$`),
+	}
+	for _, tt := range []struct {
+		name, ruleName string
+		bytes          []byte
+		values         *values
+		expectedError  error
+	}{
+		{
+			name:     "read",
+			ruleName: "dnssec_disabled",
+			bytes:    []byte(dnssecDisabled),
+			values: &values{
+				Finding: Finding{
+					Name:          "organizations/154584661726/sources/2673592633662526977/findings/d1",
+					Category:      "DNSSEC_DISABLED",
+					ResourceName:  "//dns.googleapis.com/projects/test-project/managedZones/public-zone",
+					State:         "ACTIVE",
+					EventTime:     "2019-11-22T18:34:36.153Z",
+					SecurityMarks: map[string]string{"sra-remediated-event-time": "2019-11-22T18:34:36.153Z"},
+				},
+				ProjectID: "test-project",
+				Zone:      "public-zone",
+				Severity:  3,
+				Networks:  []string{"default"},
+			},
+		},
+		{
+			name:     "missing optional",
+			ruleName: "dnssec_disabled",
+			bytes:    []byte(missingOptional),
+			values: &values{
+				Finding: Finding{
+					Name:         "organizations/154584661726/sources/2673592633662526977/findings/d2",
+					Category:     "DNSSEC_DISABLED",
+					ResourceName: "//dns.googleapis.com/projects/test-project/managedZones/public-zone",
+				},
+				ProjectID: "test-project",
+				Zone:      "public-zone",
+				Networks:  []string{"default"},
+			},
+		},
+		{name: "missing required", ruleName: "dnssec_disabled", bytes: []byte(missingRequired), expectedError: ErrValueNotFound},
+		{
+			name:  "other category",
+			bytes: []byte(otherCategory),
+			values: &values{
+				Finding: Finding{
+					Name:         "organizations/154584661726/sources/2673592633662526977/findings/d5",
+					Category:     "ACME_DNSSEC",
+					ResourceName: "//dns.googleapis.com/projects/test-project/managedZones/public-zone",
+				},
+				ProjectID: "test-project",
+				Zone:      "public-zone",
+				Networks:  []string{"default"},
+			},
+		},
+		{name: "mismatched resource", bytes: []byte(mismatchedResource), expectedError: ErrUnsupportedFinding},
+		{name: "not a finding", bytes: []byte(notFinding), expectedError: ErrUnsupportedFinding},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if name := reader.Name(tt.bytes); name != tt.ruleName {
+				t.Errorf("%s failed: got:%q want:%q", tt.name, name, tt.ruleName)
+			}
+			var v values
+			err := reader.Read(tt.bytes, &v)
+			if tt.expectedError == nil && err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if tt.expectedError != nil && !xerrors.Is(err, tt.expectedError) {
+				t.Errorf("%s failed: got:%q want:%q", tt.name, err, tt.expectedError)
+			}
+			if tt.values == nil {
+				return
+			}
+			if diff := cmp.Diff(&v, tt.values, cmp.AllowUnexported(values{})); diff != "" {
+				t.Errorf("%s failed, difference:%+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	const finding = `{
+		"finding": {
+			"name": "organizations/154584661726/sources/2673592633662526977/findings/d1",
+			"resourceName": "//dns.googleapis.com/projects/test-project/managedZones/public-zone",
+			"category": "DNSSEC_DISABLED",
+			"sourceProperties": {
+				"Networks": "default"
+			}
+		}
+	}`
+	type networks struct {
+		Networks []string `finding:"sourceProperties.Networks"`
+	}
+	reader := &Reader{}
+	for _, tt := range []struct {
+		name  string
+		bytes []byte
+		v     interface{}
+	}{
+		{name: "wrong type", bytes: []byte(finding), v: &networks{}},
+		{name: "not a pointer", bytes: []byte(finding), v: networks{}},
+		{name: "invalid json", bytes: []byte(`{"finding":`), v: &networks{}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := reader.Read(tt.bytes, tt.v)
+			if err == nil {
+				t.Fatalf("%s failed: got no error", tt.name)
+			}
+			if xerrors.Is(err, ErrUnsupportedFinding) || xerrors.Is(err, ErrValueNotFound) {
+				t.Errorf("%s failed: got:%q", tt.name, err)
+			}
+		})
+	}
+}
+
+func TestReadProperties(t *testing.T) {
+	const (
+		storageScanner = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/s1",
+				"resourceName": "//storage.googleapis.com/public-bucket",
+				"category": "PUBLIC_BUCKET_ACL",
+				"sourceProperties": {
+					"ScannerName": "STORAGE_SCANNER",
+					"ProjectId": "test-project"
+				}
+			}
+		}`
+		otherScanner = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/s2",
+				"resourceName": "//storage.googleapis.com/public-bucket",
+				"category": "PUBLIC_BUCKET_ACL",
+				"sourceProperties": {
+					"ScannerName": "IAM_SCANNER",
+					"ProjectId": "test-project"
+				}
+			}
+		}`
+	)
+	type storage struct {
+		Finding
+		ProjectID string `finding:"sourceProperties.projectId"`
+	}
+	reader := &Reader{Properties: map[string]string{"ScannerName": "STORAGE_SCANNER"}}
+	var v storage
+	if err := reader.Read([]byte(storageScanner), &v); err != nil {
+		t.Fatalf("failed to read: %q", err)
+	}
+	if v.ProjectID != "test-project" {
+		t.Errorf("got project %q want %q", v.ProjectID, "test-project")
+	}
+	if name := reader.Name([]byte(storageScanner)); name != "public_bucket_acl" {
+		t.Errorf("got name %q want %q", name, "public_bucket_acl")
+	}
+	if name := reader.Name([]byte(otherScanner)); name != "" {
+		t.Errorf("got name %q of another scanner's finding", name)
+	}
+}
@@ -0,0 +1,185 @@
+// Package fixtures loads the findings kept as fixtures for tests, one per rule and format.
+package fixtures
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/googlecloudplatform/security-response-automation/simulate"
+)
+
+// Format is the format a finding is published in.
+type Format string
+
+const (
+	// Notification is the notification Security Command Center publishes for a finding.
+	Notification Format = "notification"
+	// LogEntry is the log entry Event Threat Detection writes for a finding, exported by a log sink.
+	LogEntry Format = "log"
+	// ProtoNames is the notification as notification configs of the v1 API may publish it, with the
+	// field names of its protocol buffers, enums as numbers, timestamps as seconds and nanos and the
+	// resource of the finding in a resource block. It's converted from the notification.
+	ProtoNames Format = "proto_names"
+	// EventBridge is the event Amazon EventBridge publishes for a GuardDuty finding, relayed from
+	// an SQS queue by a bridge. Only GuardDuty rules have a finding in this format.
+	EventBridge Format = "eventbridge"
+	// Defender is the alert Microsoft Defender for Cloud exports, forwarded from Azure. Only
+	// Defender rules have a finding in this format.
+	Defender Format = "defender"
+	// Falco is the alert Falco sends to its webhook, before it's normalized into a notification.
+	// Only Falco rules have a finding in this format.
+	Falco Format = "falco"
+)
+
+// enums are the numbers of the enum values of a finding.
+var enums = map[string]map[string]int{
+	"state":    {"ACTIVE": 1, "INACTIVE": 2},
+	"severity": {"CRITICAL": 1, "HIGH": 2, "MEDIUM": 3, "LOW": 4},
+}
+
+// Read returns the Security Command Center notification of the finding of the rule. Fields are
+// set by the overrides, "path=value" where the path is the keys of the field joined by dots,
+// such as "finding.state=INACTIVE", as the overrides of simulated findings.
+func Read(t testing.TB, rule string, overrides ...string) []byte {
+	t.Helper()
+	return ReadFormat(t, rule, Notification, overrides...)
+}
+
+// ReadFormat returns the finding of the rule in the format.
+func ReadFormat(t testing.TB, rule string, format Format, overrides ...string) []byte {
+	t.Helper()
+	if format == ProtoNames {
+		return protoNames(t, ReadFormat(t, rule, Notification, overrides...))
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir(), string(format), rule+".json"))
+	if err != nil {
+		t.Fatalf("failed to read %s finding %q: %q", format, rule, err)
+	}
+	if len(overrides) == 0 {
+		return b
+	}
+	m := map[string]string{}
+	for _, o := range overrides {
+		i := strings.Index(o, "=")
+		if i < 1 {
+			t.Fatalf("override %q of %s finding %q isn't path=value", o, format, rule)
+		}
+		m[o[:i]] = o[i+1:]
+	}
+	b, err = simulate.Override(b, m)
+	if err != nil {
+		t.Fatalf("failed to override %s finding %q: %q", format, rule, err)
+	}
+	return b
+}
+
+// Rules returns the rules with a finding in the format.
+func Rules(t testing.TB, format Format) []string {
+	t.Helper()
+	if format == ProtoNames {
+		format = Notification
+	}
+	files, err := filepath.Glob(filepath.Join(dir(), string(format), "*.json"))
+	if err != nil {
+		t.Fatalf("failed to list %s findings: %q", format, err)
+	}
+	rules := make([]string, 0, len(files))
+	for _, f := range files {
+		rules = append(rules, strings.TrimSuffix(filepath.Base(f), ".json"))
+	}
+	sort.Strings(rules)
+	return rules
+}
+
+// protoNames returns the notification in the ProtoNames format.
+func protoNames(t testing.TB, b []byte) []byte {
+	t.Helper()
+	var notification map[string]interface{}
+	if err := json.Unmarshal(b, &notification); err != nil {
+		t.Fatalf("failed to read notification: %q", err)
+	}
+	finding, _ := notification["finding"].(map[string]interface{})
+	resource := map[string]interface{}{"name": finding["resourceName"]}
+	if properties, ok := finding["sourceProperties"].(map[string]interface{}); ok && properties["ProjectId"] != nil {
+		resource["project_display_name"] = properties["ProjectId"]
+	}
+	notification["resource"] = resource
+	for key, numbers := range enums {
+		if n, ok := numbers[fmt.Sprint(finding[key])]; ok {
+			finding[key] = n
+		}
+	}
+	for _, key := range []string{"eventTime", "createTime"} {
+		v, ok := finding[key].(string)
+		if !ok {
+			continue
+		}
+		e, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			t.Fatalf("failed to read %s of notification: %q", key, err)
+		}
+		finding[key] = map[string]int64{"seconds": e.Unix(), "nanos": int64(e.Nanosecond())}
+	}
+	b, err := json.Marshal(snakeCase(notification))
+	if err != nil {
+		t.Fatalf("failed to write notification: %q", err)
+	}
+	return b
+}
+
+// snakeCase returns v with the field names it holds in snake_case, leaving the keys of source
+// properties and security marks, which are data.
+func snakeCase(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, value := range v {
+			if k != "sourceProperties" && k != "marks" {
+				value = snakeCase(value)
+			}
+			var key strings.Builder
+			for _, r := range k {
+				if unicode.IsUpper(r) {
+					key.WriteRune('_')
+				}
+				key.WriteRune(unicode.ToLower(r))
+			}
+			m[key.String()] = value
+		}
+		return m
+	case []interface{}:
+		for i, value := range v {
+			v[i] = snakeCase(value)
+		}
+	}
+	return v
+}
+
+// dir returns the directory of the fixtures, next to the source of this package so tests of any
+// package can read them.
+func dir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "testdata")
+}
@@ -0,0 +1,66 @@
+package fixtures
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRead(t *testing.T) {
+	for _, format := range []Format{Notification, ProtoNames, LogEntry, EventBridge, Defender, Falco} {
+		for _, rule := range Rules(t, format) {
+			var v map[string]interface{}
+			if err := json.Unmarshal(ReadFormat(t, rule, format), &v); err != nil {
+				t.Errorf("%s %s failed: %q", rule, format, err)
+			}
+		}
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	b := Read(t, "public_bucket_acl", "finding.state=INACTIVE", "finding.securityMarks.marks.sra-remediated-event-time=2019-09-23T17:20:27.204Z")
+	var got struct {
+		Finding struct {
+			Name          string `json:"name"`
+			State         string `json:"state"`
+			SecurityMarks struct {
+				Marks map[string]string `json:"marks"`
+			} `json:"securityMarks"`
+		} `json:"finding"`
+	}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("overrides failed: %q", err)
+	}
+	if got.Finding.Name != "organizations/154584661726/sources/2673592633662526977/findings/782e52631d61da6117a3772137c270d8" {
+		t.Errorf("overrides failed, name changed to %q", got.Finding.Name)
+	}
+	if got.Finding.State != "INACTIVE" {
+		t.Errorf("overrides failed: got:%q want:%q", got.Finding.State, "INACTIVE")
+	}
+	want := map[string]string{"sra-remediated-event-time": "2019-09-23T17:20:27.204Z"}
+	if diff := cmp.Diff(got.Finding.SecurityMarks.Marks, want); diff != "" {
+		t.Errorf("overrides failed, difference:%+v", diff)
+	}
+}
+
+func TestRules(t *testing.T) {
+	want := []string{"bad_ip", "iam_anomalous_grant", "ssh_brute_force"}
+	if diff := cmp.Diff(Rules(t, LogEntry), want); diff != "" {
+		t.Errorf("rules failed, difference:%+v", diff)
+	}
+}
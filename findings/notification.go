@@ -0,0 +1,172 @@
+package findings
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// enums maps the numbers of the enum fields of a finding to their names.
+var enums = map[string][]string{
+	"state":    {"STATE_UNSPECIFIED", "ACTIVE", "INACTIVE"},
+	"severity": {"SEVERITY_UNSPECIFIED", "CRITICAL", "HIGH", "MEDIUM", "LOW"},
+}
+
+// timestamps are the fields of a finding holding a timestamp.
+var timestamps = map[string]bool{
+	"eventTime":  true,
+	"createTime": true,
+}
+
+// opaque are the fields whose keys are data rather than field names.
+var opaque = map[string]bool{
+	"sourceProperties": true,
+	"marks":            true,
+	"labels":           true,
+}
+
+// Normalize returns the notification of a finding in the format providers read.
+//
+// Notifications of the Security Command Center v1 and v1p1beta1 APIs may name fields as their
+// protocol buffers do, such as "source_properties" or "SourceProperties", carry enums as numbers
+// and timestamps as seconds and nanos, and describe the resource of the finding in a "resource"
+// block. These are rewritten to camelCase names, enum names and RFC 3339 timestamps, keeping the
+// resource block. Payloads already in this format, or that aren't notifications such as log
+// entries, are returned unchanged.
+func Normalize(b []byte) []byte {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var notification map[string]interface{}
+	if err := d.Decode(&notification); err != nil {
+		return b
+	}
+	isNotification := false
+	for k := range notification {
+		if camelCase(k) == "finding" {
+			isNotification = true
+		}
+	}
+	if !isNotification {
+		return b
+	}
+	v, changed := normalize(notification)
+	if !changed {
+		return b
+	}
+	n, err := json.Marshal(v)
+	if err != nil {
+		return b
+	}
+	return n
+}
+
+// normalize rewrites the field names of v, and of the fields it holds, to camelCase and returns
+// whether anything changed.
+func normalize(v interface{}) (interface{}, bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		changed := false
+		for k, value := range v {
+			key := camelCase(k)
+			if key != k {
+				changed = true
+			}
+			if !opaque[key] {
+				var c bool
+				value, c = normalize(value)
+				changed = changed || c
+			}
+			if converted, ok := convert(key, value); ok {
+				value = converted
+				changed = true
+			}
+			m[key] = value
+		}
+		return m, changed
+	case []interface{}:
+		changed := false
+		for i, value := range v {
+			var c bool
+			v[i], c = normalize(value)
+			changed = changed || c
+		}
+		return v, changed
+	}
+	return v, false
+}
+
+// convert returns the enum name or RFC 3339 timestamp of the value of the field, if held as a
+// number or as seconds and nanos.
+func convert(key string, value interface{}) (interface{}, bool) {
+	if names, ok := enums[key]; ok {
+		n, ok := value.(json.Number)
+		if !ok {
+			return nil, false
+		}
+		i, err := n.Int64()
+		if err != nil || i < 0 || i >= int64(len(names)) {
+			return nil, false
+		}
+		return names[i], true
+	}
+	if !timestamps[key] {
+		return nil, false
+	}
+	t, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	var seconds, nanos int64
+	if n, ok := t["seconds"].(json.Number); ok {
+		seconds, _ = n.Int64()
+	}
+	if n, ok := t["nanos"].(json.Number); ok {
+		nanos, _ = n.Int64()
+	}
+	// Fractional seconds are written in 3, 6 or 9 digits as Security Command Center does.
+	layout := "2006-01-02T15:04:05.000000000Z"
+	switch {
+	case nanos == 0:
+		layout = "2006-01-02T15:04:05Z"
+	case nanos%1e6 == 0:
+		layout = "2006-01-02T15:04:05.000Z"
+	case nanos%1e3 == 0:
+		layout = "2006-01-02T15:04:05.000000Z"
+	}
+	return time.Unix(seconds, nanos).UTC().Format(layout), true
+}
+
+// camelCase returns the snake_case or PascalCase name in camelCase.
+func camelCase(name string) string {
+	parts := strings.Split(name, "_")
+	for i, p := range parts {
+		if p == "" {
+			continue
+		}
+		r := []rune(p)
+		if i == 0 {
+			r[0] = unicode.ToLower(r[0])
+		} else {
+			r[0] = unicode.ToUpper(r[0])
+		}
+		parts[i] = string(r)
+	}
+	return strings.Join(parts, "")
+}
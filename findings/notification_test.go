@@ -0,0 +1,125 @@
+package findings
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/findings/fixtures"
+)
+
+func TestNormalize(t *testing.T) {
+	const (
+		legacy = `{
+			"notificationConfigName": "organizations/154584661726/notificationConfigs/sampleConfigId",
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/d1",
+				"resourceName": "//dns.googleapis.com/projects/test-project/managedZones/public-zone",
+				"state": "ACTIVE",
+				"category": "DNSSEC_DISABLED"
+			}
+		}`
+		logEntry = `{
+			"jsonPayload": {"detectionCategory": {"ruleName": "bad_ip"}},
+			"resource": {"labels": {"project_id": "test-project"}}
+		}`
+		v1p1beta1 = `{
+			"NotificationConfigName": "organizations/154584661726/notificationConfigs/sampleConfigId",
+			"Finding": {
+				"Name": "organizations/154584661726/sources/2673592633662526977/findings/d1",
+				"ResourceName": "//dns.googleapis.com/projects/test-project/managedZones/public-zone",
+				"State": 2,
+				"Severity": 3,
+				"Category": "DNSSEC_DISABLED",
+				"SourceProperties": {"ProjectId": "test-project"},
+				"SecurityMarks": {"Marks": {"sra-remediated-event-time": "2019-11-22T18:34:36.153Z"}},
+				"EventTime": {"seconds": 1574447676, "nanos": 153000000}
+			},
+			"Resource": {
+				"Name": "//dns.googleapis.com/projects/test-project/managedZones/public-zone",
+				"ProjectDisplayName": "test-project"
+			}
+		}`
+		want = `{
+			"notificationConfigName": "organizations/154584661726/notificationConfigs/sampleConfigId",
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/d1",
+				"resourceName": "//dns.googleapis.com/projects/test-project/managedZones/public-zone",
+				"state": "INACTIVE",
+				"severity": "MEDIUM",
+				"category": "DNSSEC_DISABLED",
+				"sourceProperties": {"ProjectId": "test-project"},
+				"securityMarks": {"marks": {"sra-remediated-event-time": "2019-11-22T18:34:36.153Z"}},
+				"eventTime": "2019-11-22T18:34:36.153Z"
+			},
+			"resource": {
+				"name": "//dns.googleapis.com/projects/test-project/managedZones/public-zone",
+				"projectDisplayName": "test-project"
+			}
+		}`
+	)
+	for _, tt := range []struct {
+		name      string
+		bytes     []byte
+		want      []byte
+		unchanged bool
+	}{
+		{name: "legacy", bytes: []byte(legacy), want: []byte(legacy), unchanged: true},
+		{name: "log entry", bytes: []byte(logEntry), want: []byte(logEntry), unchanged: true},
+		{name: "not json", bytes: []byte("{"), want: []byte("{"), unchanged: true},
+		{name: "v1p1beta1", bytes: []byte(v1p1beta1), want: []byte(want)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Normalize(tt.bytes)
+			if tt.unchanged {
+				if string(got) != string(tt.want) {
+					t.Errorf("%s failed: got:%q want:%q", tt.name, got, tt.want)
+				}
+				return
+			}
+			if diff := cmp.Diff(decoded(t, got), decoded(t, tt.want)); diff != "" {
+				t.Errorf("%s failed, difference:%+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestNormalizeFixtures(t *testing.T) {
+	// The notification of every rule reads the same in the field names of the v1 API.
+	for _, rule := range fixtures.Rules(t, fixtures.ProtoNames) {
+		got := decoded(t, Normalize(fixtures.ReadFormat(t, rule, fixtures.ProtoNames)))
+		want := decoded(t, fixtures.Read(t, rule))
+		resource, _ := got["resource"].(map[string]interface{})
+		delete(got, "resource")
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Errorf("%s failed, difference:%+v", rule, diff)
+		}
+		finding, _ := want["finding"].(map[string]interface{})
+		if resource["name"] != finding["resourceName"] {
+			t.Errorf("%s failed: got resource %q want:%q", rule, resource["name"], finding["resourceName"])
+		}
+	}
+}
+
+func decoded(t *testing.T, b []byte) map[string]interface{} {
+	t.Helper()
+	var v map[string]interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		t.Fatalf("failed to decode %q: %q", b, err)
+	}
+	return v
+}
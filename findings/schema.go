@@ -0,0 +1,206 @@
+package findings
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SchemaParent prefixes the parent of the findings mapped by schemas, followed by the name of the
+// schema. Their names aren't Security Command Center names so they're never marked.
+const SchemaParent = "schemas/"
+
+// placeholder matches the paths embedded in the values of schema fields, such as
+// "//storage.googleapis.com/{$.bucket}".
+var placeholder = regexp.MustCompile(`\{(\$[^}]*)\}`)
+
+// Schema maps the JSON payloads of a detector to findings, so its detections are routed like
+// Security Command Center notifications.
+//
+// Paths into the payload are JSONPath-style, such as "$.resource.buckets[0].name". A value that
+// is a path is the value at the path, a value embedding paths in braces is the string with the
+// values at the paths, any other value is used as is.
+type Schema struct {
+	// Name identifies the detector, the parent of its findings is "schemas/<name>".
+	Name string
+	// Match holds the values payloads of the detector have, keyed by their path, such as
+	// "$.source": "acme-scanner". Every payload matches if empty.
+	Match map[string]string
+	// Fields holds the values of the fields of the finding, keyed by their path relative to the
+	// finding such as "sourceProperties.ProjectId". The category and resourceName are required,
+	// the name and parent are set from the schema and the payload.
+	Fields map[string]string
+}
+
+// Matches returns whether the payload is one of the detector's.
+func (s *Schema) Matches(b []byte) bool {
+	var payload interface{}
+	if err := json.Unmarshal(b, &payload); err != nil {
+		return false
+	}
+	for path, want := range s.Match {
+		v, ok := jsonPath(payload, path)
+		if !ok || text(v) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// Notification returns the Security Command Center notification of the finding the payload maps
+// to. The name of the finding is a hash of the payload, so redeliveries share their name.
+func (s *Schema) Notification(b []byte) ([]byte, error) {
+	var payload interface{}
+	if err := json.Unmarshal(b, &payload); err != nil {
+		return nil, err
+	}
+	finding := map[string]interface{}{}
+	for path, expr := range s.Fields {
+		v, err := evaluate(payload, expr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to map %q", path)
+		}
+		if err := setPath(finding, path, v); err != nil {
+			return nil, err
+		}
+	}
+	for _, field := range []string{"category", "resourceName"} {
+		if v, _ := finding[field].(string); v == "" {
+			return nil, errors.Wrapf(ErrValueNotFound, "schema %q doesn't map %q", s.Name, field)
+		}
+	}
+	sum := sha256.Sum256(b)
+	finding["parent"] = SchemaParent + s.Name
+	finding["name"] = SchemaParent + s.Name + "/detections/" + hex.EncodeToString(sum[:16])
+	if _, ok := finding["state"]; !ok {
+		finding["state"] = "ACTIVE"
+	}
+	return json.Marshal(map[string]interface{}{"finding": finding})
+}
+
+// evaluate returns the value of the expression of a field for the payload.
+func evaluate(payload interface{}, expr string) (interface{}, error) {
+	if strings.HasPrefix(expr, "$") {
+		v, ok := jsonPath(payload, expr)
+		if !ok {
+			return nil, errors.Wrapf(ErrValueNotFound, "%q", expr)
+		}
+		return v, nil
+	}
+	var err error
+	s := placeholder.ReplaceAllStringFunc(expr, func(m string) string {
+		path := placeholder.FindStringSubmatch(m)[1]
+		v, ok := jsonPath(payload, path)
+		if !ok {
+			err = errors.Wrapf(ErrValueNotFound, "%q", path)
+			return ""
+		}
+		return text(v)
+	})
+	return s, err
+}
+
+// text returns strings as is and other values as JSON.
+func text(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// jsonPath returns the value at the path, the keys of the objects joined by dots with array
+// elements indexed in brackets.
+func jsonPath(v interface{}, path string) (interface{}, bool) {
+	keys, err := splitPath(path)
+	if err != nil {
+		return nil, false
+	}
+	for _, key := range keys {
+		switch value := v.(type) {
+		case map[string]interface{}:
+			if v = value[key]; v == nil {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(value) {
+				return nil, false
+			}
+			v = value[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// splitPath returns the keys and indices of the JSONPath-style path.
+func splitPath(path string) ([]string, error) {
+	p := strings.TrimPrefix(path, "$")
+	var keys []string
+	for p != "" {
+		switch p[0] {
+		case '.':
+			p = p[1:]
+			end := strings.IndexAny(p, ".[")
+			if end < 0 {
+				end = len(p)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty key in path %q", path)
+			}
+			keys = append(keys, p[:end])
+			p = p[end:]
+		case '[':
+			end := strings.IndexByte(p, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed index in path %q", path)
+			}
+			keys = append(keys, strings.Trim(p[1:end], `'"`))
+			p = p[end+1:]
+		default:
+			return nil, fmt.Errorf("path %q must start with $", path)
+		}
+	}
+	return keys, nil
+}
+
+// setPath sets the value at the dot separated path of the finding.
+func setPath(finding map[string]interface{}, path string, v interface{}) error {
+	keys := strings.Split(path, ".")
+	m := finding
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := m[key].(map[string]interface{})
+		if !ok {
+			if _, set := m[key]; set {
+				return fmt.Errorf("field %q of %q is not an object", key, path)
+			}
+			next = map[string]interface{}{}
+			m[key] = next
+		}
+		m = next
+	}
+	m[keys[len(keys)-1]] = v
+	return nil
+}
@@ -0,0 +1,114 @@
+package findings
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestSchema(t *testing.T) {
+	const payload = `{
+		"scanner": "acme",
+		"kind": "public-bucket",
+		"detectedAt": "2023-05-04T10:00:00Z",
+		"asset": {"project": "test-project", "buckets": [{"name": "public-assets"}]},
+		"score": 9
+	}`
+	schema := &Schema{
+		Name:  "acme",
+		Match: map[string]string{"$.scanner": "acme", "$.kind": "public-bucket"},
+		Fields: map[string]string{
+			"category":                     "PUBLIC_BUCKET_ACL",
+			"resourceName":                 "//storage.googleapis.com/{$.asset.buckets[0].name}",
+			"eventTime":                    "$.detectedAt",
+			"sourceProperties.ProjectId":   "$.asset.project",
+			"sourceProperties.Score":       "$.score",
+			"sourceProperties.Description": "{$.kind} in {$['asset'].project}",
+		},
+	}
+	for _, tt := range []struct {
+		name    string
+		schema  *Schema
+		payload string
+		matches bool
+		want    map[string]interface{}
+		err     error
+	}{
+		{
+			name:    "mapped",
+			schema:  schema,
+			payload: payload,
+			matches: true,
+			want: map[string]interface{}{
+				"name":         "schemas/acme/detections/efdb62e20181388c616c485ed9fcaddb",
+				"parent":       "schemas/acme",
+				"state":        "ACTIVE",
+				"category":     "PUBLIC_BUCKET_ACL",
+				"resourceName": "//storage.googleapis.com/public-assets",
+				"eventTime":    "2023-05-04T10:00:00Z",
+				"sourceProperties": map[string]interface{}{
+					"ProjectId":   "test-project",
+					"Score":       float64(9),
+					"Description": "public-bucket in test-project",
+				},
+			},
+		},
+		{name: "not matched", schema: schema, payload: `{"scanner": "other", "kind": "public-bucket"}`},
+		{name: "not json", schema: schema, payload: `not json`},
+		{
+			name:    "missing value",
+			schema:  schema,
+			payload: `{"scanner": "acme", "kind": "public-bucket", "asset": {"buckets": []}}`,
+			matches: true,
+			err:     ErrValueNotFound,
+		},
+		{
+			name:    "missing category",
+			schema:  &Schema{Name: "acme", Fields: map[string]string{"resourceName": "$.scanner"}},
+			payload: payload,
+			matches: true,
+			err:     ErrValueNotFound,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if matches := tt.schema.Matches([]byte(tt.payload)); matches != tt.matches {
+				t.Fatalf("%s failed: got matches %t want %t", tt.name, matches, tt.matches)
+			}
+			if !tt.matches {
+				return
+			}
+			b, err := tt.schema.Notification([]byte(tt.payload))
+			if errors.Cause(err) != tt.err {
+				t.Fatalf("%s failed: got error %v want %v", tt.name, err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+			var got struct {
+				Finding map[string]interface{}
+			}
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(got.Finding, tt.want); diff != "" {
+				t.Errorf("%s failed, difference:%+v", tt.name, diff)
+			}
+		})
+	}
+}
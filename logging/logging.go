@@ -0,0 +1,161 @@
+// Package logging writes structured log entries correlated by finding, project, automation and
+// trace, so Cloud Logging queries can follow a remediation from the router to its API calls.
+package logging
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Severities of the entries, as Cloud Logging names them.
+const (
+	severityDebug   = "DEBUG"
+	severityInfo    = "INFO"
+	severityWarning = "WARNING"
+	severityError   = "ERROR"
+)
+
+// projectID is the project the traces of the entries are in.
+var projectID = os.Getenv("GCP_PROJECT")
+
+// Entry is a structured log entry. Cloud Functions write the entries printed to the standard
+// output to Cloud Logging, which parses the severity and trace and keeps the rest in the
+// jsonPayload of the entry.
+type Entry struct {
+	Severity   string `json:"severity"`
+	Message    string `json:"message"`
+	Finding    string `json:"finding,omitempty"`
+	Project    string `json:"project,omitempty"`
+	Automation string `json:"automation,omitempty"`
+	Trace      string `json:"logging.googleapis.com/trace,omitempty"`
+	SpanID     string `json:"logging.googleapis.com/spanId,omitempty"`
+}
+
+// Logger writes entries labeled with the finding, project and automation of its context.
+type Logger struct {
+	out   *output
+	entry Entry
+}
+
+// output serializes the entries written to the writer, automations log from several goroutines.
+type output struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// stdout is where entries are written unless the context sets another writer.
+var stdout = &output{w: os.Stdout}
+
+type outputKey struct{}
+
+type fieldsKey struct{}
+
+type fields struct {
+	finding, project, automation string
+}
+
+// NewContext returns a context whose loggers write their entries to w, such as a stub in tests.
+func NewContext(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, outputKey{}, &output{w: w})
+}
+
+// WithFinding returns a context whose entries are labeled with the name of the finding.
+func WithFinding(ctx context.Context, name string) context.Context {
+	f := fieldsFrom(ctx)
+	f.finding = name
+	return context.WithValue(ctx, fieldsKey{}, f)
+}
+
+// WithProject returns a context whose entries are labeled with the project being remediated.
+func WithProject(ctx context.Context, projectID string) context.Context {
+	f := fieldsFrom(ctx)
+	f.project = projectID
+	return context.WithValue(ctx, fieldsKey{}, f)
+}
+
+// WithAutomation returns a context whose entries are labeled with the automation running.
+func WithAutomation(ctx context.Context, name string) context.Context {
+	f := fieldsFrom(ctx)
+	f.automation = name
+	return context.WithValue(ctx, fieldsKey{}, f)
+}
+
+func fieldsFrom(ctx context.Context) fields {
+	f, _ := ctx.Value(fieldsKey{}).(fields)
+	return f
+}
+
+// FromContext returns a logger labeling its entries with the finding, project and automation of
+// the context, and with the trace and span of the context if it's traced.
+func FromContext(ctx context.Context) *Logger {
+	out, ok := ctx.Value(outputKey{}).(*output)
+	if !ok {
+		out = stdout
+	}
+	f := fieldsFrom(ctx)
+	l := &Logger{out: out, entry: Entry{Finding: f.finding, Project: f.project, Automation: f.automation}}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		l.entry.Trace = fmt.Sprintf("projects/%s/traces/%s", projectID, sc.TraceID())
+		l.entry.SpanID = sc.SpanID().String()
+	}
+	return l
+}
+
+// Info logs a message using info as the severity.
+func (l *Logger) Info(message string, a ...interface{}) {
+	l.log(severityInfo, message, a...)
+}
+
+// Warning logs a message using warning as the severity.
+func (l *Logger) Warning(message string, a ...interface{}) {
+	l.log(severityWarning, message, a...)
+}
+
+// Error logs a message using error as the severity.
+func (l *Logger) Error(message string, a ...interface{}) {
+	l.log(severityError, message, a...)
+}
+
+// Debug logs a message using debug as the severity.
+func (l *Logger) Debug(message string, a ...interface{}) {
+	l.log(severityDebug, message, a...)
+}
+
+// Planned logs the change a remediation would have made if dry run was off.
+func (l *Logger) Planned(change fmt.Stringer) {
+	l.Info("dry_run on, would have changed %s", change)
+}
+
+func (l *Logger) log(severity, message string, a ...interface{}) {
+	e := l.entry
+	e.Severity = severity
+	e.Message = fmt.Sprintf(message, a...)
+	b, err := json.Marshal(e)
+	if err != nil {
+		// Entries only hold strings, this doesn't happen.
+		return
+	}
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	l.out.w.Write(append(b, '\n'))
+}
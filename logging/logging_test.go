@@ -0,0 +1,97 @@
+package logging
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestFromContext(t *testing.T) {
+	traced := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	}))
+	for _, tt := range []struct {
+		name string
+		ctx  context.Context
+		log  func(l *Logger)
+		want Entry
+	}{
+		{
+			name: "no fields",
+			ctx:  context.Background(),
+			log:  func(l *Logger) { l.Info("routed %d findings", 2) },
+			want: Entry{Severity: "INFO", Message: "routed 2 findings"},
+		},
+		{
+			name: "remediation",
+			ctx:  WithAutomation(WithProject(WithFinding(context.Background(), "organizations/1/sources/2/findings/3"), "test-project"), "CloseBucket"),
+			log:  func(l *Logger) { l.Error("failed to close bucket %q", "b") },
+			want: Entry{
+				Severity:   "ERROR",
+				Message:    `failed to close bucket "b"`,
+				Finding:    "organizations/1/sources/2/findings/3",
+				Project:    "test-project",
+				Automation: "CloseBucket",
+			},
+		},
+		{
+			name: "traced",
+			ctx:  WithFinding(traced, "f1"),
+			log:  func(l *Logger) { l.Warning("retrying") },
+			want: Entry{
+				Severity: "WARNING",
+				Message:  "retrying",
+				Finding:  "f1",
+				Trace:    "projects/" + projectID + "/traces/01000000000000000000000000000000",
+				SpanID:   "0200000000000000",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.log(FromContext(NewContext(tt.ctx, &buf)))
+			var got Entry
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("%s failed to parse entry %q: %q", tt.name, buf.String(), err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("%s failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestWithFieldsDoNotLeak(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := WithFinding(NewContext(context.Background(), &buf), "f1")
+	other := WithFinding(ctx, "f2")
+	FromContext(ctx).Debug("first")
+	FromContext(other).Debug("second")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d entries want 2", len(lines))
+	}
+	if !strings.Contains(lines[0], `"finding":"f1"`) || !strings.Contains(lines[1], `"finding":"f2"`) {
+		t.Errorf("got entries %q, want each labeled with its own finding", lines)
+	}
+}
@@ -32,6 +32,12 @@ module "google-setup" {
   cscc-notifications-topic-prefix = local.cscc-findings-topic
   findings-topic                  = local.findings-topic
   enable-scc-notification         = var.enable-scc-notification
+  config-uri                      = var.config-uri
+}
+
+module "metrics" {
+  source = "./terraform/setup/metrics"
+  setup  = module.google-setup
 }
 
 module "filter" {
@@ -40,9 +46,16 @@ module "filter" {
 }
 
 module "router" {
-  source     = "./cloudfunctions/router/"
-  setup      = module.google-setup
-  folder-ids = var.folder-ids
+  source        = "./cloudfunctions/router/"
+  setup         = module.google-setup
+  folder-ids    = var.folder-ids
+  batch-routing = var.batch-routing
+}
+
+module "route_batch" {
+  source        = "./cloudfunctions/routebatch"
+  setup         = module.google-setup
+  batch-routing = var.batch-routing
 }
 
 module "close_public_bucket" {
@@ -51,12 +64,94 @@ module "close_public_bucket" {
   folder-ids = var.folder-ids
 }
 
+module "remove_public_pubsub" {
+  source     = "./cloudfunctions/pubsub/removepublicpubsub"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "remove_public_kms" {
+  source     = "./cloudfunctions/kms/removepublickms"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "disable_aws_access_key" {
+  source = "./cloudfunctions/aws/disableaccesskey"
+  setup  = module.google-setup
+}
+
+module "isolate_ec2_instance" {
+  source = "./cloudfunctions/aws/isolateinstance"
+  setup  = module.google-setup
+}
+
+module "remove_public_blob_container" {
+  source = "./cloudfunctions/azure/removepubliccontainer"
+  setup  = module.google-setup
+}
+
+module "registered_automation" {
+  source = "./automations"
+  setup  = module.google-setup
+}
+
+module "quarantine_image" {
+  source     = "./cloudfunctions/artifactregistry/quarantineimage"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "remove_public_repository" {
+  source     = "./cloudfunctions/artifactregistry/removepublicrepository"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
 module "revoke_iam_grants" {
   source     = "./cloudfunctions/iam/revoke"
   setup      = module.google-setup
   folder-ids = var.folder-ids
 }
 
+module "revoke_iam_grants_exact" {
+  source     = "./cloudfunctions/iam/revokegrants"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "remove_impersonation" {
+  source     = "./cloudfunctions/iam/removeimpersonation"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "remove_default_editor" {
+  source     = "./cloudfunctions/iam/removedefaulteditor"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "disable_old_keys" {
+  source     = "./cloudfunctions/iam/disableoldkeys"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "backup_iam_policies" {
+  source     = "./cloudfunctions/iam/backuppolicies"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+  resources  = var.iam-policy-backup-resources
+}
+
+module "restore_iam_policy" {
+  source     = "./cloudfunctions/iam/restorepolicy"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+  bucket     = module.backup_iam_policies.bucket
+}
+
 module "create_disk_snapshot" {
   source              = "./cloudfunctions/gce/createsnapshot"
   setup               = module.google-setup
@@ -77,12 +172,36 @@ module "open_firewall" {
   folder-ids = var.folder-ids
 }
 
+module "quarantine_instance" {
+  source     = "./cloudfunctions/gce/quarantineinstance"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
 module "remove_public_ip" {
   source     = "./cloudfunctions/gce/removepublicip"
   setup      = module.google-setup
   folder-ids = var.folder-ids
 }
 
+module "rotate_secrets" {
+  source     = "./cloudfunctions/secretmanager/rotatesecrets"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "stop_rogue_job" {
+  source     = "./cloudfunctions/dataprocessing/stoproguejob"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "disable_billing" {
+  source     = "./cloudfunctions/billing/disablebilling"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
 module "close_public_dataset" {
   source     = "./cloudfunctions/bigquery/closepublicdataset"
   setup      = module.google-setup
@@ -107,6 +226,96 @@ module "disable_dashboard" {
   folder-ids = var.folder-ids
 }
 
+module "delete_pod" {
+  source     = "./cloudfunctions/gke/deletepod"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "drain_node" {
+  source     = "./cloudfunctions/gke/drainnode"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "enable_authorized_networks" {
+  source     = "./cloudfunctions/gke/enableauthorizednetworks"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "block_project_ssh_keys" {
+  source     = "./cloudfunctions/gce/blockprojectsshkeys"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "delete_firewall_rules" {
+  source     = "./cloudfunctions/gce/deletefirewallrules"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "enable_shielded_vm" {
+  source     = "./cloudfunctions/gce/enableshieldedvm"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "disable_serial_port" {
+  source     = "./cloudfunctions/gce/disableserialport"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "enable_dnssec" {
+  source     = "./cloudfunctions/dns/enablednssec"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "restrict_api_key" {
+  source     = "./cloudfunctions/apikeys/restrictapikey"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "enable_os_login" {
+  source     = "./cloudfunctions/gce/enableoslogin"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "enable_private_access" {
+  source     = "./cloudfunctions/gce/enableprivateaccess"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "enable_flow_logs" {
+  source     = "./cloudfunctions/gce/enableflowlogs"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "remove_external_exposure" {
+  source     = "./cloudfunctions/gce/removeexternalexposure"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "enforce_authentication" {
+  source     = "./cloudfunctions/serverless/enforceauthentication"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "remove_public_invoker" {
+  source     = "./cloudfunctions/serverless/removepublicinvoker"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
 module "update_password" {
   source     = "./cloudfunctions/cloud-sql/updatepassword"
   setup      = module.google-setup
@@ -125,3 +334,54 @@ module "enable_audit_logs" {
 //  setup      = module.google-setup
 //  folder-ids = var.folder-ids
 //}
+
+module "suspend_user" {
+  source = "./cloudfunctions/workspace/suspenduser"
+  setup  = module.google-setup
+}
+
+module "enforce_reenrollment" {
+  source = "./cloudfunctions/workspace/enforcereenrollment"
+  setup  = module.google-setup
+}
+
+module "revoke_user_tokens" {
+  source = "./cloudfunctions/workspace/revoketokens"
+  setup  = module.google-setup
+}
+
+module "undo_remediation" {
+  source     = "./cloudfunctions/undo"
+  setup      = module.google-setup
+  folder-ids = var.folder-ids
+}
+
+module "expire_exemptions" {
+  source = "./cloudfunctions/expireexemptions"
+  setup  = module.google-setup
+}
+
+module "send_digests" {
+  source = "./cloudfunctions/senddigests"
+  setup  = module.google-setup
+}
+
+module "dead_letter" {
+  source = "./cloudfunctions/deadletter"
+  setup  = module.google-setup
+}
+
+module "replay_dead_letters" {
+  source = "./cloudfunctions/replay"
+  setup  = module.google-setup
+}
+
+module "approve_remediation" {
+  source = "./cloudfunctions/approve"
+  setup  = module.google-setup
+}
+
+module "falco_webhook" {
+  source = "./cloudfunctions/falco/ingestalert"
+  setup  = module.google-setup
+}
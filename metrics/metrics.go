@@ -0,0 +1,117 @@
+// Package metrics records the health of the automations as log-based metrics.
+package metrics
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+// Metric names, the values of the metric field log-based metrics filter on.
+const (
+	// FindingReceived counts the findings received by the router.
+	FindingReceived = "finding_received"
+	// Remediation counts the outcomes of remediations and findings the router skipped, with the
+	// latency from the finding's event to the outcome.
+	Remediation = "remediation"
+)
+
+// Recorder records the metrics of the automations.
+type Recorder interface {
+	// Received records a finding received by the router.
+	Received(category, severity string)
+	// Outcome records the outcome of the record, eventTime is when the finding happened and is
+	// zero if it's unknown.
+	Outcome(r *services.AuditRecord, eventTime time.Time)
+}
+
+// ParseEventTime returns the event time of a finding, such as "2019-09-19T16:58:39.276Z", or zero
+// if it can't be parsed.
+func ParseEventTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// Noop discards metrics, for tests and functions that don't record any.
+type Noop struct{}
+
+// Received discards the finding.
+func (Noop) Received(category, severity string) {}
+
+// Outcome discards the outcome.
+func (Noop) Outcome(r *services.AuditRecord, eventTime time.Time) {}
+
+// entry is a structured log entry Cloud Logging parses into the jsonPayload of the entry.
+type entry struct {
+	Severity        string `json:"severity"`
+	Message         string `json:"message"`
+	Metric          string `json:"metric"`
+	Category        string `json:"category,omitempty"`
+	FindingSeverity string `json:"finding_severity,omitempty"`
+	Action          string `json:"action,omitempty"`
+	Outcome         string `json:"outcome,omitempty"`
+	// Latency is the seconds from the finding's event to the outcome, if known.
+	Latency float64 `json:"latency_seconds,omitempty"`
+}
+
+// Log records metrics as structured log entries, which the log-based metrics deployed by
+// Terraform count.
+type Log struct {
+	mu  sync.Mutex
+	w   io.Writer
+	now func() time.Time
+}
+
+// NewLog returns a recorder writing log entries to w, the standard output in Cloud Functions.
+func NewLog(w io.Writer) *Log {
+	return &Log{w: w, now: time.Now}
+}
+
+// Received records a finding received by the router.
+func (l *Log) Received(category, severity string) {
+	l.write(&entry{Metric: FindingReceived, Category: category, FindingSeverity: severity})
+}
+
+// Outcome records the outcome of the record.
+func (l *Log) Outcome(r *services.AuditRecord, eventTime time.Time) {
+	e := &entry{Metric: Remediation, Category: r.Category, FindingSeverity: r.Severity, Action: r.Action, Outcome: r.Outcome}
+	if !eventTime.IsZero() {
+		e.Latency = l.now().Sub(eventTime).Seconds()
+	}
+	l.write(e)
+}
+
+func (l *Log) write(e *entry) {
+	e.Severity, e.Message = "INFO", "automation metric "+e.Metric
+	b, err := json.Marshal(e)
+	if err != nil {
+		log.Printf("failed to marshal metric %q: %q", e.Metric, err)
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.w.Write(append(b, '\n')); err != nil {
+		log.Printf("failed to write metric %q: %q", e.Metric, err)
+	}
+}
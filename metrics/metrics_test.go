@@ -0,0 +1,85 @@
+package metrics
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/services"
+)
+
+func TestLog(t *testing.T) {
+	eventTime := time.Date(2019, 9, 19, 16, 58, 39, 0, time.UTC)
+	for _, tt := range []struct {
+		name   string
+		record func(*Log)
+		want   map[string]interface{}
+	}{
+		{
+			name:   "received",
+			record: func(l *Log) { l.Received("bad_ip", "HIGH") },
+			want:   map[string]interface{}{"metric": FindingReceived, "category": "bad_ip", "finding_severity": "HIGH"},
+		},
+		{
+			name: "remediation",
+			record: func(l *Log) {
+				l.Outcome(&services.AuditRecord{Action: "OpenFirewall", Category: "open_firewall", Outcome: services.AuditSuccess}, eventTime)
+			},
+			want: map[string]interface{}{"metric": Remediation, "action": "OpenFirewall", "category": "open_firewall", "outcome": "success", "latency_seconds": 90.0},
+		},
+		{
+			name: "unknown event time",
+			record: func(l *Log) {
+				l.Outcome(&services.AuditRecord{Action: "Router", Outcome: services.AuditSkipped}, time.Time{})
+			},
+			want: map[string]interface{}{"metric": Remediation, "action": "Router", "outcome": "skipped"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			l := NewLog(&b)
+			l.now = func() time.Time { return eventTime.Add(90 * time.Second) }
+			tt.record(l)
+			if !strings.HasSuffix(b.String(), "}\n") || strings.Count(b.String(), "\n") != 1 {
+				t.Fatalf("%s failed: got %q want one entry per line", tt.name, b.String())
+			}
+			var got map[string]interface{}
+			if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("%s failed: got %s %v want %v", tt.name, k, got[k], v)
+				}
+			}
+			if _, ok := tt.want["latency_seconds"]; !ok && got["latency_seconds"] != nil {
+				t.Errorf("%s failed: got latency %v for an unknown event time", tt.name, got["latency_seconds"])
+			}
+		})
+	}
+}
+
+func TestParseEventTime(t *testing.T) {
+	if got := ParseEventTime("2019-09-19T16:58:39.276Z"); got.IsZero() {
+		t.Errorf("failed to parse event time")
+	}
+	if got := ParseEventTime(""); !got.IsZero() {
+		t.Errorf("got %s for an empty event time", got)
+	}
+}
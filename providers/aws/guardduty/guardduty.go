@@ -0,0 +1,176 @@
+// Package guardduty represents AWS GuardDuty findings, either mirrored into Security Command
+// Center or delivered by an SQS bridge as the EventBridge events GuardDuty publishes.
+package guardduty
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"encoding/json"
+
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/aws/disableaccesskey"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/aws/isolateinstance"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+	"github.com/pkg/errors"
+)
+
+// serviceName is the name of the service reporting GuardDuty findings.
+const serviceName = "guardduty"
+
+// reader reads the GuardDuty findings mirrored into Security Command Center.
+var reader = &findings.Reader{}
+
+// rules maps the types of resources of GuardDuty findings to the rule names of the findings.
+var rules = map[string]string{
+	"AccessKey": "guardduty_access_key",
+	"Instance":  "guardduty_instance",
+}
+
+// Finding represents this finding.
+type Finding struct {
+	guardDuty *guardDutyFinding
+	// scc is set for findings mirrored into Security Command Center.
+	scc *sccFinding
+}
+
+// guardDutyFinding is the GuardDuty finding, the detail of its EventBridge event or the source
+// properties of its Security Command Center finding.
+type guardDutyFinding struct {
+	ID        string `json:"id"`
+	AccountID string `json:"accountId"`
+	Region    string `json:"region"`
+	Type      string `json:"type"`
+	Resource  struct {
+		ResourceType     string `json:"resourceType"`
+		AccessKeyDetails struct {
+			AccessKeyID string `json:"accessKeyId"`
+			UserName    string `json:"userName"`
+			UserType    string `json:"userType"`
+		} `json:"accessKeyDetails"`
+		InstanceDetails struct {
+			InstanceID string `json:"instanceId"`
+		} `json:"instanceDetails"`
+	} `json:"resource"`
+	Service struct {
+		ServiceName string `json:"serviceName"`
+	} `json:"service"`
+}
+
+// event is the EventBridge event of the finding, relayed from SQS.
+type event struct {
+	Source string            `json:"source"`
+	Detail *guardDutyFinding `json:"detail"`
+}
+
+// sccFinding holds the fields of the Security Command Center finding used by this provider.
+type sccFinding struct {
+	findings.Finding
+	GuardDuty guardDutyFinding `finding:"sourceProperties"`
+}
+
+// Name returns the rule name of the finding.
+func (f *Finding) Name(b []byte) string {
+	ff, err := New(b)
+	if err != nil {
+		return ""
+	}
+	g := ff.guardDuty
+	switch {
+	case g.Resource.ResourceType == "AccessKey" && g.Resource.AccessKeyDetails.AccessKeyID == "":
+		return ""
+	case g.Resource.ResourceType == "Instance" && g.Resource.InstanceDetails.InstanceID == "":
+		return ""
+	}
+	return rules[g.Resource.ResourceType]
+}
+
+// New returns a new finding.
+func New(b []byte) (*Finding, error) {
+	var e event
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, err
+	}
+	if e.Source == "aws.guardduty" && e.Detail != nil {
+		return newFinding(e.Detail, nil)
+	}
+	var scc sccFinding
+	if err := reader.Read(b, &scc); err != nil {
+		return nil, err
+	}
+	return newFinding(&scc.GuardDuty, &scc)
+}
+
+// newFinding returns the finding if GuardDuty reported it.
+func newFinding(g *guardDutyFinding, scc *sccFinding) (*Finding, error) {
+	if g.Service.ServiceName != serviceName {
+		return nil, errors.Wrap(findings.ErrUnsupportedFinding, "not a guardduty finding")
+	}
+	return &Finding{guardDuty: g, scc: scc}, nil
+}
+
+// FindingName returns the Security Command Center name of the finding, empty for findings
+// delivered by the SQS bridge.
+func (f *Finding) FindingName() string {
+	if f.scc == nil {
+		return ""
+	}
+	return f.scc.Name
+}
+
+// EventTime returns the event time of the Security Command Center finding.
+func (f *Finding) EventTime() string {
+	if f.scc == nil {
+		return ""
+	}
+	return f.scc.EventTime
+}
+
+// SecurityMarks returns the security marks of the Security Command Center finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	if f.scc == nil {
+		return nil
+	}
+	return f.scc.SecurityMarks
+}
+
+// AccountID returns the ID of the AWS account of the finding.
+func (f *Finding) AccountID() string {
+	return f.guardDuty.AccountID
+}
+
+// Type returns the GuardDuty type of the finding, such as
+// "UnauthorizedAccess:IAMUser/InstanceCredentialExfiltration.OutsideAWS".
+func (f *Finding) Type() string {
+	return f.guardDuty.Type
+}
+
+// DisableAccessKey returns values for the disable AWS access key automation.
+func (f *Finding) DisableAccessKey() *disableaccesskey.Values {
+	details := f.guardDuty.Resource.AccessKeyDetails
+	return &disableaccesskey.Values{
+		AccountID:   f.guardDuty.AccountID,
+		UserName:    details.UserName,
+		AccessKeyID: details.AccessKeyID,
+		UserType:    details.UserType,
+	}
+}
+
+// IsolateInstance returns values for the isolate EC2 instance automation.
+func (f *Finding) IsolateInstance() *isolateinstance.Values {
+	return &isolateinstance.Values{
+		AccountID:  f.guardDuty.AccountID,
+		Region:     f.guardDuty.Region,
+		InstanceID: f.guardDuty.Resource.InstanceDetails.InstanceID,
+	}
+}
@@ -0,0 +1,134 @@
+// Package defender represents Microsoft Defender for Cloud alerts, exported from Azure and
+// forwarded into the Pub/Sub topic as the alerts of the Azure Resource Manager API.
+package defender
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/azure/removepubliccontainer"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+	"github.com/pkg/errors"
+)
+
+// alertType is the type of resource of Defender for Cloud alerts.
+const alertType = "Microsoft.Security/Locations/alerts"
+
+// rules maps the types of Defender for Cloud alerts to the rule names of the findings.
+var rules = map[string]string{
+	"Storage.Blob_AnonymousAccessAnomaly":                     "defender_public_container",
+	"Storage.Blob_OpenContainersScanning.SuccessfulDiscovery": "defender_public_container",
+}
+
+// Finding represents this finding.
+type Finding struct {
+	alert *alert
+	// account is the storage account the alert is about, parsed from its Azure resource ID.
+	account storageAccount
+}
+
+// alert is the Defender for Cloud alert.
+type alert struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Properties struct {
+		AlertType           string `json:"alertType"`
+		Status              string `json:"status"`
+		TimeGeneratedUTC    string `json:"timeGeneratedUtc"`
+		CompromisedEntity   string `json:"compromisedEntity"`
+		ResourceIdentifiers []struct {
+			Type            string `json:"type"`
+			AzureResourceID string `json:"azureResourceId"`
+		} `json:"resourceIdentifiers"`
+		ExtendedProperties map[string]string `json:"extendedProperties"`
+	} `json:"properties"`
+}
+
+// storageAccount is a storage account identified by its Azure resource ID, such as
+// "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/account".
+type storageAccount struct {
+	subscriptionID string
+	resourceGroup  string
+	name           string
+}
+
+// Name returns the rule name of the finding.
+func (f *Finding) Name(b []byte) string {
+	ff, err := New(b)
+	if err != nil {
+		return ""
+	}
+	if ff.account.name == "" || ff.alert.Properties.ExtendedProperties["Container"] == "" {
+		return ""
+	}
+	return rules[ff.alert.Properties.AlertType]
+}
+
+// New returns a new finding.
+func New(b []byte) (*Finding, error) {
+	var a alert
+	if err := json.Unmarshal(b, &a); err != nil {
+		return nil, err
+	}
+	if a.Type != alertType {
+		return nil, errors.Wrap(findings.ErrUnsupportedFinding, "not a defender for cloud alert")
+	}
+	f := &Finding{alert: &a}
+	for _, r := range a.Properties.ResourceIdentifiers {
+		if r.Type == "AzureResource" {
+			f.account = parseStorageAccount(r.AzureResourceID)
+		}
+	}
+	return f, nil
+}
+
+// parseStorageAccount returns the storage account of the Azure resource ID, empty if the resource
+// isn't a storage account.
+func parseStorageAccount(id string) storageAccount {
+	// Keys of resource IDs are case insensitive.
+	p := strings.Split(strings.TrimPrefix(id, "/"), "/")
+	if len(p) != 8 || !strings.EqualFold(p[0], "subscriptions") || !strings.EqualFold(p[2], "resourceGroups") ||
+		!strings.EqualFold(p[4], "providers") || !strings.EqualFold(p[5], "Microsoft.Storage") || !strings.EqualFold(p[6], "storageAccounts") {
+		return storageAccount{}
+	}
+	return storageAccount{subscriptionID: p[1], resourceGroup: p[3], name: p[7]}
+}
+
+// AlertID returns the Azure resource ID of the alert.
+func (f *Finding) AlertID() string {
+	return f.alert.ID
+}
+
+// EventTime returns the time the alert was generated.
+func (f *Finding) EventTime() string {
+	return f.alert.Properties.TimeGeneratedUTC
+}
+
+// SubscriptionID returns the ID of the Azure subscription of the alert.
+func (f *Finding) SubscriptionID() string {
+	return f.account.subscriptionID
+}
+
+// RemovePublicContainer returns values for the remove public container access automation.
+func (f *Finding) RemovePublicContainer() *removepubliccontainer.Values {
+	return &removepubliccontainer.Values{
+		SubscriptionID: f.account.subscriptionID,
+		ResourceGroup:  f.account.resourceGroup,
+		StorageAccount: f.account.name,
+		Container:      f.alert.Properties.ExtendedProperties["Container"],
+	}
+}
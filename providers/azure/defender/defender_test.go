@@ -0,0 +1,74 @@
+package defender
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/azure/removepubliccontainer"
+	"github.com/googlecloudplatform/security-response-automation/findings/fixtures"
+)
+
+func TestPublicContainer(t *testing.T) {
+	expected := &removepubliccontainer.Values{
+		SubscriptionID: "0b1f6471-1bf0-4dda-aec3-111122223333",
+		ResourceGroup:  "simulated-rg",
+		StorageAccount: "simulatedaccount",
+		Container:      "simulated-container",
+	}
+	for _, tt := range []struct {
+		name    string
+		finding []byte
+	}{
+		{name: "anonymous access", finding: fixtures.ReadFormat(t, "defender_public_container", fixtures.Defender)},
+		{name: "open containers scanning", finding: fixtures.ReadFormat(t, "defender_public_container", fixtures.Defender, "properties.alertType=Storage.Blob_OpenContainersScanning.SuccessfulDiscovery")},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(tt.finding)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if name := f.Name(tt.finding); name != "defender_public_container" {
+				t.Errorf("%s failed: got:%q want:%q", tt.name, name, "defender_public_container")
+			}
+			if got := f.EventTime(); got != "2020-06-10T17:48:49.358Z" {
+				t.Errorf("%s failed: got:%q want:%q", tt.name, got, "2020-06-10T17:48:49.358Z")
+			}
+			if diff := cmp.Diff(f.RemovePublicContainer(), expected); diff != "" {
+				t.Errorf("%s failed, difference:%+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestOtherFindings(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		finding []byte
+	}{
+		{name: "security health analytics", finding: fixtures.Read(t, "public_bucket_acl")},
+		{name: "guardduty", finding: fixtures.ReadFormat(t, "guardduty_instance", fixtures.EventBridge)},
+		{name: "unsupported alert", finding: fixtures.ReadFormat(t, "defender_public_container", fixtures.Defender, "properties.alertType=Storage.Blob_MalwareHashReputation")},
+		{name: "missing container", finding: fixtures.ReadFormat(t, "defender_public_container", fixtures.Defender, "properties.extendedProperties.Container=")},
+		{name: "not a storage account", finding: []byte(`{"type": "Microsoft.Security/Locations/alerts", "properties": {"alertType": "Storage.Blob_AnonymousAccessAnomaly", "resourceIdentifiers": [{"type": "AzureResource", "azureResourceId": "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm"}], "extendedProperties": {"Container": "c"}}}`)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if name := (&Finding{}).Name(tt.finding); name != "" {
+				t.Errorf("%s failed: got:%q want:%q", tt.name, name, "")
+			}
+		})
+	}
+}
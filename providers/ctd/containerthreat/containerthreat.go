@@ -0,0 +1,124 @@
+// Package containerthreat represents Container Threat Detection findings.
+package containerthreat
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/artifactregistry/quarantineimage"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gke/deletepod"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gke/drainnode"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/secretmanager/rotatesecrets"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+	"github.com/googlecloudplatform/security-response-automation/providers/ctd"
+)
+
+// reader reads the findings of Container Threat Detection.
+var reader = &findings.Reader{}
+
+// Finding represents this finding.
+type Finding struct {
+	containerThreat containerThreat
+}
+
+// containerThreat holds the fields of a Container Threat Detection finding used by this provider.
+type containerThreat struct {
+	findings.Finding
+	VMInstanceName    string `finding:"sourceProperties.VM_Instance_Name,optional"`
+	PodNamespace      string `finding:"sourceProperties.Pod_Namespace,optional"`
+	PodName           string `finding:"sourceProperties.Pod_Name,optional"`
+	ContainerImageURI string `finding:"sourceProperties.Container_Image_Uri,optional"`
+}
+
+// Name returns the rule name of the finding.
+func (f *Finding) Name(b []byte) string {
+	ff, err := New(b)
+	if err != nil {
+		return ""
+	}
+	return ctd.RuleName(ff.containerThreat.ResourceName, ff.containerThreat.Category)
+}
+
+// New returns a new finding.
+func New(b []byte) (*Finding, error) {
+	var f Finding
+	if err := reader.Read(b, &f.containerThreat); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.containerThreat.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.containerThreat.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.containerThreat.SecurityMarks
+}
+
+// DrainNode returns values for the drain node automation.
+func (f *Finding) DrainNode() *drainnode.Values {
+	resource := f.containerThreat.ResourceName
+	return &drainnode.Values{
+		ProjectID: ctd.ProjectID(resource),
+		Zone:      ctd.ClusterZone(resource),
+		ClusterID: ctd.ClusterID(resource),
+		Node:      f.containerThreat.VMInstanceName,
+	}
+}
+
+// DeletePod returns values for the delete pod automation. The pod is resolved from the resource
+// name, falling back to the finding's source properties.
+func (f *Finding) DeletePod() *deletepod.Values {
+	resource := f.containerThreat.ResourceName
+	namespace, pod := ctd.PodNamespace(resource), ctd.PodName(resource)
+	if pod == "" {
+		namespace = f.containerThreat.PodNamespace
+		pod = f.containerThreat.PodName
+	}
+	return &deletepod.Values{
+		ProjectID:   ctd.ProjectID(resource),
+		Zone:        ctd.ClusterZone(resource),
+		ClusterID:   ctd.ClusterID(resource),
+		Namespace:   namespace,
+		Pod:         pod,
+		FindingName: f.containerThreat.Name,
+	}
+}
+
+// QuarantineImage returns values for the quarantine image automation. The project is the cluster's
+// project whose Binary Authorization policy admits the image.
+func (f *Finding) QuarantineImage() *quarantineimage.Values {
+	return &quarantineimage.Values{
+		ProjectID: ctd.ProjectID(f.containerThreat.ResourceName),
+		Image:     f.containerThreat.ContainerImageURI,
+	}
+}
+
+// RotateSecrets returns values for the rotate secrets automation. The pod's namespace is the
+// workload whose secrets are rotated.
+func (f *Finding) RotateSecrets() *rotatesecrets.Values {
+	pod := f.DeletePod()
+	return &rotatesecrets.Values{
+		ProjectID: pod.ProjectID,
+		Workload:  pod.Namespace,
+	}
+}
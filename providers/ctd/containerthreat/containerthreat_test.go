@@ -0,0 +1,163 @@
+package containerthreat
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/artifactregistry/quarantineimage"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gke/deletepod"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gke/drainnode"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/secretmanager/rotatesecrets"
+	"github.com/googlecloudplatform/security-response-automation/findings/fixtures"
+)
+
+func TestReadFinding(t *testing.T) {
+	const regionalFinding = `{
+		"finding": {
+			"name": "organizations/154584661726/sources/2673592633662526977/findings/r1",
+			"resourceName": "//container.googleapis.com/projects/test-project/locations/us-central1/clusters/test-cluster",
+			"category": "Reverse Shell",
+			"sourceProperties": {
+				"Pod_Namespace": "default",
+				"Pod_Name": "shell"
+			}
+		}
+	}`
+	const sqlFinding = `{
+		"finding": {
+			"resourceName": "//cloudsql.googleapis.com/projects/test-project/instances/public-sql-instance",
+			"category": "Reverse Shell"
+		}
+	}`
+	for _, tt := range []struct {
+		name, ruleName string
+		bytes          []byte
+		values         *drainnode.Values
+	}{
+		{
+			name:     "added binary executed",
+			ruleName: "added_binary_executed",
+			bytes:    fixtures.Read(t, "added_binary_executed"),
+			values: &drainnode.Values{
+				ProjectID: "test-project",
+				Zone:      "us-central1-a",
+				ClusterID: "test-cluster",
+				Node:      "gke-test-cluster-default-pool-3b1f2a7c-x9k2",
+			},
+		},
+		{
+			name:     "regional cluster without node",
+			ruleName: "reverse_shell",
+			bytes:    []byte(regionalFinding),
+			values: &drainnode.Values{
+				ProjectID: "test-project",
+				Zone:      "us-central1",
+				ClusterID: "test-cluster",
+			},
+		},
+		{name: "not a container finding", ruleName: "", bytes: []byte(sqlFinding)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Finding{}
+			if name := f.Name(tt.bytes); name != tt.ruleName {
+				t.Errorf("%s failed: got:%q want:%q", tt.name, name, tt.ruleName)
+			}
+			if tt.values == nil {
+				return
+			}
+			r, err := New(tt.bytes)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(r.DrainNode(), tt.values); diff != "" {
+				t.Errorf("%s failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestDeletePodValues(t *testing.T) {
+	const podResourceFinding = `{
+		"finding": {
+			"name": "organizations/154584661726/sources/8072959356540502587/findings/c1",
+			"resourceName": "//container.googleapis.com/projects/test-project/locations/us-central1/clusters/test-cluster/k8s/namespaces/mining/pods/xmrig-5d8c",
+			"category": "Added Binary Executed"
+		}
+	}`
+	for _, tt := range []struct {
+		name   string
+		bytes  []byte
+		values *deletepod.Values
+	}{
+		{
+			name:  "pod from source properties",
+			bytes: fixtures.Read(t, "added_binary_executed"),
+			values: &deletepod.Values{
+				ProjectID:   "test-project",
+				Zone:        "us-central1-a",
+				ClusterID:   "test-cluster",
+				Namespace:   "default",
+				Pod:         "miner",
+				FindingName: "organizations/154584661726/sources/8072959356540502587/findings/b8a0f6d3e2c14c6c9d1f2e3a4b5c6d7e",
+			},
+		},
+		{
+			name:  "pod from resource name",
+			bytes: []byte(podResourceFinding),
+			values: &deletepod.Values{
+				ProjectID:   "test-project",
+				Zone:        "us-central1",
+				ClusterID:   "test-cluster",
+				Namespace:   "mining",
+				Pod:         "xmrig-5d8c",
+				FindingName: "organizations/154584661726/sources/8072959356540502587/findings/c1",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := New(tt.bytes)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(r.DeletePod(), tt.values); diff != "" {
+				t.Errorf("%s failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestQuarantineImageValues(t *testing.T) {
+	r, err := New(fixtures.Read(t, "added_binary_executed"))
+	if err != nil {
+		t.Fatalf("failed to read finding: %q", err)
+	}
+	want := &quarantineimage.Values{ProjectID: "test-project", Image: "docker.io/library/alpine:latest"}
+	if diff := cmp.Diff(r.QuarantineImage(), want); diff != "" {
+		t.Errorf("quarantine image values difference: %+v", diff)
+	}
+}
+
+func TestRotateSecretsValues(t *testing.T) {
+	r, err := New(fixtures.Read(t, "added_binary_executed"))
+	if err != nil {
+		t.Fatalf("failed to read finding: %q", err)
+	}
+	want := &rotatesecrets.Values{ProjectID: "test-project", Workload: "default"}
+	if diff := cmp.Diff(r.RotateSecrets(), want); diff != "" {
+		t.Errorf("rotate secrets values difference: %+v", diff)
+	}
+}
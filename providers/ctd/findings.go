@@ -0,0 +1,83 @@
+package ctd
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"regexp"
+	"strings"
+)
+
+// resourcePrefix is the prefix of resource names reported by Container Threat Detection.
+const resourcePrefix = "//container.googleapis.com/"
+
+var (
+	// extractProjectID is a regex to extract the project ID that is on the resource name.
+	extractProjectID = regexp.MustCompile(`/projects/([^/]+)`)
+	// extractClusterZone is a regex to extract the zone or region of the cluster that is on the resource name.
+	extractClusterZone = regexp.MustCompile(`/(?:zones|locations)/([^/]+)/clusters`)
+	// extractClusterID is a regex to extract the cluster ID that is on the resource name.
+	extractClusterID = regexp.MustCompile(`/clusters/([^/]+)`)
+	// extractPod is a regex to extract the namespace and name of the pod that is on the resource name.
+	extractPod = regexp.MustCompile(`/k8s/namespaces/([^/]+)/pods/([^/]+)`)
+)
+
+var categories = map[string]string{
+	"added binary executed":     "added_binary_executed",
+	"added library loaded":      "added_library_loaded",
+	"reverse shell":             "reverse_shell",
+	"malicious script executed": "malicious_script_executed",
+}
+
+// RuleName returns the rule name of a Container Threat Detection finding or an empty string
+// if the finding is not from Container Threat Detection.
+func RuleName(resource, category string) string {
+	if !strings.HasPrefix(resource, resourcePrefix) {
+		return ""
+	}
+	return categories[strings.ToLower(strings.TrimSpace(category))]
+}
+
+// ProjectID returns the project ID from a resource name.
+func ProjectID(resource string) string {
+	return submatch(extractProjectID, resource, 1)
+}
+
+// ClusterZone returns the zone or region of the cluster from a resource name.
+func ClusterZone(resource string) string {
+	return submatch(extractClusterZone, resource, 1)
+}
+
+// ClusterID returns the cluster ID from a resource name.
+func ClusterID(resource string) string {
+	return submatch(extractClusterID, resource, 1)
+}
+
+// PodNamespace returns the pod namespace from a resource name.
+func PodNamespace(resource string) string {
+	return submatch(extractPod, resource, 1)
+}
+
+// PodName returns the pod name from a resource name.
+func PodName(resource string) string {
+	return submatch(extractPod, resource, 2)
+}
+
+func submatch(re *regexp.Regexp, resource string, i int) string {
+	m := re.FindStringSubmatch(resource)
+	if len(m) <= i {
+		return ""
+	}
+	return m[i]
+}
@@ -0,0 +1,48 @@
+// Package detection represents the detections of custom detectors, mapped to findings by the
+// schemas of the configuration.
+package detection
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"strings"
+
+	"github.com/googlecloudplatform/security-response-automation/findings"
+)
+
+// reader reads the detections of any category.
+var reader = &findings.Reader{}
+
+// detection holds the fields of the finding used by this provider.
+type detection struct {
+	findings.Finding
+	Parent string `finding:"parent"`
+}
+
+// Finding represents this finding.
+type Finding struct{}
+
+// Name returns the rule name of the finding, its category in lower case. Detections are routed
+// as the rule of their category or of its mapping.
+func (f *Finding) Name(b []byte) string {
+	var d detection
+	if err := reader.Read(b, &d); err != nil {
+		return ""
+	}
+	if !strings.HasPrefix(d.Parent, findings.SchemaParent) {
+		return ""
+	}
+	return strings.ToLower(d.Category)
+}
@@ -0,0 +1,46 @@
+package detection
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"testing"
+
+	"github.com/googlecloudplatform/security-response-automation/findings/fixtures"
+)
+
+func TestName(t *testing.T) {
+	const detection = `{
+		"finding": {
+			"name": "schemas/acme/detections/efdb62e20181388c616c485ed9fcaddb",
+			"parent": "schemas/acme",
+			"resourceName": "//storage.googleapis.com/public-assets",
+			"category": "ACME_PUBLIC_BUCKET"
+		}
+	}`
+	for _, tt := range []struct {
+		name, ruleName string
+		bytes          []byte
+	}{
+		{name: "detection", ruleName: "acme_public_bucket", bytes: []byte(detection)},
+		{name: "scc finding", ruleName: "", bytes: fixtures.Read(t, "public_bucket_acl")},
+		{name: "not json", ruleName: "", bytes: []byte("not json")},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if name := (&Finding{}).Name(tt.bytes); name != tt.ruleName {
+				t.Errorf("%s failed: got:%q want:%q", tt.name, name, tt.ruleName)
+			}
+		})
+	}
+}
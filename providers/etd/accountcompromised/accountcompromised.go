@@ -0,0 +1,106 @@
+package accountcompromised
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/workspace/enforcereenrollment"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/workspace/revoketokens"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/workspace/suspenduser"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+	"github.com/googlecloudplatform/security-response-automation/providers/etd"
+)
+
+// ruleNames are the Event Threat Detection rules reporting a compromised Google Workspace account.
+var ruleNames = map[string]bool{
+	"account_disabled_hijacked":      true,
+	"account_disabled_password_leak": true,
+}
+
+// reader reads the findings of compromised Google Workspace accounts.
+var reader = &findings.Reader{}
+
+// Finding represents this finding.
+type Finding struct {
+	account accountCompromised
+}
+
+// accountCompromised holds the fields of the finding used by this provider.
+type accountCompromised struct {
+	etd.Finding
+	PrincipalEmail string `finding:"sourceProperties.properties.principalEmail,optional"`
+}
+
+// Name returns the rule name of the finding.
+//
+// Every rule reporting a compromised account is returned as account_compromised.
+func (f *Finding) Name(b []byte) string {
+	ff, err := New(b)
+	if err != nil {
+		return ""
+	}
+	if !ruleNames[ff.account.Rule()] {
+		return ""
+	}
+	return "account_compromised"
+}
+
+// New returns a new finding.
+func New(b []byte) (*Finding, error) {
+	var f Finding
+	if err := reader.Read(b, &f.account); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.account.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.account.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.account.SecurityMarks
+}
+
+// PasswordLeaked returns true if the finding reports the user's password leaked.
+func (f *Finding) PasswordLeaked() bool {
+	return f.account.Rule() == "account_disabled_password_leak"
+}
+
+// SuspendUser returns values for the suspend user automation.
+func (f *Finding) SuspendUser() *suspenduser.Values {
+	return &suspenduser.Values{
+		UserEmail: f.account.PrincipalEmail,
+	}
+}
+
+// EnforceReenrollment returns values for the enforce re-enrollment automation.
+func (f *Finding) EnforceReenrollment() *enforcereenrollment.Values {
+	return &enforcereenrollment.Values{
+		UserEmail: f.account.PrincipalEmail,
+	}
+}
+
+// RevokeTokens returns values for the revoke user tokens automation.
+func (f *Finding) RevokeTokens() *revoketokens.Values {
+	return &revoketokens.Values{
+		UserEmail: f.account.PrincipalEmail,
+	}
+}
@@ -0,0 +1,96 @@
+package accountcompromised
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/workspace/suspenduser"
+)
+
+func TestReadFinding(t *testing.T) {
+	const (
+		accountHijacked = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/a1",
+				"resourceName": "//cloudresourcemanager.googleapis.com/organizations/154584661726",
+				"category": "Initial Access: Account Disabled Hijacked",
+				"sourceProperties": {
+					"detectionCategory": {
+						"ruleName": "account_disabled_hijacked"
+					},
+					"properties": {
+						"principalEmail": "bob@example.com"
+					}
+				},
+				"eventTime": "2020-06-10T17:48:49Z"
+			}
+		}`
+		passwordLeakCategoryOnly = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/a2",
+				"resourceName": "//cloudresourcemanager.googleapis.com/organizations/154584661726",
+				"category": "Initial Access: Disabled Password Leak",
+				"sourceProperties": {
+					"properties": {
+						"principalEmail": "alice@example.com"
+					}
+				},
+				"eventTime": "2020-06-10T17:48:49Z"
+			}
+		}`
+		badIP = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/a3",
+				"resourceName": "//cloudresourcemanager.googleapis.com/projects/72231686866",
+				"category": "C2: Bad IP",
+				"sourceProperties": {
+					"detectionCategory": {
+						"ruleName": "bad_ip"
+					}
+				}
+			}
+		}`
+	)
+	for _, tt := range []struct {
+		name     string
+		bytes    []byte
+		ruleName string
+		expected *suspenduser.Values
+	}{
+		{name: "hijacked", bytes: []byte(accountHijacked), ruleName: "account_compromised", expected: &suspenduser.Values{UserEmail: "bob@example.com"}},
+		{name: "password leak category only", bytes: []byte(passwordLeakCategoryOnly), ruleName: "account_compromised", expected: &suspenduser.Values{UserEmail: "alice@example.com"}},
+		{name: "other finding", bytes: []byte(badIP), ruleName: ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(tt.bytes)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if name := f.Name(tt.bytes); name != tt.ruleName {
+				t.Errorf("%s got:%q want:%q", tt.name, name, tt.ruleName)
+			}
+			if tt.expected == nil {
+				return
+			}
+			if diff := cmp.Diff(f.SuspendUser(), tt.expected); diff != "" {
+				t.Errorf("%s failed: diff:%s", tt.name, diff)
+			}
+			if email := f.RevokeTokens().UserEmail; email != tt.expected.UserEmail {
+				t.Errorf("%s got:%q want:%q", tt.name, email, tt.expected.UserEmail)
+			}
+		})
+	}
+}
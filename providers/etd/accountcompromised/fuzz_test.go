@@ -0,0 +1,42 @@
+//go:build go1.18
+// +build go1.18
+
+package accountcompromised
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"testing"
+
+	"github.com/googlecloudplatform/security-response-automation/findings/fixtures"
+)
+
+func FuzzFinding(f *testing.F) {
+	f.Add(fixtures.Read(f, "account_compromised"))
+	f.Fuzz(func(t *testing.T, b []byte) {
+		(&Finding{}).Name(b)
+		r, err := New(b)
+		if err != nil {
+			return
+		}
+		r.FindingName()
+		r.EventTime()
+		r.SecurityMarks()
+		r.PasswordLeaked()
+		r.SuspendUser()
+		r.EnforceReenrollment()
+		r.RevokeTokens()
+	})
+}
@@ -3,11 +3,19 @@ package anomalousiam
 
 import (
 	"encoding/json"
+	"time"
 
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/restorepolicy"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/revoke"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/revokegrants"
 	pb "github.com/googlecloudplatform/security-response-automation/compiled/etd/protos"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+	"github.com/googlecloudplatform/security-response-automation/providers/etd"
 )
 
+// reader reads the Security Command Center notifications of anomalous IAM grant findings.
+var reader = &findings.Reader{}
+
 // Name verifies and returns the rule name of the finding.
 func (f *Finding) Name(b []byte) string {
 	ff, err := New(b)
@@ -16,7 +24,7 @@ func (f *Finding) Name(b []byte) string {
 	}
 	name := ""
 	if ff.UseCSCC {
-		name = ff.anomalousIAMSCC.GetFinding().GetSourceProperties().GetDetectionCategory().GetRuleName()
+		name = ff.anomalousIAMSCC.Rule()
 	} else {
 		name = ff.anomalousIAM.GetJsonPayload().GetDetectionCategory().GetRuleName()
 	}
@@ -33,11 +41,19 @@ func New(b []byte) (*Finding, error) {
 		return nil, err
 	}
 	if f.anomalousIAM.GetJsonPayload().GetDetectionCategory().GetRuleName() != "" {
+		var g grant
+		if err := json.Unmarshal(b, &g); err != nil {
+			return nil, err
+		}
+		f.bindingDeltas = g.JSONPayload.Properties.SensitiveRoleGrant.BindingDeltas
+		f.eventTime = g.Timestamp
 		return &f, nil
 	}
-	if err := json.Unmarshal(b, &f.anomalousIAMSCC); err != nil {
+	if err := reader.Read(b, &f.anomalousIAMSCC); err != nil {
 		return nil, err
 	}
+	f.bindingDeltas = f.anomalousIAMSCC.Properties.SensitiveRoleGrant.BindingDeltas
+	f.eventTime = f.anomalousIAMSCC.EventTime
 	f.UseCSCC = true
 	return &f, nil
 }
@@ -46,19 +62,112 @@ func New(b []byte) (*Finding, error) {
 type Finding struct {
 	UseCSCC         bool
 	anomalousIAM    *pb.AnomalousIAMGrant
-	anomalousIAMSCC *pb.AnomalousIAMGrantSCC
+	anomalousIAMSCC anomalousIAMFinding
+	bindingDeltas   []bindingDelta
+	eventTime       string
+}
+
+// bindingDelta is a single IAM policy change reported by the finding.
+type bindingDelta struct {
+	Action string `json:"action"`
+	Role   string `json:"role"`
+	Member string `json:"member"`
+}
+
+// properties contains the finding properties not present in the compiled protos.
+type properties struct {
+	SensitiveRoleGrant struct {
+		BindingDeltas []bindingDelta `json:"bindingDeltas"`
+	} `json:"sensitiveRoleGrant"`
+}
+
+type grant struct {
+	JSONPayload struct {
+		Properties properties `json:"properties"`
+	} `json:"jsonPayload"`
+	Timestamp string `json:"timestamp"`
+}
+
+// anomalousIAMFinding holds the fields of the Security Command Center finding used by this provider.
+type anomalousIAMFinding struct {
+	etd.Finding
+	Evidence []struct {
+		SourceLogID struct {
+			ProjectID string `json:"projectId"`
+		} `json:"sourceLogId"`
+	} `finding:"sourceProperties.evidence,optional"`
+	Properties struct {
+		SensitiveRoleGrant struct {
+			Members       []string       `json:"members"`
+			BindingDeltas []bindingDelta `json:"bindingDeltas"`
+		} `json:"sensitiveRoleGrant"`
+	} `finding:"sourceProperties.properties,optional"`
+}
+
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.anomalousIAMSCC.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.anomalousIAMSCC.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.anomalousIAMSCC.SecurityMarks
 }
 
 // IAMRevoke returns values for the IAM revoke automation.
 func (f *Finding) IAMRevoke() *revoke.Values {
 	if f.UseCSCC {
 		return &revoke.Values{
-			ProjectID:       f.anomalousIAMSCC.GetFinding().GetSourceProperties().GetEvidence()[0].GetSourceLogId().GetProjectId(),
-			ExternalMembers: f.anomalousIAMSCC.GetFinding().GetSourceProperties().GetProperties().GetSensitiveRoleGrant().GetMembers(),
+			ProjectID:       f.projectID(),
+			ExternalMembers: f.anomalousIAMSCC.Properties.SensitiveRoleGrant.Members,
 		}
 	}
 	return &revoke.Values{
-		ProjectID:       f.anomalousIAM.GetJsonPayload().GetEvidence()[0].GetSourceLogId().GetProjectId(),
+		ProjectID:       f.projectID(),
 		ExternalMembers: f.anomalousIAM.GetJsonPayload().GetProperties().GetSensitiveRoleGrant().GetMembers(),
 	}
 }
+
+// projectID returns the project of the first evidence of the finding.
+func (f *Finding) projectID() string {
+	if f.UseCSCC {
+		if e := f.anomalousIAMSCC.Evidence; len(e) > 0 {
+			return e[0].SourceLogID.ProjectID
+		}
+		return ""
+	}
+	if e := f.anomalousIAM.GetJsonPayload().GetEvidence(); len(e) > 0 {
+		return e[0].GetSourceLogId().GetProjectId()
+	}
+	return ""
+}
+
+// IAMRevokeGrants returns values for the IAM revoke grants automation.
+func (f *Finding) IAMRevokeGrants() *revokegrants.Values {
+	bindings := []revokegrants.Binding{}
+	for _, d := range f.bindingDeltas {
+		if d.Action != "ADD" {
+			continue
+		}
+		bindings = append(bindings, revokegrants.Binding{Role: d.Role, Member: d.Member})
+	}
+	return &revokegrants.Values{
+		ProjectID: f.projectID(),
+		Bindings:  bindings,
+	}
+}
+
+// RestoreIAMPolicy returns values for the restore IAM policy automation.
+func (f *Finding) RestoreIAMPolicy() *restorepolicy.Values {
+	// An unparseable event time leaves the zero time, so no snapshot will qualify for restore.
+	eventTime, _ := time.Parse(time.RFC3339, f.eventTime)
+	return &restorepolicy.Values{
+		ProjectID: f.projectID(),
+		EventTime: eventTime,
+	}
+}
@@ -2,8 +2,11 @@ package anomalousiam
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/restorepolicy"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/revokegrants"
 	"golang.org/x/xerrors"
 )
 
@@ -34,6 +37,27 @@ func TestReadFinding(t *testing.T) {
 				"createTime": "2019-11-22T18:34:36.688Z"
 			}
 		}`
+		sccAnomalousIAMCategoryOnly = `{
+			"notificationConfigName": "organizations/0000000000000/notificationConfigs/noticonf-active-001-id",
+			"finding": {
+				"name": "organizations/0000000000000/sources/0000000000000000000/findings/6a30ce604c11417995b1fa260753f3b5",
+				"parent": "organizations/0000000000000/sources/0000000000000000000",
+				"resourceName": "//cloudresourcemanager.googleapis.com/projects/000000000000",
+				"state": "ACTIVE",
+				"category": "Persistence: IAM Anomalous Grant",
+				"sourceProperties": {
+					"evidence": [{"sourceLogId": {"projectId": "onboarding-project"}}],
+					"properties": {
+						"sensitiveRoleGrant": {
+							"members": ["user:john.doe@example.com", "user:jane.doe@example.com"]
+						}
+					}
+				},
+				"securityMarks": {},
+				"eventTime": "2019-11-22T18:34:36.153Z",
+				"createTime": "2019-11-22T18:34:36.688Z"
+			}
+		}`
 		etdAnomalousIAM = `{
 			"jsonPayload": {
 				"properties": {
@@ -58,6 +82,7 @@ func TestReadFinding(t *testing.T) {
 	}{
 		{name: "read etd", externalMembers: []string{"user:john.doe@example.com", "user:jane.doe@example.com"}, projectID: "onboarding-project", bytes: []byte(etdAnomalousIAM), expectedError: nil, ruleName: "iam_anomalous_grant"},
 		{name: "read SCC", externalMembers: []string{"user:john.doe@example.com", "user:jane.doe@example.com"}, projectID: "onboarding-project", bytes: []byte(sccAnomalousIAM), expectedError: nil, ruleName: "iam_anomalous_grant"},
+		{name: "read SCC category only", externalMembers: []string{"user:john.doe@example.com", "user:jane.doe@example.com"}, projectID: "onboarding-project", bytes: []byte(sccAnomalousIAMCategoryOnly), expectedError: nil, ruleName: "iam_anomalous_grant"},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			r, err := New(tt.bytes)
@@ -82,3 +107,135 @@ func TestReadFinding(t *testing.T) {
 		})
 	}
 }
+
+func TestReadFindingRevokeGrants(t *testing.T) {
+	const (
+		sccAnomalousIAM = `{
+			"notificationConfigName": "organizations/0000000000000/notificationConfigs/noticonf-active-001-id",
+			"finding": {
+				"name": "organizations/0000000000000/sources/0000000000000000000/findings/6a30ce604c11417995b1fa260753f3b5",
+				"resourceName": "//cloudresourcemanager.googleapis.com/projects/000000000000",
+				"category": "Persistence: IAM Anomalous Grant",
+				"sourceProperties": {
+					"evidence": [{"sourceLogId": {"projectId": "onboarding-project"}}],
+					"properties": {
+						"sensitiveRoleGrant": {
+							"principalEmail": "admin@example.com",
+							"bindingDeltas": [
+								{"action": "ADD", "role": "roles/owner", "member": "user:john.doe@gmail.com"},
+								{"action": "REMOVE", "role": "roles/viewer", "member": "user:jane.doe@example.com"}
+							],
+							"members": ["user:john.doe@gmail.com"]
+						}
+					}
+				}
+			}
+		}`
+		etdAnomalousIAM = `{
+			"jsonPayload": {
+				"properties": {
+					"sensitiveRoleGrant": {
+						"bindingDeltas": [{"action": "ADD", "role": "roles/editor", "member": "user:john.doe@gmail.com"}],
+						"members": ["user:john.doe@gmail.com"]
+					}
+				},
+				"evidence": [{"sourceLogId": {"projectId": "onboarding-project"}}],
+				"detectionCategory": {
+					"ruleName": "iam_anomalous_grant"
+				}
+			}
+		}`
+	)
+	for _, tt := range []struct {
+		name     string
+		bytes    []byte
+		expected *revokegrants.Values
+	}{
+		{
+			name:  "read SCC",
+			bytes: []byte(sccAnomalousIAM),
+			expected: &revokegrants.Values{
+				ProjectID: "onboarding-project",
+				Bindings:  []revokegrants.Binding{{Role: "roles/owner", Member: "user:john.doe@gmail.com"}},
+			},
+		},
+		{
+			name:  "read etd",
+			bytes: []byte(etdAnomalousIAM),
+			expected: &revokegrants.Values{
+				ProjectID: "onboarding-project",
+				Bindings:  []revokegrants.Binding{{Role: "roles/editor", Member: "user:john.doe@gmail.com"}},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := New(tt.bytes)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(r.IAMRevokeGrants(), tt.expected); diff != "" {
+				t.Errorf("%s failed: diff:%s", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestReadFindingRestoreIAMPolicy(t *testing.T) {
+	const (
+		sccAnomalousIAM = `{
+			"finding": {
+				"name": "organizations/0000000000000/sources/0000000000000000000/findings/6a30ce604c11417995b1fa260753f3b5",
+				"resourceName": "//cloudresourcemanager.googleapis.com/projects/000000000000",
+				"category": "Persistence: IAM Anomalous Grant",
+				"sourceProperties": {
+					"detectionCategory": {
+						"ruleName": "iam_anomalous_grant"
+					},
+					"evidence": [{"sourceLogId": {"projectId": "onboarding-project"}}]
+				},
+				"eventTime": "2019-11-22T18:34:36.153Z"
+			}
+		}`
+		etdAnomalousIAM = `{
+			"jsonPayload": {
+				"evidence": [{"sourceLogId": {"projectId": "onboarding-project"}}],
+				"detectionCategory": {
+					"ruleName": "iam_anomalous_grant"
+				}
+			},
+			"timestamp": "2019-11-22T18:34:36Z"
+		}`
+	)
+	for _, tt := range []struct {
+		name     string
+		bytes    []byte
+		expected *restorepolicy.Values
+	}{
+		{
+			name:  "read SCC",
+			bytes: []byte(sccAnomalousIAM),
+			expected: &restorepolicy.Values{
+				ProjectID: "onboarding-project",
+				EventTime: time.Date(2019, 11, 22, 18, 34, 36, 153000000, time.UTC),
+			},
+		},
+		{
+			name:  "read etd",
+			bytes: []byte(etdAnomalousIAM),
+			expected: &restorepolicy.Values{
+				ProjectID: "onboarding-project",
+				EventTime: time.Date(2019, 11, 22, 18, 34, 36, 0, time.UTC),
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := New(tt.bytes)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(r.RestoreIAMPolicy(), tt.expected); diff != "" {
+				t.Errorf("%s failed: diff:%s", tt.name, diff)
+			}
+		})
+	}
+}
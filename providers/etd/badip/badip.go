@@ -18,11 +18,18 @@ package badip
 import (
 	"encoding/json"
 
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/billing/disablebilling"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/createsnapshot"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/quarantineinstance"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/secretmanager/rotatesecrets"
 	pb "github.com/googlecloudplatform/security-response-automation/compiled/etd/protos"
+	"github.com/googlecloudplatform/security-response-automation/findings"
 	"github.com/googlecloudplatform/security-response-automation/providers/etd"
 )
 
+// reader reads the Security Command Center notifications of bad IP findings.
+var reader = &findings.Reader{}
+
 // Name returns the rule name of the finding.
 func (f *Finding) Name(b []byte) string {
 	ff, err := New(b)
@@ -31,7 +38,7 @@ func (f *Finding) Name(b []byte) string {
 	}
 	name := ""
 	if ff.UseCSCC {
-		name = ff.BadIPCSCC.GetFinding().GetSourceProperties().GetDetectionCategory().GetRuleName()
+		name = ff.badIPSCC.Rule()
 	} else {
 		name = ff.badIP.GetJsonPayload().GetDetectionCategory().GetRuleName()
 	}
@@ -43,9 +50,16 @@ func (f *Finding) Name(b []byte) string {
 
 // Finding represents a bad IP finding.
 type Finding struct {
-	UseCSCC   bool
-	badIP     *pb.BadIP
-	BadIPCSCC *pb.BadIPSCC
+	UseCSCC  bool
+	badIP    *pb.BadIP
+	badIPSCC badIPFinding
+}
+
+// badIPFinding holds the fields of the Security Command Center finding used by this provider.
+type badIPFinding struct {
+	etd.Finding
+	ProjectID       string `finding:"sourceProperties.properties.network.project"`
+	InstanceDetails string `finding:"sourceProperties.properties.instanceDetails,optional"`
 }
 
 // New returns a new bad IP finding.
@@ -57,21 +71,37 @@ func New(b []byte) (*Finding, error) {
 	if f.badIP.GetJsonPayload().GetDetectionCategory().GetRuleName() != "" {
 		return &f, nil
 	}
-	if err := json.Unmarshal(b, &f.BadIPCSCC); err != nil {
+	if err := reader.Read(b, &f.badIPSCC); err != nil {
 		return nil, err
 	}
 	f.UseCSCC = true
 	return &f, nil
 }
 
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.badIPSCC.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.badIPSCC.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.badIPSCC.SecurityMarks
+}
+
 // CreateSnapshot returns values for the create snapshot automation.
 func (f *Finding) CreateSnapshot() *createsnapshot.Values {
 	if f.UseCSCC {
 		return &createsnapshot.Values{
-			ProjectID: f.BadIPCSCC.GetFinding().GetSourceProperties().GetProperties().GetNetwork().GetProject(),
-			RuleName:  f.BadIPCSCC.GetFinding().GetSourceProperties().GetDetectionCategory().GetRuleName(),
-			Instance:  etd.Instance(f.BadIPCSCC.GetFinding().GetSourceProperties().GetProperties().GetInstanceDetails()),
-			Zone:      etd.Zone(f.BadIPCSCC.GetFinding().GetSourceProperties().GetProperties().GetInstanceDetails()),
+			ProjectID: f.badIPSCC.ProjectID,
+			RuleName:  f.badIPSCC.Rule(),
+			Instance:  etd.Instance(f.badIPSCC.InstanceDetails),
+			Zone:      etd.Zone(f.badIPSCC.InstanceDetails),
+			FindingID: etd.FindingID(f.badIPSCC.Name),
 		}
 	}
 	return &createsnapshot.Values{
@@ -79,5 +109,40 @@ func (f *Finding) CreateSnapshot() *createsnapshot.Values {
 		RuleName:  f.badIP.GetJsonPayload().GetDetectionCategory().GetRuleName(),
 		Instance:  etd.Instance(f.badIP.GetJsonPayload().GetProperties().GetInstanceDetails()),
 		Zone:      etd.Zone(f.badIP.GetJsonPayload().GetProperties().GetInstanceDetails()),
+		FindingID: f.badIP.GetInsertId(),
+	}
+}
+
+// QuarantineInstance returns values for the quarantine instance automation.
+func (f *Finding) QuarantineInstance() *quarantineinstance.Values {
+	snapshot := f.CreateSnapshot()
+	return &quarantineinstance.Values{
+		ProjectID: snapshot.ProjectID,
+		Zone:      snapshot.Zone,
+		Instance:  snapshot.Instance,
+	}
+}
+
+// RotateSecrets returns values for the rotate secrets automation. The instance is the workload
+// whose secrets are rotated.
+func (f *Finding) RotateSecrets() *rotatesecrets.Values {
+	snapshot := f.CreateSnapshot()
+	return &rotatesecrets.Values{
+		ProjectID: snapshot.ProjectID,
+		Workload:  snapshot.Instance,
+	}
+}
+
+// DisableBilling returns values for the disable billing automation. The finding name is only set
+// for Security Command Center findings, which are marked once billing is disabled.
+func (f *Finding) DisableBilling() *disablebilling.Values {
+	if f.UseCSCC {
+		return &disablebilling.Values{
+			ProjectID:   f.badIPSCC.ProjectID,
+			FindingName: f.badIPSCC.Name,
+		}
+	}
+	return &disablebilling.Values{
+		ProjectID: f.badIP.GetJsonPayload().GetProperties().GetNetwork().GetProject(),
 	}
 }
@@ -16,22 +16,20 @@ package badip
 
 import (
 	"testing"
+
+	"github.com/googlecloudplatform/security-response-automation/findings/fixtures"
 )
 
 func TestBadIP(t *testing.T) {
 	const (
-		badIPSCC = `{
+		badIPSCCCategoryOnly = `{
 			"finding": {
 				"name": "organizations/0000000000000/sources/0000000000000000000/findings/6a30ce604c11417995b1fa260753f3b5",
 				"parent": "organizations/0000000000000/sources/0000000000000000000",
 				"resourceName": "//cloudresourcemanager.googleapis.com/projects/000000000000",
 				"state": "ACTIVE",
-				"category": "C2: Bad IP",
-				"externalUri": "https://console.cloud.google.com/home?project=test-project-15511551515",
+				"category": "Malware: Bad IP",
 				"sourceProperties": {
-					"detectionCategory": {
-						"ruleName": "bad_ip"
-					},
 					"properties": {
 						"instanceDetails": "/projects/test-project-15511551515/zones/us-central1-a/instances/bad-ip-caller",
 							"network": {
@@ -44,6 +42,26 @@ func TestBadIP(t *testing.T) {
 				"createTime": "2019-11-22T18:34:36.688Z"
 			}
 		}`
+		cryptominingSCC = `{
+			"finding": {
+				"name": "organizations/0000000000000/sources/0000000000000000000/findings/7b41df715d22528006c2gb371864g4c6",
+				"parent": "organizations/0000000000000/sources/0000000000000000000",
+				"resourceName": "//cloudresourcemanager.googleapis.com/projects/000000000000",
+				"state": "ACTIVE",
+				"category": "Malware: Cryptomining Bad IP",
+				"sourceProperties": {
+					"properties": {
+						"instanceDetails": "/projects/test-project-15511551515/zones/us-central1-a/instances/miner",
+							"network": {
+								"project": "test-project-15511551515"
+							}
+					}
+				},
+				"securityMarks": {},
+				"eventTime": "2019-11-22T18:34:36.153Z",
+				"createTime": "2019-11-22T18:34:36.688Z"
+			}
+		}`
 		badIPStackdriver = `{
 			"jsonPayload": {
 				"properties": {
@@ -67,9 +85,12 @@ func TestBadIP(t *testing.T) {
 		projectID string
 		instance  string
 		zone      string
+		findingID string
 	}{
 		{name: "bad_ip SD", finding: []byte(badIPStackdriver), ruleName: "bad_ip", projectID: "test-project-15511551515", instance: "bad-ip-caller", zone: "us-central1-a"},
-		{name: "bad_ip CSCC", finding: []byte(badIPSCC), ruleName: "bad_ip", projectID: "test-project-15511551515", instance: "bad-ip-caller", zone: "us-central1-a"},
+		{name: "bad_ip CSCC", finding: fixtures.Read(t, "bad_ip"), ruleName: "bad_ip", projectID: "test-project-15511551515", instance: "bad-ip-caller", zone: "us-central1-a", findingID: "6a30ce604c11417995b1fa260753f3b5"},
+		{name: "bad_ip CSCC category only", finding: []byte(badIPSCCCategoryOnly), ruleName: "bad_ip", projectID: "test-project-15511551515", instance: "bad-ip-caller", zone: "us-central1-a", findingID: "6a30ce604c11417995b1fa260753f3b5"},
+		{name: "cryptomining CSCC", finding: []byte(cryptominingSCC), ruleName: "bad_ip", projectID: "test-project-15511551515", instance: "miner", zone: "us-central1-a", findingID: "7b41df715d22528006c2gb371864g4c6"},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			f, err := New(tt.finding)
@@ -90,6 +111,17 @@ func TestBadIP(t *testing.T) {
 				if values.Zone != tt.zone {
 					t.Errorf("%s failed: got:%q want:%q", tt.name, values.Zone, tt.zone)
 				}
+				if values.FindingID != tt.findingID {
+					t.Errorf("%s failed: got:%q want:%q", tt.name, values.FindingID, tt.findingID)
+				}
+				quarantine := f.QuarantineInstance()
+				if quarantine.ProjectID != tt.projectID || quarantine.Instance != tt.instance || quarantine.Zone != tt.zone {
+					t.Errorf("%s failed: got:%+v", tt.name, quarantine)
+				}
+				rotate := f.RotateSecrets()
+				if rotate.ProjectID != tt.projectID || rotate.Workload != tt.instance {
+					t.Errorf("%s failed: got:%+v", tt.name, rotate)
+				}
 
 			}
 		})
@@ -1,6 +1,11 @@
 package etd
 
-import "regexp"
+import (
+	"regexp"
+	"strings"
+
+	"github.com/googlecloudplatform/security-response-automation/findings"
+)
 
 // Copyright 2019 Google LLC
 //
@@ -23,6 +28,42 @@ var (
 	extractZone = regexp.MustCompile(`/zones/([^/]*)`)
 )
 
+// categories maps Security Command Center finding categories to Event Threat Detection rule names.
+// Notifications do not always include the detection category so the rule name is derived from
+// the finding's category instead.
+var categories = map[string]string{
+	"persistence: iam anomalous grant":          "iam_anomalous_grant",
+	"malware: bad ip":                           "bad_ip",
+	"c2: bad ip":                                "bad_ip",
+	"malware: cryptomining bad ip":              "bad_ip",
+	"brute force: ssh":                          "ssh_brute_force",
+	"brute_force: ssh brute force":              "ssh_brute_force",
+	"persistence: firewall rule created":        "firewall_rule_created",
+	"initial access: account disabled hijacked": "account_disabled_hijacked",
+	"initial access: disabled password leak":    "account_disabled_password_leak",
+}
+
+// Finding holds the fields common to the Security Command Center notifications of Event Threat
+// Detection findings, embed it in the values read by a findings.Reader.
+type Finding struct {
+	findings.Finding
+	DetectionRule string `finding:"sourceProperties.detectionCategory.ruleName,optional"`
+}
+
+// Rule returns the rule name of the finding.
+func (f *Finding) Rule() string {
+	return RuleName(f.DetectionRule, f.Category)
+}
+
+// RuleName returns the rule name of an Event Threat Detection finding. If the rule name is not
+// present the Security Command Center category is used to look it up.
+func RuleName(ruleName, category string) string {
+	if ruleName != "" {
+		return ruleName
+	}
+	return categories[strings.ToLower(strings.TrimSpace(category))]
+}
+
 // Instance returns the instance name from the source instance string.
 func Instance(resource string) string {
 	i := extractInstance.FindStringSubmatch(resource)
@@ -32,6 +73,11 @@ func Instance(resource string) string {
 	return i[1]
 }
 
+// FindingID returns the ID portion of a Security Command Center finding name.
+func FindingID(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
 // Zone returns the zone from the source instance string.
 func Zone(resource string) string {
 	i := extractZone.FindStringSubmatch(resource)
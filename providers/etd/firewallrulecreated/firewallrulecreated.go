@@ -0,0 +1,104 @@
+// Package firewallrulecreated represents Event Threat Detection findings about firewall rules created by a compromised identity.
+package firewallrulecreated
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"strings"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/deletefirewallrules"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+	"github.com/googlecloudplatform/security-response-automation/providers/etd"
+)
+
+// reader reads the findings of firewall rules created by a compromised identity.
+var reader = &findings.Reader{}
+
+// Finding represents this finding.
+type Finding struct {
+	firewall firewallRuleCreated
+}
+
+// firewallRuleCreated holds the fields of the finding used by this provider.
+type firewallRuleCreated struct {
+	etd.Finding
+	Evidence []struct {
+		SourceLogID struct {
+			ProjectID string `json:"projectId"`
+		} `json:"sourceLogId"`
+	} `finding:"sourceProperties.evidence,optional"`
+	FirewallRules     []string `finding:"sourceProperties.properties.firewallRules,optional"`
+	IncidentStartTime string   `finding:"sourceProperties.properties.incidentStartTime,optional"`
+}
+
+// Name returns the rule name of the finding.
+func (f *Finding) Name(b []byte) string {
+	ff, err := New(b)
+	if err != nil {
+		return ""
+	}
+	name := ff.firewall.Rule()
+	if name != "firewall_rule_created" {
+		return ""
+	}
+	return name
+}
+
+// New returns a new finding.
+func New(b []byte) (*Finding, error) {
+	var f Finding
+	if err := reader.Read(b, &f.firewall); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.firewall.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.firewall.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.firewall.SecurityMarks
+}
+
+// DeleteFirewallRules returns values for the delete firewall rules automation.
+//
+// The incident start is taken from the finding's properties, falling back to its event time.
+func (f *Finding) DeleteFirewallRules() *deletefirewallrules.Values {
+	finding := f.firewall
+	values := &deletefirewallrules.Values{FirewallRules: []string{}}
+	if len(finding.Evidence) > 0 {
+		values.ProjectID = finding.Evidence[0].SourceLogID.ProjectID
+	}
+	for _, r := range finding.FirewallRules {
+		values.FirewallRules = append(values.FirewallRules, r[strings.LastIndex(r, "/")+1:])
+	}
+	start := finding.IncidentStartTime
+	if start == "" {
+		start = finding.EventTime
+	}
+	if t, err := time.Parse(time.RFC3339, start); err == nil {
+		values.IncidentStart = t
+	}
+	return values
+}
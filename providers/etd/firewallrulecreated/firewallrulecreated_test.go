@@ -0,0 +1,105 @@
+package firewallrulecreated
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/deletefirewallrules"
+)
+
+func TestReadFinding(t *testing.T) {
+	const (
+		firewallRuleCreated = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/f1",
+				"resourceName": "//cloudresourcemanager.googleapis.com/projects/72231686866",
+				"category": "Persistence: Firewall Rule Created",
+				"eventTime": "2020-06-10T17:48:49.358Z",
+				"sourceProperties": {
+					"evidence": [{"sourceLogId": {"projectId": "test-project"}}],
+					"properties": {
+						"firewallRules": ["projects/test-project/global/firewalls/attacker-rule", "other-rule"],
+						"incidentStartTime": "2020-06-10T17:00:00Z"
+					}
+				}
+			}
+		}`
+		withoutIncidentStart = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/f2",
+				"resourceName": "//cloudresourcemanager.googleapis.com/projects/72231686866",
+				"category": "Persistence: Firewall Rule Created",
+				"eventTime": "2020-06-10T17:48:49Z",
+				"sourceProperties": {
+					"detectionCategory": {"ruleName": "firewall_rule_created"},
+					"evidence": [{"sourceLogId": {"projectId": "test-project"}}],
+					"properties": {"firewallRules": ["attacker-rule"]}
+				}
+			}
+		}`
+		otherFinding = `{
+			"finding": {
+				"category": "Persistence: IAM Anomalous Grant"
+			}
+		}`
+	)
+	for _, tt := range []struct {
+		name, ruleName string
+		bytes          []byte
+		values         *deletefirewallrules.Values
+	}{
+		{
+			name:     "firewall rule created",
+			ruleName: "firewall_rule_created",
+			bytes:    []byte(firewallRuleCreated),
+			values: &deletefirewallrules.Values{
+				ProjectID:     "test-project",
+				FirewallRules: []string{"attacker-rule", "other-rule"},
+				IncidentStart: time.Date(2020, 6, 10, 17, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "event time as incident start",
+			ruleName: "firewall_rule_created",
+			bytes:    []byte(withoutIncidentStart),
+			values: &deletefirewallrules.Values{
+				ProjectID:     "test-project",
+				FirewallRules: []string{"attacker-rule"},
+				IncidentStart: time.Date(2020, 6, 10, 17, 48, 49, 0, time.UTC),
+			},
+		},
+		{name: "other finding", ruleName: "", bytes: []byte(otherFinding)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Finding{}
+			if name := f.Name(tt.bytes); name != tt.ruleName {
+				t.Errorf("%s failed: got:%q want:%q", tt.name, name, tt.ruleName)
+			}
+			if tt.values == nil {
+				return
+			}
+			r, err := New(tt.bytes)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(r.DeleteFirewallRules(), tt.values); diff != "" {
+				t.Errorf("%s failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
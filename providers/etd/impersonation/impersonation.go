@@ -0,0 +1,116 @@
+package impersonation
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"strings"
+
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/removeimpersonation"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+)
+
+// categories contains the service account impersonation categories of Event Threat Detection findings.
+var categories = map[string]bool{
+	"discovery: service account self-investigation":                                           true,
+	"privilege escalation: anomalous impersonation of service account for admin activity":     true,
+	"privilege escalation: anomalous impersonation of service account for data access":        true,
+	"privilege escalation: anomalous multistep service account delegation for admin activity": true,
+	"privilege escalation: anomalous multistep service account delegation for data access":    true,
+	"privilege escalation: anomalous service account impersonator for admin activity":         true,
+	"privilege escalation: anomalous service account impersonator for data access":            true,
+}
+
+// reader reads the findings of service account impersonation.
+var reader = &findings.Reader{}
+
+// Finding represents a service account impersonation finding.
+type Finding struct {
+	impersonation impersonation
+}
+
+// impersonation holds the fields of the finding used by this provider.
+type impersonation struct {
+	findings.Finding
+	PrincipalEmail               string `finding:"access.principalEmail,optional"`
+	ServiceAccountDelegationInfo []struct {
+		PrincipalEmail string `json:"principalEmail"`
+	} `finding:"access.serviceAccountDelegationInfo,optional"`
+	Evidence []struct {
+		SourceLogID struct {
+			ProjectID string `json:"projectId"`
+		} `json:"sourceLogId"`
+	} `finding:"sourceProperties.evidence,optional"`
+}
+
+// Name returns the rule name of the finding.
+func (f *Finding) Name(b []byte) string {
+	ff, err := New(b)
+	if err != nil {
+		return ""
+	}
+	if !categories[strings.ToLower(strings.TrimSpace(ff.impersonation.Category))] {
+		return ""
+	}
+	return "service_account_impersonation"
+}
+
+// New returns a new finding.
+func New(b []byte) (*Finding, error) {
+	var f Finding
+	if err := reader.Read(b, &f.impersonation); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.impersonation.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.impersonation.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.impersonation.SecurityMarks
+}
+
+// RemoveImpersonation returns values for the remove impersonation automation.
+//
+// The first entry of the delegation chain is the principal that started the impersonation, the
+// other entries and the caller are the service accounts it impersonated. Without a delegation chain
+// no principal is returned.
+func (f *Finding) RemoveImpersonation() *removeimpersonation.Values {
+	finding := f.impersonation
+	values := &removeimpersonation.Values{ServiceAccounts: []string{}}
+	if len(finding.Evidence) > 0 {
+		values.ProjectID = finding.Evidence[0].SourceLogID.ProjectID
+	}
+	chain := finding.ServiceAccountDelegationInfo
+	if len(chain) == 0 {
+		return values
+	}
+	values.Principal = chain[0].PrincipalEmail
+	for _, d := range chain[1:] {
+		values.ServiceAccounts = append(values.ServiceAccounts, d.PrincipalEmail)
+	}
+	if finding.PrincipalEmail != "" {
+		values.ServiceAccounts = append(values.ServiceAccounts, finding.PrincipalEmail)
+	}
+	return values
+}
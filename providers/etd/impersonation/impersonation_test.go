@@ -0,0 +1,95 @@
+package impersonation
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/removeimpersonation"
+)
+
+const impersonationFinding = `{
+	"notificationConfigName": "organizations/154584661726/notificationConfigs/sampleConfigId",
+	"finding": {
+		"name": "organizations/154584661726/sources/2673592633662526977/findings/3e5f7a9b1c3d4e5f6a7b8c9d0e1f2a3b",
+		"parent": "organizations/154584661726/sources/2673592633662526977",
+		"resourceName": "//cloudresourcemanager.googleapis.com/projects/72231686866",
+		"state": "ACTIVE",
+		"category": "Privilege Escalation: Anomalous Multistep Service Account Delegation for Admin Activity",
+		"access": {
+			"principalEmail": "admin@test-project.iam.gserviceaccount.com",
+			"serviceAccountDelegationInfo": [
+				{"principalEmail": "attacker@external.com"},
+				{"principalEmail": "deployer@test-project.iam.gserviceaccount.com"}
+			]
+		},
+		"sourceProperties": {
+			"evidence": [{"sourceLogId": {"projectId": "test-project"}}]
+		},
+		"eventTime": "2020-06-10T17:48:49.358Z",
+		"createTime": "2020-06-10T17:48:50.596Z"
+	}
+}`
+
+func TestReadFinding(t *testing.T) {
+	for _, tt := range []struct {
+		name, ruleName string
+		bytes          []byte
+		values         *removeimpersonation.Values
+	}{
+		{
+			name:     "multistep delegation",
+			ruleName: "service_account_impersonation",
+			bytes:    []byte(impersonationFinding),
+			values: &removeimpersonation.Values{
+				ProjectID:       "test-project",
+				Principal:       "attacker@external.com",
+				ServiceAccounts: []string{"deployer@test-project.iam.gserviceaccount.com", "admin@test-project.iam.gserviceaccount.com"},
+			},
+		},
+		{
+			name:     "self investigation without delegation",
+			ruleName: "service_account_impersonation",
+			bytes: []byte(strings.Replace(strings.Replace(impersonationFinding,
+				"Privilege Escalation: Anomalous Multistep Service Account Delegation for Admin Activity", "Discovery: Service Account Self-Investigation", 1),
+				`"serviceAccountDelegationInfo"`, `"unused"`, 1)),
+			values: &removeimpersonation.Values{ProjectID: "test-project", ServiceAccounts: []string{}},
+		},
+		{
+			name:     "other category",
+			ruleName: "",
+			bytes:    []byte(strings.Replace(impersonationFinding, "Privilege Escalation: Anomalous Multistep Service Account Delegation for Admin Activity", "Persistence: IAM Anomalous Grant", 1)),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Finding{}
+			if name := f.Name(tt.bytes); name != tt.ruleName {
+				t.Errorf("%s failed: got:%q want:%q", tt.name, name, tt.ruleName)
+			}
+			if tt.values == nil {
+				return
+			}
+			r, err := New(tt.bytes)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(r.RemoveImpersonation(), tt.values); diff != "" {
+				t.Errorf("%s failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package roguejob
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/dataprocessing/stoproguejob"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+)
+
+var (
+	// reader reads the cryptomining findings of Event Threat Detection.
+	reader = &findings.Reader{}
+	// dataprocCluster extracts the project, region and cluster of a Dataproc cluster.
+	dataprocCluster = regexp.MustCompile(`^//dataproc\.googleapis\.com/projects/([^/]+)/regions/([^/]+)/clusters/([^/]+)$`)
+	// dataprocJob extracts the project, region and job of a Dataproc job.
+	dataprocJob = regexp.MustCompile(`^//dataproc\.googleapis\.com/projects/([^/]+)/regions/([^/]+)/jobs/([^/]+)$`)
+	// dataflowJob extracts the project, location and job of a Dataflow job.
+	dataflowJob = regexp.MustCompile(`^//dataflow\.googleapis\.com/projects/([^/]+)/locations/([^/]+)/jobs/([^/]+)$`)
+)
+
+// categories contains the cryptomining categories of Event Threat Detection findings.
+var categories = map[string]bool{
+	"malware: cryptomining bad ip":                true,
+	"malware: cryptomining bad domain":            true,
+	"execution: cryptocurrency mining hash match": true,
+	"execution: cryptocurrency mining yara rule":  true,
+}
+
+// Finding represents a cryptomining finding against a Dataproc or Dataflow resource.
+type Finding struct {
+	rogueJob findings.Finding
+}
+
+// Name returns the rule name of the finding.
+func (f *Finding) Name(b []byte) string {
+	ff, err := New(b)
+	if err != nil {
+		return ""
+	}
+	if !categories[strings.ToLower(strings.TrimSpace(ff.rogueJob.Category))] {
+		return ""
+	}
+	resource := ff.rogueJob.ResourceName
+	if !dataprocCluster.MatchString(resource) && !dataprocJob.MatchString(resource) && !dataflowJob.MatchString(resource) {
+		return ""
+	}
+	return "rogue_job"
+}
+
+// New returns a new finding.
+func New(b []byte) (*Finding, error) {
+	var f Finding
+	if err := reader.Read(b, &f.rogueJob); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.rogueJob.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.rogueJob.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.rogueJob.SecurityMarks
+}
+
+// StopRogueJob returns values for the stop rogue job automation.
+func (f *Finding) StopRogueJob() *stoproguejob.Values {
+	resource := f.rogueJob.ResourceName
+	if m := dataprocCluster.FindStringSubmatch(resource); m != nil {
+		return &stoproguejob.Values{ProjectID: m[1], Region: m[2], Cluster: m[3]}
+	}
+	if m := dataprocJob.FindStringSubmatch(resource); m != nil {
+		return &stoproguejob.Values{ProjectID: m[1], Region: m[2], DataprocJob: m[3]}
+	}
+	if m := dataflowJob.FindStringSubmatch(resource); m != nil {
+		return &stoproguejob.Values{ProjectID: m[1], Region: m[2], DataflowJob: m[3]}
+	}
+	return &stoproguejob.Values{}
+}
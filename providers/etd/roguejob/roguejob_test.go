@@ -0,0 +1,91 @@
+package roguejob
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/dataprocessing/stoproguejob"
+)
+
+const cryptominingFinding = `{
+	"notificationConfigName": "organizations/154584661726/notificationConfigs/sampleConfigId",
+	"finding": {
+		"name": "organizations/154584661726/sources/2673592633662526977/findings/7c1d3e5f7a9b4c2d8e0f1a2b3c4d5e6f",
+		"parent": "organizations/154584661726/sources/2673592633662526977",
+		"resourceName": "//dataproc.googleapis.com/projects/test-project/regions/us-central1/clusters/miner-cluster",
+		"state": "ACTIVE",
+		"category": "Malware: Cryptomining Bad Domain",
+		"eventTime": "2020-06-10T17:48:49.358Z",
+		"createTime": "2020-06-10T17:48:50.596Z"
+	}
+}`
+
+func TestReadFinding(t *testing.T) {
+	const clusterResource = "//dataproc.googleapis.com/projects/test-project/regions/us-central1/clusters/miner-cluster"
+	for _, tt := range []struct {
+		name, ruleName string
+		bytes          []byte
+		values         *stoproguejob.Values
+	}{
+		{
+			name:     "dataproc cluster",
+			ruleName: "rogue_job",
+			bytes:    []byte(cryptominingFinding),
+			values:   &stoproguejob.Values{ProjectID: "test-project", Region: "us-central1", Cluster: "miner-cluster"},
+		},
+		{
+			name:     "dataproc job",
+			ruleName: "rogue_job",
+			bytes:    []byte(strings.Replace(cryptominingFinding, clusterResource, "//dataproc.googleapis.com/projects/test-project/regions/us-central1/jobs/job-1234", 1)),
+			values:   &stoproguejob.Values{ProjectID: "test-project", Region: "us-central1", DataprocJob: "job-1234"},
+		},
+		{
+			name:     "dataflow job",
+			ruleName: "rogue_job",
+			bytes:    []byte(strings.Replace(cryptominingFinding, clusterResource, "//dataflow.googleapis.com/projects/test-project/locations/us-east1/jobs/2020-06-10_10_00_00-1234", 1)),
+			values:   &stoproguejob.Values{ProjectID: "test-project", Region: "us-east1", DataflowJob: "2020-06-10_10_00_00-1234"},
+		},
+		{
+			name:     "compute instance",
+			ruleName: "",
+			bytes:    []byte(strings.Replace(cryptominingFinding, clusterResource, "//compute.googleapis.com/projects/test-project/zones/us-central1-a/instances/miner", 1)),
+		},
+		{
+			name:     "not a cryptomining finding",
+			ruleName: "",
+			bytes:    []byte(strings.Replace(cryptominingFinding, "Malware: Cryptomining Bad Domain", "Malware: Bad Domain", 1)),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Finding{}
+			if name := f.Name(tt.bytes); name != tt.ruleName {
+				t.Errorf("%s failed: got:%q want:%q", tt.name, name, tt.ruleName)
+			}
+			if tt.values == nil {
+				return
+			}
+			r, err := New(tt.bytes)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(r.StopRogueJob(), tt.values); diff != "" {
+				t.Errorf("%s failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
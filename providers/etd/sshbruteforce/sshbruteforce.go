@@ -5,13 +5,29 @@ import (
 
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/openfirewall"
 	pb "github.com/googlecloudplatform/security-response-automation/compiled/etd/protos"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+	"github.com/googlecloudplatform/security-response-automation/providers/etd"
 )
 
+// reader reads the Security Command Center notifications of SSH brute force findings.
+var reader = &findings.Reader{}
+
 // Finding represents this finding.
 type Finding struct {
 	UseCSCC          bool
 	sshBruteForce    *pb.SshBruteForce
-	sshBruteForceSCC *pb.SshBruteForceSCC
+	sshBruteForceSCC sshBruteForceFinding
+}
+
+// sshBruteForceFinding holds the fields of the Security Command Center finding used by this provider.
+type sshBruteForceFinding struct {
+	etd.Finding
+	ProjectID     string `finding:"sourceProperties.properties.project_id"`
+	Instance      string `finding:"sourceProperties.properties.instance_id,optional"`
+	Zone          string `finding:"sourceProperties.properties.zone,optional"`
+	LoginAttempts []struct {
+		SourceIP string `json:"sourceIp"`
+	} `finding:"sourceProperties.properties.loginAttempts,optional"`
 }
 
 // Name returns the rule name of the finding.
@@ -22,7 +38,7 @@ func (f *Finding) Name(b []byte) string {
 	}
 	name := ""
 	if ff.UseCSCC {
-		name = ff.sshBruteForceSCC.GetFinding().GetSourceProperties().GetDetectionCategory().GetRuleName()
+		name = ff.sshBruteForceSCC.Rule()
 	} else {
 		name = ff.sshBruteForce.GetJsonPayload().GetDetectionCategory().GetRuleName()
 	}
@@ -41,13 +57,28 @@ func New(b []byte) (*Finding, error) {
 	if f.sshBruteForce.GetJsonPayload().GetDetectionCategory().GetRuleName() != "" {
 		return &f, nil
 	}
-	if err := json.Unmarshal(b, &f.sshBruteForceSCC); err != nil {
+	if err := reader.Read(b, &f.sshBruteForceSCC); err != nil {
 		return nil, err
 	}
 	f.UseCSCC = true
 	return &f, nil
 }
 
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.sshBruteForceSCC.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.sshBruteForceSCC.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.sshBruteForceSCC.SecurityMarks
+}
+
 // sourceIPRanges will return a slice of IP ranges from an SSH brute force.
 func sourceIPRanges(finding *pb.SshBruteForce) []string {
 	ranges := []string{}
@@ -58,11 +89,10 @@ func sourceIPRanges(finding *pb.SshBruteForce) []string {
 	return ranges
 }
 
-func sourceIPRangesSCC(finding *pb.SshBruteForceSCC) []string {
+func sourceIPRangesSCC(finding sshBruteForceFinding) []string {
 	ranges := []string{}
-	attempts := finding.GetFinding().GetSourceProperties().GetProperties().GetLoginAttempts()
-	for _, attempt := range attempts {
-		ranges = append(ranges, attempt.GetSourceIp()+"/32")
+	for _, attempt := range finding.LoginAttempts {
+		ranges = append(ranges, attempt.SourceIP+"/32")
 	}
 	return ranges
 }
@@ -71,12 +101,16 @@ func sourceIPRangesSCC(finding *pb.SshBruteForceSCC) []string {
 func (f *Finding) OpenFirewall() *openfirewall.Values {
 	if f.UseCSCC {
 		return &openfirewall.Values{
-			ProjectID:    f.sshBruteForceSCC.GetFinding().GetSourceProperties().GetProperties().GetProjectId(),
+			ProjectID:    f.sshBruteForceSCC.ProjectID,
 			SourceRanges: sourceIPRangesSCC(f.sshBruteForceSCC),
+			Instance:     f.sshBruteForceSCC.Instance,
+			Zone:         f.sshBruteForceSCC.Zone,
 		}
 	}
 	return &openfirewall.Values{
 		ProjectID:    f.sshBruteForce.GetJsonPayload().GetProperties().GetProjectId(),
 		SourceRanges: sourceIPRanges(f.sshBruteForce),
+		Instance:     f.sshBruteForce.GetJsonPayload().GetProperties().GetInstanceId(),
+		Zone:         f.sshBruteForce.GetJsonPayload().GetProperties().GetZone(),
 	}
 }
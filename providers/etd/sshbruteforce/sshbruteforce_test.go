@@ -4,48 +4,18 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/findings/fixtures"
 	"golang.org/x/xerrors"
 )
 
 func TestReadFinding(t *testing.T) {
 	const (
-		sccSSHBruteForceFinding = `{
-			"notificationConfigName": "organizations/0000000000000/notificationConfigs/noticonf-active-001-id",
-			"finding": {
-				"name": "organizations/0000000000000/sources/0000000000000000000/findings/6a30ce604c11417995b1fa260753f3b5",
-				"parent": "organizations/0000000000000/sources/0000000000000000000",
-				"resourceName": "//cloudresourcemanager.googleapis.com/projects/000000000000",
-				"state": "ACTIVE",
-				"category": "Brute_force: SSH Brute Force",
-				"externalUri": "https://console.cloud.google.com/home?project=onboarding-project",
-				"sourceProperties": {
-					"detectionCategory": {
-						"ruleName": "ssh_brute_force"
-					},
-					"properties": {
-						"project_id": "onboarding-project",
-						"loginAttempts": [{
-							"authResult": "FAIL",
-							"sourceIp": "10.200.0.2",
-							"userName": "okokok",
-							"vmName": "ssh-password-auth-debian-9"
-							}, {
-							"authResult": "SUCCESS",
-							"sourceIp": "10.200.0.3",
-							"userName": "okokok",
-							"vmName": "ssh-password-auth-debian-9"
-							}]
-					}
-				},
-				"securityMarks": {},
-				"eventTime": "2019-11-22T18:34:36.153Z",
-				"createTime": "2019-11-22T18:34:36.688Z"
-			}
-		}`
 		etdSSHBruteForceFinding = `{
 		"jsonPayload": {
 			"properties": {
 				"project_id": "onboarding-project",
+				"instance_id": "ssh-password-auth-debian-9",
+				"zone": "us-central1-a",
 				"loginAttempts": [{
 					"authResult": "FAIL",
 					"sourceIp": "10.200.0.2",
@@ -73,7 +43,7 @@ func TestReadFinding(t *testing.T) {
 		ruleName                    string
 	}{
 		{name: "read etd", ranges: []string{"10.200.0.2/32", "10.200.0.3/32"}, projectID: "onboarding-project", firewallID: "", bytes: []byte(etdSSHBruteForceFinding), expectedError: nil, ruleName: "ssh_brute_force"},
-		{name: "read SCC", ranges: []string{"10.200.0.2/32", "10.200.0.3/32"}, projectID: "onboarding-project", firewallID: "", bytes: []byte(sccSSHBruteForceFinding), expectedError: nil, ruleName: "ssh_brute_force"},
+		{name: "read SCC", ranges: []string{"10.200.0.2/32", "10.200.0.3/32"}, projectID: "onboarding-project", firewallID: "", bytes: fixtures.Read(t, "ssh_brute_force"), expectedError: nil, ruleName: "ssh_brute_force"},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			r, err := New(tt.bytes)
@@ -97,6 +67,9 @@ func TestReadFinding(t *testing.T) {
 				if values.ProjectID != tt.projectID {
 					t.Errorf("%s failed: got:%q want:%q", tt.name, values.ProjectID, tt.projectID)
 				}
+				if values.Instance != "ssh-password-auth-debian-9" || values.Zone != "us-central1-a" {
+					t.Errorf("%s failed: got instance:%q zone:%q", tt.name, values.Instance, values.Zone)
+				}
 			}
 		})
 	}
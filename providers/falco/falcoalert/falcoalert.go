@@ -0,0 +1,214 @@
+// Package falcoalert represents the alerts Falco sends to its webhook, normalized into Security
+// Command Center notifications of findings on the pods of GKE clusters.
+package falcoalert
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gke/deletepod"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gke/drainnode"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+	"github.com/googlecloudplatform/security-response-automation/providers/ctd"
+	"github.com/pkg/errors"
+)
+
+// source is the parent of the findings normalized from Falco alerts, which aren't in Security
+// Command Center.
+const source = "falco"
+
+var (
+	// reader reads the findings normalized from Falco alerts.
+	reader = &findings.Reader{}
+	// validCluster matches the GKE clusters Falco alerts are sent from, such as
+	// "projects/p/locations/us-central1/clusters/c".
+	validCluster = regexp.MustCompile(`^projects/[^/]+/(zones|locations)/[^/]+/clusters/[^/]+$`)
+	// nonAlphanumeric matches the characters of Falco rules replaced in rule names.
+	nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// severities maps the priorities of Falco alerts to the severities of findings.
+var severities = map[string]string{
+	"emergency":     "CRITICAL",
+	"alert":         "CRITICAL",
+	"critical":      "CRITICAL",
+	"error":         "HIGH",
+	"warning":       "MEDIUM",
+	"notice":        "LOW",
+	"informational": "LOW",
+	"info":          "LOW",
+	"debug":         "LOW",
+}
+
+// alert is the alert Falco sends to its webhook.
+type alert struct {
+	Output   string `json:"output"`
+	Priority string `json:"priority"`
+	Rule     string `json:"rule"`
+	Time     string `json:"time"`
+	Hostname string `json:"hostname"`
+	Fields   struct {
+		Namespace  string `json:"k8s.ns.name"`
+		Pod        string `json:"k8s.pod.name"`
+		Repository string `json:"container.image.repository"`
+		Tag        string `json:"container.image.tag"`
+	} `json:"output_fields"`
+}
+
+// Finding represents this finding.
+type Finding struct {
+	falco falcoValues
+}
+
+// falcoValues holds the fields of the normalized finding used by this provider.
+type falcoValues struct {
+	findings.Finding
+	Parent         string `finding:"parent"`
+	VMInstanceName string `finding:"sourceProperties.VM_Instance_Name,optional"`
+}
+
+// falcoFinding is the Security Command Center notification a Falco alert is normalized into.
+type falcoFinding struct {
+	Finding struct {
+		Name             string `json:"name"`
+		Parent           string `json:"parent"`
+		ResourceName     string `json:"resourceName"`
+		State            string `json:"state"`
+		Category         string `json:"category"`
+		EventTime        string `json:"eventTime"`
+		Severity         string `json:"severity"`
+		SourceProperties struct {
+			Priority          string `json:"Priority"`
+			Output            string `json:"Output"`
+			ProjectID         string `json:"ProjectId"`
+			VMInstanceName    string `json:"VM_Instance_Name"`
+			PodNamespace      string `json:"Pod_Namespace"`
+			PodName           string `json:"Pod_Name"`
+			ContainerImageURI string `json:"Container_Image_Uri"`
+		} `json:"sourceProperties"`
+	} `json:"finding"`
+}
+
+// Normalize returns the Security Command Center notification of the Falco alert, raised on the
+// pod of the GKE cluster it was sent from, such as "projects/p/locations/l/clusters/c". The name
+// of the finding is derived from the cluster and alert, so alerts sent again are routed once.
+func Normalize(b []byte, clusterName string) ([]byte, error) {
+	if !validCluster.MatchString(clusterName) {
+		return nil, errors.Errorf("invalid cluster %q", clusterName)
+	}
+	var a alert
+	if err := json.Unmarshal(b, &a); err != nil {
+		return nil, errors.Wrap(err, "invalid falco alert")
+	}
+	if a.Rule == "" || a.Time == "" {
+		return nil, errors.New("falco alert has no rule or time")
+	}
+	sum := sha256.Sum256(append([]byte(clusterName+"\n"), b...))
+	var f falcoFinding
+	f.Finding.Name = source + "/alerts/" + hex.EncodeToString(sum[:16])
+	f.Finding.Parent = source
+	f.Finding.ResourceName = "//container.googleapis.com/" + clusterName
+	if a.Fields.Pod != "" {
+		f.Finding.ResourceName += "/k8s/namespaces/" + a.Fields.Namespace + "/pods/" + a.Fields.Pod
+	}
+	f.Finding.State = "ACTIVE"
+	f.Finding.Category = a.Rule
+	f.Finding.EventTime = a.Time
+	f.Finding.Severity = severities[strings.ToLower(a.Priority)]
+	properties := &f.Finding.SourceProperties
+	properties.Priority = a.Priority
+	properties.Output = a.Output
+	properties.ProjectID = ctd.ProjectID(f.Finding.ResourceName)
+	properties.VMInstanceName = a.Hostname
+	properties.PodNamespace = a.Fields.Namespace
+	properties.PodName = a.Fields.Pod
+	if a.Fields.Repository != "" && a.Fields.Tag != "" {
+		properties.ContainerImageURI = a.Fields.Repository + ":" + a.Fields.Tag
+	} else {
+		properties.ContainerImageURI = a.Fields.Repository
+	}
+	return json.Marshal(&f)
+}
+
+// RuleName returns the rule name of findings of the Falco rule, such as
+// "falco_terminal_shell_in_container" for "Terminal shell in container".
+func RuleName(rule string) string {
+	name := strings.Trim(nonAlphanumeric.ReplaceAllString(strings.ToLower(rule), "_"), "_")
+	if name == "" {
+		return ""
+	}
+	return source + "_" + name
+}
+
+// Name returns the rule name of the finding.
+func (f *Finding) Name(b []byte) string {
+	ff, err := New(b)
+	if err != nil {
+		return ""
+	}
+	return RuleName(ff.falco.Category)
+}
+
+// New returns a new finding.
+func New(b []byte) (*Finding, error) {
+	var f Finding
+	if err := reader.Read(b, &f.falco); err != nil {
+		return nil, err
+	}
+	if f.falco.Parent != source {
+		return nil, errors.Wrap(findings.ErrUnsupportedFinding, "not a falco finding")
+	}
+	return &f, nil
+}
+
+// FindingName returns the name of the finding, derived from the Falco alert.
+func (f *Finding) FindingName() string {
+	return f.falco.Name
+}
+
+// EventTime returns the time of the Falco alert.
+func (f *Finding) EventTime() string {
+	return f.falco.EventTime
+}
+
+// DrainNode returns values for the drain node automation. The node is the host Falco reported
+// the alert from.
+func (f *Finding) DrainNode() *drainnode.Values {
+	resource := f.falco.ResourceName
+	return &drainnode.Values{
+		ProjectID: ctd.ProjectID(resource),
+		Zone:      ctd.ClusterZone(resource),
+		ClusterID: ctd.ClusterID(resource),
+		Node:      f.falco.VMInstanceName,
+	}
+}
+
+// DeletePod returns values for the delete pod automation.
+func (f *Finding) DeletePod() *deletepod.Values {
+	resource := f.falco.ResourceName
+	return &deletepod.Values{
+		ProjectID:   ctd.ProjectID(resource),
+		Zone:        ctd.ClusterZone(resource),
+		ClusterID:   ctd.ClusterID(resource),
+		Namespace:   ctd.PodNamespace(resource),
+		Pod:         ctd.PodName(resource),
+		FindingName: f.falco.Name,
+	}
+}
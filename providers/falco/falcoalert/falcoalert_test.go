@@ -0,0 +1,145 @@
+package falcoalert
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gke/deletepod"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gke/drainnode"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+	"github.com/googlecloudplatform/security-response-automation/findings/fixtures"
+)
+
+const cluster = "projects/aerial-jigsaw-235219/zones/us-central1-a/clusters/ctd-cluster"
+
+func TestTerminalShell(t *testing.T) {
+	b, err := Normalize(fixtures.ReadFormat(t, "falco_terminal_shell_in_container", fixtures.Falco), cluster)
+	if err != nil {
+		t.Fatalf("normalize failed: %q", err)
+	}
+	f, err := New(b)
+	if err != nil {
+		t.Fatalf("new failed: %q", err)
+	}
+	if name := f.Name(b); name != "falco_terminal_shell_in_container" {
+		t.Errorf("name failed: got:%q want:%q", name, "falco_terminal_shell_in_container")
+	}
+	if got := f.EventTime(); got != "2020-06-10T17:48:49.358291513Z" {
+		t.Errorf("event time failed: got:%q want:%q", got, "2020-06-10T17:48:49.358291513Z")
+	}
+	wantPod := &deletepod.Values{
+		ProjectID:   "aerial-jigsaw-235219",
+		Zone:        "us-central1-a",
+		ClusterID:   "ctd-cluster",
+		Namespace:   "default",
+		Pod:         "nginx-7d8f6b4c9-x2x5q",
+		FindingName: f.FindingName(),
+	}
+	if diff := cmp.Diff(f.DeletePod(), wantPod); diff != "" {
+		t.Errorf("delete pod failed, difference:%+v", diff)
+	}
+	wantNode := &drainnode.Values{
+		ProjectID: "aerial-jigsaw-235219",
+		Zone:      "us-central1-a",
+		ClusterID: "ctd-cluster",
+		Node:      "gke-ctd-cluster-default-pool-d8e6b3f2-7h2k",
+	}
+	if diff := cmp.Diff(f.DrainNode(), wantNode); diff != "" {
+		t.Errorf("drain node failed, difference:%+v", diff)
+	}
+	// The normalized finding reads as any other notification.
+	var v struct {
+		findings.Finding
+		Severity string `finding:"severity"`
+		Image    string `finding:"sourceProperties.Container_Image_Uri"`
+	}
+	if err := (&findings.Reader{}).Read(b, &v); err != nil {
+		t.Fatalf("read failed: %q", err)
+	}
+	if v.Severity != "LOW" || v.Image != "gcr.io/aerial-jigsaw-235219/nginx:latest" || v.State != "ACTIVE" {
+		t.Errorf("read failed: got severity:%q image:%q state:%q", v.Severity, v.Image, v.State)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	alert := fixtures.ReadFormat(t, "falco_terminal_shell_in_container", fixtures.Falco)
+	again, err := Normalize(alert, cluster)
+	if err != nil {
+		t.Fatalf("normalize failed: %q", err)
+	}
+	for _, tt := range []struct {
+		name, cluster string
+		alert         []byte
+		wantErr       bool
+		sameName      bool
+	}{
+		{name: "same alert", cluster: cluster, alert: alert, sameName: true},
+		{name: "regional cluster", cluster: "projects/p/locations/us-central1/clusters/c", alert: alert},
+		{name: "other alert", cluster: cluster, alert: fixtures.ReadFormat(t, "falco_terminal_shell_in_container", fixtures.Falco, "time=2020-06-10T17:50:00Z")},
+		{name: "invalid cluster", cluster: "ctd-cluster", alert: alert, wantErr: true},
+		{name: "no rule", cluster: cluster, alert: fixtures.ReadFormat(t, "falco_terminal_shell_in_container", fixtures.Falco, "rule="), wantErr: true},
+		{name: "not json", cluster: cluster, alert: []byte("Notice A shell was spawned"), wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Normalize(tt.alert, tt.cluster)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("%s failed: got no error", tt.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			f, _ := New(b)
+			g, _ := New(again)
+			if same := f.FindingName() == g.FindingName(); same != tt.sameName {
+				t.Errorf("%s failed, same finding name: got:%t want:%t", tt.name, same, tt.sameName)
+			}
+		})
+	}
+}
+
+func TestRuleName(t *testing.T) {
+	for _, tt := range []struct{ rule, want string }{
+		{rule: "Terminal shell in container", want: "falco_terminal_shell_in_container"},
+		{rule: "Drop and execute new binary in container", want: "falco_drop_and_execute_new_binary_in_container"},
+		{rule: "Write below binary dir", want: "falco_write_below_binary_dir"},
+		{rule: " (K8s) Attach/Exec Pod ", want: "falco_k8s_attach_exec_pod"},
+		{rule: "--", want: ""},
+	} {
+		if got := RuleName(tt.rule); got != tt.want {
+			t.Errorf("%q failed: got:%q want:%q", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestOtherFindings(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		finding []byte
+	}{
+		{name: "container threat detection", finding: fixtures.Read(t, "reverse_shell")},
+		{name: "raw falco alert", finding: fixtures.ReadFormat(t, "falco_terminal_shell_in_container", fixtures.Falco)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if name := (&Finding{}).Name(tt.finding); name != "" {
+				t.Errorf("%s failed: got:%q want:%q", tt.name, name, "")
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+//go:build go1.18
+// +build go1.18
+
+package falcoalert
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"testing"
+
+	"github.com/googlecloudplatform/security-response-automation/findings/fixtures"
+)
+
+func FuzzFinding(f *testing.F) {
+	f.Add(fixtures.ReadFormat(f, "falco_terminal_shell_in_container", fixtures.Falco))
+	f.Fuzz(func(t *testing.T, b []byte) {
+		n, err := Normalize(b, "projects/p/locations/l/clusters/c")
+		if err != nil {
+			return
+		}
+		(&Finding{}).Name(n)
+		r, err := New(n)
+		if err != nil {
+			t.Fatalf("normalized alert not read: %q", err)
+		}
+		r.FindingName()
+		r.EventTime()
+		r.DeletePod()
+		r.DrainNode()
+	})
+}
@@ -0,0 +1,136 @@
+// Package violation represents the violations Forseti Security sends to Security Command Center
+// with its CSCC notifier.
+package violation
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"regexp"
+
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gcs/closebucket"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/removenonorgmembers"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+	"github.com/pkg/errors"
+)
+
+// source is the source property set by Forseti on its violations.
+const source = "FORSETI"
+
+// rules maps the categories of Forseti violations, their violation type, to the rule names of
+// the findings. The IAM scanner reports members its rules don't allow as ADDED violations.
+var rules = map[string]string{
+	"BUCKET_VIOLATION": "forseti_bucket_violation",
+	"ADDED":            "forseti_iam_policy_violation",
+}
+
+// readers read the violations by their category. The resource name of a violation is the full
+// name of its resource in the Forseti inventory, such as
+// "organization/123/project/test-project/bucket/test-bucket/".
+var readers = map[string]*findings.Reader{
+	"BUCKET_VIOLATION": {
+		Categories: []string{"BUCKET_VIOLATION"},
+		Resource:   regexp.MustCompile(`^organization/[0-9]+/(?:folder/[0-9]+/)*project/(?P<project>[^/]+)/bucket/(?P<bucket>[^/]+)/?$`),
+	},
+	"ADDED": {
+		Categories: []string{"ADDED"},
+		Resource:   regexp.MustCompile(`^organization/(?P<organization>[0-9]+)/(?:folder/(?P<folder>[0-9]+)/)*(?:project/(?P<project>[^/]+)/)?(?:iam_policy/[^/]+/?)?$`),
+	},
+}
+
+// Finding represents this finding.
+type Finding struct {
+	violation violationFinding
+}
+
+// violationFinding holds the fields of the violation used by this provider. The groups of the
+// resource not matched by its reader are empty.
+type violationFinding struct {
+	findings.Finding
+	Source       string `finding:"sourceProperties.source"`
+	Organization string `finding:"resource.organization,optional"`
+	Folder       string `finding:"resource.folder,optional"`
+	ProjectID    string `finding:"resource.project,optional"`
+	Bucket       string `finding:"resource.bucket,optional"`
+}
+
+// Name returns the rule name of the finding.
+func (f *Finding) Name(b []byte) string {
+	v, err := New(b)
+	if err != nil {
+		return ""
+	}
+	return rules[v.violation.Category]
+}
+
+// New returns a new finding.
+func New(b []byte) (*Finding, error) {
+	var common findings.Finding
+	if err := (&findings.Reader{}).Read(b, &common); err != nil {
+		return nil, err
+	}
+	reader, ok := readers[common.Category]
+	if !ok {
+		return nil, errors.Wrapf(findings.ErrUnsupportedFinding, "category %q", common.Category)
+	}
+	var f Finding
+	if err := reader.Read(b, &f.violation); err != nil {
+		return nil, err
+	}
+	if f.violation.Source != source {
+		return nil, errors.New("not a forseti violation")
+	}
+	return &f, nil
+}
+
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.violation.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.violation.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.violation.SecurityMarks
+}
+
+// CloseBucket returns values for the close bucket automation.
+func (f *Finding) CloseBucket() *closebucket.Values {
+	return &closebucket.Values{
+		ProjectID:   f.violation.ProjectID,
+		BucketName:  f.violation.Bucket,
+		FindingName: f.violation.Name,
+	}
+}
+
+// RemoveNonOrgMembers returns values for the remove non org members automation.
+//
+// Violations on a folder or organization change its policy instead of the project's.
+func (f *Finding) RemoveNonOrgMembers() *removenonorgmembers.Values {
+	values := &removenonorgmembers.Values{
+		ProjectID: f.violation.ProjectID,
+	}
+	switch {
+	case f.violation.ProjectID != "":
+	case f.violation.Folder != "":
+		values.Resource = "folders/" + f.violation.Folder
+	default:
+		values.Resource = "organizations/" + f.violation.Organization
+	}
+	return values
+}
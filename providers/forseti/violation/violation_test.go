@@ -0,0 +1,115 @@
+package violation
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gcs/closebucket"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/removenonorgmembers"
+	"github.com/googlecloudplatform/security-response-automation/findings/fixtures"
+)
+
+func TestReadFinding(t *testing.T) {
+	for _, tt := range []struct {
+		name, ruleName string
+		bytes          []byte
+		closeBucket    *closebucket.Values
+		nonOrgMembers  *removenonorgmembers.Values
+	}{
+		{
+			name:     "bucket violation",
+			ruleName: "forseti_bucket_violation",
+			bytes:    fixtures.Read(t, "forseti_bucket_violation"),
+			closeBucket: &closebucket.Values{
+				ProjectID:   "test-project",
+				BucketName:  "this-is-public-on-purpose",
+				FindingName: "organizations/154584661726/sources/4923185120951702946/findings/9d1c6a0e2b7f43e58c2a6f1d0b3e7a45",
+			},
+		},
+		{
+			name:     "bucket violation in folder",
+			ruleName: "forseti_bucket_violation",
+			bytes: fixtures.Read(t, "forseti_bucket_violation",
+				"finding.resourceName=organization/154584661726/folder/123/project/test-project/bucket/this-is-public-on-purpose/"),
+			closeBucket: &closebucket.Values{
+				ProjectID:   "test-project",
+				BucketName:  "this-is-public-on-purpose",
+				FindingName: "organizations/154584661726/sources/4923185120951702946/findings/9d1c6a0e2b7f43e58c2a6f1d0b3e7a45",
+			},
+		},
+		{
+			name:          "project policy violation",
+			ruleName:      "forseti_iam_policy_violation",
+			bytes:         fixtures.Read(t, "forseti_iam_policy_violation"),
+			nonOrgMembers: &removenonorgmembers.Values{ProjectID: "test-project"},
+		},
+		{
+			name:     "folder policy violation",
+			ruleName: "forseti_iam_policy_violation",
+			bytes: fixtures.Read(t, "forseti_iam_policy_violation",
+				"finding.resourceName=organization/154584661726/folder/123/folder/456/"),
+			nonOrgMembers: &removenonorgmembers.Values{Resource: "folders/456"},
+		},
+		{
+			name:     "organization policy violation",
+			ruleName: "forseti_iam_policy_violation",
+			bytes: fixtures.Read(t, "forseti_iam_policy_violation",
+				"finding.resourceName=organization/154584661726/"),
+			nonOrgMembers: &removenonorgmembers.Values{Resource: "organizations/154584661726"},
+		},
+		{
+			name:     "other source",
+			ruleName: "",
+			bytes:    fixtures.Read(t, "forseti_bucket_violation", "finding.sourceProperties.source=OTHER"),
+		},
+		{
+			name:     "unsupported violation type",
+			ruleName: "",
+			bytes:    fixtures.Read(t, "forseti_bucket_violation", "finding.category=FIREWALL_VIOLATION"),
+		},
+		{
+			name:     "mismatched resource",
+			ruleName: "",
+			bytes:    fixtures.Read(t, "forseti_bucket_violation", "finding.resourceName=organization/154584661726/project/test-project/"),
+		},
+		{name: "scc finding", ruleName: "", bytes: fixtures.Read(t, "public_bucket_acl")},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Finding{}
+			if name := f.Name(tt.bytes); name != tt.ruleName {
+				t.Errorf("%s failed: got:%q want:%q", tt.name, name, tt.ruleName)
+			}
+			if tt.ruleName == "" {
+				return
+			}
+			r, err := New(tt.bytes)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if tt.closeBucket != nil {
+				if diff := cmp.Diff(r.CloseBucket(), tt.closeBucket); diff != "" {
+					t.Errorf("%s failed, difference: %+v", tt.name, diff)
+				}
+			}
+			if tt.nonOrgMembers != nil {
+				if diff := cmp.Diff(r.RemoveNonOrgMembers(), tt.nonOrgMembers); diff != "" {
+					t.Errorf("%s failed, difference: %+v", tt.name, diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Package apikeyscanner represents findings about exposed or unrestricted API keys.
+package apikeyscanner
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"regexp"
+
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/apikeys/restrictapikey"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+)
+
+// reader reads the API key findings supported by this provider, extracting the key name from the
+// resource name.
+var reader = &findings.Reader{
+	Categories: []string{"API_KEY_EXISTS", "API_KEY_APIS_UNRESTRICTED", "API_KEY_APPS_UNRESTRICTED"},
+	Resource:   regexp.MustCompile(`^//apikeys\.googleapis\.com/(?P<key>projects/[^/]+/locations/[^/]+/keys/[^/]+)$`),
+}
+
+// Finding represents this finding.
+type Finding struct {
+	apiKey apiKeyFinding
+}
+
+// apiKeyFinding holds the fields of the finding used by this provider.
+type apiKeyFinding struct {
+	findings.Finding
+	ProjectID string `finding:"sourceProperties.ProjectId"`
+	KeyName   string `finding:"resource.key"`
+}
+
+// Name returns the rule name of the finding.
+func (f *Finding) Name(b []byte) string {
+	return reader.Name(b)
+}
+
+// New returns a new finding.
+func New(b []byte) (*Finding, error) {
+	var f Finding
+	if err := reader.Read(b, &f.apiKey); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.apiKey.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.apiKey.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.apiKey.SecurityMarks
+}
+
+// RestrictAPIKey returns values for the restrict API key automation.
+func (f *Finding) RestrictAPIKey() *restrictapikey.Values {
+	return &restrictapikey.Values{
+		ProjectID: f.apiKey.ProjectID,
+		KeyName:   f.apiKey.KeyName,
+	}
+}
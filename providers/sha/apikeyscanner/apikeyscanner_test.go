@@ -0,0 +1,84 @@
+package apikeyscanner
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/apikeys/restrictapikey"
+)
+
+func TestReadFinding(t *testing.T) {
+	const (
+		apiKeyExists = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/k1",
+				"resourceName": "//apikeys.googleapis.com/projects/72300000536/locations/global/keys/6d5f2a1b-0c3e-4f7a-9b8d-1e2f3a4b5c6d",
+				"state": "ACTIVE",
+				"category": "API_KEY_EXISTS",
+				"sourceProperties": {
+					"ProjectId": "test-project"
+				}
+			}
+		}`
+		wrongCategory = `{
+			"finding": {
+				"resourceName": "//apikeys.googleapis.com/projects/72300000536/locations/global/keys/6d5f2a1b-0c3e-4f7a-9b8d-1e2f3a4b5c6d",
+				"category": "API_KEY_NOT_ROTATED"
+			}
+		}`
+		mismatchedResource = `{
+			"finding": {
+				"resourceName": "//cloudresourcemanager.googleapis.com/projects/72300000536",
+				"category": "API_KEY_APIS_UNRESTRICTED"
+			}
+		}`
+	)
+	for _, tt := range []struct {
+		name, ruleName string
+		bytes          []byte
+		values         *restrictapikey.Values
+	}{
+		{
+			name:     "api key exists",
+			ruleName: "api_key_exists",
+			bytes:    []byte(apiKeyExists),
+			values: &restrictapikey.Values{
+				ProjectID: "test-project",
+				KeyName:   "projects/72300000536/locations/global/keys/6d5f2a1b-0c3e-4f7a-9b8d-1e2f3a4b5c6d",
+			},
+		},
+		{name: "wrong category", ruleName: "", bytes: []byte(wrongCategory)},
+		{name: "mismatched resource", ruleName: "", bytes: []byte(mismatchedResource)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Finding{}
+			if name := f.Name(tt.bytes); name != tt.ruleName {
+				t.Errorf("%s failed: got:%q want:%q", tt.name, name, tt.ruleName)
+			}
+			if tt.values == nil {
+				return
+			}
+			r, err := New(tt.bytes)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(r.RestrictAPIKey(), tt.values); diff != "" {
+				t.Errorf("%s failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package artifactscanner
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"regexp"
+
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/artifactregistry/removepublicrepository"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+	"github.com/pkg/errors"
+)
+
+// readers read the findings supported by this provider, each category on the resources it applies
+// to, extracting the Artifact Registry repository or Container Registry bucket and its project
+// from the resource name.
+var readers = []*findings.Reader{
+	{
+		Categories: []string{"PUBLIC_ARTIFACT_REGISTRY_REPOSITORY"},
+		Resource:   regexp.MustCompile(`^//artifactregistry\.googleapis\.com/(?P<repository>projects/(?P<project>[^/]+)/locations/[^/]+/repositories/[^/]+)$`),
+	},
+	{
+		Categories: []string{"PUBLIC_CONTAINER_REGISTRY"},
+		Resource:   regexp.MustCompile(`^//storage\.googleapis\.com/(?P<bucket>(?:[a-z]+\.)?artifacts\.(?P<project>[^.]+)\.appspot\.com)$`),
+	},
+}
+
+// Finding represents this finding.
+type Finding struct {
+	artifact artifactFinding
+}
+
+// artifactFinding holds the fields of the finding used by this provider, either the repository
+// or the bucket is set.
+type artifactFinding struct {
+	findings.Finding
+	ProjectID  string `finding:"resource.project"`
+	Repository string `finding:"resource.repository,optional"`
+	Bucket     string `finding:"resource.bucket,optional"`
+}
+
+// Name returns the rule name of the finding.
+func (f *Finding) Name(b []byte) string {
+	for _, r := range readers {
+		if name := r.Name(b); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// New returns a new finding.
+func New(b []byte) (*Finding, error) {
+	for _, r := range readers {
+		var f Finding
+		err := r.Read(b, &f.artifact)
+		if errors.Cause(err) == findings.ErrUnsupportedFinding {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &f, nil
+	}
+	return nil, errors.Wrap(findings.ErrUnsupportedFinding, "not a public repository")
+}
+
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.artifact.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.artifact.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.artifact.SecurityMarks
+}
+
+// RemovePublicRepository returns values for the remove public repository automation.
+func (f *Finding) RemovePublicRepository() *removepublicrepository.Values {
+	return &removepublicrepository.Values{
+		ProjectID:  f.artifact.ProjectID,
+		Repository: f.artifact.Repository,
+		Bucket:     f.artifact.Bucket,
+	}
+}
@@ -0,0 +1,98 @@
+package artifactscanner
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/artifactregistry/removepublicrepository"
+)
+
+func TestReadFinding(t *testing.T) {
+	const (
+		publicRepository = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/a1",
+				"resourceName": "//artifactregistry.googleapis.com/projects/test-project/locations/us-central1/repositories/images",
+				"state": "ACTIVE",
+				"category": "PUBLIC_ARTIFACT_REGISTRY_REPOSITORY"
+			}
+		}`
+		publicContainerRegistry = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/a2",
+				"resourceName": "//storage.googleapis.com/eu.artifacts.test-project.appspot.com",
+				"state": "ACTIVE",
+				"category": "PUBLIC_CONTAINER_REGISTRY"
+			}
+		}`
+		wrongCategory = `{
+			"finding": {
+				"resourceName": "//artifactregistry.googleapis.com/projects/test-project/locations/us-central1/repositories/images",
+				"category": "PUBLIC_BUCKET_ACL"
+			}
+		}`
+		mismatchedResource = `{
+			"finding": {
+				"resourceName": "//storage.googleapis.com/test-bucket",
+				"category": "PUBLIC_CONTAINER_REGISTRY"
+			}
+		}`
+	)
+	for _, tt := range []struct {
+		name, ruleName string
+		bytes          []byte
+		values         *removepublicrepository.Values
+	}{
+		{
+			name:     "public repository",
+			ruleName: "public_artifact_registry_repository",
+			bytes:    []byte(publicRepository),
+			values: &removepublicrepository.Values{
+				Repository: "projects/test-project/locations/us-central1/repositories/images",
+				ProjectID:  "test-project",
+			},
+		},
+		{
+			name:     "public container registry",
+			ruleName: "public_container_registry",
+			bytes:    []byte(publicContainerRegistry),
+			values: &removepublicrepository.Values{
+				Bucket:    "eu.artifacts.test-project.appspot.com",
+				ProjectID: "test-project",
+			},
+		},
+		{name: "wrong category", ruleName: "", bytes: []byte(wrongCategory)},
+		{name: "mismatched resource", ruleName: "", bytes: []byte(mismatchedResource)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Finding{}
+			if name := f.Name(tt.bytes); name != tt.ruleName {
+				t.Errorf("%s failed: got:%q want:%q", tt.name, name, tt.ruleName)
+			}
+			if tt.values == nil {
+				return
+			}
+			r, err := New(tt.bytes)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(r.RemovePublicRepository(), tt.values); diff != "" {
+				t.Errorf("%s failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
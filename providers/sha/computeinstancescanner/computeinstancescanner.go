@@ -1,45 +1,107 @@
 package computeinstancescanner
 
 import (
-	"encoding/json"
-	"strings"
-
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/blockprojectsshkeys"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/disableserialport"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/enableoslogin"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/enableshieldedvm"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/removepublicip"
-	pb "github.com/googlecloudplatform/security-response-automation/compiled/sha/protos"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/removedefaulteditor"
+	"github.com/googlecloudplatform/security-response-automation/findings"
 	"github.com/googlecloudplatform/security-response-automation/providers/sha"
 )
 
+// reader reads the findings of the COMPUTE_INSTANCE_SCANNER of Security Health Analytics.
+var reader = &findings.Reader{
+	Properties: map[string]string{"ScannerName": "COMPUTE_INSTANCE_SCANNER"},
+}
+
 // Finding represents this finding.
 type Finding struct {
-	ComputeInstanceScanner *pb.ComputeInstanceScanner
+	instance instanceFinding
+}
+
+// instanceFinding holds the fields of the finding used by this provider.
+type instanceFinding struct {
+	findings.Finding
+	ProjectID string `finding:"sourceProperties.ProjectId"`
 }
 
 // Name returns the rule name of the finding.
 func (f *Finding) Name(b []byte) string {
-	var finding pb.ComputeInstanceScanner
-	if err := json.Unmarshal(b, &finding); err != nil {
-		return ""
-	}
-	if finding.GetFinding().GetSourceProperties().GetScannerName() != "COMPUTE_INSTANCE_SCANNER" {
-		return ""
-	}
-	return strings.ToLower(finding.GetFinding().GetCategory())
+	return reader.Name(b)
 }
 
 // New returns a new finding.
 func New(b []byte) (*Finding, error) {
 	var f Finding
-	if err := json.Unmarshal(b, &f.ComputeInstanceScanner); err != nil {
+	if err := reader.Read(b, &f.instance); err != nil {
 		return nil, err
 	}
 	return &f, nil
 }
 
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.instance.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.instance.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.instance.SecurityMarks
+}
+
 // RemovePublicIP returns values for the remove public IP policy automation.
 func (f *Finding) RemovePublicIP() *removepublicip.Values {
 	return &removepublicip.Values{
-		ProjectID:    f.ComputeInstanceScanner.GetFinding().GetSourceProperties().GetProjectID(),
-		InstanceZone: sha.Zone(f.ComputeInstanceScanner.GetFinding().GetResourceName()),
-		InstanceID:   sha.Instance(f.ComputeInstanceScanner.GetFinding().GetResourceName()),
+		ProjectID:    f.instance.ProjectID,
+		InstanceZone: sha.Zone(f.instance.ResourceName),
+		InstanceID:   sha.Instance(f.instance.ResourceName),
+	}
+}
+
+// BlockProjectSSHKeys returns values for the block project SSH keys automation.
+func (f *Finding) BlockProjectSSHKeys() *blockprojectsshkeys.Values {
+	return &blockprojectsshkeys.Values{
+		ProjectID:    f.instance.ProjectID,
+		InstanceZone: sha.Zone(f.instance.ResourceName),
+		InstanceID:   sha.Instance(f.instance.ResourceName),
+	}
+}
+
+// EnableOSLogin returns values for the enable OS Login automation.
+func (f *Finding) EnableOSLogin() *enableoslogin.Values {
+	return &enableoslogin.Values{
+		ProjectID: f.instance.ProjectID,
+	}
+}
+
+// DisableSerialPort returns values for the disable serial port automation.
+func (f *Finding) DisableSerialPort() *disableserialport.Values {
+	return &disableserialport.Values{
+		ProjectID:    f.instance.ProjectID,
+		InstanceZone: sha.Zone(f.instance.ResourceName),
+		InstanceID:   sha.Instance(f.instance.ResourceName),
+	}
+}
+
+// EnableShieldedVM returns values for the enable Shielded VM automation.
+func (f *Finding) EnableShieldedVM() *enableshieldedvm.Values {
+	return &enableshieldedvm.Values{
+		ProjectID:    f.instance.ProjectID,
+		InstanceZone: sha.Zone(f.instance.ResourceName),
+		InstanceID:   sha.Instance(f.instance.ResourceName),
+	}
+}
+
+// RemoveDefaultEditor returns values for the remove default service account Editor role automation.
+func (f *Finding) RemoveDefaultEditor() *removedefaulteditor.Values {
+	return &removedefaulteditor.Values{
+		ProjectID: f.instance.ProjectID,
 	}
 }
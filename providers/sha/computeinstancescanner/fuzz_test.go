@@ -0,0 +1,43 @@
+//go:build go1.18
+// +build go1.18
+
+package computeinstancescanner
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"testing"
+
+	"github.com/googlecloudplatform/security-response-automation/findings/fixtures"
+)
+
+func FuzzFinding(f *testing.F) {
+	for _, rule := range []string{"compute_project_wide_ssh_keys_allowed", "compute_serial_ports_enabled", "default_service_account_used", "os_login_disabled", "public_ip_address", "shielded_vm_disabled"} {
+		f.Add(fixtures.Read(f, rule))
+	}
+	f.Fuzz(func(t *testing.T, b []byte) {
+		(&Finding{}).Name(b)
+		r, err := New(b)
+		if err != nil {
+			return
+		}
+		r.RemovePublicIP()
+		r.BlockProjectSSHKeys()
+		r.EnableOSLogin()
+		r.DisableSerialPort()
+		r.EnableShieldedVM()
+		r.RemoveDefaultEditor()
+	})
+}
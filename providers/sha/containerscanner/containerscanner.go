@@ -1,45 +1,71 @@
 package containerscanner
 
 import (
-	"encoding/json"
-	"strings"
-
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gke/disabledashboard"
-	pb "github.com/googlecloudplatform/security-response-automation/compiled/sha/protos"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gke/enableauthorizednetworks"
+	"github.com/googlecloudplatform/security-response-automation/findings"
 	"github.com/googlecloudplatform/security-response-automation/providers/sha"
 )
 
+// reader reads the findings of the CONTAINER_SCANNER of Security Health Analytics.
+var reader = &findings.Reader{
+	Properties: map[string]string{"ScannerName": "CONTAINER_SCANNER"},
+}
+
 // Finding represents this finding.
 type Finding struct {
-	Containerscanner *pb.ContainerScanner
+	container containerFinding
+}
+
+// containerFinding holds the fields of the finding used by this provider.
+type containerFinding struct {
+	findings.Finding
+	ProjectID string `finding:"sourceProperties.ProjectId"`
 }
 
 // Name returns the rule name of the finding.
 func (f *Finding) Name(b []byte) string {
-	var finding pb.ContainerScanner
-	if err := json.Unmarshal(b, &finding); err != nil {
-		return ""
-	}
-	if finding.GetFinding().GetSourceProperties().GetScannerName() != "CONTAINER_SCANNER" {
-		return ""
-	}
-	return strings.ToLower(finding.GetFinding().GetCategory())
+	return reader.Name(b)
 }
 
 // New returns a new finding.
 func New(b []byte) (*Finding, error) {
 	var f Finding
-	if err := json.Unmarshal(b, &f.Containerscanner); err != nil {
+	if err := reader.Read(b, &f.container); err != nil {
 		return nil, err
 	}
 	return &f, nil
 }
 
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.container.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.container.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.container.SecurityMarks
+}
+
 // DisableDashboard returns values for the disable dashboard automation.
 func (f *Finding) DisableDashboard() *disabledashboard.Values {
 	return &disabledashboard.Values{
-		ProjectID: f.Containerscanner.GetFinding().GetSourceProperties().GetProjectID(),
-		Zone:      sha.ClusterZone(f.Containerscanner.GetFinding().GetResourceName()),
-		ClusterID: sha.ClusterID(f.Containerscanner.GetFinding().GetResourceName()),
+		ProjectID: f.container.ProjectID,
+		Zone:      sha.ClusterZone(f.container.ResourceName),
+		ClusterID: sha.ClusterID(f.container.ResourceName),
+	}
+}
+
+// EnableAuthorizedNetworks returns values for the enable master authorized networks automation.
+func (f *Finding) EnableAuthorizedNetworks() *enableauthorizednetworks.Values {
+	return &enableauthorizednetworks.Values{
+		ProjectID: f.container.ProjectID,
+		Zone:      sha.ClusterZone(f.container.ResourceName),
+		ClusterID: sha.ClusterID(f.container.ResourceName),
 	}
 }
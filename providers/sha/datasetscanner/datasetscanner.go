@@ -1,44 +1,60 @@
 package datasetscanner
 
 import (
-	"encoding/json"
-	"strings"
-
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/bigquery/closepublicdataset"
-	pb "github.com/googlecloudplatform/security-response-automation/compiled/sha/protos"
+	"github.com/googlecloudplatform/security-response-automation/findings"
 	"github.com/googlecloudplatform/security-response-automation/providers/sha"
 )
 
-// Finding represents this finding structure by SHA scanner.
+// reader reads the findings of the DATASET_SCANNER of Security Health Analytics.
+var reader = &findings.Reader{
+	Properties: map[string]string{"ScannerName": "DATASET_SCANNER"},
+}
+
+// Finding represents this finding.
 type Finding struct {
-	DatasetScanner *pb.DatasetScanner
+	dataset datasetFinding
+}
+
+// datasetFinding holds the fields of the finding used by this provider.
+type datasetFinding struct {
+	findings.Finding
+	ProjectID string `finding:"sourceProperties.ProjectId"`
 }
 
 // Name returns the category of the finding.
 func (f *Finding) Name(b []byte) string {
-	var finding pb.DatasetScanner
-	if err := json.Unmarshal(b, &finding); err != nil {
-		return ""
-	}
-	if finding.GetFinding().GetSourceProperties().GetScannerName() != "DATASET_SCANNER" {
-		return ""
-	}
-	return strings.ToLower(finding.GetFinding().GetCategory())
+	return reader.Name(b)
 }
 
 // New returns a new finding.
 func New(b []byte) (*Finding, error) {
 	var f Finding
-	if err := json.Unmarshal(b, &f.DatasetScanner); err != nil {
+	if err := reader.Read(b, &f.dataset); err != nil {
 		return nil, err
 	}
 	return &f, nil
 }
 
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.dataset.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.dataset.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.dataset.SecurityMarks
+}
+
 // ClosePublicDataset returns values for the close public dataset automation.
 func (f *Finding) ClosePublicDataset() *closepublicdataset.Values {
 	return &closepublicdataset.Values{
-		ProjectID: f.DatasetScanner.GetFinding().GetSourceProperties().GetProjectID(),
-		DatasetID: sha.Dataset(f.DatasetScanner.GetFinding().GetResourceName()),
+		ProjectID: f.dataset.ProjectID,
+		DatasetID: sha.Dataset(f.dataset.ResourceName),
 	}
 }
@@ -0,0 +1,79 @@
+// Package dnsscanner represents findings about Cloud DNS managed zones without DNSSEC.
+package dnsscanner
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"regexp"
+
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/dns/enablednssec"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+)
+
+// reader reads the DNSSEC findings on managed zones, extracting their project and managed zone.
+var reader = &findings.Reader{
+	Categories: []string{"DNSSEC_DISABLED"},
+	Resource:   regexp.MustCompile(`^//dns\.googleapis\.com/projects/(?P<project>[^/]+)/managedZones/(?P<zone>[^/]+)$`),
+}
+
+// Finding represents this finding.
+type Finding struct {
+	dns dnsFinding
+}
+
+// dnsFinding holds the fields of the finding used by this provider.
+type dnsFinding struct {
+	findings.Finding
+	ProjectID   string `finding:"resource.project"`
+	ManagedZone string `finding:"resource.zone"`
+}
+
+// Name returns the rule name of the finding.
+func (f *Finding) Name(b []byte) string {
+	return reader.Name(b)
+}
+
+// New returns a new finding.
+func New(b []byte) (*Finding, error) {
+	var f Finding
+	if err := reader.Read(b, &f.dns); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.dns.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.dns.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.dns.SecurityMarks
+}
+
+// EnableDNSSEC returns values for the enable DNSSEC automation.
+func (f *Finding) EnableDNSSEC() *enablednssec.Values {
+	return &enablednssec.Values{
+		ProjectID:   f.dns.ProjectID,
+		ManagedZone: f.dns.ManagedZone,
+		FindingName: f.dns.Name,
+	}
+}
@@ -0,0 +1,85 @@
+package dnsscanner
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/dns/enablednssec"
+)
+
+func TestReadFinding(t *testing.T) {
+	const (
+		dnssecDisabled = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/d1",
+				"resourceName": "//dns.googleapis.com/projects/test-project/managedZones/public-zone",
+				"state": "ACTIVE",
+				"category": "DNSSEC_DISABLED",
+				"sourceProperties": {
+					"ProjectId": "test-project"
+				}
+			}
+		}`
+		wrongCategory = `{
+			"finding": {
+				"resourceName": "//dns.googleapis.com/projects/test-project/managedZones/public-zone",
+				"category": "RSASHA1_FOR_SIGNING"
+			}
+		}`
+		mismatchedResource = `{
+			"finding": {
+				"resourceName": "//dns.googleapis.com/projects/test-project/policies/default",
+				"category": "DNSSEC_DISABLED"
+			}
+		}`
+	)
+	for _, tt := range []struct {
+		name, ruleName string
+		bytes          []byte
+		values         *enablednssec.Values
+	}{
+		{
+			name:     "dnssec disabled",
+			ruleName: "dnssec_disabled",
+			bytes:    []byte(dnssecDisabled),
+			values: &enablednssec.Values{
+				ProjectID:   "test-project",
+				ManagedZone: "public-zone",
+				FindingName: "organizations/154584661726/sources/2673592633662526977/findings/d1",
+			},
+		},
+		{name: "wrong category", ruleName: "", bytes: []byte(wrongCategory)},
+		{name: "mismatched resource", ruleName: "", bytes: []byte(mismatchedResource)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Finding{}
+			if name := f.Name(tt.bytes); name != tt.ruleName {
+				t.Errorf("%s failed: got:%q want:%q", tt.name, name, tt.ruleName)
+			}
+			if tt.values == nil {
+				return
+			}
+			r, err := New(tt.bytes)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(r.EnableDNSSEC(), tt.values); diff != "" {
+				t.Errorf("%s failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
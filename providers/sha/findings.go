@@ -37,6 +37,10 @@ var (
 	extractClusterID = regexp.MustCompile(`/clusters/(.+)`)
 	// extractOrganizationID is a regex to extract the organizationID value from a resource string.
 	extractOrganizationID = regexp.MustCompile(`organizations/(.+)/sources`)
+	// extractServiceAccount is a regex to extract the service account that is on the resource name.
+	extractServiceAccount = regexp.MustCompile(`/serviceAccounts/([^/]+)`)
+	// extractResourceManager is a regex to extract the project, folder or organization that is on the resource name.
+	extractResourceManager = regexp.MustCompile(`^//cloudresourcemanager\.googleapis\.com/((?:projects|folders|organizations)/[^/]+)$`)
 )
 
 // GenericFindingState is a finding that exposes its state.
@@ -51,40 +55,65 @@ func IgnoreFinding(finding GenericFindingState) bool {
 
 // Zone returns the zone of the instance.
 func Zone(resource string) string {
-	return extractZone.FindStringSubmatch(resource)[1]
+	return submatch(extractZone, resource)
 }
 
 // Instance returns the name of the instance.
 func Instance(resource string) string {
-	return extractInstance.FindStringSubmatch(resource)[1]
+	return submatch(extractInstance, resource)
 }
 
 // Dataset returns the ID of the BigQuery dataset.
 func Dataset(resource string) string {
-	return extractDataset.FindStringSubmatch(resource)[1]
+	return submatch(extractDataset, resource)
 }
 
-// BucketName returns name of the bucket. Resource assumed valid due to prior validate call.
+// BucketName returns name of the bucket.
 func BucketName(resource string) string {
-	return strings.Split(resource, resourcePrefix)[1]
+	s := strings.Split(resource, resourcePrefix)
+	if len(s) < 2 {
+		return ""
+	}
+	return s[1]
 }
 
 // FirewallID returns the numerical ID of the firewall.
 func FirewallID(resource string) string {
-	return extractFirewallID.FindStringSubmatch(resource)[1]
+	return submatch(extractFirewallID, resource)
 }
 
 // ClusterZone returns the zone of the cluster.
 func ClusterZone(resource string) string {
-	return extractClusterZone.FindStringSubmatch(resource)[1]
+	return submatch(extractClusterZone, resource)
 }
 
 // ClusterID returns the cluster id of the cluster.
 func ClusterID(resource string) string {
-	return extractClusterID.FindStringSubmatch(resource)[1]
+	return submatch(extractClusterID, resource)
 }
 
 // OrganizationID returns the organization name.
 func OrganizationID(resource string) string {
-	return extractOrganizationID.FindStringSubmatch(resource)[1]
+	return submatch(extractOrganizationID, resource)
+}
+
+// ServiceAccount returns the email or unique ID of the service account.
+func ServiceAccount(resource string) string {
+	return submatch(extractServiceAccount, resource)
+}
+
+// ResourceManager returns the project, folder or organization, such as "folders/123", of a Cloud
+// Resource Manager resource name.
+func ResourceManager(resource string) string {
+	return submatch(extractResourceManager, resource)
+}
+
+// submatch returns the value the regex extracts from the resource, or an empty string if it
+// doesn't match.
+func submatch(r *regexp.Regexp, resource string) string {
+	m := r.FindStringSubmatch(resource)
+	if len(m) < 2 {
+		return ""
+	}
+	return m[1]
 }
@@ -1,44 +1,60 @@
 package firewallscanner
 
 import (
-	"encoding/json"
-	"strings"
-
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/openfirewall"
-	pb "github.com/googlecloudplatform/security-response-automation/compiled/sha/protos"
+	"github.com/googlecloudplatform/security-response-automation/findings"
 	"github.com/googlecloudplatform/security-response-automation/providers/sha"
 )
 
+// reader reads the findings of the FIREWALL_SCANNER of Security Health Analytics.
+var reader = &findings.Reader{
+	Properties: map[string]string{"ScannerName": "FIREWALL_SCANNER"},
+}
+
 // Finding represents this finding.
 type Finding struct {
-	FirewallScanner *pb.FirewallScanner
+	firewall firewallFinding
+}
+
+// firewallFinding holds the fields of the finding used by this provider.
+type firewallFinding struct {
+	findings.Finding
+	ProjectID string `finding:"sourceProperties.ProjectId"`
 }
 
 // Name returns the rule name of the finding.
 func (f *Finding) Name(b []byte) string {
-	var finding pb.FirewallScanner
-	if err := json.Unmarshal(b, &finding); err != nil {
-		return ""
-	}
-	if finding.GetFinding().GetSourceProperties().GetScannerName() != "FIREWALL_SCANNER" {
-		return ""
-	}
-	return strings.ToLower(finding.GetFinding().GetCategory())
+	return reader.Name(b)
 }
 
 // New returns a new finding.
 func New(b []byte) (*Finding, error) {
 	var f Finding
-	if err := json.Unmarshal(b, &f.FirewallScanner); err != nil {
+	if err := reader.Read(b, &f.firewall); err != nil {
 		return nil, err
 	}
 	return &f, nil
 }
 
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.firewall.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.firewall.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.firewall.SecurityMarks
+}
+
 // OpenFirewall returns values for the remediate automation.
 func (f *Finding) OpenFirewall() *openfirewall.Values {
 	return &openfirewall.Values{
-		ProjectID:  f.FirewallScanner.GetFinding().GetSourceProperties().GetProjectId(),
-		FirewallID: sha.FirewallID(f.FirewallScanner.GetFinding().GetResourceName()),
+		ProjectID:  f.firewall.ProjectID,
+		FirewallID: sha.FirewallID(f.firewall.ResourceName),
 	}
 }
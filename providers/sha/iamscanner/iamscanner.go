@@ -1,42 +1,85 @@
 package iamscanner
 
 import (
-	"encoding/json"
 	"strings"
 
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/disableoldkeys"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/removedefaulteditor"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/removenonorgmembers"
-	pb "github.com/googlecloudplatform/security-response-automation/compiled/sha/protos"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+	"github.com/googlecloudplatform/security-response-automation/providers/sha"
 )
 
-// Finding represents this finding structure by SHA scanner.
+// reader reads the findings of the IAM_SCANNER of Security Health Analytics.
+var reader = &findings.Reader{
+	Properties: map[string]string{"ScannerName": "IAM_SCANNER"},
+}
+
+// Finding represents this finding.
 type Finding struct {
-	IAMScanner *pb.IamScanner
+	iam iamFinding
+}
+
+// iamFinding holds the fields of the finding used by this provider.
+type iamFinding struct {
+	findings.Finding
+	// ProjectID isn't set on findings of folders and organizations.
+	ProjectID string `finding:"sourceProperties.ProjectId,optional"`
+}
+
+// Name returns the category of the finding.
+func (f *Finding) Name(b []byte) string {
+	return reader.Name(b)
 }
 
 // New returns a new finding.
 func New(b []byte) (*Finding, error) {
 	var f Finding
-	if err := json.Unmarshal(b, &f.IAMScanner); err != nil {
+	if err := reader.Read(b, &f.iam); err != nil {
 		return nil, err
 	}
 	return &f, nil
 }
 
-// Name returns the category of the finding.
-func (f *Finding) Name(b []byte) string {
-	var finding pb.IamScanner
-	if err := json.Unmarshal(b, &finding); err != nil {
-		return ""
-	}
-	if finding.GetFinding().GetSourceProperties().GetScannerName() != "IAM_SCANNER" {
-		return ""
-	}
-	return strings.ToLower(finding.GetFinding().GetCategory())
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.iam.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.iam.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.iam.SecurityMarks
 }
 
 // RemoveNonOrgMembers returns values for the remove non org members automation.
+//
+// Findings on a folder or organization change its policy instead of the project's.
 func (f *Finding) RemoveNonOrgMembers() *removenonorgmembers.Values {
-	return &removenonorgmembers.Values{
-		ProjectID: f.IAMScanner.GetFinding().GetSourceProperties().GetProjectID(),
+	values := &removenonorgmembers.Values{
+		ProjectID: f.iam.ProjectID,
+	}
+	if r := sha.ResourceManager(f.iam.ResourceName); !strings.HasPrefix(r, "projects/") {
+		values.Resource = r
+	}
+	return values
+}
+
+// RemoveDefaultEditor returns values for the remove default service account Editor role automation.
+func (f *Finding) RemoveDefaultEditor() *removedefaulteditor.Values {
+	return &removedefaulteditor.Values{
+		ProjectID: f.iam.ProjectID,
+	}
+}
+
+// DisableOldKeys returns values for the disable old service account keys automation.
+func (f *Finding) DisableOldKeys() *disableoldkeys.Values {
+	return &disableoldkeys.Values{
+		ProjectID:      f.iam.ProjectID,
+		ServiceAccount: sha.ServiceAccount(f.iam.ResourceName),
 	}
 }
@@ -1,47 +1,23 @@
 package iamscanner
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/removenonorgmembers"
+	"github.com/googlecloudplatform/security-response-automation/findings/fixtures"
 	"golang.org/x/xerrors"
 )
 
 func TestReadFinding(t *testing.T) {
-	const (
-		nonOrgMemberFinding = `{
-		"finding": {
-			"name": "organizations/1050000000008/sources/1986930501000008034/findings/047db1bc23a4b1fb00cbaa79b468945a",
-			"parent": "organizations/1050000000008/sources/1986930501000008034",
-			"resourceName": "//cloudresourcemanager.googleapis.com/projects/72300000536",
-			"state": "ACTIVE",
-			"category": "NON_ORG_IAM_MEMBER",
-			"externalUri": "https://console.cloud.google.com/iam-admin/iam?project=test-project",
-			"sourceProperties": {
-				"ReactivationCount": 0,
-				"ExceptionInstructions": "Add the security mark \"allow_non_org_iam_member\" to the asset with a value of \"true\" to prevent this finding from being activated again.",
-				"SeverityLevel": "High",
-				"Recommendation": "Go to https://console.cloud.google.com/iam-admin/iam?project=test-project and remove entries for users which are not in your organization (e.g. gmail.com addresses).",
-				"ProjectId": "test-project",
-				"AssetCreationTime": "2019-02-26T15:41:40.726Z",
-				"ScannerName": "IAM_SCANNER",
-				"ScanRunId": "2019-10-18T08:30:22.082-07:00",
-				"Explanation": "A user outside of your organization has IAM permissions on a project or organization."
-			},
-			"securityMarks": {
-				"name": "organizations/1050000000008/sources/1986930501000008034/findings/047db1bc23a4b1fb00cbaa79b468945a/securityMarks"
-			},
-			"eventTime": "2019-10-18T15:30:22.082Z",
-			"createTime": "2019-10-18T15:31:58.487Z"
-           }
-		}`
-	)
 	for _, tt := range []struct {
 		name          string
 		projectID     string
 		bytes         []byte
 		expectedError error
 	}{
-		{name: "read", projectID: "test-project", bytes: []byte(nonOrgMemberFinding), expectedError: nil},
+		{name: "read", projectID: "test-project", bytes: fixtures.Read(t, "non_org_iam_member"), expectedError: nil},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			f, err := New(tt.bytes)
@@ -58,3 +34,83 @@ func TestReadFinding(t *testing.T) {
 		})
 	}
 }
+
+func TestDisableOldKeys(t *testing.T) {
+	const keyNotRotatedFinding = `{
+		"finding": {
+			"name": "organizations/1050000000008/sources/1986930501000008034/findings/6a30ce604c11417995b1fa71007d66d0",
+			"parent": "organizations/1050000000008/sources/1986930501000008034",
+			"resourceName": "//iam.googleapis.com/projects/test-project/serviceAccounts/105000000000000000001/keys/2a0b2c1d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b",
+			"state": "ACTIVE",
+			"category": "SERVICE_ACCOUNT_KEY_NOT_ROTATED",
+			"sourceProperties": {
+				"ProjectId": "test-project",
+				"ScannerName": "IAM_SCANNER"
+			},
+			"eventTime": "2019-10-18T15:30:22.082Z"
+		}
+	}`
+	f, err := New([]byte(keyNotRotatedFinding))
+	if err != nil {
+		t.Fatalf("failed to read finding: %q", err)
+	}
+	values := f.DisableOldKeys()
+	if values.ProjectID != "test-project" {
+		t.Errorf("got project:%q want:%q", values.ProjectID, "test-project")
+	}
+	if values.ServiceAccount != "105000000000000000001" {
+		t.Errorf("got service account:%q want:%q", values.ServiceAccount, "105000000000000000001")
+	}
+}
+
+func TestRemoveNonOrgMembers(t *testing.T) {
+	const finding = `{
+		"finding": {
+			"name": "organizations/1050000000008/sources/1986930501000008034/findings/047db1bc23a4b1fb00cbaa79b468945a",
+			"parent": "organizations/1050000000008/sources/1986930501000008034",
+			"resourceName": "//cloudresourcemanager.googleapis.com/RESOURCE",
+			"state": "ACTIVE",
+			"category": "NON_ORG_IAM_MEMBER",
+			"sourceProperties": {
+				"ProjectId": "PROJECT",
+				"ScannerName": "IAM_SCANNER"
+			},
+			"eventTime": "2019-10-18T15:30:22.082Z"
+		}
+	}`
+	tests := []struct {
+		name      string
+		resource  string
+		projectID string
+		expected  *removenonorgmembers.Values
+	}{
+		{
+			name:      "project",
+			resource:  "projects/72300000536",
+			projectID: "test-project",
+			expected:  &removenonorgmembers.Values{ProjectID: "test-project"},
+		},
+		{
+			name:     "folder",
+			resource: "folders/123",
+			expected: &removenonorgmembers.Values{Resource: "folders/123"},
+		},
+		{
+			name:     "organization",
+			resource: "organizations/1050000000008",
+			expected: &removenonorgmembers.Values{Resource: "organizations/1050000000008"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := strings.Replace(strings.Replace(finding, "RESOURCE", tt.resource, 1), "PROJECT", tt.projectID, 1)
+			f, err := New([]byte(b))
+			if err != nil {
+				t.Fatalf("failed to read finding: %q", err)
+			}
+			if diff := cmp.Diff(tt.expected, f.RemoveNonOrgMembers()); diff != "" {
+				t.Errorf("%s failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
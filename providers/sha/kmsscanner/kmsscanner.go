@@ -0,0 +1,79 @@
+// Package kmsscanner represents findings about publicly accessible Cloud KMS key rings and keys.
+package kmsscanner
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"regexp"
+
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/kms/removepublickms"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+)
+
+// reader reads the KMS findings supported by this provider, extracting the key ring or crypto
+// key and its project from the resource name.
+var reader = &findings.Reader{
+	Categories: []string{"KMS_PUBLIC_KEY"},
+	Resource:   regexp.MustCompile(`^//cloudkms\.googleapis\.com/(?P<path>projects/(?P<project>[^/]+)/locations/[^/]+/keyRings/[^/]+(?:/cryptoKeys/[^/]+)?)$`),
+}
+
+// Finding represents this finding.
+type Finding struct {
+	kms kmsFinding
+}
+
+// kmsFinding holds the fields of the finding used by this provider.
+type kmsFinding struct {
+	findings.Finding
+	Resource  string `finding:"resource.path"`
+	ProjectID string `finding:"resource.project"`
+}
+
+// Name returns the rule name of the finding.
+func (f *Finding) Name(b []byte) string {
+	return reader.Name(b)
+}
+
+// New returns a new finding.
+func New(b []byte) (*Finding, error) {
+	var f Finding
+	if err := reader.Read(b, &f.kms); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.kms.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.kms.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.kms.SecurityMarks
+}
+
+// RemovePublicKMS returns values for the remove public KMS access automation.
+func (f *Finding) RemovePublicKMS() *removepublickms.Values {
+	return &removepublickms.Values{
+		Resource:  f.kms.Resource,
+		ProjectID: f.kms.ProjectID,
+	}
+}
@@ -0,0 +1,98 @@
+package kmsscanner
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/kms/removepublickms"
+)
+
+func TestReadFinding(t *testing.T) {
+	const (
+		publicKey = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/k1",
+				"resourceName": "//cloudkms.googleapis.com/projects/test-project/locations/global/keyRings/ring/cryptoKeys/key",
+				"state": "ACTIVE",
+				"category": "KMS_PUBLIC_KEY"
+			}
+		}`
+		publicKeyRing = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/k2",
+				"resourceName": "//cloudkms.googleapis.com/projects/test-project/locations/us-east1/keyRings/ring",
+				"state": "ACTIVE",
+				"category": "KMS_PUBLIC_KEY"
+			}
+		}`
+		wrongCategory = `{
+			"finding": {
+				"resourceName": "//cloudkms.googleapis.com/projects/test-project/locations/global/keyRings/ring/cryptoKeys/key",
+				"category": "KMS_KEY_NOT_ROTATED"
+			}
+		}`
+		mismatchedResource = `{
+			"finding": {
+				"resourceName": "//cloudresourcemanager.googleapis.com/projects/test-project",
+				"category": "KMS_PUBLIC_KEY"
+			}
+		}`
+	)
+	for _, tt := range []struct {
+		name, ruleName string
+		bytes          []byte
+		values         *removepublickms.Values
+	}{
+		{
+			name:     "public key",
+			ruleName: "kms_public_key",
+			bytes:    []byte(publicKey),
+			values: &removepublickms.Values{
+				Resource:  "projects/test-project/locations/global/keyRings/ring/cryptoKeys/key",
+				ProjectID: "test-project",
+			},
+		},
+		{
+			name:     "public key ring",
+			ruleName: "kms_public_key",
+			bytes:    []byte(publicKeyRing),
+			values: &removepublickms.Values{
+				Resource:  "projects/test-project/locations/us-east1/keyRings/ring",
+				ProjectID: "test-project",
+			},
+		},
+		{name: "wrong category", ruleName: "", bytes: []byte(wrongCategory)},
+		{name: "mismatched resource", ruleName: "", bytes: []byte(mismatchedResource)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Finding{}
+			if name := f.Name(tt.bytes); name != tt.ruleName {
+				t.Errorf("%s failed: got:%q want:%q", tt.name, name, tt.ruleName)
+			}
+			if tt.values == nil {
+				return
+			}
+			r, err := New(tt.bytes)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(r.RemovePublicKMS(), tt.values); diff != "" {
+				t.Errorf("%s failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package loadbalancerscanner
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"regexp"
+
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/removeexternalexposure"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+)
+
+// reader reads the findings of a backend service unintentionally exposed through an external
+// HTTP(S) load balancer, for example reported by a Security Health Analytics custom module,
+// extracting the project and backend service from the resource name.
+var reader = &findings.Reader{
+	Categories: []string{"UNINTENDED_EXTERNAL_EXPOSURE"},
+	Resource:   regexp.MustCompile(`^//compute\.googleapis\.com/projects/(?P<project>[^/]+)/global/backendServices/(?P<service>[^/]+)$`),
+}
+
+// Finding represents this finding.
+type Finding struct {
+	loadBalancer loadBalancerFinding
+}
+
+// loadBalancerFinding holds the fields of the finding used by this provider.
+type loadBalancerFinding struct {
+	findings.Finding
+	ProjectID      string `finding:"resource.project"`
+	BackendService string `finding:"resource.service"`
+}
+
+// Name returns the rule name of the finding.
+func (f *Finding) Name(b []byte) string {
+	return reader.Name(b)
+}
+
+// New returns a new finding.
+func New(b []byte) (*Finding, error) {
+	var f Finding
+	if err := reader.Read(b, &f.loadBalancer); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.loadBalancer.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.loadBalancer.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.loadBalancer.SecurityMarks
+}
+
+// RemoveExternalExposure returns values for the remove external exposure automation.
+func (f *Finding) RemoveExternalExposure() *removeexternalexposure.Values {
+	return &removeexternalexposure.Values{
+		ProjectID:      f.loadBalancer.ProjectID,
+		BackendService: f.loadBalancer.BackendService,
+	}
+}
@@ -0,0 +1,81 @@
+package loadbalancerscanner
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/removeexternalexposure"
+)
+
+func TestReadFinding(t *testing.T) {
+	const (
+		exposedBackend = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/lb1",
+				"resourceName": "//compute.googleapis.com/projects/test-project/global/backendServices/web-backend",
+				"state": "ACTIVE",
+				"category": "UNINTENDED_EXTERNAL_EXPOSURE"
+			}
+		}`
+		regionalBackend = `{
+			"finding": {
+				"resourceName": "//compute.googleapis.com/projects/test-project/regions/us-central1/backendServices/web-backend",
+				"category": "UNINTENDED_EXTERNAL_EXPOSURE"
+			}
+		}`
+		otherCategory = `{
+			"finding": {
+				"resourceName": "//compute.googleapis.com/projects/test-project/global/backendServices/web-backend",
+				"category": "OPEN_FIREWALL"
+			}
+		}`
+	)
+	for _, tt := range []struct {
+		name, ruleName string
+		bytes          []byte
+		values         *removeexternalexposure.Values
+	}{
+		{
+			name:     "exposed backend service",
+			ruleName: "unintended_external_exposure",
+			bytes:    []byte(exposedBackend),
+			values: &removeexternalexposure.Values{
+				ProjectID:      "test-project",
+				BackendService: "web-backend",
+			},
+		},
+		{name: "regional backend service", ruleName: "", bytes: []byte(regionalBackend)},
+		{name: "other category", ruleName: "", bytes: []byte(otherCategory)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Finding{}
+			if name := f.Name(tt.bytes); name != tt.ruleName {
+				t.Errorf("%s failed: got:%q want:%q", tt.name, name, tt.ruleName)
+			}
+			if tt.values == nil {
+				return
+			}
+			r, err := New(tt.bytes)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(r.RemoveExternalExposure(), tt.values); diff != "" {
+				t.Errorf("%s failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
@@ -1,42 +1,68 @@
 package loggingscanner
 
 import (
-	"encoding/json"
-	"strings"
-
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/iam/enableauditlogs"
-	pb "github.com/googlecloudplatform/security-response-automation/compiled/sha/protos"
+	"github.com/googlecloudplatform/security-response-automation/findings"
 )
 
+// reader reads the findings of the LOGGING_SCANNER of Security Health Analytics.
+var reader = &findings.Reader{
+	Properties: map[string]string{"ScannerName": "LOGGING_SCANNER"},
+}
+
 // Finding represents this finding.
 type Finding struct {
-	Loggingscanner *pb.LoggingScanner
+	logging loggingFinding
+}
+
+// loggingFinding holds the fields of the finding used by this provider.
+type loggingFinding struct {
+	findings.Finding
+	ProjectID string `finding:"sourceProperties.ProjectId"`
+	// DisabledServices are the services missing data access logs.
+	DisabledServices []string `finding:"sourceProperties.DisabledServices,optional"`
+}
+
+// Name returns the category of the finding.
+func (f *Finding) Name(b []byte) string {
+	return reader.Name(b)
 }
 
 // New returns a new finding.
 func New(b []byte) (*Finding, error) {
 	var f Finding
-	if err := json.Unmarshal(b, &f.Loggingscanner); err != nil {
+	if err := reader.Read(b, &f.logging); err != nil {
 		return nil, err
 	}
 	return &f, nil
 }
 
-// Name returns the category of the finding.
-func (f *Finding) Name(b []byte) string {
-	var finding pb.LoggingScanner
-	if err := json.Unmarshal(b, &finding); err != nil {
-		return ""
-	}
-	if finding.GetFinding().GetSourceProperties().GetScannerName() != "LOGGING_SCANNER" {
-		return ""
-	}
-	return strings.ToLower(finding.GetFinding().GetCategory())
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.logging.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.logging.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.logging.SecurityMarks
 }
 
 // EnableAuditLogs return values for the enable audit logs automation.
 func (f *Finding) EnableAuditLogs() *enableauditlogs.Values {
 	return &enableauditlogs.Values{
-		ProjectID: f.Loggingscanner.GetFinding().GetSourceProperties().GetProjectID(),
+		ProjectID: f.logging.ProjectID,
+	}
+}
+
+// EnableServiceAuditLogs returns values for the enable service audit logs automation.
+func (f *Finding) EnableServiceAuditLogs() *enableauditlogs.Values {
+	return &enableauditlogs.Values{
+		ProjectID: f.logging.ProjectID,
+		Services:  f.logging.DisabledServices,
 	}
 }
@@ -0,0 +1,91 @@
+// Package networkscanner represents findings about subnetworks without Private Google Access.
+package networkscanner
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"regexp"
+
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/enableflowlogs"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/enableprivateaccess"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+)
+
+// reader reads the findings on subnetworks supported by this provider, extracting the project,
+// region and subnetwork from the resource name.
+var reader = &findings.Reader{
+	Categories: []string{"PRIVATE_GOOGLE_ACCESS_DISABLED", "FLOW_LOGS_DISABLED"},
+	Resource:   regexp.MustCompile(`^//compute\.googleapis\.com/projects/(?P<project>[^/]+)/regions/(?P<region>[^/]+)/subnetworks/(?P<subnetwork>[^/]+)$`),
+}
+
+// Finding represents this finding.
+type Finding struct {
+	network networkFinding
+}
+
+// networkFinding holds the fields of the finding used by this provider.
+type networkFinding struct {
+	findings.Finding
+	ProjectID  string `finding:"resource.project"`
+	Region     string `finding:"resource.region"`
+	Subnetwork string `finding:"resource.subnetwork"`
+}
+
+// Name returns the rule name of the finding.
+func (f *Finding) Name(b []byte) string {
+	return reader.Name(b)
+}
+
+// New returns a new finding.
+func New(b []byte) (*Finding, error) {
+	var f Finding
+	if err := reader.Read(b, &f.network); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.network.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.network.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.network.SecurityMarks
+}
+
+// EnablePrivateAccess returns values for the enable private access automation.
+func (f *Finding) EnablePrivateAccess() *enableprivateaccess.Values {
+	return &enableprivateaccess.Values{
+		ProjectID:  f.network.ProjectID,
+		Region:     f.network.Region,
+		Subnetwork: f.network.Subnetwork,
+	}
+}
+
+// EnableFlowLogs returns values for the enable flow logs automation.
+func (f *Finding) EnableFlowLogs() *enableflowlogs.Values {
+	return &enableflowlogs.Values{
+		ProjectID:  f.network.ProjectID,
+		Region:     f.network.Region,
+		Subnetwork: f.network.Subnetwork,
+	}
+}
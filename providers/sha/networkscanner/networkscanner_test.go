@@ -0,0 +1,112 @@
+package networkscanner
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/enableflowlogs"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gce/enableprivateaccess"
+)
+
+func TestReadFinding(t *testing.T) {
+	const (
+		privateAccessDisabled = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/n1",
+				"resourceName": "//compute.googleapis.com/projects/test-project/regions/us-central1/subnetworks/default",
+				"state": "ACTIVE",
+				"category": "PRIVATE_GOOGLE_ACCESS_DISABLED",
+				"sourceProperties": {
+					"ProjectId": "test-project"
+				}
+			}
+		}`
+		wrongCategory = `{
+			"finding": {
+				"resourceName": "//compute.googleapis.com/projects/test-project/regions/us-central1/subnetworks/default",
+				"category": "OPEN_FIREWALL"
+			}
+		}`
+		mismatchedResource = `{
+			"finding": {
+				"resourceName": "//compute.googleapis.com/projects/test-project/global/networks/default",
+				"category": "PRIVATE_GOOGLE_ACCESS_DISABLED"
+			}
+		}`
+	)
+	for _, tt := range []struct {
+		name, ruleName string
+		bytes          []byte
+		values         *enableprivateaccess.Values
+	}{
+		{
+			name:     "private google access disabled",
+			ruleName: "private_google_access_disabled",
+			bytes:    []byte(privateAccessDisabled),
+			values: &enableprivateaccess.Values{
+				ProjectID:  "test-project",
+				Region:     "us-central1",
+				Subnetwork: "default",
+			},
+		},
+		{name: "wrong category", ruleName: "", bytes: []byte(wrongCategory)},
+		{name: "mismatched resource", ruleName: "", bytes: []byte(mismatchedResource)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Finding{}
+			if name := f.Name(tt.bytes); name != tt.ruleName {
+				t.Errorf("%s failed: got:%q want:%q", tt.name, name, tt.ruleName)
+			}
+			if tt.values == nil {
+				return
+			}
+			r, err := New(tt.bytes)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(r.EnablePrivateAccess(), tt.values); diff != "" {
+				t.Errorf("%s failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestReadFlowLogsDisabled(t *testing.T) {
+	const flowLogsDisabled = `{
+		"finding": {
+			"name": "organizations/154584661726/sources/2673592633662526977/findings/n2",
+			"resourceName": "//compute.googleapis.com/projects/test-project/regions/us-central1/subnetworks/default",
+			"state": "ACTIVE",
+			"category": "FLOW_LOGS_DISABLED",
+			"sourceProperties": {
+				"ProjectId": "test-project"
+			}
+		}
+	}`
+	f := &Finding{}
+	if name := f.Name([]byte(flowLogsDisabled)); name != "flow_logs_disabled" {
+		t.Errorf("got:%q want:%q", name, "flow_logs_disabled")
+	}
+	r, err := New([]byte(flowLogsDisabled))
+	if err != nil {
+		t.Fatalf("failed to read finding: %q", err)
+	}
+	want := &enableflowlogs.Values{ProjectID: "test-project", Region: "us-central1", Subnetwork: "default"}
+	if diff := cmp.Diff(r.EnableFlowLogs(), want); diff != "" {
+		t.Errorf("difference: %+v", diff)
+	}
+}
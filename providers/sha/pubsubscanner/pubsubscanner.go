@@ -0,0 +1,80 @@
+// Package pubsubscanner represents findings about publicly accessible Pub/Sub topics and subscriptions.
+package pubsubscanner
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"regexp"
+
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/pubsub/removepublicpubsub"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+)
+
+// reader reads the Pub/Sub findings supported by this provider, extracting the topic or
+// subscription and its project from the resource name.
+var reader = &findings.Reader{
+	Categories: []string{"PUBLIC_PUBSUB_TOPIC", "PUBLIC_PUBSUB_SUBSCRIPTION"},
+	Resource:   regexp.MustCompile(`^//pubsub\.googleapis\.com/(?P<path>projects/(?P<project>[^/]+)/(?:topics|subscriptions)/[^/]+)$`),
+}
+
+// Finding represents this finding.
+type Finding struct {
+	pubsub pubsubFinding
+}
+
+// pubsubFinding holds the fields of the finding used by this provider.
+type pubsubFinding struct {
+	findings.Finding
+	Resource  string `finding:"resource.path"`
+	ProjectID string `finding:"resource.project"`
+}
+
+// Name returns the rule name of the finding.
+func (f *Finding) Name(b []byte) string {
+	return reader.Name(b)
+}
+
+// New returns a new finding.
+func New(b []byte) (*Finding, error) {
+	var f Finding
+	if err := reader.Read(b, &f.pubsub); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.pubsub.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.pubsub.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.pubsub.SecurityMarks
+}
+
+// RemovePublic returns values for the remove public Pub/Sub access automation.
+func (f *Finding) RemovePublic() *removepublicpubsub.Values {
+	return &removepublicpubsub.Values{
+		ProjectID:   f.pubsub.ProjectID,
+		Resource:    f.pubsub.Resource,
+		FindingName: f.pubsub.Name,
+	}
+}
@@ -0,0 +1,100 @@
+package pubsubscanner
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/pubsub/removepublicpubsub"
+)
+
+func TestReadFinding(t *testing.T) {
+	const (
+		publicTopic = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/p1",
+				"resourceName": "//pubsub.googleapis.com/projects/test-project/topics/public-topic",
+				"state": "ACTIVE",
+				"category": "PUBLIC_PUBSUB_TOPIC"
+			}
+		}`
+		publicSubscription = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/p2",
+				"resourceName": "//pubsub.googleapis.com/projects/test-project/subscriptions/public-sub",
+				"state": "ACTIVE",
+				"category": "PUBLIC_PUBSUB_SUBSCRIPTION"
+			}
+		}`
+		wrongCategory = `{
+			"finding": {
+				"resourceName": "//pubsub.googleapis.com/projects/test-project/topics/public-topic",
+				"category": "PUBLIC_BUCKET_ACL"
+			}
+		}`
+		mismatchedResource = `{
+			"finding": {
+				"resourceName": "//storage.googleapis.com/public-bucket",
+				"category": "PUBLIC_PUBSUB_TOPIC"
+			}
+		}`
+	)
+	for _, tt := range []struct {
+		name, ruleName string
+		bytes          []byte
+		values         *removepublicpubsub.Values
+	}{
+		{
+			name:     "public topic",
+			ruleName: "public_pubsub_topic",
+			bytes:    []byte(publicTopic),
+			values: &removepublicpubsub.Values{
+				Resource:    "projects/test-project/topics/public-topic",
+				ProjectID:   "test-project",
+				FindingName: "organizations/154584661726/sources/2673592633662526977/findings/p1",
+			},
+		},
+		{
+			name:     "public subscription",
+			ruleName: "public_pubsub_subscription",
+			bytes:    []byte(publicSubscription),
+			values: &removepublicpubsub.Values{
+				Resource:    "projects/test-project/subscriptions/public-sub",
+				ProjectID:   "test-project",
+				FindingName: "organizations/154584661726/sources/2673592633662526977/findings/p2",
+			},
+		},
+		{name: "wrong category", ruleName: "", bytes: []byte(wrongCategory)},
+		{name: "mismatched resource", ruleName: "", bytes: []byte(mismatchedResource)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Finding{}
+			if name := f.Name(tt.bytes); name != tt.ruleName {
+				t.Errorf("%s failed: got:%q want:%q", tt.name, name, tt.ruleName)
+			}
+			if tt.values == nil {
+				return
+			}
+			r, err := New(tt.bytes)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(r.RemovePublic(), tt.values); diff != "" {
+				t.Errorf("%s failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Package serverlessscanner represents findings about publicly invokable Cloud Functions and Cloud Run services.
+package serverlessscanner
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/serverless/enforceauthentication"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/serverless/removepublicinvoker"
+	"github.com/googlecloudplatform/security-response-automation/findings"
+	"github.com/pkg/errors"
+)
+
+// readers read the findings supported by this provider, each category on the resources it is
+// reported on, extracting the function or service from the resource name.
+var readers = []*findings.Reader{
+	{
+		Categories: []string{"PUBLIC_CLOUD_FUNCTION"},
+		Resource:   regexp.MustCompile(`^//cloudfunctions\.googleapis\.com/(?P<resource>.+)$`),
+	},
+	{
+		Categories: []string{"PUBLIC_CLOUD_RUN_SERVICE"},
+		Resource:   regexp.MustCompile(`^//run\.googleapis\.com/(?P<resource>.+)$`),
+	},
+}
+
+// Finding represents this finding.
+type Finding struct {
+	serverless serverlessFinding
+}
+
+// serverlessFinding holds the fields of the finding used by this provider.
+type serverlessFinding struct {
+	findings.Finding
+	ProjectID string `finding:"sourceProperties.ProjectId"`
+	Resource  string `finding:"resource.resource"`
+}
+
+// Name returns the rule name of the finding.
+func (f *Finding) Name(b []byte) string {
+	for _, r := range readers {
+		if name := r.Name(b); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// New returns a new finding.
+func New(b []byte) (*Finding, error) {
+	for _, r := range readers {
+		var f Finding
+		err := r.Read(b, &f.serverless)
+		if errors.Cause(err) == findings.ErrUnsupportedFinding {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &f, nil
+	}
+	return nil, errors.Wrap(findings.ErrUnsupportedFinding, "not a public cloud function or cloud run service")
+}
+
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.serverless.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.serverless.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.serverless.SecurityMarks
+}
+
+// RemovePublicInvoker returns values for the remove public invoker automation.
+func (f *Finding) RemovePublicInvoker() *removepublicinvoker.Values {
+	return &removepublicinvoker.Values{
+		ProjectID:    f.serverless.ProjectID,
+		ResourceName: f.serverless.Resource,
+	}
+}
+
+// EnforceAuthentication returns values for the enforce authentication automation.
+func (f *Finding) EnforceAuthentication() *enforceauthentication.Values {
+	name := f.serverless.Name
+	return &enforceauthentication.Values{
+		ProjectID:    f.serverless.ProjectID,
+		ResourceName: f.serverless.Resource,
+		FindingID:    name[strings.LastIndex(name, "/")+1:],
+	}
+}
@@ -0,0 +1,123 @@
+package serverlessscanner
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/serverless/enforceauthentication"
+	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/serverless/removepublicinvoker"
+)
+
+func TestReadFinding(t *testing.T) {
+	const (
+		publicFunction = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/f1",
+				"resourceName": "//cloudfunctions.googleapis.com/projects/test-project/locations/us-central1/functions/public-function",
+				"state": "ACTIVE",
+				"category": "PUBLIC_CLOUD_FUNCTION",
+				"sourceProperties": {
+					"ProjectId": "test-project"
+				}
+			}
+		}`
+		publicService = `{
+			"finding": {
+				"name": "organizations/154584661726/sources/2673592633662526977/findings/r1",
+				"resourceName": "//run.googleapis.com/projects/test-project/locations/us-central1/services/public-service",
+				"state": "ACTIVE",
+				"category": "PUBLIC_CLOUD_RUN_SERVICE",
+				"sourceProperties": {
+					"ProjectId": "test-project"
+				}
+			}
+		}`
+		mismatchedResource = `{
+			"finding": {
+				"resourceName": "//storage.googleapis.com/public-bucket",
+				"category": "PUBLIC_CLOUD_FUNCTION"
+			}
+		}`
+	)
+	for _, tt := range []struct {
+		name, ruleName string
+		bytes          []byte
+		values         *removepublicinvoker.Values
+	}{
+		{
+			name:     "public cloud function",
+			ruleName: "public_cloud_function",
+			bytes:    []byte(publicFunction),
+			values: &removepublicinvoker.Values{
+				ProjectID:    "test-project",
+				ResourceName: "projects/test-project/locations/us-central1/functions/public-function",
+			},
+		},
+		{
+			name:     "public cloud run service",
+			ruleName: "public_cloud_run_service",
+			bytes:    []byte(publicService),
+			values: &removepublicinvoker.Values{
+				ProjectID:    "test-project",
+				ResourceName: "projects/test-project/locations/us-central1/services/public-service",
+			},
+		},
+		{name: "mismatched resource", ruleName: "", bytes: []byte(mismatchedResource)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Finding{}
+			if name := f.Name(tt.bytes); name != tt.ruleName {
+				t.Errorf("%s failed: got:%q want:%q", tt.name, name, tt.ruleName)
+			}
+			if tt.values == nil {
+				return
+			}
+			r, err := New(tt.bytes)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(r.RemovePublicInvoker(), tt.values); diff != "" {
+				t.Errorf("%s failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestEnforceAuthenticationValues(t *testing.T) {
+	const publicService = `{
+		"finding": {
+			"name": "organizations/154584661726/sources/2673592633662526977/findings/r1",
+			"resourceName": "//run.googleapis.com/projects/test-project/locations/us-central1/services/public-service",
+			"category": "PUBLIC_CLOUD_RUN_SERVICE",
+			"sourceProperties": {
+				"ProjectId": "test-project"
+			}
+		}
+	}`
+	r, err := New([]byte(publicService))
+	if err != nil {
+		t.Fatalf("failed to read finding: %q", err)
+	}
+	want := &enforceauthentication.Values{
+		ProjectID:    "test-project",
+		ResourceName: "projects/test-project/locations/us-central1/services/public-service",
+		FindingID:    "r1",
+	}
+	if diff := cmp.Diff(r.EnforceAuthentication(), want); diff != "" {
+		t.Errorf("enforce authentication values difference: %+v", diff)
+	}
+}
@@ -1,15 +1,11 @@
 package sqlscanner
 
 import (
-	"encoding/json"
-	"strings"
-
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/cloud-sql/removepublic"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/cloud-sql/requiressl"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/cloud-sql/updatepassword"
-	pb "github.com/googlecloudplatform/security-response-automation/compiled/sha/protos"
+	"github.com/googlecloudplatform/security-response-automation/findings"
 	"github.com/googlecloudplatform/security-response-automation/providers/sha"
-	"github.com/googlecloudplatform/security-response-automation/services"
 )
 
 const (
@@ -19,59 +15,73 @@ const (
 	userName = "root"
 )
 
+// reader reads the findings of the SQL_SCANNER of Security Health Analytics.
+var reader = &findings.Reader{
+	Properties: map[string]string{"ScannerName": "SQL_SCANNER"},
+}
+
 // Finding represents this finding.
 type Finding struct {
-	SQLScanner *pb.SqlScanner
+	sql sqlFinding
+}
+
+// sqlFinding holds the fields of the finding used by this provider.
+type sqlFinding struct {
+	findings.Finding
+	ProjectID string `finding:"sourceProperties.ProjectId"`
 }
 
 // Name returns the rule name of the finding.
 func (f *Finding) Name(b []byte) string {
-	var finding pb.SqlScanner
-	if err := json.Unmarshal(b, &finding); err != nil {
-		return ""
-	}
-	if finding.GetFinding().GetSourceProperties().GetScannerName() != "SQL_SCANNER" {
-		return ""
-	}
-	return strings.ToLower(finding.GetFinding().GetCategory())
+	return reader.Name(b)
 }
 
 // New returns a new finding.
 func New(b []byte) (*Finding, error) {
 	var f Finding
-	if err := json.Unmarshal(b, &f.SQLScanner); err != nil {
+	if err := reader.Read(b, &f.sql); err != nil {
 		return nil, err
 	}
 	return &f, nil
 }
 
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.sql.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.sql.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.sql.SecurityMarks
+}
+
 // RemovePublic returns values for the remove public automation.
 func (f *Finding) RemovePublic() *removepublic.Values {
 	return &removepublic.Values{
-		ProjectID:    f.SQLScanner.GetFinding().GetSourceProperties().GetProjectID(),
-		InstanceName: sha.Instance(f.SQLScanner.GetFinding().GetResourceName()),
+		ProjectID:    f.sql.ProjectID,
+		InstanceName: sha.Instance(f.sql.ResourceName),
 	}
 }
 
-// UpdatePassword returns values for the update password automation.
-func (f *Finding) UpdatePassword() (*updatepassword.Values, error) {
-	password, err := services.GeneratePassword()
-	if err != nil {
-		return nil, err
-	}
+// UpdatePassword returns values for the update password automation, which generates the password.
+func (f *Finding) UpdatePassword() *updatepassword.Values {
 	return &updatepassword.Values{
-		ProjectID:    f.SQLScanner.GetFinding().GetSourceProperties().GetProjectID(),
-		InstanceName: sha.Instance(f.SQLScanner.GetFinding().GetResourceName()),
+		ProjectID:    f.sql.ProjectID,
+		InstanceName: sha.Instance(f.sql.ResourceName),
 		Host:         hostWildcard,
 		UserName:     userName,
-		Password:     password,
-	}, nil
+	}
 }
 
 // RequireSSL returns values for the require SSL automation.
 func (f *Finding) RequireSSL() *requiressl.Values {
 	return &requiressl.Values{
-		ProjectID:    f.SQLScanner.GetFinding().GetSourceProperties().GetProjectID(),
-		InstanceName: sha.Instance(f.SQLScanner.GetFinding().GetResourceName()),
+		ProjectID:    f.sql.ProjectID,
+		InstanceName: sha.Instance(f.sql.ResourceName),
 	}
 }
@@ -1,53 +1,70 @@
 package storagescanner
 
 import (
-	"encoding/json"
-	"strings"
-
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gcs/closebucket"
 	"github.com/googlecloudplatform/security-response-automation/cloudfunctions/gcs/enablebucketonlypolicy"
-	pb "github.com/googlecloudplatform/security-response-automation/compiled/sha/protos"
+	"github.com/googlecloudplatform/security-response-automation/findings"
 	"github.com/googlecloudplatform/security-response-automation/providers/sha"
 )
 
+// reader reads the findings of the STORAGE_SCANNER of Security Health Analytics.
+var reader = &findings.Reader{
+	Properties: map[string]string{"ScannerName": "STORAGE_SCANNER"},
+}
+
 // Finding represents this finding.
 type Finding struct {
-	StorageScanner *pb.StorageScanner
+	storage storageFinding
+}
+
+// storageFinding holds the fields of the finding used by this provider.
+type storageFinding struct {
+	findings.Finding
+	ProjectID string `finding:"sourceProperties.ProjectId"`
 }
 
 // Name returns the rule name of the finding.
 func (f *Finding) Name(b []byte) string {
-	var finding pb.StorageScanner
-	if err := json.Unmarshal(b, &finding); err != nil {
-		return ""
-	}
-	if finding.GetFinding().GetSourceProperties().GetScannerName() != "STORAGE_SCANNER" {
-		return ""
-	}
-	return strings.ToLower(finding.GetFinding().GetCategory())
+	return reader.Name(b)
 }
 
 // New returns a new finding.
 func New(b []byte) (*Finding, error) {
 	var f Finding
-	if err := json.Unmarshal(b, &f.StorageScanner); err != nil {
+	if err := reader.Read(b, &f.storage); err != nil {
 		return nil, err
 	}
 	return &f, nil
 }
 
+// FindingName returns the Security Command Center name of the finding.
+func (f *Finding) FindingName() string {
+	return f.storage.Name
+}
+
+// EventTime returns the event time of the finding.
+func (f *Finding) EventTime() string {
+	return f.storage.EventTime
+}
+
+// SecurityMarks returns the security marks of the finding.
+func (f *Finding) SecurityMarks() map[string]string {
+	return f.storage.SecurityMarks
+}
+
 // EnableBucketOnlyPolicy returns values for the enable bucket only policy automation.
 func (f *Finding) EnableBucketOnlyPolicy() *enablebucketonlypolicy.Values {
 	return &enablebucketonlypolicy.Values{
-		ProjectID:  f.StorageScanner.GetFinding().GetSourceProperties().GetProjectId(),
-		BucketName: sha.BucketName(f.StorageScanner.GetFinding().GetResourceName()),
+		ProjectID:  f.storage.ProjectID,
+		BucketName: sha.BucketName(f.storage.ResourceName),
 	}
 }
 
 // CloseBucket returns values for the close bucket automation.
 func (f *Finding) CloseBucket() *closebucket.Values {
 	return &closebucket.Values{
-		ProjectID:  f.StorageScanner.GetFinding().GetSourceProperties().GetProjectId(),
-		BucketName: sha.BucketName(f.StorageScanner.GetFinding().GetResourceName()),
+		ProjectID:   f.storage.ProjectID,
+		BucketName:  sha.BucketName(f.storage.ResourceName),
+		FindingName: f.storage.Name,
 	}
 }
@@ -0,0 +1,139 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+// AdminClient contains minimum interface required by the service.
+type AdminClient interface {
+	GetUser(context.Context, string) (*admin.User, error)
+	UpdateUser(context.Context, string, *admin.User) (*admin.User, error)
+	SignOutUser(context.Context, string) error
+	ListTokens(context.Context, string) (*admin.Tokens, error)
+	DeleteToken(context.Context, string, string) error
+	ListASPs(context.Context, string) (*admin.Asps, error)
+	DeleteASP(context.Context, string, int64) error
+}
+
+// Admin service manages Google Workspace users.
+type Admin struct {
+	client AdminClient
+}
+
+// NewAdmin returns an Admin service.
+func NewAdmin(client AdminClient) *Admin {
+	return &Admin{client: client}
+}
+
+// User returns the Google Workspace user.
+func (a *Admin) User(ctx context.Context, email string) (*admin.User, error) {
+	u, err := a.client.GetUser(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %q", err)
+	}
+	return u, nil
+}
+
+// SuspendUser suspends the user and revokes their sign-in cookies so existing sessions end.
+func (a *Admin) SuspendUser(ctx context.Context, email string) error {
+	if _, err := a.client.UpdateUser(ctx, email, &admin.User{Suspended: true}); err != nil {
+		return fmt.Errorf("failed to suspend user: %q", err)
+	}
+	if err := a.client.SignOutUser(ctx, email); err != nil {
+		return fmt.Errorf("failed to sign out user: %q", err)
+	}
+	return nil
+}
+
+// ForceReenrollment requires the user to change their password at next sign-in and signs them out
+// so the change takes effect immediately. If orgUnit is set the user is also moved to it, which is
+// expected to be an organizational unit enforcing 2-Step Verification.
+func (a *Admin) ForceReenrollment(ctx context.Context, email, orgUnit string) error {
+	if _, err := a.client.UpdateUser(ctx, email, &admin.User{ChangePasswordAtNextLogin: true, OrgUnitPath: orgUnit}); err != nil {
+		return fmt.Errorf("failed to update user: %q", err)
+	}
+	if err := a.client.SignOutUser(ctx, email); err != nil {
+		return fmt.Errorf("failed to sign out user: %q", err)
+	}
+	return nil
+}
+
+// Tokens returns the OAuth 2.0 tokens the user has issued to applications.
+func (a *Admin) Tokens(ctx context.Context, email string) ([]*admin.Token, error) {
+	t, err := a.client.ListTokens(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tokens: %q", err)
+	}
+	return t.Items, nil
+}
+
+// RevokeTokens revokes every OAuth 2.0 token the user has issued and returns the applications
+// they were issued to.
+func (a *Admin) RevokeTokens(ctx context.Context, email string) ([]string, error) {
+	tokens, err := a.Tokens(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	revoked := []string{}
+	for _, t := range tokens {
+		if err := a.client.DeleteToken(ctx, email, t.ClientId); err != nil {
+			return revoked, fmt.Errorf("failed to revoke token for %q: %q", t.ClientId, err)
+		}
+		revoked = append(revoked, t.DisplayText)
+	}
+	return revoked, nil
+}
+
+// ASPs returns the application-specific passwords of the user.
+func (a *Admin) ASPs(ctx context.Context, email string) ([]*admin.Asp, error) {
+	asps, err := a.client.ListASPs(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list application-specific passwords: %q", err)
+	}
+	return asps.Items, nil
+}
+
+// RevokeASPs deletes every application-specific password of the user and returns their names.
+func (a *Admin) RevokeASPs(ctx context.Context, email string) ([]string, error) {
+	asps, err := a.ASPs(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	revoked := []string{}
+	for _, asp := range asps {
+		if err := a.client.DeleteASP(ctx, email, asp.CodeId); err != nil {
+			return revoked, fmt.Errorf("failed to delete application-specific password %q: %q", asp.Name, err)
+		}
+		revoked = append(revoked, asp.Name)
+	}
+	return revoked, nil
+}
+
+// InOrgUnits returns whether the user is in one of, or nested within one of, the given
+// organizational units.
+func (a *Admin) InOrgUnits(user *admin.User, orgUnits []string) bool {
+	for _, ou := range orgUnits {
+		ou = strings.TrimSuffix(ou, "/")
+		if ou == "" || user.OrgUnitPath == ou || strings.HasPrefix(user.OrgUnitPath, ou+"/") {
+			return true
+		}
+	}
+	return false
+}
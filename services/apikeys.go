@@ -0,0 +1,52 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+)
+
+// APIKeysClient contains minimum interface required by the service.
+type APIKeysClient interface {
+	DeleteKey(context.Context, string) error
+	UpdateKeyRestrictions(context.Context, string, []string, []string, []string) error
+}
+
+// APIKeys service.
+type APIKeys struct {
+	client APIKeysClient
+}
+
+// NewAPIKeys returns an API Keys service.
+func NewAPIKeys(client APIKeysClient) *APIKeys {
+	return &APIKeys{client: client}
+}
+
+// DeleteKey deletes the API key.
+func (a *APIKeys) DeleteKey(ctx context.Context, name string) error {
+	if err := a.client.DeleteKey(ctx, name); err != nil {
+		return fmt.Errorf("failed to delete api key: %q", err)
+	}
+	return nil
+}
+
+// RestrictKey replaces the restrictions of the API key with the given referrers or IPs and APIs.
+func (a *APIKeys) RestrictKey(ctx context.Context, name string, referrers, ips, apis []string) error {
+	if err := a.client.UpdateKeyRestrictions(ctx, name, referrers, ips, apis); err != nil {
+		return fmt.Errorf("failed to restrict api key: %q", err)
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	firestore "google.golang.org/api/firestore/v1"
+)
+
+// approvalCollection is the Firestore collection holding pending actions.
+const approvalCollection = "automation-approvals"
+
+// MinApprovalKeyLength is the minimum length of the key signing approval links, shorter keys
+// could be guessed to forge links.
+const MinApprovalKeyLength = 32
+
+// Statuses of a pending action.
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalDenied   = "denied"
+)
+
+// Decisions an approver can make.
+const (
+	DecisionApprove = "approve"
+	DecisionDeny    = "deny"
+)
+
+// ApprovalDocumentClient contains minimum interface required by the approval service.
+type ApprovalDocumentClient interface {
+	CreateDocument(ctx context.Context, projectID, collection, documentID string, fields map[string]firestore.Value) error
+	GetDocument(ctx context.Context, projectID, collection, documentID string) (*firestore.Document, error)
+	UpdateDocument(ctx context.Context, projectID, collection, documentID string, fields map[string]firestore.Value, updateTime string) error
+}
+
+// Approvals service holds remediations until they are approved.
+type Approvals struct {
+	documents ApprovalDocumentClient
+	projectID string
+	key       []byte
+}
+
+// PendingAction is a remediation waiting for approval before it is published to its topic.
+type PendingAction struct {
+	ID        string
+	Action    string
+	Topic     string
+	FindingID string
+	// Severity is the severity of the finding, if it has one.
+	Severity string
+	// Category is the rule the finding matched, such as "bad_ip".
+	Category string
+	// ServiceAccount is the remediator service account the action runs as, if any.
+	ServiceAccount string
+	// Data is the message published to the topic once approved.
+	Data []byte
+	// Reporter is the principal identified by the finding, who can't approve the action.
+	Reporter string
+	// Required is the number of distinct approvers needed, at least one.
+	Required int
+	// Approvers are the approvers who approved the action so far.
+	Approvers []string
+	// Notify are the notification channels the action notifies of its outcome once it runs.
+	Notify  []string
+	Expires time.Time
+	Status  string
+	// updateTime is the version of the document the action was read from.
+	updateTime string
+}
+
+// Approved returns whether the action was approved.
+func (p *PendingAction) Approved() bool {
+	return p.Status == ApprovalApproved
+}
+
+// NewApprovals returns an approval service storing pending actions in the automation project.
+// The key signs the approval links.
+func NewApprovals(documents ApprovalDocumentClient, projectID string, key []byte) *Approvals {
+	return &Approvals{documents: documents, projectID: projectID, key: key}
+}
+
+// Request stores the action as pending until it expires after the TTL.
+func (a *Approvals) Request(ctx context.Context, p *PendingAction, ttl time.Duration, now time.Time) error {
+	p.ID = uuid.New().String()
+	p.Expires = now.Add(ttl).UTC()
+	p.Status = ApprovalPending
+	if p.Required < 1 {
+		p.Required = 1
+	}
+	if err := a.documents.CreateDocument(ctx, a.projectID, approvalCollection, p.ID, pendingFields(p)); err != nil {
+		return errors.Wrapf(err, "failed to store pending action %q", p.Action)
+	}
+	return nil
+}
+
+func pendingFields(p *PendingAction) map[string]firestore.Value {
+	return map[string]firestore.Value{
+		"action":          {StringValue: p.Action},
+		"topic":           {StringValue: p.Topic},
+		"finding_id":      {StringValue: p.FindingID},
+		"severity":        {StringValue: p.Severity},
+		"category":        {StringValue: p.Category},
+		"service_account": {StringValue: p.ServiceAccount},
+		"data":            {StringValue: string(p.Data)},
+		"reporter":        {StringValue: p.Reporter},
+		"required":        {IntegerValue: int64(p.Required)},
+		"approvers":       arrayValue(p.Approvers),
+		"notify":          arrayValue(p.Notify),
+		"expires":         {TimestampValue: p.Expires.Format(time.RFC3339Nano)},
+		"status":          {StringValue: p.Status},
+	}
+}
+
+// Link returns the signed URL the approver follows to make the decision on the pending action.
+// The link expires with the action.
+func (a *Approvals) Link(baseURL string, p *PendingAction, decision, approver string) string {
+	v := url.Values{}
+	v.Set("id", p.ID)
+	v.Set("decision", decision)
+	v.Set("approver", approver)
+	v.Set("expires", strconv.FormatInt(p.Expires.Unix(), 10))
+	v.Set("signature", a.sign(p.ID, decision, approver, p.Expires.Unix()))
+	return baseURL + "?" + v.Encode()
+}
+
+// Verify checks the link's query values were signed by this service and have not expired.
+func (a *Approvals) Verify(id, decision, approver, expires, signature string, now time.Time) error {
+	e, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid expiry %q", expires)
+	}
+	if len(a.key) == 0 {
+		return errors.New("no key to verify the signature with")
+	}
+	if !hmac.Equal([]byte(a.sign(id, decision, approver, e)), []byte(signature)) {
+		return errors.New("invalid signature")
+	}
+	if now.Unix() > e {
+		return errors.New("link expired")
+	}
+	return nil
+}
+
+func (a *Approvals) sign(id, decision, approver string, expires int64) string {
+	m := hmac.New(sha256.New, a.key)
+	fmt.Fprintf(m, "%s\n%s\n%s\n%d", id, decision, approver, expires)
+	return hex.EncodeToString(m.Sum(nil))
+}
+
+// Pending returns the pending action.
+func (a *Approvals) Pending(ctx context.Context, id string) (*PendingAction, error) {
+	d, err := a.documents.GetDocument(ctx, a.projectID, approvalCollection, id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get pending action %q", id)
+	}
+	expires, err := time.Parse(time.RFC3339Nano, d.Fields["expires"].TimestampValue)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid expiry of pending action %q", id)
+	}
+	required := int(d.Fields["required"].IntegerValue)
+	if required < 1 {
+		required = 1
+	}
+	return &PendingAction{
+		ID:             id,
+		Action:         d.Fields["action"].StringValue,
+		Topic:          d.Fields["topic"].StringValue,
+		FindingID:      d.Fields["finding_id"].StringValue,
+		Severity:       d.Fields["severity"].StringValue,
+		Category:       d.Fields["category"].StringValue,
+		ServiceAccount: d.Fields["service_account"].StringValue,
+		Data:           []byte(d.Fields["data"].StringValue),
+		Reporter:       d.Fields["reporter"].StringValue,
+		Required:       required,
+		Approvers:      arrayStrings(d.Fields["approvers"]),
+		Notify:         arrayStrings(d.Fields["notify"]),
+		Expires:        expires,
+		Status:         d.Fields["status"].StringValue,
+		updateTime:     d.UpdateTime,
+	}, nil
+}
+
+// Decide records the approver's decision on the pending action. A single denial denies the
+// action while it is only approved once the required number of distinct approvers approved it.
+// Actions already decided or expired can't be decided again and the reporter of the finding can't
+// approve the action.
+func (a *Approvals) Decide(ctx context.Context, p *PendingAction, decision, approver string, now time.Time) error {
+	if p.Status != ApprovalPending {
+		return fmt.Errorf("pending action %q was already %s", p.ID, p.Status)
+	}
+	if now.After(p.Expires) {
+		return fmt.Errorf("pending action %q expired at %s", p.ID, p.Expires.Format(time.RFC3339))
+	}
+	switch decision {
+	case DecisionApprove:
+		if approver == "" || strings.EqualFold(approver, p.Reporter) {
+			return fmt.Errorf("%q can't approve pending action %q", approver, p.ID)
+		}
+		for _, a := range p.Approvers {
+			if strings.EqualFold(a, approver) {
+				return fmt.Errorf("%q already approved pending action %q", approver, p.ID)
+			}
+		}
+		p.Approvers = append(p.Approvers, approver)
+		if len(p.Approvers) >= p.Required {
+			p.Status = ApprovalApproved
+		}
+	case DecisionDeny:
+		p.Status = ApprovalDenied
+	default:
+		return fmt.Errorf("unknown decision %q", decision)
+	}
+	fields := pendingFields(p)
+	if err := a.documents.UpdateDocument(ctx, a.projectID, approvalCollection, p.ID, map[string]firestore.Value{
+		"status":    fields["status"],
+		"approvers": fields["approvers"],
+	}, p.updateTime); err != nil {
+		return errors.Wrapf(err, "failed to update pending action %q", p.ID)
+	}
+	return nil
+}
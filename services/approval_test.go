@@ -0,0 +1,177 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+)
+
+func TestApprovalLink(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := NewApprovals(&stubs.FirestoreStub{}, "automation-project", []byte("secret"))
+	p := &PendingAction{ID: "123", Expires: now.Add(time.Hour)}
+	link, err := url.Parse(a.Link("https://approve.example.com", p, DecisionApprove, "alice@cloudorg.com"))
+	if err != nil {
+		t.Fatalf("failed to parse link: %q", err)
+	}
+	q := link.Query()
+	for _, tt := range []struct {
+		name     string
+		approver string
+		decision string
+		key      string
+		now      time.Time
+		wantErr  bool
+	}{
+		{name: "valid", approver: "alice@cloudorg.com", decision: DecisionApprove, key: "secret", now: now},
+		{name: "other approver", approver: "bob@cloudorg.com", decision: DecisionApprove, key: "secret", now: now, wantErr: true},
+		{name: "other decision", approver: "alice@cloudorg.com", decision: DecisionDeny, key: "secret", now: now, wantErr: true},
+		{name: "other key", approver: "alice@cloudorg.com", decision: DecisionApprove, key: "other", now: now, wantErr: true},
+		{name: "expired", approver: "alice@cloudorg.com", decision: DecisionApprove, key: "secret", now: now.Add(2 * time.Hour), wantErr: true},
+		{name: "no key", approver: "alice@cloudorg.com", decision: DecisionApprove, key: "", now: now, wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewApprovals(&stubs.FirestoreStub{}, "automation-project", []byte(tt.key))
+			err := a.Verify(q.Get("id"), tt.decision, tt.approver, q.Get("expires"), q.Get("signature"), tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("%s failed: got err %v want err %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+	if got := q.Get("expires"); got != strconv.FormatInt(p.Expires.Unix(), 10) {
+		t.Errorf("unexpected expiry %q", got)
+	}
+}
+
+func TestInitApprovalsKey(t *testing.T) {
+	for _, key := range []string{"", "secret"} {
+		if _, err := InitApprovals(context.Background(), "automation-project", key); err == nil {
+			t.Errorf("accepted approval key %q", key)
+		}
+	}
+}
+
+func TestApprovalDecide(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	type decision struct {
+		approver string
+		decision string
+		wantErr  bool
+	}
+	for _, tt := range []struct {
+		name          string
+		required      int
+		decisions     []decision
+		now           time.Time
+		wantStatus    string
+		wantApprovers []string
+	}{
+		{
+			name:          "approve",
+			decisions:     []decision{{approver: "alice@cloudorg.com", decision: DecisionApprove}},
+			now:           now,
+			wantStatus:    ApprovalApproved,
+			wantApprovers: []string{"alice@cloudorg.com"},
+		},
+		{
+			name:       "deny",
+			decisions:  []decision{{approver: "alice@cloudorg.com", decision: DecisionDeny}},
+			now:        now,
+			wantStatus: ApprovalDenied,
+		},
+		{
+			name: "decided twice",
+			decisions: []decision{
+				{approver: "alice@cloudorg.com", decision: DecisionDeny},
+				{approver: "bob@cloudorg.com", decision: DecisionApprove, wantErr: true},
+			},
+			now:        now,
+			wantStatus: ApprovalDenied,
+		},
+		{
+			name:       "expired",
+			decisions:  []decision{{approver: "alice@cloudorg.com", decision: DecisionApprove, wantErr: true}},
+			now:        now.Add(2 * time.Hour),
+			wantStatus: ApprovalPending,
+		},
+		{
+			name:       "reporter can't approve",
+			decisions:  []decision{{approver: "Mallory@cloudorg.com", decision: DecisionApprove, wantErr: true}},
+			now:        now,
+			wantStatus: ApprovalPending,
+		},
+		{
+			name:     "two approvers",
+			required: 2,
+			decisions: []decision{
+				{approver: "alice@cloudorg.com", decision: DecisionApprove},
+				{approver: "alice@cloudorg.com", decision: DecisionApprove, wantErr: true},
+				{approver: "bob@cloudorg.com", decision: DecisionApprove},
+			},
+			now:           now,
+			wantStatus:    ApprovalApproved,
+			wantApprovers: []string{"alice@cloudorg.com", "bob@cloudorg.com"},
+		},
+		{
+			name:          "one of two approvers",
+			required:      2,
+			decisions:     []decision{{approver: "alice@cloudorg.com", decision: DecisionApprove}},
+			now:           now,
+			wantStatus:    ApprovalPending,
+			wantApprovers: []string{"alice@cloudorg.com"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewApprovals(&stubs.FirestoreStub{}, "automation-project", []byte("secret"))
+			p := &PendingAction{
+				Action:    "disable_billing",
+				Topic:     "threat-findings-disable-billing",
+				FindingID: "finding-1",
+				Data:      []byte(`{"ProjectID":"test-project"}`),
+				Reporter:  "mallory@cloudorg.com",
+				Required:  tt.required,
+			}
+			if err := a.Request(ctx, p, time.Hour, now); err != nil {
+				t.Fatalf("failed to request approval: %q", err)
+			}
+			for _, d := range tt.decisions {
+				pending, err := a.Pending(ctx, p.ID)
+				if err != nil {
+					t.Fatalf("failed to get pending action: %q", err)
+				}
+				if err := a.Decide(ctx, pending, d.decision, d.approver, tt.now); (err != nil) != d.wantErr {
+					t.Errorf("%s failed: %s got err %v want err %v", tt.name, d.approver, err, d.wantErr)
+				}
+			}
+			pending, err := a.Pending(ctx, p.ID)
+			if err != nil {
+				t.Fatalf("failed to get pending action: %q", err)
+			}
+			if pending.Status != tt.wantStatus || string(pending.Data) != `{"ProjectID":"test-project"}` {
+				t.Errorf("%s failed: got %+v", tt.name, pending)
+			}
+			if diff := cmp.Diff(tt.wantApprovers, pending.Approvers); diff != "" {
+				t.Errorf("%s failed, approvers (-want +got):\n%s", tt.name, diff)
+			}
+		})
+	}
+}
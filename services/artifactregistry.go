@@ -0,0 +1,122 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	artifactregistry "google.golang.org/api/artifactregistry/v1beta1"
+)
+
+// ArtifactRegistryClient contains minimum interface required by the service.
+type ArtifactRegistryClient interface {
+	GetRepositoryPolicy(context.Context, string) (*artifactregistry.Policy, error)
+	SetRepositoryPolicy(context.Context, string, *artifactregistry.Policy) (*artifactregistry.Policy, error)
+	ListTags(context.Context, string) ([]*artifactregistry.Tag, error)
+	CreateTag(context.Context, string, string, *artifactregistry.Tag) (*artifactregistry.Tag, error)
+	UpdateTagVersion(context.Context, string, string) (*artifactregistry.Tag, error)
+	DeleteTag(context.Context, string) error
+}
+
+// dockerImage extracts the location, project, repository, image and digest of an Artifact Registry image URI.
+var dockerImage = regexp.MustCompile(`^([a-z0-9-]+)-docker\.pkg\.dev/([^/]+)/([^/]+)/([^@:]+)(?::[^@]+)?@(sha256:[a-f0-9]+)$`)
+
+// ArtifactRegistry service manages Artifact Registry repositories.
+type ArtifactRegistry struct {
+	client ArtifactRegistryClient
+}
+
+// NewArtifactRegistry returns an Artifact Registry service.
+func NewArtifactRegistry(client ArtifactRegistryClient) *ArtifactRegistry {
+	return &ArtifactRegistry{client: client}
+}
+
+// RemoveMembers removes the members from every role of the repository's policy.
+//
+// The repository is named "projects/<project>/locations/<location>/repositories/<repository>".
+// The members removed are returned and the policy is only updated if one was found.
+func (a *ArtifactRegistry) RemoveMembers(ctx context.Context, repository string, members []string) ([]string, error) {
+	policy, err := a.client.GetRepositoryPolicy(ctx, repository)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get policy of %q", repository)
+	}
+	removed := []string{}
+	for _, b := range policy.Bindings {
+		keep := []string{}
+		for _, m := range b.Members {
+			if containsMember(members, m) {
+				removed = append(removed, m)
+				continue
+			}
+			keep = append(keep, m)
+		}
+		b.Members = keep
+	}
+	if len(removed) == 0 {
+		return removed, nil
+	}
+	if _, err := a.client.SetRepositoryPolicy(ctx, repository, policy); err != nil {
+		return nil, errors.Wrapf(err, "failed to set policy of %q", repository)
+	}
+	return removed, nil
+}
+
+// QuarantineImage points the tag at the image's digest and removes every other tag of the digest
+// so it can no longer be pulled by tag. The image must be an Artifact Registry URI with a digest,
+// such as "us-docker.pkg.dev/<project>/<repository>/<image>@sha256:<digest>". The removed tags are returned.
+func (a *ArtifactRegistry) QuarantineImage(ctx context.Context, image, tag string) ([]string, error) {
+	m := dockerImage.FindStringSubmatch(image)
+	if m == nil {
+		return nil, fmt.Errorf("%q is not an artifact registry image with a digest", image)
+	}
+	pkg := fmt.Sprintf("projects/%s/locations/%s/repositories/%s/packages/%s", m[2], m[1], m[3], url.PathEscape(m[4]))
+	version := pkg + "/versions/" + m[5]
+	name := pkg + "/tags/" + tag
+	tags, err := a.client.ListTags(ctx, pkg)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list tags of %q", image)
+	}
+	removed := []string{}
+	exists := false
+	for _, t := range tags {
+		if t.Name == name {
+			exists = true
+			if t.Version != version {
+				if _, err := a.client.UpdateTagVersion(ctx, name, version); err != nil {
+					return removed, errors.Wrapf(err, "failed to update tag %q", name)
+				}
+			}
+			continue
+		}
+		if t.Version != version {
+			continue
+		}
+		if err := a.client.DeleteTag(ctx, t.Name); err != nil {
+			return removed, errors.Wrapf(err, "failed to delete tag %q", t.Name)
+		}
+		removed = append(removed, t.Name[strings.LastIndex(t.Name, "/")+1:])
+	}
+	if !exists {
+		if _, err := a.client.CreateTag(ctx, pkg, tag, &artifactregistry.Tag{Version: version}); err != nil {
+			return removed, errors.Wrapf(err, "failed to create tag %q", name)
+		}
+	}
+	return removed, nil
+}
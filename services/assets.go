@@ -0,0 +1,70 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	cloudasset "google.golang.org/api/cloudasset/v1"
+)
+
+// CloudAssetClient contains minimum interface required by the service.
+type CloudAssetClient interface {
+	SearchAllResources(context.Context, string, string) ([]*cloudasset.ResourceSearchResult, error)
+}
+
+// Assets service.
+type Assets struct {
+	client CloudAssetClient
+}
+
+// NewAssets returns an Assets service.
+func NewAssets(client CloudAssetClient) *Assets {
+	return &Assets{client: client}
+}
+
+// Labels returns the labels of the resource, looked up within the scope such as
+// projects/my-project or organizations/123. The resource is its full name, such as
+// //compute.googleapis.com/projects/my-project/zones/us-central1-a/instances/my-instance.
+func (a *Assets) Labels(ctx context.Context, scope, resourceName string) (map[string]string, error) {
+	results, err := a.client.SearchAllResources(ctx, scope, fmt.Sprintf("name:%q", resourceName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for %q: %q", resourceName, err)
+	}
+	// The name query matches resources whose name contains it, such as the disks of an instance.
+	for _, r := range results {
+		if r.Name == resourceName {
+			return r.Labels, nil
+		}
+	}
+	return nil, fmt.Errorf("resource %q not found in %q", resourceName, scope)
+}
+
+// MatchesLabels returns whether the labels have all labels of the target and none of the
+// excluded labels. A target or excluded label with an empty value matches any value of its key.
+func MatchesLabels(labels, target, exclude map[string]string) bool {
+	for k, v := range exclude {
+		if got, ok := labels[k]; ok && (v == "" || got == v) {
+			return false
+		}
+	}
+	for k, v := range target {
+		if got, ok := labels[k]; !ok || (v != "" && got != v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	cloudasset "google.golang.org/api/cloudasset/v1"
+)
+
+func TestLabels(t *testing.T) {
+	const instance = "//compute.googleapis.com/projects/my-project/zones/us-central1-a/instances/my-instance"
+	for _, tt := range []struct {
+		name    string
+		results []*cloudasset.ResourceSearchResult
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "labels of the resource",
+			results: []*cloudasset.ResourceSearchResult{
+				{Name: "//compute.googleapis.com/projects/my-project/zones/us-central1-a/disks/my-instance", Labels: map[string]string{"env": "dev"}},
+				{Name: instance, Labels: map[string]string{"env": "prod"}},
+			},
+			want: map[string]string{"env": "prod"},
+		},
+		{name: "resource not found", results: []*cloudasset.ResourceSearchResult{}, wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubs.CloudAssetStub{StubbedResults: tt.results}
+			got, err := NewAssets(stub).Labels(context.Background(), "projects/my-project", instance)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%s failed: got error %v", tt.name, err)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("%s failed, difference:%+v", tt.name, diff)
+			}
+			if stub.SavedScope != "projects/my-project" || stub.SavedQuery != `name:"`+instance+`"` {
+				t.Errorf("%s failed: searched %q in %q", tt.name, stub.SavedQuery, stub.SavedScope)
+			}
+		})
+	}
+}
+
+func TestMatchesLabels(t *testing.T) {
+	labels := map[string]string{"env": "prod", "managed-by": "terraform"}
+	for _, tt := range []struct {
+		name            string
+		target, exclude map[string]string
+		want            bool
+	}{
+		{name: "no selectors", want: true},
+		{name: "target matching", target: map[string]string{"env": "prod"}, want: true},
+		{name: "target not matching", target: map[string]string{"env": "dev"}},
+		{name: "target key only", target: map[string]string{"env": ""}, want: true},
+		{name: "target missing key", target: map[string]string{"team": ""}},
+		{name: "excluded", target: map[string]string{"env": "prod"}, exclude: map[string]string{"managed-by": "terraform"}},
+		{name: "excluded key only", exclude: map[string]string{"managed-by": ""}},
+		{name: "exclude not matching", exclude: map[string]string{"managed-by": "manual"}, want: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchesLabels(labels, tt.target, tt.exclude); got != tt.want {
+				t.Errorf("%s failed: got %t want %t", tt.name, got, tt.want)
+			}
+		})
+	}
+}
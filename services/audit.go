@@ -0,0 +1,318 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/clients"
+	crm "google.golang.org/api/cloudresourcemanager/v1"
+	firestore "google.golang.org/api/firestore/v1"
+)
+
+const (
+	// auditDataset is the BigQuery dataset of the audit trail in the automation project.
+	auditDataset = "automation_audit"
+	// auditTable is the BigQuery table of the audit trail.
+	auditTable = "actions"
+	// auditCollection is the Firestore collection records are written to if BigQuery fails.
+	auditCollection = "automation-audit"
+)
+
+// Audit outcomes.
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+	AuditDryRun  = "dry_run"
+	AuditSkipped = "skipped"
+)
+
+// Redacted replaces the values of secret fields in recorded values.
+const Redacted = "REDACTED"
+
+// secretFields are the fields of the values of automations holding secrets, compared in lower case.
+var secretFields = map[string]bool{
+	"password": true,
+	"apikey":   true,
+	"api_key":  true,
+	"token":    true,
+}
+
+// AuditTableClient contains minimum interface required by the audit service to write to BigQuery.
+type AuditTableClient interface {
+	Insert(ctx context.Context, projectID, datasetID, tableID string, src interface{}) error
+}
+
+// AuditDocumentClient contains minimum interface required by the audit service to write to Firestore.
+type AuditDocumentClient interface {
+	CreateDocument(ctx context.Context, projectID, collection, documentID string, fields map[string]firestore.Value) error
+}
+
+// Audit service records every remediation attempt.
+type Audit struct {
+	table     AuditTableClient
+	documents AuditDocumentClient
+	projectID string
+	actor     string
+}
+
+// AuditRecord is an attempt of an automation to remediate a finding.
+type AuditRecord struct {
+	// ID identifies the remediation, it is used to undo it.
+	ID        string    `bigquery:"remediation_id"`
+	Time      time.Time `bigquery:"time"`
+	FindingID string    `bigquery:"finding_id"`
+	// Severity is the severity of the finding, such as "HIGH", if it has one.
+	Severity string `bigquery:"severity"`
+	// Category is the rule the finding matched, such as "bad_ip".
+	Category  string `bigquery:"category"`
+	ProjectID string `bigquery:"project_id"`
+	// Action is the automation's Cloud Function, such as "RemoveNonOrganizationMembers".
+	Action string `bigquery:"action"`
+	// Values are the values the automation ran with, as JSON, with their secrets redacted.
+	Values string `bigquery:"values"`
+	// Before and After are the state of the changed resource, as JSON, for automations reporting it.
+	Before  string `bigquery:"before"`
+	After   string `bigquery:"after"`
+	Outcome string `bigquery:"outcome"`
+	Error   string `bigquery:"error"`
+	// Reason is why the finding was skipped, such as the exemption mark set on its asset.
+	Reason string `bigquery:"reason"`
+	// Actor is the service account the automation ran as.
+	Actor string `bigquery:"actor"`
+	// ApprovalID and Approvers identify the approval of actions that waited for one.
+	ApprovalID string   `bigquery:"approval_id"`
+	Approvers  []string `bigquery:"approvers"`
+	// States are the prior states of the resources changed, saved so the remediation can be undone.
+	States []*PriorState `bigquery:"-"`
+	// Evidence are the artifacts of the remediation attached to its notifications, such as the
+	// diff of the changed IAM policy.
+	Evidence []clients.Attachment `bigquery:"-"`
+}
+
+// NewAudit returns an audit service writing to the automation project as the actor.
+func NewAudit(table AuditTableClient, documents AuditDocumentClient, projectID, actor string) *Audit {
+	return &Audit{table: table, documents: documents, projectID: projectID, actor: actor}
+}
+
+// Record writes the record to BigQuery, or to Firestore if BigQuery fails. The prior states of
+// the changed resources are saved to Firestore under the record's ID.
+func (a *Audit) Record(ctx context.Context, r *AuditRecord) error {
+	if r.Actor == "" {
+		r.Actor = a.actor
+	}
+	if len(r.States) > 0 {
+		if err := a.documents.CreateDocument(ctx, a.projectID, undoCollection, r.ID, undoFields(r)); err != nil {
+			return fmt.Errorf("failed to save prior states of remediation %q: %q", r.ID, err)
+		}
+	}
+	tableErr := a.table.Insert(ctx, a.projectID, auditDataset, auditTable, r)
+	if tableErr == nil {
+		return nil
+	}
+	if err := a.documents.CreateDocument(ctx, a.projectID, auditCollection, r.ID, auditFields(r)); err != nil {
+		return fmt.Errorf("failed to write audit record to bigquery: %q and firestore: %q", tableErr, err)
+	}
+	return nil
+}
+
+// RedactValues returns the JSON values of an automation with the values of their secret fields,
+// such as passwords and API keys, replaced. Values without secrets are returned as is.
+func RedactValues(b []byte) []byte {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil || !redact(v) {
+		return b
+	}
+	r, err := json.Marshal(v)
+	if err != nil {
+		return b
+	}
+	return r
+}
+
+// redact replaces the values of the secret fields within v, returning whether any was replaced.
+func redact(v interface{}) bool {
+	redacted := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, f := range v {
+			if s, ok := f.(string); ok && s != "" && secretFields[strings.ToLower(k)] {
+				v[k] = Redacted
+				redacted = true
+				continue
+			}
+			redacted = redact(f) || redacted
+		}
+	case []interface{}:
+		for _, f := range v {
+			redacted = redact(f) || redacted
+		}
+	}
+	return redacted
+}
+
+// SetOutcome sets the outcome of the attempt from the error it returned.
+func (r *AuditRecord) SetOutcome(err error, dryRun bool) {
+	switch {
+	case err != nil:
+		r.Outcome = AuditFailure
+		r.Error = err.Error()
+	case dryRun:
+		r.Outcome = AuditDryRun
+	default:
+		r.Outcome = AuditSuccess
+	}
+}
+
+func auditFields(r *AuditRecord) map[string]firestore.Value {
+	return map[string]firestore.Value{
+		"remediation_id": {StringValue: r.ID},
+		"time":           {TimestampValue: r.Time.UTC().Format(time.RFC3339Nano)},
+		"finding_id":     {StringValue: r.FindingID},
+		"severity":       {StringValue: r.Severity},
+		"category":       {StringValue: r.Category},
+		"project_id":     {StringValue: r.ProjectID},
+		"action":         {StringValue: r.Action},
+		"values":         {StringValue: r.Values},
+		"before":         {StringValue: r.Before},
+		"after":          {StringValue: r.After},
+		"outcome":        {StringValue: r.Outcome},
+		"error":          {StringValue: r.Error},
+		"reason":         {StringValue: r.Reason},
+		"actor":          {StringValue: r.Actor},
+		"approval_id":    {StringValue: r.ApprovalID},
+		"approvers":      arrayValue(r.Approvers),
+	}
+}
+
+// arrayValue returns the strings as a Firestore array.
+func arrayValue(values []string) firestore.Value {
+	a := &firestore.ArrayValue{Values: []*firestore.Value{}}
+	for _, v := range values {
+		a.Values = append(a.Values, &firestore.Value{StringValue: v})
+	}
+	return firestore.Value{ArrayValue: a}
+}
+
+// arrayStrings returns the strings of a Firestore array.
+func arrayStrings(v firestore.Value) []string {
+	var values []string
+	if v.ArrayValue != nil {
+		for _, s := range v.ArrayValue.Values {
+			values = append(values, s.StringValue)
+		}
+	}
+	return values
+}
+
+type auditKey struct{}
+
+// auditing returns whether the context collects the state changed by the automation.
+func auditing(ctx context.Context) bool {
+	_, ok := ctx.Value(auditKey{}).(*AuditRecord)
+	return ok
+}
+
+// WithAudit returns a context that collects the state changed by the automation into the record.
+func WithAudit(ctx context.Context, r *AuditRecord) context.Context {
+	return context.WithValue(ctx, auditKey{}, r)
+}
+
+// AuditState records the state of a resource before and after the automation changed it, if the
+// context is audited. Services call this where they change a resource.
+func AuditState(ctx context.Context, before, after interface{}) {
+	r, ok := ctx.Value(auditKey{}).(*AuditRecord)
+	if !ok {
+		return
+	}
+	if b, err := json.Marshal(before); err == nil {
+		r.Before = string(b)
+	}
+	if b, err := json.Marshal(after); err == nil {
+		r.After = string(b)
+	}
+}
+
+// AuditApprovers records the approval and approvers of the automation, if the context is audited.
+// Automations that verify their approval record the approvers they verified.
+func AuditApprovers(ctx context.Context, approvalID string, approvers []string) {
+	r, ok := ctx.Value(auditKey{}).(*AuditRecord)
+	if !ok {
+		return
+	}
+	r.ApprovalID = approvalID
+	r.Approvers = approvers
+}
+
+// AttachEvidence attaches the artifact to the notifications of the automation, if the context is
+// audited. The content type is guessed from the filename if empty.
+func AttachEvidence(ctx context.Context, filename, contentType string, content []byte) {
+	r, ok := ctx.Value(auditKey{}).(*AuditRecord)
+	if !ok {
+		return
+	}
+	r.Evidence = append(r.Evidence, clients.Attachment{Filename: filename, ContentType: contentType, Content: content})
+}
+
+// auditUndo records the state change like AuditState and saves the prior state of the resource so
+// the remediation can be undone. The change is attached as evidence.
+func auditUndo(ctx context.Context, kind, resource string, before, after interface{}) {
+	r, ok := ctx.Value(auditKey{}).(*AuditRecord)
+	if !ok {
+		return
+	}
+	AuditState(ctx, before, after)
+	b, err := json.Marshal(before)
+	if err != nil {
+		return
+	}
+	r.States = append(r.States, &PriorState{Kind: kind, Resource: resource, State: string(b)})
+	attachChange(ctx, kind, resource, before, after)
+}
+
+// attachChange attaches the diff and the removed members of changed IAM policies, or the state of
+// other resources before and after the change.
+func attachChange(ctx context.Context, kind, resource string, before, after interface{}) {
+	name := strings.Replace(strings.Trim(resource, "/"), "/", "-", -1)
+	old, ok1 := before.(*crm.Policy)
+	updated, ok2 := after.(*crm.Policy)
+	if ok1 && ok2 {
+		change := PolicyChange(resource, old, updated)
+		if b, err := json.MarshalIndent(change, "", "  "); err == nil {
+			AttachEvidence(ctx, name+"-policy-diff.json", "application/json", b)
+		}
+		removed := []string{}
+		for _, binding := range change.Bindings {
+			for _, m := range binding.Removed {
+				removed = append(removed, binding.Role+" "+m)
+			}
+		}
+		if len(removed) > 0 {
+			AttachEvidence(ctx, name+"-removed-members.txt", "text/plain", []byte(strings.Join(removed, "\n")+"\n"))
+		}
+		return
+	}
+	for i, state := range []interface{}{before, after} {
+		if b, err := json.MarshalIndent(state, "", "  "); err == nil && string(b) != "null" {
+			AttachEvidence(ctx, fmt.Sprintf("%s-%s-%s.json", name, kind, []string{"before", "after"}[i]), "application/json", b)
+		}
+	}
+}
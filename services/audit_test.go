@@ -0,0 +1,146 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	crm "google.golang.org/api/cloudresourcemanager/v1"
+)
+
+func TestAuditRecord(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		name         string
+		insertErr    error
+		documentErr  error
+		wantRows     int
+		wantDocument bool
+		wantErr      bool
+	}{
+		{name: "bigquery", wantRows: 1},
+		{name: "firestore fallback", insertErr: errors.New("unavailable"), wantDocument: true},
+		{name: "both fail", insertErr: errors.New("unavailable"), documentErr: errors.New("unavailable"), wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			bq := &stubs.BigQueryStub{StubbedInsertErr: tt.insertErr}
+			fs := &stubs.FirestoreStub{StubbedErr: tt.documentErr}
+			a := NewAudit(bq, fs, "automation-project", "automation@automation-project.iam.gserviceaccount.com")
+			r := &AuditRecord{Time: now, FindingID: "finding-1", Action: "IAMRevoke"}
+			r.SetOutcome(nil, false)
+			err := a.Record(context.Background(), r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%s failed: got err %v want err %v", tt.name, err, tt.wantErr)
+			}
+			if len(bq.SavedRows) != tt.wantRows {
+				t.Errorf("%s failed: got %d rows want %d", tt.name, len(bq.SavedRows), tt.wantRows)
+			}
+			if got := len(fs.SavedDocuments) == 1; got != tt.wantDocument {
+				t.Fatalf("%s failed: got document %v want %v", tt.name, got, tt.wantDocument)
+			}
+			if tt.wantDocument {
+				doc := fs.SavedDocuments[0]
+				if doc["finding_id"].StringValue != "finding-1" || doc["outcome"].StringValue != AuditSuccess || doc["time"].TimestampValue != "2020-01-01T00:00:00Z" {
+					t.Errorf("%s failed: unexpected document %v", tt.name, doc)
+				}
+			}
+			if r.Actor != "automation@automation-project.iam.gserviceaccount.com" {
+				t.Errorf("%s failed: got actor %q", tt.name, r.Actor)
+			}
+		})
+	}
+}
+
+func TestRedactValues(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		values string
+		want   string
+	}{
+		{name: "no secrets", values: `{"ProjectID":"test-project","DryRun":false}`, want: `{"ProjectID":"test-project","DryRun":false}`},
+		{name: "password", values: `{"ProjectID":"test-project","InstanceName":"db","Password":"hunter22"}`, want: `{"InstanceName":"db","Password":"REDACTED","ProjectID":"test-project"}`},
+		{name: "nested api key", values: `{"ProjectID":"test-project","MaxAge":7,"SendGrid":{"APIKey":"SG.xxx","From":"sra@cloudorg.com"}}`, want: `{"MaxAge":7,"ProjectID":"test-project","SendGrid":{"APIKey":"REDACTED","From":"sra@cloudorg.com"}}`},
+		{name: "empty secret", values: `{"Password":""}`, want: `{"Password":""}`},
+		{name: "not json", values: `not json`, want: `not json`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(RedactValues([]byte(tt.values))); got != tt.want {
+				t.Errorf("%s failed: got %s want %s", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuditOutcome(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		err         error
+		dryRun      bool
+		wantOutcome string
+		wantError   string
+	}{
+		{name: "success", wantOutcome: AuditSuccess},
+		{name: "dry run", dryRun: true, wantOutcome: AuditDryRun},
+		{name: "failure", err: errors.New("permission denied"), dryRun: true, wantOutcome: AuditFailure, wantError: "permission denied"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &AuditRecord{}
+			r.SetOutcome(tt.err, tt.dryRun)
+			if r.Outcome != tt.wantOutcome || r.Error != tt.wantError {
+				t.Errorf("%s failed: got %q %q want %q %q", tt.name, r.Outcome, r.Error, tt.wantOutcome, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestAuditState(t *testing.T) {
+	crmStub := &stubs.ResourceManagerStub{}
+	crmStub.GetPolicyResponse = &crm.Policy{Bindings: []*crm.Binding{
+		{Role: "roles/editor", Members: []string{"user:ddgo@cloudorg.com", "user:bob@gmail.com"}},
+	}}
+	r := NewResource(crmStub, &stubs.StorageStub{})
+	record := &AuditRecord{}
+	ctx := WithAudit(context.Background(), record)
+	if _, err := r.ProjectOnlyKeepUsersFromDomains(ctx, "test-project", []string{"cloudorg.com"}, KeepOptions{}); err != nil {
+		t.Fatalf("failed to keep users: %q", err)
+	}
+	wantBefore := `{"bindings":[{"members":["user:ddgo@cloudorg.com","user:bob@gmail.com"],"role":"roles/editor"}]}`
+	wantAfter := `{"bindings":[{"members":["user:ddgo@cloudorg.com"],"role":"roles/editor"}]}`
+	if diff := cmp.Diff(wantBefore, record.Before); diff != "" {
+		t.Errorf("unexpected before state (-want +got): %v", diff)
+	}
+	if diff := cmp.Diff(wantAfter, record.After); diff != "" {
+		t.Errorf("unexpected after state (-want +got): %v", diff)
+	}
+	wantStates := []*PriorState{{Kind: "iam_policy", Resource: "projects/test-project", State: wantBefore}}
+	if diff := cmp.Diff(wantStates, record.States); diff != "" {
+		t.Errorf("unexpected prior states (-want +got): %v", diff)
+	}
+	names := []string{}
+	for _, a := range record.Evidence {
+		names = append(names, a.Filename)
+	}
+	wantNames := []string{"projects-test-project-policy-diff.json", "projects-test-project-removed-members.txt"}
+	if diff := cmp.Diff(wantNames, names); diff != "" {
+		t.Fatalf("unexpected evidence (-want +got): %v", diff)
+	}
+	if got := string(record.Evidence[1].Content); got != "roles/editor user:bob@gmail.com\n" {
+		t.Errorf("got removed members %q", got)
+	}
+}
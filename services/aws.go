@@ -0,0 +1,111 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+)
+
+// isolationDescription describes the security groups created to isolate instances.
+const isolationDescription = "Isolates compromised instances, denying all traffic. Created by Security Response Automation."
+
+// AWSClient contains minimum interface required by the service.
+type AWSClient interface {
+	UpdateAccessKey(context.Context, string, string, string) error
+	DescribeInstance(context.Context, string, string) (*ec2.Instance, error)
+	SecurityGroups(context.Context, string, string, string) ([]*ec2.SecurityGroup, error)
+	CreateSecurityGroup(context.Context, string, string, string, string) (string, error)
+	RevokeSecurityGroupEgress(context.Context, string, string, []*ec2.IpPermission) error
+	ModifyInstanceSecurityGroups(context.Context, string, string, []string) error
+}
+
+// AWS service.
+type AWS struct {
+	client AWSClient
+}
+
+// NewAWS returns an AWS service.
+func NewAWS(client AWSClient) *AWS {
+	return &AWS{client: client}
+}
+
+// DisableAccessKey deactivates the access key of the IAM user.
+func (a *AWS) DisableAccessKey(ctx context.Context, userName, accessKeyID string) error {
+	if err := a.client.UpdateAccessKey(ctx, userName, accessKeyID, "Inactive"); err != nil {
+		return errors.Wrapf(err, "failed to disable access key %q of %q", accessKeyID, userName)
+	}
+	return nil
+}
+
+// IsolateInstance replaces the security groups of the instance with the isolation security group
+// of its VPC, created without rules if the VPC has none, and returns the groups it replaced.
+//
+// Nothing is replaced if the instance is only in the isolation group.
+func (a *AWS) IsolateInstance(ctx context.Context, region, instanceID, groupName string) ([]string, error) {
+	instance, err := a.client.DescribeInstance(ctx, region, instanceID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get instance %q", instanceID)
+	}
+	vpcID := aws.StringValue(instance.VpcId)
+	if vpcID == "" {
+		return nil, fmt.Errorf("instance %q is not in a vpc", instanceID)
+	}
+	groupID, err := a.isolationGroup(ctx, region, vpcID, groupName)
+	if err != nil {
+		return nil, err
+	}
+	replaced := []string{}
+	for _, g := range instance.SecurityGroups {
+		if id := aws.StringValue(g.GroupId); id != groupID {
+			replaced = append(replaced, id)
+		}
+	}
+	if len(replaced) == 0 && len(instance.SecurityGroups) > 0 {
+		return replaced, nil
+	}
+	if err := a.client.ModifyInstanceSecurityGroups(ctx, region, instanceID, []string{groupID}); err != nil {
+		return nil, errors.Wrapf(err, "failed to isolate instance %q", instanceID)
+	}
+	return replaced, nil
+}
+
+// isolationGroup returns the ID of the security group of the VPC with the name, creating it if
+// needed. Created groups have their default rule allowing all egress revoked.
+func (a *AWS) isolationGroup(ctx context.Context, region, vpcID, name string) (string, error) {
+	groups, err := a.client.SecurityGroups(ctx, region, vpcID, name)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to find security group %q of %q", name, vpcID)
+	}
+	if len(groups) > 0 {
+		return aws.StringValue(groups[0].GroupId), nil
+	}
+	id, err := a.client.CreateSecurityGroup(ctx, region, vpcID, name, isolationDescription)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create security group %q in %q", name, vpcID)
+	}
+	allowAll := []*ec2.IpPermission{{
+		IpProtocol: aws.String("-1"),
+		IpRanges:   []*ec2.IpRange{{CidrIp: aws.String("0.0.0.0/0")}},
+	}}
+	if err := a.client.RevokeSecurityGroupEgress(ctx, region, id, allowAll); err != nil {
+		return "", errors.Wrapf(err, "failed to revoke egress of security group %q", id)
+	}
+	return id, nil
+}
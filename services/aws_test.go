@@ -0,0 +1,96 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+)
+
+func TestIsolateInstance(t *testing.T) {
+	isolation := &ec2.SecurityGroup{GroupId: aws.String("sg-isolation"), GroupName: aws.String("sra-isolation")}
+	instance := func(groups ...string) *ec2.Instance {
+		i := &ec2.Instance{InstanceId: aws.String("i-0123456789abcdef0"), VpcId: aws.String("vpc-1")}
+		for _, g := range groups {
+			i.SecurityGroups = append(i.SecurityGroups, &ec2.GroupIdentifier{GroupId: aws.String(g)})
+		}
+		return i
+	}
+	for _, tt := range []struct {
+		name           string
+		instance       *ec2.Instance
+		groups         []*ec2.SecurityGroup
+		replaced       []string
+		instanceGroups []string
+		createdGroup   string
+		revokedGroup   string
+		wantErr        bool
+	}{
+		{
+			name:           "create isolation group",
+			instance:       instance("sg-web", "sg-ssh"),
+			replaced:       []string{"sg-web", "sg-ssh"},
+			instanceGroups: []string{"sg-created"},
+			createdGroup:   "sra-isolation",
+			revokedGroup:   "sg-created",
+		},
+		{
+			name:           "existing isolation group",
+			instance:       instance("sg-web"),
+			groups:         []*ec2.SecurityGroup{isolation},
+			replaced:       []string{"sg-web"},
+			instanceGroups: []string{"sg-isolation"},
+		},
+		{
+			name:     "already isolated",
+			instance: instance("sg-isolation"),
+			groups:   []*ec2.SecurityGroup{isolation},
+			replaced: []string{},
+		},
+		{
+			name:     "not in a vpc",
+			instance: &ec2.Instance{InstanceId: aws.String("i-0123456789abcdef0")},
+			wantErr:  true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			awsStub := &stubs.AWSStub{StubbedInstance: tt.instance, StubbedSecurityGroups: tt.groups}
+			replaced, err := NewAWS(awsStub).IsolateInstance(context.Background(), "us-east-1", "i-0123456789abcdef0", "sra-isolation")
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("%s failed: got no error", tt.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(tt.replaced, replaced); diff != "" {
+				t.Errorf("%s failed, difference:%+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(tt.instanceGroups, awsStub.SavedInstanceGroups); diff != "" {
+				t.Errorf("%s failed, difference:%+v", tt.name, diff)
+			}
+			if awsStub.CreatedSecurityGroup != tt.createdGroup || awsStub.RevokedGroup != tt.revokedGroup {
+				t.Errorf("%s failed: got created:%q revoked:%q want created:%q revoked:%q", tt.name, awsStub.CreatedSecurityGroup, awsStub.RevokedGroup, tt.createdGroup, tt.revokedGroup)
+			}
+		})
+	}
+}
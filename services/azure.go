@@ -0,0 +1,61 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// noPublicAccess is the public access level of blob containers that can't be read anonymously.
+const noPublicAccess = "None"
+
+// AzureClient contains minimum interface required by the service.
+type AzureClient interface {
+	ContainerPublicAccess(context.Context, string, string, string, string) (string, error)
+	SetContainerPublicAccess(context.Context, string, string, string, string, string) error
+}
+
+// Azure service.
+type Azure struct {
+	client AzureClient
+}
+
+// NewAzure returns an Azure service.
+func NewAzure(client AzureClient) *Azure {
+	return &Azure{client: client}
+}
+
+// ContainerPublicAccess returns the public access level of the blob container, "None" if it can't
+// be read anonymously.
+func (a *Azure) ContainerPublicAccess(ctx context.Context, subscriptionID, resourceGroup, account, container string) (string, error) {
+	access, err := a.client.ContainerPublicAccess(ctx, subscriptionID, resourceGroup, account, container)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get public access of container %q of %q", container, account)
+	}
+	if access == "" {
+		return noPublicAccess, nil
+	}
+	return access, nil
+}
+
+// RemoveContainerPublicAccess removes anonymous read access to the blob container and its blobs.
+func (a *Azure) RemoveContainerPublicAccess(ctx context.Context, subscriptionID, resourceGroup, account, container string) error {
+	if err := a.client.SetContainerPublicAccess(ctx, subscriptionID, resourceGroup, account, container, noPublicAccess); err != nil {
+		return errors.Wrapf(err, "failed to remove public access of container %q of %q", container, account)
+	}
+	return nil
+}
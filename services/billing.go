@@ -0,0 +1,59 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	cloudbilling "google.golang.org/api/cloudbilling/v1"
+)
+
+// BillingClient contains minimum interface required by the billing service.
+type BillingClient interface {
+	GetBillingInfo(context.Context, string) (*cloudbilling.ProjectBillingInfo, error)
+	UpdateBillingInfo(context.Context, string, *cloudbilling.ProjectBillingInfo) (*cloudbilling.ProjectBillingInfo, error)
+}
+
+// Billing service.
+type Billing struct {
+	client BillingClient
+}
+
+// NewBilling returns a billing service.
+func NewBilling(client BillingClient) *Billing {
+	return &Billing{client: client}
+}
+
+// DisableBilling detaches the billing account from the project, shutting down its paid resources.
+// The detached billing account is returned, empty if billing was already disabled.
+func (b *Billing) DisableBilling(ctx context.Context, projectID string) (string, error) {
+	name := "projects/" + projectID
+	info, err := b.client.GetBillingInfo(ctx, name)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get billing info for %q", projectID)
+	}
+	if !info.BillingEnabled || info.BillingAccountName == "" {
+		return "", nil
+	}
+	// An empty billing account name detaches the project's billing account.
+	if _, err := b.client.UpdateBillingInfo(ctx, name, &cloudbilling.ProjectBillingInfo{
+		BillingAccountName: "",
+		ForceSendFields:    []string{"BillingAccountName"},
+	}); err != nil {
+		return "", errors.Wrapf(err, "failed to disable billing for %q", projectID)
+	}
+	return info.BillingAccountName, nil
+}
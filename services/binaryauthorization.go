@@ -0,0 +1,86 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	binaryauthorization "google.golang.org/api/binaryauthorization/v1"
+)
+
+// BinaryAuthorizationClient contains minimum interface required by the service.
+type BinaryAuthorizationClient interface {
+	GetPolicy(context.Context, string) (*binaryauthorization.Policy, error)
+	UpdatePolicy(context.Context, string, *binaryauthorization.Policy) (*binaryauthorization.Policy, error)
+}
+
+// BinaryAuthorization service manages the Binary Authorization policy of projects.
+type BinaryAuthorization struct {
+	client BinaryAuthorizationClient
+}
+
+// NewBinaryAuthorization returns a Binary Authorization service.
+func NewBinaryAuthorization(client BinaryAuthorizationClient) *BinaryAuthorization {
+	return &BinaryAuthorization{client: client}
+}
+
+// BlockImage removes the admission allowlist patterns matching the image so it must pass the
+// policy's admission rules to be deployed again. Binary Authorization has no rule denying a single
+// digest, so the image is only blocked if the default admission rule does not always allow images.
+// The patterns removed are returned along with whether the default rule enforces admission.
+func (b *BinaryAuthorization) BlockImage(ctx context.Context, projectID, image string) ([]string, bool, error) {
+	name := "projects/" + projectID + "/policy"
+	policy, err := b.client.GetPolicy(ctx, name)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to get binary authorization policy of %q", projectID)
+	}
+	enforced := policy.DefaultAdmissionRule != nil && policy.DefaultAdmissionRule.EvaluationMode != "ALWAYS_ALLOW"
+	// Patterns are matched against the image name without its tag or digest.
+	imageName := image
+	if i := strings.Index(imageName, "@"); i != -1 {
+		imageName = imageName[:i]
+	}
+	if i := strings.LastIndex(imageName, ":"); i > strings.LastIndex(imageName, "/") {
+		imageName = imageName[:i]
+	}
+	removed := []string{}
+	keep := []*binaryauthorization.AdmissionWhitelistPattern{}
+	for _, p := range policy.AdmissionWhitelistPatterns {
+		if admissionPatternMatches(p.NamePattern, imageName) {
+			removed = append(removed, p.NamePattern)
+			continue
+		}
+		keep = append(keep, p)
+	}
+	if len(removed) == 0 {
+		return removed, enforced, nil
+	}
+	policy.AdmissionWhitelistPatterns = keep
+	if _, err := b.client.UpdatePolicy(ctx, name, policy); err != nil {
+		return nil, enforced, errors.Wrapf(err, "failed to update binary authorization policy of %q", projectID)
+	}
+	return removed, enforced, nil
+}
+
+// admissionPatternMatches returns true if the allowlist pattern, optionally ending with a "*"
+// wildcard, matches the image name.
+func admissionPatternMatches(pattern, image string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(image, strings.TrimSuffix(pattern, "*"))
+	}
+	return pattern == image
+}
@@ -0,0 +1,201 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	firestore "google.golang.org/api/firestore/v1"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+)
+
+const (
+	// breakerCollection is the Firestore collection of the circuit breaker of each API.
+	breakerCollection = "automation-circuit-breakers"
+	// breakerRefresh is how long the state of a circuit breaker is cached before it's read again.
+	breakerRefresh          = 30 * time.Second
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 5 * time.Minute
+)
+
+// BreakerConfig configures when the circuit breaker of an API opens.
+type BreakerConfig struct {
+	// Threshold is how many consecutive calls fail before the breaker opens, defaults to 5.
+	Threshold int
+	// Cooldown is how long the breaker stays open before a call is let through to check whether
+	// the API recovered. Defaults to 5 minutes.
+	Cooldown time.Duration
+}
+
+// BreakerDocumentClient contains minimum interface required by the circuit breaker service.
+type BreakerDocumentClient interface {
+	CreateDocument(ctx context.Context, projectID, collection, documentID string, fields map[string]firestore.Value) error
+	GetDocument(ctx context.Context, projectID, collection, documentID string) (*firestore.Document, error)
+	UpdateDocument(ctx context.Context, projectID, collection, documentID string, fields map[string]firestore.Value, updateTime string) error
+}
+
+// breakerState is the state of the circuit breaker of an API.
+type breakerState struct {
+	failures int
+	opened   time.Time
+	// read is when the state was read, the state is refreshed once breakerRefresh passed.
+	read time.Time
+	// exists is whether the state is stored.
+	exists bool
+}
+
+// Breaker service opens a circuit breaker for each API that fails persistently, so automations
+// don't keep calling it and exhausting its quota.
+//
+// The state of each breaker is kept in Firestore, shared by all functions and their instances,
+// and cached for a short while so it isn't read for every call.
+type Breaker struct {
+	documents BreakerDocumentClient
+	projectID string
+	config    func() BreakerConfig
+	now       func() time.Time
+	mu        sync.Mutex
+	states    map[string]*breakerState
+	// probes are when the call probing whether each API recovered was let through.
+	probes map[string]time.Time
+}
+
+// NewBreaker returns a circuit breaker service keeping its state in the automation project. The
+// configuration is read on each call, so changes to it are picked up.
+func NewBreaker(documents BreakerDocumentClient, projectID string, config func() BreakerConfig) *Breaker {
+	return &Breaker{
+		documents: documents,
+		projectID: projectID,
+		config:    config,
+		now:       time.Now,
+		states:    map[string]*breakerState{},
+		probes:    map[string]time.Time{},
+	}
+}
+
+func (c BreakerConfig) threshold() int {
+	if c.Threshold <= 0 {
+		return defaultBreakerThreshold
+	}
+	return c.Threshold
+}
+
+func (c BreakerConfig) cooldown() time.Duration {
+	if c.Cooldown <= 0 {
+		return defaultBreakerCooldown
+	}
+	return c.Cooldown
+}
+
+// Allow returns whether the API may be called, false while its breaker is open. Once the cooldown
+// passed a single call per cooldown is let through to probe the API, and the breaker opens again
+// if it fails. The API is allowed if the state of its breaker can't be read.
+func (b *Breaker) Allow(ctx context.Context, api string) bool {
+	now := b.now()
+	s, err := b.state(ctx, api, now)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to read circuit breaker of %s: %q", api, err)
+		return true
+	}
+	conf := b.config()
+	if s.failures < conf.threshold() {
+		return true
+	}
+	if now.Before(s.opened.Add(conf.cooldown())) {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if probed, ok := b.probes[api]; ok && now.Before(probed.Add(conf.cooldown())) {
+		return false
+	}
+	b.probes[api] = now
+	return true
+}
+
+// Record counts the consecutive failed calls of the API, opening its breaker once they reach the
+// threshold. A successful call closes the breaker.
+func (b *Breaker) Record(ctx context.Context, api string, failed bool) {
+	now := b.now()
+	s, err := b.state(ctx, api, now)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to read circuit breaker of %s: %q", api, err)
+		return
+	}
+	if !failed && s.failures == 0 {
+		return
+	}
+	next := breakerState{read: now, exists: true}
+	if failed {
+		next.failures, next.opened = s.failures+1, s.opened
+		if next.failures >= b.config().threshold() {
+			next.opened = now
+			logging.FromContext(ctx).Warning("opening circuit breaker of %s after %d consecutive failures", api, next.failures)
+		}
+	}
+	err = b.save(ctx, api, s, &next)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err != nil {
+		// The state is read again, another instance may have changed it.
+		delete(b.states, api)
+		logging.FromContext(ctx).Error("failed to save circuit breaker of %s: %q", api, err)
+		return
+	}
+	b.states[api] = &next
+	if !failed {
+		delete(b.probes, api)
+	}
+}
+
+// state returns the cached state of the API's breaker, reading it again once it's stale. The lock
+// isn't held while reading, so calls to the other APIs don't wait for Firestore.
+func (b *Breaker) state(ctx context.Context, api string, now time.Time) (*breakerState, error) {
+	b.mu.Lock()
+	cached, ok := b.states[api]
+	b.mu.Unlock()
+	if ok && now.Sub(cached.read) < breakerRefresh {
+		return cached, nil
+	}
+	s := &breakerState{read: now}
+	doc, err := b.documents.GetDocument(ctx, b.projectID, breakerCollection, api)
+	if err != nil && !notFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		s.exists = true
+		s.failures = int(doc.Fields["failures"].IntegerValue)
+		s.opened, _ = time.Parse(time.RFC3339Nano, doc.Fields["opened"].TimestampValue)
+	}
+	b.mu.Lock()
+	b.states[api] = s
+	b.mu.Unlock()
+	return s, nil
+}
+
+func (b *Breaker) save(ctx context.Context, api string, prev, next *breakerState) error {
+	fields := map[string]firestore.Value{
+		"failures": {IntegerValue: int64(next.failures)},
+		"opened":   {TimestampValue: next.opened.UTC().Format(time.RFC3339Nano)},
+	}
+	if !prev.exists {
+		return b.documents.CreateDocument(ctx, b.projectID, breakerCollection, api, fields)
+	}
+	// Concurrent failures may overwrite each other's count, which only delays opening the breaker.
+	return b.documents.UpdateDocument(ctx, b.projectID, breakerCollection, api, fields, "")
+}
@@ -0,0 +1,93 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+)
+
+func TestBreaker(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	fsStub := &stubs.FirestoreStub{}
+	b := NewBreaker(fsStub, "automation-project", func() BreakerConfig {
+		return BreakerConfig{Threshold: 2, Cooldown: time.Minute}
+	})
+	b.now = func() time.Time { return now }
+	for _, tt := range []struct {
+		name    string
+		elapsed time.Duration
+		failed  []bool
+		want    bool
+	}{
+		{name: "closed", want: true},
+		{name: "below threshold", failed: []bool{true}, want: true},
+		{name: "open", failed: []bool{true}, want: false},
+		{name: "cooled down", elapsed: time.Minute, want: true},
+		{name: "probing", want: false},
+		{name: "failed again", failed: []bool{true}, want: false},
+		{name: "recovered", elapsed: 2 * time.Minute, failed: []bool{false}, want: true},
+		{name: "failure after recovery", failed: []bool{true}, want: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			now = now.Add(tt.elapsed)
+			for _, failed := range tt.failed {
+				b.Record(ctx, "compute", failed)
+			}
+			if got := b.Allow(ctx, "compute"); got != tt.want {
+				t.Errorf("%s failed: got %t want %t", tt.name, got, tt.want)
+			}
+		})
+	}
+	if !b.Allow(ctx, "storage") {
+		t.Errorf("breaker of another API is open")
+	}
+	// Other instances read the state from Firestore.
+	other := NewBreaker(fsStub, "automation-project", b.config)
+	other.now = b.now
+	b.Record(ctx, "compute", true)
+	if other.Allow(ctx, "compute") {
+		t.Errorf("breaker isn't open in another instance")
+	}
+}
+
+func TestBreakerProbe(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := NewBreaker(&stubs.FirestoreStub{}, "automation-project", func() BreakerConfig {
+		return BreakerConfig{Threshold: 1, Cooldown: time.Minute}
+	})
+	b.now = func() time.Time { return now }
+	b.Record(ctx, "compute", true)
+	now = now.Add(time.Minute)
+	allowed := 0
+	for i := 0; i < 5; i++ {
+		if b.Allow(ctx, "compute") {
+			allowed++
+		}
+	}
+	if allowed != 1 {
+		t.Errorf("got %d probes after the cooldown want 1", allowed)
+	}
+	// A probe that never recorded its outcome is retried after another cooldown.
+	now = now.Add(time.Minute)
+	if !b.Allow(ctx, "compute") {
+		t.Errorf("no probe after another cooldown")
+	}
+}
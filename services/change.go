@@ -0,0 +1,134 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	crm "google.golang.org/api/cloudresourcemanager/v1"
+)
+
+// Change is a change a remediation makes to a resource. In dry run it is computed without calling
+// any mutating API and logged instead.
+type Change struct {
+	// Resource is the changed resource, such as "projects/test-project".
+	Resource string
+	// Bindings are the changes to the IAM role bindings of the resource.
+	Bindings []BindingChange `json:",omitempty"`
+}
+
+// BindingChange is a change to the members of a role binding.
+type BindingChange struct {
+	Role string
+	// Condition is the title of the binding's IAM condition, if any.
+	Condition string   `json:",omitempty"`
+	Added     []string `json:",omitempty"`
+	Removed   []string `json:",omitempty"`
+}
+
+// Empty returns whether the change does nothing.
+func (c *Change) Empty() bool {
+	return len(c.Bindings) == 0
+}
+
+// String returns the change, such as `projects/p: roles/editor -user:bob@gmail.com`.
+func (c *Change) String() string {
+	if c.Empty() {
+		return c.Resource + ": no change"
+	}
+	s := []string{}
+	for _, b := range c.Bindings {
+		role := b.Role
+		if b.Condition != "" {
+			role += fmt.Sprintf(" (%s)", b.Condition)
+		}
+		for _, m := range b.Removed {
+			role += " -" + m
+		}
+		for _, m := range b.Added {
+			role += " +" + m
+		}
+		s = append(s, role)
+	}
+	return c.Resource + ": " + strings.Join(s, ", ")
+}
+
+// PolicyChange returns the change between the IAM policy before and after it was updated.
+// Bindings are matched by role and condition.
+func PolicyChange(resource string, before, after *crm.Policy) *Change {
+	old := bindingMembers(before)
+	updated := bindingMembers(after)
+	keys := []bindingKey{}
+	for k := range old {
+		keys = append(keys, k)
+	}
+	for k := range updated {
+		if _, ok := old[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].role != keys[j].role {
+			return keys[i].role < keys[j].role
+		}
+		return keys[i].condition < keys[j].condition
+	})
+	c := &Change{Resource: resource}
+	for _, k := range keys {
+		b := BindingChange{
+			Role:      k.role,
+			Condition: k.condition,
+			Added:     difference(updated[k], old[k]),
+			Removed:   difference(old[k], updated[k]),
+		}
+		if len(b.Added) > 0 || len(b.Removed) > 0 {
+			c.Bindings = append(c.Bindings, b)
+		}
+	}
+	return c
+}
+
+// bindingKey identifies a role binding within a policy.
+type bindingKey struct {
+	role      string
+	condition string
+}
+
+func bindingMembers(policy *crm.Policy) map[bindingKey][]string {
+	m := map[bindingKey][]string{}
+	if policy == nil {
+		return m
+	}
+	for _, b := range policy.Bindings {
+		k := bindingKey{role: b.Role}
+		if b.Condition != nil {
+			k.condition = b.Condition.Title
+		}
+		m[k] = append(m[k], b.Members...)
+	}
+	return m
+}
+
+// difference returns the members of a not in b.
+func difference(a, b []string) []string {
+	var d []string
+	for _, m := range a {
+		if !containsMember(b, m) {
+			d = append(d, m)
+		}
+	}
+	return d
+}
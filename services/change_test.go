@@ -0,0 +1,55 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	crm "google.golang.org/api/cloudresourcemanager/v1"
+)
+
+func TestPolicyChange(t *testing.T) {
+	condition := &crm.Expr{Title: "expires"}
+	before := &crm.Policy{Bindings: []*crm.Binding{
+		{Role: "roles/viewer", Members: []string{"user:ddgo@cloudorg.com", "user:bob@gmail.com"}},
+		{Role: "roles/viewer", Members: []string{"user:bob@gmail.com"}, Condition: condition},
+		{Role: "roles/owner", Members: []string{"user:ddgo@cloudorg.com"}},
+	}}
+	after := &crm.Policy{Bindings: []*crm.Binding{
+		{Role: "roles/viewer", Members: []string{"user:ddgo@cloudorg.com"}},
+		{Role: "roles/viewer", Members: []string{}, Condition: condition},
+		{Role: "roles/owner", Members: []string{"user:ddgo@cloudorg.com"}},
+		{Role: "roles/browser", Members: []string{"user:bob@gmail.com"}},
+	}}
+	want := &Change{
+		Resource: "projects/test-project",
+		Bindings: []BindingChange{
+			{Role: "roles/browser", Added: []string{"user:bob@gmail.com"}},
+			{Role: "roles/viewer", Removed: []string{"user:bob@gmail.com"}},
+			{Role: "roles/viewer", Condition: "expires", Removed: []string{"user:bob@gmail.com"}},
+		},
+	}
+	got := PolicyChange("projects/test-project", before, after)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("failed, difference: %v", diff)
+	}
+	const s = "projects/test-project: roles/browser +user:bob@gmail.com, roles/viewer -user:bob@gmail.com, roles/viewer (expires) -user:bob@gmail.com"
+	if got.String() != s {
+		t.Errorf("got %q want %q", got.String(), s)
+	}
+	if c := PolicyChange("projects/test-project", before, before); !c.Empty() {
+		t.Errorf("got change %q want none", c)
+	}
+}
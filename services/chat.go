@@ -0,0 +1,126 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// chatReplyOption is added to the webhook URL so messages with a thread key reply in the thread.
+const chatReplyOption = "messageReplyOption=REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD"
+
+// ChatConfig configures the Google Chat notification channel.
+type ChatConfig struct {
+	// WebhookURL is the incoming webhook of the Chat space.
+	WebhookURL string `yaml:"webhook_url"`
+}
+
+// Chat service posts notifications as cards to a Google Chat space. Messages about the same
+// finding are posted in one thread.
+type Chat struct {
+	client WebhookClient
+}
+
+type chatDecoratedText struct {
+	TopLabel string `json:"topLabel"`
+	Text     string `json:"text"`
+}
+
+type chatWidget struct {
+	DecoratedText *chatDecoratedText `json:"decoratedText"`
+}
+
+type chatSection struct {
+	Widgets []chatWidget `json:"widgets"`
+}
+
+type chatHeader struct {
+	Title    string `json:"title"`
+	Subtitle string `json:"subtitle"`
+}
+
+type chatCard struct {
+	Header   chatHeader    `json:"header"`
+	Sections []chatSection `json:"sections"`
+}
+
+type chatCardV2 struct {
+	CardID string   `json:"cardId"`
+	Card   chatCard `json:"card"`
+}
+
+type chatThread struct {
+	ThreadKey string `json:"threadKey"`
+}
+
+type chatMessage struct {
+	Text    string       `json:"text"`
+	CardsV2 []chatCardV2 `json:"cardsV2"`
+	Thread  *chatThread  `json:"thread,omitempty"`
+}
+
+// NewChat returns a Chat service posting to the incoming webhook.
+func NewChat(client WebhookClient) *Chat {
+	return &Chat{client: client}
+}
+
+// Notify posts the notification as a card summarizing the finding and the action taken.
+func (c *Chat) Notify(ctx context.Context, n *Notification) error {
+	widgets := []chatWidget{}
+	for _, f := range []chatDecoratedText{
+		{TopLabel: "Outcome", Text: n.Outcome},
+		{TopLabel: "Finding", Text: n.FindingID},
+		{TopLabel: "Severity", Text: n.Severity},
+		{TopLabel: "Project", Text: n.ProjectID},
+		{TopLabel: "Error", Text: n.Error},
+		{TopLabel: "Reason", Text: n.Reason},
+		{TopLabel: "Remediation ID", Text: n.RemediationID},
+	} {
+		if f.Text != "" {
+			f := f
+			widgets = append(widgets, chatWidget{DecoratedText: &f})
+		}
+	}
+	// Digests list the notifications they summarize instead.
+	if n.Digest != nil {
+		widgets = []chatWidget{}
+		for _, d := range n.Digest {
+			text := d.FindingID
+			if text == "" {
+				text = d.Outcome
+			}
+			widgets = append(widgets, chatWidget{DecoratedText: &chatDecoratedText{TopLabel: d.Subject(), Text: text}})
+		}
+	}
+	m := &chatMessage{
+		Text: n.Subject(),
+		CardsV2: []chatCardV2{{
+			CardID: "notification",
+			Card: chatCard{
+				Header:   chatHeader{Title: n.Subject(), Subtitle: "Security Response Automation ran " + n.Action},
+				Sections: []chatSection{{Widgets: widgets}},
+			},
+		}},
+	}
+	if n.FindingID != "" {
+		m.Thread = &chatThread{ThreadKey: documentID(n.FindingID)}
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return c.client.Post(ctx, b)
+}
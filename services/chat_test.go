@@ -0,0 +1,49 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+)
+
+func TestChat(t *testing.T) {
+	ctx := context.Background()
+	stub := &stubs.WebhookStub{}
+	n := &Notification{FindingID: "organizations/456/sources/1/findings/2", ProjectID: "test-project", Action: "CloseBucket", Outcome: AuditSuccess}
+	if err := NewChat(stub).Notify(ctx, n); err != nil {
+		t.Fatalf("failed to notify: %q", err)
+	}
+	if len(stub.SavedRequests) != 1 {
+		t.Fatalf("got %d messages want 1", len(stub.SavedRequests))
+	}
+	var m chatMessage
+	if err := json.Unmarshal([]byte(stub.SavedRequests[0]), &m); err != nil {
+		t.Fatalf("failed to unmarshal message: %q", err)
+	}
+	if m.Thread == nil || m.Thread.ThreadKey != documentID(n.FindingID) {
+		t.Errorf("got thread %+v want key %q", m.Thread, documentID(n.FindingID))
+	}
+	if len(m.CardsV2) != 1 || m.CardsV2[0].Card.Header.Title != n.Subject() {
+		t.Fatalf("unexpected cards %+v", m.CardsV2)
+	}
+	widgets := m.CardsV2[0].Card.Sections[0].Widgets
+	if len(widgets) != 3 || widgets[2].DecoratedText.TopLabel != "Project" || widgets[2].DecoratedText.Text != "test-project" {
+		t.Errorf("unexpected widgets %+v", widgets)
+	}
+}
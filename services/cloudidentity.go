@@ -0,0 +1,88 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	ci "google.golang.org/api/cloudidentity/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// CloudIdentityClient contains minimum interface required by the service.
+type CloudIdentityClient interface {
+	LookupGroup(context.Context, string) (string, error)
+	GetGroup(context.Context, string) (*ci.Group, error)
+	LookupMembership(context.Context, string, string) (string, error)
+}
+
+// CloudIdentity service looks up Cloud Identity groups and their members.
+type CloudIdentity struct {
+	client CloudIdentityClient
+}
+
+// NewCloudIdentity returns a CloudIdentity service.
+func NewCloudIdentity(client CloudIdentityClient) *CloudIdentity {
+	return &CloudIdentity{client: client}
+}
+
+// GroupCustomer returns the ID of the customer owning the group.
+//
+// An empty ID is returned if the group can't be found, which is also the case for groups owned
+// by other customers.
+func (c *CloudIdentity) GroupCustomer(ctx context.Context, email string) (string, error) {
+	name, err := c.client.LookupGroup(ctx, email)
+	if groupNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to lookup group: %q", err)
+	}
+	g, err := c.client.GetGroup(ctx, name)
+	if groupNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get group: %q", err)
+	}
+	return strings.TrimPrefix(g.Parent, "customers/"), nil
+}
+
+// IsMember returns whether the email is a direct member of the group.
+func (c *CloudIdentity) IsMember(ctx context.Context, group, email string) (bool, error) {
+	name, err := c.client.LookupGroup(ctx, group)
+	if groupNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to lookup group: %q", err)
+	}
+	_, err = c.client.LookupMembership(ctx, name, email)
+	if groupNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to lookup membership: %q", err)
+	}
+	return true, nil
+}
+
+// groupNotFound returns whether the error is caused by a group or membership that does not exist or can't be seen.
+func groupNotFound(err error) bool {
+	e, ok := err.(*googleapi.Error)
+	return ok && (e.Code == http.StatusNotFound || e.Code == http.StatusForbidden)
+}
@@ -112,6 +112,29 @@ func (s *CloudSQL) ClosePublicAccess(ctx context.Context, projectID, instance st
 	return nil
 }
 
+// DisablePublicIP disables the public IPv4 address of an instance. The instance must have a private IP
+// configured for this to succeed.
+func (s *CloudSQL) DisablePublicIP(ctx context.Context, projectID, instance string) error {
+	op, err := s.client.PatchInstance(ctx, projectID, instance, &sqladmin.DatabaseInstance{
+		Name:    instance,
+		Project: projectID,
+		Settings: &sqladmin.Settings{
+			IpConfiguration: &sqladmin.IpConfiguration{
+				Ipv4Enabled: false,
+				// Ipv4Enabled is omitted from the request when false unless explicitly sent.
+				ForceSendFields: []string{"Ipv4Enabled"},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.wait(projectID, op); err != nil {
+		return err
+	}
+	return nil
+}
+
 // IsPublic checks if the Cloud SQL instance contains public IPs.
 func (s *CloudSQL) IsPublic(acls []*sqladmin.AclEntry) bool {
 	found := false
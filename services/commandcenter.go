@@ -17,6 +17,7 @@ package services
 import (
 	"context"
 
+	"github.com/pkg/errors"
 	crm "google.golang.org/genproto/googleapis/cloud/securitycenter/v1beta1"
 	"google.golang.org/genproto/protobuf/field_mask"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
@@ -26,6 +27,8 @@ import (
 type CommandCenterClient interface {
 	AddSecurityMarks(context.Context, *crm.UpdateSecurityMarksRequest) (*crm.SecurityMarks, error)
 	SetFindingState(ctx context.Context, request *crm.SetFindingStateRequest) (*crm.Finding, error)
+	ListAssets(ctx context.Context, request *crm.ListAssetsRequest) ([]*crm.ListAssetsResponse_ListAssetsResult, error)
+	ListFindings(ctx context.Context, request *crm.ListFindingsRequest) ([]*crm.Finding, error)
 }
 
 // CommandCenter service.
@@ -58,9 +61,37 @@ func (r *CommandCenter) AddSecurityMarks(ctx context.Context, serviceID string,
 
 // SetInactive sets a finding as inactive
 func (r *CommandCenter) SetInactive(ctx context.Context, name string) (*crm.Finding, error) {
+	return r.SetFindingState(ctx, name, crm.Finding_INACTIVE)
+}
+
+// SetFindingState sets the state of a finding.
+func (r *CommandCenter) SetFindingState(ctx context.Context, name string, state crm.Finding_State) (*crm.Finding, error) {
 	return r.client.SetFindingState(ctx, &crm.SetFindingStateRequest{
 		Name:      name,
-		State:     crm.Finding_INACTIVE,
+		State:     state,
 		StartTime: timestamppb.Now(),
 	})
 }
+
+// UpdateSecurityMarks sets the given security marks on a finding or asset, leaving its other marks as is.
+func (r *CommandCenter) UpdateSecurityMarks(ctx context.Context, name string, securityMarks map[string]string) (*crm.SecurityMarks, error) {
+	return r.AddSecurityMarks(ctx, name, securityMarks)
+}
+
+// RemediatedMark is the security mark added to findings remediated by an automation.
+const RemediatedMark = "remediated-by"
+
+// MarkRemediated adds the remediated-by=automation mark to a finding and, if inactive is set,
+// sets its state to inactive.
+func (r *CommandCenter) MarkRemediated(ctx context.Context, name string, inactive bool) error {
+	if _, err := r.UpdateSecurityMarks(ctx, name, map[string]string{RemediatedMark: "automation"}); err != nil {
+		return errors.Wrapf(err, "failed to mark %q as remediated", name)
+	}
+	if !inactive {
+		return nil
+	}
+	if _, err := r.SetInactive(ctx, name); err != nil {
+		return errors.Wrapf(err, "failed to set %q as inactive", name)
+	}
+	return nil
+}
@@ -102,3 +102,31 @@ func TestAddSecurityMarkToFinding(t *testing.T) {
 		})
 	}
 }
+
+func TestMarkRemediated(t *testing.T) {
+	const name = "organizations/1055058813388/sources/2299436883026055247/findings/f909c48ed690424397eb3c3242062599"
+	tests := []struct {
+		name      string
+		inactive  bool
+		wantState crm.Finding_State
+	}{
+		{name: "add remediated mark", inactive: false, wantState: crm.Finding_STATE_UNSPECIFIED},
+		{name: "add remediated mark and set inactive", inactive: true, wantState: crm.Finding_INACTIVE},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commandCenterStub := &stubs.SecurityCommandCenterStub{}
+			c := NewCommandCenter(commandCenterStub)
+			if err := c.MarkRemediated(context.Background(), name, tt.inactive); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			marks := commandCenterStub.GetUpdateSecurityMarksRequest.GetSecurityMarks()
+			if diff := cmp.Diff(map[string]string{"remediated-by": "automation"}, marks.GetMarks()); diff != "" {
+				t.Errorf("%s failed, difference: %+v", tt.name, diff)
+			}
+			if got := commandCenterStub.GetSetFindingStateRequest.GetState(); got != tt.wantState {
+				t.Errorf("%s failed exp:%v got:%v", tt.name, tt.wantState, got)
+			}
+		})
+	}
+}
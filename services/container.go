@@ -23,6 +23,8 @@ import (
 // ContainerClient holds the minimum interface required by the Container service.
 type ContainerClient interface {
 	UpdateAddonsConfig(context.Context, string, string, string, *container.SetAddonsConfigRequest) (*container.Operation, error)
+	UpdateCluster(context.Context, string, string, string, *container.UpdateClusterRequest) (*container.Operation, error)
+	GetCluster(context.Context, string, string, string) (*container.Cluster, error)
 }
 
 // Container Service.
@@ -35,6 +37,11 @@ func NewContainer(client ContainerClient) *Container {
 	return &Container{client: client}
 }
 
+// Cluster returns the details of a given cluster.
+func (c *Container) Cluster(ctx context.Context, projectID, zone, clusterID string) (*container.Cluster, error) {
+	return c.client.GetCluster(ctx, projectID, zone, clusterID)
+}
+
 // DisableDashboard disables the Kubernetes Dashboard for a given cluster.
 func (c *Container) DisableDashboard(ctx context.Context, projectID, zone, clusterID string) (*container.Operation, error) {
 	req := &container.SetAddonsConfigRequest{
@@ -46,3 +53,21 @@ func (c *Container) DisableDashboard(ctx context.Context, projectID, zone, clust
 	}
 	return c.client.UpdateAddonsConfig(ctx, projectID, zone, clusterID, req)
 }
+
+// EnableMasterAuthorizedNetworks enables master authorized networks for a given cluster, only
+// allowing the provided CIDR blocks to reach the Kubernetes master.
+func (c *Container) EnableMasterAuthorizedNetworks(ctx context.Context, projectID, zone, clusterID string, cidrBlocks []string) (*container.Operation, error) {
+	blocks := make([]*container.CidrBlock, 0, len(cidrBlocks))
+	for _, cidr := range cidrBlocks {
+		blocks = append(blocks, &container.CidrBlock{CidrBlock: cidr})
+	}
+	req := &container.UpdateClusterRequest{
+		Update: &container.ClusterUpdate{
+			DesiredMasterAuthorizedNetworksConfig: &container.MasterAuthorizedNetworksConfig{
+				Enabled:    true,
+				CidrBlocks: blocks,
+			},
+		},
+	}
+	return c.client.UpdateCluster(ctx, projectID, zone, clusterID, req)
+}
@@ -0,0 +1,77 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"path"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// counterPrefix is the object prefix under which occurrences are stored.
+	counterPrefix = "counters/"
+	// counterTimeFormat names each occurrence after the time it happened.
+	counterTimeFormat = "20060102T150405.000000000Z"
+)
+
+// CounterStorage contains minimum interface required by the counter service.
+type CounterStorage interface {
+	WriteObject(context.Context, string, string, []byte) error
+	ListObjects(context.Context, string, string) ([]string, error)
+}
+
+// Counter service counts occurrences of an event within a time window.
+//
+// Each occurrence is stored as its own empty object in a GCS bucket so concurrent Cloud Functions
+// never overwrite each other's counts. Old occurrences should be removed by a lifecycle rule on
+// the bucket.
+type Counter struct {
+	storage CounterStorage
+}
+
+// NewCounter returns a counter service.
+func NewCounter(storage CounterStorage) *Counter {
+	return &Counter{storage: storage}
+}
+
+// Increment records an occurrence of the key at the given time.
+func (c *Counter) Increment(ctx context.Context, bucket, key string, now time.Time) error {
+	name := counterPrefix + key + "/" + now.UTC().Format(counterTimeFormat)
+	if err := c.storage.WriteObject(ctx, bucket, name, []byte{}); err != nil {
+		return errors.Wrapf(err, "failed to increment %q", key)
+	}
+	return nil
+}
+
+// Count returns the number of occurrences of the key recorded after the given time.
+func (c *Counter) Count(ctx context.Context, bucket, key string, since time.Time) (int, error) {
+	names, err := c.storage.ListObjects(ctx, bucket, counterPrefix+key+"/")
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to count %q", key)
+	}
+	count := 0
+	for _, name := range names {
+		t, err := time.Parse(counterTimeFormat, path.Base(name))
+		if err != nil {
+			continue
+		}
+		if t.After(since) {
+			count++
+		}
+	}
+	return count, nil
+}
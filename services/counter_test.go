@@ -0,0 +1,57 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+)
+
+// TestCounter tests counting occurrences within a window.
+func TestCounter(t *testing.T) {
+	ctx := context.Background()
+	c := NewCounter(&stubs.StorageStub{})
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		if err := c.Increment(ctx, "bucket", "non-org-members/test-project", start.Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatalf("Increment() failed: %q", err)
+		}
+	}
+	if err := c.Increment(ctx, "bucket", "non-org-members/other-project", start); err != nil {
+		t.Fatalf("Increment() failed: %q", err)
+	}
+	tests := []struct {
+		name     string
+		since    time.Time
+		expected int
+	}{
+		{name: "all occurrences", since: start.Add(-time.Minute), expected: 4},
+		{name: "within window", since: start.Add(90 * time.Minute), expected: 2},
+		{name: "none within window", since: start.Add(4 * time.Hour), expected: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.Count(ctx, "bucket", "non-org-members/test-project", tt.since)
+			if err != nil {
+				t.Fatalf("Count() failed: %q", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Count() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
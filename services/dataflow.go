@@ -0,0 +1,48 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	dataflow "google.golang.org/api/dataflow/v1b3"
+)
+
+// dataflowCancelled is the requested state used to cancel a Dataflow job.
+const dataflowCancelled = "JOB_STATE_CANCELLED"
+
+// DataflowClient contains minimum interface required by the Dataflow service.
+type DataflowClient interface {
+	UpdateJob(context.Context, string, string, string, *dataflow.Job) (*dataflow.Job, error)
+}
+
+// Dataflow service.
+type Dataflow struct {
+	client DataflowClient
+}
+
+// NewDataflow returns a Dataflow service.
+func NewDataflow(client DataflowClient) *Dataflow {
+	return &Dataflow{client: client}
+}
+
+// CancelJob requests the job be cancelled, stopping its workers without draining in flight data.
+func (d *Dataflow) CancelJob(ctx context.Context, projectID, location, jobID string) error {
+	if _, err := d.client.UpdateJob(ctx, projectID, location, jobID, &dataflow.Job{RequestedState: dataflowCancelled}); err != nil {
+		return errors.Wrapf(err, "failed to cancel dataflow job %q", jobID)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	dataproc "google.golang.org/api/dataproc/v1"
+)
+
+// DataprocClient contains minimum interface required by the Dataproc service.
+type DataprocClient interface {
+	CancelJob(context.Context, string, string, string) (*dataproc.Job, error)
+	DeleteCluster(context.Context, string, string, string) (*dataproc.Operation, error)
+}
+
+// Dataproc service.
+type Dataproc struct {
+	client DataprocClient
+}
+
+// NewDataproc returns a Dataproc service.
+func NewDataproc(client DataprocClient) *Dataproc {
+	return &Dataproc{client: client}
+}
+
+// CancelJob starts cancelling the job. Cancellation is asynchronous and the job may keep running
+// for a short time.
+func (d *Dataproc) CancelJob(ctx context.Context, projectID, region, jobID string) error {
+	if _, err := d.client.CancelJob(ctx, projectID, region, jobID); err != nil {
+		return errors.Wrapf(err, "failed to cancel dataproc job %q", jobID)
+	}
+	return nil
+}
+
+// DeleteCluster deletes the cluster along with any jobs running on it.
+func (d *Dataproc) DeleteCluster(ctx context.Context, projectID, region, cluster string) error {
+	if _, err := d.client.DeleteCluster(ctx, projectID, region, cluster); err != nil {
+		return errors.Wrapf(err, "failed to delete dataproc cluster %q", cluster)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	firestore "google.golang.org/api/firestore/v1"
+)
+
+const (
+	// DeadLetterTopic is the topic failed remediations are published to.
+	DeadLetterTopic = "threat-findings-dead-letter"
+	// deadLetterCollection is the Firestore collection of failed remediations waiting to be replayed.
+	deadLetterCollection = "automation-dead-letters"
+	// AttemptAttribute is the message attribute counting the replays of a remediation.
+	AttemptAttribute = "attempt"
+)
+
+const (
+	defaultDeadLetterBackoff     = 5 * time.Minute
+	defaultDeadLetterMaxBackoff  = 6 * time.Hour
+	defaultDeadLetterMaxAttempts = 5
+)
+
+// DeadLetterConfig configures how failed remediations are replayed.
+type DeadLetterConfig struct {
+	// Backoff is how long a failed remediation waits before it is replayed, doubled after each
+	// replay that fails again. Defaults to 5 minutes.
+	Backoff time.Duration
+	// MaxBackoff caps how long a failed remediation waits, defaults to 6 hours.
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+	// MaxAttempts is how many times a remediation is replayed on schedule, after which it's only
+	// replayed on demand. Defaults to 5.
+	MaxAttempts int `yaml:"max_attempts"`
+}
+
+// Delay returns how long a remediation replayed the given number of times waits before its next
+// replay.
+func (c DeadLetterConfig) Delay(attempts int) time.Duration {
+	backoff, max := c.Backoff, c.MaxBackoff
+	if backoff <= 0 {
+		backoff = defaultDeadLetterBackoff
+	}
+	if max <= 0 {
+		max = defaultDeadLetterMaxBackoff
+	}
+	d := backoff
+	for i := 0; i < attempts && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d
+}
+
+// Replays returns whether a remediation replayed the given number of times is replayed on schedule
+// once it fails again.
+func (c DeadLetterConfig) Replays(attempts int) bool {
+	return attempts < c.maxAttempts()
+}
+
+func (c DeadLetterConfig) maxAttempts() int {
+	if c.MaxAttempts <= 0 {
+		return defaultDeadLetterMaxAttempts
+	}
+	return c.MaxAttempts
+}
+
+// DeadLetter is a failed remediation, with the message it failed on so it can be replayed.
+type DeadLetter struct {
+	// ID is the ID of the failed remediation in the audit trail.
+	ID string
+	// Action is the automation's Cloud Function, such as "OpenFirewall".
+	Action string
+	// Topic is the topic the message was published to, which it is replayed to.
+	Topic      string
+	Data       []byte
+	Attributes map[string]string
+	Error      string
+	// Attempts is how many times the remediation was replayed before this failure.
+	Attempts int
+	Failed   time.Time
+	// NextAttempt is when the remediation is replayed, zero once its attempts are exhausted.
+	NextAttempt time.Time
+}
+
+// DeadLetterDocumentClient contains minimum interface required by the dead letters service.
+type DeadLetterDocumentClient interface {
+	CreateDocument(ctx context.Context, projectID, collection, documentID string, fields map[string]firestore.Value) error
+	ListDocuments(ctx context.Context, projectID, collection string) ([]*firestore.Document, error)
+	DeleteDocument(ctx context.Context, projectID, collection, documentID, updateTime string) error
+}
+
+// DeadLetters service keeps failed remediations until they are replayed.
+type DeadLetters struct {
+	documents DeadLetterDocumentClient
+	projectID string
+	conf      DeadLetterConfig
+}
+
+// NewDeadLetters returns a dead letters service storing failed remediations in the automation project.
+func NewDeadLetters(documents DeadLetterDocumentClient, projectID string, conf DeadLetterConfig) *DeadLetters {
+	return &DeadLetters{documents: documents, projectID: projectID, conf: conf}
+}
+
+// Store keeps the failed remediation until it is replayed, after the backoff of its attempts. A
+// remediation whose attempts are exhausted is kept until it is replayed on demand.
+func (d *DeadLetters) Store(ctx context.Context, l *DeadLetter, now time.Time) error {
+	l.NextAttempt = time.Time{}
+	if d.conf.Replays(l.Attempts) {
+		l.NextAttempt = now.Add(d.conf.Delay(l.Attempts)).UTC()
+	}
+	b, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	fields := map[string]firestore.Value{
+		"action":      {StringValue: l.Action},
+		"failed":      {TimestampValue: l.Failed.UTC().Format(time.RFC3339Nano)},
+		"dead_letter": {StringValue: string(b)},
+	}
+	if err := d.documents.CreateDocument(ctx, d.projectID, deadLetterCollection, l.ID, fields); err != nil {
+		return errors.Wrapf(err, "failed to store dead letter of %q", l.Action)
+	}
+	return nil
+}
+
+// List returns the failed remediations kept, including those whose attempts are exhausted.
+func (d *DeadLetters) List(ctx context.Context) ([]*DeadLetter, error) {
+	docs, err := d.documents.ListDocuments(ctx, d.projectID, deadLetterCollection)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list dead letters")
+	}
+	letters := make([]*DeadLetter, 0, len(docs))
+	for _, doc := range docs {
+		var l DeadLetter
+		if err := json.Unmarshal([]byte(doc.Fields["dead_letter"].StringValue), &l); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse dead letter %q", doc.Name)
+		}
+		letters = append(letters, &l)
+	}
+	return letters, nil
+}
+
+// Due returns whether the failed remediation is to be replayed on schedule by now.
+func (l *DeadLetter) Due(now time.Time) bool {
+	return !l.NextAttempt.IsZero() && !now.Before(l.NextAttempt)
+}
+
+// Remove deletes the failed remediation once it is replayed.
+func (d *DeadLetters) Remove(ctx context.Context, l *DeadLetter) error {
+	if err := d.documents.DeleteDocument(ctx, d.projectID, deadLetterCollection, l.ID, ""); err != nil && !notFound(err) {
+		return errors.Wrapf(err, "failed to remove dead letter %q", l.ID)
+	}
+	return nil
+}
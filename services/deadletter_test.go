@@ -0,0 +1,92 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+)
+
+func TestDeadLetterDelay(t *testing.T) {
+	conf := DeadLetterConfig{Backoff: time.Minute, MaxBackoff: 10 * time.Minute}
+	for _, tt := range []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 0, want: time.Minute},
+		{attempts: 1, want: 2 * time.Minute},
+		{attempts: 3, want: 8 * time.Minute},
+		{attempts: 4, want: 10 * time.Minute},
+		{attempts: 100, want: 10 * time.Minute},
+	} {
+		if got := conf.Delay(tt.attempts); got != tt.want {
+			t.Errorf("%d attempts failed: got %s want %s", tt.attempts, got, tt.want)
+		}
+	}
+	if got := (DeadLetterConfig{}).Delay(1); got != 2*defaultDeadLetterBackoff {
+		t.Errorf("default backoff failed: got %s", got)
+	}
+}
+
+func TestDeadLetterReplays(t *testing.T) {
+	conf := DeadLetterConfig{MaxAttempts: 2}
+	for attempts, want := range []bool{true, true, false} {
+		if got := conf.Replays(attempts); got != want {
+			t.Errorf("%d attempts failed: got %t want %t", attempts, got, want)
+		}
+	}
+	if got := (DeadLetterConfig{}).Replays(defaultDeadLetterMaxAttempts); got {
+		t.Errorf("default max attempts failed: got %t", got)
+	}
+}
+
+func TestDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	fsStub := &stubs.FirestoreStub{}
+	d := NewDeadLetters(fsStub, "automation-project", DeadLetterConfig{Backoff: time.Minute, MaxAttempts: 2})
+	for _, l := range []*DeadLetter{
+		{ID: "first", Action: "OpenFirewall", Topic: "threat-findings-open-firewall", Data: []byte(`{}`), Failed: now},
+		{ID: "retried", Action: "OpenFirewall", Topic: "threat-findings-open-firewall", Attempts: 1, Failed: now},
+		{ID: "exhausted", Action: "OpenFirewall", Topic: "threat-findings-open-firewall", Attempts: 2, Failed: now},
+	} {
+		if err := d.Store(ctx, l, now); err != nil {
+			t.Fatalf("failed to store %q: %q", l.ID, err)
+		}
+	}
+	letters, err := d.List(ctx)
+	if err != nil {
+		t.Fatalf("failed to list dead letters: %q", err)
+	}
+	if len(letters) != 3 {
+		t.Fatalf("got %d dead letters want 3", len(letters))
+	}
+	due := map[string]bool{}
+	for _, l := range letters {
+		due[l.ID] = l.Due(now.Add(90 * time.Second))
+	}
+	if !due["first"] || due["retried"] || due["exhausted"] {
+		t.Errorf("unexpected due dead letters %v", due)
+	}
+	if err := d.Remove(ctx, letters[0]); err != nil {
+		t.Fatalf("failed to remove dead letter: %q", err)
+	}
+	if len(fsStub.StubbedDocuments) != 2 {
+		t.Errorf("got %d dead letters after removing one want 2", len(fsStub.StubbedDocuments))
+	}
+}
@@ -0,0 +1,165 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	firestore "google.golang.org/api/firestore/v1"
+)
+
+// digestCollection is the Firestore collection of notifications waiting for their digest.
+const digestCollection = "automation-notification-digests"
+
+// DigestConfig configures batching the notifications of a channel into digests.
+type DigestConfig struct {
+	// Window is how long notifications are batched for before their digest is sent, such as "15m".
+	Window time.Duration
+	// Immediate are the severities of findings whose notifications are sent right away, defaults
+	// to critical findings.
+	Immediate []string
+}
+
+// DigestDocumentClient contains minimum interface required by the digests service.
+type DigestDocumentClient interface {
+	CreateDocument(ctx context.Context, projectID, collection, documentID string, fields map[string]firestore.Value) error
+	ListDocuments(ctx context.Context, projectID, collection string) ([]*firestore.Document, error)
+	DeleteDocument(ctx context.Context, projectID, collection, documentID, updateTime string) error
+}
+
+// Digests service keeps the notifications of digested channels until their digest is sent.
+type Digests struct {
+	documents DigestDocumentClient
+	projectID string
+	configs   map[string]DigestConfig
+}
+
+// NewDigests returns a digests service batching the channels as configured, keyed by channel name.
+func NewDigests(documents DigestDocumentClient, projectID string, configs map[string]DigestConfig) *Digests {
+	return &Digests{documents: documents, projectID: projectID, configs: configs}
+}
+
+// Queue keeps the notification for the digest of the channel. False is returned, and nothing is
+// kept, if the channel is not digested or the severity of the finding is sent right away. Digests
+// don't carry the attachments of the notifications.
+func (d *Digests) Queue(ctx context.Context, channel string, n *Notification) (bool, error) {
+	conf, ok := d.configs[channel]
+	if !ok || conf.Window <= 0 || immediate(conf, n.Severity) {
+		return false, nil
+	}
+	b, err := json.Marshal(n)
+	if err != nil {
+		return false, err
+	}
+	fields := map[string]firestore.Value{
+		"channel":      {StringValue: channel},
+		"time":         {TimestampValue: n.Time.UTC().Format(time.RFC3339Nano)},
+		"notification": {StringValue: string(b)},
+	}
+	if err := d.documents.CreateDocument(ctx, d.projectID, digestCollection, documentID(channel+"/"+n.RemediationID), fields); err != nil {
+		return false, errors.Wrapf(err, "failed to queue notification for %q", channel)
+	}
+	return true, nil
+}
+
+func immediate(conf DigestConfig, severity string) bool {
+	severities := conf.Immediate
+	if len(severities) == 0 {
+		severities = []string{"CRITICAL"}
+	}
+	for _, s := range severities {
+		if strings.EqualFold(s, severity) {
+			return true
+		}
+	}
+	return false
+}
+
+// queued is a notification waiting for its digest.
+type queued struct {
+	id           string
+	channel      string
+	notification *Notification
+}
+
+// due returns the notifications of the channels whose oldest notification has waited for the
+// window of the channel, keyed by channel. Channels no longer digested are due right away.
+func (d *Digests) due(ctx context.Context, now time.Time) (map[string][]*queued, error) {
+	docs, err := d.documents.ListDocuments(ctx, d.projectID, digestCollection)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list queued notifications")
+	}
+	channels := map[string][]*queued{}
+	oldest := map[string]time.Time{}
+	for _, doc := range docs {
+		var n Notification
+		if err := json.Unmarshal([]byte(doc.Fields["notification"].StringValue), &n); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse queued notification %q", doc.Name)
+		}
+		c := doc.Fields["channel"].StringValue
+		channels[c] = append(channels[c], &queued{id: doc.Name[strings.LastIndex(doc.Name, "/")+1:], channel: c, notification: &n})
+		if t, ok := oldest[c]; !ok || n.Time.Before(t) {
+			oldest[c] = n.Time
+		}
+	}
+	for c := range channels {
+		if now.Sub(oldest[c]) < d.configs[c].Window {
+			delete(channels, c)
+		}
+	}
+	return channels, nil
+}
+
+// remove deletes the notifications sent in a digest.
+func (d *Digests) remove(ctx context.Context, sent []*queued) error {
+	for _, q := range sent {
+		if err := d.documents.DeleteDocument(ctx, d.projectID, digestCollection, q.id, ""); err != nil && !notFound(err) {
+			return errors.Wrapf(err, "failed to remove notification queued for %q", q.channel)
+		}
+	}
+	return nil
+}
+
+// DigestAction is the action of digest notifications.
+const DigestAction = "Digest"
+
+// NewDigest returns the notification summarizing the notifications, oldest first.
+func NewDigest(ns []*Notification) *Notification {
+	sort.SliceStable(ns, func(i, j int) bool { return ns[i].Time.Before(ns[j].Time) })
+	outcomes := map[string]int{}
+	for _, n := range ns {
+		outcomes[n.Outcome]++
+	}
+	names := make([]string, 0, len(outcomes))
+	for o := range outcomes {
+		names = append(names, o)
+	}
+	sort.Strings(names)
+	counts := make([]string, 0, len(names))
+	for _, o := range names {
+		counts = append(counts, fmt.Sprintf("%d %s", outcomes[o], o))
+	}
+	d := &Notification{Action: DigestAction, Outcome: strings.Join(counts, ", "), Digest: ns}
+	if len(ns) > 0 {
+		d.Time = ns[len(ns)-1].Time
+	}
+	return d
+}
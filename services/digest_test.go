@@ -0,0 +1,72 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+)
+
+func TestDigests(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2020, 6, 2, 0, 0, 0, 0, time.UTC)
+	fsStub := &stubs.FirestoreStub{}
+	slackStub := &stubs.WebhookStub{}
+	webhookStub := &stubs.WebhookStub{}
+	notifications := NewDigestedNotifications(map[string]Notifier{
+		"slack":   NewSlackWebhookNotifier(slackStub),
+		"webhook": NewWebhookNotifier(webhookStub),
+	}, NewDigests(fsStub, "automation-project", map[string]DigestConfig{"slack": {Window: 15 * time.Minute}}))
+	for i, n := range []*Notification{
+		{RemediationID: "r1", Time: start, FindingID: "f1", Severity: "HIGH", Action: "CloseBucket", Outcome: AuditSuccess},
+		{RemediationID: "r2", Time: start.Add(time.Minute), FindingID: "f2", Severity: "LOW", Action: "CloseBucket", Outcome: AuditFailure},
+		{RemediationID: "r3", Time: start.Add(2 * time.Minute), FindingID: "f3", Severity: "CRITICAL", Action: "CloseBucket", Outcome: AuditFailure},
+	} {
+		if err := notifications.Notify(ctx, []string{"slack", "webhook"}, n); err != nil {
+			t.Fatalf("failed to notify %d: %q", i, err)
+		}
+	}
+	if len(webhookStub.SavedRequests) != 3 {
+		t.Errorf("got %d webhook requests want 3, only digested channels should be batched", len(webhookStub.SavedRequests))
+	}
+	if len(slackStub.SavedRequests) != 1 {
+		t.Fatalf("got %d slack messages want 1, critical findings should skip the digest", len(slackStub.SavedRequests))
+	}
+	if err := notifications.SendDigests(ctx, start.Add(10*time.Minute)); err != nil {
+		t.Fatalf("failed to send digests: %q", err)
+	}
+	if len(slackStub.SavedRequests) != 1 {
+		t.Fatalf("got %d slack messages want 1, digest sent before its window passed", len(slackStub.SavedRequests))
+	}
+	if err := notifications.SendDigests(ctx, start.Add(15*time.Minute)); err != nil {
+		t.Fatalf("failed to send digests: %q", err)
+	}
+	if len(slackStub.SavedRequests) != 2 {
+		t.Fatalf("got %d slack messages want 2", len(slackStub.SavedRequests))
+	}
+	digest := slackStub.SavedRequests[1]
+	for _, want := range []string{"digest: 2 remediations, 1 failure, 1 success", `finding \"f1\"`, `finding \"f2\"`} {
+		if !strings.Contains(digest, want) {
+			t.Errorf("digest %s does not contain %q", digest, want)
+		}
+	}
+	if len(fsStub.StubbedDocuments) != 0 {
+		t.Errorf("got %d queued notifications after the digest was sent", len(fsStub.StubbedDocuments))
+	}
+}
@@ -0,0 +1,55 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"fmt"
+
+	dns "google.golang.org/api/dns/v1"
+)
+
+// DNSClient contains minimum interface required by the service.
+type DNSClient interface {
+	GetManagedZone(context.Context, string, string) (*dns.ManagedZone, error)
+	PatchManagedZone(context.Context, string, string, *dns.ManagedZone) (*dns.Operation, error)
+}
+
+// DNS service.
+type DNS struct {
+	client DNSClient
+}
+
+// NewDNS returns a DNS service.
+func NewDNS(client DNSClient) *DNS {
+	return &DNS{client: client}
+}
+
+// ManagedZone returns the managed zone.
+func (d *DNS) ManagedZone(ctx context.Context, projectID, zone string) (*dns.ManagedZone, error) {
+	mz, err := d.client.GetManagedZone(ctx, projectID, zone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get managed zone: %q", err)
+	}
+	return mz, nil
+}
+
+// EnableDNSSEC turns DNSSEC on for the managed zone using the zone's default key specs.
+func (d *DNS) EnableDNSSEC(ctx context.Context, projectID, zone string) error {
+	patch := &dns.ManagedZone{DnssecConfig: &dns.ManagedZoneDnsSecConfig{State: "on"}}
+	if _, err := d.client.PatchManagedZone(ctx, projectID, zone, patch); err != nil {
+		return fmt.Errorf("failed to enable dnssec: %q", err)
+	}
+	return nil
+}
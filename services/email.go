@@ -16,9 +16,11 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"html/template"
 	"path/filepath"
 
+	"github.com/googlecloudplatform/security-response-automation/clients"
 	"github.com/pkg/errors"
 	"github.com/sendgrid/rest"
 )
@@ -35,7 +37,19 @@ var (
 
 // EmailClient is the interface used for sending emails.
 type EmailClient interface {
-	Send(subject, from, body string, to []string) (*rest.Response, error)
+	Send(ctx context.Context, subject, from, body string, to []string, attachments ...clients.Attachment) (*rest.Response, error)
+}
+
+// SMTPConfig configures sending emails through an SMTP server instead of SendGrid.
+type SMTPConfig struct {
+	Host string
+	// Port defaults to 587, the submission port.
+	Port     int
+	Username string
+	Password string
+	// PasswordSecret is the Secret Manager secret version holding the password, such as
+	// "projects/automation-project/secrets/smtp-password/versions/latest", used instead of Password.
+	PasswordSecret string `yaml:"password_secret"`
 }
 
 // EmailResponse contains the response from sending an email.
@@ -54,9 +68,9 @@ func NewEmail(service EmailClient) *Email {
 	return &Email{service: service}
 }
 
-// Send will send an email.
-func (m *Email) Send(subject, from, body string, to []string) (*rest.Response, error) {
-	return m.service.Send(subject, from, body, to)
+// Send will send an email with the attachments, if any.
+func (m *Email) Send(ctx context.Context, subject, from, body string, to []string, attachments ...clients.Attachment) (*rest.Response, error) {
+	return m.service.Send(ctx, subject, from, body, to, attachments...)
 }
 
 // RenderTemplate parses the content based on template.
@@ -0,0 +1,234 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	firestore "google.golang.org/api/firestore/v1"
+	crm "google.golang.org/genproto/googleapis/cloud/securitycenter/v1beta1"
+	"google.golang.org/genproto/protobuf/field_mask"
+)
+
+const (
+	// exemptionPrefix starts the security marks exempting an asset from remediation, such as the
+	// allow_non_org_iam_member=true mark suggested by findings.
+	exemptionPrefix = "allow_"
+	// expirySuffix ends the security mark holding when an exemption expires, such as
+	// allow_non_org_iam_member_expires=2020-07-01T00:00:00Z.
+	expirySuffix = "_expires"
+	// suppressedCollection is the Firestore collection of findings skipped until their exemption expires.
+	suppressedCollection = "automation-suppressed"
+)
+
+// AssetExemption is an exemption mark set on an asset.
+type AssetExemption struct {
+	// Asset is the Security Command Center name of the asset, such as organizations/123/assets/456.
+	Asset string
+	Mark  string
+	// Expires is when the exemption expires, zero if it doesn't.
+	Expires time.Time
+}
+
+// Exemption returns the exemption mark set on the asset of the finding that hasn't expired by now,
+// or nil if the asset isn't exempted. The asset is looked up in the organization of the finding.
+func (r *CommandCenter) Exemption(ctx context.Context, findingName, resourceName string, now time.Time) (*AssetExemption, error) {
+	org := findingOrganization(findingName)
+	if org == "" {
+		return nil, fmt.Errorf("finding %q isn't in an organization", findingName)
+	}
+	assets, err := r.client.ListAssets(ctx, &crm.ListAssetsRequest{
+		Parent: org,
+		Filter: fmt.Sprintf("security_center_properties.resource_name = %q", resourceName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list assets of %q: %q", resourceName, err)
+	}
+	for _, a := range assets {
+		marks := a.GetAsset().GetSecurityMarks().GetMarks()
+		if m, expires := ExemptionMark(marks, now); m != "" {
+			return &AssetExemption{Asset: a.GetAsset().GetName(), Mark: m, Expires: expires}, nil
+		}
+	}
+	return nil, nil
+}
+
+// ExemptionMark returns the first exemption mark set to true that hasn't expired by now and when
+// it expires, or an empty string if there is none. Expiry marks that can't be parsed are ignored,
+// leaving the exemption in place.
+func ExemptionMark(marks map[string]string, now time.Time) (string, time.Time) {
+	keys := make([]string, 0, len(marks))
+	for k := range marks {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if !strings.HasPrefix(k, exemptionPrefix) || !strings.EqualFold(marks[k], "true") {
+			continue
+		}
+		expires, err := time.Parse(time.RFC3339, marks[k+expirySuffix])
+		if err != nil {
+			return k, time.Time{}
+		}
+		if now.Before(expires) {
+			return k, expires
+		}
+	}
+	return "", time.Time{}
+}
+
+// ClearExemption removes the exemption mark and its expiry from the asset.
+func (r *CommandCenter) ClearExemption(ctx context.Context, e *AssetExemption) error {
+	_, err := r.client.AddSecurityMarks(ctx, &crm.UpdateSecurityMarksRequest{
+		UpdateMask: &field_mask.FieldMask{
+			Paths: []string{"marks." + e.Mark, "marks." + e.Mark + expirySuffix},
+		},
+		SecurityMarks: &crm.SecurityMarks{
+			Name: e.Asset + "/securityMarks",
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to clear exemption %q of %q", e.Mark, e.Asset)
+	}
+	return nil
+}
+
+// FindingActive returns whether the finding is still active.
+func (r *CommandCenter) FindingActive(ctx context.Context, name string) (bool, error) {
+	i := strings.Index(name, "/findings/")
+	if i < 0 {
+		return false, fmt.Errorf("%q isn't a finding", name)
+	}
+	findings, err := r.client.ListFindings(ctx, &crm.ListFindingsRequest{
+		Parent: name[:i],
+		Filter: fmt.Sprintf("name = %q", name),
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get finding %q", name)
+	}
+	for _, f := range findings {
+		if f.GetName() == name {
+			return f.GetState() == crm.Finding_ACTIVE, nil
+		}
+	}
+	return false, nil
+}
+
+// documentID returns a Firestore document ID for the key, which may contain slashes such as
+// finding names do.
+func documentID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// findingOrganization returns the organization of a finding named
+// organizations/<id>/sources/<id>/findings/<id>.
+func findingOrganization(findingName string) string {
+	parts := strings.Split(findingName, "/")
+	if len(parts) < 2 || parts[0] != "organizations" || parts[1] == "" {
+		return ""
+	}
+	return parts[0] + "/" + parts[1]
+}
+
+// ExemptionDocumentClient contains minimum interface required by the exemptions service.
+type ExemptionDocumentClient interface {
+	CreateDocument(ctx context.Context, projectID, collection, documentID string, fields map[string]firestore.Value) error
+	ListDocuments(ctx context.Context, projectID, collection string) ([]*firestore.Document, error)
+	DeleteDocument(ctx context.Context, projectID, collection, documentID, updateTime string) error
+}
+
+// Exemptions service keeps the findings skipped because of an exemption that expires, so they can
+// be remediated once it does.
+type Exemptions struct {
+	documents ExemptionDocumentClient
+	projectID string
+}
+
+// Suppressed is a finding skipped until its exemption expires.
+type Suppressed struct {
+	ID          string
+	FindingName string
+	// Finding is the finding as received by the router.
+	Finding []byte
+	// Asset and Mark identify the exemption mark, they are empty for exemptions in the configuration.
+	Asset   string
+	Mark    string
+	Expires time.Time
+}
+
+// NewExemptions returns an exemptions service storing suppressed findings in the automation project.
+func NewExemptions(documents ExemptionDocumentClient, projectID string) *Exemptions {
+	return &Exemptions{documents: documents, projectID: projectID}
+}
+
+// Suppress stores the finding until its exemption expires. A finding suppressed again replaces
+// the previous one.
+func (e *Exemptions) Suppress(ctx context.Context, s *Suppressed) error {
+	s.ID = documentID(s.FindingName)
+	if err := e.documents.DeleteDocument(ctx, e.projectID, suppressedCollection, s.ID, ""); err != nil && !notFound(err) {
+		return errors.Wrapf(err, "failed to replace suppressed finding %q", s.FindingName)
+	}
+	fields := map[string]firestore.Value{
+		"finding_name": {StringValue: s.FindingName},
+		"finding":      {StringValue: string(s.Finding)},
+		"asset":        {StringValue: s.Asset},
+		"mark":         {StringValue: s.Mark},
+		"expires":      {TimestampValue: s.Expires.UTC().Format(time.RFC3339Nano)},
+	}
+	if err := e.documents.CreateDocument(ctx, e.projectID, suppressedCollection, s.ID, fields); err != nil {
+		return errors.Wrapf(err, "failed to store suppressed finding %q", s.FindingName)
+	}
+	return nil
+}
+
+// Suppressed returns the suppressed findings.
+func (e *Exemptions) Suppressed(ctx context.Context) ([]*Suppressed, error) {
+	docs, err := e.documents.ListDocuments(ctx, e.projectID, suppressedCollection)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list suppressed findings")
+	}
+	suppressed := make([]*Suppressed, 0, len(docs))
+	for _, d := range docs {
+		expires, err := time.Parse(time.RFC3339Nano, d.Fields["expires"].TimestampValue)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse expiry of suppressed finding %q", d.Name)
+		}
+		suppressed = append(suppressed, &Suppressed{
+			ID:          d.Name[strings.LastIndex(d.Name, "/")+1:],
+			FindingName: d.Fields["finding_name"].StringValue,
+			Finding:     []byte(d.Fields["finding"].StringValue),
+			Asset:       d.Fields["asset"].StringValue,
+			Mark:        d.Fields["mark"].StringValue,
+			Expires:     expires,
+		})
+	}
+	return suppressed, nil
+}
+
+// Release removes the suppressed finding.
+func (e *Exemptions) Release(ctx context.Context, s *Suppressed) error {
+	if err := e.documents.DeleteDocument(ctx, e.projectID, suppressedCollection, s.ID, ""); err != nil {
+		return errors.Wrapf(err, "failed to release suppressed finding %q", s.FindingName)
+	}
+	return nil
+}
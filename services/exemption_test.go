@@ -0,0 +1,145 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	crm "google.golang.org/genproto/googleapis/cloud/securitycenter/v1beta1"
+)
+
+func TestExemption(t *testing.T) {
+	const resource = "//storage.googleapis.com/public-bucket"
+	now := time.Date(2020, 6, 2, 0, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		name        string
+		finding     string
+		marks       map[string]string
+		wantMark    string
+		wantExpires time.Time
+		wantErr     bool
+	}{
+		{name: "no marks", finding: "organizations/456/sources/1/findings/2"},
+		{name: "exemption mark", finding: "organizations/456/sources/1/findings/2", marks: map[string]string{"owner": "bob", "allow_public_bucket_acl": "TRUE"}, wantMark: "allow_public_bucket_acl"},
+		{name: "exemption mark not true", finding: "organizations/456/sources/1/findings/2", marks: map[string]string{"allow_public_bucket_acl": "false"}},
+		{name: "exemption mark expires", finding: "organizations/456/sources/1/findings/2", marks: map[string]string{"allow_public_bucket_acl": "true", "allow_public_bucket_acl_expires": "2020-07-01T00:00:00Z"}, wantMark: "allow_public_bucket_acl", wantExpires: time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "exemption mark expired", finding: "organizations/456/sources/1/findings/2", marks: map[string]string{"allow_public_bucket_acl": "true", "allow_public_bucket_acl_expires": "2020-06-01T00:00:00Z"}},
+		{name: "finding not in organization", finding: "projects/p/findings/2", wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sccStub := &stubs.SecurityCommandCenterStub{StubbedAssets: []*crm.ListAssetsResponse_ListAssetsResult{
+				{Asset: &crm.Asset{Name: "organizations/456/assets/789", SecurityMarks: &crm.SecurityMarks{Marks: tt.marks}}},
+			}}
+			got, err := NewCommandCenter(sccStub).Exemption(context.Background(), tt.finding, resource, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%s failed: got error %v", tt.name, err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if sccStub.GetListAssetsRequest.GetParent() != "organizations/456" {
+				t.Errorf("%s failed: got parent %q", tt.name, sccStub.GetListAssetsRequest.GetParent())
+			}
+			if tt.wantMark == "" {
+				if got != nil {
+					t.Errorf("%s failed: got exemption %+v", tt.name, got)
+				}
+				return
+			}
+			want := &AssetExemption{Asset: "organizations/456/assets/789", Mark: tt.wantMark, Expires: tt.wantExpires}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("%s failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestClearExemption(t *testing.T) {
+	sccStub := &stubs.SecurityCommandCenterStub{}
+	e := &AssetExemption{Asset: "organizations/456/assets/789", Mark: "allow_public_bucket_acl"}
+	if err := NewCommandCenter(sccStub).ClearExemption(context.Background(), e); err != nil {
+		t.Fatalf("failed to clear exemption: %q", err)
+	}
+	r := sccStub.GetUpdateSecurityMarksRequest
+	if got := r.GetSecurityMarks().GetName(); got != "organizations/456/assets/789/securityMarks" {
+		t.Errorf("got security marks %q", got)
+	}
+	if diff := cmp.Diff([]string{"marks.allow_public_bucket_acl", "marks.allow_public_bucket_acl_expires"}, r.GetUpdateMask().GetPaths()); diff != "" {
+		t.Errorf("difference: %+v", diff)
+	}
+	if len(r.GetSecurityMarks().GetMarks()) != 0 {
+		t.Errorf("got marks %v want none", r.GetSecurityMarks().GetMarks())
+	}
+}
+
+func TestFindingActive(t *testing.T) {
+	const name = "organizations/456/sources/1/findings/2"
+	for _, tt := range []struct {
+		name     string
+		findings []*crm.Finding
+		want     bool
+	}{
+		{name: "active", findings: []*crm.Finding{{Name: name, State: crm.Finding_ACTIVE}}, want: true},
+		{name: "inactive", findings: []*crm.Finding{{Name: name, State: crm.Finding_INACTIVE}}, want: false},
+		{name: "deleted", want: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCommandCenter(&stubs.SecurityCommandCenterStub{StubbedFindings: tt.findings})
+			got, err := c.FindingActive(context.Background(), name)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if got != tt.want {
+				t.Errorf("%s failed: got %t want %t", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuppress(t *testing.T) {
+	ctx := context.Background()
+	fs := &stubs.FirestoreStub{}
+	e := NewExemptions(fs, "automation-project")
+	s := &Suppressed{
+		FindingName: "organizations/456/sources/1/findings/2",
+		Finding:     []byte(`{"finding": {"name": "organizations/456/sources/1/findings/2"}}`),
+		Asset:       "organizations/456/assets/789",
+		Mark:        "allow_public_bucket_acl",
+		Expires:     time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC),
+	}
+	// Suppressing the finding again replaces it.
+	for i := 0; i < 2; i++ {
+		if err := e.Suppress(ctx, s); err != nil {
+			t.Fatalf("failed to suppress: %q", err)
+		}
+	}
+	got, err := e.Suppressed(ctx)
+	if err != nil {
+		t.Fatalf("failed to list suppressed findings: %q", err)
+	}
+	if diff := cmp.Diff([]*Suppressed{s}, got); diff != "" {
+		t.Errorf("difference: %+v", diff)
+	}
+	if err := e.Release(ctx, got[0]); err != nil {
+		t.Fatalf("failed to release: %q", err)
+	}
+	if got, _ := e.Suppressed(ctx); len(got) != 0 {
+		t.Errorf("got %d suppressed findings after release", len(got))
+	}
+}
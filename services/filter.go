@@ -0,0 +1,96 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// severities orders the Security Command Center severities from lowest to highest.
+var severities = []string{"LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+// FindingFilter restricts remediation to the findings matching all of its properties.
+type FindingFilter struct {
+	// MinSeverity is the lowest severity remediated, such as HIGH. Findings without a severity don't
+	// meet it.
+	MinSeverity string `yaml:"min_severity"`
+	// Sources are the Security Command Center sources remediated, either as source IDs or as names
+	// such as organizations/123/sources/456.
+	Sources []string
+	// ResourcePattern is a regular expression the resource name of the finding must match.
+	ResourcePattern string `yaml:"resource_pattern"`
+}
+
+// FilteredFinding holds the properties of a finding filters are evaluated against.
+type FilteredFinding struct {
+	// Name is the name of the finding, such as organizations/123/sources/456/findings/789.
+	Name         string
+	Severity     string
+	ResourceName string
+}
+
+// Match returns whether the finding matches the filter, or why it doesn't.
+func (f *FindingFilter) Match(finding *FilteredFinding) (bool, string, error) {
+	if f.MinSeverity != "" {
+		min := severityRank(f.MinSeverity)
+		if min < 0 {
+			return false, "", fmt.Errorf("unknown minimum severity %q", f.MinSeverity)
+		}
+		if severityRank(finding.Severity) < min {
+			return false, fmt.Sprintf("severity %q is below %q", finding.Severity, f.MinSeverity), nil
+		}
+	}
+	if len(f.Sources) > 0 && !f.fromSource(finding.Name) {
+		return false, fmt.Sprintf("finding %q isn't from sources %q", finding.Name, f.Sources), nil
+	}
+	if f.ResourcePattern != "" {
+		re, err := regexp.Compile(f.ResourcePattern)
+		if err != nil {
+			return false, "", fmt.Errorf("failed to compile resource pattern %q: %q", f.ResourcePattern, err)
+		}
+		if !re.MatchString(finding.ResourceName) {
+			return false, fmt.Sprintf("resource %q doesn't match %q", finding.ResourceName, f.ResourcePattern), nil
+		}
+	}
+	return true, "", nil
+}
+
+// fromSource returns whether the finding was reported by one of the sources of the filter.
+func (f *FindingFilter) fromSource(name string) bool {
+	i := strings.Index(name, "/findings/")
+	if i < 0 {
+		return false
+	}
+	source := name[:i]
+	id := source[strings.LastIndex(source, "/")+1:]
+	for _, s := range f.Sources {
+		if s == source || s == id {
+			return true
+		}
+	}
+	return false
+}
+
+// severityRank returns the position of the severity in severities, or -1 if it's unknown.
+func severityRank(severity string) int {
+	for i, s := range severities {
+		if strings.EqualFold(s, severity) {
+			return i
+		}
+	}
+	return -1
+}
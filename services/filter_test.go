@@ -0,0 +1,61 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import "testing"
+
+func TestFindingFilterMatch(t *testing.T) {
+	const name = "organizations/123/sources/456/findings/789"
+	const resource = "//compute.googleapis.com/projects/prod-project/global/firewalls/123"
+	for _, tt := range []struct {
+		name     string
+		filter   FindingFilter
+		severity string
+		want     bool
+		wantErr  bool
+	}{
+		{name: "empty filter", severity: "LOW", want: true},
+		{name: "severity above threshold", filter: FindingFilter{MinSeverity: "HIGH"}, severity: "CRITICAL", want: true},
+		{name: "severity at threshold", filter: FindingFilter{MinSeverity: "high"}, severity: "HIGH", want: true},
+		{name: "severity below threshold", filter: FindingFilter{MinSeverity: "HIGH"}, severity: "MEDIUM"},
+		{name: "missing severity", filter: FindingFilter{MinSeverity: "LOW"}},
+		{name: "unknown threshold", filter: FindingFilter{MinSeverity: "SEVERE"}, severity: "HIGH", wantErr: true},
+		{name: "source ID", filter: FindingFilter{Sources: []string{"456"}}, want: true},
+		{name: "source name", filter: FindingFilter{Sources: []string{"organizations/123/sources/456"}}, want: true},
+		{name: "other source", filter: FindingFilter{Sources: []string{"111"}}},
+		{name: "resource matching", filter: FindingFilter{ResourcePattern: "/projects/prod-"}, want: true},
+		{name: "resource not matching", filter: FindingFilter{ResourcePattern: "^//storage"}},
+		{name: "invalid pattern", filter: FindingFilter{ResourcePattern: "("}, wantErr: true},
+		{
+			name:     "all properties",
+			filter:   FindingFilter{MinSeverity: "MEDIUM", Sources: []string{"456"}, ResourcePattern: "firewalls"},
+			severity: "HIGH",
+			want:     true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason, err := tt.filter.Match(&FilteredFinding{Name: name, Severity: tt.severity, ResourceName: resource})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%s failed: got error %v", tt.name, err)
+			}
+			if got != tt.want {
+				t.Errorf("%s failed: got %t want %t", tt.name, got, tt.want)
+			}
+			if !got && !tt.wantErr && reason == "" {
+				t.Errorf("%s failed: no reason given", tt.name)
+			}
+		})
+	}
+}
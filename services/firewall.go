@@ -17,15 +17,28 @@ package services
 import (
 	"context"
 	"fmt"
-	"log"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	compute "google.golang.org/api/compute/v1"
 	"google.golang.org/api/googleapi"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
 )
 
-// sshBlockName is the firewall rule name created when blocking SSH.
-const sshBlockName = "automatic-ssh-block"
+const (
+	// sshBlockName is the firewall rule name created when blocking SSH.
+	sshBlockName = "automatic-ssh-block"
+	// sshBlockDescription is the description of firewall rules created when blocking SSH.
+	sshBlockDescription = "Block SSH TCP port 22 by Security Response Automation"
+	// sshBlockExpiresAt precedes the expiry time saved in the description of SSH block rules.
+	sshBlockExpiresAt = "expires-at="
+	// quarantineName prefixes the firewall rules created to isolate quarantined instances.
+	quarantineName = "sra-quarantine"
+	// quarantineDescription is the description of firewall rules created to isolate quarantined instances.
+	quarantineDescription = "Deny all traffic to quarantined instances by Security Response Automation"
+)
 
 // FirewallClient holds the minimum interface required by the firewall service.
 type FirewallClient interface {
@@ -33,7 +46,12 @@ type FirewallClient interface {
 	PatchFirewallRule(context.Context, string, string, *compute.Firewall) (*compute.Operation, error)
 	FirewallRule(context.Context, string, string) (*compute.Firewall, error)
 	DeleteFirewallRule(context.Context, string, string) (*compute.Operation, error)
+	ListFirewallRules(context.Context, string) (*compute.FirewallList, error)
+	SetPrivateIPGoogleAccess(context.Context, string, string, string, bool) (*compute.Operation, error)
+	Subnetwork(context.Context, string, string, string) (*compute.Subnetwork, error)
+	PatchSubnetwork(context.Context, string, string, string, *compute.Subnetwork) (*compute.Operation, error)
 	WaitGlobal(string, *compute.Operation) []error
+	WaitRegion(string, string, *compute.Operation) []error
 }
 
 // Firewall service.
@@ -48,12 +66,12 @@ func NewFirewall(client FirewallClient) *Firewall {
 
 // BlockSSH will add a firewall rule that blocks SSH for the given project.
 func (f *Firewall) BlockSSH(ctx context.Context, projectID string, sourceRanges []string) error {
-	log.Printf("will attempt to block ssh for %q in %q", sourceRanges, projectID)
+	logging.FromContext(ctx).Info("will attempt to block ssh for %q in %q", sourceRanges, projectID)
 	fw, err := f.FirewallRule(ctx, projectID, sshBlockName)
 	if err != nil {
-		switch err.(*googleapi.Error).Code {
-		case 404:
-			log.Println("adding a new firewall rule to block ssh")
+		switch {
+		case notFound(err):
+			logging.FromContext(ctx).Info("adding a new firewall rule to block ssh")
 			return f.addFirewallRule(ctx, projectID, &compute.Firewall{
 				Denied: []*compute.FirewallDenied{
 					{
@@ -61,7 +79,7 @@ func (f *Firewall) BlockSSH(ctx context.Context, projectID string, sourceRanges
 						Ports:      []string{"22"},
 					},
 				},
-				Description:  "Block SSH TCP port 22 by Security Response Automation",
+				Description:  sshBlockDescription,
 				Name:         sshBlockName,
 				SourceRanges: sourceRanges,
 			})
@@ -70,17 +88,162 @@ func (f *Firewall) BlockSSH(ctx context.Context, projectID string, sourceRanges
 		}
 	}
 
-	log.Printf("existing rule found, combine incoming source ranges %q with existing %q", sourceRanges, fw.SourceRanges)
+	logging.FromContext(ctx).Info("existing rule found, combine incoming source ranges %q with existing %q", sourceRanges, fw.SourceRanges)
 	// Consider deduping. Currently this is done by the API.
 	sourceRanges = append(sourceRanges, fw.SourceRanges...)
 	ruleID := fmt.Sprintf("%d", fw.Id)
 	if err := f.UpdateFirewallRuleSourceRange(ctx, projectID, ruleID, fw.Name, sourceRanges); err != nil {
 		return errors.Wrapf(err, "failed to update source ranges for: %q %q %q", projectID, ruleID, fw.Name)
 	}
-	log.Printf("firewall rule %q updated in %q", fw.Name, projectID)
+	logging.FromContext(ctx).Info("firewall rule %q updated in %q", fw.Name, projectID)
+	return nil
+}
+
+// BlockSSHNetwork will add a deny ingress rule blocking SSH from the source ranges to the given network.
+//
+// An existing rule for the network has the incoming source ranges combined with its own. If expiry is set
+// it's saved within the rule's description so RemoveExpiredSSHBlocks can remove the rule later.
+func (f *Firewall) BlockSSHNetwork(ctx context.Context, projectID, network string, sourceRanges []string, priority int64, expiry time.Time) error {
+	name := sshBlockNetworkName(network)
+	description := sshBlockDescription
+	if !expiry.IsZero() {
+		description = fmt.Sprintf("%s, %s%s", sshBlockDescription, sshBlockExpiresAt, expiry.UTC().Format(time.RFC3339))
+	}
+	logging.FromContext(ctx).Info("will attempt to block ssh for %q to network %q in %q", sourceRanges, network, projectID)
+	fw, err := f.FirewallRule(ctx, projectID, name)
+	if err != nil {
+		if e, ok := err.(*googleapi.Error); !ok || e.Code != 404 {
+			return errors.Wrapf(err, "failed getting firewall rule: %q", name)
+		}
+		logging.FromContext(ctx).Info("adding a new firewall rule %q to block ssh", name)
+		return f.addFirewallRule(ctx, projectID, &compute.Firewall{
+			Denied: []*compute.FirewallDenied{
+				{
+					IPProtocol: "tcp",
+					Ports:      []string{"22"},
+				},
+			},
+			Description:  description,
+			Direction:    "INGRESS",
+			Name:         name,
+			Network:      network,
+			Priority:     priority,
+			SourceRanges: sourceRanges,
+		})
+	}
+	logging.FromContext(ctx).Info("existing rule found, combine incoming source ranges %q with existing %q", sourceRanges, fw.SourceRanges)
+	ruleID := fmt.Sprintf("%d", fw.Id)
+	op, err := f.client.PatchFirewallRule(ctx, projectID, ruleID, &compute.Firewall{
+		Name:         fw.Name,
+		Description:  description,
+		Priority:     priority,
+		SourceRanges: append(sourceRanges, fw.SourceRanges...),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update firewall rule: %q %q %q", projectID, ruleID, fw.Name)
+	}
+	if errs := f.WaitGlobal(projectID, op); len(errs) > 0 {
+		return errs[0]
+	}
+	logging.FromContext(ctx).Info("firewall rule %q updated in %q", fw.Name, projectID)
 	return nil
 }
 
+// RemoveExpiredSSHBlocks deletes the SSH block rules within the project that expired before now.
+func (f *Firewall) RemoveExpiredSSHBlocks(ctx context.Context, projectID string, now time.Time) ([]string, error) {
+	rules, err := f.client.ListFirewallRules(ctx, projectID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list firewall rules in %q", projectID)
+	}
+	removed := []string{}
+	for _, fw := range rules.Items {
+		if !strings.HasPrefix(fw.Name, sshBlockName) {
+			continue
+		}
+		expiry, ok := sshBlockExpiry(fw.Description)
+		if !ok || expiry.After(now) {
+			continue
+		}
+		op, err := f.client.DeleteFirewallRule(ctx, projectID, fw.Name)
+		if err != nil {
+			return removed, errors.Wrapf(err, "failed to delete firewall rule: %q", fw.Name)
+		}
+		if errs := f.WaitGlobal(projectID, op); len(errs) > 0 {
+			return removed, errs[0]
+		}
+		removed = append(removed, fw.Name)
+	}
+	return removed, nil
+}
+
+// QuarantineNetwork ensures the given network has deny all ingress and egress rules applied to
+// instances carrying the tag. The names of the rules created are returned, existing rules are left as is.
+func (f *Firewall) QuarantineNetwork(ctx context.Context, projectID, network, tag string) ([]string, error) {
+	created := []string{}
+	for _, direction := range []string{"INGRESS", "EGRESS"} {
+		name := quarantineRuleName(network, direction)
+		if _, err := f.FirewallRule(ctx, projectID, name); err == nil {
+			continue
+		} else if e, ok := err.(*googleapi.Error); !ok || e.Code != 404 {
+			return created, errors.Wrapf(err, "failed getting firewall rule: %q", name)
+		}
+		fw := &compute.Firewall{
+			Denied:      []*compute.FirewallDenied{{IPProtocol: "all"}},
+			Description: quarantineDescription,
+			Direction:   direction,
+			Name:        name,
+			Network:     network,
+			Priority:    0,
+			TargetTags:  []string{tag},
+			// Priority 0 is the highest priority and would otherwise be omitted.
+			ForceSendFields: []string{"Priority"},
+		}
+		if direction == "INGRESS" {
+			fw.SourceRanges = []string{"0.0.0.0/0"}
+		} else {
+			fw.DestinationRanges = []string{"0.0.0.0/0"}
+		}
+		logging.FromContext(ctx).Info("adding a new firewall rule %q to quarantine %q", name, tag)
+		if err := f.addFirewallRule(ctx, projectID, fw); err != nil {
+			return created, errors.Wrapf(err, "failed to add firewall rule: %q", name)
+		}
+		created = append(created, name)
+	}
+	return created, nil
+}
+
+// quarantineRuleName returns the quarantine rule name for the given network URL and direction.
+func quarantineRuleName(network, direction string) string {
+	suffix := "-" + strings.ToLower(direction)
+	name := quarantineName + "-" + network[strings.LastIndex(network, "/")+1:]
+	if len(name)+len(suffix) > 63 {
+		name = name[:63-len(suffix)]
+	}
+	return name + suffix
+}
+
+// sshBlockNetworkName returns the SSH block rule name for the given network URL.
+func sshBlockNetworkName(network string) string {
+	name := sshBlockName + "-" + network[strings.LastIndex(network, "/")+1:]
+	if len(name) > 63 {
+		name = name[:63]
+	}
+	return name
+}
+
+// sshBlockExpiry returns the expiry saved within the rule's description.
+func sshBlockExpiry(description string) (time.Time, bool) {
+	i := strings.Index(description, sshBlockExpiresAt)
+	if i == -1 {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, description[i+len(sshBlockExpiresAt):])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
 // addFirewallRule will add a firewall rule.
 func (f *Firewall) addFirewallRule(ctx context.Context, projectID string, fw *compute.Firewall) error {
 	op, err := f.client.InsertFirewallRule(ctx, projectID, fw)
@@ -100,12 +263,16 @@ func (f *Firewall) EnableFirewallRule(ctx context.Context, projectID string, rul
 
 // DisableFirewallRule sets the firewall rule to disabled.
 func (f *Firewall) DisableFirewallRule(ctx context.Context, projectID string, ruleID string, name string) (*compute.Operation, error) {
-	return f.client.PatchFirewallRule(ctx, projectID, ruleID, &compute.Firewall{Name: name, Disabled: true})
+	patch := &compute.Firewall{Name: name, Disabled: true}
+	f.auditRule(ctx, projectID, ruleID, patch)
+	return f.client.PatchFirewallRule(ctx, projectID, ruleID, patch)
 }
 
 // UpdateFirewallRuleSourceRange updates the firewall source ranges
 func (f *Firewall) UpdateFirewallRuleSourceRange(ctx context.Context, projectID string, ruleID string, name string, sourceRanges []string) error {
-	op, err := f.client.PatchFirewallRule(ctx, projectID, ruleID, &compute.Firewall{Name: name, SourceRanges: sourceRanges})
+	patch := &compute.Firewall{Name: name, SourceRanges: sourceRanges}
+	f.auditRule(ctx, projectID, ruleID, patch)
+	op, err := f.client.PatchFirewallRule(ctx, projectID, ruleID, patch)
 	if err != nil {
 		return err
 	}
@@ -117,9 +284,23 @@ func (f *Firewall) UpdateFirewallRuleSourceRange(ctx context.Context, projectID
 
 // DeleteFirewallRule delete the firewall rule.
 func (f *Firewall) DeleteFirewallRule(ctx context.Context, projectID string, ruleID string) (*compute.Operation, error) {
+	f.auditRule(ctx, projectID, ruleID, nil)
 	return f.client.DeleteFirewallRule(ctx, projectID, ruleID)
 }
 
+// auditRule saves the rule's prior state so its change can be undone. The rule is only read if
+// the context is audited and is not saved if it can't be read.
+func (f *Firewall) auditRule(ctx context.Context, projectID, ruleID string, after *compute.Firewall) {
+	if !auditing(ctx) {
+		return
+	}
+	before, err := f.client.FirewallRule(ctx, projectID, ruleID)
+	if err != nil {
+		return
+	}
+	auditUndo(ctx, undoFirewall, "projects/"+projectID+"/global/firewalls/"+before.Name, before, after)
+}
+
 // FirewallRule get a firewall rule
 func (f *Firewall) FirewallRule(ctx context.Context, projectID string, ruleID string) (*compute.Firewall, error) {
 	return f.client.FirewallRule(ctx, projectID, ruleID)
@@ -129,3 +310,50 @@ func (f *Firewall) FirewallRule(ctx context.Context, projectID string, ruleID st
 func (f *Firewall) WaitGlobal(project string, op *compute.Operation) []error {
 	return f.client.WaitGlobal(project, op)
 }
+
+// EnablePrivateGoogleAccess enables Private Google Access on the subnetwork so its VMs can reach
+// Google APIs without external IPs.
+func (f *Firewall) EnablePrivateGoogleAccess(ctx context.Context, projectID, region, subnetwork string) error {
+	op, err := f.client.SetPrivateIPGoogleAccess(ctx, projectID, region, subnetwork, true)
+	if err != nil {
+		return errors.Wrapf(err, "failed to enable private google access: %q %q", projectID, subnetwork)
+	}
+	if errs := f.client.WaitRegion(projectID, region, op); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// Subnetwork returns the subnetwork.
+func (f *Firewall) Subnetwork(ctx context.Context, projectID, region, subnetwork string) (*compute.Subnetwork, error) {
+	sn, err := f.client.Subnetwork(ctx, projectID, region, subnetwork)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get subnetwork: %q %q", projectID, subnetwork)
+	}
+	return sn, nil
+}
+
+// EnableFlowLogs enables VPC Flow Logs on the subnetwork. A zero sampling rate or an empty
+// aggregation interval leaves the API's default.
+func (f *Firewall) EnableFlowLogs(ctx context.Context, projectID, region, subnetwork string, sampling float64, interval string) error {
+	sn, err := f.Subnetwork(ctx, projectID, region, subnetwork)
+	if err != nil {
+		return err
+	}
+	patch := &compute.Subnetwork{
+		Fingerprint: sn.Fingerprint,
+		LogConfig: &compute.SubnetworkLogConfig{
+			Enable:              true,
+			FlowSampling:        sampling,
+			AggregationInterval: interval,
+		},
+	}
+	op, err := f.client.PatchSubnetwork(ctx, projectID, region, subnetwork, patch)
+	if err != nil {
+		return errors.Wrapf(err, "failed to enable flow logs: %q %q", projectID, subnetwork)
+	}
+	if errs := f.client.WaitRegion(projectID, region, op); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
@@ -17,13 +17,26 @@ package services
 import (
 	"context"
 	"fmt"
-	"log"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	compute "google.golang.org/api/compute/v1"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+)
+
+const (
+	// blockProjectSSHKeys is the instance metadata key preventing project-wide SSH keys from being used.
+	blockProjectSSHKeys = "block-project-ssh-keys"
+	// sshKeys is the metadata key holding SSH keys, one per line.
+	sshKeys = "ssh-keys"
+	// enableOSLogin is the metadata key enabling OS Login.
+	enableOSLogin = "enable-oslogin"
+	// serialPortEnable is the metadata key enabling interactive serial console access.
+	serialPortEnable = "serial-port-enable"
 )
 
 // ComputeClient contains minimum interface required by the host service.
@@ -34,11 +47,16 @@ type ComputeClient interface {
 	DeleteDiskSnapshot(context.Context, string, string) (*compute.Operation, error)
 	DeleteInstance(context.Context, string, string, string) (*compute.Operation, error)
 	GetInstance(ctx context.Context, project, zone, instance string) (*compute.Instance, error)
+	GetProject(context.Context, string) (*compute.Project, error)
+	SetCommonInstanceMetadata(context.Context, string, *compute.Metadata) (*compute.Operation, error)
+	SetInstanceMetadata(context.Context, string, string, string, *compute.Metadata) (*compute.Operation, error)
+	SetInstanceTags(context.Context, string, string, string, *compute.Tags) (*compute.Operation, error)
 	ListDisks(context.Context, string, string) (*compute.DiskList, error)
 	ListProjectSnapshots(context.Context, string) (*compute.SnapshotList, error)
 	SetLabels(context.Context, string, string, *compute.GlobalSetLabelsRequest) (*compute.Operation, error)
 	StartInstance(context.Context, string, string, string) (*compute.Operation, error)
 	StopInstance(context.Context, string, string, string) (*compute.Operation, error)
+	UpdateShieldedInstanceConfig(context.Context, string, string, string, *compute.ShieldedInstanceConfig) (*compute.Operation, error)
 	WaitGlobal(string, *compute.Operation) []error
 	WaitZone(string, string, *compute.Operation) []error
 }
@@ -65,6 +83,11 @@ func (h *Host) DeleteDiskSnapshot(ctx context.Context, projectID, snapshot strin
 	return nil
 }
 
+// Instance returns the given compute instance.
+func (h *Host) Instance(ctx context.Context, project, zone, instance string) (*compute.Instance, error) {
+	return h.client.GetInstance(ctx, project, zone, instance)
+}
+
 // RemoveExternalIPs iterates on all network interfaces of an instance and deletes its accessConfigs, actually removing the external IP addresses of the instance.
 func (h *Host) RemoveExternalIPs(ctx context.Context, project, zone, instance string) error {
 	i, err := h.client.GetInstance(ctx, project, zone, instance)
@@ -91,6 +114,48 @@ func (h *Host) RemoveExternalIPs(ctx context.Context, project, zone, instance st
 	return nil
 }
 
+// ReplaceNetworkTags sets the network tags of an instance. When strip is true the existing tags are
+// removed and returned, otherwise the tags in add are appended to them. Nothing is changed if the
+// resulting tags match the existing ones.
+func (h *Host) ReplaceNetworkTags(ctx context.Context, project, zone, instance string, strip bool, add []string) ([]string, error) {
+	i, err := h.client.GetInstance(ctx, project, zone, instance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance: %q", err)
+	}
+	tags := i.Tags
+	if tags == nil {
+		tags = &compute.Tags{}
+	}
+	removed := []string{}
+	items := []string{}
+	for _, t := range tags.Items {
+		if strip && !containsTag(add, t) {
+			removed = append(removed, t)
+			continue
+		}
+		items = append(items, t)
+	}
+	for _, t := range add {
+		if !containsTag(items, t) {
+			items = append(items, t)
+		}
+	}
+	if len(removed) == 0 && len(items) == len(tags.Items) {
+		return removed, nil
+	}
+	op, err := h.client.SetInstanceTags(ctx, project, zone, instance, &compute.Tags{
+		Items:       items,
+		Fingerprint: tags.Fingerprint,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set tags: %q", err)
+	}
+	if errs := h.WaitZone(project, zone, op); len(errs) > 0 {
+		return nil, fmt.Errorf("failed to waiting instance. Errors[0]: %s", errs[0])
+	}
+	return removed, nil
+}
+
 // DiskSnapshot gets a snapshot by name associated with a given disk.
 func (h *Host) DiskSnapshot(ctx context.Context, snapshotName, projectID string, disk *compute.Disk) (*compute.Snapshot, error) {
 	snapshots, err := h.ListProjectSnapshots(ctx, projectID)
@@ -153,13 +218,13 @@ func (h *Host) ListInstanceDisks(ctx context.Context, projectID, zone, instance
 			dl = append(dl, d)
 		}
 	}
-	log.Printf("got %d disks associated with instance %q", len(dl), instance)
+	logging.FromContext(ctx).Info("got %d disks associated with instance %q", len(dl), instance)
 	return dl, nil
 }
 
 // SetSnapshotLabels sets the labels on a snapshot.
 func (h *Host) SetSnapshotLabels(ctx context.Context, projectID, snapshotName string, disk *compute.Disk, labels map[string]string) error {
-	log.Printf("get snapshot %q from %q %q", snapshotName, projectID, disk.Name)
+	logging.FromContext(ctx).Info("get snapshot %q from %q %q", snapshotName, projectID, disk.Name)
 	snapshot, err := h.DiskSnapshot(ctx, snapshotName, projectID, disk)
 	if err != nil {
 		return errors.Wrapf(err, "failed to get disk snapshots for %s in %s", disk.Name, projectID)
@@ -168,7 +233,7 @@ func (h *Host) SetSnapshotLabels(ctx context.Context, projectID, snapshotName st
 	labelFp := snapshot.LabelFingerprint
 
 	req := &compute.GlobalSetLabelsRequest{LabelFingerprint: labelFp, Labels: labels}
-	log.Printf("set label for %q %+v", projectID, labels)
+	logging.FromContext(ctx).Info("set label for %q %+v", projectID, labels)
 	op, err := h.client.SetLabels(ctx, projectID, id, req)
 	if err != nil {
 		return errors.Wrapf(err, "failed setting labels for %s %s", projectID, id)
@@ -223,6 +288,167 @@ func (h *Host) StartInstance(ctx context.Context, projectID, zone, instance stri
 	return nil
 }
 
+// UpdateShieldedInstanceConfig sets the Shielded VM options of the provided instance.
+func (h *Host) UpdateShieldedInstanceConfig(ctx context.Context, projectID, zone, instance string, config *compute.ShieldedInstanceConfig) error {
+	op, err := h.client.UpdateShieldedInstanceConfig(ctx, projectID, zone, instance, config)
+	if err != nil {
+		return fmt.Errorf("failed to update shielded instance config: %q", err)
+	}
+	if errs := h.WaitZone(projectID, zone, op); len(errs) > 0 {
+		return fmt.Errorf("failed to waiting instance. Errors[0]: %s", errs[0])
+	}
+	return nil
+}
+
+// BlockProjectSSHKeys sets block-project-ssh-keys to true on the instance metadata so project-wide
+// SSH keys can no longer be used to access it. False is returned if the key was already set.
+func (h *Host) BlockProjectSSHKeys(ctx context.Context, projectID, zone, instance string) (bool, error) {
+	_, changed, err := h.setInstanceMetadataItem(ctx, projectID, zone, instance, blockProjectSSHKeys, "true")
+	return changed, err
+}
+
+// DisableSerialPort sets serial-port-enable to false on the instance metadata. The prior value is
+// returned along with false if the key was already set.
+func (h *Host) DisableSerialPort(ctx context.Context, projectID, zone, instance string) (string, bool, error) {
+	return h.setInstanceMetadataItem(ctx, projectID, zone, instance, serialPortEnable, "false")
+}
+
+// SerialPortEnabled returns the serial-port-enable value of the instance metadata.
+func (h *Host) SerialPortEnabled(ctx context.Context, projectID, zone, instance string) (string, error) {
+	i, err := h.client.GetInstance(ctx, projectID, zone, instance)
+	if err != nil {
+		return "", fmt.Errorf("failed to get instance: %q", err)
+	}
+	return metadataItem(i.Metadata, serialPortEnable), nil
+}
+
+// setInstanceMetadataItem sets the instance metadata key to value, returning its prior value and
+// whether the metadata was updated.
+func (h *Host) setInstanceMetadataItem(ctx context.Context, projectID, zone, instance, key, value string) (string, bool, error) {
+	i, err := h.client.GetInstance(ctx, projectID, zone, instance)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get instance: %q", err)
+	}
+	if i.Metadata == nil {
+		i.Metadata = &compute.Metadata{}
+	}
+	prior := metadataItem(i.Metadata, key)
+	if !setMetadataItem(i.Metadata, key, value) {
+		return prior, false, nil
+	}
+	op, err := h.client.SetInstanceMetadata(ctx, projectID, zone, instance, i.Metadata)
+	if err != nil {
+		return prior, false, fmt.Errorf("failed to set instance metadata: %q", err)
+	}
+	if errs := h.WaitZone(projectID, zone, op); len(errs) > 0 {
+		return prior, false, fmt.Errorf("failed to waiting instance. Errors[0]: %s", errs[0])
+	}
+	return prior, true, nil
+}
+
+// EnableProjectOSLogin sets enable-oslogin to true in the project-wide metadata so OS Login is used
+// by all instances that do not override it. False is returned if the key was already set.
+func (h *Host) EnableProjectOSLogin(ctx context.Context, projectID string) (bool, error) {
+	p, err := h.client.GetProject(ctx, projectID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get project: %q", err)
+	}
+	if p.CommonInstanceMetadata == nil {
+		p.CommonInstanceMetadata = &compute.Metadata{}
+	}
+	if strings.EqualFold(metadataItem(p.CommonInstanceMetadata, enableOSLogin), "true") {
+		return false, nil
+	}
+	setMetadataItem(p.CommonInstanceMetadata, enableOSLogin, "true")
+	op, err := h.client.SetCommonInstanceMetadata(ctx, projectID, p.CommonInstanceMetadata)
+	if err != nil {
+		return false, fmt.Errorf("failed to set project metadata: %q", err)
+	}
+	if errs := h.WaitGlobal(projectID, op); len(errs) > 0 {
+		return false, fmt.Errorf("failed to waiting project. Errors[0]: %s", errs[0])
+	}
+	return true, nil
+}
+
+// RemoveProjectSSHKeys removes the project-wide SSH keys matching the pattern and returns the removed
+// keys. The project metadata is only updated if a key was removed.
+func (h *Host) RemoveProjectSSHKeys(ctx context.Context, projectID string, pattern *regexp.Regexp) ([]string, error) {
+	p, err := h.client.GetProject(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %q", err)
+	}
+	removed := []string{}
+	kept := []string{}
+	for _, key := range splitSSHKeys(metadataItem(p.CommonInstanceMetadata, sshKeys)) {
+		if pattern.MatchString(key) {
+			removed = append(removed, key)
+			continue
+		}
+		kept = append(kept, key)
+	}
+	if len(removed) == 0 {
+		return removed, nil
+	}
+	setMetadataItem(p.CommonInstanceMetadata, sshKeys, strings.Join(kept, "\n"))
+	op, err := h.client.SetCommonInstanceMetadata(ctx, projectID, p.CommonInstanceMetadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set project metadata: %q", err)
+	}
+	if errs := h.WaitGlobal(projectID, op); len(errs) > 0 {
+		return nil, fmt.Errorf("failed to waiting project. Errors[0]: %s", errs[0])
+	}
+	return removed, nil
+}
+
+// metadataItem returns the value of the metadata key or an empty string if not set.
+func metadataItem(metadata *compute.Metadata, key string) string {
+	if metadata == nil {
+		return ""
+	}
+	for _, item := range metadata.Items {
+		if item.Key == key && item.Value != nil {
+			return *item.Value
+		}
+	}
+	return ""
+}
+
+// setMetadataItem sets the metadata key to value, returning false if it already had that value.
+func setMetadataItem(metadata *compute.Metadata, key, value string) bool {
+	for _, item := range metadata.Items {
+		if item.Key != key {
+			continue
+		}
+		if item.Value != nil && *item.Value == value {
+			return false
+		}
+		item.Value = &value
+		return true
+	}
+	metadata.Items = append(metadata.Items, &compute.MetadataItems{Key: key, Value: &value})
+	return true
+}
+
+// containsTag returns true if the tag is within tags.
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func splitSSHKeys(value string) []string {
+	keys := []string{}
+	for _, key := range strings.Split(value, "\n") {
+		if strings.TrimSpace(key) != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
 // DeleteInstance starts a given instance in given zone.
 func (h *Host) DeleteInstance(ctx context.Context, projectID, zone, instance string) (*compute.Operation, error) {
 	return h.client.DeleteInstance(ctx, projectID, zone, instance)
@@ -217,3 +217,68 @@ func TestRemoveExternalIPFromInstanceNetworkInterfacesFailing(t *testing.T) {
 		})
 	}
 }
+
+func TestReplaceNetworkTags(t *testing.T) {
+	const (
+		project  = "test-project"
+		zone     = "test-zone"
+		instance = "test-instance"
+	)
+	tests := []struct {
+		name        string
+		existing    []string
+		strip       bool
+		add         []string
+		wantRemoved []string
+		wantTags    *compute.Tags
+	}{
+		{
+			name:        "strip and quarantine",
+			existing:    []string{"http-server", "https-server"},
+			strip:       true,
+			add:         []string{"quarantine"},
+			wantRemoved: []string{"http-server", "https-server"},
+			wantTags:    &compute.Tags{Items: []string{"quarantine"}, Fingerprint: "abc"},
+		},
+		{
+			name:        "quarantine only",
+			existing:    []string{"http-server"},
+			add:         []string{"quarantine"},
+			wantRemoved: []string{},
+			wantTags:    &compute.Tags{Items: []string{"http-server", "quarantine"}, Fingerprint: "abc"},
+		},
+		{
+			name:        "strip only",
+			existing:    []string{"http-server"},
+			strip:       true,
+			wantRemoved: []string{"http-server"},
+			wantTags:    &compute.Tags{Items: []string{}, Fingerprint: "abc"},
+		},
+		{
+			name:        "already quarantined",
+			existing:    []string{"quarantine"},
+			strip:       true,
+			add:         []string{"quarantine"},
+			wantRemoved: []string{},
+			wantTags:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			computeStub := &stubs.ComputeStub{
+				StubbedInstance: &compute.Instance{Tags: &compute.Tags{Items: tt.existing, Fingerprint: "abc"}},
+			}
+			h := NewHost(computeStub)
+			removed, err := h.ReplaceNetworkTags(context.Background(), project, zone, instance, tt.strip, tt.add)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(tt.wantRemoved, removed); diff != "" {
+				t.Errorf("%s failed, removed difference: %+v", tt.name, diff)
+			}
+			if diff := cmp.Diff(tt.wantTags, computeStub.SavedInstanceTags); diff != "" {
+				t.Errorf("%s failed, tags difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
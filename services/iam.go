@@ -0,0 +1,108 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	iam "google.golang.org/api/iam/v1"
+)
+
+// IAMClient contains minimum interface required by the service.
+type IAMClient interface {
+	ListServiceAccountKeys(context.Context, string) ([]*iam.ServiceAccountKey, error)
+	DisableKey(context.Context, string) error
+	GetServiceAccountPolicy(context.Context, string) (*iam.Policy, error)
+	SetServiceAccountPolicy(context.Context, string, *iam.Policy) (*iam.Policy, error)
+}
+
+// IAM service.
+type IAM struct {
+	client IAMClient
+}
+
+// NewIAM returns an IAM service.
+func NewIAM(client IAMClient) *IAM {
+	return &IAM{client: client}
+}
+
+// OldServiceAccountKeys returns the names of the user-managed keys of the service account that were
+// created more than maxAge before now.
+func (i *IAM) OldServiceAccountKeys(ctx context.Context, projectID, serviceAccount string, maxAge time.Duration, now time.Time) ([]string, error) {
+	name := fmt.Sprintf("projects/%s/serviceAccounts/%s", projectID, serviceAccount)
+	keys, err := i.client.ListServiceAccountKeys(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service account keys: %q", err)
+	}
+	old := []string{}
+	for _, k := range keys {
+		created, err := time.Parse(time.RFC3339, k.ValidAfterTime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key creation time: %q", err)
+		}
+		if now.Sub(created) > maxAge {
+			old = append(old, k.Name)
+		}
+	}
+	return old, nil
+}
+
+// RemoveServiceAccountMember removes the member from the given roles of the service account's
+// policy and returns the roles it was removed from. The policy is only updated if the member was
+// found.
+func (i *IAM) RemoveServiceAccountMember(ctx context.Context, serviceAccount, member string, roles []string) ([]string, error) {
+	name := fmt.Sprintf("projects/-/serviceAccounts/%s", serviceAccount)
+	policy, err := i.client.GetServiceAccountPolicy(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service account policy: %q", err)
+	}
+	removed := []string{}
+	for _, b := range policy.Bindings {
+		if !containsMember(roles, b.Role) {
+			continue
+		}
+		members := []string{}
+		for _, m := range b.Members {
+			if strings.EqualFold(m, member) {
+				continue
+			}
+			members = append(members, m)
+		}
+		if len(members) != len(b.Members) {
+			removed = append(removed, b.Role)
+		}
+		b.Members = members
+	}
+	if len(removed) == 0 {
+		return removed, nil
+	}
+	if _, err := i.client.SetServiceAccountPolicy(ctx, name, policy); err != nil {
+		return nil, fmt.Errorf("failed to set service account policy: %q", err)
+	}
+	return removed, nil
+}
+
+// DisableKeys disables the given service account keys.
+func (i *IAM) DisableKeys(ctx context.Context, keys []string) error {
+	for _, k := range keys {
+		if err := i.client.DisableKey(ctx, k); err != nil {
+			return fmt.Errorf("failed to disable key %q: %q", k, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	iam "google.golang.org/api/iam/v1"
+)
+
+func TestOldServiceAccountKeys(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	const prefix = "projects/test-project/serviceAccounts/sa@test-project.iam.gserviceaccount.com/keys/"
+	tests := []struct {
+		name     string
+		keys     []*iam.ServiceAccountKey
+		maxAge   time.Duration
+		expected []string
+	}{
+		{
+			name: "only old keys returned",
+			keys: []*iam.ServiceAccountKey{
+				{Name: prefix + "old", ValidAfterTime: "2020-01-01T00:00:00Z"},
+				{Name: prefix + "new", ValidAfterTime: "2020-05-20T00:00:00Z"},
+			},
+			maxAge:   90 * 24 * time.Hour,
+			expected: []string{prefix + "old"},
+		},
+		{
+			name:     "no keys",
+			maxAge:   90 * 24 * time.Hour,
+			expected: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := NewIAM(&stubs.IAMStub{StubbedKeys: tt.keys})
+			got, err := i.OldServiceAccountKeys(context.Background(), "test-project", "sa@test-project.iam.gserviceaccount.com", tt.maxAge, now)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(tt.expected, got); diff != "" {
+				t.Errorf("%s failed, difference: %+v", tt.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	firestore "google.golang.org/api/firestore/v1"
+)
+
+const (
+	// idempotencyCollection is the Firestore collection of the keys of findings being processed.
+	idempotencyCollection = "automation-idempotency-keys"
+	// defaultIdempotencyTTL is how long a finding is deduplicated if no TTL is configured.
+	defaultIdempotencyTTL = 24 * time.Hour
+)
+
+// IdempotencyConfig configures how long redelivered findings are deduplicated.
+type IdempotencyConfig struct {
+	// TTL is how long a finding that was processed isn't processed again, defaults to 24 hours.
+	TTL time.Duration
+}
+
+// IdempotencyDocumentClient contains minimum interface required by the idempotency service.
+type IdempotencyDocumentClient interface {
+	CreateDocument(ctx context.Context, projectID, collection, documentID string, fields map[string]firestore.Value) error
+	GetDocument(ctx context.Context, projectID, collection, documentID string) (*firestore.Document, error)
+	UpdateDocument(ctx context.Context, projectID, collection, documentID string, fields map[string]firestore.Value, updateTime string) error
+	DeleteDocument(ctx context.Context, projectID, collection, documentID, updateTime string) error
+}
+
+// Idempotency service keeps Pub/Sub redeliveries of a finding from being processed twice.
+//
+// Each finding is claimed with a key derived from its name and event time. A key can only be
+// claimed once until its TTL has passed.
+type Idempotency struct {
+	documents IdempotencyDocumentClient
+	projectID string
+	ttl       time.Duration
+}
+
+// NewIdempotency returns an idempotency service keeping keys in the automation project.
+func NewIdempotency(documents IdempotencyDocumentClient, projectID string, conf IdempotencyConfig) *Idempotency {
+	ttl := conf.TTL
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+	return &Idempotency{documents: documents, projectID: projectID, ttl: ttl}
+}
+
+// IdempotencyKey returns the key of the finding processed by the function, or an empty key if
+// the finding's name or event time is unknown.
+func IdempotencyKey(function, findingID, eventTime string) string {
+	if findingID == "" || eventTime == "" {
+		return ""
+	}
+	// Finding names contain slashes, which aren't allowed in document IDs.
+	sum := sha256.Sum256([]byte(strings.Join([]string{function, findingID, eventTime}, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Claim returns whether the key was claimed, false if it was claimed before and hasn't expired by
+// now. An empty key is always claimed.
+func (i *Idempotency) Claim(ctx context.Context, key string, now time.Time) (bool, error) {
+	if key == "" {
+		return true, nil
+	}
+	fields := map[string]firestore.Value{
+		"claimed": {TimestampValue: now.UTC().Format(time.RFC3339Nano)},
+		"expires": {TimestampValue: now.Add(i.ttl).UTC().Format(time.RFC3339Nano)},
+	}
+	err := i.documents.CreateDocument(ctx, i.projectID, idempotencyCollection, key, fields)
+	if err == nil {
+		return true, nil
+	}
+	if !alreadyExists(err) {
+		return false, errors.Wrapf(err, "failed to claim %q", key)
+	}
+	doc, err := i.documents.GetDocument(ctx, i.projectID, idempotencyCollection, key)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to read %q", key)
+	}
+	expires, err := time.Parse(time.RFC3339Nano, doc.Fields["expires"].TimestampValue)
+	if err == nil && now.Before(expires) {
+		return false, nil
+	}
+	// The update fails if another redelivery reclaimed the expired key first.
+	if err := i.documents.UpdateDocument(ctx, i.projectID, idempotencyCollection, key, fields, doc.UpdateTime); err != nil {
+		return false, errors.Wrapf(err, "failed to reclaim %q", key)
+	}
+	return true, nil
+}
+
+// Release removes the claim of the key, so the finding can be processed again. Findings whose
+// processing failed are released so they can be retried.
+func (i *Idempotency) Release(ctx context.Context, key string) error {
+	if key == "" {
+		return nil
+	}
+	if err := i.documents.DeleteDocument(ctx, i.projectID, idempotencyCollection, key, ""); err != nil && !notFound(err) {
+		return errors.Wrapf(err, "failed to release %q", key)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+)
+
+func TestIdempotency(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	i := NewIdempotency(&stubs.FirestoreStub{}, "automation-project", IdempotencyConfig{TTL: time.Hour})
+	key := IdempotencyKey("OpenFirewall", "organizations/1/sources/2/findings/3", "2020-01-01T00:00:00Z")
+	for _, tt := range []struct {
+		name    string
+		release bool
+		now     time.Time
+		want    bool
+	}{
+		{name: "first delivery", now: now, want: true},
+		{name: "redelivery", now: now.Add(time.Minute), want: false},
+		{name: "expired", now: now.Add(2 * time.Hour), want: true},
+		{name: "released", release: true, now: now.Add(2*time.Hour + time.Minute), want: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.release {
+				if err := i.Release(ctx, key); err != nil {
+					t.Fatalf("%s failed to release: %q", tt.name, err)
+				}
+			}
+			got, err := i.Claim(ctx, key, tt.now)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if got != tt.want {
+				t.Errorf("%s failed: got %t want %t", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	name := "organizations/1/sources/2/findings/3"
+	if IdempotencyKey("OpenFirewall", name, "") != "" {
+		t.Errorf("key without event time isn't empty")
+	}
+	first := IdempotencyKey("OpenFirewall", name, "2020-01-01T00:00:00Z")
+	if first == IdempotencyKey("OpenFirewall", name, "2020-01-02T00:00:00Z") {
+		t.Errorf("keys of different events are the same")
+	}
+	if first == IdempotencyKey("CloseBucket", name, "2020-01-01T00:00:00Z") {
+		t.Errorf("keys of different functions are the same")
+	}
+}
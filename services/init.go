@@ -2,14 +2,18 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 
+	"cloud.google.com/go/compute/metadata"
 	"github.com/googlecloudplatform/security-response-automation/clients"
+	"github.com/pkg/errors"
+	container "google.golang.org/api/container/v1"
 )
 
 // Global holds all initialized services.
 type Global struct {
-	Logger                *Logger
 	Resource              *Resource
 	Host                  *Host
 	Firewall              *Firewall
@@ -25,11 +29,6 @@ func New(ctx context.Context) (*Global, error) {
 		return nil, err
 	}
 
-	log, err := initLog(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	res, err := initResource(ctx)
 	if err != nil {
 		return nil, err
@@ -57,7 +56,6 @@ func New(ctx context.Context) (*Global, error) {
 
 	return &Global{
 		Host:                  host,
-		Logger:                log,
 		Resource:              res,
 		Firewall:              fw,
 		Container:             cont,
@@ -90,20 +88,120 @@ func InitPubSub(ctx context.Context, projectID string) (*PubSub, error) {
 	return NewPubSub(pubsub), nil
 }
 
-func initHost(ctx context.Context) (*Host, error) {
-	cs, err := clients.NewCompute(ctx)
+// InitSecretManager creates and initializes a new instance of Secret Manager.
+func InitSecretManager(ctx context.Context) (*SecretManager, error) {
+	sm, err := clients.NewSecretManager(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize compute client: %q", err)
+		return nil, fmt.Errorf("failed to initialize secret manager client: %q", err)
 	}
-	return NewHost(cs), nil
+	return NewSecretManager(sm), nil
+}
+
+// InitEmail creates and initializes a new instance of Email using SendGrid.
+func InitEmail(apiKey string) *Email {
+	sg := clients.NewSendGridClient(apiKey)
+	return NewEmail(sg)
+}
+
+// InitSMTPEmail creates and initializes a new instance of Email using the SMTP server, reading
+// the password from Secret Manager if configured.
+func InitSMTPEmail(ctx context.Context, conf SMTPConfig) (*Email, error) {
+	password := conf.Password
+	if conf.PasswordSecret != "" {
+		sm, err := InitSecretManager(ctx)
+		if err != nil {
+			return nil, err
+		}
+		b, err := sm.Access(ctx, conf.PasswordSecret)
+		if err != nil {
+			return nil, err
+		}
+		password = string(b)
+	}
+	port := conf.Port
+	if port == 0 {
+		port = 587
+	}
+	return NewEmail(clients.NewSMTP(conf.Host, port, conf.Username, password)), nil
+}
+
+// InitKubernetes creates and initializes a new instance of Kubernetes for the given cluster.
+func InitKubernetes(ctx context.Context, cluster *container.Cluster) (*Kubernetes, error) {
+	k, err := clients.NewKubernetes(ctx, cluster.Endpoint, cluster.MasterAuth.ClusterCaCertificate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize kubernetes client: %q", err)
+	}
+	return NewKubernetes(k), nil
+}
+
+// InitServerless creates and initializes a new instance of Serverless.
+func InitServerless(ctx context.Context) (*Serverless, error) {
+	cf, err := clients.NewCloudFunctions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize cloud functions client: %q", err)
+	}
+	run, err := clients.NewCloudRun(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize cloud run client: %q", err)
+	}
+	return NewServerless(cf, run), nil
+}
+
+// InitDNS creates and initializes a new instance of DNS.
+func InitDNS(ctx context.Context) (*DNS, error) {
+	d, err := clients.NewDNS(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize dns client: %q", err)
+	}
+	return NewDNS(d), nil
+}
+
+// InitAssets creates and initializes a new instance of Assets.
+func InitAssets(ctx context.Context) (*Assets, error) {
+	c, err := clients.NewCloudAsset(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize cloud asset client: %q", err)
+	}
+	return NewAssets(c), nil
+}
+
+// InitPolicyBackup creates and initializes a new instance of PolicyBackup.
+func InitPolicyBackup(ctx context.Context) (*PolicyBackup, error) {
+	crm, err := clients.NewCloudResourceManager(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize cloud resource manager client: %q", err)
+	}
+	stg, err := clients.NewStorage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize storage client: %q", err)
+	}
+	return NewPolicyBackup(crm, stg), nil
+}
+
+// InitAdmin creates and initializes a new instance of Admin.
+func InitAdmin(ctx context.Context) (*Admin, error) {
+	a, err := clients.NewAdmin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize admin client: %q", err)
+	}
+	return NewAdmin(a), nil
+}
+
+// InitCounter creates and initializes a new instance of Counter.
+func InitCounter(ctx context.Context) (*Counter, error) {
+	stg, err := clients.NewStorage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize storage client: %q", err)
+	}
+	return NewCounter(stg), nil
 }
 
-func initLog(ctx context.Context) (*Logger, error) {
-	logClient, err := clients.NewLogger(ctx)
+func initHost(ctx context.Context) (*Host, error) {
+	cs, err := clients.NewCompute(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize logger client: %q", err)
+		return nil, fmt.Errorf("failed to initialize compute client: %q", err)
 	}
-	return NewLogger(logClient), nil
+	return NewHost(cs), nil
 }
 
 func initResource(ctx context.Context) (*Resource, error) {
@@ -149,3 +247,367 @@ func initSecurityCommandCenter(ctx context.Context) (*CommandCenter, error) {
 	}
 	return NewCommandCenter(scc), nil
 }
+
+// InitIAM creates and initializes a new instance of IAM.
+func InitIAM(ctx context.Context) (*IAM, error) {
+	i, err := clients.NewIAM(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize iam client: %q", err)
+	}
+	return NewIAM(i), nil
+}
+
+// InitAPIKeys creates and initializes a new instance of APIKeys.
+func InitAPIKeys(ctx context.Context) (*APIKeys, error) {
+	a, err := clients.NewAPIKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize api keys client: %q", err)
+	}
+	return NewAPIKeys(a), nil
+}
+
+// InitPubSubAdmin creates and initializes a new instance of PubSubAdmin.
+func InitPubSubAdmin(ctx context.Context) (*PubSubAdmin, error) {
+	p, err := clients.NewPubSubAdmin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize pubsub admin client: %q", err)
+	}
+	return NewPubSubAdmin(p), nil
+}
+
+// InitSubscriber creates and initializes a new instance of Subscriber.
+func InitSubscriber(ctx context.Context) (*Subscriber, error) {
+	s, err := clients.NewSubscriber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize pubsub subscriber client: %q", err)
+	}
+	return NewSubscriber(s), nil
+}
+
+// InitKMS creates and initializes a new instance of KMS.
+func InitKMS(ctx context.Context) (*KMS, error) {
+	k, err := clients.NewKMS(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize kms client: %q", err)
+	}
+	return NewKMS(k), nil
+}
+
+// InitBilling creates and initializes a new instance of Billing.
+func InitBilling(ctx context.Context) (*Billing, error) {
+	b, err := clients.NewBilling(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize billing client: %q", err)
+	}
+	return NewBilling(b), nil
+}
+
+// InitArtifactRegistry creates and initializes a new instance of ArtifactRegistry.
+func InitArtifactRegistry(ctx context.Context) (*ArtifactRegistry, error) {
+	a, err := clients.NewArtifactRegistry(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize artifact registry client: %q", err)
+	}
+	return NewArtifactRegistry(a), nil
+}
+
+// InitBinaryAuthorization creates and initializes a new instance of BinaryAuthorization.
+func InitBinaryAuthorization(ctx context.Context) (*BinaryAuthorization, error) {
+	b, err := clients.NewBinaryAuthorization(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize binary authorization client: %q", err)
+	}
+	return NewBinaryAuthorization(b), nil
+}
+
+// InitDataproc creates and initializes a new instance of Dataproc.
+func InitDataproc(ctx context.Context) (*Dataproc, error) {
+	d, err := clients.NewDataproc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize dataproc client: %q", err)
+	}
+	return NewDataproc(d), nil
+}
+
+// InitDataflow creates and initializes a new instance of Dataflow.
+func InitDataflow(ctx context.Context) (*Dataflow, error) {
+	d, err := clients.NewDataflow(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize dataflow client: %q", err)
+	}
+	return NewDataflow(d), nil
+}
+
+// InitRotationHook creates and initializes a new instance of RotationHook calling the URL.
+func InitRotationHook(ctx context.Context, url string) (*RotationHook, error) {
+	r, err := clients.NewRotationHook(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize rotation hook client: %q", err)
+	}
+	return NewRotationHook(r), nil
+}
+
+// InitLoadBalancer creates and initializes a new instance of LoadBalancer.
+func InitLoadBalancer(ctx context.Context) (*LoadBalancer, error) {
+	cs, err := clients.NewCompute(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize compute client: %q", err)
+	}
+	return NewLoadBalancer(cs), nil
+}
+
+// InitCloudIdentity creates and initializes a new instance of CloudIdentity.
+func InitCloudIdentity(ctx context.Context) (*CloudIdentity, error) {
+	c, err := clients.NewCloudIdentity(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize cloud identity client: %q", err)
+	}
+	return NewCloudIdentity(c), nil
+}
+
+// InitAudit creates and initializes a new instance of Audit writing to the automation project.
+func InitAudit(ctx context.Context, projectID string) (*Audit, error) {
+	bq, err := clients.NewBigQuery(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize bigquery client: %q", err)
+	}
+	fs, err := clients.NewFirestore(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize firestore client: %q", err)
+	}
+	// The actor is best effort, the metadata server is only available when deployed.
+	var actor string
+	if metadata.OnGCE() {
+		actor, _ = metadata.Email("default")
+	}
+	return NewAudit(bq, fs, projectID, actor), nil
+}
+
+// InitUndo creates and initializes a new instance of Undo reading from the automation project.
+func InitUndo(ctx context.Context, projectID string) (*Undo, error) {
+	fs, err := clients.NewFirestore(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize firestore client: %q", err)
+	}
+	crm, err := clients.NewCloudResourceManager(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize cloud resource manager client: %q", err)
+	}
+	cs, err := clients.NewCompute(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize compute client: %q", err)
+	}
+	return NewUndo(fs, crm, cs, projectID), nil
+}
+
+// InitApprovals creates and initializes a new instance of Approvals in the automation project,
+// signing links with the key.
+// Keys shorter than MinApprovalKeyLength are rejected.
+func InitApprovals(ctx context.Context, projectID, key string) (*Approvals, error) {
+	if len(key) < MinApprovalKeyLength {
+		return nil, fmt.Errorf("approval key must be at least %d characters", MinApprovalKeyLength)
+	}
+	fs, err := clients.NewFirestore(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize firestore client: %q", err)
+	}
+	return NewApprovals(fs, projectID, []byte(key)), nil
+}
+
+// InitExemptions creates and initializes a new instance of Exemptions in the automation project.
+func InitExemptions(ctx context.Context, projectID string) (*Exemptions, error) {
+	fs, err := clients.NewFirestore(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize firestore client: %q", err)
+	}
+	return NewExemptions(fs, projectID), nil
+}
+
+// InitDeadLetters creates and initializes a new instance of DeadLetters.
+func InitDeadLetters(ctx context.Context, projectID string, conf DeadLetterConfig) (*DeadLetters, error) {
+	fs, err := clients.NewFirestore(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize firestore client: %q", err)
+	}
+	return NewDeadLetters(fs, projectID, conf), nil
+}
+
+// InitBreaker creates and initializes a new instance of Breaker.
+func InitBreaker(ctx context.Context, projectID string, config func() BreakerConfig) (*Breaker, error) {
+	fs, err := clients.NewFirestore(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize firestore client: %q", err)
+	}
+	return NewBreaker(fs, projectID, config), nil
+}
+
+// InitIdempotency creates and initializes a new instance of Idempotency.
+func InitIdempotency(ctx context.Context, projectID string, conf IdempotencyConfig) (*Idempotency, error) {
+	fs, err := clients.NewFirestore(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize firestore client: %q", err)
+	}
+	return NewIdempotency(fs, projectID, conf), nil
+}
+
+// InitProjectLocks creates and initializes a new instance of ProjectLocks.
+func InitProjectLocks(ctx context.Context, projectID string) (*ProjectLocks, error) {
+	fs, err := clients.NewFirestore(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize firestore client: %q", err)
+	}
+	return NewProjectLocks(fs, projectID), nil
+}
+
+// InitNotifications creates and initializes a new instance of Notifications with the configured
+// channels, publishing to topics of the automation project.
+func InitNotifications(ctx context.Context, projectID string, conf NotificationConfig) (*Notifications, error) {
+	notifiers := map[string]Notifier{}
+	switch email := conf.Email; {
+	case email.SendGrid.APIKey != "":
+		notifiers["email"] = NewEmailNotifier(InitEmail(email.SendGrid.APIKey), email.From, email.To)
+	case email.SMTP.Host != "":
+		e, err := InitSMTPEmail(ctx, email.SMTP)
+		if err != nil {
+			return nil, err
+		}
+		notifiers["email"] = NewEmailNotifier(e, email.From, email.To)
+	}
+	switch {
+	case conf.Slack.Token != "":
+		s, err := InitSlack(ctx, projectID, conf.Slack)
+		if err != nil {
+			return nil, err
+		}
+		notifiers["slack"] = s
+	case conf.Slack.WebhookURL != "":
+		notifiers["slack"] = NewSlackWebhookNotifier(clients.NewWebhook(conf.Slack.WebhookURL))
+	}
+	if conf.Teams.WebhookURL != "" {
+		notifiers["teams"] = InitTeams(conf.Teams)
+	}
+	if conf.Chat.WebhookURL != "" {
+		notifiers["chat"] = InitChat(conf.Chat)
+	}
+	if t := conf.Twilio; t.AccountSID != "" {
+		notifiers["twilio"] = NewTwilioNotifier(clients.NewTwilio(t.AccountSID, t.AuthToken), t)
+	}
+	if o := conf.Opsgenie; o.APIKey != "" {
+		apiURL := "https://api.opsgenie.com"
+		if strings.EqualFold(o.Region, "eu") {
+			apiURL = "https://api.eu.opsgenie.com"
+		}
+		notifiers["opsgenie"] = NewOpsgenieNotifier(clients.NewOpsgenie(apiURL, o.APIKey), o)
+	}
+	if conf.PubSub.Topic != "" {
+		ps, err := InitPubSub(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		notifiers["pubsub"] = NewPubSubNotifier(ps, conf.PubSub.Topic)
+	}
+	if conf.Webhook.URL != "" {
+		notifiers["webhook"] = NewWebhookNotifier(clients.NewWebhook(conf.Webhook.URL))
+	}
+	if len(conf.Digests) > 0 {
+		fs, err := clients.NewFirestore(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize firestore client: %q", err)
+		}
+		return NewDigestedNotifications(notifiers, NewDigests(fs, projectID, conf.Digests)), nil
+	}
+	return NewNotifications(notifiers), nil
+}
+
+// InitSlack creates and initializes a new instance of Slack posting with the bot token and keeping
+// threads in the automation project.
+func InitSlack(ctx context.Context, projectID string, conf SlackConfig) (*Slack, error) {
+	fs, err := clients.NewFirestore(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize firestore client: %q", err)
+	}
+	return NewSlack(clients.NewSlack(conf.Token), fs, projectID, conf.Channel, conf.SigningSecret), nil
+}
+
+// InitTeams creates and initializes a new instance of Teams posting to the incoming webhook.
+func InitTeams(conf TeamsConfig) *Teams {
+	return NewTeams(clients.NewWebhook(conf.WebhookURL))
+}
+
+// InitChat creates and initializes a new instance of Chat posting to the incoming webhook, replying
+// in the thread of each finding.
+func InitChat(conf ChatConfig) *Chat {
+	url := conf.WebhookURL
+	if strings.Contains(url, "?") {
+		url += "&" + chatReplyOption
+	} else {
+		url += "?" + chatReplyOption
+	}
+	return NewChat(clients.NewWebhook(url))
+}
+
+// InitTickets creates and initializes a new instance of Tickets filing in the configured trackers.
+func InitTickets(ctx context.Context, projectID string, conf TicketConfig) (*Tickets, error) {
+	trackers := []TicketTracker{}
+	if conf.Jira.URL != "" {
+		fs, err := clients.NewFirestore(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize firestore client: %q", err)
+		}
+		trackers = append(trackers, NewJira(clients.NewJira(conf.Jira.URL, conf.Jira.User, conf.Jira.APIToken), fs, projectID, conf.Jira))
+	}
+	if conf.ServiceNow.URL != "" {
+		trackers = append(trackers, NewServiceNow(clients.NewServiceNow(conf.ServiceNow.URL, conf.ServiceNow.User, conf.ServiceNow.Password), conf.ServiceNow))
+	}
+	return NewTickets(conf.Outcomes, trackers...), nil
+}
+
+// InitAWS creates and initializes a new instance of AWS acting with the credentials stored as JSON
+// in the Secret Manager secret version, such as "projects/p/secrets/aws/versions/latest".
+func InitAWS(ctx context.Context, credentialsSecret string) (*AWS, error) {
+	if credentialsSecret == "" {
+		return nil, errors.New("no aws credentials secret configured")
+	}
+	sm, err := InitSecretManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b, err := sm.Access(ctx, credentialsSecret)
+	if err != nil {
+		return nil, err
+	}
+	var creds clients.AWSCredentials
+	if err := json.Unmarshal(b, &creds); err != nil {
+		return nil, fmt.Errorf("invalid aws credentials in %q: %q", credentialsSecret, err)
+	}
+	a, err := clients.NewAWS(creds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize aws client: %q", err)
+	}
+	return NewAWS(a), nil
+}
+
+// InitAzure creates and initializes a new instance of Azure acting with the service principal
+// credentials stored as JSON in the Secret Manager secret version.
+func InitAzure(ctx context.Context, credentialsSecret string) (*Azure, error) {
+	if credentialsSecret == "" {
+		return nil, errors.New("no azure credentials secret configured")
+	}
+	sm, err := InitSecretManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b, err := sm.Access(ctx, credentialsSecret)
+	if err != nil {
+		return nil, err
+	}
+	var creds clients.AzureCredentials
+	if err := json.Unmarshal(b, &creds); err != nil {
+		return nil, fmt.Errorf("invalid azure credentials in %q: %q", credentialsSecret, err)
+	}
+	a, err := clients.NewAzure(creds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize azure client: %q", err)
+	}
+	return NewAzure(a), nil
+}
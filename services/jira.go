@@ -0,0 +1,124 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	firestore "google.golang.org/api/firestore/v1"
+)
+
+// jiraTicketCollection is the Firestore collection holding the Jira issue of each finding.
+const jiraTicketCollection = "automation-jira-tickets"
+
+// JiraConfig configures the Jira project issues are created in.
+type JiraConfig struct {
+	// URL is the Jira site, such as "https://cloudorg.atlassian.net".
+	URL      string
+	User     string
+	APIToken string `yaml:"api_token"`
+	// ProjectKey and IssueType are the project and type of the issues, the type defaults to "Task".
+	ProjectKey string `yaml:"project_key"`
+	IssueType  string `yaml:"issue_type"`
+	// Fields maps Jira field IDs, such as "customfield_10010", to the dotted path of a property of
+	// the remediation, such as "project_id" or "values.finding.severity".
+	Fields map[string]string
+}
+
+// JiraClient contains minimum interface required by the Jira service.
+type JiraClient interface {
+	CreateIssue(ctx context.Context, body []byte) (string, error)
+	UpdateIssue(ctx context.Context, key string, body []byte) error
+}
+
+// JiraDocumentClient contains minimum interface required by the Jira service to keep the issue
+// of each finding.
+type JiraDocumentClient interface {
+	CreateDocument(ctx context.Context, projectID, collection, documentID string, fields map[string]firestore.Value) error
+	GetDocument(ctx context.Context, projectID, collection, documentID string) (*firestore.Document, error)
+}
+
+// Jira service files Jira issues for remediations, one per finding.
+type Jira struct {
+	client    JiraClient
+	documents JiraDocumentClient
+	projectID string
+	conf      JiraConfig
+}
+
+// NewJira returns a Jira service creating issues as configured and keeping the issue of each
+// finding in the automation project.
+func NewJira(client JiraClient, documents JiraDocumentClient, projectID string, conf JiraConfig) *Jira {
+	if conf.IssueType == "" {
+		conf.IssueType = "Task"
+	}
+	return &Jira{client: client, documents: documents, projectID: projectID, conf: conf}
+}
+
+// File creates an issue for the remediation, or updates the finding's issue if it has one.
+func (j *Jira) File(ctx context.Context, r *AuditRecord) error {
+	n := NewNotification(r)
+	fields := map[string]interface{}{
+		"summary":     "Security Response Automation: " + n.Subject(),
+		"description": n.Text(),
+	}
+	props := ticketProperties(r)
+	for field, path := range j.conf.Fields {
+		if v := property(props, path); v != nil {
+			fields[field] = v
+		}
+	}
+	id := documentID(r.FindingID)
+	if r.FindingID != "" {
+		d, err := j.documents.GetDocument(ctx, j.projectID, jiraTicketCollection, id)
+		switch {
+		case err == nil:
+			key := d.Fields["key"].StringValue
+			b, err := json.Marshal(map[string]interface{}{"fields": fields})
+			if err != nil {
+				return err
+			}
+			if err := j.client.UpdateIssue(ctx, key, b); err != nil {
+				return errors.Wrapf(err, "failed to update jira issue %q", key)
+			}
+			return nil
+		case !notFound(err):
+			return errors.Wrapf(err, "failed to get jira issue of %q", r.FindingID)
+		}
+	}
+	fields["project"] = map[string]string{"key": j.conf.ProjectKey}
+	fields["issuetype"] = map[string]string{"name": j.conf.IssueType}
+	b, err := json.Marshal(map[string]interface{}{"fields": fields})
+	if err != nil {
+		return err
+	}
+	key, err := j.client.CreateIssue(ctx, b)
+	if err != nil {
+		return errors.Wrap(err, "failed to create jira issue")
+	}
+	if r.FindingID == "" {
+		return nil
+	}
+	doc := map[string]firestore.Value{
+		"finding_id": {StringValue: r.FindingID},
+		"key":        {StringValue: key},
+	}
+	if err := j.documents.CreateDocument(ctx, j.projectID, jiraTicketCollection, id, doc); err != nil {
+		return errors.Wrapf(err, "failed to save jira issue %q of %q", key, r.FindingID)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+)
+
+func TestJiraFile(t *testing.T) {
+	ctx := context.Background()
+	jiraStub := &stubs.JiraStub{}
+	tickets := NewTickets(nil, NewJira(jiraStub, &stubs.FirestoreStub{}, "automation-project", JiraConfig{
+		ProjectKey: "SEC",
+		Fields: map[string]string{
+			"customfield_10010": "project_id",
+			"customfield_10011": "values.finding.category",
+			"customfield_10012": "values.finding.missing",
+		},
+	}))
+	r := &AuditRecord{
+		ID:        "0b7e3c1a",
+		FindingID: "organizations/456/sources/1/findings/2",
+		ProjectID: "test-project",
+		Action:    "Router",
+		Values:    `{"finding": {"category": "PUBLIC_BUCKET_ACL"}}`,
+		Outcome:   AuditSkipped,
+		Reason:    "asset has exemption mark allow_public",
+	}
+	if err := tickets.File(ctx, r); err != nil {
+		t.Fatalf("failed to file ticket: %q", err)
+	}
+	if len(jiraStub.SavedIssues) != 1 {
+		t.Fatalf("got %d issues want 1", len(jiraStub.SavedIssues))
+	}
+	var issue struct {
+		Fields map[string]interface{}
+	}
+	if err := json.Unmarshal([]byte(jiraStub.SavedIssues[0]), &issue); err != nil {
+		t.Fatalf("failed to unmarshal issue: %q", err)
+	}
+	for field, want := range map[string]interface{}{
+		"customfield_10010": "test-project",
+		"customfield_10011": "PUBLIC_BUCKET_ACL",
+		"customfield_10012": nil,
+		"issuetype":         map[string]interface{}{"name": "Task"},
+		"project":           map[string]interface{}{"key": "SEC"},
+	} {
+		if got := issue.Fields[field]; !equalJSON(got, want) {
+			t.Errorf("got field %q %v want %v", field, got, want)
+		}
+	}
+
+	r.Outcome, r.Error, r.Reason = AuditFailure, "permission denied", ""
+	if err := tickets.File(ctx, r); err != nil {
+		t.Fatalf("failed to file ticket: %q", err)
+	}
+	if len(jiraStub.SavedIssues) != 1 || len(jiraStub.SavedUpdates["SEC-1"]) != 1 {
+		t.Errorf("expected the finding's issue to be updated, got issues %v updates %v", jiraStub.SavedIssues, jiraStub.SavedUpdates)
+	}
+
+	r.Outcome, r.Error = AuditSuccess, ""
+	if err := tickets.File(ctx, r); err != nil {
+		t.Fatalf("failed to file ticket: %q", err)
+	}
+	if len(jiraStub.SavedUpdates["SEC-1"]) != 1 {
+		t.Errorf("successful remediations should not file tickets")
+	}
+}
+
+func equalJSON(a, b interface{}) bool {
+	x, _ := json.Marshal(a)
+	y, _ := json.Marshal(b)
+	return string(x) == string(y)
+}
@@ -0,0 +1,97 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	kms "google.golang.org/api/cloudkms/v1"
+)
+
+// KMSClient contains minimum interface required by the service.
+type KMSClient interface {
+	GetKeyRingPolicy(context.Context, string) (*kms.Policy, error)
+	SetKeyRingPolicy(context.Context, string, *kms.Policy) (*kms.Policy, error)
+	GetCryptoKeyPolicy(context.Context, string) (*kms.Policy, error)
+	SetCryptoKeyPolicy(context.Context, string, *kms.Policy) (*kms.Policy, error)
+	PatchCryptoKey(context.Context, string, string, *kms.CryptoKey) (*kms.CryptoKey, error)
+}
+
+// KMS service.
+type KMS struct {
+	client KMSClient
+}
+
+// NewKMS returns a Cloud KMS service.
+func NewKMS(client KMSClient) *KMS {
+	return &KMS{client: client}
+}
+
+// isCryptoKey returns whether the resource is a crypto key rather than a key ring.
+func isCryptoKey(resource string) bool {
+	return strings.Contains(resource, "/cryptoKeys/")
+}
+
+// RemoveMembers removes the members from every role of the key ring or crypto key's policy.
+//
+// The members removed are returned and the policy is only updated if one was found.
+func (k *KMS) RemoveMembers(ctx context.Context, resource string, members []string) ([]string, error) {
+	get, set := k.client.GetKeyRingPolicy, k.client.SetKeyRingPolicy
+	if isCryptoKey(resource) {
+		get, set = k.client.GetCryptoKeyPolicy, k.client.SetCryptoKeyPolicy
+	}
+	policy, err := get(ctx, resource)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get policy of %q", resource)
+	}
+	removed := []string{}
+	for _, b := range policy.Bindings {
+		keep := []string{}
+		for _, m := range b.Members {
+			if containsMember(members, m) {
+				removed = append(removed, m)
+				continue
+			}
+			keep = append(keep, m)
+		}
+		b.Members = keep
+	}
+	if len(removed) == 0 {
+		return removed, nil
+	}
+	if _, err := set(ctx, resource, policy); err != nil {
+		return nil, errors.Wrapf(err, "failed to set policy of %q", resource)
+	}
+	return removed, nil
+}
+
+// ScheduleRotation sets the rotation period of the crypto key with the next rotation one period after now.
+func (k *KMS) ScheduleRotation(ctx context.Context, key string, period time.Duration, now time.Time) error {
+	if !isCryptoKey(key) {
+		return fmt.Errorf("%q is not a crypto key", key)
+	}
+	patch := &kms.CryptoKey{
+		RotationPeriod:   fmt.Sprintf("%ds", int64(period.Seconds())),
+		NextRotationTime: now.Add(period).UTC().Format(time.RFC3339),
+	}
+	if _, err := k.client.PatchCryptoKey(ctx, key, "rotationPeriod,nextRotationTime", patch); err != nil {
+		return errors.Wrapf(err, "failed to schedule rotation of %q", key)
+	}
+	return nil
+}
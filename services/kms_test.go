@@ -0,0 +1,45 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	kms "google.golang.org/api/cloudkms/v1"
+)
+
+func TestScheduleRotation(t *testing.T) {
+	const key = "projects/test-project/locations/global/keyRings/ring/cryptoKeys/key"
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	kmsStub := &stubs.KMSStub{}
+	k := NewKMS(kmsStub)
+	if err := k.ScheduleRotation(context.Background(), key, 90*24*time.Hour, now); err != nil {
+		t.Fatalf("schedule rotation failed: %q", err)
+	}
+	expected := &kms.CryptoKey{RotationPeriod: "7776000s", NextRotationTime: "2020-08-30T00:00:00Z"}
+	if diff := cmp.Diff(expected, kmsStub.SavedKeyPatch); diff != "" {
+		t.Errorf("schedule rotation failed, difference: %+v", diff)
+	}
+	if kmsStub.SavedPatchMask != "rotationPeriod,nextRotationTime" {
+		t.Errorf("schedule rotation failed, got mask:%q", kmsStub.SavedPatchMask)
+	}
+	if err := k.ScheduleRotation(context.Background(), "projects/test-project/locations/global/keyRings/ring", time.Hour, now); err == nil {
+		t.Errorf("schedule rotation of a key ring should fail")
+	}
+}
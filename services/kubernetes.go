@@ -0,0 +1,153 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// mirrorPodAnnotation is set on static pods managed directly by the kubelet.
+const mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+// KubernetesClient contains minimum interface required by the service.
+type KubernetesClient interface {
+	Get(context.Context, string, interface{}) error
+	Patch(context.Context, string, interface{}) error
+	Post(context.Context, string, interface{}) error
+	Delete(context.Context, string) error
+}
+
+// Kubernetes service.
+type Kubernetes struct {
+	client KubernetesClient
+}
+
+// NewKubernetes returns a Kubernetes service.
+func NewKubernetes(client KubernetesClient) *Kubernetes {
+	return &Kubernetes{client: client}
+}
+
+// KubernetesOwnerReference identifies the controller owning a Kubernetes object.
+type KubernetesOwnerReference struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// KubernetesObjectMeta is the subset of Kubernetes object metadata used by automations.
+type KubernetesObjectMeta struct {
+	Name            string                     `json:"name"`
+	Namespace       string                     `json:"namespace,omitempty"`
+	Labels          map[string]string          `json:"labels,omitempty"`
+	Annotations     map[string]string          `json:"annotations,omitempty"`
+	OwnerReferences []KubernetesOwnerReference `json:"ownerReferences,omitempty"`
+}
+
+// KubernetesPod is the subset of a Kubernetes pod used by automations.
+type KubernetesPod struct {
+	Metadata KubernetesObjectMeta `json:"metadata"`
+}
+
+// KubernetesPodList is a list of Kubernetes pods.
+type KubernetesPodList struct {
+	Items []KubernetesPod `json:"items"`
+}
+
+// NodePath returns the API path of a node.
+func NodePath(node string) string {
+	return "/api/v1/nodes/" + url.PathEscape(node)
+}
+
+// PodPath returns the API path of a pod.
+func PodPath(namespace, name string) string {
+	return fmt.Sprintf("/api/v1/namespaces/%s/pods/%s", url.PathEscape(namespace), url.PathEscape(name))
+}
+
+// NodePodsPath returns the API path listing all pods scheduled on a node.
+func NodePodsPath(node string) string {
+	return "/api/v1/pods?" + url.Values{"fieldSelector": []string{"spec.nodeName=" + node}}.Encode()
+}
+
+// CordonNode marks the node as unschedulable and adds the given labels to it.
+func (k *Kubernetes) CordonNode(ctx context.Context, node string, labels map[string]string) error {
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": labels},
+		"spec":     map[string]interface{}{"unschedulable": true},
+	}
+	return k.client.Patch(ctx, NodePath(node), patch)
+}
+
+// DrainNode evicts all pods from the node except DaemonSet and mirror pods, which would be
+// recreated on the same node. The evicted pods are returned as "namespace/name".
+func (k *Kubernetes) DrainNode(ctx context.Context, node string) ([]string, error) {
+	var pods KubernetesPodList
+	if err := k.client.Get(ctx, NodePodsPath(node), &pods); err != nil {
+		return nil, err
+	}
+	evicted := []string{}
+	failed := []string{}
+	for _, pod := range pods.Items {
+		if !evictable(pod) {
+			continue
+		}
+		name := pod.Metadata.Namespace + "/" + pod.Metadata.Name
+		if err := k.evictPod(ctx, pod.Metadata.Namespace, pod.Metadata.Name); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %q", name, err))
+			continue
+		}
+		evicted = append(evicted, name)
+	}
+	if len(failed) > 0 {
+		return evicted, fmt.Errorf("failed to evict %d pods from node %q: %s", len(failed), node, strings.Join(failed, "; "))
+	}
+	return evicted, nil
+}
+
+// DeletePod deletes a pod.
+func (k *Kubernetes) DeletePod(ctx context.Context, namespace, name string) error {
+	return k.client.Delete(ctx, PodPath(namespace, name))
+}
+
+// AnnotatePod adds the given annotations to a pod.
+func (k *Kubernetes) AnnotatePod(ctx context.Context, namespace, name string, annotations map[string]string) error {
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": annotations},
+	}
+	return k.client.Patch(ctx, PodPath(namespace, name), patch)
+}
+
+// evictPod evicts a pod using the eviction API so pod disruption budgets are respected.
+func (k *Kubernetes) evictPod(ctx context.Context, namespace, name string) error {
+	eviction := map[string]interface{}{
+		"apiVersion": "policy/v1",
+		"kind":       "Eviction",
+		"metadata":   KubernetesObjectMeta{Name: name, Namespace: namespace},
+	}
+	return k.client.Post(ctx, PodPath(namespace, name)+"/eviction", eviction)
+}
+
+func evictable(pod KubernetesPod) bool {
+	if _, ok := pod.Metadata.Annotations[mirrorPodAnnotation]; ok {
+		return false
+	}
+	for _, owner := range pod.Metadata.OwnerReferences {
+		if owner.Kind == "DaemonSet" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,166 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	compute "google.golang.org/api/compute/v1"
+)
+
+// LoadBalancerClient holds the minimum interface required by the load balancer service.
+type LoadBalancerClient interface {
+	ListGlobalForwardingRules(context.Context, string) (*compute.ForwardingRuleList, error)
+	DeleteGlobalForwardingRule(context.Context, string, string) (*compute.Operation, error)
+	TargetHTTPProxy(context.Context, string, string) (*compute.TargetHttpProxy, error)
+	TargetHTTPSProxy(context.Context, string, string) (*compute.TargetHttpsProxy, error)
+	URLMap(context.Context, string, string) (*compute.UrlMap, error)
+	BackendService(context.Context, string, string) (*compute.BackendService, error)
+	PatchBackendService(context.Context, string, string, *compute.BackendService) (*compute.Operation, error)
+	WaitGlobal(string, *compute.Operation) []error
+}
+
+// LoadBalancer service manages external HTTP(S) load balancers.
+type LoadBalancer struct {
+	client LoadBalancerClient
+}
+
+// NewLoadBalancer returns a load balancer service.
+func NewLoadBalancer(client LoadBalancerClient) *LoadBalancer {
+	return &LoadBalancer{client: client}
+}
+
+// ExternalForwardingRules returns the names of the external global forwarding rules whose target
+// HTTP or HTTPS proxy routes traffic to the backend service through its URL map.
+func (l *LoadBalancer) ExternalForwardingRules(ctx context.Context, projectID, backendService string) ([]string, error) {
+	rules, err := l.client.ListGlobalForwardingRules(ctx, projectID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list forwarding rules in %q", projectID)
+	}
+	names := []string{}
+	routes := map[string]bool{}
+	for _, rule := range rules.Items {
+		if !strings.HasPrefix(rule.LoadBalancingScheme, "EXTERNAL") {
+			continue
+		}
+		urlMap, err := l.proxyURLMap(ctx, projectID, rule.Target)
+		if err != nil {
+			return nil, err
+		}
+		if urlMap == "" {
+			continue
+		}
+		routed, ok := routes[urlMap]
+		if !ok {
+			m, err := l.client.URLMap(ctx, projectID, urlMap)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get url map %q", urlMap)
+			}
+			routed = routesTo(m, backendService)
+			routes[urlMap] = routed
+		}
+		if routed {
+			names = append(names, rule.Name)
+		}
+	}
+	return names, nil
+}
+
+// DeleteForwardingRule deletes the global forwarding rule and waits for it to complete.
+func (l *LoadBalancer) DeleteForwardingRule(ctx context.Context, projectID, rule string) error {
+	op, err := l.client.DeleteGlobalForwardingRule(ctx, projectID, rule)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete forwarding rule %q", rule)
+	}
+	if errs := l.client.WaitGlobal(projectID, op); len(errs) > 0 {
+		return errors.Wrap(errs[0], "failed waiting")
+	}
+	return nil
+}
+
+// DrainBackendService sets the capacity scaler of every backend of the service to zero so it stops
+// receiving traffic while its configuration is kept. The previous capacity scalers are returned
+// keyed by backend group.
+func (l *LoadBalancer) DrainBackendService(ctx context.Context, projectID, backendService string) (map[string]float64, error) {
+	bs, err := l.client.BackendService(ctx, projectID, backendService)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get backend service %q", backendService)
+	}
+	previous := map[string]float64{}
+	for _, b := range bs.Backends {
+		previous[b.Group] = b.CapacityScaler
+		b.CapacityScaler = 0
+		b.ForceSendFields = append(b.ForceSendFields, "CapacityScaler")
+	}
+	op, err := l.client.PatchBackendService(ctx, projectID, backendService, &compute.BackendService{
+		Backends:    bs.Backends,
+		Fingerprint: bs.Fingerprint,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to drain backend service %q", backendService)
+	}
+	if errs := l.client.WaitGlobal(projectID, op); len(errs) > 0 {
+		return nil, errors.Wrap(errs[0], "failed waiting")
+	}
+	return previous, nil
+}
+
+// proxyURLMap returns the name of the URL map of a target HTTP or HTTPS proxy, empty if the target
+// is another kind of proxy.
+func (l *LoadBalancer) proxyURLMap(ctx context.Context, projectID, target string) (string, error) {
+	name := resourceName(target)
+	switch {
+	case strings.Contains(target, "/targetHttpProxies/"):
+		p, err := l.client.TargetHTTPProxy(ctx, projectID, name)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to get target http proxy %q", name)
+		}
+		return resourceName(p.UrlMap), nil
+	case strings.Contains(target, "/targetHttpsProxies/"):
+		p, err := l.client.TargetHTTPSProxy(ctx, projectID, name)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to get target https proxy %q", name)
+		}
+		return resourceName(p.UrlMap), nil
+	}
+	return "", nil
+}
+
+// routesTo returns true if any rule of the URL map sends traffic to the backend service.
+func routesTo(m *compute.UrlMap, backendService string) bool {
+	services := []string{m.DefaultService}
+	for _, pm := range m.PathMatchers {
+		services = append(services, pm.DefaultService)
+		for _, pr := range pm.PathRules {
+			services = append(services, pr.Service)
+		}
+		for _, rr := range pm.RouteRules {
+			services = append(services, rr.Service)
+		}
+	}
+	for _, s := range services {
+		if s != "" && resourceName(s) == backendService {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceName returns the last segment of a resource URL.
+func resourceName(url string) string {
+	return url[strings.LastIndex(url, "/")+1:]
+}
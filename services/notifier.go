@@ -0,0 +1,324 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/googlecloudplatform/security-response-automation/clients"
+	"github.com/pkg/errors"
+)
+
+// NotificationConfig configures the notification channels automations can notify.
+type NotificationConfig struct {
+	Email struct {
+		SendGrid struct {
+			APIKey string `yaml:"api_key"`
+		} `yaml:"sendgrid"`
+		// SMTP sends the emails through an SMTP server if SendGrid is not configured.
+		SMTP SMTPConfig `yaml:"smtp"`
+		From string
+		To   []string
+	}
+	Slack    SlackConfig
+	Teams    TeamsConfig
+	Chat     ChatConfig
+	Twilio   TwilioConfig
+	Opsgenie OpsgenieConfig
+	PubSub   struct {
+		Topic string
+	} `yaml:"pubsub"`
+	Webhook struct {
+		URL string
+	}
+	// Digests batches the notifications of the channels, keyed by channel name, into one message
+	// per window.
+	Digests map[string]DigestConfig
+}
+
+// Notification is the outcome of an automation, sent to the channels the automation notifies.
+type Notification struct {
+	// RemediationID identifies the remediation in the audit trail.
+	RemediationID string    `json:"remediation_id"`
+	Time          time.Time `json:"time"`
+	FindingID     string    `json:"finding_id"`
+	Severity      string    `json:"severity,omitempty"`
+	ProjectID     string    `json:"project_id"`
+	// Action is the automation's Cloud Function, such as "CloseBucket".
+	Action  string `json:"action"`
+	Outcome string `json:"outcome"`
+	Error   string `json:"error,omitempty"`
+	// Reason is why the finding was skipped.
+	Reason string `json:"reason,omitempty"`
+	// Replaying is set on failed remediations that are replayed on schedule, so paging channels
+	// wait for their last attempt.
+	Replaying bool `json:"replaying,omitempty"`
+	// Attachments are the evidence of the remediation, attached by channels supporting files.
+	Attachments []clients.Attachment `json:"-"`
+	// Digest are the notifications summarized by a digest.
+	Digest []*Notification `json:"digest,omitempty"`
+}
+
+// NewNotification returns the notification of the audit record.
+func NewNotification(r *AuditRecord) *Notification {
+	return &Notification{
+		RemediationID: r.ID,
+		Time:          r.Time,
+		FindingID:     r.FindingID,
+		Severity:      r.Severity,
+		ProjectID:     r.ProjectID,
+		Action:        r.Action,
+		Outcome:       r.Outcome,
+		Error:         r.Error,
+		Reason:        r.Reason,
+		Attachments:   r.Evidence,
+	}
+}
+
+// Subject summarizes the notification in a line.
+func (n *Notification) Subject() string {
+	if n.Digest != nil {
+		return fmt.Sprintf("Security Response Automation digest: %d remediations, %s", len(n.Digest), n.Outcome)
+	}
+	if n.ProjectID == "" {
+		return fmt.Sprintf("%s: %s", n.Action, n.Outcome)
+	}
+	return fmt.Sprintf("%s in %q: %s", n.Action, n.ProjectID, n.Outcome)
+}
+
+// Text describes the notification in plain text.
+func (n *Notification) Text() string {
+	var b strings.Builder
+	if n.Digest != nil {
+		for _, d := range n.Digest {
+			fmt.Fprintf(&b, "- %s %s", d.Time.UTC().Format(time.RFC3339), d.Subject())
+			if d.FindingID != "" {
+				fmt.Fprintf(&b, ", finding %q", d.FindingID)
+			}
+			if d.Error != "" {
+				fmt.Fprintf(&b, ": %s", d.Error)
+			}
+			b.WriteString("\n")
+		}
+		return b.String()
+	}
+	fmt.Fprintf(&b, "Security Response Automation ran %q", n.Action)
+	if n.FindingID != "" {
+		fmt.Fprintf(&b, " for finding %q", n.FindingID)
+	}
+	fmt.Fprintf(&b, ".\n\nOutcome: %s\n", n.Outcome)
+	if n.Severity != "" {
+		fmt.Fprintf(&b, "Severity: %s\n", n.Severity)
+	}
+	if n.ProjectID != "" {
+		fmt.Fprintf(&b, "Project: %s\n", n.ProjectID)
+	}
+	if n.Error != "" {
+		fmt.Fprintf(&b, "Error: %s\n", n.Error)
+	}
+	if n.Reason != "" {
+		fmt.Fprintf(&b, "Reason: %s\n", n.Reason)
+	}
+	fmt.Fprintf(&b, "Remediation ID: %s\n", n.RemediationID)
+	return b.String()
+}
+
+// Notifier sends notifications to a channel.
+type Notifier interface {
+	Notify(ctx context.Context, n *Notification) error
+}
+
+// Notifications service sends notifications to the channels they are configured for, by name.
+type Notifications struct {
+	notifiers map[string]Notifier
+	digests   *Digests
+}
+
+// NewNotifications returns a notifications service with the notifiers keyed by channel name.
+func NewNotifications(notifiers map[string]Notifier) *Notifications {
+	return &Notifications{notifiers: notifiers}
+}
+
+// NewDigestedNotifications returns a notifications service batching the notifications of the
+// digested channels.
+func NewDigestedNotifications(notifiers map[string]Notifier, digests *Digests) *Notifications {
+	return &Notifications{notifiers: notifiers, digests: digests}
+}
+
+// Notify sends the notification to each of the channels. All channels are tried even if some fail.
+func (s *Notifications) Notify(ctx context.Context, channels []string, n *Notification) error {
+	var errs []string
+	for _, c := range channels {
+		notifier, ok := s.notifiers[c]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("channel %q is not configured", c))
+			continue
+		}
+		if s.digests != nil {
+			queued, err := s.digests.Queue(ctx, c, n)
+			if err != nil {
+				errs = append(errs, err.Error())
+			}
+			if queued {
+				continue
+			}
+		}
+		if err := notifier.Notify(ctx, n); err != nil {
+			errs = append(errs, fmt.Sprintf("failed to notify %q: %q", c, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// SendDigests sends a digest to each channel whose window has passed by now and removes the
+// notifications it summarizes. All channels are tried even if some fail.
+func (s *Notifications) SendDigests(ctx context.Context, now time.Time) error {
+	if s.digests == nil {
+		return nil
+	}
+	due, err := s.digests.due(ctx, now)
+	if err != nil {
+		return err
+	}
+	channels := make([]string, 0, len(due))
+	for c := range due {
+		channels = append(channels, c)
+	}
+	sort.Strings(channels)
+	var errs []string
+	for _, c := range channels {
+		notifier, ok := s.notifiers[c]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("channel %q is not configured", c))
+			continue
+		}
+		ns := make([]*Notification, 0, len(due[c]))
+		for _, q := range due[c] {
+			ns = append(ns, q.notification)
+		}
+		if err := notifier.Notify(ctx, NewDigest(ns)); err != nil {
+			errs = append(errs, fmt.Sprintf("failed to send digest to %q: %q", c, err))
+			continue
+		}
+		if err := s.digests.remove(ctx, due[c]); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// Channels returns the names of the configured channels.
+func (s *Notifications) Channels() []string {
+	channels := make([]string, 0, len(s.notifiers))
+	for c := range s.notifiers {
+		channels = append(channels, c)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// EmailNotifier sends notifications by email.
+type EmailNotifier struct {
+	email *Email
+	from  string
+	to    []string
+}
+
+// NewEmailNotifier returns a notifier emailing from the address to the recipients.
+func NewEmailNotifier(email *Email, from string, to []string) *EmailNotifier {
+	return &EmailNotifier{email: email, from: from, to: to}
+}
+
+// Notify emails the notification.
+func (e *EmailNotifier) Notify(ctx context.Context, n *Notification) error {
+	_, err := e.email.Send(ctx, n.Subject(), e.from, n.Text(), e.to, n.Attachments...)
+	return err
+}
+
+// PubSubNotifier publishes notifications as JSON to a topic.
+type PubSubNotifier struct {
+	pubsub *PubSub
+	topic  string
+}
+
+// NewPubSubNotifier returns a notifier publishing to the topic.
+func NewPubSubNotifier(pubsub *PubSub, topic string) *PubSubNotifier {
+	return &PubSubNotifier{pubsub: pubsub, topic: topic}
+}
+
+// Notify publishes the notification.
+func (p *PubSubNotifier) Notify(ctx context.Context, n *Notification) error {
+	b, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	_, err = p.pubsub.Publish(ctx, p.topic, &pubsub.Message{Data: b})
+	return err
+}
+
+// WebhookClient contains minimum interface required by the webhook notifiers.
+type WebhookClient interface {
+	Post(ctx context.Context, body []byte) error
+}
+
+// WebhookNotifier posts notifications as JSON to a webhook.
+type WebhookNotifier struct {
+	client WebhookClient
+}
+
+// NewWebhookNotifier returns a notifier posting to the webhook.
+func NewWebhookNotifier(client WebhookClient) *WebhookNotifier {
+	return &WebhookNotifier{client: client}
+}
+
+// Notify posts the notification.
+func (w *WebhookNotifier) Notify(ctx context.Context, n *Notification) error {
+	b, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	return w.client.Post(ctx, b)
+}
+
+// SlackWebhookNotifier posts notifications to a Slack incoming webhook.
+type SlackWebhookNotifier struct {
+	client WebhookClient
+}
+
+// NewSlackWebhookNotifier returns a notifier posting to the Slack incoming webhook.
+func NewSlackWebhookNotifier(client WebhookClient) *SlackWebhookNotifier {
+	return &SlackWebhookNotifier{client: client}
+}
+
+// Notify posts the notification as a Slack message.
+func (s *SlackWebhookNotifier) Notify(ctx context.Context, n *Notification) error {
+	b, err := json.Marshal(map[string]string{"text": "*" + n.Subject() + "*\n" + n.Text()})
+	if err != nil {
+		return err
+	}
+	return s.client.Post(ctx, b)
+}
@@ -0,0 +1,96 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/clients"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"github.com/sendgrid/rest"
+)
+
+func TestNotify(t *testing.T) {
+	n := &Notification{
+		RemediationID: "0b7e3c1a",
+		Time:          time.Date(2020, 6, 2, 0, 0, 0, 0, time.UTC),
+		FindingID:     "organizations/456/sources/1/findings/2",
+		ProjectID:     "test-project",
+		Action:        "CloseBucket",
+		Outcome:       AuditSuccess,
+	}
+	for _, tt := range []struct {
+		name         string
+		channels     []string
+		webhookErr   error
+		wantErr      bool
+		wantMail     int
+		wantSlack    int
+		wantWebhooks int
+	}{
+		{name: "email", channels: []string{"email"}, wantMail: 1},
+		{name: "slack and webhook", channels: []string{"slack", "webhook"}, wantSlack: 1, wantWebhooks: 1},
+		{name: "unknown channel", channels: []string{"pager", "email"}, wantErr: true, wantMail: 1},
+		{name: "failing channel", channels: []string{"webhook", "email"}, webhookErr: errors.New("webhook returned 500"), wantErr: true, wantMail: 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sgStub := &stubs.SendGridStub{StubbedSend: &rest.Response{StatusCode: 202}}
+			slackStub := &stubs.WebhookStub{}
+			webhookStub := &stubs.WebhookStub{StubbedErr: tt.webhookErr}
+			notifications := NewNotifications(map[string]Notifier{
+				"email":   NewEmailNotifier(NewEmail(&clients.SendGrid{Service: sgStub}), "automation@cloudorg.com", []string{"security@cloudorg.com"}),
+				"slack":   NewSlackWebhookNotifier(slackStub),
+				"webhook": NewWebhookNotifier(webhookStub),
+			})
+			err := notifications.Notify(context.Background(), tt.channels, n)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("%s failed: got error %v", tt.name, err)
+			}
+			if len(sgStub.SentMail) != tt.wantMail {
+				t.Errorf("%s failed: got %d emails want %d", tt.name, len(sgStub.SentMail), tt.wantMail)
+			}
+			if len(slackStub.SavedRequests) != tt.wantSlack {
+				t.Errorf("%s failed: got %d slack messages want %d", tt.name, len(slackStub.SavedRequests), tt.wantSlack)
+			}
+			if len(webhookStub.SavedRequests) != tt.wantWebhooks {
+				t.Errorf("%s failed: got %d webhook requests want %d", tt.name, len(webhookStub.SavedRequests), tt.wantWebhooks)
+			}
+			for _, r := range slackStub.SavedRequests {
+				var msg struct{ Text string }
+				if err := json.Unmarshal([]byte(r), &msg); err != nil {
+					t.Fatalf("%s failed: %q", tt.name, err)
+				}
+				if !strings.HasPrefix(msg.Text, `*CloseBucket in "test-project": success*`) {
+					t.Errorf("%s failed: got slack message %q", tt.name, msg.Text)
+				}
+			}
+			for _, r := range webhookStub.SavedRequests {
+				var got Notification
+				if err := json.Unmarshal([]byte(r), &got); err != nil {
+					t.Fatalf("%s failed: %q", tt.name, err)
+				}
+				if !reflect.DeepEqual(&got, n) {
+					t.Errorf("%s failed: got %+v want %+v", tt.name, got, n)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// OpsgenieConfig configures the Opsgenie alerts opened for failed remediations.
+type OpsgenieConfig struct {
+	APIKey string `yaml:"api_key"`
+	// Region is "eu" for accounts hosted in the EU, the US otherwise.
+	Region string
+	// Priorities maps the severity of the finding to the priority of its alert, from P1 to P5.
+	// Critical findings are P1, high P2, medium P3 and low P4 by default.
+	Priorities map[string]string
+	Tags       []string
+}
+
+// OpsgenieClient contains minimum interface required by the Opsgenie notifier.
+type OpsgenieClient interface {
+	CreateAlert(ctx context.Context, alias, message, description, priority string, tags []string, details map[string]string) error
+	CloseAlert(ctx context.Context, alias, note string) error
+}
+
+// defaultOpsgeniePriorities maps finding severities to alert priorities if none are configured.
+var defaultOpsgeniePriorities = map[string]string{"CRITICAL": "P1", "HIGH": "P2", "MEDIUM": "P3", "LOW": "P4"}
+
+// OpsgenieNotifier opens an Opsgenie alert when the remediation of a finding fails and closes it
+// once the finding is remediated.
+type OpsgenieNotifier struct {
+	client     OpsgenieClient
+	priorities map[string]string
+	tags       []string
+}
+
+// NewOpsgenieNotifier returns a notifier alerting as configured.
+func NewOpsgenieNotifier(client OpsgenieClient, conf OpsgenieConfig) *OpsgenieNotifier {
+	priorities := defaultOpsgeniePriorities
+	if len(conf.Priorities) > 0 {
+		priorities = map[string]string{}
+		for severity, p := range conf.Priorities {
+			priorities[strings.ToUpper(severity)] = strings.ToUpper(p)
+		}
+	}
+	return &OpsgenieNotifier{client: client, priorities: priorities, tags: conf.Tags}
+}
+
+// Notify opens an alert for failures and closes the alert of the finding on success. Other
+// outcomes are ignored. Each finding has one alert, its alias is the hash of the finding ID.
+func (o *OpsgenieNotifier) Notify(ctx context.Context, n *Notification) error {
+	alias := documentID(n.FindingID)
+	switch n.Outcome {
+	case AuditFailure:
+		message := fmt.Sprintf("%s failed for finding in %s", n.Action, n.ProjectID)
+		if n.ProjectID == "" {
+			message = fmt.Sprintf("%s failed", n.Action)
+		}
+		details := map[string]string{
+			"finding_id":     n.FindingID,
+			"remediation_id": n.RemediationID,
+			"action":         n.Action,
+			"severity":       n.Severity,
+		}
+		if err := o.client.CreateAlert(ctx, alias, message, n.Text(), o.priority(n.Severity), o.tags, details); err != nil {
+			return fmt.Errorf("failed to create opsgenie alert: %q", err)
+		}
+	case AuditSuccess:
+		if err := o.client.CloseAlert(ctx, alias, fmt.Sprintf("Remediated by %s, remediation %s.", n.Action, n.RemediationID)); err != nil {
+			return fmt.Errorf("failed to close opsgenie alert: %q", err)
+		}
+	}
+	return nil
+}
+
+// priority returns the priority of alerts for findings with the severity, P3 if it is not mapped.
+func (o *OpsgenieNotifier) priority(severity string) string {
+	if p, ok := o.priorities[strings.ToUpper(severity)]; ok {
+		return p
+	}
+	return "P3"
+}
@@ -0,0 +1,54 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+)
+
+func TestOpsgenieNotifier(t *testing.T) {
+	ctx := context.Background()
+	alias := documentID("f1")
+	for _, tt := range []struct {
+		name         string
+		priorities   map[string]string
+		notification *Notification
+		wantPriority string
+		wantClosed   bool
+	}{
+		{name: "critical failure", notification: &Notification{FindingID: "f1", Action: "CloseBucket", Severity: "CRITICAL", Outcome: AuditFailure}, wantPriority: "P1"},
+		{name: "unmapped severity", notification: &Notification{FindingID: "f1", Action: "CloseBucket", Outcome: AuditFailure}, wantPriority: "P3"},
+		{name: "configured priority", priorities: map[string]string{"high": "p1"}, notification: &Notification{FindingID: "f1", Action: "CloseBucket", Severity: "HIGH", Outcome: AuditFailure}, wantPriority: "P1"},
+		{name: "success closes", notification: &Notification{FindingID: "f1", Action: "CloseBucket", Severity: "HIGH", Outcome: AuditSuccess}, wantClosed: true},
+		{name: "dry run ignored", notification: &Notification{FindingID: "f1", Action: "CloseBucket", Severity: "HIGH", Outcome: AuditDryRun}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubs.OpsgenieStub{}
+			n := NewOpsgenieNotifier(stub, OpsgenieConfig{Priorities: tt.priorities})
+			if err := n.Notify(ctx, tt.notification); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if got := stub.SavedAlerts[alias]; got != tt.wantPriority {
+				t.Errorf("%s failed: got priority %q want %q", tt.name, got, tt.wantPriority)
+			}
+			if _, closed := stub.ClosedAlerts[alias]; closed != tt.wantClosed {
+				t.Errorf("%s failed: got closed %t want %t", tt.name, closed, tt.wantClosed)
+			}
+		})
+	}
+}
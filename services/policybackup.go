@@ -0,0 +1,173 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	crm "google.golang.org/api/cloudresourcemanager/v1"
+)
+
+const (
+	// policySnapshotPrefix is the object prefix under which IAM policy snapshots are stored.
+	policySnapshotPrefix = "iam-policies/"
+	// policySnapshotTimeFormat is used to name snapshots so they sort chronologically.
+	policySnapshotTimeFormat = "20060102T150405Z"
+)
+
+// PolicyBackupStorage contains minimum interface required by the policy backup service.
+type PolicyBackupStorage interface {
+	WriteObject(context.Context, string, string, []byte) error
+	ReadObject(context.Context, string, string) ([]byte, error)
+	ListObjects(context.Context, string, string) ([]string, error)
+}
+
+// PolicyBackup service stores and restores snapshots of project and organization IAM policies.
+type PolicyBackup struct {
+	crm     crmClient
+	storage PolicyBackupStorage
+}
+
+// PolicySnapshot is a stored copy of a resource's IAM policy.
+type PolicySnapshot struct {
+	// Resource is either projects/<project-id> or organizations/<organization-id>.
+	Resource string
+	// Object is the name of the object holding the policy.
+	Object string
+	// Time is when the snapshot was taken.
+	Time time.Time
+}
+
+// NewPolicyBackup returns a policy backup service.
+func NewPolicyBackup(crm crmClient, storage PolicyBackupStorage) *PolicyBackup {
+	return &PolicyBackup{crm: crm, storage: storage}
+}
+
+// Store snapshots the current IAM policy of the resource into the bucket.
+func (p *PolicyBackup) Store(ctx context.Context, bucket, resource string, now time.Time) (*PolicySnapshot, error) {
+	policy, err := resourcePolicy(ctx, p.crm, resource)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(policy)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal policy of %q", resource)
+	}
+	snapshot := &PolicySnapshot{
+		Resource: resource,
+		Object:   policySnapshotPrefix + resource + "/" + now.UTC().Format(policySnapshotTimeFormat) + ".json",
+		Time:     now.UTC().Truncate(time.Second),
+	}
+	if err := p.storage.WriteObject(ctx, bucket, snapshot.Object, b); err != nil {
+		return nil, errors.Wrapf(err, "failed to write policy snapshot %q", snapshot.Object)
+	}
+	return snapshot, nil
+}
+
+// List returns the snapshots of the resource stored in the bucket, oldest first.
+func (p *PolicyBackup) List(ctx context.Context, bucket, resource string) ([]*PolicySnapshot, error) {
+	names, err := p.storage.ListObjects(ctx, bucket, policySnapshotPrefix+resource+"/")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list policy snapshots of %q", resource)
+	}
+	snapshots := []*PolicySnapshot{}
+	for _, name := range names {
+		t, err := time.Parse(policySnapshotTimeFormat, strings.TrimSuffix(path.Base(name), ".json"))
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, &PolicySnapshot{Resource: resource, Object: name, Time: t})
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Time.Before(snapshots[j].Time) })
+	return snapshots, nil
+}
+
+// Latest returns the most recent snapshot of the resource taken before the given time.
+func (p *PolicyBackup) Latest(ctx context.Context, bucket, resource string, before time.Time) (*PolicySnapshot, error) {
+	snapshots, err := p.List(ctx, bucket, resource)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if snapshots[i].Time.Before(before) {
+			return snapshots[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no policy snapshot of %q taken before %s", resource, before.Format(time.RFC3339))
+}
+
+// Restore replaces the resource's current IAM policy with the one held by the snapshot.
+func (p *PolicyBackup) Restore(ctx context.Context, bucket string, snapshot *PolicySnapshot) error {
+	b, err := p.storage.ReadObject(ctx, bucket, snapshot.Object)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read policy snapshot %q", snapshot.Object)
+	}
+	var policy crm.Policy
+	if err := json.Unmarshal(b, &policy); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal policy snapshot %q", snapshot.Object)
+	}
+	current, err := resourcePolicy(ctx, p.crm, snapshot.Resource)
+	if err != nil {
+		return err
+	}
+	// Use the current etag so the restore overwrites any changes made since the snapshot.
+	policy.Etag = current.Etag
+	return setResourcePolicy(ctx, p.crm, snapshot.Resource, &policy)
+}
+
+// resourcePolicy returns the IAM policy of the project, folder or organization.
+func resourcePolicy(ctx context.Context, c crmClient, resource string) (*crm.Policy, error) {
+	var policy *crm.Policy
+	var err error
+	switch {
+	case strings.HasPrefix(resource, "projects/"):
+		policy, err = c.GetPolicyProject(ctx, strings.TrimPrefix(resource, "projects/"))
+	case strings.HasPrefix(resource, "organizations/"):
+		policy, err = c.GetPolicyOrganization(ctx, resource)
+	case strings.HasPrefix(resource, "folders/"):
+		policy, err = c.GetPolicyFolder(ctx, resource)
+	default:
+		return nil, fmt.Errorf("unsupported resource %q", resource)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get policy of %q", resource)
+	}
+	return policy, nil
+}
+
+// setResourcePolicy sets the IAM policy of the project, folder or organization.
+func setResourcePolicy(ctx context.Context, c crmClient, resource string, policy *crm.Policy) error {
+	var err error
+	switch {
+	case strings.HasPrefix(resource, "projects/"):
+		_, err = c.SetPolicyProject(ctx, strings.TrimPrefix(resource, "projects/"), policy)
+	case strings.HasPrefix(resource, "organizations/"):
+		_, err = c.SetPolicyOrganization(ctx, resource, policy)
+	case strings.HasPrefix(resource, "folders/"):
+		_, err = c.SetPolicyFolder(ctx, resource, policy)
+	default:
+		return fmt.Errorf("unsupported resource %q", resource)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to set policy of %q", resource)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	crm "google.golang.org/api/cloudresourcemanager/v1"
+)
+
+// TestPolicyBackupStoreAndList tests storing and listing policy snapshots.
+func TestPolicyBackupStoreAndList(t *testing.T) {
+	ctx := context.Background()
+	crmStub := &stubs.ResourceManagerStub{
+		GetPolicyResponse: &crm.Policy{Bindings: createBindings([]string{"user:bob@gmail.com"})},
+	}
+	storageStub := &stubs.StorageStub{}
+	p := NewPolicyBackup(crmStub, storageStub)
+	first := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	second := first.Add(time.Hour)
+	for _, now := range []time.Time{second, first} {
+		if _, err := p.Store(ctx, "bucket", "projects/test-project", now); err != nil {
+			t.Fatalf("Store() failed: %q", err)
+		}
+	}
+	// Objects which aren't named after a timestamp are ignored.
+	storageStub.Objects["bucket/iam-policies/projects/test-project/README"] = []byte("")
+	got, err := p.List(ctx, "bucket", "projects/test-project")
+	if err != nil {
+		t.Fatalf("List() failed: %q", err)
+	}
+	want := []*PolicySnapshot{
+		{Resource: "projects/test-project", Object: "iam-policies/projects/test-project/20200102T030405Z.json", Time: first},
+		{Resource: "projects/test-project", Object: "iam-policies/projects/test-project/20200102T040405Z.json", Time: second},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("List() returned unexpected snapshots (-want +got):\n%s", diff)
+	}
+}
+
+// TestPolicyBackupLatest tests finding the most recent snapshot before a given time.
+func TestPolicyBackupLatest(t *testing.T) {
+	ctx := context.Background()
+	storageStub := &stubs.StorageStub{Objects: map[string][]byte{
+		"bucket/iam-policies/organizations/1234/20200101T000000Z.json": []byte("{}"),
+		"bucket/iam-policies/organizations/1234/20200102T000000Z.json": []byte("{}"),
+		"bucket/iam-policies/organizations/1234/20200103T000000Z.json": []byte("{}"),
+	}}
+	p := NewPolicyBackup(&stubs.ResourceManagerStub{}, storageStub)
+	tests := []struct {
+		name     string
+		before   time.Time
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "between snapshots",
+			before:   time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC),
+			expected: "iam-policies/organizations/1234/20200102T000000Z.json",
+		},
+		{
+			name:     "after all snapshots",
+			before:   time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC),
+			expected: "iam-policies/organizations/1234/20200103T000000Z.json",
+		},
+		{
+			name:    "before all snapshots",
+			before:  time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.Latest(ctx, "bucket", "organizations/1234", tt.before)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Latest() expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Latest() failed: %q", err)
+			}
+			if got.Object != tt.expected {
+				t.Errorf("Latest() = %q, want %q", got.Object, tt.expected)
+			}
+		})
+	}
+}
+
+// TestPolicyBackupRestore tests restoring a policy from a snapshot.
+func TestPolicyBackupRestore(t *testing.T) {
+	ctx := context.Background()
+	crmStub := &stubs.ResourceManagerStub{
+		GetPolicyResponse: &crm.Policy{
+			Bindings: createBindings([]string{"user:bob@gmail.com", "user:attacker@gmail.com"}),
+			Etag:     "current",
+		},
+	}
+	storageStub := &stubs.StorageStub{Objects: map[string][]byte{
+		"bucket/iam-policies/projects/test-project/20200101T000000Z.json": []byte(`{"bindings":[{"members":["user:bob@gmail.com"],"role":"roles/editor"}],"etag":"old"}`),
+	}}
+	p := NewPolicyBackup(crmStub, storageStub)
+	snapshot := &PolicySnapshot{
+		Resource: "projects/test-project",
+		Object:   "iam-policies/projects/test-project/20200101T000000Z.json",
+	}
+	if err := p.Restore(ctx, "bucket", snapshot); err != nil {
+		t.Fatalf("Restore() failed: %q", err)
+	}
+	want := &crm.Policy{Bindings: createBindings([]string{"user:bob@gmail.com"}), Etag: "current"}
+	if diff := cmp.Diff(want, crmStub.SavedSetPolicy); diff != "" {
+		t.Errorf("Restore() set unexpected policy (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,124 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	firestore "google.golang.org/api/firestore/v1"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	// projectLockCollection is the Firestore collection of the locks of projects being remediated.
+	projectLockCollection = "automation-project-locks"
+	// projectLockTTL is how long a lock is held at most. It outlasts the timeout of the automations
+	// so only the lock of an automation that crashed is taken over.
+	projectLockTTL = 10 * time.Minute
+	// projectLockPoll is how long to wait before trying to lock a locked project again.
+	projectLockPoll = time.Second
+)
+
+// ProjectLocks keeps automations from remediating the same project at once, so automations
+// writing the IAM policy of the project run one after the other.
+type ProjectLocks struct {
+	documents IdempotencyDocumentClient
+	projectID string
+	poll      time.Duration
+}
+
+// NewProjectLocks returns a project locks service keeping locks in the automation project.
+func NewProjectLocks(documents IdempotencyDocumentClient, projectID string) *ProjectLocks {
+	return &ProjectLocks{documents: documents, projectID: projectID, poll: projectLockPoll}
+}
+
+// Lock waits until the project is locked by the holder, or fails once the context is done.
+func (l *ProjectLocks) Lock(ctx context.Context, project, holder string) error {
+	for {
+		ok, err := l.TryLock(ctx, project, holder, time.Now())
+		if err != nil || ok {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "failed to wait for the lock of %q", project)
+		case <-time.After(l.poll):
+		}
+	}
+}
+
+// TryLock returns whether the project was locked by the holder, false if another holder locked it
+// and the lock hasn't expired by now.
+func (l *ProjectLocks) TryLock(ctx context.Context, project, holder string, now time.Time) (bool, error) {
+	fields := map[string]firestore.Value{
+		"holder":  {StringValue: holder},
+		"expires": {TimestampValue: now.Add(projectLockTTL).UTC().Format(time.RFC3339Nano)},
+	}
+	err := l.documents.CreateDocument(ctx, l.projectID, projectLockCollection, project, fields)
+	if err == nil {
+		return true, nil
+	}
+	if !alreadyExists(err) {
+		return false, errors.Wrapf(err, "failed to lock %q", project)
+	}
+	doc, err := l.documents.GetDocument(ctx, l.projectID, projectLockCollection, project)
+	if notFound(err) {
+		// Unlocked since, try again.
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to read the lock of %q", project)
+	}
+	expires, err := time.Parse(time.RFC3339Nano, doc.Fields["expires"].TimestampValue)
+	if err == nil && now.Before(expires) {
+		return false, nil
+	}
+	// The update fails if another holder took over the expired lock first.
+	if err := l.documents.UpdateDocument(ctx, l.projectID, projectLockCollection, project, fields, doc.UpdateTime); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+// Unlock releases the lock of the project if it's still held by the holder.
+func (l *ProjectLocks) Unlock(ctx context.Context, project, holder string) error {
+	doc, err := l.documents.GetDocument(ctx, l.projectID, projectLockCollection, project)
+	if notFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the lock of %q", project)
+	}
+	if doc.Fields["holder"].StringValue != holder {
+		return nil
+	}
+	// The delete fails if another holder took over the expired lock since it was read.
+	err = l.documents.DeleteDocument(ctx, l.projectID, projectLockCollection, project, doc.UpdateTime)
+	if err != nil && !notFound(err) && !preconditionFailed(err) {
+		return errors.Wrapf(err, "failed to unlock %q", project)
+	}
+	return nil
+}
+
+// preconditionFailed returns whether the document changed since the version a write was
+// conditioned on.
+func preconditionFailed(err error) bool {
+	e, ok := err.(*googleapi.Error)
+	return ok && e.Code == http.StatusBadRequest && strings.Contains(e.Body, "FAILED_PRECONDITION")
+}
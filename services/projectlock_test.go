@@ -0,0 +1,112 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	firestore "google.golang.org/api/firestore/v1"
+)
+
+func TestProjectLocks(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewProjectLocks(&stubs.FirestoreStub{}, "automation-project")
+	for _, tt := range []struct {
+		name   string
+		holder string
+		unlock string
+		now    time.Time
+		want   bool
+	}{
+		{name: "unlocked", holder: "first", now: now, want: true},
+		{name: "locked", holder: "second", now: now.Add(time.Minute), want: false},
+		{name: "unlocked by another holder", holder: "second", unlock: "second", now: now.Add(time.Minute), want: false},
+		{name: "unlocked by the holder", holder: "second", unlock: "first", now: now.Add(time.Minute), want: true},
+		{name: "expired", holder: "third", now: now.Add(time.Minute + projectLockTTL), want: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.unlock != "" {
+				if err := l.Unlock(ctx, "test-project", tt.unlock); err != nil {
+					t.Fatalf("%s failed to unlock: %q", tt.name, err)
+				}
+			}
+			got, err := l.TryLock(ctx, "test-project", tt.holder, tt.now)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if got != tt.want {
+				t.Errorf("%s failed: got %t want %t", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjectLocksWait(t *testing.T) {
+	l := NewProjectLocks(&stubs.FirestoreStub{}, "automation-project")
+	l.poll = time.Millisecond
+	if err := l.Lock(context.Background(), "test-project", "first"); err != nil {
+		t.Fatalf("failed to lock: %q", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Lock(ctx, "test-project", "second"); err == nil {
+		t.Errorf("locked a locked project")
+	}
+	if err := l.Unlock(context.Background(), "test-project", "first"); err != nil {
+		t.Fatalf("failed to unlock: %q", err)
+	}
+	if err := l.Lock(context.Background(), "test-project", "second"); err != nil {
+		t.Errorf("failed to lock an unlocked project: %q", err)
+	}
+}
+
+// takenOverFirestore hands out the lock as read, then lets another holder take it over.
+type takenOverFirestore struct {
+	*stubs.FirestoreStub
+}
+
+func (f *takenOverFirestore) GetDocument(ctx context.Context, projectID, collection, documentID string) (*firestore.Document, error) {
+	d, err := f.FirestoreStub.GetDocument(ctx, projectID, collection, documentID)
+	if err != nil {
+		return nil, err
+	}
+	read := *d
+	f.StubbedDocuments[collection+"/"+documentID] = &firestore.Document{
+		Fields:     map[string]firestore.Value{"holder": {StringValue: "second"}},
+		UpdateTime: "2020-01-01T00:11:00Z",
+	}
+	return &read, nil
+}
+
+func TestProjectLocksUnlockTakenOver(t *testing.T) {
+	f := &takenOverFirestore{&stubs.FirestoreStub{StubbedDocuments: map[string]*firestore.Document{
+		projectLockCollection + "/test-project": {
+			Fields:     map[string]firestore.Value{"holder": {StringValue: "first"}},
+			UpdateTime: "2020-01-01T00:00:00Z",
+		},
+	}}}
+	l := NewProjectLocks(f, "automation-project")
+	if err := l.Unlock(context.Background(), "test-project", "first"); err != nil {
+		t.Fatalf("failed to unlock: %q", err)
+	}
+	d, ok := f.StubbedDocuments[projectLockCollection+"/test-project"]
+	if !ok || d.Fields["holder"].StringValue != "second" {
+		t.Errorf("released the lock taken over by another holder")
+	}
+}
@@ -0,0 +1,77 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	pubsub "google.golang.org/api/pubsub/v1"
+)
+
+// PubSubAdminClient contains minimum interface required by the service.
+type PubSubAdminClient interface {
+	GetTopicPolicy(context.Context, string) (*pubsub.Policy, error)
+	SetTopicPolicy(context.Context, string, *pubsub.Policy) (*pubsub.Policy, error)
+	GetSubscriptionPolicy(context.Context, string) (*pubsub.Policy, error)
+	SetSubscriptionPolicy(context.Context, string, *pubsub.Policy) (*pubsub.Policy, error)
+}
+
+// PubSubAdmin service manages the IAM policies of topics and subscriptions.
+type PubSubAdmin struct {
+	client PubSubAdminClient
+}
+
+// NewPubSubAdmin returns a Pub/Sub admin service.
+func NewPubSubAdmin(client PubSubAdminClient) *PubSubAdmin {
+	return &PubSubAdmin{client: client}
+}
+
+// RemoveMembers removes the members from every role of the topic or subscription's policy.
+//
+// The resource is either "projects/<project>/topics/<topic>" or
+// "projects/<project>/subscriptions/<subscription>". The members removed are returned and the
+// policy is only updated if one was found.
+func (p *PubSubAdmin) RemoveMembers(ctx context.Context, resource string, members []string) ([]string, error) {
+	subscription := strings.Contains(resource, "/subscriptions/")
+	get, set := p.client.GetTopicPolicy, p.client.SetTopicPolicy
+	if subscription {
+		get, set = p.client.GetSubscriptionPolicy, p.client.SetSubscriptionPolicy
+	}
+	policy, err := get(ctx, resource)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get policy of %q", resource)
+	}
+	removed := []string{}
+	for _, b := range policy.Bindings {
+		keep := []string{}
+		for _, m := range b.Members {
+			if containsMember(members, m) {
+				removed = append(removed, m)
+				continue
+			}
+			keep = append(keep, m)
+		}
+		b.Members = keep
+	}
+	if len(removed) == 0 {
+		return removed, nil
+	}
+	if _, err := set(ctx, resource, policy); err != nil {
+		return nil, errors.Wrapf(err, "failed to set policy of %q", resource)
+	}
+	return removed, nil
+}
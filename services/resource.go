@@ -17,13 +17,20 @@ package services
 import (
 	"context"
 	"fmt"
-	"log"
+	"net/http"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"cloud.google.com/go/iam"
+	"cloud.google.com/go/storage"
 	"github.com/pkg/errors"
 	crm "google.golang.org/api/cloudresourcemanager/v1"
+	crmv2 "google.golang.org/api/cloudresourcemanager/v2"
+	"google.golang.org/api/googleapi"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
 )
 
 type crmClient interface {
@@ -34,73 +41,393 @@ type crmClient interface {
 	SetPolicyOrganization(context.Context, string, *crm.Policy) (*crm.Policy, error)
 	GetOrganization(context.Context, string) (*crm.Organization, error)
 	SetPolicyProjectWithMask(context.Context, string, *crm.Policy, ...string) (*crm.Policy, error)
+	SetOrgPolicyProject(context.Context, string, *crm.OrgPolicy) (*crm.OrgPolicy, error)
+	GetPolicyFolder(context.Context, string) (*crm.Policy, error)
+	SetPolicyFolder(context.Context, string, *crm.Policy) (*crm.Policy, error)
+	GetFolder(context.Context, string) (*crmv2.Folder, error)
 }
 
 type storageClient interface {
 	SetBucketPolicy(context.Context, string, *iam.Policy) error
 	BucketPolicy(context.Context, string) (*iam.Policy, error)
-	EnableBucketOnlyPolicy(context.Context, string) error
+	SetBucketPolicyOnly(context.Context, string, bool) error
+	BucketAttrs(context.Context, string) (*storage.BucketAttrs, error)
+	DeleteBucketACL(context.Context, string, storage.ACLEntity) error
 }
 
+// ancestryTTL is how long the ancestry of projects and folders is cached.
+const ancestryTTL = 10 * time.Minute
+
 // Resource service.
 type Resource struct {
 	crm     crmClient
 	storage storageClient
+	// mu guards ancestry, the cached ancestry paths keyed by project ID or folder name.
+	mu       sync.Mutex
+	ancestry map[string]cachedAncestry
+}
+
+// cachedAncestry is an ancestry path, such as organizations/456/folders/123/projects/p.
+type cachedAncestry struct {
+	path    string
+	expires time.Time
 }
 
 // NewResource returns a new resource service.
 func NewResource(crm crmClient, s storageClient) *Resource {
 	return &Resource{
-		crm:     crm,
-		storage: s,
+		crm:      crm,
+		storage:  s,
+		ancestry: map[string]cachedAncestry{},
 	}
 }
 
-// ProjectOnlyKeepUsersFromDomains removes users from the policy if they do not match the domain. (Non-users are not affected.)
-func (r *Resource) ProjectOnlyKeepUsersFromDomains(ctx context.Context, projectID string, allowDomains []string) ([]string, error) {
-	existingPolicy, err := r.crm.GetPolicyProject(ctx, projectID)
+// MemberFilter returns whether a member that is neither a user nor a domain should be removed.
+type MemberFilter func(ctx context.Context, member string) (bool, error)
+
+// KeepOptions configures which members are kept in addition to the users and domains matching the
+// allowed domains.
+type KeepOptions struct {
+	// UnconditionalOnly leaves bindings with an IAM condition untouched.
+	UnconditionalOnly bool
+	// InOrganization, if set, returns whether a user whose email does not match the allowed domains
+	// still belongs to the organization and is kept.
+	InOrganization func(ctx context.Context, email string) (bool, error)
+	// Filters are asked about members that are neither users nor domains, which are only removed if
+	// one of the filters returns true for them.
+	Filters []MemberFilter
+}
+
+// ProjectOnlyKeepUsersFromDomains removes users and domains from the policy if they do not match the domain.
+// Other members are only removed as configured by the options.
+func (r *Resource) ProjectOnlyKeepUsersFromDomains(ctx context.Context, projectID string, allowDomains []string, opts KeepOptions) ([]string, error) {
+	var removed []string
+	before, after, err := updatePolicy(ctx, "projects/"+projectID, func() (*crm.Policy, error) {
+		return r.crm.GetPolicyProject(ctx, projectID)
+	}, func(policy *crm.Policy) (*crm.Policy, error) {
+		var err error
+		removed, policy, err = r.keepUsersFromPolicy(ctx, policy, allowDomains, opts)
+		return policy, err
+	}, func(policy *crm.Policy) error {
+		_, err := r.crm.SetPolicyProject(ctx, projectID, policy)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get project policy: %q", err)
+		return nil, err
 	}
-	removed, policy, err := r.keepUsersFromPolicy(existingPolicy, allowDomains)
+	auditUndo(ctx, undoIAMPolicy, "projects/"+projectID, before, after)
+	return removed, nil
+}
+
+// OrganizationOnlyKeepUsersFromDomains removes all users and domains from an organization except where they match allowed domains.
+func (r *Resource) OrganizationOnlyKeepUsersFromDomains(ctx context.Context, orgID string, allowDomains []string, opts KeepOptions) ([]string, error) {
+	var removed []string
+	before, after, err := updatePolicy(ctx, orgID, func() (*crm.Policy, error) {
+		return r.crm.GetPolicyOrganization(ctx, orgID)
+	}, func(policy *crm.Policy) (*crm.Policy, error) {
+		var err error
+		removed, policy, err = r.keepUsersFromPolicy(ctx, policy, allowDomains, opts)
+		return policy, err
+	}, func(policy *crm.Policy) error {
+		_, err := r.crm.SetPolicyOrganization(ctx, orgID, policy)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	if _, err := r.crm.SetPolicyProject(ctx, projectID, policy); err != nil {
-		return nil, fmt.Errorf("failed to set project policy: %q", err)
+	auditUndo(ctx, undoIAMPolicy, orgID, before, after)
+	return removed, nil
+}
+
+// FolderOnlyKeepUsersFromDomains removes all users and domains from a folder except where they match allowed domains.
+func (r *Resource) FolderOnlyKeepUsersFromDomains(ctx context.Context, folderID string, allowDomains []string, opts KeepOptions) ([]string, error) {
+	var removed []string
+	before, after, err := updatePolicy(ctx, "folders/"+folderID, func() (*crm.Policy, error) {
+		return r.crm.GetPolicyFolder(ctx, "folders/"+folderID)
+	}, func(policy *crm.Policy) (*crm.Policy, error) {
+		var err error
+		removed, policy, err = r.keepUsersFromPolicy(ctx, policy, allowDomains, opts)
+		return policy, err
+	}, func(policy *crm.Policy) error {
+		_, err := r.crm.SetPolicyFolder(ctx, "folders/"+folderID, policy)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	auditUndo(ctx, undoIAMPolicy, "folders/"+folderID, before, after)
 	return removed, nil
 }
 
-// OrganizationOnlyKeepUsersFromDomains removes all users from an organization except where the user matches allowed domains.
-func (r *Resource) OrganizationOnlyKeepUsersFromDomains(ctx context.Context, orgID string, allowDomains []string) ([]string, error) {
-	existingPolicy, err := r.crm.GetPolicyOrganization(ctx, orgID)
+// PlanKeepUsersFromDomains returns the change the OnlyKeepUsersFromDomains methods would make to
+// the policy of the project, folder or organization, such as "folders/123", without setting it.
+func (r *Resource) PlanKeepUsersFromDomains(ctx context.Context, resource string, allowDomains []string, opts KeepOptions) (*Change, error) {
+	var before *crm.Policy
+	var err error
+	switch {
+	case strings.HasPrefix(resource, "projects/"):
+		before, err = r.crm.GetPolicyProject(ctx, strings.TrimPrefix(resource, "projects/"))
+	case strings.HasPrefix(resource, "folders/"):
+		before, err = r.crm.GetPolicyFolder(ctx, resource)
+	case strings.HasPrefix(resource, "organizations/"):
+		before, err = r.crm.GetPolicyOrganization(ctx, resource)
+	default:
+		return nil, fmt.Errorf("unsupported resource %q", resource)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get project policy: %q", err)
+		return nil, errors.Wrapf(err, "failed to get policy of %q", resource)
 	}
-	removed, policy, err := r.keepUsersFromPolicy(existingPolicy, allowDomains)
+	_, after, err := r.keepUsersFromPolicy(ctx, copyPolicy(before), allowDomains, opts)
 	if err != nil {
 		return nil, err
 	}
-	if _, err := r.crm.SetPolicyOrganization(ctx, orgID, policy); err != nil {
-		return nil, fmt.Errorf("failed to set project policy: %q", err)
+	return PolicyChange(resource, before, after), nil
+}
+
+// policyAttempts is how many times a policy is read, changed and set when setting it conflicts
+// with a concurrent change to the policy.
+const policyAttempts = 3
+
+// updatePolicy reads the policy of the resource with get, changes it with change and sets it
+// with set, returning the policy before and after. Setting a policy whose etag is stale conflicts
+// and resending it would conflict again, so the policy is read and changed again instead. change
+// returns nil to leave the policy as is, which isn't set and has no policy after.
+func updatePolicy(ctx context.Context, resource string, get func() (*crm.Policy, error), change func(*crm.Policy) (*crm.Policy, error), set func(*crm.Policy) error) (*crm.Policy, *crm.Policy, error) {
+	for attempt := 1; ; attempt++ {
+		policy, err := get()
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to get policy of %q", resource)
+		}
+		before := copyPolicy(policy)
+		after, err := change(policy)
+		if err != nil || after == nil {
+			return before, nil, err
+		}
+		err = set(after)
+		if err == nil {
+			return before, after, nil
+		}
+		if !conflict(err) || attempt >= policyAttempts {
+			return nil, nil, errors.Wrapf(err, "failed to set policy of %q", resource)
+		}
+		logging.FromContext(ctx).Warning("policy of %q changed concurrently, updating it again: %q", resource, err)
+	}
+}
+
+// updateProjectPolicy updates the policy of the project as updatePolicy does.
+func (r *Resource) updateProjectPolicy(ctx context.Context, projectID string, change func(*crm.Policy) (*crm.Policy, error)) (*crm.Policy, *crm.Policy, error) {
+	return updatePolicy(ctx, "projects/"+projectID, func() (*crm.Policy, error) {
+		return r.crm.GetPolicyProject(ctx, projectID)
+	}, change, func(policy *crm.Policy) error {
+		_, err := r.crm.SetPolicyProject(ctx, projectID, policy)
+		return err
+	})
+}
+
+// conflict returns whether the call failed because it conflicts with the resource's state, such
+// as a policy with a stale etag.
+func conflict(err error) bool {
+	e, ok := err.(*googleapi.Error)
+	return ok && e.Code == http.StatusConflict
+}
+
+// copyPolicy returns a copy of the policy whose bindings can be changed without changing the original.
+func copyPolicy(policy *crm.Policy) *crm.Policy {
+	c := *policy
+	c.Bindings = make([]*crm.Binding, len(policy.Bindings))
+	for i, b := range policy.Bindings {
+		binding := *b
+		binding.Members = append([]string(nil), b.Members...)
+		c.Bindings[i] = &binding
 	}
-	return removed, nil
+	return &c
 }
 
 // RemoveUsersProject removes a slice of users from a project.
 func (r *Resource) RemoveUsersProject(ctx context.Context, projectID string, remove []string) error {
-	existingPolicy, err := r.crm.GetPolicyProject(ctx, projectID)
+	before, after, err := r.updateProjectPolicy(ctx, projectID, func(policy *crm.Policy) (*crm.Policy, error) {
+		return r.removeUsersFromPolicy(policy, remove), nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get project policy: %q", err)
+		return err
 	}
-	policy := r.removeUsersFromPolicy(existingPolicy, remove)
-	if _, err := r.crm.SetPolicyProject(ctx, projectID, policy); err != nil {
-		return fmt.Errorf("failed to set project policy: %q", err)
+	auditUndo(ctx, undoIAMPolicy, "projects/"+projectID, before, after)
+	return nil
+}
+
+// RemoveRoleMembersProject removes the given members from the given roles of a project's policy.
+// The remove map is keyed by role, all other bindings are left untouched.
+func (r *Resource) RemoveRoleMembersProject(ctx context.Context, projectID string, remove map[string][]string) error {
+	before, after, err := r.updateProjectPolicy(ctx, projectID, func(policy *crm.Policy) (*crm.Policy, error) {
+		for _, b := range policy.Bindings {
+			users, ok := remove[b.Role]
+			if !ok {
+				continue
+			}
+			members := []string{}
+			for _, member := range b.Members {
+				found := false
+				for _, user := range users {
+					if strings.EqualFold(user, member) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					members = append(members, member)
+				}
+			}
+			b.Members = members
+		}
+		return policy, nil
+	})
+	if err != nil {
+		return err
 	}
+	auditUndo(ctx, undoIAMPolicy, "projects/"+projectID, before, after)
 	return nil
 }
 
+// RemoveMemberRolesProject removes the member from the given roles of a project's policy and
+// returns the roles it was removed from. The policy is only updated if the member was found.
+func (r *Resource) RemoveMemberRolesProject(ctx context.Context, projectID, member string, roles []string) ([]string, error) {
+	var removed []string
+	before, after, err := r.updateProjectPolicy(ctx, projectID, func(policy *crm.Policy) (*crm.Policy, error) {
+		removed = []string{}
+		for _, b := range policy.Bindings {
+			if !containsMember(roles, b.Role) {
+				continue
+			}
+			members := []string{}
+			for _, m := range b.Members {
+				if strings.EqualFold(m, member) {
+					continue
+				}
+				members = append(members, m)
+			}
+			if len(members) != len(b.Members) {
+				removed = append(removed, b.Role)
+			}
+			b.Members = members
+		}
+		if len(removed) == 0 {
+			return nil, nil
+		}
+		return policy, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if after != nil {
+		auditUndo(ctx, undoIAMPolicy, "projects/"+projectID, before, after)
+	}
+	return removed, nil
+}
+
+// defaultServiceAccount matches the Compute Engine and App Engine default service accounts.
+var defaultServiceAccount = regexp.MustCompile(`^serviceAccount:(?:\d+-compute@developer|[a-z0-9:.-]+@appspot)\.gserviceaccount\.com$`)
+
+// ReplaceDefaultServiceAccountEditor removes the default service accounts from the Editor role
+// and grants them the given roles instead. The replaced members are returned and the policy is
+// only updated if a default service account was found in the Editor binding.
+func (r *Resource) ReplaceDefaultServiceAccountEditor(ctx context.Context, projectID string, roles []string) ([]string, error) {
+	var replaced []string
+	before, after, err := r.updateProjectPolicy(ctx, projectID, func(policy *crm.Policy) (*crm.Policy, error) {
+		if replaced = replaceDefaultServiceAccountEditor(policy, roles); len(replaced) == 0 {
+			return nil, nil
+		}
+		return policy, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if after != nil {
+		auditUndo(ctx, undoIAMPolicy, "projects/"+projectID, before, after)
+	}
+	return replaced, nil
+}
+
+// PlanReplaceDefaultServiceAccountEditor returns the change ReplaceDefaultServiceAccountEditor
+// would make to the project's policy without setting it.
+func (r *Resource) PlanReplaceDefaultServiceAccountEditor(ctx context.Context, projectID string, roles []string) (*Change, error) {
+	before, err := r.crm.GetPolicyProject(ctx, projectID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get project policy")
+	}
+	after := copyPolicy(before)
+	replaceDefaultServiceAccountEditor(after, roles)
+	return PolicyChange("projects/"+projectID, before, after), nil
+}
+
+func replaceDefaultServiceAccountEditor(policy *crm.Policy, roles []string) []string {
+	replaced := []string{}
+	for _, b := range policy.Bindings {
+		if b.Role != "roles/editor" {
+			continue
+		}
+		members := []string{}
+		for _, member := range b.Members {
+			if defaultServiceAccount.MatchString(member) {
+				replaced = append(replaced, member)
+				continue
+			}
+			members = append(members, member)
+		}
+		b.Members = members
+	}
+	if len(replaced) == 0 {
+		return replaced
+	}
+	for _, role := range roles {
+		var binding *crm.Binding
+		for _, b := range policy.Bindings {
+			if b.Role == role && b.Condition == nil {
+				binding = b
+				break
+			}
+		}
+		if binding == nil {
+			binding = &crm.Binding{Role: role}
+			policy.Bindings = append(policy.Bindings, binding)
+		}
+		for _, member := range replaced {
+			if !containsMember(binding.Members, member) {
+				binding.Members = append(binding.Members, member)
+			}
+		}
+	}
+	return replaced
+}
+
+func containsMember(members []string, member string) bool {
+	for _, m := range members {
+		if strings.EqualFold(m, member) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProjectOwners returns the email addresses of the users granted the Owner role on the project.
+func (r *Resource) ProjectOwners(ctx context.Context, projectID string) ([]string, error) {
+	policy, err := r.crm.GetPolicyProject(ctx, projectID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get project policy")
+	}
+	owners := []string{}
+	for _, b := range policy.Bindings {
+		if b.Role != "roles/owner" {
+			continue
+		}
+		for _, member := range b.Members {
+			if strings.HasPrefix(member, "user:") {
+				owners = append(owners, strings.TrimPrefix(member, "user:"))
+			}
+		}
+	}
+	return owners, nil
+}
+
 // RemoveMembersFromBucket removes members from the bucket.
 func (r *Resource) RemoveMembersFromBucket(ctx context.Context, bucketName string, members []string) error {
 	p, err := r.storage.BucketPolicy(ctx, bucketName)
@@ -132,40 +459,150 @@ func (r *Resource) RemoveMembersFromBucket(ctx context.Context, bucketName strin
 	return r.storage.SetBucketPolicy(ctx, bucketName, p)
 }
 
-// EnableAuditLogs enable audit logs to all services and LogTypes.
-func (r *Resource) EnableAuditLogs(ctx context.Context, projectID string) (*crm.Policy, error) {
-	res, err := r.crm.GetPolicyProject(ctx, projectID)
+// RemoveEntitiesFromBucketACL removes the given entities from the bucket's ACL. Buckets using
+// uniform bucket-level access have no ACLs so nothing is removed.
+func (r *Resource) RemoveEntitiesFromBucketACL(ctx context.Context, bucketName string, entities []string) error {
+	attrs, err := r.storage.BucketAttrs(ctx, bucketName)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get project policy")
+		return err
 	}
-	isDefault := false
-	enableAll := &crm.AuditConfig{
-		AuditLogConfigs: []*crm.AuditLogConfig{
-			{LogType: "ADMIN_READ"},
-			{LogType: "DATA_READ"},
-			{LogType: "DATA_WRITE"},
-		},
-		Service: "allServices",
+	if attrs.UniformBucketLevelAccess.Enabled || attrs.BucketPolicyOnly.Enabled {
+		return nil
 	}
-	for _, conf := range res.AuditConfigs {
-		if conf.Service == "allServices" {
-			conf.AuditLogConfigs = enableAll.AuditLogConfigs
-			isDefault = true
+	for _, rule := range attrs.ACL {
+		for _, e := range entities {
+			if string(rule.Entity) != e {
+				continue
+			}
+			if err := r.storage.DeleteBucketACL(ctx, bucketName, rule.Entity); err != nil {
+				return err
+			}
 		}
 	}
-	if !isDefault {
-		res.AuditConfigs = append(res.AuditConfigs, enableAll)
+	return nil
+}
+
+// DenyVMExternalIPAccess applies the compute.vmExternalIpAccess constraint to the project so no
+// VM can be given an external IP.
+func (r *Resource) DenyVMExternalIPAccess(ctx context.Context, projectID string) error {
+	policy := &crm.OrgPolicy{
+		Constraint: "constraints/compute.vmExternalIpAccess",
+		ListPolicy: &crm.ListPolicy{AllValues: "DENY"},
+	}
+	if _, err := r.crm.SetOrgPolicyProject(ctx, projectID, policy); err != nil {
+		return fmt.Errorf("failed to set project org policy: %q", err)
 	}
+	return nil
+}
 
-	result, err := r.crm.SetPolicyProjectWithMask(ctx, projectID, res, "auditConfigs")
+// RestrictMemberDomains applies the iam.allowedPolicyMemberDomains constraint to the project so
+// only members of the given Google Workspace customer IDs can be granted roles.
+func (r *Resource) RestrictMemberDomains(ctx context.Context, projectID string, customerIDs []string) error {
+	policy := &crm.OrgPolicy{
+		Constraint: "constraints/iam.allowedPolicyMemberDomains",
+		ListPolicy: &crm.ListPolicy{AllowedValues: customerIDs},
+	}
+	if _, err := r.crm.SetOrgPolicyProject(ctx, projectID, policy); err != nil {
+		return fmt.Errorf("failed to set project org policy: %q", err)
+	}
+	return nil
+}
+
+// EnableAuditLogs enable audit logs to all services and LogTypes.
+func (r *Resource) EnableAuditLogs(ctx context.Context, projectID string) (*crm.Policy, error) {
+	var result *crm.Policy
+	_, _, err := updatePolicy(ctx, "projects/"+projectID, func() (*crm.Policy, error) {
+		return r.crm.GetPolicyProject(ctx, projectID)
+	}, func(res *crm.Policy) (*crm.Policy, error) {
+		isDefault := false
+		enableAll := &crm.AuditConfig{
+			AuditLogConfigs: []*crm.AuditLogConfig{
+				{LogType: "ADMIN_READ"},
+				{LogType: "DATA_READ"},
+				{LogType: "DATA_WRITE"},
+			},
+			Service: "allServices",
+		}
+		for _, conf := range res.AuditConfigs {
+			if conf.Service == "allServices" {
+				conf.AuditLogConfigs = enableAll.AuditLogConfigs
+				isDefault = true
+			}
+		}
+		if !isDefault {
+			res.AuditConfigs = append(res.AuditConfigs, enableAll)
+		}
+		return res, nil
+	}, func(policy *crm.Policy) (err error) {
+		result, err = r.crm.SetPolicyProjectWithMask(ctx, projectID, policy, "auditConfigs")
+		return err
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to update project policy")
+		return nil, err
 	}
 	return result, nil
 }
 
-// keepUsersFromPolicy keeps users if they match the given domain.
-func (r *Resource) keepUsersFromPolicy(policy *crm.Policy, allowedDomains []string) ([]string, *crm.Policy, error) {
+// EnableServiceAuditLogs enables DATA_READ and DATA_WRITE audit logs for the given services.
+//
+// Existing audit configs are merged rather than replaced so other log types and exempted members
+// are kept. The services whose audit configs changed are returned.
+func (r *Resource) EnableServiceAuditLogs(ctx context.Context, projectID string, services []string) ([]string, error) {
+	var changed []string
+	_, _, err := updatePolicy(ctx, "projects/"+projectID, func() (*crm.Policy, error) {
+		return r.crm.GetPolicyProject(ctx, projectID)
+	}, func(policy *crm.Policy) (*crm.Policy, error) {
+		changed = []string{}
+		for _, service := range services {
+			var conf *crm.AuditConfig
+			for _, c := range policy.AuditConfigs {
+				if c.Service == service {
+					conf = c
+					break
+				}
+			}
+			if conf == nil {
+				conf = &crm.AuditConfig{Service: service}
+				policy.AuditConfigs = append(policy.AuditConfigs, conf)
+			}
+			added := false
+			for _, logType := range []string{"DATA_READ", "DATA_WRITE"} {
+				if hasLogType(conf, logType) {
+					continue
+				}
+				conf.AuditLogConfigs = append(conf.AuditLogConfigs, &crm.AuditLogConfig{LogType: logType})
+				added = true
+			}
+			if added {
+				changed = append(changed, service)
+			}
+		}
+		if len(changed) == 0 {
+			return nil, nil
+		}
+		return policy, nil
+	}, func(policy *crm.Policy) error {
+		_, err := r.crm.SetPolicyProjectWithMask(ctx, projectID, policy, "auditConfigs")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
+
+func hasLogType(conf *crm.AuditConfig, logType string) bool {
+	for _, c := range conf.AuditLogConfigs {
+		if c.LogType == logType {
+			return true
+		}
+	}
+	return false
+}
+
+// keepUsersFromPolicy keeps users and domains if they match the given domains. Bindings are changed
+// in place so their conditions and the policy's etag are kept.
+func (r *Resource) keepUsersFromPolicy(ctx context.Context, policy *crm.Policy, allowedDomains []string, opts KeepOptions) ([]string, *crm.Policy, error) {
 	// Throw an error if no allowed domains are passed. Otherwise all users would be removed.
 	if len(allowedDomains) == 0 {
 		return nil, nil, errors.New("must provide at least one domain to allow")
@@ -177,26 +614,55 @@ func (r *Resource) keepUsersFromPolicy(policy *crm.Policy, allowedDomains []stri
 	}
 	removed := []string{}
 	for _, b := range policy.Bindings {
+		if opts.UnconditionalOnly && b.Condition != nil {
+			continue
+		}
 		members := []string{}
 		for _, member := range b.Members {
-			isUser := strings.HasPrefix(member, "user:")
-			found := false
-			if allowedRegExp.MatchString(member) {
-				found = true
-			}
-			if !isUser || found {
-				members = append(members, member)
-				continue
+			keep := true
+			switch {
+			case strings.HasPrefix(member, "user:"):
+				keep = allowedRegExp.MatchString(member)
+				if !keep && opts.InOrganization != nil {
+					if keep, err = opts.InOrganization(ctx, strings.TrimPrefix(member, "user:")); err != nil {
+						return nil, nil, err
+					}
+				}
+			case strings.HasPrefix(member, "domain:"):
+				keep = allowedDomain(strings.TrimPrefix(member, "domain:"), allowedDomains)
+			default:
+				for _, filter := range opts.Filters {
+					remove, err := filter(ctx, member)
+					if err != nil {
+						return nil, nil, err
+					}
+					if remove {
+						keep = false
+						break
+					}
+				}
 			}
-			if isUser && !found {
+			if !keep {
 				removed = append(removed, member)
+				continue
 			}
+			members = append(members, member)
 		}
 		b.Members = members
 	}
 	return removed, policy, nil
 }
 
+// allowedDomain returns whether the domain is exactly one of the allowed domains.
+func allowedDomain(domain string, allowedDomains []string) bool {
+	for _, d := range allowedDomains {
+		if strings.EqualFold(domain, d) {
+			return true
+		}
+	}
+	return false
+}
+
 // removeUsersFromPolicy removes a slice of users from a policy
 func (r *Resource) removeUsersFromPolicy(policy *crm.Policy, users []string) *crm.Policy {
 	for _, b := range policy.Bindings {
@@ -232,10 +698,13 @@ func (r *Resource) Organization(ctx context.Context, orgID string) (*crm.Organiz
 
 // EnableBucketOnlyPolicy enable bucket only policy for the given bucket
 func (r *Resource) EnableBucketOnlyPolicy(ctx context.Context, bucketName string) error {
-	return r.storage.EnableBucketOnlyPolicy(ctx, bucketName)
+	return r.storage.SetBucketPolicyOnly(ctx, bucketName, true)
 }
 
 func (r *Resource) getProjectAncestryPath(ctx context.Context, projectID string) (string, error) {
+	if path, ok := r.cachedAncestry(projectID, time.Now()); ok {
+		return path, nil
+	}
 	resp, err := r.crm.GetAncestry(ctx, projectID)
 	if err != nil {
 		return "", err
@@ -244,18 +713,52 @@ func (r *Resource) getProjectAncestryPath(ctx context.Context, projectID string)
 	for i := len(resp.Ancestor) - 1; i >= 0; i-- {
 		s = append(s, resp.Ancestor[i].ResourceId.Type+"s/"+resp.Ancestor[i].ResourceId.Id)
 	}
-	return strings.Join(s, "/"), nil
+	path := strings.Join(s, "/")
+	r.cacheAncestry(projectID, path, time.Now())
+	return path, nil
+}
+
+// cachedAncestry returns the cached ancestry path of the project ID or folder name, if it hasn't
+// expired by now.
+func (r *Resource) cachedAncestry(key string, now time.Time) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.ancestry[key]
+	if !ok || !now.Before(c.expires) {
+		return "", false
+	}
+	return c.path, true
+}
+
+func (r *Resource) cacheAncestry(key, path string, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ancestry[key] = cachedAncestry{path: path, expires: now.Add(ancestryTTL)}
 }
 
-func (r *Resource) ancestryMatches(patterns []string, ancestorPath string) (bool, error) {
+// inFolderPath returns whether the ancestry path goes through one of the folder IDs.
+func inFolderPath(path string, folderIDs []string) bool {
+	s := strings.Split(path, "/")
+	for i := 0; i+1 < len(s); i++ {
+		if s[i] != "folders" {
+			continue
+		}
+		for _, id := range folderIDs {
+			if s[i+1] == strings.TrimPrefix(id, "folders/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *Resource) ancestryMatches(ctx context.Context, patterns []string, ancestorPath string) (bool, error) {
 	for _, pattern := range patterns {
 		match, err := regexp.MatchString("^"+strings.Replace(pattern, "*", ".*", -1), ancestorPath)
 		if err != nil {
 			return false, errors.Wrapf(err, "failed to parse: %s", pattern)
 		}
-		log.Printf("pattern: %q", pattern)
-		log.Printf("comparing: %q", ancestorPath)
-		log.Printf("match: %t", match)
+		logging.FromContext(ctx).Debug("pattern %q matching %q: %t", pattern, ancestorPath, match)
 		if match {
 			return true, nil
 		}
@@ -263,20 +766,92 @@ func (r *Resource) ancestryMatches(patterns []string, ancestorPath string) (bool
 	return false, nil
 }
 
+// InFolders checks if the project is a descendant of one of the folder IDs.
+func (r *Resource) InFolders(ctx context.Context, projectID string, folderIDs []string) (bool, error) {
+	path, err := r.getProjectAncestryPath(ctx, projectID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get project ancestry")
+	}
+	return inFolderPath(path, folderIDs), nil
+}
+
+// CheckMatchesResource checks if a folder or organization, such as "folders/123", is included in
+// the target and not included in ignore. Patterns match the resource as they match its descendants,
+// so "organizations/456/*" includes the organization itself.
+func (r *Resource) CheckMatchesResource(ctx context.Context, name string, target, ignore []string) (bool, error) {
+	return r.CheckMatchesResourceFolders(ctx, name, target, nil, ignore)
+}
+
+// CheckMatchesResourceFolders checks if a folder or organization is included in the target or is
+// one of the folder IDs or a descendant of them, and is not included in ignore.
+func (r *Resource) CheckMatchesResourceFolders(ctx context.Context, name string, target, folderIDs, ignore []string) (bool, error) {
+	ancestorPath, err := r.getResourceAncestryPath(ctx, name)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get resource ancestry path")
+	}
+	ancestorPath += "/"
+	matchesIgnore, err := r.ancestryMatches(ctx, ignore, ancestorPath)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to process ignore list")
+	}
+	if matchesIgnore {
+		return false, nil
+	}
+	if inFolderPath(ancestorPath, folderIDs) {
+		return true, nil
+	}
+	matchesTarget, err := r.ancestryMatches(ctx, target, ancestorPath)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to process target list")
+	}
+	return matchesTarget, nil
+}
+
+// getResourceAncestryPath returns the ancestry path of a folder or organization by walking up the folder parents.
+func (r *Resource) getResourceAncestryPath(ctx context.Context, name string) (string, error) {
+	if !strings.HasPrefix(name, "folders/") {
+		return name, nil
+	}
+	if path, ok := r.cachedAncestry(name, time.Now()); ok {
+		return path, nil
+	}
+	f, err := r.crm.GetFolder(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	// Folders nested in the same parent share the ancestry of their parent.
+	parent, err := r.getResourceAncestryPath(ctx, f.Parent)
+	if err != nil {
+		return "", err
+	}
+	path := parent + "/" + name
+	r.cacheAncestry(name, path, time.Now())
+	return path, nil
+}
+
 // CheckMatches checks if a project is included in the target and not included in ignore.
 func (r *Resource) CheckMatches(ctx context.Context, projectID string, target, ignore []string) (bool, error) {
+	return r.CheckMatchesFolders(ctx, projectID, target, nil, ignore)
+}
+
+// CheckMatchesFolders checks if a project is included in the target or is a descendant of one of
+// the folder IDs, including through nested folders, and is not included in ignore.
+func (r *Resource) CheckMatchesFolders(ctx context.Context, projectID string, target, folderIDs, ignore []string) (bool, error) {
 	ancestorPath, err := r.getProjectAncestryPath(ctx, projectID)
 	if err != nil {
 		return false, errors.Wrap(err, "failed to get project ancestry path")
 	}
-	matchesIgnore, err := r.ancestryMatches(ignore, ancestorPath)
+	matchesIgnore, err := r.ancestryMatches(ctx, ignore, ancestorPath)
 	if err != nil {
 		return false, errors.Wrap(err, "failed to process ignore list")
 	}
 	if matchesIgnore {
 		return false, nil
 	}
-	matchesTarget, err := r.ancestryMatches(target, ancestorPath)
+	if inFolderPath(ancestorPath, folderIDs) {
+		return true, nil
+	}
+	matchesTarget, err := r.ancestryMatches(ctx, target, ancestorPath)
 	if err != nil {
 		return false, errors.Wrap(err, "failed to process target list")
 	}
@@ -16,12 +16,16 @@ package services
 
 import (
 	"context"
+	"net/http"
 	"testing"
+	"time"
 
 	"cloud.google.com/go/iam"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
 	crm "google.golang.org/api/cloudresourcemanager/v1"
+	crmv2 "google.golang.org/api/cloudresourcemanager/v2"
+	"google.golang.org/api/googleapi"
 )
 
 // TestRemoveUsersProject tests the removal of members from a policy.
@@ -70,6 +74,61 @@ func TestRemoveUsersProject(t *testing.T) {
 	}
 }
 
+// TestRemoveUsersProjectConflict tests a policy is read and changed again when setting it conflicts.
+func TestRemoveUsersProjectConflict(t *testing.T) {
+	errConflict := &googleapi.Error{Code: http.StatusConflict}
+	tests := []struct {
+		name     string
+		errs     []error
+		gets     int
+		expected []*crm.Binding
+		wantErr  bool
+	}{
+		{
+			name:     "conflicts once",
+			errs:     []error{errConflict},
+			gets:     2,
+			expected: createBindings([]string{"user:bob@gmail.com"}),
+		},
+		{
+			name:    "keeps conflicting",
+			errs:    []error{errConflict, errConflict, errConflict},
+			gets:    policyAttempts,
+			wantErr: true,
+		},
+		{
+			name:    "not a conflict",
+			errs:    []error{&googleapi.Error{Code: http.StatusForbidden}},
+			gets:    1,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crmStub := &stubs.ResourceManagerStub{SetPolicyErrs: tt.errs}
+			crmStub.GetPolicyResponse = &crm.Policy{Bindings: createBindings([]string{"user:bob@gmail.com", "user:tim@thegmail.com"})}
+			r := NewResource(crmStub, &stubs.StorageStub{})
+			err := r.RemoveUsersProject(context.Background(), "test-project", []string{"user:tim@thegmail.com"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%v failed, err: %+v", tt.name, err)
+			}
+			if crmStub.GetPolicyCalls != tt.gets {
+				t.Errorf("%v failed, got %d reads of the policy want %d", tt.name, crmStub.GetPolicyCalls, tt.gets)
+			}
+			if tt.wantErr {
+				if crmStub.SavedSetPolicy != nil {
+					t.Errorf("%v failed, policy set: %+v", tt.name, crmStub.SavedSetPolicy)
+				}
+				return
+			}
+			if diff := cmp.Diff(crmStub.SavedSetPolicy.Bindings, tt.expected); diff != "" {
+				t.Errorf("%v failed, difference: %v", tt.name, diff)
+			}
+		})
+	}
+}
+
 func createBindings(members []string) []*crm.Binding {
 	return []*crm.Binding{
 		{
@@ -155,6 +214,13 @@ func TestRemoveNonOrganizationMembers(t *testing.T) {
 			expected:       createBindings([]string{"user:ddgo@cloudorg.com", "user:mans@cloudorg.com"}),
 			shouldFail:     false,
 		},
+		{
+			name:           "remove domain members",
+			allowedDomains: []string{"cloudorg.com"},
+			input:          createBindings([]string{"domain:cloudorg.com", "domain:gmail.com", "domain:sub.cloudorg.com", "group:admins@gmail.com"}),
+			expected:       createBindings([]string{"domain:cloudorg.com", "group:admins@gmail.com"}),
+			shouldFail:     false,
+		},
 		{
 			name:           "allowed domains cannot be empty",
 			allowedDomains: []string{},
@@ -172,7 +238,7 @@ func TestRemoveNonOrganizationMembers(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			resource, crmStub := setupOrgTest(tt.input)
-			if _, err := resource.OrganizationOnlyKeepUsersFromDomains(ctx, orgID, tt.allowedDomains); err != nil && !tt.shouldFail {
+			if _, err := resource.OrganizationOnlyKeepUsersFromDomains(ctx, orgID, tt.allowedDomains, KeepOptions{}); err != nil && !tt.shouldFail {
 				t.Errorf("%v failed, err: %+v", tt.name, err)
 			}
 			if !tt.shouldFail {
@@ -184,6 +250,62 @@ func TestRemoveNonOrganizationMembers(t *testing.T) {
 	}
 }
 
+func TestKeepUsersConditionalBindings(t *testing.T) {
+	ctx := context.Background()
+	condition := &crm.Expr{Title: "expires", Expression: `request.time < timestamp("2020-01-01T00:00:00Z")`}
+	input := func() *crm.Policy {
+		return &crm.Policy{
+			Etag:    "BwWWja0YfJA=",
+			Version: 3,
+			Bindings: []*crm.Binding{
+				{Role: "roles/editor", Members: []string{"user:ddgo@cloudorg.com", "user:tim@thegmail.com"}},
+				{Role: "roles/viewer", Members: []string{"user:ddgo@cloudorg.com", "user:tim@thegmail.com"}, Condition: condition},
+			},
+		}
+	}
+	tests := []struct {
+		name              string
+		unconditionalOnly bool
+		expected          *crm.Policy
+	}{
+		{
+			name: "conditions are kept",
+			expected: &crm.Policy{
+				Etag:    "BwWWja0YfJA=",
+				Version: 3,
+				Bindings: []*crm.Binding{
+					{Role: "roles/editor", Members: []string{"user:ddgo@cloudorg.com"}},
+					{Role: "roles/viewer", Members: []string{"user:ddgo@cloudorg.com"}, Condition: condition},
+				},
+			},
+		},
+		{
+			name:              "only unconditional bindings",
+			unconditionalOnly: true,
+			expected: &crm.Policy{
+				Etag:    "BwWWja0YfJA=",
+				Version: 3,
+				Bindings: []*crm.Binding{
+					{Role: "roles/editor", Members: []string{"user:ddgo@cloudorg.com"}},
+					{Role: "roles/viewer", Members: []string{"user:ddgo@cloudorg.com", "user:tim@thegmail.com"}, Condition: condition},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crmStub := &stubs.ResourceManagerStub{GetPolicyResponse: input()}
+			resource := NewResource(crmStub, &stubs.StorageStub{})
+			if _, err := resource.ProjectOnlyKeepUsersFromDomains(ctx, "project-id", []string{"cloudorg.com"}, KeepOptions{UnconditionalOnly: tt.unconditionalOnly}); err != nil {
+				t.Fatalf("%v failed, err: %+v", tt.name, err)
+			}
+			if diff := cmp.Diff(tt.expected, crmStub.SavedSetPolicy); diff != "" {
+				t.Errorf("%v failed, difference: %v", tt.name, diff)
+			}
+		})
+	}
+}
+
 func setupOrgTest(binding []*crm.Binding) (*Resource, *stubs.ResourceManagerStub) {
 	storageStub := &stubs.StorageStub{}
 	crmStub := &stubs.ResourceManagerStub{}
@@ -244,6 +366,120 @@ func TestEnableAuditLogsOnProject(t *testing.T) {
 	}
 }
 
+// TestEnableServiceAuditLogs tests merging data access logs into a project's audit configs.
+func TestEnableServiceAuditLogs(t *testing.T) {
+	tests := []struct {
+		name            string
+		existingConfig  *crm.AuditConfig
+		expectedConfig  []*crm.AuditConfig
+		expectedChanged []string
+	}{
+		{
+			name:           "add config for service",
+			existingConfig: nil,
+			expectedConfig: []*crm.AuditConfig{
+				{AuditLogConfigs: []*crm.AuditLogConfig{{LogType: "DATA_READ"}, {LogType: "DATA_WRITE"}}, Service: "storage.googleapis.com"},
+			},
+			expectedChanged: []string{"storage.googleapis.com"},
+		},
+		{
+			name: "merge with existing config",
+			existingConfig: &crm.AuditConfig{
+				AuditLogConfigs: []*crm.AuditLogConfig{{LogType: "DATA_READ", ExemptedMembers: []string{"user:bob@example.com"}}}, Service: "storage.googleapis.com",
+			},
+			expectedConfig: []*crm.AuditConfig{
+				{AuditLogConfigs: []*crm.AuditLogConfig{{LogType: "DATA_READ", ExemptedMembers: []string{"user:bob@example.com"}}, {LogType: "DATA_WRITE"}}, Service: "storage.googleapis.com"},
+			},
+			expectedChanged: []string{"storage.googleapis.com"},
+		},
+		{
+			name: "already enabled",
+			existingConfig: &crm.AuditConfig{
+				AuditLogConfigs: []*crm.AuditLogConfig{{LogType: "DATA_WRITE"}, {LogType: "DATA_READ"}}, Service: "storage.googleapis.com",
+			},
+			expectedChanged: []string{},
+		},
+	}
+	for _, tt := range tests {
+		ctx := context.Background()
+		crmStub := setupResourceManager(tt.existingConfig)
+		r := NewResource(crmStub, nil)
+		t.Run(tt.name, func(t *testing.T) {
+			changed, err := r.EnableServiceAuditLogs(ctx, "test-project-sra", []string{"storage.googleapis.com"})
+			if err != nil {
+				t.Fatalf("%s failed exp:%v got:%q", tt.name, nil, err)
+			}
+			if diff := cmp.Diff(tt.expectedChanged, changed); diff != "" {
+				t.Errorf("%s failed, difference in changed services: %+v", tt.name, diff)
+			}
+			var got []*crm.AuditConfig
+			if crmStub.SavedSetPolicy != nil {
+				got = crmStub.SavedSetPolicy.AuditConfigs
+			}
+			if diff := cmp.Diff(tt.expectedConfig, got); diff != "" {
+				t.Errorf("%s failed, difference in audit configs: %+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestReplaceDefaultServiceAccountEditor(t *testing.T) {
+	const (
+		compute = "serviceAccount:123456789-compute@developer.gserviceaccount.com"
+		appspot = "serviceAccount:test-project-sra@appspot.gserviceaccount.com"
+		custom  = "serviceAccount:custom@test-project-sra.iam.gserviceaccount.com"
+	)
+	tests := []struct {
+		name             string
+		existingBindings []*crm.Binding
+		expectedBindings []*crm.Binding
+		expectedReplaced []string
+	}{
+		{
+			name: "replace default service accounts",
+			existingBindings: []*crm.Binding{
+				{Role: "roles/editor", Members: []string{compute, appspot, custom}},
+				{Role: "roles/logging.logWriter", Members: []string{custom}},
+			},
+			expectedBindings: []*crm.Binding{
+				{Role: "roles/editor", Members: []string{custom}},
+				{Role: "roles/logging.logWriter", Members: []string{custom, compute, appspot}},
+				{Role: "roles/monitoring.metricWriter", Members: []string{compute, appspot}},
+			},
+			expectedReplaced: []string{compute, appspot},
+		},
+		{
+			name: "no default service accounts",
+			existingBindings: []*crm.Binding{
+				{Role: "roles/editor", Members: []string{custom}},
+				{Role: "roles/viewer", Members: []string{compute}},
+			},
+			expectedReplaced: []string{},
+		},
+	}
+	for _, tt := range tests {
+		ctx := context.Background()
+		crmStub := &stubs.ResourceManagerStub{GetPolicyResponse: &crm.Policy{Bindings: tt.existingBindings}}
+		r := NewResource(crmStub, nil)
+		t.Run(tt.name, func(t *testing.T) {
+			replaced, err := r.ReplaceDefaultServiceAccountEditor(ctx, "test-project-sra", []string{"roles/logging.logWriter", "roles/monitoring.metricWriter"})
+			if err != nil {
+				t.Fatalf("%s failed exp:%v got:%q", tt.name, nil, err)
+			}
+			if diff := cmp.Diff(tt.expectedReplaced, replaced); diff != "" {
+				t.Errorf("%s failed, difference in replaced members: %+v", tt.name, diff)
+			}
+			var got []*crm.Binding
+			if crmStub.SavedSetPolicy != nil {
+				got = crmStub.SavedSetPolicy.Bindings
+			}
+			if diff := cmp.Diff(tt.expectedBindings, got); diff != "" {
+				t.Errorf("%s failed, difference in bindings: %+v", tt.name, diff)
+			}
+		})
+	}
+}
+
 func setupResourceManager(auditConfig *crm.AuditConfig) *stubs.ResourceManagerStub {
 	var configs []*crm.AuditConfig
 	if auditConfig != nil {
@@ -297,3 +533,112 @@ func TestCheckMatches(t *testing.T) {
 	}
 
 }
+
+func TestCheckMatchesResource(t *testing.T) {
+	crmStub := &stubs.ResourceManagerStub{StubbedFolders: map[string]*crmv2.Folder{
+		"folders/789": {Name: "folders/789", Parent: "folders/123"},
+		"folders/123": {Name: "folders/123", Parent: "organizations/456"},
+	}}
+	r := NewResource(crmStub, &stubs.StorageStub{})
+	ctx := context.Background()
+	tests := []struct {
+		name      string
+		resource  string
+		target    string
+		ignore    string
+		mustMatch bool
+	}{
+		{name: "org in target", resource: "organizations/456", mustMatch: true, target: "organizations/456/*"},
+		{name: "org in ignore", resource: "organizations/456", mustMatch: false, target: "organizations/456/*", ignore: "organizations/456/*"},
+		{name: "folder in target", resource: "folders/789", mustMatch: true, target: "organizations/456/folders/123/*"},
+		{name: "folder itself in target", resource: "folders/123", mustMatch: true, target: "organizations/456/folders/123/*"},
+		{name: "folder in ignore", resource: "folders/789", mustMatch: false, target: "organizations/456/*", ignore: "organizations/456/folders/123/folders/789/*"},
+		{name: "folder not in target", resource: "folders/789", mustMatch: false, target: "organizations/456/folders/12/*"},
+		{name: "parent not within a project target", resource: "folders/123", mustMatch: false, target: "organizations/456/folders/123/projects/test-project"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ignore []string
+			if tt.ignore != "" {
+				ignore = []string{tt.ignore}
+			}
+			matches, err := r.CheckMatchesResource(ctx, tt.resource, []string{tt.target}, ignore)
+			if err != nil {
+				t.Errorf("%s failed, err: %+v", tt.name, err)
+			}
+			if matches != tt.mustMatch {
+				t.Errorf("%s failed: got match %t want %t", tt.name, matches, tt.mustMatch)
+			}
+		})
+	}
+}
+
+func TestCheckMatchesFolders(t *testing.T) {
+	const projectID = "test-project"
+	crmStub := &stubs.ResourceManagerStub{StubbedFolders: map[string]*crmv2.Folder{
+		"folders/789": {Name: "folders/789", Parent: "folders/123"},
+		"folders/123": {Name: "folders/123", Parent: "organizations/456"},
+	}}
+	crmStub.GetAncestryResponse = CreateAncestors([]string{"project/" + projectID, "folder/789", "folder/123", "organization/456"})
+	r := NewResource(crmStub, &stubs.StorageStub{})
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name      string
+		folderIDs []string
+		target    []string
+		ignore    []string
+		mustMatch bool
+	}{
+		{name: "parent folder", folderIDs: []string{"789"}, mustMatch: true},
+		{name: "nested folder", folderIDs: []string{"123"}, mustMatch: true},
+		{name: "folder name", folderIDs: []string{"folders/123"}, mustMatch: true},
+		{name: "other folder", folderIDs: []string{"12"}},
+		{name: "folder or target", folderIDs: []string{"12"}, target: []string{"organizations/456/*"}, mustMatch: true},
+		{name: "folder in ignore", folderIDs: []string{"123"}, ignore: []string{"organizations/456/folders/123/folders/789/*"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := r.CheckMatchesFolders(ctx, projectID, tt.target, tt.folderIDs, tt.ignore)
+			if err != nil {
+				t.Fatalf("%s failed, err: %+v", tt.name, err)
+			}
+			if matches != tt.mustMatch {
+				t.Errorf("%s failed: got match %t want %t", tt.name, matches, tt.mustMatch)
+			}
+			matches, err = r.CheckMatchesResourceFolders(ctx, "folders/789", tt.target, tt.folderIDs, tt.ignore)
+			if err != nil {
+				t.Fatalf("%s failed, err: %+v", tt.name, err)
+			}
+			if matches != tt.mustMatch {
+				t.Errorf("%s failed: got folder match %t want %t", tt.name, matches, tt.mustMatch)
+			}
+		})
+	}
+}
+
+func TestAncestryCache(t *testing.T) {
+	const projectID = "test-project"
+	crmStub := &stubs.ResourceManagerStub{StubbedFolders: map[string]*crmv2.Folder{
+		"folders/123": {Name: "folders/123", Parent: "organizations/456"},
+	}}
+	crmStub.GetAncestryResponse = CreateAncestors([]string{"project/" + projectID, "folder/123", "organization/456"})
+	r := NewResource(crmStub, &stubs.StorageStub{})
+	ctx := context.Background()
+	if _, err := r.InFolders(ctx, projectID, []string{"123"}); err != nil {
+		t.Fatalf("failed to get ancestry: %q", err)
+	}
+	if _, err := r.CheckMatchesResource(ctx, "folders/123", []string{"organizations/456/*"}, nil); err != nil {
+		t.Fatalf("failed to get ancestry: %q", err)
+	}
+	// Cached ancestry is used without looking it up again.
+	crmStub.GetAncestryResponse = CreateAncestors([]string{"project/" + projectID, "organization/456"})
+	crmStub.StubbedFolders = nil
+	if ok, err := r.InFolders(ctx, projectID, []string{"123"}); err != nil || !ok {
+		t.Errorf("cached project ancestry not used: got %t, %v", ok, err)
+	}
+	if ok, err := r.CheckMatchesResource(ctx, "folders/123", []string{"organizations/456/*"}, nil); err != nil || !ok {
+		t.Errorf("cached folder ancestry not used: got %t, %v", ok, err)
+	}
+	if _, ok := r.cachedAncestry(projectID, time.Now().Add(ancestryTTL)); ok {
+		t.Errorf("ancestry cached past its TTL")
+	}
+}
@@ -0,0 +1,134 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
+)
+
+// Reused keeps a service and its clients across the invocations of a function, so they're created
+// once per instance instead of once per invocation. The service is health checked at most once per
+// interval and created again if its check fails.
+type Reused struct {
+	name     string
+	create   func(context.Context) (interface{}, error)
+	check    func(context.Context, interface{}) error
+	interval time.Duration
+	now      func() time.Time
+	mu       sync.Mutex
+	value    interface{}
+	checked  time.Time
+}
+
+// NewReused returns a service created on first use by create and checked by check, if set. The
+// service outlives the invocation creating it, so create shouldn't keep the context.
+func NewReused(name string, create func(context.Context) (interface{}, error), check func(context.Context, interface{}) error, interval time.Duration) *Reused {
+	return &Reused{
+		name:     name,
+		create:   create,
+		check:    check,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// Get returns the service, creating it if it wasn't yet or if its health check failed. If it
+// can't be created again the previous service is returned along with the error, so callers may
+// carry on with it.
+func (r *Reused) Get(ctx context.Context) (interface{}, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	if r.value != nil {
+		if r.check == nil || now.Sub(r.checked) < r.interval {
+			return r.value, nil
+		}
+		err := r.check(ctx, r.value)
+		if err == nil {
+			r.checked = now
+			return r.value, nil
+		}
+		logging.FromContext(ctx).Warning("%s failed its health check, creating it again: %q", r.name, err)
+	}
+	v, err := r.create(ctx)
+	if err != nil {
+		return r.value, err
+	}
+	logging.FromContext(ctx).Info("created %s in %s", r.name, r.now().Sub(now))
+	r.value, r.checked = v, now
+	return v, nil
+}
+
+// Configured keeps the service set up by a configuration across invocations, like Reused, and
+// creates it again once the configuration changes.
+type Configured struct {
+	name     string
+	create   func(context.Context, interface{}) (interface{}, error)
+	check    func(context.Context, interface{}) error
+	interval time.Duration
+	mu       sync.Mutex
+	conf     interface{}
+	reused   *Reused
+}
+
+// NewConfigured returns a service created by create for the configuration it's used with and
+// checked by check, if set.
+func NewConfigured(name string, create func(context.Context, interface{}) (interface{}, error), check func(context.Context, interface{}) error, interval time.Duration) *Configured {
+	return &Configured{name: name, create: create, check: check, interval: interval}
+}
+
+// For returns the service kept for the configuration. The service of the previous configuration
+// is dropped if it's a different one.
+func (c *Configured) For(conf interface{}) *Reused {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.reused == nil || !reflect.DeepEqual(c.conf, conf) {
+		c.conf = conf
+		c.reused = NewReused(c.name, func(ctx context.Context) (interface{}, error) {
+			return c.create(ctx, conf)
+		}, c.check, c.interval)
+	}
+	return c.reused
+}
+
+// Emails keeps the email service of each SendGrid API key across invocations, so their clients
+// are created once per instance.
+type Emails struct {
+	create   func(apiKey string) *Email
+	mu       sync.Mutex
+	services map[string]*Email
+}
+
+// NewEmails returns the email services created by create for each API key.
+func NewEmails(create func(apiKey string) *Email) *Emails {
+	return &Emails{create: create, services: map[string]*Email{}}
+}
+
+// Get returns the email service sending with the API key, creating it on first use.
+func (e *Emails) Get(apiKey string) *Email {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	s, ok := e.services[apiKey]
+	if !ok {
+		s = e.create(apiKey)
+		e.services[apiKey] = s
+	}
+	return s
+}
@@ -0,0 +1,127 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReused(t *testing.T) {
+	unhealthy := errors.New("unhealthy")
+	for _, tt := range []struct {
+		name      string
+		check     error
+		createErr error
+		elapsed   time.Duration
+		want      int
+		wantErr   bool
+	}{
+		{name: "reused", elapsed: time.Second, want: 1},
+		{name: "healthy", elapsed: time.Hour, want: 1},
+		{name: "unhealthy", check: unhealthy, elapsed: time.Hour, want: 2},
+		{name: "unhealthy not checked yet", check: unhealthy, elapsed: time.Second, want: 1},
+		{name: "failed to create again", check: unhealthy, createErr: errors.New("failed"), elapsed: time.Hour, want: 1, wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			created := 0
+			now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+			r := NewReused("test service", func(context.Context) (interface{}, error) {
+				if created > 0 && tt.createErr != nil {
+					return nil, tt.createErr
+				}
+				created++
+				return created, nil
+			}, func(context.Context, interface{}) error {
+				return tt.check
+			}, time.Minute)
+			r.now = func() time.Time { return now }
+			if v, err := r.Get(ctx); err != nil || v != 1 {
+				t.Fatalf("%s failed: got %v, %v", tt.name, v, err)
+			}
+			now = now.Add(tt.elapsed)
+			v, err := r.Get(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("%s failed: got error %v", tt.name, err)
+			}
+			if v != tt.want {
+				t.Errorf("%s failed: got service %v want %d", tt.name, v, tt.want)
+			}
+		})
+	}
+}
+
+func TestReusedCreateFailed(t *testing.T) {
+	calls := 0
+	r := NewReused("test service", func(context.Context) (interface{}, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("failed")
+		}
+		return "service", nil
+	}, nil, time.Minute)
+	if _, err := r.Get(context.Background()); err == nil {
+		t.Fatalf("expected error creating service")
+	}
+	if v, err := r.Get(context.Background()); err != nil || v != "service" {
+		t.Errorf("failed to create service again: got %v, %v", v, err)
+	}
+}
+
+func TestConfigured(t *testing.T) {
+	ctx := context.Background()
+	created := map[string]int{}
+	c := NewConfigured("test service", func(_ context.Context, conf interface{}) (interface{}, error) {
+		created[conf.(TicketConfig).Jira.URL]++
+		return conf, nil
+	}, nil, time.Minute)
+	first := TicketConfig{}
+	first.Jira.URL = "https://first.example.com"
+	second := TicketConfig{}
+	second.Jira.URL = "https://second.example.com"
+	for _, conf := range []TicketConfig{first, first, second, second, first} {
+		v, err := c.For(conf).Get(ctx)
+		if err != nil {
+			t.Fatalf("failed to get service: %q", err)
+		}
+		if v.(TicketConfig).Jira.URL != conf.Jira.URL {
+			t.Errorf("got service of %q for %q", v.(TicketConfig).Jira.URL, conf.Jira.URL)
+		}
+	}
+	if created[first.Jira.URL] != 2 || created[second.Jira.URL] != 1 {
+		t.Errorf("got %v services created, want one each time the configuration changed", created)
+	}
+}
+
+func TestEmails(t *testing.T) {
+	created := map[string]int{}
+	emails := NewEmails(func(apiKey string) *Email {
+		created[apiKey]++
+		return InitEmail(apiKey)
+	})
+	first := emails.Get("key-1")
+	if again := emails.Get("key-1"); again != first {
+		t.Errorf("email service of key-1 not reused")
+	}
+	if other := emails.Get("key-2"); other == first {
+		t.Errorf("email service of key-1 reused for key-2")
+	}
+	if created["key-1"] != 1 || created["key-2"] != 1 {
+		t.Errorf("got %v email services created, want one per key", created)
+	}
+}
@@ -0,0 +1,60 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// RotationHookClient contains minimum interface required by the rotation hook service.
+type RotationHookClient interface {
+	Call(context.Context, []byte) ([]byte, error)
+}
+
+// rotationRequest is posted to the hook to request a new value for the secret.
+type rotationRequest struct {
+	// Secret is the secret's resource name, such as "projects/<project>/secrets/<secret>".
+	Secret string `json:"secret"`
+}
+
+// RotationHook service generates new secret values.
+type RotationHook struct {
+	client RotationHookClient
+}
+
+// NewRotationHook returns a rotation hook service.
+func NewRotationHook(client RotationHookClient) *RotationHook {
+	return &RotationHook{client: client}
+}
+
+// Generate calls the hook for the secret and returns the new value from the response body.
+func (r *RotationHook) Generate(ctx context.Context, secret string) ([]byte, error) {
+	b, err := json.Marshal(&rotationRequest{Secret: secret})
+	if err != nil {
+		return nil, err
+	}
+	value, err := r.client.Call(ctx, b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to call rotation hook for %q", secret)
+	}
+	if len(value) == 0 {
+		return nil, fmt.Errorf("rotation hook returned an empty value for %q", secret)
+	}
+	return value, nil
+}
@@ -0,0 +1,136 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"google.golang.org/api/googleapi"
+	secretmanager "google.golang.org/api/secretmanager/v1"
+)
+
+// SecretManagerClient contains minimum interface required by the service.
+type SecretManagerClient interface {
+	CreateSecret(context.Context, string, string, *secretmanager.Secret) (*secretmanager.Secret, error)
+	AddSecretVersion(context.Context, string, *secretmanager.AddSecretVersionRequest) (*secretmanager.SecretVersion, error)
+	ListSecrets(context.Context, string) ([]*secretmanager.Secret, error)
+	ListSecretVersions(context.Context, string) ([]*secretmanager.SecretVersion, error)
+	DisableSecretVersion(context.Context, string) (*secretmanager.SecretVersion, error)
+	AccessSecretVersion(context.Context, string) (*secretmanager.AccessSecretVersionResponse, error)
+}
+
+// SecretManager service.
+type SecretManager struct {
+	client SecretManagerClient
+}
+
+// NewSecretManager returns a Secret Manager service.
+func NewSecretManager(client SecretManagerClient) *SecretManager {
+	return &SecretManager{client: client}
+}
+
+// StoreSecret adds the payload as a new version of the secret, creating the secret if it does
+// not exist yet. The full resource name of the new version is returned.
+func (s *SecretManager) StoreSecret(ctx context.Context, projectID, secretID string, payload []byte) (string, error) {
+	parent := fmt.Sprintf("projects/%s", projectID)
+	_, err := s.client.CreateSecret(ctx, parent, secretID, &secretmanager.Secret{
+		Replication: &secretmanager.Replication{Automatic: &secretmanager.Automatic{}},
+	})
+	if err != nil && !alreadyExists(err) {
+		return "", errors.Wrapf(err, "failed to create secret %q", secretID)
+	}
+	version, err := s.client.AddSecretVersion(ctx, parent+"/secrets/"+secretID, &secretmanager.AddSecretVersionRequest{
+		Payload: &secretmanager.SecretPayload{Data: base64.StdEncoding.EncodeToString(payload)},
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to add version to secret %q", secretID)
+	}
+	return version.Name, nil
+}
+
+// SecretsByLabel returns the names of the project's secrets whose label key is set to value.
+func (s *SecretManager) SecretsByLabel(ctx context.Context, projectID, key, value string) ([]string, error) {
+	secrets, err := s.client.ListSecrets(ctx, "projects/"+projectID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list secrets in %q", projectID)
+	}
+	names := []string{}
+	for _, secret := range secrets {
+		if v, ok := secret.Labels[key]; ok && v == value {
+			names = append(names, secret.Name)
+		}
+	}
+	return names, nil
+}
+
+// AddVersion adds the payload as a new version of an existing secret. The full resource name of
+// the new version is returned.
+func (s *SecretManager) AddVersion(ctx context.Context, secret string, payload []byte) (string, error) {
+	version, err := s.client.AddSecretVersion(ctx, secret, &secretmanager.AddSecretVersionRequest{
+		Payload: &secretmanager.SecretPayload{Data: base64.StdEncoding.EncodeToString(payload)},
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to add version to secret %q", secret)
+	}
+	return version.Name, nil
+}
+
+// EnabledVersions returns the names of the secret's enabled versions.
+func (s *SecretManager) EnabledVersions(ctx context.Context, secret string) ([]string, error) {
+	versions, err := s.client.ListSecretVersions(ctx, secret)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list versions of secret %q", secret)
+	}
+	names := []string{}
+	for _, v := range versions {
+		if v.State == "ENABLED" {
+			names = append(names, v.Name)
+		}
+	}
+	return names, nil
+}
+
+// Access returns the payload of the secret version, such as
+// "projects/p/secrets/s/versions/latest".
+func (s *SecretManager) Access(ctx context.Context, version string) ([]byte, error) {
+	r, err := s.client.AccessSecretVersion(ctx, version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to access secret version %q", version)
+	}
+	payload, err := base64.StdEncoding.DecodeString(r.Payload.Data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid payload of secret version %q", version)
+	}
+	return payload, nil
+}
+
+// DisableVersions disables the secret versions so they can no longer be accessed.
+func (s *SecretManager) DisableVersions(ctx context.Context, versions []string) error {
+	for _, v := range versions {
+		if _, err := s.client.DisableSecretVersion(ctx, v); err != nil {
+			return errors.Wrapf(err, "failed to disable secret version %q", v)
+		}
+	}
+	return nil
+}
+
+func alreadyExists(err error) bool {
+	e, ok := err.(*googleapi.Error)
+	return ok && e.Code == http.StatusConflict
+}
@@ -0,0 +1,138 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SecretReferencePrefix marks configuration values referencing a Secret Manager secret version,
+// such as "secret://projects/p/secrets/s/versions/latest".
+const SecretReferencePrefix = "secret://"
+
+// SecretAccessor contains minimum interface required by the secret resolver.
+type SecretAccessor interface {
+	Access(ctx context.Context, version string) ([]byte, error)
+}
+
+// SecretResolver replaces secret references in configurations with the payloads of the secret
+// versions they reference.
+//
+// Payloads are cached. Versions referenced by number never change and are kept, versions
+// referenced by an alias such as "latest" are accessed again once the TTL passes so rotated
+// secrets are picked up. If accessing fails the cached payload is used until the next TTL passes.
+type SecretResolver struct {
+	secrets SecretAccessor
+	ttl     time.Duration
+	mu      sync.Mutex
+	cache   map[string]*cachedSecret
+}
+
+type cachedSecret struct {
+	payload string
+	// expires is zero for versions referenced by number.
+	expires time.Time
+}
+
+// NewSecretResolver returns a resolver accessing secret versions with the accessor.
+func NewSecretResolver(secrets SecretAccessor, ttl time.Duration) *SecretResolver {
+	return &SecretResolver{secrets: secrets, ttl: ttl, cache: map[string]*cachedSecret{}}
+}
+
+// Resolve replaces the secret references in the strings of v, which must be a pointer, walking
+// its structs, slices and maps.
+func (r *SecretResolver) Resolve(ctx context.Context, v interface{}, now time.Time) error {
+	return r.resolve(ctx, reflect.ValueOf(v), now)
+}
+
+func (r *SecretResolver) resolve(ctx context.Context, v reflect.Value, now time.Time) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return r.resolve(ctx, v.Elem(), now)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				if err := r.resolve(ctx, v.Field(i), now); err != nil {
+					return err
+				}
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := r.resolve(ctx, v.Index(i), now); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			e := reflect.New(v.Type().Elem()).Elem()
+			e.Set(v.MapIndex(k))
+			if err := r.resolve(ctx, e, now); err != nil {
+				return err
+			}
+			v.SetMapIndex(k, e)
+		}
+	case reflect.String:
+		if !strings.HasPrefix(v.String(), SecretReferencePrefix) || !v.CanSet() {
+			return nil
+		}
+		payload, err := r.access(ctx, strings.TrimPrefix(v.String(), SecretReferencePrefix), now)
+		if err != nil {
+			return err
+		}
+		v.SetString(payload)
+	}
+	return nil
+}
+
+// access returns the payload of the secret version, from the cache unless it expired.
+func (r *SecretResolver) access(ctx context.Context, version string, now time.Time) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cached, ok := r.cache[version]
+	if ok && (cached.expires.IsZero() || now.Before(cached.expires)) {
+		return cached.payload, nil
+	}
+	b, err := r.secrets.Access(ctx, version)
+	if err != nil {
+		if ok {
+			cached.expires = now.Add(r.ttl)
+			return cached.payload, nil
+		}
+		return "", errors.Wrapf(err, "failed to resolve secret reference %q", version)
+	}
+	cached = &cachedSecret{payload: string(b)}
+	if !pinnedVersion(version) {
+		cached.expires = now.Add(r.ttl)
+	}
+	r.cache[version] = cached
+	return cached.payload, nil
+}
+
+// pinnedVersion returns whether the secret version is referenced by number rather than an alias.
+func pinnedVersion(version string) bool {
+	_, err := strconv.Atoi(version[strings.LastIndex(version, "/")+1:])
+	return err == nil
+}
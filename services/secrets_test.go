@@ -0,0 +1,79 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+)
+
+func TestSecretResolver(t *testing.T) {
+	const (
+		latest = "projects/p/secrets/slack-token/versions/latest"
+		pinned = "projects/p/secrets/sendgrid-key/versions/3"
+	)
+	ctx := context.Background()
+	now := time.Now()
+	stub := &stubs.SecretManagerStub{StubbedPayloads: map[string]string{
+		latest: base64.StdEncoding.EncodeToString([]byte("xoxb-1")),
+		pinned: base64.StdEncoding.EncodeToString([]byte("SG.1")),
+	}}
+	type config struct {
+		Slack   SlackConfig
+		Keys    []string
+		Headers map[string]string
+		Plain   string
+	}
+	r := NewSecretResolver(NewSecretManager(stub), 5*time.Minute)
+	resolve := func(at time.Time) *config {
+		c := &config{
+			Slack:   SlackConfig{Token: "secret://" + latest},
+			Keys:    []string{"secret://" + pinned},
+			Headers: map[string]string{"Authorization": "secret://" + latest},
+			Plain:   "not-a-secret",
+		}
+		if err := r.Resolve(ctx, c, at); err != nil {
+			t.Fatalf("failed to resolve: %q", err)
+		}
+		return c
+	}
+	c := resolve(now)
+	if c.Slack.Token != "xoxb-1" || c.Keys[0] != "SG.1" || c.Headers["Authorization"] != "xoxb-1" || c.Plain != "not-a-secret" {
+		t.Errorf("unexpected resolved config %+v", c)
+	}
+	stub.StubbedPayloads[latest] = base64.StdEncoding.EncodeToString([]byte("xoxb-2"))
+	stub.StubbedPayloads[pinned] = base64.StdEncoding.EncodeToString([]byte("SG.2"))
+	if c := resolve(now.Add(time.Minute)); c.Slack.Token != "xoxb-1" {
+		t.Errorf("got token %q, cached payload should be used before the TTL passes", c.Slack.Token)
+	}
+	c = resolve(now.Add(10 * time.Minute))
+	if c.Slack.Token != "xoxb-2" {
+		t.Errorf("got token %q, rotated secret not picked up after the TTL passed", c.Slack.Token)
+	}
+	if c.Keys[0] != "SG.1" {
+		t.Errorf("got key %q, versions referenced by number should stay cached", c.Keys[0])
+	}
+	delete(stub.StubbedPayloads, latest)
+	if c := resolve(now.Add(20 * time.Minute)); c.Slack.Token != "xoxb-2" {
+		t.Errorf("got token %q, cached payload should be used if accessing fails", c.Slack.Token)
+	}
+	if err := NewSecretResolver(NewSecretManager(stub), time.Minute).Resolve(ctx, &config{Plain: "secret://" + latest}, now); err == nil {
+		t.Error("expected error resolving missing secret")
+	}
+}
@@ -0,0 +1,157 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cloudfunctions "google.golang.org/api/cloudfunctions/v1"
+	run "google.golang.org/api/run/v1"
+)
+
+const (
+	// functionInvokerRole is the role allowing members to invoke a Cloud Function.
+	functionInvokerRole = "roles/cloudfunctions.invoker"
+	// runInvokerRole is the role allowing members to invoke a Cloud Run service.
+	runInvokerRole = "roles/run.invoker"
+)
+
+// CloudFunctionsClient contains minimum interface required by the service.
+type CloudFunctionsClient interface {
+	GetFunctionPolicy(context.Context, string) (*cloudfunctions.Policy, error)
+	SetFunctionPolicy(context.Context, string, *cloudfunctions.Policy) (*cloudfunctions.Policy, error)
+}
+
+// CloudRunClient contains minimum interface required by the service.
+type CloudRunClient interface {
+	GetServicePolicy(context.Context, string) (*run.Policy, error)
+	SetServicePolicy(context.Context, string, *run.Policy) (*run.Policy, error)
+	GetService(context.Context, string) (*run.Service, error)
+	ReplaceService(context.Context, string, *run.Service) (*run.Service, error)
+}
+
+// Serverless service for Cloud Functions and Cloud Run.
+type Serverless struct {
+	functions CloudFunctionsClient
+	run       CloudRunClient
+}
+
+// NewServerless returns a Serverless service.
+func NewServerless(functions CloudFunctionsClient, run CloudRunClient) *Serverless {
+	return &Serverless{functions: functions, run: run}
+}
+
+// RemoveFunctionInvokers removes the members from the invoker role of a Cloud Function. The
+// policy is only updated if a member was found, the removed members are returned.
+func (s *Serverless) RemoveFunctionInvokers(ctx context.Context, name string, members []string) ([]string, error) {
+	policy, err := s.functions.GetFunctionPolicy(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get function policy: %q", err)
+	}
+	removed := []string{}
+	for _, b := range policy.Bindings {
+		if b.Role != functionInvokerRole {
+			continue
+		}
+		var r []string
+		b.Members, r = filterMembers(b.Members, members)
+		removed = append(removed, r...)
+	}
+	if len(removed) == 0 {
+		return removed, nil
+	}
+	if _, err := s.functions.SetFunctionPolicy(ctx, name, policy); err != nil {
+		return nil, fmt.Errorf("failed to set function policy: %q", err)
+	}
+	return removed, nil
+}
+
+// RemoveServiceInvokers removes the members from the invoker role of a Cloud Run service. The
+// policy is only updated if a member was found, the removed members are returned.
+func (s *Serverless) RemoveServiceInvokers(ctx context.Context, name string, members []string) ([]string, error) {
+	policy, err := s.run.GetServicePolicy(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service policy: %q", err)
+	}
+	removed := []string{}
+	for _, b := range policy.Bindings {
+		if b.Role != runInvokerRole {
+			continue
+		}
+		var r []string
+		b.Members, r = filterMembers(b.Members, members)
+		removed = append(removed, r...)
+	}
+	if len(removed) == 0 {
+		return removed, nil
+	}
+	if _, err := s.run.SetServicePolicy(ctx, name, policy); err != nil {
+		return nil, fmt.Errorf("failed to set service policy: %q", err)
+	}
+	return removed, nil
+}
+
+// AnnotateService adds the annotations to the metadata of a Cloud Run service. Only the service's
+// metadata changes so no new revision is created. The service is only replaced if an annotation differs.
+func (s *Serverless) AnnotateService(ctx context.Context, name string, annotations map[string]string) error {
+	service, err := s.run.GetService(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to get service: %q", err)
+	}
+	if service.Metadata == nil {
+		service.Metadata = &run.ObjectMeta{}
+	}
+	if service.Metadata.Annotations == nil {
+		service.Metadata.Annotations = map[string]string{}
+	}
+	changed := false
+	for k, v := range annotations {
+		if service.Metadata.Annotations[k] == v {
+			continue
+		}
+		service.Metadata.Annotations[k] = v
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	if _, err := s.run.ReplaceService(ctx, name, service); err != nil {
+		return fmt.Errorf("failed to replace service: %q", err)
+	}
+	return nil
+}
+
+// filterMembers splits members into those kept and those found in remove.
+func filterMembers(members, remove []string) ([]string, []string) {
+	kept := []string{}
+	removed := []string{}
+	for _, member := range members {
+		found := false
+		for _, r := range remove {
+			if strings.EqualFold(r, member) {
+				found = true
+				break
+			}
+		}
+		if found {
+			removed = append(removed, member)
+			continue
+		}
+		kept = append(kept, member)
+	}
+	return kept, removed
+}
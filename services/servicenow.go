@@ -0,0 +1,112 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ServiceNowConfig configures the ServiceNow instance incidents are opened in.
+type ServiceNowConfig struct {
+	// URL is the instance, such as "https://cloudorg.service-now.com".
+	URL      string
+	User     string
+	Password string
+	// AssignmentGroup is the name of the group incidents are assigned to.
+	AssignmentGroup string `yaml:"assignment_group"`
+	// Severity maps the severity of findings, such as "critical", to the impact and urgency of
+	// their incidents, from 1 for high to 3 for low.
+	Severity map[string]int
+}
+
+// ServiceNowClient contains minimum interface required by the ServiceNow service.
+type ServiceNowClient interface {
+	FindIncident(ctx context.Context, correlationID string) (string, error)
+	CreateIncident(ctx context.Context, body []byte) (string, error)
+	UpdateIncident(ctx context.Context, sysID string, body []byte) error
+}
+
+// ServiceNow service opens ServiceNow incidents for remediations, one per finding.
+type ServiceNow struct {
+	client ServiceNowClient
+	conf   ServiceNowConfig
+}
+
+// defaultIncidentSeverity maps the severity of findings to incidents if none is configured.
+var defaultIncidentSeverity = map[string]int{"CRITICAL": 1, "HIGH": 1, "MEDIUM": 2, "LOW": 3}
+
+// NewServiceNow returns a ServiceNow service opening incidents as configured.
+func NewServiceNow(client ServiceNowClient, conf ServiceNowConfig) *ServiceNow {
+	severity := map[string]int{}
+	for k, v := range conf.Severity {
+		severity[strings.ToUpper(k)] = v
+	}
+	if len(severity) == 0 {
+		severity = defaultIncidentSeverity
+	}
+	conf.Severity = severity
+	return &ServiceNow{client: client, conf: conf}
+}
+
+// File opens an incident for the remediation, or updates the finding's incident if it has one.
+// Incidents are correlated to findings by the hash of the finding ID.
+func (s *ServiceNow) File(ctx context.Context, r *AuditRecord) error {
+	n := NewNotification(r)
+	fields := map[string]string{
+		"short_description": "Security Response Automation: " + n.Subject(),
+		"description":       n.Text(),
+	}
+	if s.conf.AssignmentGroup != "" {
+		fields["assignment_group"] = s.conf.AssignmentGroup
+	}
+	if level, ok := s.conf.Severity[strings.ToUpper(r.Severity)]; ok {
+		fields["impact"] = strconv.Itoa(level)
+		fields["urgency"] = strconv.Itoa(level)
+	}
+	sysID := ""
+	if r.FindingID != "" {
+		correlationID := documentID(r.FindingID)
+		id, err := s.client.FindIncident(ctx, correlationID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find incident of %q", r.FindingID)
+		}
+		sysID = id
+		fields["correlation_id"] = correlationID
+		fields["correlation_display"] = "Security Response Automation"
+	}
+	if sysID != "" {
+		// The work notes keep the history of the remediations of the finding.
+		fields["work_notes"] = n.Text()
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	if sysID != "" {
+		if err := s.client.UpdateIncident(ctx, sysID, b); err != nil {
+			return errors.Wrapf(err, "failed to update incident %q", sysID)
+		}
+		return nil
+	}
+	if _, err := s.client.CreateIncident(ctx, b); err != nil {
+		return errors.Wrap(err, "failed to create incident")
+	}
+	return nil
+}
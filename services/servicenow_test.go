@@ -0,0 +1,84 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+)
+
+func TestServiceNowFile(t *testing.T) {
+	ctx := context.Background()
+	const findingID = "organizations/456/sources/1/findings/2"
+	for _, tt := range []struct {
+		name      string
+		severity  map[string]int
+		incidents map[string]string
+		record    *AuditRecord
+		want      map[string]string
+		wantSysID string
+	}{
+		{
+			name:   "open",
+			record: &AuditRecord{FindingID: findingID, Severity: "HIGH", Action: "CloseBucket", Outcome: AuditFailure},
+			want:   map[string]string{"assignment_group": "Security Operations", "impact": "1", "urgency": "1", "correlation_id": documentID(findingID)},
+		},
+		{
+			name:     "configured severity",
+			severity: map[string]int{"high": 2},
+			record:   &AuditRecord{FindingID: findingID, Severity: "HIGH", Action: "CloseBucket", Outcome: AuditFailure},
+			want:     map[string]string{"impact": "2", "urgency": "2"},
+		},
+		{
+			name:      "update",
+			incidents: map[string]string{documentID(findingID): "sys-42"},
+			record:    &AuditRecord{FindingID: findingID, Action: "CloseBucket", Outcome: AuditDryRun},
+			want:      map[string]string{"impact": "", "work_notes": NewNotification(&AuditRecord{FindingID: findingID, Action: "CloseBucket", Outcome: AuditDryRun}).Text()},
+			wantSysID: "sys-42",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubs.ServiceNowStub{StubbedIncidents: tt.incidents}
+			s := NewServiceNow(stub, ServiceNowConfig{AssignmentGroup: "Security Operations", Severity: tt.severity})
+			if err := s.File(ctx, tt.record); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			body := ""
+			if tt.wantSysID != "" {
+				if len(stub.SavedIncidents) != 0 || len(stub.SavedUpdates[tt.wantSysID]) != 1 {
+					t.Fatalf("%s failed: expected an update of %q, got %v %v", tt.name, tt.wantSysID, stub.SavedIncidents, stub.SavedUpdates)
+				}
+				body = stub.SavedUpdates[tt.wantSysID][0]
+			} else {
+				if len(stub.SavedIncidents) != 1 {
+					t.Fatalf("%s failed: got %d incidents want 1", tt.name, len(stub.SavedIncidents))
+				}
+				body = stub.SavedIncidents[0]
+			}
+			var got map[string]string
+			if err := json.Unmarshal([]byte(body), &got); err != nil {
+				t.Fatalf("%s failed to unmarshal incident: %q", tt.name, err)
+			}
+			for k, want := range tt.want {
+				if got[k] != want {
+					t.Errorf("%s failed: got %s %q want %q", tt.name, k, got[k], want)
+				}
+			}
+		})
+	}
+}
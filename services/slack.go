@@ -0,0 +1,234 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	firestore "google.golang.org/api/firestore/v1"
+)
+
+const (
+	// slackThreadCollection is the Firestore collection holding the Slack thread of each finding.
+	slackThreadCollection = "automation-slack-threads"
+	// slackRequestAge is how old requests from Slack can be, to prevent replays.
+	slackRequestAge = 5 * time.Minute
+)
+
+// SlackConfig configures the Slack notification channel. Messages are posted to the channel with
+// the bot token if set, otherwise to the incoming webhook.
+type SlackConfig struct {
+	WebhookURL string `yaml:"webhook_url"`
+	Token      string
+	Channel    string
+	// SigningSecret verifies the requests Slack sends when approve or deny buttons are clicked.
+	SigningSecret string `yaml:"signing_secret"`
+}
+
+// SlackClient contains minimum interface required by the Slack service.
+type SlackClient interface {
+	PostMessage(ctx context.Context, body []byte) (string, error)
+	UploadFile(ctx context.Context, channel, threadTS, filename, contentType string, content []byte) error
+	UserEmail(ctx context.Context, userID string) (string, error)
+}
+
+// SlackDocumentClient contains minimum interface required by the Slack service to keep threads.
+type SlackDocumentClient interface {
+	CreateDocument(ctx context.Context, projectID, collection, documentID string, fields map[string]firestore.Value) error
+	GetDocument(ctx context.Context, projectID, collection, documentID string) (*firestore.Document, error)
+}
+
+// Slack service posts notifications and approval requests to a Slack channel. Messages about the
+// same finding are posted in one thread.
+type Slack struct {
+	client        SlackClient
+	documents     SlackDocumentClient
+	projectID     string
+	channel       string
+	signingSecret []byte
+}
+
+// SlackDecision is a decision made with the buttons of an approval request.
+type SlackDecision struct {
+	ID       string
+	Decision string
+	// Approver is the email address of the Slack user who clicked the button.
+	Approver string
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackElement struct {
+	Type     string     `json:"type"`
+	Text     *slackText `json:"text"`
+	ActionID string     `json:"action_id"`
+	Value    string     `json:"value"`
+	Style    string     `json:"style,omitempty"`
+}
+
+type slackBlock struct {
+	Type     string         `json:"type"`
+	Text     *slackText     `json:"text,omitempty"`
+	Elements []slackElement `json:"elements,omitempty"`
+}
+
+type slackMessage struct {
+	Channel  string       `json:"channel"`
+	Text     string       `json:"text"`
+	ThreadTS string       `json:"thread_ts,omitempty"`
+	Blocks   []slackBlock `json:"blocks,omitempty"`
+}
+
+// NewSlack returns a Slack service posting to the channel and keeping threads in the automation project.
+func NewSlack(client SlackClient, documents SlackDocumentClient, projectID, channel, signingSecret string) *Slack {
+	return &Slack{client: client, documents: documents, projectID: projectID, channel: channel, signingSecret: []byte(signingSecret)}
+}
+
+// Notify posts the notification in the thread of its finding, uploading its attachments in the
+// thread as well.
+func (s *Slack) Notify(ctx context.Context, n *Notification) error {
+	ts, err := s.post(ctx, n.FindingID, &slackMessage{Text: "*" + n.Subject() + "*\n" + n.Text()})
+	if err != nil {
+		return err
+	}
+	for _, a := range n.Attachments {
+		if err := s.client.UploadFile(ctx, s.channel, ts, a.Filename, a.Type(), a.Content); err != nil {
+			return errors.Wrapf(err, "failed to upload %q to slack", a.Filename)
+		}
+	}
+	return nil
+}
+
+// Reply posts the text in the thread of the finding.
+func (s *Slack) Reply(ctx context.Context, findingID, text string) error {
+	_, err := s.post(ctx, findingID, &slackMessage{Text: text})
+	return err
+}
+
+// RequestApproval posts the text with buttons to approve or deny the pending action in the thread
+// of its finding.
+func (s *Slack) RequestApproval(ctx context.Context, p *PendingAction, text string) error {
+	button := func(label, decision, style string) slackElement {
+		return slackElement{Type: "button", Text: &slackText{Type: "plain_text", Text: label}, ActionID: decision, Value: p.ID, Style: style}
+	}
+	_, err := s.post(ctx, p.FindingID, &slackMessage{
+		Text: text,
+		Blocks: []slackBlock{
+			{Type: "section", Text: &slackText{Type: "mrkdwn", Text: text}},
+			{Type: "actions", Elements: []slackElement{
+				button("Approve", DecisionApprove, "primary"),
+				button("Deny", DecisionDeny, "danger"),
+			}},
+		},
+	})
+	return err
+}
+
+// post posts the message in the thread of the finding, starting it if there is none yet. The
+// timestamp of the thread is returned.
+func (s *Slack) post(ctx context.Context, findingID string, m *slackMessage) (string, error) {
+	m.Channel = s.channel
+	id := documentID(findingID)
+	if findingID != "" {
+		if d, err := s.documents.GetDocument(ctx, s.projectID, slackThreadCollection, id); err == nil {
+			m.ThreadTS = d.Fields["ts"].StringValue
+		} else if !notFound(err) {
+			return "", errors.Wrapf(err, "failed to get slack thread of %q", findingID)
+		}
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	ts, err := s.client.PostMessage(ctx, b)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to post slack message")
+	}
+	if m.ThreadTS != "" {
+		return m.ThreadTS, nil
+	}
+	if findingID == "" {
+		return ts, nil
+	}
+	fields := map[string]firestore.Value{
+		"finding_id": {StringValue: findingID},
+		"ts":         {StringValue: ts},
+	}
+	if err := s.documents.CreateDocument(ctx, s.projectID, slackThreadCollection, id, fields); err != nil {
+		return "", errors.Wrapf(err, "failed to save slack thread of %q", findingID)
+	}
+	return ts, nil
+}
+
+// VerifyRequest checks the request was signed by Slack with the signing secret and is recent.
+func (s *Slack) VerifyRequest(timestamp, signature string, body []byte, now time.Time) error {
+	if len(s.signingSecret) == 0 {
+		return errors.New("no slack signing secret configured")
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q", timestamp)
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age > slackRequestAge || age < -slackRequestAge {
+		return errors.New("request expired")
+	}
+	m := hmac.New(sha256.New, s.signingSecret)
+	fmt.Fprintf(m, "v0:%s:%s", timestamp, body)
+	if !hmac.Equal([]byte("v0="+hex.EncodeToString(m.Sum(nil))), []byte(signature)) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// Decision returns the decision made with the buttons of an approval request from the
+// interaction payload Slack sent.
+func (s *Slack) Decision(ctx context.Context, payload []byte) (*SlackDecision, error) {
+	var p struct {
+		Type string `json:"type"`
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+		Actions []struct {
+			ActionID string `json:"action_id"`
+			Value    string `json:"value"`
+		} `json:"actions"`
+	}
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal slack interaction")
+	}
+	if p.Type != "block_actions" || len(p.Actions) != 1 {
+		return nil, fmt.Errorf("unsupported slack interaction %q", p.Type)
+	}
+	a := p.Actions[0]
+	if a.ActionID != DecisionApprove && a.ActionID != DecisionDeny {
+		return nil, fmt.Errorf("unsupported decision %q", a.ActionID)
+	}
+	email, err := s.client.UserEmail(ctx, p.User.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get email of slack user %q", p.User.ID)
+	}
+	return &SlackDecision{ID: a.Value, Decision: a.ActionID, Approver: email}, nil
+}
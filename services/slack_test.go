@@ -0,0 +1,152 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/clients"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+)
+
+func TestSlackThreads(t *testing.T) {
+	ctx := context.Background()
+	slackStub := &stubs.SlackStub{}
+	s := NewSlack(slackStub, &stubs.FirestoreStub{}, "automation-project", "C0123", "secret")
+	n := &Notification{
+		FindingID:   "organizations/456/sources/1/findings/2",
+		Action:      "CloseBucket",
+		Outcome:     AuditSuccess,
+		Attachments: []clients.Attachment{{Filename: "bucket-policy-diff.json", Content: []byte("{}")}},
+	}
+	if err := s.Notify(ctx, n); err != nil {
+		t.Fatalf("failed to notify: %q", err)
+	}
+	p := &PendingAction{ID: "p1", FindingID: n.FindingID, Action: "close_bucket"}
+	if err := s.RequestApproval(ctx, p, "approve?"); err != nil {
+		t.Fatalf("failed to request approval: %q", err)
+	}
+	if err := s.Notify(ctx, &Notification{FindingID: "organizations/456/sources/1/findings/3"}); err != nil {
+		t.Fatalf("failed to notify: %q", err)
+	}
+	var got []slackMessage
+	for _, m := range slackStub.SavedMessages {
+		var msg slackMessage
+		if err := json.Unmarshal([]byte(m), &msg); err != nil {
+			t.Fatalf("failed to unmarshal message: %q", err)
+		}
+		got = append(got, msg)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d messages want 3", len(got))
+	}
+	if got[0].Channel != "C0123" || got[0].ThreadTS != "" || !strings.Contains(got[0].Text, "CloseBucket") {
+		t.Errorf("unexpected first message %+v", got[0])
+	}
+	if got[1].ThreadTS != "1591056000.000001" {
+		t.Errorf("approval request posted in thread %q want %q", got[1].ThreadTS, "1591056000.000001")
+	}
+	if len(got[1].Blocks) != 2 || len(got[1].Blocks[1].Elements) != 2 {
+		t.Fatalf("unexpected approval blocks %+v", got[1].Blocks)
+	}
+	for i, want := range []string{DecisionApprove, DecisionDeny} {
+		if e := got[1].Blocks[1].Elements[i]; e.ActionID != want || e.Value != "p1" {
+			t.Errorf("got button %+v want action %q for %q", e, want, "p1")
+		}
+	}
+	if got[2].ThreadTS != "" {
+		t.Errorf("other finding posted in thread %q", got[2].ThreadTS)
+	}
+	if files := slackStub.SavedFiles["1591056000.000001"]; len(files) != 1 || files[0] != "bucket-policy-diff.json" {
+		t.Errorf("got files %v uploaded in thread want the attachment", slackStub.SavedFiles)
+	}
+}
+
+func TestSlackVerifyRequest(t *testing.T) {
+	now := time.Date(2020, 6, 2, 0, 0, 0, 0, time.UTC)
+	body := []byte("payload=%7B%7D")
+	sign := func(secret, ts string) string {
+		m := hmac.New(sha256.New, []byte(secret))
+		fmt.Fprintf(m, "v0:%s:%s", ts, body)
+		return "v0=" + hex.EncodeToString(m.Sum(nil))
+	}
+	ts := strconv.FormatInt(now.Unix(), 10)
+	old := strconv.FormatInt(now.Add(-time.Hour).Unix(), 10)
+	for _, tt := range []struct {
+		name      string
+		timestamp string
+		signature string
+		wantErr   bool
+	}{
+		{name: "valid", timestamp: ts, signature: sign("secret", ts)},
+		{name: "wrong secret", timestamp: ts, signature: sign("other", ts), wantErr: true},
+		{name: "expired", timestamp: old, signature: sign("secret", old), wantErr: true},
+		{name: "invalid timestamp", timestamp: "now", signature: sign("secret", "now"), wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSlack(&stubs.SlackStub{}, &stubs.FirestoreStub{}, "automation-project", "C0123", "secret")
+			if err := s.VerifyRequest(tt.timestamp, tt.signature, body, now); (err != nil) != tt.wantErr {
+				t.Errorf("%s failed: got err %v want err %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSlackDecision(t *testing.T) {
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name    string
+		payload string
+		want    *SlackDecision
+		wantErr bool
+	}{
+		{
+			name:    "approve",
+			payload: `{"type":"block_actions","user":{"id":"U1"},"actions":[{"action_id":"approve","value":"p1"}]}`,
+			want:    &SlackDecision{ID: "p1", Decision: DecisionApprove, Approver: "alice@cloudorg.com"},
+		},
+		{
+			name:    "unknown user",
+			payload: `{"type":"block_actions","user":{"id":"U2"},"actions":[{"action_id":"deny","value":"p1"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported action",
+			payload: `{"type":"block_actions","user":{"id":"U1"},"actions":[{"action_id":"escalate","value":"p1"}]}`,
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			slackStub := &stubs.SlackStub{StubbedEmails: map[string]string{"U1": "alice@cloudorg.com"}}
+			s := NewSlack(slackStub, &stubs.FirestoreStub{}, "automation-project", "C0123", "secret")
+			got, err := s.Decision(ctx, []byte(tt.payload))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%s failed: got err %v want err %v", tt.name, err, tt.wantErr)
+			}
+			if err == nil && *got != *tt.want {
+				t.Errorf("%s failed: got %+v want %+v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
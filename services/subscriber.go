@@ -0,0 +1,92 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/pkg/errors"
+	pubsub "google.golang.org/api/pubsub/v1"
+)
+
+// maxAcknowledge is the number of messages acknowledged per request.
+const maxAcknowledge = 1000
+
+// SubscriberClient contains minimum interface required by the service.
+type SubscriberClient interface {
+	Pull(context.Context, string, int64) ([]*pubsub.ReceivedMessage, error)
+	Acknowledge(context.Context, string, []string) error
+}
+
+// Subscriber service.
+type Subscriber struct {
+	client SubscriberClient
+}
+
+// Message is a message pulled from a subscription.
+type Message struct {
+	// AckID acknowledges the message.
+	AckID string
+	Data  []byte
+}
+
+// NewSubscriber returns a Subscriber service.
+func NewSubscriber(client SubscriberClient) *Subscriber {
+	return &Subscriber{client: client}
+}
+
+// Pull returns up to max messages of the subscription, fewer if it runs out of messages.
+func (s *Subscriber) Pull(ctx context.Context, subscription string, max int) ([]*Message, error) {
+	messages := []*Message{}
+	for len(messages) < max {
+		received, err := s.client.Pull(ctx, subscription, int64(max-len(messages)))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to pull %q", subscription)
+		}
+		if len(received) == 0 {
+			break
+		}
+		for _, r := range received {
+			if r.Message == nil {
+				continue
+			}
+			data, err := base64.StdEncoding.DecodeString(r.Message.Data)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to decode message %q", r.Message.MessageId)
+			}
+			messages = append(messages, &Message{AckID: r.AckId, Data: data})
+		}
+	}
+	return messages, nil
+}
+
+// Acknowledge acknowledges the messages so they're not redelivered.
+func (s *Subscriber) Acknowledge(ctx context.Context, subscription string, messages []*Message) error {
+	for start := 0; start < len(messages); start += maxAcknowledge {
+		end := start + maxAcknowledge
+		if end > len(messages) {
+			end = len(messages)
+		}
+		ackIDs := make([]string, 0, end-start)
+		for _, m := range messages[start:end] {
+			ackIDs = append(ackIDs, m.AckID)
+		}
+		if err := s.client.Acknowledge(ctx, subscription, ackIDs); err != nil {
+			return errors.Wrapf(err, "failed to acknowledge %q", subscription)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	pubsub "google.golang.org/api/pubsub/v1"
+)
+
+func TestSubscriber(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		stubbed int
+		max     int
+		want    int
+	}{
+		{name: "runs out of messages", stubbed: 3, max: 10, want: 3},
+		{name: "bounded", stubbed: 5, max: 4, want: 4},
+		{name: "empty", stubbed: 0, max: 10, want: 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			stub := &stubs.SubscriberStub{}
+			for i := 0; i < tt.stubbed; i++ {
+				stub.StubbedMessages = append(stub.StubbedMessages, &pubsub.ReceivedMessage{
+					AckId:   fmt.Sprintf("ack-%d", i),
+					Message: &pubsub.PubsubMessage{Data: base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("finding-%d", i)))},
+				})
+			}
+			s := NewSubscriber(stub)
+			messages, err := s.Pull(ctx, "projects/p/subscriptions/s", tt.max)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if len(messages) != tt.want {
+				t.Fatalf("%s failed: got %d messages want %d", tt.name, len(messages), tt.want)
+			}
+			want := []string{}
+			for i, m := range messages {
+				if string(m.Data) != fmt.Sprintf("finding-%d", i) {
+					t.Errorf("%s failed: got data %q", tt.name, m.Data)
+				}
+				want = append(want, m.AckID)
+			}
+			if err := s.Acknowledge(ctx, "projects/p/subscriptions/s", messages); err != nil {
+				t.Fatalf("%s failed to acknowledge: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(append([]string{}, stub.Acknowledged...), want); diff != "" {
+				t.Errorf("%s failed, acknowledged difference:%+v", tt.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// TeamsConfig configures the Microsoft Teams notification channel.
+type TeamsConfig struct {
+	// WebhookURL is the incoming webhook connector of the Teams channel.
+	WebhookURL string `yaml:"webhook_url"`
+}
+
+// Teams service posts notifications and approval requests as connector cards to a Teams channel.
+type Teams struct {
+	client WebhookClient
+}
+
+type teamsFact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type teamsSection struct {
+	ActivityTitle string      `json:"activityTitle,omitempty"`
+	Text          string      `json:"text,omitempty"`
+	Facts         []teamsFact `json:"facts,omitempty"`
+}
+
+type teamsCard struct {
+	Type       string         `json:"@type"`
+	Context    string         `json:"@context"`
+	Summary    string         `json:"summary"`
+	ThemeColor string         `json:"themeColor"`
+	Title      string         `json:"title"`
+	Sections   []teamsSection `json:"sections"`
+}
+
+// teamsColors are the theme colors of the cards by outcome.
+var teamsColors = map[string]string{
+	AuditSuccess: "2EB886",
+	AuditFailure: "D40E0D",
+	AuditDryRun:  "0076D7",
+	AuditSkipped: "DAA038",
+}
+
+// NewTeams returns a Teams service posting to the incoming webhook.
+func NewTeams(client WebhookClient) *Teams {
+	return &Teams{client: client}
+}
+
+// Notify posts the notification as a card listing the finding, project and outcome.
+func (t *Teams) Notify(ctx context.Context, n *Notification) error {
+	facts := []teamsFact{{Name: "Outcome", Value: n.Outcome}}
+	for _, f := range []teamsFact{
+		{Name: "Finding", Value: n.FindingID},
+		{Name: "Severity", Value: n.Severity},
+		{Name: "Project", Value: n.ProjectID},
+		{Name: "Error", Value: n.Error},
+		{Name: "Reason", Value: n.Reason},
+		{Name: "Remediation ID", Value: n.RemediationID},
+	} {
+		if f.Value != "" {
+			facts = append(facts, f)
+		}
+	}
+	// Digests list the notifications they summarize instead.
+	if n.Digest != nil {
+		facts = []teamsFact{}
+		for _, d := range n.Digest {
+			facts = append(facts, teamsFact{Name: d.Subject(), Value: d.FindingID})
+		}
+	}
+	color, ok := teamsColors[n.Outcome]
+	if !ok {
+		color = teamsColors[AuditDryRun]
+	}
+	return t.post(ctx, &teamsCard{
+		Summary:    n.Subject(),
+		ThemeColor: color,
+		Title:      n.Subject(),
+		Sections:   []teamsSection{{ActivityTitle: "Security Response Automation ran " + n.Action, Facts: facts}},
+	})
+}
+
+// RequestApproval posts the text as a card letting the channel know the pending action waits
+// for approval. The card has no approve or deny links, anyone in the channel could follow them.
+func (t *Teams) RequestApproval(ctx context.Context, p *PendingAction, text string) error {
+	return t.post(ctx, &teamsCard{
+		Summary:    "Approval required to run " + p.Action,
+		ThemeColor: teamsColors[AuditSkipped],
+		Title:      "Approval required to run " + p.Action,
+		Sections:   []teamsSection{{Text: strings.Replace(text, "\n", "\n\n", -1)}},
+	})
+}
+
+func (t *Teams) post(ctx context.Context, card *teamsCard) error {
+	card.Type, card.Context = "MessageCard", "https://schema.org/extensions"
+	b, err := json.Marshal(card)
+	if err != nil {
+		return err
+	}
+	return t.client.Post(ctx, b)
+}
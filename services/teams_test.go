@@ -0,0 +1,57 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+)
+
+func TestTeams(t *testing.T) {
+	ctx := context.Background()
+	stub := &stubs.WebhookStub{}
+	teams := NewTeams(stub)
+	if err := teams.Notify(ctx, &Notification{FindingID: "organizations/456/sources/1/findings/2", Action: "CloseBucket", Outcome: AuditFailure, Error: "permission denied"}); err != nil {
+		t.Fatalf("failed to notify: %q", err)
+	}
+	p := &PendingAction{ID: "p1", Action: "close_bucket"}
+	if err := teams.RequestApproval(ctx, p, "approve?"); err != nil {
+		t.Fatalf("failed to request approval: %q", err)
+	}
+	if len(stub.SavedRequests) != 2 {
+		t.Fatalf("got %d cards want 2", len(stub.SavedRequests))
+	}
+	var cards []teamsCard
+	for _, r := range stub.SavedRequests {
+		var c teamsCard
+		if err := json.Unmarshal([]byte(r), &c); err != nil {
+			t.Fatalf("failed to unmarshal card: %q", err)
+		}
+		if c.Type != "MessageCard" {
+			t.Errorf("got card type %q want %q", c.Type, "MessageCard")
+		}
+		cards = append(cards, c)
+	}
+	if cards[0].ThemeColor != teamsColors[AuditFailure] || len(cards[0].Sections) != 1 || len(cards[0].Sections[0].Facts) != 3 {
+		t.Errorf("unexpected notification card %+v", cards[0])
+	}
+	if cards[1].Title != "Approval required to run close_bucket" || strings.Contains(stub.SavedRequests[1], "OpenUri") {
+		t.Errorf("unexpected approval card %+v", cards[1])
+	}
+}
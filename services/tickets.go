@@ -0,0 +1,108 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// TicketConfig configures the trackers tickets are filed in for remediations needing human
+// follow-up.
+type TicketConfig struct {
+	// Outcomes file tickets, defaults to failures, skipped findings and dry runs.
+	Outcomes   []string
+	Jira       JiraConfig
+	ServiceNow ServiceNowConfig `yaml:"servicenow"`
+}
+
+// TicketTracker files tickets for remediations in an issue tracker, updating the finding's
+// ticket if it already has one.
+type TicketTracker interface {
+	File(ctx context.Context, r *AuditRecord) error
+}
+
+// Tickets service files tickets for the remediations needing follow-up in each tracker.
+type Tickets struct {
+	outcomes []string
+	trackers []TicketTracker
+}
+
+// NewTickets returns a tickets service filing remediations with the outcomes in the trackers.
+func NewTickets(outcomes []string, trackers ...TicketTracker) *Tickets {
+	if len(outcomes) == 0 {
+		outcomes = []string{AuditFailure, AuditSkipped, AuditDryRun}
+	}
+	return &Tickets{outcomes: outcomes, trackers: trackers}
+}
+
+// File files a ticket for the remediation in each tracker if its outcome needs follow-up. All
+// trackers are tried even if some fail.
+func (t *Tickets) File(ctx context.Context, r *AuditRecord) error {
+	if !t.needsFollowUp(r.Outcome) {
+		return nil
+	}
+	var errs []string
+	for _, tracker := range t.trackers {
+		if err := tracker.File(ctx, r); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to file tickets: %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func (t *Tickets) needsFollowUp(outcome string) bool {
+	for _, o := range t.outcomes {
+		if o == outcome {
+			return true
+		}
+	}
+	return false
+}
+
+// ticketProperties returns the properties of the remediation ticket fields can be mapped from:
+// the fields of its notification and its values, which are the finding for skipped findings.
+func ticketProperties(r *AuditRecord) map[string]interface{} {
+	props := map[string]interface{}{}
+	if b, err := json.Marshal(NewNotification(r)); err == nil {
+		_ = json.Unmarshal(b, &props)
+	}
+	var values interface{}
+	if err := json.Unmarshal([]byte(r.Values), &values); err == nil {
+		props["values"] = values
+	}
+	return props
+}
+
+// property returns the property at the dotted path, such as "values.finding.category", or nil if
+// there is none.
+func property(props map[string]interface{}, path string) interface{} {
+	var v interface{} = props
+	for _, k := range strings.Split(path, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if v, ok = m[k]; !ok {
+			return nil
+		}
+	}
+	return v
+}
@@ -17,11 +17,12 @@ package services
 import (
 	"context"
 	"encoding/json"
-	"log"
 
 	"cloud.google.com/go/pubsub"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+
+	"github.com/googlecloudplatform/security-response-automation/logging"
 )
 
 const turbiniaRequestType = "TurbiniaRequest"
@@ -56,7 +57,7 @@ func SendTurbinia(ctx context.Context, turbiniaProjectID, topic, zone string, di
 			return err
 		}
 		m.Data = b
-		log.Printf("sending disk %q to turbinia project %q", diskName, turbiniaProjectID)
+		logging.FromContext(ctx).Info("sending disk %q to turbinia project %q", diskName, turbiniaProjectID)
 		if _, err := ps.Publish(ctx, topic, m); err != nil {
 			return err
 		}
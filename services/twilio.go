@@ -0,0 +1,107 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// TwilioConfig configures paging on-call by text message and voice call through Twilio.
+type TwilioConfig struct {
+	AccountSID string `yaml:"account_sid"`
+	AuthToken  string `yaml:"auth_token"`
+	// From is the Twilio phone number, To the on-call phone numbers.
+	From string
+	To   []string
+	// Voice calls the phone numbers as well as texting them.
+	Voice bool
+	// Severities are the severities of the findings whose failed remediations page, defaults to
+	// critical findings.
+	Severities []string
+}
+
+// TwilioClient contains minimum interface required by the Twilio notifier.
+type TwilioClient interface {
+	SendSMS(ctx context.Context, from, to, body string) error
+	Call(ctx context.Context, from, to, twiml string) error
+}
+
+// TwilioNotifier pages on-call when remediations of critical findings fail.
+type TwilioNotifier struct {
+	client TwilioClient
+	conf   TwilioConfig
+}
+
+// NewTwilioNotifier returns a notifier paging the on-call phone numbers as configured.
+func NewTwilioNotifier(client TwilioClient, conf TwilioConfig) *TwilioNotifier {
+	if len(conf.Severities) == 0 {
+		conf.Severities = []string{"CRITICAL"}
+	}
+	return &TwilioNotifier{client: client, conf: conf}
+}
+
+// Notify pages on-call if the remediation failed and its finding has one of the severities. Failures
+// replayed on schedule only page once their last attempt failed, other notifications are ignored.
+// All phone numbers are tried even if some fail.
+func (t *TwilioNotifier) Notify(ctx context.Context, n *Notification) error {
+	if n.Outcome != AuditFailure || n.Replaying || !t.pages(n.Severity) {
+		return nil
+	}
+	text := fmt.Sprintf("Security Response Automation: %s failed for %s finding %s", n.Action, strings.ToLower(n.Severity), n.FindingID)
+	if n.ProjectID != "" {
+		text += " in " + n.ProjectID
+	}
+	if n.Error != "" {
+		text += ": " + n.Error
+	}
+	var errs []string
+	for _, to := range t.conf.To {
+		if err := t.client.SendSMS(ctx, t.conf.From, to, text); err != nil {
+			errs = append(errs, fmt.Sprintf("failed to text %q: %q", to, err))
+		}
+		if !t.conf.Voice {
+			continue
+		}
+		if err := t.client.Call(ctx, t.conf.From, to, twiml(text)); err != nil {
+			errs = append(errs, fmt.Sprintf("failed to call %q: %q", to, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to page: %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func (t *TwilioNotifier) pages(severity string) bool {
+	for _, s := range t.conf.Severities {
+		if strings.EqualFold(s, severity) {
+			return true
+		}
+	}
+	return false
+}
+
+// twiml returns the TwiML instructions saying the text.
+func twiml(text string) string {
+	var b bytes.Buffer
+	b.WriteString("<Response><Say>")
+	_ = xml.EscapeText(&b, []byte(text))
+	b.WriteString("</Say></Response>")
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+)
+
+func TestTwilioNotifier(t *testing.T) {
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name         string
+		severities   []string
+		voice        bool
+		notification *Notification
+		wantPages    int
+	}{
+		{name: "critical failure", notification: &Notification{Action: "CloseBucket", Severity: "CRITICAL", Outcome: AuditFailure}, wantPages: 1},
+		{name: "voice", voice: true, notification: &Notification{Action: "CloseBucket", Severity: "CRITICAL", Outcome: AuditFailure}, wantPages: 1},
+		{name: "critical success", notification: &Notification{Action: "CloseBucket", Severity: "CRITICAL", Outcome: AuditSuccess}},
+		{name: "high failure", notification: &Notification{Action: "CloseBucket", Severity: "HIGH", Outcome: AuditFailure}},
+		{name: "replaying failure", notification: &Notification{Action: "CloseBucket", Severity: "CRITICAL", Outcome: AuditFailure, Replaying: true}},
+		{name: "configured severity", severities: []string{"high"}, notification: &Notification{Action: "CloseBucket", Severity: "HIGH", Outcome: AuditFailure}, wantPages: 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubs.TwilioStub{}
+			n := NewTwilioNotifier(stub, TwilioConfig{From: "+15550100", To: []string{"+15550199"}, Voice: tt.voice, Severities: tt.severities})
+			if err := n.Notify(ctx, tt.notification); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			messages := stub.SavedMessages["+15550199"]
+			if len(messages) != tt.wantPages {
+				t.Fatalf("%s failed: got %d messages want %d", tt.name, len(messages), tt.wantPages)
+			}
+			wantCalls := 0
+			if tt.voice {
+				wantCalls = tt.wantPages
+			}
+			calls := stub.SavedCalls["+15550199"]
+			if len(calls) != wantCalls {
+				t.Fatalf("%s failed: got %d calls want %d", tt.name, len(calls), wantCalls)
+			}
+			if wantCalls > 0 && !strings.HasPrefix(calls[0], "<Response><Say>Security Response Automation: CloseBucket failed") {
+				t.Errorf("%s failed: unexpected call %q", tt.name, calls[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	crm "google.golang.org/api/cloudresourcemanager/v1"
+	compute "google.golang.org/api/compute/v1"
+	firestore "google.golang.org/api/firestore/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// undoCollection is the Firestore collection holding the prior states of remediations.
+const undoCollection = "automation-undo"
+
+// Kinds of prior states that can be restored.
+const (
+	undoIAMPolicy = "iam_policy"
+	undoFirewall  = "firewall"
+)
+
+// PriorState is the state of a resource before an automation changed it.
+type PriorState struct {
+	// Kind is either "iam_policy" or "firewall".
+	Kind string
+	// Resource is projects/<project-id>, folders/<folder-id> or organizations/<organization-id> for
+	// IAM policies and projects/<project-id>/global/firewalls/<name> for firewall rules.
+	Resource string
+	// State is the resource's IAM policy or firewall rule as JSON.
+	State string
+}
+
+// UndoDocumentClient contains minimum interface required by the undo service.
+type UndoDocumentClient interface {
+	GetDocument(ctx context.Context, projectID, collection, documentID string) (*firestore.Document, error)
+}
+
+// Undo service restores the prior states saved by remediations.
+type Undo struct {
+	documents UndoDocumentClient
+	crm       crmClient
+	firewall  FirewallClient
+	projectID string
+}
+
+// NewUndo returns an undo service reading prior states from the automation project.
+func NewUndo(documents UndoDocumentClient, crm crmClient, firewall FirewallClient, projectID string) *Undo {
+	return &Undo{documents: documents, crm: crm, firewall: firewall, projectID: projectID}
+}
+
+// States returns the prior states saved by the remediation, in the order they were changed.
+func (u *Undo) States(ctx context.Context, remediationID string) ([]*PriorState, error) {
+	d, err := u.documents.GetDocument(ctx, u.projectID, undoCollection, remediationID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get prior states of remediation %q", remediationID)
+	}
+	var states []*PriorState
+	if err := json.Unmarshal([]byte(d.Fields["states"].StringValue), &states); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal prior states of remediation %q", remediationID)
+	}
+	return states, nil
+}
+
+// Restore replaces the current state of the resource with its prior state. IAM policies are
+// overwritten with the prior policy, firewall rules are reverted or recreated if deleted.
+func (u *Undo) Restore(ctx context.Context, state *PriorState) error {
+	switch state.Kind {
+	case undoIAMPolicy:
+		return u.restorePolicy(ctx, state)
+	case undoFirewall:
+		return u.restoreFirewall(ctx, state)
+	default:
+		return fmt.Errorf("unsupported prior state %q of %q", state.Kind, state.Resource)
+	}
+}
+
+func (u *Undo) restorePolicy(ctx context.Context, state *PriorState) error {
+	var policy crm.Policy
+	if err := json.Unmarshal([]byte(state.State), &policy); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal policy of %q", state.Resource)
+	}
+	current, err := resourcePolicy(ctx, u.crm, state.Resource)
+	if err != nil {
+		return err
+	}
+	// Use the current etag so the restore overwrites any changes made since the remediation.
+	policy.Etag = current.Etag
+	auditUndo(ctx, undoIAMPolicy, state.Resource, current, &policy)
+	return setResourcePolicy(ctx, u.crm, state.Resource, &policy)
+}
+
+func (u *Undo) restoreFirewall(ctx context.Context, state *PriorState) error {
+	parts := strings.Split(state.Resource, "/")
+	if len(parts) != 5 || parts[0] != "projects" || parts[2] != "global" || parts[3] != "firewalls" {
+		return fmt.Errorf("unsupported firewall rule %q", state.Resource)
+	}
+	projectID, name := parts[1], parts[4]
+	var rule compute.Firewall
+	if err := json.Unmarshal([]byte(state.State), &rule); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal firewall rule %q", state.Resource)
+	}
+	rule.Id = 0
+	rule.CreationTimestamp = ""
+	rule.SelfLink = ""
+	current, err := u.firewall.FirewallRule(ctx, projectID, name)
+	var op *compute.Operation
+	switch {
+	case notFound(err):
+		op, err = u.firewall.InsertFirewallRule(ctx, projectID, &rule)
+	case err != nil:
+		return errors.Wrapf(err, "failed to get firewall rule %q", state.Resource)
+	default:
+		// Send the zero values as well so a disabled rule is enabled again.
+		rule.ForceSendFields = []string{"Disabled", "SourceRanges"}
+		auditUndo(ctx, undoFirewall, state.Resource, current, &rule)
+		op, err = u.firewall.PatchFirewallRule(ctx, projectID, name, &rule)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to restore firewall rule %q", state.Resource)
+	}
+	if errs := u.firewall.WaitGlobal(projectID, op); len(errs) > 0 {
+		return errors.Wrapf(errs[0], "failed to restore firewall rule %q", state.Resource)
+	}
+	return nil
+}
+
+func notFound(err error) bool {
+	e, ok := err.(*googleapi.Error)
+	return ok && e.Code == http.StatusNotFound
+}
+
+func undoFields(r *AuditRecord) map[string]firestore.Value {
+	b, _ := json.Marshal(r.States)
+	return map[string]firestore.Value{
+		"time":       {TimestampValue: r.Time.UTC().Format(time.RFC3339Nano)},
+		"finding_id": {StringValue: r.FindingID},
+		"action":     {StringValue: r.Action},
+		"states":     {StringValue: string(b)},
+	}
+}
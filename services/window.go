@@ -0,0 +1,218 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// weekdays abbreviates the days of the week as accepted by maintenance windows.
+var weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// MaintenanceWindow is when its actions may run. The window is either a cron-like schedule or
+// ranges of days and hours.
+type MaintenanceWindow struct {
+	// Actions are the actions restricted to the window, such as remediate_firewall.
+	Actions []string
+	// Schedule holds the minute, hour, day of month, month and day of week fields of the minutes
+	// within the window in cron syntax, such as "* 22-23,0-5 * * 1-5".
+	Schedule string
+	// Days are the days of the week within the window, such as mon-fri or sat. Every day if empty.
+	Days []string
+	// Hours is the range of hours within the window on those days, such as 09:00-17:00. Ranges
+	// ending before they start span midnight. The whole day if empty.
+	Hours string
+	// Timezone is the IANA time zone the window is set in, such as Europe/Paris. Defaults to UTC.
+	Timezone string
+}
+
+// WithinWindows returns whether the action may run now. Actions listed in windows may only run
+// while one of their windows is open, the others may run at any time.
+func WithinWindows(windows []MaintenanceWindow, action string, now time.Time) (bool, error) {
+	restricted := false
+	for _, w := range windows {
+		if !windowAction(w.Actions, action) {
+			continue
+		}
+		restricted = true
+		open, err := w.Open(now)
+		if err != nil {
+			return false, err
+		}
+		if open {
+			return true, nil
+		}
+	}
+	return !restricted, nil
+}
+
+func windowAction(actions []string, action string) bool {
+	for _, a := range actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// Open returns whether the window is open at the given time.
+func (w *MaintenanceWindow) Open(now time.Time) (bool, error) {
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return false, fmt.Errorf("failed to load time zone %q: %q", w.Timezone, err)
+	}
+	now = now.In(loc)
+	if w.Schedule != "" {
+		return scheduled(w.Schedule, now)
+	}
+	if len(w.Days) > 0 {
+		ok, err := onDays(w.Days, now.Weekday())
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	if w.Hours == "" {
+		return true, nil
+	}
+	return withinHours(w.Hours, now)
+}
+
+// scheduled returns whether the minute of now matches the cron schedule.
+func scheduled(schedule string, now time.Time) (bool, error) {
+	fields := strings.Fields(schedule)
+	if len(fields) != 5 {
+		return false, fmt.Errorf("schedule %q doesn't have 5 fields", schedule)
+	}
+	for i, f := range []struct {
+		min, max, value int
+	}{
+		{0, 59, now.Minute()},
+		{0, 23, now.Hour()},
+		{1, 31, now.Day()},
+		{1, 12, int(now.Month())},
+		{0, 7, int(now.Weekday())},
+	} {
+		ok, err := cronField(fields[i], f.min, f.max, f.value)
+		if err != nil {
+			return false, fmt.Errorf("failed to parse schedule %q: %q", schedule, err)
+		}
+		// Sunday is either 0 or 7 in the day of week field.
+		if !ok && i == 4 && f.value == 0 {
+			ok, _ = cronField(fields[i], f.min, f.max, 7)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// cronField returns whether the value matches the comma separated values, ranges and steps of
+// the cron field.
+func cronField(field string, min, max, value int) (bool, error) {
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			s, err := strconv.Atoi(part[i+1:])
+			if err != nil || s <= 0 {
+				return false, fmt.Errorf("invalid step in %q", part)
+			}
+			step, part = s, part[:i]
+		}
+		start, end := min, max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if start, err = strconv.Atoi(bounds[0]); err != nil {
+				return false, fmt.Errorf("invalid value in %q", part)
+			}
+			end = start
+			if len(bounds) == 2 {
+				if end, err = strconv.Atoi(bounds[1]); err != nil {
+					return false, fmt.Errorf("invalid value in %q", part)
+				}
+			} else if step > 1 {
+				end = max
+			}
+		}
+		if start < min || end > max || start > end {
+			return false, fmt.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+		if value >= start && value <= end && (value-start)%step == 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// onDays returns whether the weekday is within the days or ranges of days, such as mon-fri.
+func onDays(days []string, weekday time.Weekday) (bool, error) {
+	for _, d := range days {
+		bounds := strings.SplitN(strings.ToLower(d), "-", 2)
+		start := weekdayIndex(bounds[0])
+		end := start
+		if len(bounds) == 2 {
+			end = weekdayIndex(bounds[1])
+		}
+		if start < 0 || end < 0 {
+			return false, fmt.Errorf("invalid days %q", d)
+		}
+		day := int(weekday)
+		if start <= end && day >= start && day <= end {
+			return true, nil
+		}
+		// Ranges such as fri-mon wrap around the end of the week.
+		if start > end && (day >= start || day <= end) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// weekdayIndex returns the index of the abbreviated day in weekdays, or -1 if it's unknown.
+func weekdayIndex(day string) int {
+	for i, d := range weekdays {
+		if d == day {
+			return i
+		}
+	}
+	return -1
+}
+
+// withinHours returns whether the time of day of now is within the range of hours, such as
+// 09:00-17:00. The start is included and the end excluded.
+func withinHours(hours string, now time.Time) (bool, error) {
+	bounds := strings.SplitN(hours, "-", 2)
+	if len(bounds) != 2 {
+		return false, fmt.Errorf("invalid hours %q", hours)
+	}
+	start, err := time.Parse("15:04", strings.TrimSpace(bounds[0]))
+	if err != nil {
+		return false, fmt.Errorf("invalid hours %q: %q", hours, err)
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(bounds[1]))
+	if err != nil {
+		return false, fmt.Errorf("invalid hours %q: %q", hours, err)
+	}
+	from, to := start.Hour()*60+start.Minute(), end.Hour()*60+end.Minute()
+	minute := now.Hour()*60 + now.Minute()
+	if from <= to {
+		return minute >= from && minute < to, nil
+	}
+	return minute >= from || minute < to, nil
+}
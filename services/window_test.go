@@ -0,0 +1,101 @@
+package services
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaintenanceWindowOpen(t *testing.T) {
+	// Wednesday 2020-01-15 at 22:30 UTC.
+	now := time.Date(2020, 1, 15, 22, 30, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		name    string
+		window  MaintenanceWindow
+		want    bool
+		wantErr bool
+	}{
+		{name: "always open", window: MaintenanceWindow{}, want: true},
+		{name: "within hours", window: MaintenanceWindow{Hours: "22:00-23:00"}, want: true},
+		{name: "outside hours", window: MaintenanceWindow{Hours: "09:00-17:00"}},
+		{name: "hours spanning midnight", window: MaintenanceWindow{Hours: "22:00-06:00"}, want: true},
+		{name: "end excluded", window: MaintenanceWindow{Hours: "20:00-22:30"}},
+		{name: "within days", window: MaintenanceWindow{Days: []string{"mon-fri"}}, want: true},
+		{name: "outside days", window: MaintenanceWindow{Days: []string{"sat", "sun"}}},
+		{name: "days wrapping the week", window: MaintenanceWindow{Days: []string{"tue-sun"}}, want: true},
+		{name: "days and hours", window: MaintenanceWindow{Days: []string{"Wed"}, Hours: "22:00-06:00"}, want: true},
+		{name: "time zone", window: MaintenanceWindow{Hours: "07:00-08:00", Timezone: "Asia/Tokyo"}, want: true},
+		{name: "time zone changing day", window: MaintenanceWindow{Days: []string{"wed"}, Timezone: "Asia/Tokyo"}},
+		{name: "schedule", window: MaintenanceWindow{Schedule: "* 22-23,0-5 * * 1-5"}, want: true},
+		{name: "schedule outside hours", window: MaintenanceWindow{Schedule: "* 0-5 * * *"}},
+		{name: "schedule step", window: MaintenanceWindow{Schedule: "*/15 * * * *"}, want: true},
+		{name: "schedule step missed", window: MaintenanceWindow{Schedule: "*/20 * * * *"}},
+		{name: "schedule month", window: MaintenanceWindow{Schedule: "* * 15 1 *"}, want: true},
+		{name: "invalid days", window: MaintenanceWindow{Days: []string{"someday"}}, wantErr: true},
+		{name: "invalid hours", window: MaintenanceWindow{Hours: "9am"}, wantErr: true},
+		{name: "invalid schedule", window: MaintenanceWindow{Schedule: "* * *"}, wantErr: true},
+		{name: "schedule out of range", window: MaintenanceWindow{Schedule: "* 25 * * *"}, wantErr: true},
+		{name: "invalid time zone", window: MaintenanceWindow{Timezone: "Mars/Olympus"}, wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.window.Open(now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%s failed: got error %v", tt.name, err)
+			}
+			if got != tt.want {
+				t.Errorf("%s failed: got %t want %t", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindowSunday(t *testing.T) {
+	sunday := time.Date(2020, 1, 19, 12, 0, 0, 0, time.UTC)
+	for _, schedule := range []string{"* * * * 0", "* * * * 7", "* * * * 6-7"} {
+		w := MaintenanceWindow{Schedule: schedule}
+		if open, err := w.Open(sunday); err != nil || !open {
+			t.Errorf("%q failed: got %t, %v", schedule, open, err)
+		}
+	}
+}
+
+func TestWithinWindows(t *testing.T) {
+	now := time.Date(2020, 1, 15, 22, 30, 0, 0, time.UTC)
+	windows := []MaintenanceWindow{
+		{Actions: []string{"remediate_firewall"}, Hours: "09:00-17:00"},
+		{Actions: []string{"remediate_firewall", "quarantine_instance"}, Days: []string{"sat", "sun"}},
+		{Actions: []string{"gce_create_snapshot"}, Hours: "22:00-06:00"},
+	}
+	for _, tt := range []struct {
+		action string
+		want   bool
+	}{
+		{action: "remediate_firewall"},
+		{action: "quarantine_instance"},
+		{action: "gce_create_snapshot", want: true},
+		{action: "revoke_iam", want: true},
+	} {
+		t.Run(tt.action, func(t *testing.T) {
+			got, err := WithinWindows(windows, tt.action, now)
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.action, err)
+			}
+			if got != tt.want {
+				t.Errorf("%s failed: got %t want %t", tt.action, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package simulate
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFinding(t *testing.T) {
+	o := Options{
+		Organization: "154584661726",
+		Project:      "test-project",
+		ID:           "6a30ce604c11417995b1fa260753f3b5",
+		EventTime:    time.Date(2020, 6, 10, 17, 48, 49, 0, time.UTC),
+	}
+	b, err := Finding("public_bucket_acl", o)
+	if err != nil {
+		t.Fatalf("public_bucket_acl failed: %q", err)
+	}
+	var got struct {
+		Finding struct {
+			Name             string                 `json:"name"`
+			ResourceName     string                 `json:"resourceName"`
+			Category         string                 `json:"category"`
+			EventTime        string                 `json:"eventTime"`
+			SourceProperties map[string]interface{} `json:"sourceProperties"`
+			SecurityMarks    struct {
+				Marks map[string]string `json:"marks"`
+			} `json:"securityMarks"`
+		} `json:"finding"`
+	}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("public_bucket_acl failed: %q", err)
+	}
+	f := got.Finding
+	if f.Name != "organizations/154584661726/sources/2673592633662526977/findings/6a30ce604c11417995b1fa260753f3b5" {
+		t.Errorf("public_bucket_acl failed, name %q", f.Name)
+	}
+	if f.ResourceName != "//storage.googleapis.com/test-project-simulated-bucket" || f.Category != "PUBLIC_BUCKET_ACL" {
+		t.Errorf("public_bucket_acl failed, resource %q category %q", f.ResourceName, f.Category)
+	}
+	if f.EventTime != "2020-06-10T17:48:49.000Z" || f.SourceProperties["ProjectId"] != "test-project" {
+		t.Errorf("public_bucket_acl failed, event time %q project %q", f.EventTime, f.SourceProperties["ProjectId"])
+	}
+	if f.SecurityMarks.Marks[SimulatedMark] != "true" {
+		t.Errorf("public_bucket_acl failed, not marked as simulated: %v", f.SecurityMarks.Marks)
+	}
+	if _, err := Finding("unknown_rule", o); err == nil {
+		t.Errorf("expected an error for an unknown rule")
+	}
+	if _, err := Finding("public_bucket_acl", Options{}); err == nil {
+		t.Errorf("expected an error without organization and project")
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	const attempts = "finding.sourceProperties.properties.loginAttempts"
+	for _, tt := range []struct {
+		name      string
+		path      string
+		value     string
+		want      interface{}
+		expectErr bool
+	}{
+		{name: "string", path: "finding.resourceName", value: "//cloudresourcemanager.googleapis.com/projects/other-project", want: "//cloudresourcemanager.googleapis.com/projects/other-project"},
+		{name: "missing", path: "finding.securityMarks.marks.owner", value: "security", want: "security"},
+		{name: "element", path: attempts + ".0.sourceIp", value: "198.51.100.1", want: "198.51.100.1"},
+		{name: "json", path: attempts, value: `[{"sourceIp": "198.51.100.1"}]`, want: []interface{}{map[string]interface{}{"sourceIp": "198.51.100.1"}}},
+		{name: "not json", path: attempts, value: "198.51.100.1", expectErr: true},
+		{name: "out of range", path: attempts + ".2.sourceIp", value: "198.51.100.1", expectErr: true},
+		{name: "not an object", path: "finding.state.value", value: "ACTIVE", expectErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			o := Options{Organization: "154584661726", Project: "test-project", Overrides: map[string]string{tt.path: tt.value}}
+			b, err := Finding("ssh_brute_force", o)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("%s failed, expected an error", tt.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			var v interface{}
+			if err := json.Unmarshal(b, &v); err != nil {
+				t.Fatalf("%s failed: %q", tt.name, err)
+			}
+			if diff := cmp.Diff(get(v, tt.path), tt.want); diff != "" {
+				t.Errorf("%s failed, difference:%+v", tt.name, diff)
+			}
+		})
+	}
+}
+
+// get returns the field at the path.
+func get(v interface{}, path string) interface{} {
+	for _, k := range strings.Split(path, ".") {
+		switch x := v.(type) {
+		case map[string]interface{}:
+			v = x[k]
+		case []interface{}:
+			n, _ := strconv.Atoi(k)
+			v = x[n]
+		}
+	}
+	return v
+}
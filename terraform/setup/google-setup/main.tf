@@ -21,7 +21,7 @@ data "archive_file" "cloud_functions_zip" {
   source_dir  = path.root
   output_path = "${path.root}/deploy/functions.zip"
   excludes = ["deploy", ".git", ".gitignore", ".terraform", ".pre-commit-config.yaml", ".github", ".vscode", ".idea",
-  "README.md", "CONTRIBUTING.md", "automations.md", "LICENSE", "terraform.tfstate", "terraform", "local"]
+  "README.md", "CONTRIBUTING.md", "automations.md", "LICENSE", "terraform.tfstate", "terraform", "local", "cmd"]
   depends_on = [
     google_project_service.cloudresourcemanager_api,
     google_project_service.logging_api,
@@ -60,6 +60,11 @@ resource "google_organization_iam_member" "update-findings" {
   for_each = toset([
     "roles/securitycenter.findingsStateSetter",
     "roles/securitycenter.findingSecurityMarksWriter",
+    // Reads and clears the exemption marks of assets.
+    "roles/securitycenter.assetsViewer",
+    "roles/securitycenter.assetSecurityMarksWriter",
+    // Checks whether suppressed findings are still active.
+    "roles/securitycenter.findingsViewer",
   ])
 
   member = "serviceAccount:${google_service_account.automation-service-account.email}"
@@ -118,6 +123,59 @@ resource "google_project_iam_member" "stackdriver-writer" {
   member  = "serviceAccount:${google_service_account.automation-service-account.email}"
 }
 
+// Audit trail of every automated action, written by the automations to BigQuery and to
+// Firestore if BigQuery is unavailable.
+resource "google_bigquery_dataset" "audit" {
+  project    = var.automation-project
+  dataset_id = "automation_audit"
+  location   = var.region
+  depends_on = [google_project_service.bigquery_api]
+}
+
+resource "google_bigquery_table" "audit-actions" {
+  project    = var.automation-project
+  dataset_id = google_bigquery_dataset.audit.dataset_id
+  table_id   = "actions"
+
+  time_partitioning {
+    type  = "DAY"
+    field = "time"
+  }
+
+  schema = <<EOF
+[
+  {"name": "remediation_id", "type": "STRING"},
+  {"name": "time", "type": "TIMESTAMP", "mode": "REQUIRED"},
+  {"name": "finding_id", "type": "STRING"},
+  {"name": "severity", "type": "STRING"},
+  {"name": "category", "type": "STRING"},
+  {"name": "project_id", "type": "STRING"},
+  {"name": "action", "type": "STRING"},
+  {"name": "values", "type": "STRING"},
+  {"name": "before", "type": "STRING"},
+  {"name": "after", "type": "STRING"},
+  {"name": "outcome", "type": "STRING"},
+  {"name": "error", "type": "STRING"},
+  {"name": "reason", "type": "STRING"},
+  {"name": "actor", "type": "STRING"},
+  {"name": "approval_id", "type": "STRING"},
+  {"name": "approvers", "type": "STRING", "mode": "REPEATED"}
+]
+EOF
+}
+
+resource "google_project_iam_member" "audit-writer" {
+  for_each = toset([
+    "roles/bigquery.dataEditor",
+    "roles/datastore.user",
+    "roles/cloudtrace.agent",
+  ])
+
+  project = var.automation-project
+  role    = each.value
+  member  = "serviceAccount:${google_service_account.automation-service-account.email}"
+}
+
 resource "google_project_service" "cloudresourcemanager_api" {
   project                    = var.automation-project
   service                    = "cloudresourcemanager.googleapis.com"
@@ -166,3 +224,32 @@ resource "google_project_service" "cloudbuild_api" {
   disable_dependent_services = false
   disable_on_destroy         = false
 }
+
+resource "google_project_service" "bigquery_api" {
+  project                    = var.automation-project
+  service                    = "bigquery.googleapis.com"
+  disable_dependent_services = false
+  disable_on_destroy         = false
+}
+
+resource "google_project_service" "firestore_api" {
+  project                    = var.automation-project
+  service                    = "firestore.googleapis.com"
+  disable_dependent_services = false
+  disable_on_destroy         = false
+}
+
+# Required to impersonate remediator service accounts.
+resource "google_project_service" "iamcredentials_api" {
+  project                    = var.automation-project
+  service                    = "iamcredentials.googleapis.com"
+  disable_dependent_services = false
+  disable_on_destroy         = false
+}
+
+resource "google_project_service" "cloudtrace_api" {
+  project                    = var.automation-project
+  service                    = "cloudtrace.googleapis.com"
+  disable_dependent_services = false
+  disable_on_destroy         = false
+}
@@ -37,3 +37,7 @@ output "router-topic-id" {
 output "organization-id" {
   value = var.organization-id
 }
+
+output "config-uri" {
+  value = var.config-uri
+}
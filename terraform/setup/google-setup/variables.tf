@@ -36,3 +36,9 @@ variable "notification-name" {
   type    = string
   default = "sra-notifications"
 }
+
+variable "config-uri" {
+  type        = string
+  default     = ""
+  description = "GCS object the functions read their configuration from, such as gs://cloudorg-sra-config/sra.yaml. The deployed config/sra.yaml is used if empty."
+}
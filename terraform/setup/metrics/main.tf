@@ -0,0 +1,90 @@
+# Package automation contains the Cloud Function code to automate actions.
+
+# Copyright 2019 Google LLC
+
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+
+# 	https://www.apache.org/licenses/LICENSE-2.0
+
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+# Counts the findings received by the router, by category and severity.
+resource "google_logging_metric" "findings_received" {
+  name    = "sra/findings_received"
+  project = var.setup.automation-project
+  filter  = "resource.type=\"cloud_function\" AND jsonPayload.metric=\"finding_received\""
+  metric_descriptor {
+    metric_kind = "DELTA"
+    value_type  = "INT64"
+    labels {
+      key = "category"
+    }
+    labels {
+      key = "severity"
+    }
+  }
+  label_extractors = {
+    "category" = "EXTRACT(jsonPayload.category)"
+    "severity" = "EXTRACT(jsonPayload.finding_severity)"
+  }
+}
+
+# Counts the outcomes of remediations, success, failure, dry_run or skipped, by action and category.
+resource "google_logging_metric" "remediations" {
+  name    = "sra/remediations"
+  project = var.setup.automation-project
+  filter  = "resource.type=\"cloud_function\" AND jsonPayload.metric=\"remediation\""
+  metric_descriptor {
+    metric_kind = "DELTA"
+    value_type  = "INT64"
+    labels {
+      key = "action"
+    }
+    labels {
+      key = "category"
+    }
+    labels {
+      key = "outcome"
+    }
+  }
+  label_extractors = {
+    "action"   = "EXTRACT(jsonPayload.action)"
+    "category" = "EXTRACT(jsonPayload.category)"
+    "outcome"  = "EXTRACT(jsonPayload.outcome)"
+  }
+}
+
+# Distribution of the seconds from the finding's event to the outcome of its remediation.
+resource "google_logging_metric" "remediation_latency" {
+  name            = "sra/remediation_latency"
+  project         = var.setup.automation-project
+  filter          = "resource.type=\"cloud_function\" AND jsonPayload.metric=\"remediation\" AND jsonPayload.latency_seconds>0"
+  value_extractor = "EXTRACT(jsonPayload.latency_seconds)"
+  metric_descriptor {
+    metric_kind = "DELTA"
+    value_type  = "DISTRIBUTION"
+    unit        = "s"
+    labels {
+      key = "action"
+    }
+    labels {
+      key = "outcome"
+    }
+  }
+  label_extractors = {
+    "action"  = "EXTRACT(jsonPayload.action)"
+    "outcome" = "EXTRACT(jsonPayload.outcome)"
+  }
+  bucket_options {
+    exponential_buckets {
+      num_finite_buckets = 20
+      growth_factor      = 2
+      scale              = 1
+    }
+  }
+}
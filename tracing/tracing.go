@@ -0,0 +1,231 @@
+// Package tracing traces remediations from the router through the automations and their API
+// calls, exporting the spans to Cloud Trace.
+package tracing
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/googlecloudplatform/security-response-automation/clients"
+	"github.com/googlecloudplatform/security-response-automation/logging"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	cloudtrace "google.golang.org/api/cloudtrace/v2"
+)
+
+// instrumentationName names the tracer of the automations.
+const instrumentationName = "github.com/googlecloudplatform/security-response-automation"
+
+// statusUnknown is the google.rpc.Code of spans that failed.
+const statusUnknown = 2
+
+// provider is the tracer provider, flushed at the end of each invocation since the instance may
+// be frozen once the function returns.
+var provider *sdktrace.TracerProvider
+
+// propagator carries the trace context in the W3C traceparent and tracestate attributes.
+var propagator = propagation.TraceContext{}
+
+// SpanWriter contains minimum interface required to export spans.
+type SpanWriter interface {
+	BatchWriteSpans(ctx context.Context, projectID string, spans []*cloudtrace.Span) error
+}
+
+// Init exports the spans of the function to Cloud Trace in the project, and propagates traces
+// with the W3C trace context.
+func Init(ctx context.Context, projectID string) error {
+	ct, err := clients.NewCloudTrace(ctx)
+	if err != nil {
+		return err
+	}
+	// Cloud Functions set the name of the function being run.
+	r := resource.NewWithAttributes("", attribute.String("service.name", os.Getenv("FUNCTION_TARGET")))
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(NewExporter(ct, projectID)),
+		sdktrace.WithResource(r),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagator)
+	return nil
+}
+
+// Flush exports the spans that ended, logging if they couldn't be.
+func Flush(ctx context.Context) {
+	if provider == nil {
+		return
+	}
+	if err := provider.ForceFlush(ctx); err != nil {
+		logging.FromContext(ctx).Error("failed to export spans: %q", err)
+	}
+}
+
+// Start starts a span named after the automation or API, as a child of the span of the context.
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// End ends the span, setting its status to the error if it failed.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Attributes carries the trace context of a Pub/Sub message in its attributes.
+type Attributes map[string]string
+
+// Get returns the value of the attribute.
+func (a Attributes) Get(key string) string {
+	return a[key]
+}
+
+// Set sets the attribute.
+func (a Attributes) Set(key, value string) {
+	a[key] = value
+}
+
+// Keys returns the names of the attributes.
+func (a Attributes) Keys() []string {
+	keys := make([]string, 0, len(a))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Inject adds the trace context of the span to the attributes of a message, so the automation
+// consuming it continues the trace.
+func Inject(ctx context.Context, attributes map[string]string) {
+	propagator.Inject(ctx, Attributes(attributes))
+}
+
+// Extract returns the context of the trace the message's attributes carry, if any.
+func Extract(ctx context.Context, attributes map[string]string) context.Context {
+	if attributes == nil {
+		return ctx
+	}
+	return propagator.Extract(ctx, Attributes(attributes))
+}
+
+// Exporter writes spans to Cloud Trace.
+type Exporter struct {
+	writer    SpanWriter
+	projectID string
+}
+
+// NewExporter returns an exporter writing spans to the traces of the project.
+func NewExporter(writer SpanWriter, projectID string) *Exporter {
+	return &Exporter{writer: writer, projectID: projectID}
+}
+
+// ExportSpans writes the spans to Cloud Trace.
+func (e *Exporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	out := make([]*cloudtrace.Span, 0, len(spans))
+	for _, s := range spans {
+		out = append(out, e.span(s))
+	}
+	if err := e.writer.BatchWriteSpans(ctx, e.projectID, out); err != nil {
+		return fmt.Errorf("failed to write %d spans: %q", len(out), err)
+	}
+	return nil
+}
+
+// Shutdown releases nothing, the writer is shared.
+func (e *Exporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// span converts the span to a Cloud Trace span.
+func (e *Exporter) span(s sdktrace.ReadOnlySpan) *cloudtrace.Span {
+	sc := s.SpanContext()
+	span := &cloudtrace.Span{
+		Name:        fmt.Sprintf("projects/%s/traces/%s/spans/%s", e.projectID, sc.TraceID(), sc.SpanID()),
+		SpanId:      sc.SpanID().String(),
+		DisplayName: &cloudtrace.TruncatableString{Value: s.Name()},
+		StartTime:   s.StartTime().UTC().Format(time.RFC3339Nano),
+		EndTime:     s.EndTime().UTC().Format(time.RFC3339Nano),
+		SpanKind:    spanKind(s.SpanKind()),
+		Attributes:  attributes(s.Attributes()),
+	}
+	if parent := s.Parent(); parent.IsValid() {
+		span.ParentSpanId = parent.SpanID().String()
+		// Spans continued from a message's trace context are in another function.
+		span.SameProcessAsParentSpan = !parent.IsRemote()
+	}
+	if status := s.Status(); status.Code == codes.Error {
+		span.Status = &cloudtrace.Status{Code: statusUnknown, Message: status.Description}
+	}
+	if events := s.Events(); len(events) > 0 {
+		span.TimeEvents = &cloudtrace.TimeEvents{}
+		for _, ev := range events {
+			span.TimeEvents.TimeEvent = append(span.TimeEvents.TimeEvent, &cloudtrace.TimeEvent{
+				Time: ev.Time.UTC().Format(time.RFC3339Nano),
+				Annotation: &cloudtrace.Annotation{
+					Description: &cloudtrace.TruncatableString{Value: ev.Name},
+					Attributes:  attributes(ev.Attributes),
+				},
+			})
+		}
+	}
+	return span
+}
+
+func attributes(kvs []attribute.KeyValue) *cloudtrace.Attributes {
+	if len(kvs) == 0 {
+		return nil
+	}
+	m := map[string]cloudtrace.AttributeValue{}
+	for _, kv := range kvs {
+		switch kv.Value.Type() {
+		case attribute.BOOL:
+			m[string(kv.Key)] = cloudtrace.AttributeValue{BoolValue: kv.Value.AsBool()}
+		case attribute.INT64:
+			m[string(kv.Key)] = cloudtrace.AttributeValue{IntValue: kv.Value.AsInt64()}
+		default:
+			m[string(kv.Key)] = cloudtrace.AttributeValue{StringValue: &cloudtrace.TruncatableString{Value: kv.Value.Emit()}}
+		}
+	}
+	return &cloudtrace.Attributes{AttributeMap: m}
+}
+
+func spanKind(k trace.SpanKind) string {
+	switch k {
+	case trace.SpanKindServer:
+		return "SERVER"
+	case trace.SpanKindClient:
+		return "CLIENT"
+	case trace.SpanKindProducer:
+		return "PRODUCER"
+	case trace.SpanKindConsumer:
+		return "CONSUMER"
+	case trace.SpanKindInternal:
+		return "INTERNAL"
+	}
+	return "SPAN_KIND_UNSPECIFIED"
+}
@@ -0,0 +1,93 @@
+package tracing
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/googlecloudplatform/security-response-automation/clients/stubs"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestExporter(t *testing.T) {
+	ctx := context.Background()
+	ct := &stubs.CloudTraceStub{}
+	p := sdktrace.NewTracerProvider(sdktrace.WithSyncer(NewExporter(ct, "automation-project")))
+	tracer := p.Tracer(instrumentationName)
+
+	ctx, parent := tracer.Start(ctx, "Router", trace.WithAttributes(attribute.String("finding_id", "f1")))
+	_, child := tracer.Start(ctx, "Compute.DeleteInstance", trace.WithSpanKind(trace.SpanKindClient))
+	child.AddEvent("retry", trace.WithAttributes(attribute.Int("attempt", 1)))
+	End(child, errors.New("backend error"))
+	End(parent, nil)
+
+	if got := len(ct.WrittenSpans); got != 2 {
+		t.Fatalf("exporter failed: got %d spans, want 2", got)
+	}
+	c, r := ct.WrittenSpans[0], ct.WrittenSpans[1]
+	if got, want := r.Name, "projects/automation-project/traces/"+parent.SpanContext().TraceID().String()+"/spans/"+r.SpanId; got != want {
+		t.Errorf("exporter failed: got name %q, want %q", got, want)
+	}
+	if got := r.Attributes.AttributeMap["finding_id"].StringValue.Value; got != "f1" {
+		t.Errorf("exporter failed: got finding_id %q, want %q", got, "f1")
+	}
+	if r.Status != nil || r.ParentSpanId != "" {
+		t.Errorf("exporter failed: got status %v and parent %q for the root span", r.Status, r.ParentSpanId)
+	}
+	if c.ParentSpanId != r.SpanId || !c.SameProcessAsParentSpan {
+		t.Errorf("exporter failed: got parent %q, want %q in the same process", c.ParentSpanId, r.SpanId)
+	}
+	if c.SpanKind != "CLIENT" {
+		t.Errorf("exporter failed: got kind %q, want %q", c.SpanKind, "CLIENT")
+	}
+	if c.Status == nil || c.Status.Message != "backend error" {
+		t.Errorf("exporter failed: got status %v, want the error", c.Status)
+	}
+	// The error is recorded as an event after the retry.
+	if c.TimeEvents == nil || len(c.TimeEvents.TimeEvent) != 2 || c.TimeEvents.TimeEvent[0].Annotation.Description.Value != "retry" {
+		t.Errorf("exporter failed: got events %v, want the retry", c.TimeEvents)
+	}
+}
+
+func TestPropagation(t *testing.T) {
+	ctx := context.Background()
+	ct := &stubs.CloudTraceStub{}
+	p := sdktrace.NewTracerProvider(sdktrace.WithSyncer(NewExporter(ct, "automation-project")))
+	ctx, router := p.Tracer(instrumentationName).Start(ctx, "Router")
+	attributes := map[string]string{"finding_id": "f1"}
+	Inject(ctx, attributes)
+	if attributes["traceparent"] == "" {
+		t.Fatalf("inject failed: got attributes %v, want traceparent", attributes)
+	}
+
+	ctx = Extract(context.Background(), attributes)
+	_, automation := p.Tracer(instrumentationName).Start(ctx, "CloseBucket")
+	automation.End()
+	router.End()
+
+	if got, want := automation.SpanContext().TraceID(), router.SpanContext().TraceID(); got != want {
+		t.Errorf("extract failed: got trace %s, want %s", got, want)
+	}
+	if c := ct.WrittenSpans[0]; c.ParentSpanId != router.SpanContext().SpanID().String() || c.SameProcessAsParentSpan {
+		t.Errorf("extract failed: got parent %q, want %q in another process", c.ParentSpanId, router.SpanContext().SpanID())
+	}
+	if ctx := Extract(context.Background(), nil); trace.SpanContextFromContext(ctx).IsValid() {
+		t.Errorf("extract failed: got a trace from no attributes")
+	}
+}
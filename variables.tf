@@ -24,3 +24,21 @@ variable "enable-scc-notification" {
   default     = true
   description = "If true, create the notification config from SCC instead of Cloud Logging"
 }
+
+variable "iam-policy-backup-resources" {
+  type        = list(string)
+  default     = []
+  description = "Projects (projects/<project-id>) and organizations (organizations/<organization-id>) whose IAM policies are periodically backed up."
+}
+
+variable "config-uri" {
+  type        = string
+  default     = ""
+  description = "GCS object the functions read their configuration from, such as gs://cloudorg-sra-config/sra.yaml. The deployed config/sra.yaml is used if empty."
+}
+
+variable "batch-routing" {
+  type        = bool
+  default     = false
+  description = "If true, the RouteBatch function pulls and routes findings in batches instead of the router routing each finding as it's published. Meant for organizations with bursts of findings."
+}